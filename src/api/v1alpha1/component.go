@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	"path"
+
 	"github.com/invopop/jsonschema"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1/extensions"
 )
@@ -41,18 +43,36 @@ type ZarfComponent struct {
 	// Helm charts to install during package deploy.
 	Charts []ZarfChart `json:"charts,omitempty"`
 
+	// Operators to install from OLM catalogs during package deploy, before the component's charts and manifests.
+	Operators []ZarfOperator `json:"operators,omitempty"`
+
 	// Datasets to inject into a container in the target cluster.
 	DataInjections []ZarfDataInjection `json:"dataInjections,omitempty"`
 
+	// Resources to wait for after the component's charts, manifests and plugins are deployed, before its onDeploy after actions.
+	HealthChecks []ZarfComponentHealthCheck `json:"healthChecks,omitempty"`
+
+	// Plugins that add custom component behavior, each run by a zarf-plugin-<name> executable on package create, deploy and remove.
+	Plugins []ZarfComponentPlugin `json:"plugins,omitempty"`
+
 	// Files or folders to place on disk during package deployment.
 	Files []ZarfFile `json:"files,omitempty"`
 
-	// List of OCI images to include in the package.
+	// List of OCI images to include in the package. Entries of the form docker-label:<key>=<value> are replaced by the images with a matching label in the local Docker or containerd daemon on package create.
 	Images []string `json:"images,omitempty"`
 
+	// Cosign signatures the images must carry, verified against the pulled image digests during package create.
+	ImageSignatures []ZarfImageSignature `json:"imageSignatures,omitempty"`
+
+	// Images to squash into a single layer on package create, the digest of the original image is recorded in the 'dev.zarf.image.squashed-from' manifest annotation (supports '*' globbing).
+	SquashImages []string `json:"squashImages,omitempty" jsonschema:"example=ghcr.io/stefanprodan/podinfo:*"`
+
 	// List of git repos to include in the package.
 	Repos []string `json:"repos,omitempty"`
 
+	// Python, NPM and Maven packages to resolve on package create and publish to the Zarf artifact server on package deploy.
+	Artifacts []ZarfArtifacts `json:"artifacts,omitempty"`
+
 	// Extend component functionality with additional features.
 	Extensions extensions.ZarfComponentExtensions `json:"extensions,omitempty"`
 
@@ -68,13 +88,32 @@ func (c ZarfComponent) RequiresCluster() bool {
 	hasImages := len(c.Images) > 0
 	hasCharts := len(c.Charts) > 0
 	hasManifests := len(c.Manifests) > 0
+	hasOperators := len(c.Operators) > 0
 	hasRepos := len(c.Repos) > 0
+	hasArtifacts := len(c.Artifacts) > 0
 	hasDataInjections := len(c.DataInjections) > 0
+	hasHealthChecks := len(c.HealthChecks) > 0
 
-	if hasImages || hasCharts || hasManifests || hasRepos || hasDataInjections {
+	if hasImages || hasCharts || hasManifests || hasOperators || hasRepos || hasArtifacts || hasDataInjections || hasHealthChecks {
 		return true
 	}
 
+	for _, plugin := range c.Plugins {
+		if plugin.RequiresCluster {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SquashesImage returns if the given image of the component is squashed into a single layer on package create.
+func (c ZarfComponent) SquashesImage(image string) bool {
+	for _, pattern := range c.SquashImages {
+		if matched, _ := path.Match(pattern, image); matched {
+			return true
+		}
+	}
 	return false
 }
 
@@ -105,6 +144,31 @@ type ZarfComponentOnlyCluster struct {
 	Distros []string `json:"distros,omitempty" jsonschema:"example=k3s,example=eks"`
 }
 
+// ZarfImageSignature defines a cosign signature that images must carry to be included in the package.
+type ZarfImageSignature struct {
+	// Images the signature is required on, defaults to all images of the component (supports '*' globbing).
+	Images []string `json:"images,omitempty" jsonschema:"example=ghcr.io/stefanprodan/podinfo:*"`
+	// Path, URL or KMS URI of the cosign public key the images must be signed with.
+	Key string `json:"key,omitempty" jsonschema:"example=cosign.pub,example=awskms:///alias/cosign"`
+	// (keyless) Regular expression the identity of the signing certificate must match.
+	Identity string `json:"identity,omitempty" jsonschema:"example=^https://github.com/stefanprodan/podinfo/.github/workflows/release.yml@refs/tags/.*$"`
+	// (keyless) Regular expression the OIDC issuer of the signing certificate must match.
+	Issuer string `json:"issuer,omitempty" jsonschema:"example=^https://token.actions.githubusercontent.com$"`
+}
+
+// Matches returns if the signature is required on the given image.
+func (s ZarfImageSignature) Matches(image string) bool {
+	if len(s.Images) == 0 {
+		return true
+	}
+	for _, pattern := range s.Images {
+		if matched, _ := path.Match(pattern, image); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ZarfFile defines a file to deploy.
 type ZarfFile struct {
 	// Local folder or file path or remote URL to pull into the package.
@@ -119,6 +183,29 @@ type ZarfFile struct {
 	Symlinks []string `json:"symlinks,omitempty"`
 	// Local folder or file to be extracted from a 'source' archive.
 	ExtractPath string `json:"extractPath,omitempty"`
+	// Octal permissions to set on the file, or on each file within the folder, during package deploy (overrides executable).
+	Mode string `json:"mode,omitempty" jsonschema:"example=0644,example=0755"`
+	// User ID to set as the owner of the file or folder during package deploy.
+	UID *int `json:"uid,omitempty"`
+	// Group ID to set as the group of the file or folder during package deploy.
+	GID *int `json:"gid,omitempty"`
+	// Install the file as a systemd unit during package deploy.
+	Systemd ZarfFileSystemd `json:"systemd,omitempty"`
+}
+
+// ZarfFileSystemd installs a file as a systemd unit, the name of the unit is the name of the target.
+type ZarfFileSystemd struct {
+	// Reload the systemd manager configuration after the unit is placed, implied by enable and start.
+	Install bool `json:"install,omitempty"`
+	// Enable the unit to start on boot.
+	Enable bool `json:"enable,omitempty"`
+	// Start the unit, restarting it if it is already running.
+	Start bool `json:"start,omitempty"`
+}
+
+// IsUnit returns if the file is installed as a systemd unit.
+func (s ZarfFileSystemd) IsUnit() bool {
+	return s.Install || s.Enable || s.Start
 }
 
 // ZarfChart defines a helm chart to be deployed.
@@ -173,6 +260,58 @@ type ZarfManifest struct {
 	NoWait bool `json:"noWait,omitempty"`
 }
 
+// ZarfOperator defines an operator Zarf installs from an Operator Lifecycle Manager (OLM) catalog.
+type ZarfOperator struct {
+	// The name of the operator package in the catalog; this is also the name of the created subscription.
+	Name string `json:"name" jsonschema:"example=cert-manager"`
+	// The namespace to install the operator to.
+	Namespace string `json:"namespace"`
+	// The catalog (index) image to serve the operator from; it is added to the component images and rewritten to the Zarf registry on deploy.
+	CatalogImage string `json:"catalogImage" jsonschema:"example=registry.redhat.io/redhat/community-operator-index:v4.15"`
+	// The catalog channel to subscribe to (defaults to the default channel of the package).
+	Channel string `json:"channel,omitempty" jsonschema:"example=stable"`
+	// The ClusterServiceVersion to start the subscription from (defaults to the latest version in the channel).
+	StartingCSV string `json:"startingCSV,omitempty" jsonschema:"example=cert-manager.v1.14.4"`
+	// The namespaces the operator watches when an operator group is created for it (defaults to all namespaces).
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Whether to not wait for the operator to be installed before continuing.
+	NoWait bool `json:"noWait,omitempty"`
+}
+
+// ZarfArtifacts defines a set of packages of a language ecosystem to publish to the Zarf artifact server.
+type ZarfArtifacts struct {
+	// The package ecosystem of the artifacts.
+	Type string `json:"type" jsonschema:"enum=pypi,enum=npm,enum=maven"`
+	// The packages pinned to exact versions, as name==version for pypi, name@version for npm and groupId:artifactId:version[:packaging[:classifier]] for maven. Dependencies are not resolved, so the list must include them (e.g. from a lockfile).
+	Packages []string `json:"packages" jsonschema:"example=requests==2.32.3,example=lodash@4.17.21,example=org.apache.commons:commons-lang3:3.14.0"`
+	// The index, registry or repository to download the packages from (defaults to https://pypi.org, https://registry.npmjs.org and https://repo.maven.apache.org/maven2).
+	Source string `json:"source,omitempty"`
+}
+
+// ZarfComponentHealthCheck defines resources to wait for after a component is deployed.
+type ZarfComponentHealthCheck struct {
+	// The kind of resource to wait for.
+	Kind string `json:"kind" jsonschema:"example=Deployment,example=StatefulSet"`
+	// The name of the resource or selector to wait for.
+	Name string `json:"name" jsonschema:"example=podinfo,example=app=podinfo"`
+	// The namespace of the resource to wait for.
+	Namespace string `json:"namespace,omitempty"`
+	// The condition or jsonpath state to wait for; defaults to current, a special condition that will wait for the resource to be fully reconciled.
+	Condition string `json:"condition,omitempty" jsonschema:"example=current,example=Available,'{.status.availableReplicas}'=23"`
+	// A CEL expression over the resource that must also evaluate to true, the fields of the resource are variables and the whole resource is object.
+	Expression string `json:"expression,omitempty" jsonschema:"example=status.readyReplicas == spec.replicas"`
+}
+
+// ZarfComponentPlugin defines custom component behavior handled by a plugin executable.
+type ZarfComponentPlugin struct {
+	// The name of the plugin, run as the zarf-plugin-<name> executable from the PATH.
+	Name string `json:"name" jsonschema:"pattern=^[a-z0-9][a-z0-9\\-]*$,example=database-dump"`
+	// Configuration passed to the plugin as is.
+	Config map[string]any `json:"config,omitempty"`
+	// Whether the plugin needs a connection to the cluster and the Zarf state on deploy and remove.
+	RequiresCluster bool `json:"requiresCluster,omitempty"`
+}
+
 // DeprecatedZarfComponentScripts are scripts that run before or after a component is deployed.
 type DeprecatedZarfComponentScripts struct {
 	// Show the output of the script during package deployment.
@@ -273,6 +412,8 @@ type ZarfComponentActionWaitCluster struct {
 	Namespace string `json:"namespace,omitempty"`
 	// The condition or jsonpath state to wait for; defaults to exist, a special condition that will wait for the resource to exist.
 	Condition string `json:"condition,omitempty" jsonschema:"example=Ready,example=Available,'{.status.availableReplicas}'=23"`
+	// A CEL expression over the resource that must also evaluate to true, the fields of the resource are variables and the whole resource is object.
+	Expression string `json:"expression,omitempty" jsonschema:"example=status.readyReplicas == spec.replicas"`
 }
 
 // ZarfComponentActionWaitNetwork specifies a condition to wait for before continuing
@@ -311,10 +452,10 @@ type ZarfDataInjection struct {
 type ZarfComponentImport struct {
 	// The name of the component to import from the referenced zarf.yaml.
 	Name string `json:"name,omitempty"`
-	// The path to the directory containing the zarf.yaml to import.
+	// The path to the directory containing the zarf.yaml to import, within the repository when the URL is a git repository.
 	Path string `json:"path,omitempty"`
-	// [beta] The URL to a Zarf package to import via OCI.
-	URL string `json:"url,omitempty" jsonschema:"pattern=^oci://.*$"`
+	// [beta] The URL to a Zarf package to import via OCI, whose tag can be a semver range, or to a git repository (optionally followed by @ref) to import from.
+	URL string `json:"url,omitempty" jsonschema:"pattern=^(oci|https?|ssh|file)://.*$"`
 }
 
 // JSONSchemaExtend extends the generated json schema during `zarf internal gen-config-schema`
@@ -31,6 +31,8 @@ const (
 	ZarfPackageVariablePrefix = "###ZARF_PKG_VAR_"
 	ZarfPackageArch           = "###ZARF_PKG_ARCH###"
 	ZarfComponentName         = "###ZARF_COMPONENT_NAME###"
+	// ZarfImageLabelPrefix selects the local images with a label instead of naming an image (e.g. docker-label:com.example.build=123)
+	ZarfImageLabelPrefix = "docker-label:"
 )
 
 // ZarfPackageKind is an enum of the different kinds of Zarf packages.
@@ -160,6 +162,8 @@ type ZarfMetadata struct {
 	Architecture string `json:"architecture,omitempty" jsonschema:"example=arm64,example=amd64"`
 	// Yaml OnLy Online (YOLO): True enables deploying a Zarf package without first running zarf init against the cluster. This is ideal for connected environments where you want to use existing VCS and container registries.
 	YOLO bool `json:"yolo,omitempty"`
+	// Fail the deployment instead of warning when the Zarf agent would not rewrite the image references of this package.
+	RequireAgent bool `json:"requireAgent,omitempty"`
 	// Comma-separated list of package authors (including contact info).
 	Authors string `json:"authors,omitempty" jsonschema:"example=Doug &#60;hello@defenseunicorns.com&#62;&#44; Pepr &#60;hello@defenseunicorns.com&#62;"`
 	// Link to package documentation when online.
@@ -194,8 +198,20 @@ type ZarfBuildData struct {
 	DifferentialPackageVersion string `json:"differentialPackageVersion,omitempty"`
 	// List of components that were not included in this package due to differential packaging.
 	DifferentialMissing []string `json:"differentialMissing,omitempty"`
+	// The published package this differential package was created from, which has to be deployed before it.
+	DifferentialPackage string `json:"differentialPackage,omitempty"`
 	// The minimum version of Zarf that does not have breaking package structure changes.
 	LastNonBreakingVersion string `json:"lastNonBreakingVersion,omitempty"`
 	// The flavor of Zarf used to build this package.
 	Flavor string `json:"flavor,omitempty"`
+	// The images and git repositories this package was built from, recorded for its provenance.
+	Materials []ZarfBuildMaterial `json:"materials,omitempty"`
+}
+
+// ZarfBuildMaterial is an image or git repository a package was built from.
+type ZarfBuildMaterial struct {
+	// The URI of the material, oci:// for an image and git+ for a git repository.
+	URI string `json:"uri"`
+	// The digests of the material by algorithm, sha256 for an image and gitCommit for a git repository.
+	Digest map[string]string `json:"digest"`
 }
@@ -5,6 +5,8 @@
 package v1beta1
 
 import (
+	"path"
+
 	"github.com/invopop/jsonschema"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -44,6 +46,12 @@ type ZarfComponent struct {
 	// List of OCI images to include in the package.
 	Images []string `json:"images,omitempty"`
 
+	// Cosign signatures the images must carry, verified against the pulled image digests during package create.
+	ImageSignatures []ZarfImageSignature `json:"imageSignatures,omitempty"`
+
+	// Images to squash into a single layer on package create, the digest of the original image is recorded in the 'dev.zarf.image.squashed-from' manifest annotation (supports '*' globbing).
+	SquashImages []string `json:"squashImages,omitempty" jsonschema:"example=ghcr.io/stefanprodan/podinfo:*"`
+
 	// List of git repos to include in the package.
 	Repos []string `json:"repos,omitempty"`
 
@@ -92,6 +100,31 @@ type ZarfComponentOnlyCluster struct {
 	Distros []string `json:"distros,omitempty" jsonschema:"example=k3s,example=eks"`
 }
 
+// ZarfImageSignature defines a cosign signature that images must carry to be included in the package.
+type ZarfImageSignature struct {
+	// Images the signature is required on, defaults to all images of the component (supports '*' globbing).
+	Images []string `json:"images,omitempty" jsonschema:"example=ghcr.io/stefanprodan/podinfo:*"`
+	// Path, URL or KMS URI of the cosign public key the images must be signed with.
+	Key string `json:"key,omitempty" jsonschema:"example=cosign.pub,example=awskms:///alias/cosign"`
+	// (keyless) Regular expression the identity of the signing certificate must match.
+	Identity string `json:"identity,omitempty" jsonschema:"example=^https://github.com/stefanprodan/podinfo/.github/workflows/release.yml@refs/tags/.*$"`
+	// (keyless) Regular expression the OIDC issuer of the signing certificate must match.
+	Issuer string `json:"issuer,omitempty" jsonschema:"example=^https://token.actions.githubusercontent.com$"`
+}
+
+// Matches returns if the signature is required on the given image.
+func (s ZarfImageSignature) Matches(image string) bool {
+	if len(s.Images) == 0 {
+		return true
+	}
+	for _, pattern := range s.Images {
+		if matched, _ := path.Match(pattern, image); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ZarfFile defines a file to deploy.
 type ZarfFile struct {
 	// Local folder or file path or remote URL to pull into the package.
@@ -106,6 +139,29 @@ type ZarfFile struct {
 	Symlinks []string `json:"symlinks,omitempty"`
 	// Local folder or file to be extracted from a 'source' archive.
 	ExtractPath string `json:"extractPath,omitempty"`
+	// Octal permissions to set on the file, or on each file within the folder, during package deploy (overrides executable).
+	Mode string `json:"mode,omitempty" jsonschema:"example=0644,example=0755"`
+	// User ID to set as the owner of the file or folder during package deploy.
+	UID *int `json:"uid,omitempty"`
+	// Group ID to set as the group of the file or folder during package deploy.
+	GID *int `json:"gid,omitempty"`
+	// Install the file as a systemd unit during package deploy.
+	Systemd ZarfFileSystemd `json:"systemd,omitempty"`
+}
+
+// ZarfFileSystemd installs a file as a systemd unit, the name of the unit is the name of the target.
+type ZarfFileSystemd struct {
+	// Reload the systemd manager configuration after the unit is placed, implied by enable and start.
+	Install bool `json:"install,omitempty"`
+	// Enable the unit to start on boot.
+	Enable bool `json:"enable,omitempty"`
+	// Start the unit, restarting it if it is already running.
+	Start bool `json:"start,omitempty"`
+}
+
+// IsUnit returns if the file is installed as a systemd unit.
+func (s ZarfFileSystemd) IsUnit() bool {
+	return s.Install || s.Enable || s.Start
 }
 
 // ZarfChart defines a helm chart to be deployed.
@@ -308,10 +364,10 @@ type ZarfDataInjection struct {
 type ZarfComponentImport struct {
 	// The name of the component to import from the referenced zarf.yaml.
 	Name string `json:"name,omitempty"`
-	// The path to the directory containing the zarf.yaml to import.
+	// The path to the directory containing the zarf.yaml to import, within the repository when the URL is a git repository.
 	Path string `json:"path,omitempty"`
-	// [beta] The URL to a Zarf package to import via OCI.
-	URL string `json:"url,omitempty" jsonschema:"pattern=^oci://.*$"`
+	// [beta] The URL to a Zarf package to import via OCI, whose tag can be a semver range, or to a git repository (optionally followed by @ref) to import from.
+	URL string `json:"url,omitempty" jsonschema:"pattern=^(oci|https?|ssh|file)://.*$"`
 }
 
 // JSONSchemaExtend extends the generated json schema during `zarf internal gen-config-schema`
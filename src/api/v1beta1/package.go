@@ -173,8 +173,20 @@ type ZarfBuildData struct {
 	DifferentialPackageVersion string `json:"differentialPackageVersion,omitempty"`
 	// List of components that were not included in this package due to differential packaging.
 	DifferentialMissing []string `json:"differentialMissing,omitempty"`
+	// The published package this differential package was created from, which has to be deployed before it.
+	DifferentialPackage string `json:"differentialPackage,omitempty"`
 	// The minimum version of Zarf that does not have breaking package structure changes.
 	LastNonBreakingVersion string `json:"lastNonBreakingVersion,omitempty"`
 	// The flavor of Zarf used to build this package.
 	Flavor string `json:"flavor,omitempty"`
+	// The images and git repositories this package was built from, recorded for its provenance.
+	Materials []ZarfBuildMaterial `json:"materials,omitempty"`
+}
+
+// ZarfBuildMaterial is an image or git repository a package was built from.
+type ZarfBuildMaterial struct {
+	// The URI of the material, oci:// for an image and git+ for a git repository.
+	URI string `json:"uri"`
+	// The digests of the material by algorithm, sha256 for an image and gitCommit for a git repository.
+	Digest map[string]string `json:"digest"`
 }
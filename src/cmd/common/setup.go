@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"time"
 
@@ -17,12 +18,14 @@ import (
 )
 
 // SetupCLI sets up the CLI logging
-func SetupCLI(logLevel string, skipLogFile, noColor bool) error {
+func SetupCLI(logLevel string, verbosity int, skipLogFile, noColor bool) error {
 	if noColor {
 		message.DisableColor()
 	}
 
-	printViperConfigUsed()
+	if err := printViperConfigUsed(); err != nil {
+		return err
+	}
 
 	if logLevel != "" {
 		match := map[string]message.LogLevel{
@@ -39,6 +42,14 @@ func SetupCLI(logLevel string, skipLogFile, noColor bool) error {
 		message.Debug("Log level set to " + logLevel)
 	}
 
+	if verbosity > 0 {
+		message.SetVerbosity(verbosity)
+		message.Debugf("Verbosity set to %d", verbosity)
+	}
+
+	// Libraries such as the tunnels of the cluster package log to the default logger
+	slog.SetDefault(slog.New(message.ZarfHandler{}))
+
 	// Disable progress bars for CI envs
 	if os.Getenv("CI") == "true" {
 		message.Debug("CI environment detected, disabling progress bars")
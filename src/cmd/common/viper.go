@@ -6,13 +6,20 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"os"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/telemetry"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // Constants for use when loading configurations from viper config files
@@ -28,11 +35,59 @@ const (
 	VZarfCache    = "zarf_cache"
 	VTmpDir       = "tmp_dir"
 	VInsecure     = "insecure"
+	VKubeProxy    = "kube_proxy"
+	VStateKey     = "state_key"
+
+	// Context policy config keys
+
+	VContextPolicy  = "context_policy"
+	VConfirmContext = "confirm_context"
+
+	// Registry rate limit config keys
+
+	VRegistryRateLimit           = "registry_rate_limit"
+	VRegistryRateLimitRetries    = "registry_rate_limit.retries"
+	VRegistryRateLimitMaxBackoff = "registry_rate_limit.max_backoff"
+
+	// Registry TLS config keys
+
+	VRegistryTLS = "registry_tls"
+
+	// Registry auth config keys
+
+	VRegistryAuth = "registry_auth"
+
+	// Connect config keys
+
+	VConnectProfiles    = "connect.profiles"
+	VConnectDefinitions = "connect.definitions"
+
+	// Metrics config keys
+
+	VMetricsEndpoint = "metrics.endpoint"
+	VMetricsProtocol = "metrics.protocol"
+
+	// Registry mirrors config keys
+
+	VRegistryMirrors = "registry_mirrors"
+
+	// URL mirrors config keys
+
+	VURLMirrors = "url_mirrors"
+
+	// Git credentials config keys
+
+	VGitCredentials = "git_credentials"
+
+	// Proxy config keys
+
+	VProxy = "proxy"
 
 	// Init config keys
 
-	VInitComponents   = "init.components"
-	VInitStorageClass = "init.storage_class"
+	VInitComponents    = "init.components"
+	VInitStorageClass  = "init.storage_class"
+	VInitSkipPreflight = "init.skip_preflight"
 
 	// Init Git config keys
 
@@ -44,58 +99,125 @@ const (
 
 	// Init Registry config keys
 
-	VInitRegistryURL      = "init.registry.url"
-	VInitRegistryNodeport = "init.registry.nodeport"
-	VInitRegistrySecret   = "init.registry.secret"
-	VInitRegistryPushUser = "init.registry.push_username"
-	VInitRegistryPushPass = "init.registry.push_password"
-	VInitRegistryPullUser = "init.registry.pull_username"
-	VInitRegistryPullPass = "init.registry.pull_password"
+	VInitRegistryURL              = "init.registry.url"
+	VInitRegistryNodeport         = "init.registry.nodeport"
+	VInitRegistrySecret           = "init.registry.secret"
+	VInitRegistryPushUser         = "init.registry.push_username"
+	VInitRegistryPushPass         = "init.registry.push_password"
+	VInitRegistryPullUser         = "init.registry.pull_username"
+	VInitRegistryPullPass         = "init.registry.pull_password"
+	VInitRegistryPullPerNamespace = "init.registry.pull_per_namespace"
+	VInitRegistryAdditionalPull   = "init.registry.additional_pull_credentials"
+	VInitRegistryCredProvider     = "init.registry.credential_provider"
+	VInitRegistryReplicas         = "init.registry.replicas"
+	VInitRegistryS3Bucket         = "init.registry.s3.bucket"
+	VInitRegistryS3Region         = "init.registry.s3.region"
+	VInitRegistryS3Endpoint       = "init.registry.s3.endpoint"
+	VInitRegistryS3RootDirectory  = "init.registry.s3.root_directory"
+	VInitRegistryS3AccessKey      = "init.registry.s3.access_key"
+	VInitRegistryS3SecretKey      = "init.registry.s3.secret_key"
 
 	// Init Package config keys
 
-	VInitArtifactURL       = "init.artifact.url"
-	VInitArtifactPushUser  = "init.artifact.push_username"
-	VInitArtifactPushToken = "init.artifact.push_token"
+	VInitArtifactURL               = "init.artifact.url"
+	VInitArtifactPushUser          = "init.artifact.push_username"
+	VInitArtifactPushToken         = "init.artifact.push_token"
+	VInitArtifactPushTokenLifetime = "init.artifact.push_token_lifetime"
+
+	// Init Agent TLS config keys
+
+	VInitAgentTLSCA   = "init.agent_tls.ca"
+	VInitAgentTLSCert = "init.agent_tls.cert"
+	VInitAgentTLSKey  = "init.agent_tls.key"
+
+	// Init secret namespaces config keys
+
+	VInitSecretNamespacesAllow = "init.secret_namespaces.allow"
+	VInitSecretNamespacesDeny  = "init.secret_namespaces.deny"
 
 	// Package config keys
 
-	VPkgOCIConcurrency = "package.oci_concurrency"
-	VPkgPublicKey      = "package.public_key"
+	VPkgOCIConcurrency    = "package.oci_concurrency"
+	VPkgImageConcurrency  = "package.image_concurrency"
+	VPkgPublicKey         = "package.public_key"
+	VPkgAllowPlugins      = "package.allow_plugins"
+	VPkgDecryptIdentity   = "package.decrypt_identity"
+	VPkgDecryptPassphrase = "package.decrypt_passphrase"
 
 	// Package create config keys
 
-	VPkgCreateSet                = "package.create.set"
-	VPkgCreateOutput             = "package.create.output"
-	VPkgCreateSbom               = "package.create.sbom"
-	VPkgCreateSbomOutput         = "package.create.sbom_output"
-	VPkgCreateSkipSbom           = "package.create.skip_sbom"
-	VPkgCreateMaxPackageSize     = "package.create.max_package_size"
-	VPkgCreateSigningKey         = "package.create.signing_key"
-	VPkgCreateSigningKeyPassword = "package.create.signing_key_password"
-	VPkgCreateDifferential       = "package.create.differential"
-	VPkgCreateRegistryOverride   = "package.create.registry_override"
-	VPkgCreateFlavor             = "package.create.flavor"
+	VPkgCreateSet                  = "package.create.set"
+	VPkgCreateEnv                  = "package.create.env"
+	VPkgCreateOutput               = "package.create.output"
+	VPkgCreateSbom                 = "package.create.sbom"
+	VPkgCreateSbomOutput           = "package.create.sbom_output"
+	VPkgCreateSkipSbom             = "package.create.skip_sbom"
+	VPkgCreateSkipSbomComponents   = "package.create.skip_sbom_components"
+	VPkgCreateCompressSbom         = "package.create.compress_sbom"
+	VPkgCreateScanVulns            = "package.create.scan_vulnerabilities"
+	VPkgCreateVulnDB               = "package.create.vulnerability_db"
+	VPkgCreateFailOnSeverity       = "package.create.fail_on_severity"
+	VPkgCreateMaxPackageSize       = "package.create.max_package_size"
+	VPkgCreateEncrypt              = "package.create.encrypt"
+	VPkgCreateEncryptPassphrase    = "package.create.encrypt_passphrase"
+	VPkgCreateMaxCacheSize         = "package.create.max_cache_size"
+	VPkgCreateAllPlatforms         = "package.create.all_platforms"
+	VPkgCreateIncludeReferrers     = "package.create.include_referrers"
+	VPkgCreateComponentConcurrency = "package.create.component_concurrency"
+	VPkgCreateRecompressZstd       = "package.create.recompress_zstd"
+	VPkgCreateEstargz              = "package.create.estargz"
+	VPkgCreateImagePolicy          = "package.create.image_policy"
+	VPkgCreateContainerdAddress    = "package.create.containerd_address"
+	VPkgCreateContainerdNS         = "package.create.containerd_namespace"
+	VPkgCreateSigningKey           = "package.create.signing_key"
+	VPkgCreateSigningKeyPassword   = "package.create.signing_key_password"
+	VPkgCreateDifferential         = "package.create.differential"
+	VPkgCreateDifferentialFrom     = "package.create.differential_from"
+	VPkgCreateRegistryOverride     = "package.create.registry_override"
+	VPkgCreateFlavor               = "package.create.flavor"
+	VPkgCreateRemoteBuilder        = "package.create.remote_builder"
+	VPkgCreateRemoteBuilderToken   = "package.create.remote_builder_token"
 
 	// Package deploy config keys
 
-	VPkgDeploySet          = "package.deploy.set"
-	VPkgDeployComponents   = "package.deploy.components"
-	VPkgDeployShasum       = "package.deploy.shasum"
-	VPkgDeploySget         = "package.deploy.sget"
-	VPkgDeploySkipWebhooks = "package.deploy.skip_webhooks"
-	VPkgDeployTimeout      = "package.deploy.timeout"
-	VPkgRetries            = "package.deploy.retries"
+	VPkgDeploySet           = "package.deploy.set"
+	VPkgDeployComponents    = "package.deploy.components"
+	VPkgDeployShasum        = "package.deploy.shasum"
+	VPkgDeploySget          = "package.deploy.sget"
+	VPkgDeploySkipWebhooks  = "package.deploy.skip_webhooks"
+	VPkgDeployRequireAgent  = "package.deploy.require_agent"
+	VPkgDeploySkip          = "package.deploy.skip"
+	VPkgDeployTimeout       = "package.deploy.timeout"
+	VPkgDeployHelmDebugDir  = "package.deploy.helm_debug_dir"
+	VPkgDeployAnswers       = "package.deploy.answers"
+	VPkgDeployExportAnswers = "package.deploy.export_answers"
+	VPkgRetries             = "package.deploy.retries"
+	VPkgDeployRetain        = "package.deploy.retain"
+	VPkgDeployRetainDir     = "package.deploy.retain_dir"
+	VPkgDeployRetainMax     = "package.deploy.retain_max"
+	VPkgDeployTrustRoot     = "package.deploy.trust_root"
 
 	// Package publish config keys
 
 	VPkgPublishSigningKey         = "package.publish.signing_key"
 	VPkgPublishSigningKeyPassword = "package.publish.signing_key_password"
+	VPkgPublishCredentials        = "package.publish.credentials"
+	VPkgPublishChunkSize          = "package.publish.chunk_size"
 
 	// Package pull config keys
 
 	VPkgPullOutputDir = "package.pull.output_directory"
 
+	// Serve config keys
+
+	VServeAddress            = "serve.address"
+	VServeToken              = "serve.token"
+	VServeTLSCert            = "serve.tls_cert"
+	VServeTLSKey             = "serve.tls_key"
+	VServeSigningKey         = "serve.signing_key"
+	VServeSigningKeyPassword = "serve.signing_key_password"
+	VServeMaxUploadSize      = "serve.max_upload_size"
+
 	// Dev deploy config keys
 
 	VDevDeployNoYolo = "dev.deploy.no_yolo"
@@ -107,6 +229,8 @@ var (
 
 	// Viper configuration error
 	vConfigError error
+
+	errInterpolateConfig = errors.New("unable to interpolate the config file")
 )
 
 // InitViper initializes the viper singleton for the CLI
@@ -123,6 +247,24 @@ func InitViper() *viper.Viper {
 		return v
 	}
 
+	// Optional, so ignore errors
+	vConfigError = readConfig(v)
+
+	// Set default values for viper
+	setDefaults()
+
+	return v
+}
+
+// NewVendorViper returns a viper that reads the config file for vendored commands, which InitViper skips.
+func NewVendorViper() *viper.Viper {
+	vendorViper := viper.New()
+	_ = readConfig(vendorViper)
+	return vendorViper
+}
+
+// readConfig sets up the viper to read the config file and ZARF_ environment variables and reads the config file.
+func readConfig(v *viper.Viper) error {
 	// Specify an alternate config file
 	cfgFile := os.Getenv("ZARF_CONFIG")
 
@@ -142,13 +284,25 @@ func InitViper() *viper.Viper {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
-	// Optional, so ignore errors
-	vConfigError = v.ReadInConfig()
-
-	// Set default values for viper
-	setDefaults()
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+	return interpolateConfig(v)
+}
 
-	return v
+// interpolateConfig expands the references to environment variables and other keys in the values of the config file.
+func interpolateConfig(v *viper.Viper) error {
+	// Read the file on its own so the environment variables and defaults don't become values of the config file
+	fileViper := viper.New()
+	fileViper.SetConfigFile(v.ConfigFileUsed())
+	if err := fileViper.ReadInConfig(); err != nil {
+		return err
+	}
+	expanded, err := utils.InterpolateConfig(fileViper.AllSettings(), os.LookupEnv)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInterpolateConfig, err)
+	}
+	return v.MergeConfigMap(expanded)
 }
 
 // GetViper returns the viper singleton
@@ -156,37 +310,315 @@ func GetViper() *viper.Viper {
 	return v
 }
 
+// GetRegistryMirrors returns the registry mirrors configured in the config file.
+func GetRegistryMirrors(v *viper.Viper) (types.RegistryMirrors, error) {
+	mirrors := types.RegistryMirrors{}
+	if err := v.UnmarshalKey(VRegistryMirrors, &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VRegistryMirrors, err)
+	}
+	return mirrors, nil
+}
+
+// GetAdditionalPullCredentials returns the credentials of other registries to add to the image pull secrets configured in the config file.
+func GetAdditionalPullCredentials(v *viper.Viper) ([]types.RegistryCredential, error) {
+	// Nil rather than empty when unset so that update-creds keeps the credentials in the state
+	if !v.IsSet(VInitRegistryAdditionalPull) {
+		return nil, nil
+	}
+	credentials := []types.RegistryCredential{}
+	if err := v.UnmarshalKey(VInitRegistryAdditionalPull, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VInitRegistryAdditionalPull, err)
+	}
+	for i, credential := range credentials {
+		if credential.Address == "" {
+			return nil, fmt.Errorf("invalid %s configuration: entry %d is missing an address", VInitRegistryAdditionalPull, i)
+		}
+	}
+	return credentials, nil
+}
+
+// GetPublishCredentials returns the credentials of the registries to publish packages to configured in the config file.
+func GetPublishCredentials(v *viper.Viper) ([]types.RegistryCredential, error) {
+	credentials := []types.RegistryCredential{}
+	if err := v.UnmarshalKey(VPkgPublishCredentials, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VPkgPublishCredentials, err)
+	}
+	for i, credential := range credentials {
+		if credential.Address == "" {
+			return nil, fmt.Errorf("invalid %s configuration: entry %d is missing an address", VPkgPublishCredentials, i)
+		}
+	}
+	return credentials, nil
+}
+
+// GetURLMirrors returns the URL mirrors configured in the config file.
+func GetURLMirrors(v *viper.Viper) (map[string][]string, error) {
+	mirrors := map[string][]string{}
+	if err := v.UnmarshalKey(VURLMirrors, &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VURLMirrors, err)
+	}
+	return mirrors, nil
+}
+
+// GetGitCredentials returns the credentials of git repositories configured in the config file.
+func GetGitCredentials(v *viper.Viper) ([]types.GitCredential, error) {
+	credentials := []types.GitCredential{}
+	if err := v.UnmarshalKey(VGitCredentials, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VGitCredentials, err)
+	}
+	for i, credential := range credentials {
+		if credential.URL == "" {
+			return nil, fmt.Errorf("invalid %s configuration: entry %d is missing a url", VGitCredentials, i)
+		}
+	}
+	return credentials, nil
+}
+
+// GetProxyConfig returns the proxy configured in the config file.
+func GetProxyConfig(v *viper.Viper) (types.ProxyConfig, error) {
+	proxy := types.ProxyConfig{}
+	if err := v.UnmarshalKey(VProxy, &proxy); err != nil {
+		return types.ProxyConfig{}, fmt.Errorf("invalid %s configuration: %w", VProxy, err)
+	}
+	return proxy, nil
+}
+
+// GetContextPolicy returns the policy of the kubeconfig contexts Zarf may target configured in the config file.
+func GetContextPolicy(v *viper.Viper) (types.ContextPolicy, error) {
+	policy := types.ContextPolicy{}
+	if err := v.UnmarshalKey(VContextPolicy, &policy); err != nil {
+		return types.ContextPolicy{}, fmt.Errorf("invalid %s configuration: %w", VContextPolicy, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return types.ContextPolicy{}, fmt.Errorf("invalid %s configuration: %w", VContextPolicy, err)
+	}
+	return policy, nil
+}
+
+// GetRegistryRateLimit returns the limits on the requests sent to each registry configured in the config file.
+func GetRegistryRateLimit(v *viper.Viper) (types.RegistryRateLimit, error) {
+	limit := types.RegistryRateLimit{}
+	if err := v.UnmarshalKey(VRegistryRateLimit, &limit); err != nil {
+		return types.RegistryRateLimit{}, fmt.Errorf("invalid %s configuration: %w", VRegistryRateLimit, err)
+	}
+	if err := limit.Validate(); err != nil {
+		return types.RegistryRateLimit{}, fmt.Errorf("invalid %s configuration: %w", VRegistryRateLimit, err)
+	}
+	return limit, nil
+}
+
+// GetRegistryTLS returns the TLS configuration of each registry configured in the config file.
+func GetRegistryTLS(v *viper.Viper) (map[string]types.RegistryTLS, error) {
+	registries := map[string]types.RegistryTLS{}
+	if err := v.UnmarshalKey(VRegistryTLS, &registries); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VRegistryTLS, err)
+	}
+	for registry, settings := range registries {
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s configuration for %s: %w", VRegistryTLS, registry, err)
+		}
+	}
+	return registries, nil
+}
+
+// GetRegistryAuth returns the credential provider of each registry configured in the config file.
+func GetRegistryAuth(v *viper.Viper) (map[string]types.RegistryAuth, error) {
+	registries := map[string]types.RegistryAuth{}
+	if err := v.UnmarshalKey(VRegistryAuth, &registries); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VRegistryAuth, err)
+	}
+	for registry, settings := range registries {
+		if err := settings.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s configuration for %s: %w", VRegistryAuth, registry, err)
+		}
+	}
+	return registries, nil
+}
+
+// GetConnectProfile returns the targets of a connect profile configured in the config file.
+func GetConnectProfile(v *viper.Viper, name string) ([]string, error) {
+	profiles := map[string][]string{}
+	if err := v.UnmarshalKey(VConnectProfiles, &profiles); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VConnectProfiles, err)
+	}
+	targets, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("the connect profile %q is not in %s of the config file", name, VConnectProfiles)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("the connect profile %q has no targets", name)
+	}
+	return targets, nil
+}
+
+// GetConnectDefinitions returns the connect targets declared in the config file by their name.
+func GetConnectDefinitions(v *viper.Viper) (map[string]types.ConnectDefinition, error) {
+	definitions := map[string]types.ConnectDefinition{}
+	if err := v.UnmarshalKey(VConnectDefinitions, &definitions); err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %w", VConnectDefinitions, err)
+	}
+	return definitions, nil
+}
+
+// GetStringOrSlice returns the value of a key as a string, joining the values with commas if it is a list.
+func GetStringOrSlice(v *viper.Viper, key string) string {
+	if values, ok := v.Get(key).([]any); ok {
+		strs := []string{}
+		for _, value := range values {
+			strs = append(strs, fmt.Sprint(value))
+		}
+		return strings.Join(strs, ",")
+	}
+	return v.GetString(key)
+}
+
+// ValidateInitConfig validates the init section of the config file in use against the supported init config keys.
+func ValidateInitConfig() error {
+	if v == nil || vConfigError != nil || v.ConfigFileUsed() == "" {
+		return nil
+	}
+
+	// Read the file on its own so that defaults and environment variables are not validated
+	fileViper := viper.New()
+	fileViper.SetConfigFile(v.ConfigFileUsed())
+	if err := fileViper.ReadInConfig(); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, key := range fileViper.AllKeys() {
+		if !strings.HasPrefix(key, "init.") {
+			continue
+		}
+		kind, ok := initConfigKeys[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", key))
+			continue
+		}
+		value := fileViper.Get(key)
+		switch kind {
+		case reflect.Int:
+			isInt := false
+			switch n := value.(type) {
+			case int, int64:
+				isInt = true
+			case float64:
+				// JSON config files decode all numbers as floats
+				isInt = n == math.Trunc(n)
+			}
+			if !isInt {
+				errs = append(errs, fmt.Errorf("key %q must be an integer", key))
+			}
+		case reflect.Bool:
+			if _, isBool := value.(bool); !isBool {
+				errs = append(errs, fmt.Errorf("key %q must be a boolean", key))
+			}
+		case reflect.Slice:
+			if _, isSlice := value.([]any); !isSlice {
+				if _, isString := value.(string); !isString {
+					errs = append(errs, fmt.Errorf("key %q must be a string or a list of strings", key))
+				}
+			}
+		default:
+			if _, isString := value.(string); !isString {
+				errs = append(errs, fmt.Errorf("key %q must be a string", key))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid init configuration in %s: %w", v.ConfigFileUsed(), errors.Join(errs...))
+	}
+	return nil
+}
+
+// initConfigKeys are the keys supported in the init section of a config file and their expected kind
+var initConfigKeys = map[string]reflect.Kind{
+	VInitComponents:                reflect.Slice,
+	VInitStorageClass:              reflect.String,
+	VInitSkipPreflight:             reflect.Bool,
+	VInitGitURL:                    reflect.String,
+	VInitGitPushUser:               reflect.String,
+	VInitGitPushPass:               reflect.String,
+	VInitGitPullUser:               reflect.String,
+	VInitGitPullPass:               reflect.String,
+	VInitRegistryURL:               reflect.String,
+	VInitRegistryNodeport:          reflect.Int,
+	VInitRegistrySecret:            reflect.String,
+	VInitRegistryPushUser:          reflect.String,
+	VInitRegistryPushPass:          reflect.String,
+	VInitRegistryPullUser:          reflect.String,
+	VInitRegistryPullPass:          reflect.String,
+	VInitRegistryPullPerNamespace:  reflect.Bool,
+	VInitRegistryAdditionalPull:    reflect.Slice,
+	VInitRegistryCredProvider:      reflect.String,
+	VInitRegistryReplicas:          reflect.Int,
+	VInitRegistryS3Bucket:          reflect.String,
+	VInitRegistryS3Region:          reflect.String,
+	VInitRegistryS3Endpoint:        reflect.String,
+	VInitRegistryS3RootDirectory:   reflect.String,
+	VInitRegistryS3AccessKey:       reflect.String,
+	VInitRegistryS3SecretKey:       reflect.String,
+	VInitArtifactURL:               reflect.String,
+	VInitArtifactPushUser:          reflect.String,
+	VInitArtifactPushToken:         reflect.String,
+	VInitArtifactPushTokenLifetime: reflect.String,
+	VInitAgentTLSCA:                reflect.String,
+	VInitAgentTLSCert:              reflect.String,
+	VInitAgentTLSKey:               reflect.String,
+	VInitSecretNamespacesAllow:     reflect.Slice,
+	VInitSecretNamespacesDeny:      reflect.Slice,
+}
+
 func isVersionCmd() bool {
 	args := os.Args
 	return len(args) > 1 && (args[1] == "version" || args[1] == "v")
 }
 
-func printViperConfigUsed() {
+func printViperConfigUsed() error {
 	// Only print config info if viper is initialized.
 	vInitialized := v != nil
 	if !vInitialized {
-		return
+		return nil
 	}
 	var notFoundErr viper.ConfigFileNotFoundError
 	if errors.As(vConfigError, &notFoundErr) {
-		return
+		return nil
+	}
+	// Don't run with the unexpanded references of a config file that failed to interpolate
+	if errors.Is(vConfigError, errInterpolateConfig) {
+		return vConfigError
 	}
 	if vConfigError != nil {
 		message.WarnErrf(vConfigError, lang.CmdViperErrLoadingConfigFile, vConfigError.Error())
-		return
+		return nil
 	}
 	message.Notef(lang.CmdViperInfoUsingConfigFile, v.ConfigFileUsed())
+	return nil
 }
 
 func setDefaults() {
 	// Root defaults that are non-zero values
 	v.SetDefault(VLogLevel, "info")
 	v.SetDefault(VZarfCache, config.ZarfDefaultCachePath)
+	v.SetDefault(VMetricsProtocol, telemetry.ProtocolPushgateway)
+	v.SetDefault(VRegistryRateLimitRetries, 5)
+	v.SetDefault(VRegistryRateLimitMaxBackoff, time.Minute)
 
 	// Package defaults that are non-zero values
 	v.SetDefault(VPkgOCIConcurrency, 3)
+	v.SetDefault(VPkgImageConcurrency, 10)
 	v.SetDefault(VPkgRetries, config.ZarfDefaultRetries)
 
+	// Create opts that are non-zero values
+	v.SetDefault(VPkgCreateComponentConcurrency, 1)
+
 	// Deploy opts that are non-zero values
 	v.SetDefault(VPkgDeployTimeout, config.ZarfDefaultTimeout)
+
+	// Publish opts that are non-zero values
+	v.SetDefault(VPkgPublishChunkSize, 100)
+
+	// Serve opts that are non-zero values
+	v.SetDefault(VServeAddress, "127.0.0.1:8090")
+	v.SetDefault(VServeMaxUploadSize, 1024)
 }
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package common handles command configuration across all commands
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStringOrSlice(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{
+			name:     "unset",
+			expected: "",
+		},
+		{
+			name:     "string",
+			value:    "logging,git-server",
+			expected: "logging,git-server",
+		},
+		{
+			name:     "list",
+			value:    []any{"logging", "git-server"},
+			expected: "logging,git-server",
+		},
+		{
+			name:     "list of other values",
+			value:    []any{"logging", 1, true},
+			expected: "logging,1,true",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := viper.New()
+			if tt.value != nil {
+				v.Set(VInitComponents, tt.value)
+			}
+			require.Equal(t, tt.expected, GetStringOrSlice(v, VInitComponents))
+		})
+	}
+}
+
+func TestValidateInitConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		expectedErrs []string
+	}{
+		{
+			name: "valid config",
+			config: `
+[init]
+components = ['logging', 'git-server']
+storage_class = 'local-path'
+skip_preflight = true
+
+[init.registry]
+nodeport = 31999
+`,
+		},
+		{
+			name: "components as a string",
+			config: `
+[init]
+components = 'logging,git-server'
+`,
+		},
+		{
+			name: "other sections are not validated",
+			config: `
+[package.deploy]
+unknown = true
+`,
+		},
+		{
+			name: "invalid config",
+			config: `
+[init]
+componets = ['logging']
+storage_class = 1
+skip_preflight = 'yes'
+
+[init.registry]
+nodeport = 'thirty'
+`,
+			expectedErrs: []string{
+				`unknown key "init.componets"`,
+				`key "init.storage_class" must be a string`,
+				`key "init.skip_preflight" must be a boolean`,
+				`key "init.registry.nodeport" must be an integer`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "zarf-config.toml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+
+			oldV, oldErr := v, vConfigError
+			t.Cleanup(func() {
+				v, vConfigError = oldV, oldErr
+			})
+			v = viper.New()
+			v.SetConfigFile(path)
+			vConfigError = v.ReadInConfig()
+			require.NoError(t, vConfigError)
+
+			err := ValidateInitConfig()
+			if len(tt.expectedErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, "invalid init configuration in "+path)
+			for _, expectedErr := range tt.expectedErrs {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
@@ -5,29 +5,89 @@
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 
+	"github.com/zarf-dev/zarf/src/cmd/common"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/background"
+	"github.com/zarf-dev/zarf/src/internal/gitea"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
+// connectBackgroundEnv names the record of a zarf connect process started with --background, it is only set in the
+// environment of that process.
+const connectBackgroundEnv = "ZARF_CONNECT_BACKGROUND_NAME"
+
 var (
-	cliOnly bool
-	zt      cluster.TunnelInfo
+	cliOnly              bool
+	ephemeralCredentials bool
+	connectProfile       string
+	connectProtocol      string
+	localTLS             bool
+	localTLSCert         string
+	localTLSKey          string
+	connectBackground    bool
+	connectReconnect     bool
+	connectOutput        string
+	zt                   cluster.TunnelInfo
 )
 var connectCmd = &cobra.Command{
-	Use:     "connect { REGISTRY | GIT | connect-name }",
+	Use:     "connect { REGISTRY | GIT | connect-name }[:LOCAL_PORT]...",
 	Aliases: []string{"c"},
 	Short:   lang.CmdConnectShort,
 	Long:    lang.CmdConnectLong,
+	Example: lang.CmdConnectExample,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		switch connectOutput {
+		case "text":
+		case "json":
+			// Tools reading the endpoints have no use for a browser
+			cliOnly = true
+		default:
+			return fmt.Errorf(lang.CmdConnectErrOutput, connectOutput)
+		}
+		targets := args
+		if connectProfile != "" {
+			profileTargets, err := common.GetConnectProfile(common.GetViper(), connectProfile)
+			if err != nil {
+				return err
+			}
+			targets = append(profileTargets, args...)
+		}
+		if os.Getenv(connectBackgroundEnv) != "" {
+			// There is no one to open a browser for in the background
+			cliOnly = true
+		} else if connectBackground {
+			return connectInBackground(cmd.Context(), backgroundName(targets))
+		}
+		if len(targets) > 1 {
+			return connectTargets(cmd.Context(), targets)
+		}
+
 		target := ""
-		if len(args) > 0 {
-			target = args[0]
+		if len(targets) > 0 {
+			target = targets[0]
 		}
 
 		spinner := message.NewProgressSpinner(lang.CmdConnectPreparingTunnel, target)
@@ -40,18 +100,61 @@ var connectCmd = &cobra.Command{
 
 		ctx := cmd.Context()
 
+		tlsConfig, err := localTLSConfig()
+		if err != nil {
+			return err
+		}
+
 		var tunnel *cluster.Tunnel
 		if target == "" {
+			if zt.Selector != "" {
+				if zt.ResourceName != "" {
+					return errors.New(lang.CmdConnectErrSelectorName)
+				}
+				if zt.ResourceType != cluster.PodResource {
+					return fmt.Errorf(lang.CmdConnectErrSelectorType, zt.ResourceType)
+				}
+				// The pod the selector resolves to is replaced whenever the tunnel is re-established
+				connectReconnect = true
+			}
+			switch protocol := corev1.Protocol(strings.ToUpper(connectProtocol)); protocol {
+			case corev1.ProtocolTCP, corev1.ProtocolUDP:
+				zt.Protocol = protocol
+			default:
+				return fmt.Errorf(lang.CmdConnectErrProtocol, connectProtocol)
+			}
+			zt.TLSConfig = tlsConfig
 			tunnel, err = c.ConnectTunnelInfo(ctx, zt)
 		} else {
+			var localPort int
+			target, localPort, err = cluster.ParseConnectTarget(target)
+			if err != nil {
+				return err
+			}
 			var ti cluster.TunnelInfo
-			ti, err = c.NewTargetTunnelInfo(ctx, target)
+			var targetCLIOnly bool
+			ti, targetCLIOnly, err = targetTunnelInfo(ctx, c, target)
 			if err != nil {
 				return fmt.Errorf("unable to create tunnel: %w", err)
 			}
+			if targetCLIOnly {
+				cliOnly = true
+			}
+			if localPort != 0 {
+				ti.LocalPort = localPort
+			}
 			if zt.LocalPort != 0 {
 				ti.LocalPort = zt.LocalPort
 			}
+			ti.TLSConfig = tlsConfig
+			if ephemeralCredentials && strings.ToUpper(target) == cluster.ZarfRegistry {
+				// The registry restarts to read the credentials, so they are issued before the tunnel connects to it
+				revoke, err := issueRegistryCredentials(ctx, c)
+				if err != nil {
+					return fmt.Errorf("unable to issue ephemeral credentials: %w", err)
+				}
+				defer revoke()
+			}
 			tunnel, err = c.ConnectTunnelInfo(ctx, ti)
 		}
 
@@ -61,10 +164,27 @@ var connectCmd = &cobra.Command{
 
 		defer tunnel.Close()
 
+		if ephemeralCredentials && strings.ToUpper(target) != cluster.ZarfRegistry {
+			revoke, err := issueEphemeralCredentials(ctx, c, target, tunnel)
+			if err != nil {
+				return fmt.Errorf("unable to issue ephemeral credentials: %w", err)
+			}
+			defer revoke()
+		}
+
 		// Dump the tunnel URL to the console for other tools to use.
-		fmt.Print(tunnel.FullURL())
+		endpoints, err := printEndpoints(tunnel.FullURL(), tunnel.Describe(target))
+		if err != nil {
+			return err
+		}
 
-		if cliOnly {
+		removeRecord, err := recordBackground([]string{tunnel.FullURL()}, endpoints)
+		if err != nil {
+			return err
+		}
+		defer removeRecord()
+
+		if cliOnly || tunnel.Protocol() == corev1.ProtocolUDP {
 			spinner.Updatef(lang.CmdConnectEstablishedCLI, tunnel.FullURL())
 		} else {
 			spinner.Updatef(lang.CmdConnectEstablishedWeb, tunnel.FullURL())
@@ -75,16 +195,191 @@ var connectCmd = &cobra.Command{
 		}
 
 		// Wait for the interrupt signal or an error.
-		select {
-		case <-ctx.Done():
-			spinner.Successf(lang.CmdConnectTunnelClosed, tunnel.FullURL())
-			return nil
-		case err = <-tunnel.ErrChan():
+		if err := waitForTunnel(ctx, tunnel, connectReconnect); err != nil {
 			return fmt.Errorf("lost connection to the service: %w", err)
 		}
+		spinner.Successf(lang.CmdConnectTunnelClosed, tunnel.FullURL())
+		return nil
 	},
 }
 
+// connectTargets opens a tunnel to every target in one process, printing them in a table, and keeps them open until
+// the user interrupts or one of them is lost.
+func connectTargets(ctx context.Context, targets []string) error {
+	if zt.LocalPort != 0 {
+		return errors.New(lang.CmdConnectErrLocalPortMultiple)
+	}
+
+	spinner := message.NewProgressSpinner(lang.CmdConnectPreparingTunnels, len(targets))
+	defer spinner.Stop()
+
+	tlsConfig, err := localTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	c, err := cluster.NewCluster()
+	if err != nil {
+		return err
+	}
+
+	tunnels := []*cluster.Tunnel{}
+	defer func() {
+		for _, tunnel := range tunnels {
+			tunnel.Close()
+		}
+	}()
+	names := []string{}
+	browse := []bool{}
+	rows := [][]string{}
+	described := []cluster.TunnelEndpoint{}
+	for _, target := range targets {
+		name, localPort, err := cluster.ParseConnectTarget(target)
+		if err != nil {
+			return err
+		}
+		spinner.Updatef(lang.CmdConnectPreparingTunnel, name)
+		ti, targetCLIOnly, err := targetTunnelInfo(ctx, c, name)
+		if err != nil {
+			return fmt.Errorf("unable to create tunnel to %s: %w", name, err)
+		}
+		if localPort != 0 {
+			ti.LocalPort = localPort
+		}
+		// UDP services are not served with TLS when other targets are
+		if ti.Protocol != corev1.ProtocolUDP {
+			ti.TLSConfig = tlsConfig
+		}
+		if ephemeralCredentials && strings.ToUpper(name) == cluster.ZarfRegistry {
+			revoke, err := issueRegistryCredentials(ctx, c)
+			if err != nil {
+				return fmt.Errorf("unable to issue ephemeral credentials for %s: %w", name, err)
+			}
+			defer revoke()
+		}
+		tunnel, err := c.ConnectTunnelInfo(ctx, ti)
+		if err != nil {
+			return fmt.Errorf("unable to connect to %s: %w", name, err)
+		}
+		tunnels = append(tunnels, tunnel)
+		names = append(names, name)
+		browse = append(browse, !targetCLIOnly && ti.Protocol != corev1.ProtocolUDP)
+		rows = append(rows, []string{name, fmt.Sprintf("%s/%s/%s:%d", ti.Namespace, ti.ResourceType, ti.ResourceName, ti.RemotePort), tunnel.FullURL()})
+		described = append(described, tunnel.Describe(name))
+
+		// Credentials are only issued for the targets that support them
+		if ephemeralCredentials && strings.ToUpper(name) == cluster.ZarfGit {
+			revoke, err := issueEphemeralCredentials(ctx, c, name, tunnel)
+			if err != nil {
+				return fmt.Errorf("unable to issue ephemeral credentials for %s: %w", name, err)
+			}
+			defer revoke()
+		}
+	}
+	spinner.Stop()
+	var endpoints json.RawMessage
+	if connectOutput == "json" {
+		endpoints, err = printEndpoints("", described)
+		if err != nil {
+			return err
+		}
+	} else {
+		message.Table([]string{"Target", "Resource", "URL"}, rows)
+	}
+
+	urls := []string{}
+	for _, tunnel := range tunnels {
+		urls = append(urls, tunnel.FullURL())
+	}
+	removeRecord, err := recordBackground(urls, endpoints)
+	if err != nil {
+		return err
+	}
+	defer removeRecord()
+
+	if !cliOnly {
+		for i, tunnel := range tunnels {
+			if !browse[i] {
+				continue
+			}
+			if err := exec.LaunchURL(tunnel.FullURL()); err != nil {
+				return err
+			}
+		}
+	}
+	message.Notef(lang.CmdConnectEstablishedMultiple, len(tunnels))
+
+	// Wait for the interrupt signal or the loss of any tunnel.
+	lost := make(chan error, len(tunnels))
+	for i, tunnel := range tunnels {
+		name := names[i]
+		go func() {
+			if err := waitForTunnel(ctx, tunnel, connectReconnect); err != nil {
+				lost <- fmt.Errorf("lost connection to %s: %w", name, err)
+			}
+		}()
+	}
+	select {
+	case <-ctx.Done():
+		message.Successf(lang.CmdConnectTunnelsClosed, len(tunnels))
+		return nil
+	case err := <-lost:
+		return err
+	}
+}
+
+// targetTunnelInfo returns the TunnelInfo of a connect target declared in the connect.definitions section of the
+// config file, or found in the cluster, and whether the definition asks for its URL to only be printed.
+func targetTunnelInfo(ctx context.Context, c *cluster.Cluster, target string) (cluster.TunnelInfo, bool, error) {
+	definitions, err := common.GetConnectDefinitions(common.GetViper())
+	if err != nil {
+		return cluster.TunnelInfo{}, false, err
+	}
+	// Viper lowercases the keys of the config file
+	def, ok := definitions[strings.ToLower(target)]
+	if !ok {
+		ti, err := c.NewTargetTunnelInfo(ctx, target)
+		return ti, false, err
+	}
+	ti, err := c.NewDefinitionTunnelInfo(ctx, target, def)
+	if err != nil {
+		return cluster.TunnelInfo{}, false, err
+	}
+	if ti.Selector != "" {
+		// The pod the selector resolves to is replaced whenever the tunnel is re-established
+		connectReconnect = true
+	}
+	return ti, def.CLIOnly, nil
+}
+
+// reconnectingTunnel is the part of a cluster.Tunnel that waitForTunnel uses.
+type reconnectingTunnel interface {
+	Wait(ctx context.Context) error
+	Reconnect(ctx context.Context) error
+	FullURL() string
+}
+
+// waitForTunnel blocks until the context is done or the tunnel is lost, re-establishing lost tunnels when reconnect
+// is set. It returns the error the tunnel was lost with, or nil once the context is done.
+func waitForTunnel(ctx context.Context, tunnel reconnectingTunnel, reconnect bool) error {
+	for {
+		err := tunnel.Wait(ctx)
+		if err == nil || !reconnect {
+			return err
+		}
+		message.Warnf(lang.CmdConnectReconnecting, tunnel.FullURL(), err)
+		if err := tunnel.Reconnect(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		message.Successf(lang.CmdConnectReconnected, tunnel.FullURL(), time.Now().Format(time.TimeOnly))
+	}
+}
+
+var connectListOutput string
+
 var connectListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l"},
@@ -98,7 +393,101 @@ var connectListCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		message.PrintConnectStringTable(connections)
+		definitions, err := common.GetConnectDefinitions(common.GetViper())
+		if err != nil {
+			return err
+		}
+		// Targets declared in the config file are listed with those labeled in the cluster, taking precedence
+		for name, def := range definitions {
+			connection := connections[name]
+			if def.Description != "" {
+				connection.Description = def.Description
+			}
+			if def.URL != "" {
+				connection.URL = def.URL
+			}
+			connections[name] = connection
+		}
+		switch connectListOutput {
+		case "text":
+			message.PrintConnectStringTable(connections)
+		case "json":
+			b, err := json.MarshalIndent(connections, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(b))
+		default:
+			return fmt.Errorf(lang.CmdConnectErrOutput, connectListOutput)
+		}
+		return nil
+	},
+}
+
+var connectStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: lang.CmdConnectStatusShort,
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		dir := backgroundDir()
+		records, err := background.List(dir)
+		if err != nil {
+			return err
+		}
+		rows := [][]string{}
+		for _, record := range records {
+			// Processes that were killed could not remove their record
+			if !record.Running() {
+				message.Debugf("Removing the record of %s as process %d is no longer running", record.Name, record.PID)
+				if err := background.Remove(dir, record.Name); err != nil {
+					return err
+				}
+				continue
+			}
+			rows = append(rows, []string{record.Name, fmt.Sprint(record.PID), record.Started.Format(time.RFC3339), strings.Join(record.URLs, ", "), record.LogFile})
+		}
+		if len(rows) == 0 {
+			message.Note(lang.CmdConnectBackgroundNone)
+			return nil
+		}
+		message.Table([]string{"Name", "PID", "Started", "URLs", "Log"}, rows)
+		return nil
+	},
+}
+
+var connectStopCmd = &cobra.Command{
+	Use:   "stop NAME...",
+	Short: lang.CmdConnectStopShort,
+	Args:  cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		records, err := background.List(backgroundDir())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := []string{}
+		for _, record := range records {
+			names = append(names, record.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		dir := backgroundDir()
+		for _, name := range args {
+			record, err := background.Load(dir, name)
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf(lang.CmdConnectErrBackgroundNotFound, name)
+			}
+			if err != nil {
+				return err
+			}
+			if err := background.Stop(record, cluster.DefaultTimeout); err != nil {
+				return err
+			}
+			if err := background.Remove(dir, name); err != nil {
+				return err
+			}
+			message.Successf(lang.CmdConnectBackgroundStopped, name)
+		}
 		return nil
 	},
 }
@@ -106,11 +495,308 @@ var connectListCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(connectCmd)
 	connectCmd.AddCommand(connectListCmd)
+	connectCmd.AddCommand(connectStatusCmd)
+	connectCmd.AddCommand(connectStopCmd)
 
 	connectCmd.Flags().StringVar(&zt.ResourceName, "name", "", lang.CmdConnectFlagName)
 	connectCmd.Flags().StringVar(&zt.Namespace, "namespace", cluster.ZarfNamespaceName, lang.CmdConnectFlagNamespace)
 	connectCmd.Flags().StringVar(&zt.ResourceType, "type", cluster.SvcResource, lang.CmdConnectFlagType)
+	connectCmd.Flags().StringVar(&zt.Selector, "selector", "", lang.CmdConnectFlagSelector)
 	connectCmd.Flags().IntVar(&zt.LocalPort, "local-port", 0, lang.CmdConnectFlagLocalPort)
 	connectCmd.Flags().IntVar(&zt.RemotePort, "remote-port", 0, lang.CmdConnectFlagRemotePort)
 	connectCmd.Flags().BoolVar(&cliOnly, "cli-only", false, lang.CmdConnectFlagCliOnly)
+	connectCmd.Flags().BoolVar(&ephemeralCredentials, "ephemeral-credentials", false, lang.CmdConnectFlagEphemeralCredentials)
+	connectCmd.Flags().StringVar(&connectProfile, "profile", "", lang.CmdConnectFlagProfile)
+	connectCmd.Flags().StringVar(&connectProtocol, "protocol", "tcp", lang.CmdConnectFlagProtocol)
+	connectCmd.Flags().BoolVar(&localTLS, "local-tls", false, lang.CmdConnectFlagLocalTLS)
+	connectCmd.Flags().StringVar(&localTLSCert, "local-tls-cert", "", lang.CmdConnectFlagLocalTLSCert)
+	connectCmd.Flags().StringVar(&localTLSKey, "local-tls-key", "", lang.CmdConnectFlagLocalTLSKey)
+	connectCmd.Flags().BoolVar(&connectBackground, "background", false, lang.CmdConnectFlagBackground)
+	connectCmd.Flags().BoolVar(&connectReconnect, "reconnect", false, lang.CmdConnectFlagReconnect)
+	connectCmd.Flags().StringVarP(&connectOutput, "output", "o", "text", lang.CmdConnectFlagOutput)
+	connectListCmd.Flags().StringVarP(&connectListOutput, "output", "o", "text", lang.CmdConnectListFlagOutput)
+	connectCmd.MarkFlagsRequiredTogether("local-tls-cert", "local-tls-key")
+}
+
+// issueEphemeralCredentials prints read-only credentials for the git target, the returned function revokes them.
+// Registry credentials are issued by issueRegistryCredentials before the tunnel connects.
+func issueEphemeralCredentials(ctx context.Context, c *cluster.Cluster, target string, tunnel *cluster.Tunnel) (func(), error) {
+	if strings.ToUpper(target) != cluster.ZarfGit {
+		return nil, errors.New(lang.CmdConnectEphemeralCredsUnsupported)
+	}
+
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenName, err := ephemeralCredentialName()
+	if err != nil {
+		return nil, err
+	}
+	giteaClient, err := gitea.NewClient(tunnel.HTTPEndpoint(), state.GitServer.PullUsername, state.GitServer.PullPassword)
+	if err != nil {
+		return nil, err
+	}
+	token, err := giteaClient.CreateAccessToken(ctx, tokenName, []string{"read:repository"})
+	if err != nil {
+		return nil, err
+	}
+	message.PrintEphemeralCredential("Git (read-only)", state.GitServer.PullUsername, token, lang.CmdConnectEphemeralCredsValid)
+
+	return func() {
+		// The command context is already cancelled by the interrupt that closes the tunnel
+		revokeCtx, cancel := context.WithTimeout(context.Background(), cluster.DefaultTimeout)
+		defer cancel()
+		if err := giteaClient.DeleteAccessToken(revokeCtx, tokenName); err != nil {
+			message.WarnErrf(err, lang.CmdConnectEphemeralCredsRevokeErr, tokenName, state.GitServer.PullUsername)
+			return
+		}
+		message.Successf(lang.CmdConnectEphemeralCredsRevoked, tokenName)
+	}, nil
+}
+
+// issueRegistryCredentials adds pull credentials for a tunnel to the internal registry and prints them, the returned
+// function removes them. The registry only reads its htpasswd file on start, so it is restarted both times.
+func issueRegistryCredentials(ctx context.Context, c *cluster.Cluster) (func(), error) {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !state.RegistryInfo.IsInternal() {
+		return nil, errors.New(lang.CmdConnectEphemeralCredsRegistryExternal)
+	}
+
+	username, err := ephemeralCredentialName()
+	if err != nil {
+		return nil, err
+	}
+	password, err := helpers.RandomString(types.ZarfGeneratedPasswordLen)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := utils.GetHtpasswdString(username, password)
+	if err != nil {
+		return nil, err
+	}
+	message.Info(lang.CmdConnectEphemeralCredsRegistryRestart)
+	err = updateTunnelPullEntries(ctx, c, func(entries map[string]string) {
+		entries[username] = entry
+	})
+	if err != nil {
+		return nil, err
+	}
+	message.PrintEphemeralCredential("Registry (read-only)", username, password, lang.CmdConnectEphemeralCredsValid)
+
+	return func() {
+		// The command context is already cancelled by the interrupt that closes the tunnel
+		revokeCtx, cancel := context.WithTimeout(context.Background(), config.ZarfDefaultTimeout)
+		defer cancel()
+		err := updateTunnelPullEntries(revokeCtx, c, func(entries map[string]string) {
+			delete(entries, username)
+		})
+		if err != nil {
+			message.WarnErrf(err, lang.CmdConnectEphemeralCredsRegistryRevokeErr, username)
+			return
+		}
+		message.Successf(lang.CmdConnectEphemeralCredsRegistryRevoked, username)
+	}, nil
+}
+
+// updateTunnelPullEntries changes the htpasswd entries of the tunnels in the Zarf state and restarts the registry with them.
+func updateTunnelPullEntries(ctx context.Context, c *cluster.Cluster, update func(map[string]string)) error {
+	// The state is loaded again so that the entries of other tunnels opened in the meantime are kept
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	if state.RegistryInfo.TunnelPullEntries == nil {
+		state.RegistryInfo.TunnelPullEntries = map[string]string{}
+	}
+	update(state.RegistryInfo.TunnelPullEntries)
+	if err := c.SaveZarfState(ctx, state); err != nil {
+		return err
+	}
+	h := helm.NewClusterOnly(&types.PackagerConfig{}, template.GetZarfVariableConfig(), state, c)
+	return h.UpdateZarfRegistryValues(ctx)
+}
+
+// ephemeralCredentialName returns a unique name for the credentials of a tunnel.
+func ephemeralCredentialName() (string, error) {
+	suffix, err := helpers.RandomString(8)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("zarf-connect-%s", strings.ToLower(suffix)), nil
+}
+
+// localTLSConfig returns the TLS config to serve the local ports of tunnels with, from the --local-tls-cert and
+// --local-tls-key keypair or a keypair kept in the Zarf cache. It returns nil when local TLS is not enabled.
+func localTLSConfig() (*tls.Config, error) {
+	if !localTLS && localTLSCert == "" {
+		return nil, nil
+	}
+	if localTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(localTLSCert, localTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the local TLS certificate: %w", err)
+		}
+		return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+	}
+	dir := filepath.Join(config.GetAbsCachePath(), "connect-tls")
+	generated, err := pki.LoadOrGeneratePKI(dir, "localhost")
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the local TLS certificate: %w", err)
+	}
+	cert, err := tls.X509KeyPair(generated.Cert, generated.Key)
+	if err != nil {
+		return nil, err
+	}
+	message.Notef(lang.CmdConnectLocalTLSCA, filepath.Join(dir, "ca.pem"))
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// backgroundDir returns the directory of the records of zarf connect processes running in the background.
+func backgroundDir() string {
+	return filepath.Join(config.GetAbsCachePath(), "connect")
+}
+
+// backgroundName returns the name of the record of a zarf connect process running in the background for the targets.
+func backgroundName(targets []string) string {
+	if connectProfile != "" {
+		return background.Name(connectProfile)
+	}
+	names := []string{}
+	for _, target := range targets {
+		name, _, err := cluster.ParseConnectTarget(target)
+		if err != nil {
+			name = target
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		names = append(names, zt.ResourceName)
+	}
+	if name := background.Name(names...); name != "" {
+		return name
+	}
+	return "connect"
+}
+
+// connectInBackground starts this command again in a detached process, and returns once that process has recorded
+// its established tunnels or exited.
+func connectInBackground(ctx context.Context, name string) error {
+	dir := backgroundDir()
+	record, err := background.Load(dir, name)
+	if err == nil && record.Running() {
+		return fmt.Errorf(lang.CmdConnectErrBackgroundRunning, name, name)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := background.Remove(dir, name); err != nil {
+		return err
+	}
+
+	env := append(os.Environ(), connectBackgroundEnv+"="+name, "ZARF_NO_LOG_FILE=true", "ZARF_NO_PROGRESS=true", "ZARF_NO_COLOR=true")
+	// The background process can't prompt to confirm a protected context, so confirm it here
+	if config.CommonOptions.ContextPolicy.IsSet() {
+		kubeContext, err := cluster.CurrentContext("", "")
+		if err != nil {
+			return err
+		}
+		if err := cluster.CheckContextPolicy(config.CommonOptions.ContextPolicy, kubeContext, config.CommonOptions.ConfirmContext); err != nil {
+			return err
+		}
+		env = append(env, "ZARF_CONFIRM_CONTEXT="+kubeContext)
+	}
+
+	logPath := background.LogPath(dir, name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	spinner := message.NewProgressSpinner(lang.CmdConnectPreparingTunnel, name)
+	defer spinner.Stop()
+	child, err := background.Start(os.Args[1:], env, logFile)
+	if err != nil {
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = child.Wait()
+		close(exited)
+	}()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = child.Process.Kill()
+			return ctx.Err()
+		case <-exited:
+			return fmt.Errorf(lang.CmdConnectErrBackgroundExited, logPath)
+		case <-ticker.C:
+			record, err := background.Load(dir, name)
+			if err != nil || record.PID != child.Process.Pid {
+				continue
+			}
+			spinner.Stop()
+			// Dump the tunnel URLs to the console for other tools to use.
+			if connectOutput == "json" {
+				fmt.Fprintln(os.Stdout, string(record.Endpoints))
+			} else {
+				fmt.Print(strings.Join(record.URLs, "\n"))
+			}
+			message.Successf(lang.CmdConnectBackgroundStarted, name, record.PID, name)
+			return nil
+		}
+	}
+}
+
+// recordBackground saves the record of this zarf connect process once its tunnels are established when it runs in
+// the background, the returned function removes the record.
+func recordBackground(urls []string, endpoints json.RawMessage) (func(), error) {
+	name := os.Getenv(connectBackgroundEnv)
+	if name == "" {
+		return func() {}, nil
+	}
+	dir := backgroundDir()
+	record := background.Record{
+		Name:      name,
+		PID:       os.Getpid(),
+		URLs:      urls,
+		Started:   time.Now(),
+		LogFile:   background.LogPath(dir, name),
+		Endpoints: endpoints,
+	}
+	if err := background.Save(dir, record); err != nil {
+		return nil, fmt.Errorf("unable to record the background process: %w", err)
+	}
+	return func() {
+		if err := background.Remove(dir, name); err != nil {
+			message.Debugf("Unable to remove the record of the background process: %s", err.Error())
+		}
+	}, nil
+}
+
+// printEndpoints prints the URL of the tunnel, or the endpoints of the tunnels as JSON with --output json. It returns
+// the printed JSON to record for background processes.
+func printEndpoints(url string, endpoints any) (json.RawMessage, error) {
+	if connectOutput != "json" {
+		fmt.Print(url)
+		return nil, nil
+	}
+	b, err := json.Marshal(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, string(b))
+	return b, nil
 }
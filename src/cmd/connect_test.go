@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cmd contains the CLI commands for Zarf.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTunnel is lost with each of its wait errors in turn and reconnects with each of its reconnect errors in turn.
+type fakeTunnel struct {
+	waitErrs      []error
+	reconnectErrs []error
+	waits         int
+	reconnects    int
+	// Called on each reconnect, e.g. to cancel the context
+	onReconnect func()
+}
+
+func (f *fakeTunnel) Wait(ctx context.Context) error {
+	f.waits++
+	if len(f.waitErrs) == 0 || ctx.Err() != nil {
+		return nil
+	}
+	err := f.waitErrs[0]
+	f.waitErrs = f.waitErrs[1:]
+	return err
+}
+
+func (f *fakeTunnel) Reconnect(_ context.Context) error {
+	f.reconnects++
+	if f.onReconnect != nil {
+		f.onReconnect()
+	}
+	if len(f.reconnectErrs) == 0 {
+		return nil
+	}
+	err := f.reconnectErrs[0]
+	f.reconnectErrs = f.reconnectErrs[1:]
+	return err
+}
+
+func (f *fakeTunnel) FullURL() string {
+	return "http://127.0.0.1:42000"
+}
+
+func TestWaitForTunnel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := &fakeTunnel{}
+		require.NoError(t, waitForTunnel(context.Background(), tunnel, true))
+		require.Equal(t, 1, tunnel.waits)
+		require.Equal(t, 0, tunnel.reconnects)
+	})
+
+	t.Run("dropped connection without reconnect", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := &fakeTunnel{waitErrs: []error{errors.New("connection reset")}}
+		require.EqualError(t, waitForTunnel(context.Background(), tunnel, false), "connection reset")
+		require.Equal(t, 0, tunnel.reconnects)
+	})
+
+	t.Run("dropped connection is reconnected", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := &fakeTunnel{waitErrs: []error{errors.New("connection reset"), errors.New("connection reset")}}
+		require.NoError(t, waitForTunnel(context.Background(), tunnel, true))
+		require.Equal(t, 3, tunnel.waits)
+		require.Equal(t, 2, tunnel.reconnects)
+	})
+
+	t.Run("reconnect gives up", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := &fakeTunnel{
+			waitErrs:      []error{errors.New("connection reset")},
+			reconnectErrs: []error{errors.New("no ready pods")},
+		}
+		require.EqualError(t, waitForTunnel(context.Background(), tunnel, true), "no ready pods")
+		require.Equal(t, 1, tunnel.waits)
+	})
+
+	t.Run("context cancelled while reconnecting", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tunnel := &fakeTunnel{
+			waitErrs:      []error{errors.New("connection reset")},
+			reconnectErrs: []error{context.Canceled},
+			onReconnect:   cancel,
+		}
+		require.NoError(t, waitForTunnel(ctx, tunnel, true))
+		require.Equal(t, 1, tunnel.waits)
+		require.Equal(t, 1, tunnel.reconnects)
+	})
+}
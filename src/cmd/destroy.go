@@ -78,10 +78,13 @@ var destroyCmd = &cobra.Command{
 			// Perform chart uninstallation
 			helm.Destroy(removeComponents)
 
-			// If Zarf didn't deploy the cluster, only delete the ZarfNamespace
+			// If Zarf didn't deploy the cluster, only delete the ZarfNamespace and the cluster scoped resources of the zarf state
 			if err := c.DeleteZarfNamespace(ctx); err != nil {
 				return err
 			}
+			if err := c.DeleteZarfStateResources(ctx); err != nil {
+				return err
+			}
 
 			// Remove zarf agent labels and secrets from namespaces Zarf doesn't manage
 			c.StripZarfLabelsAndSecretsFromNamespaces(ctx)
@@ -22,6 +22,7 @@ import (
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager"
@@ -32,6 +33,10 @@ import (
 
 var extractPath string
 
+var devSchemaVersion string
+
+var devLintOutput string
+
 var devCmd = &cobra.Command{
 	Use:     "dev",
 	Aliases: []string{"prepare", "prep"},
@@ -53,6 +58,25 @@ var devDeployCmd = &cobra.Command{
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
 
+		mirrors, err := common.GetRegistryMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.RegistryMirrors = mirrors
+		pkgConfig.CreateOpts.Proxy, err = common.GetProxyConfig(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.URLMirrors, err = common.GetURLMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.GitCredentials, err = common.GetGitCredentials(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.Retries = pkgConfig.PkgOpts.Retries
+
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
 			return err
@@ -221,6 +245,24 @@ var devFindImagesCmd = &cobra.Command{
 	Short:   lang.CmdDevFindImagesShort,
 	Long:    lang.CmdDevFindImagesLong,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if pkgConfig.FindImagesOpts.FromCluster {
+			if len(args) > 0 || pkgConfig.FindImagesOpts.Update {
+				return errors.New(lang.CmdDevFindImagesErrFromCluster)
+			}
+			pkgClient, err := packager.New(&pkgConfig)
+			if err != nil {
+				return err
+			}
+			defer pkgClient.ClearTempPaths()
+			if _, err := pkgClient.FindClusterImages(cmd.Context()); err != nil {
+				return fmt.Errorf("unable to find images: %w", err)
+			}
+			return nil
+		}
+		if len(pkgConfig.FindImagesOpts.Namespaces) > 0 {
+			return errors.New(lang.CmdDevFindImagesErrNamespace)
+		}
+
 		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
 
 		v := common.GetViper()
@@ -229,6 +271,17 @@ var devFindImagesCmd = &cobra.Command{
 			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
+
+		mirrors, err := common.GetRegistryMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.FindImagesOpts.RegistryMirrors = mirrors
+		pkgConfig.CreateOpts.GitCredentials, err = common.GetGitCredentials(v)
+		if err != nil {
+			return err
+		}
+
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
 			return err
@@ -263,18 +316,64 @@ var devGenConfigFileCmd = &cobra.Command{
 	},
 }
 
+var devLockCmd = &cobra.Command{
+	Use:   "lock [ DIRECTORY ]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: lang.CmdDevLockShort,
+	Long:  lang.CmdDevLockLong,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
+		v := common.GetViper()
+		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
+			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
+
+		mirrors, err := common.GetRegistryMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.RegistryMirrors = mirrors
+		pkgConfig.CreateOpts.GitCredentials, err = common.GetGitCredentials(v)
+		if err != nil {
+			return err
+		}
+
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+
+		if err := pkgClient.Lock(cmd.Context()); err != nil {
+			return fmt.Errorf("unable to lock images: %w", err)
+		}
+		return nil
+	},
+}
+
 var devLintCmd = &cobra.Command{
 	Use:     "lint [ DIRECTORY ]",
 	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"l"},
 	Short:   lang.CmdDevLintShort,
 	Long:    lang.CmdDevLintLong,
+	Example: lang.CmdDevLintExample,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format := lint.OutputFormat(devLintOutput)
+		switch format {
+		case lint.OutputTable, lint.OutputJSON, lint.OutputSARIF:
+		default:
+			return fmt.Errorf(lang.CmdDevLintErrOutput, devLintOutput)
+		}
 		config.CommonOptions.Confirm = true
 		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
 		v := common.GetViper()
 		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
+		var err error
+		pkgConfig.CreateOpts.GitCredentials, err = common.GetGitCredentials(v)
+		if err != nil {
+			return err
+		}
 
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
@@ -282,7 +381,47 @@ var devLintCmd = &cobra.Command{
 		}
 		defer pkgClient.ClearTempPaths()
 
-		return lint.Validate(cmd.Context(), pkgConfig.CreateOpts)
+		return lint.Validate(cmd.Context(), pkgConfig.CreateOpts, format)
+	},
+}
+
+var devValidateCmd = &cobra.Command{
+	Use:     "validate [ FILE | DIRECTORY ]",
+	Args:    cobra.MaximumNArgs(1),
+	Short:   lang.CmdDevValidateShort,
+	Long:    lang.CmdDevValidateLong,
+	Example: lang.CmdDevValidateExample,
+	RunE: func(_ *cobra.Command, args []string) error {
+		path := layout.ZarfYAML
+		if len(args) > 0 {
+			path = args[0]
+		}
+		pkg, findings, err := lint.ValidateDefinition(path)
+		if err != nil {
+			return err
+		}
+		lint.PrintFindings(findings, lint.SevWarn, path, pkg.Metadata.Name)
+		if lint.HasSevOrHigher(findings, lint.SevErr) {
+			return fmt.Errorf(lang.CmdDevValidateErr, path)
+		}
+		message.Successf(lang.CmdDevValidateSuccess, path)
+		return nil
+	},
+}
+
+var devSchemaCmd = &cobra.Command{
+	Use:     "schema",
+	Args:    cobra.NoArgs,
+	Short:   lang.CmdDevSchemaShort,
+	Long:    lang.CmdDevSchemaLong,
+	Example: lang.CmdDevSchemaExample,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		schema, err := lint.Schema(cmd.Context(), devSchemaVersion)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, strings.TrimSpace(string(schema)))
+		return err
 	},
 }
 
@@ -297,6 +436,11 @@ func init() {
 	devCmd.AddCommand(devFindImagesCmd)
 	devCmd.AddCommand(devGenConfigFileCmd)
 	devCmd.AddCommand(devLintCmd)
+	devCmd.AddCommand(devLockCmd)
+	devCmd.AddCommand(devValidateCmd)
+	devCmd.AddCommand(devSchemaCmd)
+
+	devSchemaCmd.Flags().StringVar(&devSchemaVersion, "version", "", lang.CmdDevSchemaFlagVersion)
 
 	bindDevDeployFlags(v)
 	bindDevGenerateFlags(v)
@@ -318,12 +462,20 @@ func init() {
 	devFindImagesCmd.Flags().StringVar(&pkgConfig.FindImagesOpts.Why, "why", "", lang.CmdDevFlagFindImagesWhy)
 	// skip searching cosign artifacts in find images
 	devFindImagesCmd.Flags().BoolVar(&pkgConfig.FindImagesOpts.SkipCosign, "skip-cosign", false, lang.CmdDevFlagFindImagesSkipCosign)
+	// write the discovered images back into the zarf.yaml
+	devFindImagesCmd.Flags().BoolVar(&pkgConfig.FindImagesOpts.Update, "update", false, lang.CmdDevFlagFindImagesUpdate)
+	// list the images running in the cluster instead of the images of a package
+	devFindImagesCmd.Flags().BoolVar(&pkgConfig.FindImagesOpts.FromCluster, "from-cluster", false, lang.CmdDevFlagFindImagesFromCluster)
+	devFindImagesCmd.Flags().StringSliceVar(&pkgConfig.FindImagesOpts.Namespaces, "namespace", nil, lang.CmdDevFlagFindImagesNamespace)
 
 	defaultRegistry := fmt.Sprintf("%s:%d", helpers.IPV4Localhost, types.ZarfInClusterContainerRegistryNodePort)
 	devFindImagesCmd.Flags().StringVar(&pkgConfig.FindImagesOpts.RegistryURL, "registry-url", defaultRegistry, lang.CmdDevFlagFindImagesRegistry)
 
 	devLintCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
 	devLintCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	devLintCmd.Flags().StringVarP(&devLintOutput, "output", "o", string(lint.OutputTable), lang.CmdDevLintFlagOutput)
+	devLockCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
+	devLockCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
 	devTransformGitLinksCmd.Flags().StringVar(&pkgConfig.InitOpts.GitServer.PushUsername, "git-account", types.ZarfGitPushUser, lang.CmdDevFlagGitAccount)
 }
 
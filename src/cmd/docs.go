@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cmd contains the CLI commands for Zarf.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/docs"
+	"github.com/zarf-dev/zarf/src/pkg/lint"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+var (
+	docsServe   bool
+	docsAddress string
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: lang.CmdDocsShort,
+	Long:  lang.CmdDocsLong,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if !docsServe {
+			return cmd.Help()
+		}
+
+		schema, err := lint.ZarfSchema.ReadFile("zarf.schema.json")
+		if err != nil {
+			return fmt.Errorf("unable to read the embedded zarf.yaml schema: %w", err)
+		}
+		hideRootFlagsFromVendorCommands()
+		handler, err := docs.NewHandler(rootCmd, config.CLIVersion, schema)
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", docsAddress)
+		if err != nil {
+			return fmt.Errorf("unable to listen on %s: %w", docsAddress, err)
+		}
+		srv := &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		ctx := cmd.Context()
+		go func() {
+			<-ctx.Done()
+			srv.Close()
+		}()
+
+		message.Successf(lang.CmdDocsServing, listener.Addr().String())
+		message.Note(lang.CmdDocsExit)
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+
+	docsCmd.Flags().BoolVar(&docsServe, "serve", false, lang.CmdDocsFlagServe)
+	docsCmd.Flags().StringVar(&docsAddress, "address", "127.0.0.1:8080", lang.CmdDocsFlagAddress)
+}
@@ -19,9 +19,11 @@ import (
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
@@ -29,6 +31,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	agentTLSCAPath   string
+	agentTLSCertPath string
+	agentTLSKeyPath  string
+)
+
 // initCmd represents the init command.
 var initCmd = &cobra.Command{
 	Use:     "init",
@@ -40,16 +48,31 @@ var initCmd = &cobra.Command{
 		zarfLogo := message.GetLogo()
 		_, _ = fmt.Fprintln(os.Stderr, zarfLogo)
 
+		if err := common.ValidateInitConfig(); err != nil {
+			return err
+		}
+
 		if err := validateInitFlags(); err != nil {
 			return fmt.Errorf("invalid command flags were provided: %w", err)
 		}
 
+		agentTLS, err := loadAgentTLS(agentTLSCAPath, agentTLSCertPath, agentTLSKeyPath)
+		if err != nil {
+			return err
+		}
+		pkgConfig.InitOpts.AgentTLS = agentTLS
+
+		if pkgConfig.InitOpts.RegistryInfo.CredentialProvider != "" {
+			if _, err := cluster.IssueRegistryCredentials(cmd.Context(), &pkgConfig.InitOpts.RegistryInfo); err != nil {
+				return err
+			}
+		}
+
 		// Continue running package deploy for all components like any other package
 		initPackageName := sources.GetInitPackageName()
 		pkgConfig.PkgOpts.PackageSource = initPackageName
 
 		// Try to use an init-package in the executable directory if none exist in current working directory
-		var err error
 		if pkgConfig.PkgOpts.PackageSource, err = findInitPackage(cmd.Context(), initPackageName); err != nil {
 			return err
 		}
@@ -63,6 +86,19 @@ var initCmd = &cobra.Command{
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
 
+		pkgConfig.InitOpts.RegistryMirrors, err = common.GetRegistryMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.InitOpts.Proxy, err = common.GetProxyConfig(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.InitOpts.RegistryInfo.AdditionalPullCredentials, err = common.GetAdditionalPullCredentials(v)
+		if err != nil {
+			return err
+		}
+
 		pkgClient, err := packager.New(&pkgConfig, packager.WithSource(src))
 		if err != nil {
 			return err
@@ -160,11 +196,40 @@ func validateInitFlags() error {
 		}
 	}
 
+	// Credential providers issue the credentials of external registries
+	if pkgConfig.InitOpts.RegistryInfo.CredentialProvider != "" {
+		if pkgConfig.InitOpts.RegistryInfo.Address == "" {
+			return fmt.Errorf(lang.CmdInitErrValidateRegCredProvider)
+		}
+		if _, err := cluster.GetRegistryCredentialProvider(pkgConfig.InitOpts.RegistryInfo.CredentialProvider); err != nil {
+			return err
+		}
+	}
+
 	// If 'registry-url' is provided, make sure they provided values for the username and password of the push user
 	if pkgConfig.InitOpts.RegistryInfo.Address != "" {
-		if pkgConfig.InitOpts.RegistryInfo.PushUsername == "" || pkgConfig.InitOpts.RegistryInfo.PushPassword == "" {
+		if pkgConfig.InitOpts.RegistryInfo.CredentialProvider == "" && (pkgConfig.InitOpts.RegistryInfo.PushUsername == "" || pkgConfig.InitOpts.RegistryInfo.PushPassword == "") {
 			return fmt.Errorf(lang.CmdInitErrValidateRegistry)
 		}
+		if pkgConfig.InitOpts.RegistryInfo.NamespacedPullCredentials {
+			return fmt.Errorf(lang.CmdInitErrValidateRegPullNS)
+		}
+		if pkgConfig.InitOpts.RegistryInfo.Replicas != 0 || pkgConfig.InitOpts.RegistryInfo.S3 != (types.RegistryS3Storage{}) {
+			return fmt.Errorf(lang.CmdInitErrValidateRegHA)
+		}
+	}
+
+	if pkgConfig.InitOpts.RegistryInfo.Replicas < 0 {
+		return fmt.Errorf(lang.CmdInitErrValidateRegReplicas)
+	}
+
+	// If any 'registry-s3-*' flag is provided, make sure the bucket and its region are
+	s3 := pkgConfig.InitOpts.RegistryInfo.S3
+	if s3 != (types.RegistryS3Storage{}) && (s3.Bucket == "" || s3.Region == "") {
+		return fmt.Errorf(lang.CmdInitErrValidateRegS3)
+	}
+	if (s3.AccessKey == "") != (s3.SecretKey == "") {
+		return fmt.Errorf(lang.CmdInitErrValidateRegS3Keys)
 	}
 
 	// If 'artifact-url' is provided, make sure they provided values for the username and password of the push user
@@ -173,9 +238,55 @@ func validateInitFlags() error {
 			return fmt.Errorf(lang.CmdInitErrValidateArtifact)
 		}
 	}
+	if _, err := pkgConfig.InitOpts.ArtifactServer.ParsePushTokenLifetime(); err != nil {
+		return err
+	}
+
+	// If any of the agent TLS files are provided, make sure all of them are
+	if agentTLSCAPath != "" || agentTLSCertPath != "" || agentTLSKeyPath != "" {
+		if agentTLSCAPath == "" || agentTLSCertPath == "" || agentTLSKeyPath == "" {
+			return fmt.Errorf(lang.CmdInitErrValidateAgentTLS)
+		}
+	}
+
+	if err := pkgConfig.InitOpts.SecretNamespaces.Validate(); err != nil {
+		return fmt.Errorf(lang.CmdInitErrValidateSecretNS, err)
+	}
 	return nil
 }
 
+// loadAgentTLS reads the user provided PKI for the Zarf agent and checks that it is valid. An empty PKI is returned when
+// no PKI is provided, in which case one is generated during init.
+func loadAgentTLS(caPath, certPath, keyPath string) (types.GeneratedPKI, error) {
+	agentTLS := types.GeneratedPKI{}
+	if caPath == "" && certPath == "" && keyPath == "" {
+		return agentTLS, nil
+	}
+	files := []struct {
+		flag string
+		path string
+		dst  *[]byte
+	}{
+		{flag: "--agent-tls-ca", path: caPath, dst: &agentTLS.CA},
+		{flag: "--agent-tls-cert", path: certPath, dst: &agentTLS.Cert},
+		{flag: "--agent-tls-key", path: keyPath, dst: &agentTLS.Key},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			return types.GeneratedPKI{}, errors.New(lang.CmdInitErrValidateAgentTLS)
+		}
+		b, err := os.ReadFile(file.path)
+		if err != nil {
+			return types.GeneratedPKI{}, fmt.Errorf("unable to read the agent TLS file %s of %s: %w", file.path, file.flag, err)
+		}
+		*file.dst = b
+	}
+	if err := pki.ValidatePKI(agentTLS, config.ZarfAgentHost); err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("invalid agent TLS files: %w", err)
+	}
+	return agentTLS, nil
+}
+
 func init() {
 	v := common.InitViper()
 
@@ -191,8 +302,9 @@ func init() {
 
 	// Continue to require --confirm flag for init command to avoid accidental deployments
 	initCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdInitFlagConfirm)
-	initCmd.Flags().StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VInitComponents), lang.CmdInitFlagComponents)
+	initCmd.Flags().StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", common.GetStringOrSlice(v, common.VInitComponents), lang.CmdInitFlagComponents)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.StorageClass, "storage-class", v.GetString(common.VInitStorageClass), lang.CmdInitFlagStorageClass)
+	initCmd.Flags().BoolVar(&pkgConfig.InitOpts.SkipHostPreflight, "skip-preflight", v.GetBool(common.VInitSkipPreflight), lang.CmdInitFlagSkipPreflight)
 
 	// Flags for using an external Git server
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.GitServer.Address, "git-url", v.GetString(common.VInitGitURL), lang.CmdInitFlagGitURL)
@@ -209,17 +321,41 @@ func init() {
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.PullUsername, "registry-pull-username", v.GetString(common.VInitRegistryPullUser), lang.CmdInitFlagRegPullUser)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.PullPassword, "registry-pull-password", v.GetString(common.VInitRegistryPullPass), lang.CmdInitFlagRegPullPass)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.Secret, "registry-secret", v.GetString(common.VInitRegistrySecret), lang.CmdInitFlagRegSecret)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.CredentialProvider, "registry-credential-provider", v.GetString(common.VInitRegistryCredProvider), lang.CmdInitFlagRegCredProvider)
+	initCmd.Flags().BoolVar(&pkgConfig.InitOpts.RegistryInfo.NamespacedPullCredentials, "registry-pull-per-namespace", v.GetBool(common.VInitRegistryPullPerNamespace), lang.CmdInitFlagRegPullPerNamespace)
+
+	// Flags for running the internal registry with multiple replicas
+	initCmd.Flags().IntVar(&pkgConfig.InitOpts.RegistryInfo.Replicas, "registry-replicas", v.GetInt(common.VInitRegistryReplicas), lang.CmdInitFlagRegReplicas)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.Bucket, "registry-s3-bucket", v.GetString(common.VInitRegistryS3Bucket), lang.CmdInitFlagRegS3Bucket)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.Region, "registry-s3-region", v.GetString(common.VInitRegistryS3Region), lang.CmdInitFlagRegS3Region)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.Endpoint, "registry-s3-endpoint", v.GetString(common.VInitRegistryS3Endpoint), lang.CmdInitFlagRegS3Endpoint)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.RootDirectory, "registry-s3-root-directory", v.GetString(common.VInitRegistryS3RootDirectory), lang.CmdInitFlagRegS3RootDirectory)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.AccessKey, "registry-s3-access-key", v.GetString(common.VInitRegistryS3AccessKey), lang.CmdInitFlagRegS3AccessKey)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.S3.SecretKey, "registry-s3-secret-key", v.GetString(common.VInitRegistryS3SecretKey), lang.CmdInitFlagRegS3SecretKey)
 
 	// Flags for using an external artifact server
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.Address, "artifact-url", v.GetString(common.VInitArtifactURL), lang.CmdInitFlagArtifactURL)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.PushUsername, "artifact-push-username", v.GetString(common.VInitArtifactPushUser), lang.CmdInitFlagArtifactPushUser)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.PushToken, "artifact-push-token", v.GetString(common.VInitArtifactPushToken), lang.CmdInitFlagArtifactPushToken)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.PushTokenLifetime, "artifact-push-token-lifetime", v.GetString(common.VInitArtifactPushTokenLifetime), lang.CmdInitFlagArtifactPushTokenLifetime)
+
+	// Flags for providing the Zarf agent PKI
+	initCmd.Flags().StringVar(&agentTLSCAPath, "agent-tls-ca", v.GetString(common.VInitAgentTLSCA), lang.CmdInitFlagAgentTLSCA)
+	initCmd.Flags().StringVar(&agentTLSCertPath, "agent-tls-cert", v.GetString(common.VInitAgentTLSCert), lang.CmdInitFlagAgentTLSCert)
+	initCmd.Flags().StringVar(&agentTLSKeyPath, "agent-tls-key", v.GetString(common.VInitAgentTLSKey), lang.CmdInitFlagAgentTLSKey)
+
+	// Flags for restricting the namespaces Zarf-managed secrets are written to
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.SecretNamespaces.Allow, "secret-namespaces-allow", v.GetStringSlice(common.VInitSecretNamespacesAllow), lang.CmdInitFlagSecretNamespacesAllow)
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.SecretNamespaces.Deny, "secret-namespaces-deny", v.GetStringSlice(common.VInitSecretNamespacesDeny), lang.CmdInitFlagSecretNamespacesDeny)
 
 	// Flags that control how a deployment proceeds
 	// Always require adopt-existing-resources flag (no viper)
 	initCmd.Flags().BoolVar(&pkgConfig.DeployOpts.AdoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
 	initCmd.Flags().BoolVar(&pkgConfig.DeployOpts.SkipWebhooks, "skip-webhooks", v.GetBool(common.VPkgDeploySkipWebhooks), lang.CmdPackageDeployFlagSkipWebhooks)
 	initCmd.Flags().DurationVar(&pkgConfig.DeployOpts.Timeout, "timeout", v.GetDuration(common.VPkgDeployTimeout), lang.CmdPackageDeployFlagTimeout)
+	initCmd.Flags().StringVar(&pkgConfig.DeployOpts.FaultInject, "fault-inject", "", lang.CmdPackageDeployFlagFaultInject)
+	initCmd.Flags().StringVar(&pkgConfig.DeployOpts.TrustRootPath, "trust-root", v.GetString(common.VPkgDeployTrustRoot), lang.CmdPackageDeployFlagTrustRoot)
+	initCmd.Flags().MarkHidden("fault-inject")
 
 	initCmd.Flags().IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
 	initCmd.Flags().StringVarP(&pkgConfig.PkgOpts.PublicKeyPath, "key", "k", v.GetString(common.VPkgPublicKey), lang.CmdPackageFlagFlagPublicKey)
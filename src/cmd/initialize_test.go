@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cmd contains the CLI commands for Zarf.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestLoadAgentTLS(t *testing.T) {
+	t.Parallel()
+
+	agentTLS, err := pki.GeneratePKI("agent-hook.zarf.svc")
+	require.NoError(t, err)
+	otherTLS, err := pki.GeneratePKI("agent-hook.zarf.svc")
+	require.NoError(t, err)
+	wrongHostTLS, err := pki.GeneratePKI("registry.example.com")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeFile := func(name string, b []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, b, 0o600))
+		return path
+	}
+	caPath := writeFile("ca.pem", agentTLS.CA)
+	certPath := writeFile("cert.pem", agentTLS.Cert)
+	keyPath := writeFile("key.pem", agentTLS.Key)
+	otherCAPath := writeFile("other-ca.pem", otherTLS.CA)
+	otherKeyPath := writeFile("other-key.pem", otherTLS.Key)
+	notPEMPath := writeFile("not-pem.pem", []byte("not a certificate"))
+	wrongHostCAPath := writeFile("wrong-host-ca.pem", wrongHostTLS.CA)
+	wrongHostCertPath := writeFile("wrong-host-cert.pem", wrongHostTLS.Cert)
+	wrongHostKeyPath := writeFile("wrong-host-key.pem", wrongHostTLS.Key)
+
+	tests := []struct {
+		name        string
+		caPath      string
+		certPath    string
+		keyPath     string
+		expected    types.GeneratedPKI
+		expectedErr string
+	}{
+		{
+			name: "no files",
+		},
+		{
+			name:     "valid files",
+			caPath:   caPath,
+			certPath: certPath,
+			keyPath:  keyPath,
+			expected: agentTLS,
+		},
+		{
+			name:        "missing key",
+			caPath:      caPath,
+			certPath:    certPath,
+			expectedErr: lang.CmdInitErrValidateAgentTLS,
+		},
+		{
+			name:        "unreadable file",
+			caPath:      caPath,
+			certPath:    filepath.Join(dir, "missing.pem"),
+			keyPath:     keyPath,
+			expectedErr: "unable to read the agent TLS file " + filepath.Join(dir, "missing.pem") + " of --agent-tls-cert",
+		},
+		{
+			name:        "key of another keypair",
+			caPath:      caPath,
+			certPath:    certPath,
+			keyPath:     otherKeyPath,
+			expectedErr: "the certificate and key are not a valid keypair",
+		},
+		{
+			name:        "CA that is not PEM",
+			caPath:      notPEMPath,
+			certPath:    certPath,
+			keyPath:     keyPath,
+			expectedErr: "the CA is not a PEM encoded certificate",
+		},
+		{
+			name:        "CA that did not sign the certificate",
+			caPath:      otherCAPath,
+			certPath:    certPath,
+			keyPath:     keyPath,
+			expectedErr: "the certificate can not be verified with the CA",
+		},
+		{
+			name:        "certificate for another host",
+			caPath:      wrongHostCAPath,
+			certPath:    wrongHostCertPath,
+			keyPath:     wrongHostKeyPath,
+			expectedErr: "the certificate is not valid for agent-hook.zarf.svc",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			loaded, err := loadAgentTLS(tt.caPath, tt.certPath, tt.keyPath)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, loaded)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/invopop/jsonschema"
@@ -29,7 +30,10 @@ import (
 )
 
 var (
-	rollback bool
+	rollback                  bool
+	rotateArtifactTokenBefore time.Duration
+	udpRelayListen            string
+	udpRelayTarget            string
 )
 
 var internalCmd = &cobra.Command{
@@ -79,21 +83,11 @@ var genCLIDocs = &cobra.Command{
 			})
 		}
 
+		hideRootFlagsFromVendorCommands()
+
 		for _, cmd := range rootCmd.Commands() {
 			if cmd.Use == "tools" {
 				for _, toolCmd := range cmd.Commands() {
-					// If the command is a vendored command, add a dummy flag to hide root flags from the docs
-					if common.CheckVendorOnlyFromPath(toolCmd) {
-						addHiddenDummyFlag(toolCmd, "log-level")
-						addHiddenDummyFlag(toolCmd, "architecture")
-						addHiddenDummyFlag(toolCmd, "no-log-file")
-						addHiddenDummyFlag(toolCmd, "no-progress")
-						addHiddenDummyFlag(toolCmd, "zarf-cache")
-						addHiddenDummyFlag(toolCmd, "tmpdir")
-						addHiddenDummyFlag(toolCmd, "insecure")
-						addHiddenDummyFlag(toolCmd, "no-color")
-					}
-
 					// Remove the default values from all of the helm commands during the CLI command doc generation
 					if toolCmd.Use == "helm" || toolCmd.Use == "sbom" {
 						toolCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
@@ -288,29 +282,7 @@ var createPackageRegistryToken = &cobra.Command{
 
 		// If we are setup to use an internal artifact server, create the artifact registry token
 		if state.ArtifactServer.IsInternal() {
-			tunnel, err := c.NewTunnel(cluster.ZarfNamespaceName, cluster.SvcResource, cluster.ZarfGitServerName, "", 0, cluster.ZarfGitServerPort)
-			if err != nil {
-				return err
-			}
-			_, err = tunnel.Connect(cmd.Context())
-			if err != nil {
-				return err
-			}
-			defer tunnel.Close()
-			tunnelURL := tunnel.HTTPEndpoint()
-			giteaClient, err := gitea.NewClient(tunnelURL, state.GitServer.PushUsername, state.GitServer.PushPassword)
-			if err != nil {
-				return err
-			}
-			err = tunnel.Wrap(func() error {
-				tokenSha1, err := giteaClient.CreatePackageRegistryToken(ctx)
-				if err != nil {
-					return fmt.Errorf("unable to create an artifact registry token for Gitea: %w", err)
-				}
-				state.ArtifactServer.PushToken = tokenSha1
-				return nil
-			})
-			if err != nil {
+			if err := c.CreateArtifactToken(ctx, state, state.GitServer); err != nil {
 				return err
 			}
 			if err := c.SaveZarfState(ctx, state); err != nil {
@@ -321,6 +293,28 @@ var createPackageRegistryToken = &cobra.Command{
 	},
 }
 
+var rotateArtifactToken = &cobra.Command{
+	Use:   "rotate-artifact-token",
+	Short: lang.CmdInternalRotateArtifactTokenShort,
+	Long:  lang.CmdInternalRotateArtifactTokenLong,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := cluster.NewCluster()
+		if err != nil {
+			return err
+		}
+		return c.RotateArtifactToken(cmd.Context(), rotateArtifactTokenBefore)
+	},
+}
+
+var udpRelay = &cobra.Command{
+	Use:   "udp-relay",
+	Short: lang.CmdInternalUDPRelayShort,
+	Long:  lang.CmdInternalUDPRelayLong,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return cluster.RelayUDP(cmd.Context(), udpRelayListen, udpRelayTarget)
+	},
+}
+
 var updateGiteaPVC = &cobra.Command{
 	Use:   "update-gitea-pvc",
 	Short: lang.CmdInternalUpdateGiteaPVCShort,
@@ -343,6 +337,19 @@ var updateGiteaPVC = &cobra.Command{
 	},
 }
 
+var refreshRegistryCredentials = &cobra.Command{
+	Use:   "refresh-registry-credentials",
+	Short: lang.CmdInternalRefreshRegistryCredentialsShort,
+	Long:  lang.CmdInternalRefreshRegistryCredentialsLong,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := cluster.NewCluster()
+		if err != nil {
+			return err
+		}
+		return c.RefreshRegistryCredentials(cmd.Context())
+	},
+}
+
 var isValidHostname = &cobra.Command{
 	Use:   "is-valid-hostname",
 	Short: lang.CmdInternalIsValidHostnameShort,
@@ -378,10 +385,38 @@ func init() {
 	internalCmd.AddCommand(createReadOnlyGiteaUser)
 	internalCmd.AddCommand(createPackageRegistryToken)
 	internalCmd.AddCommand(updateGiteaPVC)
+	internalCmd.AddCommand(refreshRegistryCredentials)
+	internalCmd.AddCommand(rotateArtifactToken)
+	internalCmd.AddCommand(udpRelay)
 	internalCmd.AddCommand(isValidHostname)
 	internalCmd.AddCommand(computeCrc32)
 
 	updateGiteaPVC.Flags().BoolVarP(&rollback, "rollback", "r", false, lang.CmdInternalFlagUpdateGiteaPVCRollback)
+	rotateArtifactToken.Flags().DurationVar(&rotateArtifactTokenBefore, "before", 72*time.Hour, lang.CmdInternalFlagRotateArtifactTokenBefore)
+	udpRelay.Flags().StringVar(&udpRelayListen, "listen", fmt.Sprintf(":%d", cluster.ZarfUDPRelayPort), lang.CmdInternalFlagUDPRelayListen)
+	udpRelay.Flags().StringVar(&udpRelayTarget, "target", "", lang.CmdInternalFlagUDPRelayTarget)
+	_ = udpRelay.MarkFlagRequired("target")
+}
+
+// hideRootFlagsFromVendorCommands adds dummy flags to vendored tool commands so the root flags are hidden from their docs.
+func hideRootFlagsFromVendorCommands() {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Use != "tools" {
+			continue
+		}
+		for _, toolCmd := range cmd.Commands() {
+			if common.CheckVendorOnlyFromPath(toolCmd) {
+				addHiddenDummyFlag(toolCmd, "log-level")
+				addHiddenDummyFlag(toolCmd, "architecture")
+				addHiddenDummyFlag(toolCmd, "no-log-file")
+				addHiddenDummyFlag(toolCmd, "no-progress")
+				addHiddenDummyFlag(toolCmd, "zarf-cache")
+				addHiddenDummyFlag(toolCmd, "tmpdir")
+				addHiddenDummyFlag(toolCmd, "insecure")
+				addHiddenDummyFlag(toolCmd, "no-color")
+			}
+		}
+	}
 }
 
 func addHiddenDummyFlag(cmd *cobra.Command, flagDummy string) {
@@ -11,22 +11,36 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/builder"
+	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
+	"github.com/zarf-dev/zarf/src/internal/retention"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 
 	"oras.land/oras-go/v2/registry"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/defenseunicorns/pkg/oci"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/packager"
+	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+)
+
+var (
+	remoteBuilder      string
+	remoteBuilderToken string
+	createSetFiles     map[string]string
 )
 
 var packageCmd = &cobra.Command{
@@ -51,8 +65,59 @@ var packageCreateCmd = &cobra.Command{
 		}
 
 		v := common.GetViper()
-		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
-			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
+		// The --set flag defaults to the values of the config file, which the mapped environment variables take precedence over
+		setFlags := map[string]string{}
+		if cmd.Flags().Changed("set") {
+			setFlags = pkgConfig.CreateOpts.SetVariables
+		}
+		var err error
+		pkgConfig.CreateOpts.SetVariables, err = creator.MergeSetVariables(
+			v.GetStringMapString(common.VPkgCreateSet), v.GetStringMapString(common.VPkgCreateEnv), setFlags, createSetFiles)
+		if err != nil {
+			return err
+		}
+
+		mirrors, err := common.GetRegistryMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.RegistryMirrors = mirrors
+		pkgConfig.CreateOpts.Proxy, err = common.GetProxyConfig(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.URLMirrors, err = common.GetURLMirrors(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.GitCredentials, err = common.GetGitCredentials(v)
+		if err != nil {
+			return err
+		}
+		pkgConfig.CreateOpts.Retries = pkgConfig.PkgOpts.Retries
+
+		if pkgConfig.CreateOpts.FailOnSeverity != "" {
+			if err := sbom.ValidateSeverity(pkgConfig.CreateOpts.FailOnSeverity); err != nil {
+				return err
+			}
+			pkgConfig.CreateOpts.ScanVulnerabilities = true
+		}
+		if pkgConfig.CreateOpts.ScanVulnerabilities && pkgConfig.CreateOpts.SkipSBOM {
+			return errors.New(lang.CmdPackageCreateErrScanSkipSBOM)
+		}
+		if (len(pkgConfig.CreateOpts.EncryptRecipients) > 0 || pkgConfig.CreateOpts.EncryptPassphrase != "") && helpers.IsOCIURL(pkgConfig.CreateOpts.Output) {
+			return errors.New(lang.CmdPackageCreateErrEncryptOCI)
+		}
+
+		if remoteBuilder != "" {
+			if remoteBuilderToken == "" {
+				return errors.New(lang.CmdPackageCreateErrRemoteBuilderToken)
+			}
+			if _, err := builder.Create(cmd.Context(), remoteBuilder, remoteBuilderToken, pkgConfig.CreateOpts, config.CLIArch); err != nil {
+				return fmt.Errorf("failed to create package: %w", err)
+			}
+			return nil
+		}
 
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
@@ -72,19 +137,36 @@ var packageDeployCmd = &cobra.Command{
 	Aliases: []string{"d"},
 	Short:   lang.CmdPackageDeployShort,
 	Long:    lang.CmdPackageDeployLong,
+	Example: lang.CmdPackageDeployExample,
 	Args:    cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		packageSource, err := choosePackage(args)
 		if err != nil {
 			return err
 		}
+		packageSource, err = resolvePackageSource(cmd.Context(), packageSource)
+		if err != nil || packageResolveOnly {
+			return err
+		}
+		if packageSource == sources.StdinPackageSource && !config.CommonOptions.Confirm {
+			return errors.New(lang.CmdPackageDeployErrStdinConfirm)
+		}
 		pkgConfig.PkgOpts.PackageSource = packageSource
 
 		v := common.GetViper()
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
 
-		pkgClient, err := packager.New(&pkgConfig)
+		opts := []packager.Modifier{}
+		if pkgConfig.DeployOpts.FromClusterCache {
+			src, err := sources.NewClusterCacheSource(&pkgConfig.PkgOpts)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, packager.WithSource(src))
+		}
+
+		pkgClient, err := packager.New(&pkgConfig, opts...)
 		if err != nil {
 			return err
 		}
@@ -135,6 +217,10 @@ var packageInspectCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		packageSource, err = resolvePackageSource(cmd.Context(), packageSource)
+		if err != nil || packageResolveOnly {
+			return err
+		}
 		pkgConfig.PkgOpts.PackageSource = packageSource
 		src, err := identifyAndFallbackToClusterSource()
 		if err != nil {
@@ -153,11 +239,102 @@ var packageInspectCmd = &cobra.Command{
 	ValidArgsFunction: getPackageCompletionArgs,
 }
 
+var packageInspectLayersCmd = &cobra.Command{
+	Use:   "layers [ PACKAGE_SOURCE ]",
+	Short: lang.CmdPackageInspectLayersShort,
+	Long:  lang.CmdPackageInspectLayersLong,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		packageSource, err := choosePackage(args)
+		if err != nil {
+			return err
+		}
+		pkgConfig.PkgOpts.PackageSource = packageSource
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+		if err := pkgClient.InspectLayers(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to inspect package layers: %w", err)
+		}
+		return nil
+	},
+	ValidArgsFunction: getPackageCompletionArgs,
+}
+
+var packageVerifyCmd = &cobra.Command{
+	Use:     "verify [ PACKAGE_SOURCE ]",
+	Short:   lang.CmdPackageVerifyShort,
+	Long:    lang.CmdPackageVerifyLong,
+	Example: lang.CmdPackageVerifyExample,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		packageSource, err := choosePackage(args)
+		if err != nil {
+			return err
+		}
+		pkgConfig.PkgOpts.PackageSource = packageSource
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+		if err := pkgClient.Verify(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to verify package: %w", err)
+		}
+		return nil
+	},
+	ValidArgsFunction: getPackageCompletionArgs,
+}
+
+var packageSBOMCmd = &cobra.Command{
+	Use:   "sbom",
+	Short: lang.CmdPackageSBOMShort,
+}
+
+var packageSBOMGenerateCmd = &cobra.Command{
+	Use:     "generate [ PACKAGE_SOURCE ]",
+	Short:   lang.CmdPackageSBOMGenerateShort,
+	Long:    lang.CmdPackageSBOMGenerateLong,
+	Example: lang.CmdPackageSBOMGenerateExample,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		packageSource, err := choosePackage(args)
+		if err != nil {
+			return err
+		}
+		pkgConfig.PkgOpts.PackageSource = packageSource
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+		if err := pkgClient.GenerateSBOM(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to generate package SBOMs: %w", err)
+		}
+		return nil
+	},
+	ValidArgsFunction: getPackageCompletionArgs,
+}
+
+// packageResolveOnly prints the pinned reference of an OCI package source instead of deploying, pulling or inspecting it
+var packageResolveOnly bool
+
+var (
+	packageListLocal     bool
+	packageListRetainDir string
+)
+
 var packageListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l", "ls"},
 	Short:   lang.CmdPackageListShort,
 	RunE: func(cmd *cobra.Command, _ []string) error {
+		if packageListLocal {
+			return listRetainedPackages()
+		}
+
 		timeoutCtx, cancel := context.WithTimeout(cmd.Context(), cluster.DefaultTimeout)
 		defer cancel()
 		c, err := cluster.NewClusterWithWait(timeoutCtx)
@@ -197,6 +374,27 @@ var packageListCmd = &cobra.Command{
 	},
 }
 
+// listRetainedPackages prints the archives of deployed packages retained on this host.
+func listRetainedPackages() error {
+	dir := packager.RetainDir(packageListRetainDir)
+	index, err := retention.Load(dir)
+	if err != nil {
+		return err
+	}
+	if len(index.Packages) == 0 {
+		message.Notef(lang.CmdPackageListNoneRetained, dir)
+		return nil
+	}
+	packageData := [][]string{}
+	for _, entry := range index.Packages {
+		packageData = append(packageData, []string{
+			entry.Name, entry.Version, entry.Deployed.Local().Format(time.DateTime), utils.ByteFormat(float64(entry.Size), 2), entry.SHA256, filepath.Join(dir, entry.Path),
+		})
+	}
+	message.Table([]string{"Package", "Version", "Deployed", "Size", "SHA256", "Archive"}, packageData)
+	return nil
+}
+
 var packageRemoveCmd = &cobra.Command{
 	Use:     "remove { PACKAGE_SOURCE | PACKAGE_NAME } --confirm",
 	Aliases: []string{"u", "rm"},
@@ -226,24 +424,29 @@ var packageRemoveCmd = &cobra.Command{
 }
 
 var packagePublishCmd = &cobra.Command{
-	Use:     "publish { PACKAGE_SOURCE | SKELETON DIRECTORY } REPOSITORY",
+	Use:     "publish { PACKAGE_SOURCE | SKELETON DIRECTORY } REPOSITORY...",
 	Short:   lang.CmdPackagePublishShort,
+	Long:    lang.CmdPackagePublishLong,
 	Example: lang.CmdPackagePublishExample,
-	Args:    cobra.ExactArgs(2),
+	Args:    cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pkgConfig.PkgOpts.PackageSource = args[0]
 
-		if !helpers.IsOCIURL(args[1]) {
-			return errors.New("Registry must be prefixed with 'oci://'")
-		}
-		parts := strings.Split(strings.TrimPrefix(args[1], helpers.OCIURLPrefix), "/")
-		ref := registry.Reference{
-			Registry:   parts[0],
-			Repository: strings.Join(parts[1:], "/"),
-		}
-		err := ref.ValidateRegistry()
-		if err != nil {
-			return err
+		destinations := []string{}
+		for _, arg := range args[1:] {
+			if !helpers.IsOCIURL(arg) {
+				return errors.New("Registry must be prefixed with 'oci://'")
+			}
+			parts := strings.Split(strings.TrimPrefix(arg, helpers.OCIURLPrefix), "/")
+			ref := registry.Reference{
+				Registry:   parts[0],
+				Repository: strings.Join(parts[1:], "/"),
+			}
+			err := ref.ValidateRegistry()
+			if err != nil {
+				return err
+			}
+			destinations = append(destinations, ref.String())
 		}
 
 		if helpers.IsDir(pkgConfig.PkgOpts.PackageSource) {
@@ -251,7 +454,13 @@ var packagePublishCmd = &cobra.Command{
 			pkgConfig.CreateOpts.IsSkeleton = true
 		}
 
-		pkgConfig.PublishOpts.PackageDestination = ref.String()
+		pkgConfig.PublishOpts.PackageDestination = destinations[0]
+		pkgConfig.PublishOpts.MirrorDestinations = destinations[1:]
+		credentials, err := common.GetPublishCredentials(common.GetViper())
+		if err != nil {
+			return err
+		}
+		pkgConfig.PublishOpts.Credentials = credentials
 
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
@@ -266,13 +475,54 @@ var packagePublishCmd = &cobra.Command{
 	},
 }
 
+var packageCopyCmd = &cobra.Command{
+	Use:     "copy PACKAGE_SOURCE REPOSITORY",
+	Short:   lang.CmdPackageCopyShort,
+	Long:    lang.CmdPackageCopyLong,
+	Example: lang.CmdPackageCopyExample,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if !helpers.IsOCIURL(arg) {
+				return fmt.Errorf(lang.CmdPackageCopyErrOCI, arg)
+			}
+		}
+		packageSource, err := resolvePackageSource(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		pkgConfig.PkgOpts.PackageSource = packageSource
+		pkgConfig.PublishOpts.PackageDestination = strings.TrimPrefix(args[1], helpers.OCIURLPrefix)
+		credentials, err := common.GetPublishCredentials(common.GetViper())
+		if err != nil {
+			return err
+		}
+		pkgConfig.PublishOpts.Credentials = credentials
+
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+
+		if err := pkgClient.Copy(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to copy package: %w", err)
+		}
+		return nil
+	},
+}
+
 var packagePullCmd = &cobra.Command{
 	Use:     "pull PACKAGE_SOURCE",
 	Short:   lang.CmdPackagePullShort,
 	Example: lang.CmdPackagePullExample,
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		pkgConfig.PkgOpts.PackageSource = args[0]
+		packageSource, err := resolvePackageSource(cmd.Context(), args[0])
+		if err != nil || packageResolveOnly {
+			return err
+		}
+		pkgConfig.PkgOpts.PackageSource = packageSource
 		pkgClient, err := packager.New(&pkgConfig)
 		if err != nil {
 			return err
@@ -285,6 +535,37 @@ var packagePullCmd = &cobra.Command{
 	},
 }
 
+// resolvePackageSource replaces a semver range in place of the tag of an OCI package source with the highest version
+// that satisfies it. With --resolve-only, the source pinned to the digest of that version is printed instead.
+func resolvePackageSource(ctx context.Context, packageSource string) (string, error) {
+	if sources.Identify(packageSource) != "oci" {
+		if packageResolveOnly {
+			return "", errors.New(lang.CmdPackageErrResolveOnly)
+		}
+		return packageSource, nil
+	}
+	resolved, err := zoci.ResolveVersionRange(ctx, packageSource)
+	if err != nil {
+		return "", err
+	}
+	if resolved != packageSource {
+		message.Infof(lang.CmdPackageResolvedVersion, packageSource, resolved)
+	}
+	if !packageResolveOnly {
+		return resolved, nil
+	}
+	remote, err := zoci.NewRemote(resolved, oci.PlatformForArch(config.GetArch()))
+	if err != nil {
+		return "", err
+	}
+	pinned, err := remote.PinnedReference(ctx)
+	if err != nil {
+		return "", err
+	}
+	fmt.Println(pinned)
+	return pinned, nil
+}
+
 func choosePackage(args []string) (string, error) {
 	if len(args) > 0 {
 		return args[0], nil
@@ -295,9 +576,11 @@ func choosePackage(args []string) (string, error) {
 		Suggest: func(toComplete string) []string {
 			files, _ := filepath.Glob(config.ZarfPackagePrefix + toComplete + "*.tar")
 			zstFiles, _ := filepath.Glob(config.ZarfPackagePrefix + toComplete + "*.tar.zst")
+			encryptedFiles, _ := filepath.Glob(config.ZarfPackagePrefix + toComplete + "*.tar*.age")
 			splitFiles, _ := filepath.Glob(config.ZarfPackagePrefix + toComplete + "*.part000")
 
 			files = append(files, zstFiles...)
+			files = append(files, encryptedFiles...)
 			files = append(files, splitFiles...)
 			return files
 		},
@@ -351,25 +634,36 @@ func init() {
 	packageCmd.AddCommand(packageDeployCmd)
 	packageCmd.AddCommand(packageMirrorCmd)
 	packageCmd.AddCommand(packageInspectCmd)
+	packageInspectCmd.AddCommand(packageInspectLayersCmd)
+	packageCmd.AddCommand(packageVerifyCmd)
 	packageCmd.AddCommand(packageRemoveCmd)
 	packageCmd.AddCommand(packageListCmd)
 	packageCmd.AddCommand(packagePublishCmd)
+	packageCmd.AddCommand(packageCopyCmd)
 	packageCmd.AddCommand(packagePullCmd)
+	packageCmd.AddCommand(packageSBOMCmd)
+	packageSBOMCmd.AddCommand(packageSBOMGenerateCmd)
 
 	bindPackageFlags(v)
 	bindCreateFlags(v)
 	bindDeployFlags(v)
 	bindMirrorFlags(v)
 	bindInspectFlags(v)
+	bindVerifyFlags(v)
 	bindRemoveFlags(v)
 	bindPublishFlags(v)
 	bindPullFlags(v)
+	bindListFlags(v)
+	bindSBOMGenerateFlags(v)
 }
 
 func bindPackageFlags(v *viper.Viper) {
 	packageFlags := packageCmd.PersistentFlags()
 	packageFlags.IntVar(&config.CommonOptions.OCIConcurrency, "oci-concurrency", v.GetInt(common.VPkgOCIConcurrency), lang.CmdPackageFlagConcurrency)
+	packageFlags.IntVar(&config.CommonOptions.ImageConcurrency, "image-concurrency", v.GetInt(common.VPkgImageConcurrency), lang.CmdPackageFlagImageConcurrency)
 	packageFlags.StringVarP(&pkgConfig.PkgOpts.PublicKeyPath, "key", "k", v.GetString(common.VPkgPublicKey), lang.CmdPackageFlagFlagPublicKey)
+	packageFlags.StringSliceVar(&pkgConfig.PkgOpts.DecryptIdentityPaths, "decrypt-identity", v.GetStringSlice(common.VPkgDecryptIdentity), lang.CmdPackageFlagDecryptIdentity)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.DecryptPassphrase, "decrypt-passphrase", v.GetString(common.VPkgDecryptPassphrase), lang.CmdPackageFlagDecryptPassphrase)
 }
 
 func bindCreateFlags(v *viper.Viper) {
@@ -387,13 +681,33 @@ func bindCreateFlags(v *viper.Viper) {
 	createFlags.StringVarP(&pkgConfig.CreateOpts.Output, "output", "o", v.GetString(common.VPkgCreateOutput), lang.CmdPackageCreateFlagOutput)
 
 	createFlags.StringVar(&pkgConfig.CreateOpts.DifferentialPackagePath, "differential", v.GetString(common.VPkgCreateDifferential), lang.CmdPackageCreateFlagDifferential)
+	createFlags.StringVar(&pkgConfig.CreateOpts.DifferentialFrom, "differential-from", v.GetString(common.VPkgCreateDifferentialFrom), lang.CmdPackageCreateFlagDifferentialFrom)
 	createFlags.StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
+	createFlags.StringToStringVar(&createSetFiles, "set-file", map[string]string{}, lang.CmdPackageCreateFlagSetFile)
 	createFlags.BoolVarP(&pkgConfig.CreateOpts.ViewSBOM, "sbom", "s", v.GetBool(common.VPkgCreateSbom), lang.CmdPackageCreateFlagSbom)
 	createFlags.StringVar(&pkgConfig.CreateOpts.SBOMOutputDir, "sbom-out", v.GetString(common.VPkgCreateSbomOutput), lang.CmdPackageCreateFlagSbomOut)
 	createFlags.BoolVar(&pkgConfig.CreateOpts.SkipSBOM, "skip-sbom", v.GetBool(common.VPkgCreateSkipSbom), lang.CmdPackageCreateFlagSkipSbom)
+	createFlags.StringSliceVar(&pkgConfig.CreateOpts.SkipSBOMComponents, "skip-sbom-components", v.GetStringSlice(common.VPkgCreateSkipSbomComponents), lang.CmdPackageCreateFlagSkipSbomComponents)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.CompressSBOM, "compress-sbom", v.GetBool(common.VPkgCreateCompressSbom), lang.CmdPackageCreateFlagCompressSbom)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.ScanVulnerabilities, "scan-vulnerabilities", v.GetBool(common.VPkgCreateScanVulns), lang.CmdPackageCreateFlagScanVulnerabilities)
+	createFlags.StringVar(&pkgConfig.CreateOpts.VulnerabilityDBPath, "vulnerability-db", v.GetString(common.VPkgCreateVulnDB), lang.CmdPackageCreateFlagVulnerabilityDB)
+	createFlags.StringVar(&pkgConfig.CreateOpts.FailOnSeverity, "fail-on-severity", v.GetString(common.VPkgCreateFailOnSeverity), lang.CmdPackageCreateFlagFailOnSeverity)
 	createFlags.IntVarP(&pkgConfig.CreateOpts.MaxPackageSizeMB, "max-package-size", "m", v.GetInt(common.VPkgCreateMaxPackageSize), lang.CmdPackageCreateFlagMaxPackageSize)
+	createFlags.StringSliceVar(&pkgConfig.CreateOpts.EncryptRecipients, "encrypt", v.GetStringSlice(common.VPkgCreateEncrypt), lang.CmdPackageCreateFlagEncrypt)
+	createFlags.StringVar(&pkgConfig.CreateOpts.EncryptPassphrase, "encrypt-passphrase", v.GetString(common.VPkgCreateEncryptPassphrase), lang.CmdPackageCreateFlagEncryptPassphrase)
+	createFlags.IntVar(&pkgConfig.CreateOpts.MaxCacheSizeMB, "max-cache-size", v.GetInt(common.VPkgCreateMaxCacheSize), lang.CmdPackageCreateFlagMaxCacheSize)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.AllPlatforms, "all-platforms", v.GetBool(common.VPkgCreateAllPlatforms), lang.CmdPackageCreateFlagAllPlatforms)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.IncludeReferrers, "include-referrers", v.GetBool(common.VPkgCreateIncludeReferrers), lang.CmdPackageCreateFlagIncludeReferrers)
+	createFlags.IntVar(&pkgConfig.CreateOpts.ComponentConcurrency, "component-concurrency", v.GetInt(common.VPkgCreateComponentConcurrency), lang.CmdPackageCreateFlagComponentConcurrency)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.RecompressZstd, "recompress-zstd", v.GetBool(common.VPkgCreateRecompressZstd), lang.CmdPackageCreateFlagRecompressZstd)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.ConvertEstargz, "estargz", v.GetBool(common.VPkgCreateEstargz), lang.CmdPackageCreateFlagEstargz)
+	createFlags.StringVar(&pkgConfig.CreateOpts.ImagePolicyPath, "image-policy", v.GetString(common.VPkgCreateImagePolicy), lang.CmdPackageCreateFlagImagePolicy)
+	createFlags.StringVar(&pkgConfig.CreateOpts.ContainerdAddress, "containerd-address", v.GetString(common.VPkgCreateContainerdAddress), lang.CmdPackageCreateFlagContainerdAddress)
+	createFlags.StringVar(&pkgConfig.CreateOpts.ContainerdNamespace, "containerd-namespace", v.GetString(common.VPkgCreateContainerdNS), lang.CmdPackageCreateFlagContainerdNamespace)
 	createFlags.StringToStringVar(&pkgConfig.CreateOpts.RegistryOverrides, "registry-override", v.GetStringMapString(common.VPkgCreateRegistryOverride), lang.CmdPackageCreateFlagRegistryOverride)
 	createFlags.StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	createFlags.StringVar(&remoteBuilder, "remote-builder", v.GetString(common.VPkgCreateRemoteBuilder), lang.CmdPackageCreateFlagRemoteBuilder)
+	createFlags.StringVar(&remoteBuilderToken, "remote-builder-token", v.GetString(common.VPkgCreateRemoteBuilderToken), lang.CmdPackageCreateFlagRemoteBuilderToken)
 
 	createFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPath, "signing-key", v.GetString(common.VPkgCreateSigningKey), lang.CmdPackageCreateFlagSigningKey)
 	createFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPassword, "signing-key-pass", v.GetString(common.VPkgCreateSigningKeyPassword), lang.CmdPackageCreateFlagSigningKeyPassword)
@@ -406,6 +720,9 @@ func bindCreateFlags(v *viper.Viper) {
 	createFlags.MarkHidden("output-directory")
 	createFlags.MarkHidden("key")
 	createFlags.MarkHidden("key-pass")
+
+	packageCreateCmd.MarkFlagsMutuallyExclusive("recompress-zstd", "estargz")
+	packageCreateCmd.MarkFlagsMutuallyExclusive("differential", "differential-from")
 }
 
 func bindDeployFlags(v *viper.Viper) {
@@ -416,7 +733,11 @@ func bindDeployFlags(v *viper.Viper) {
 
 	// Always require adopt-existing-resources flag (no viper)
 	deployFlags.BoolVar(&pkgConfig.DeployOpts.AdoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
+	// Always require allow-shared flag (no viper)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.AllowShared, "allow-shared", false, lang.CmdPackageDeployFlagAllowShared)
 	deployFlags.BoolVar(&pkgConfig.DeployOpts.SkipWebhooks, "skip-webhooks", v.GetBool(common.VPkgDeploySkipWebhooks), lang.CmdPackageDeployFlagSkipWebhooks)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.RequireAgent, "require-agent", v.GetBool(common.VPkgDeployRequireAgent), lang.CmdPackageDeployFlagRequireAgent)
+	deployFlags.StringSliceVar(&pkgConfig.DeployOpts.SkipPhases, "skip", v.GetStringSlice(common.VPkgDeploySkip), lang.CmdPackageDeployFlagSkip)
 	deployFlags.DurationVar(&pkgConfig.DeployOpts.Timeout, "timeout", v.GetDuration(common.VPkgDeployTimeout), lang.CmdPackageDeployFlagTimeout)
 
 	deployFlags.IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
@@ -424,8 +745,21 @@ func bindDeployFlags(v *viper.Viper) {
 	deployFlags.StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VPkgDeployComponents), lang.CmdPackageDeployFlagComponents)
 	deployFlags.StringVar(&pkgConfig.PkgOpts.Shasum, "shasum", v.GetString(common.VPkgDeployShasum), lang.CmdPackageDeployFlagShasum)
 	deployFlags.StringVar(&pkgConfig.PkgOpts.SGetKeyPath, "sget", v.GetString(common.VPkgDeploySget), lang.CmdPackageDeployFlagSget)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.HelmDebugDir, "helm-debug-dir", v.GetString(common.VPkgDeployHelmDebugDir), lang.CmdPackageDeployFlagHelmDebugDir)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.AnswersPath, "answers", v.GetString(common.VPkgDeployAnswers), lang.CmdPackageDeployFlagAnswers)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.ExportAnswersPath, "export-answers", v.GetString(common.VPkgDeployExportAnswers), lang.CmdPackageDeployFlagExportAnswers)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.Retain, "retain", v.GetBool(common.VPkgDeployRetain), lang.CmdPackageDeployFlagRetain)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.RetainDir, "retain-dir", v.GetString(common.VPkgDeployRetainDir), lang.CmdPackageDeployFlagRetainDir)
+	deployFlags.IntVar(&pkgConfig.DeployOpts.RetainMax, "retain-max", v.GetInt(common.VPkgDeployRetainMax), lang.CmdPackageDeployFlagRetainMax)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.TrustRootPath, "trust-root", v.GetString(common.VPkgDeployTrustRoot), lang.CmdPackageDeployFlagTrustRoot)
+	deployFlags.BoolVar(&pkgConfig.PkgOpts.AllowPlugins, "allow-plugins", v.GetBool(common.VPkgAllowPlugins), lang.CmdPackageFlagAllowPlugins)
+
+	deployFlags.BoolVar(&packageResolveOnly, "resolve-only", false, lang.CmdPackageFlagResolveOnly)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.FromClusterCache, "from-cluster-cache", false, lang.CmdPackageDeployFlagFromClusterCache)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.FaultInject, "fault-inject", "", lang.CmdPackageDeployFlagFaultInject)
 
 	deployFlags.MarkHidden("sget")
+	deployFlags.MarkHidden("fault-inject")
 }
 
 func bindMirrorFlags(v *viper.Viper) {
@@ -460,12 +794,19 @@ func bindInspectFlags(_ *viper.Viper) {
 	inspectFlags.BoolVarP(&pkgConfig.InspectOpts.ViewSBOM, "sbom", "s", false, lang.CmdPackageInspectFlagSbom)
 	inspectFlags.StringVar(&pkgConfig.InspectOpts.SBOMOutputDir, "sbom-out", "", lang.CmdPackageInspectFlagSbomOut)
 	inspectFlags.BoolVar(&pkgConfig.InspectOpts.ListImages, "list-images", false, lang.CmdPackageInspectFlagListImages)
+	inspectFlags.BoolVar(&packageResolveOnly, "resolve-only", false, lang.CmdPackageFlagResolveOnly)
+}
+
+func bindVerifyFlags(_ *viper.Viper) {
+	verifyFlags := packageVerifyCmd.Flags()
+	verifyFlags.BoolVar(&pkgConfig.VerifyOpts.Provenance, "provenance", false, lang.CmdPackageVerifyFlagProvenance)
 }
 
 func bindRemoveFlags(v *viper.Viper) {
 	removeFlags := packageRemoveCmd.Flags()
 	removeFlags.BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdPackageRemoveFlagConfirm)
 	removeFlags.StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VPkgDeployComponents), lang.CmdPackageRemoveFlagComponents)
+	removeFlags.BoolVar(&pkgConfig.PkgOpts.AllowPlugins, "allow-plugins", v.GetBool(common.VPkgAllowPlugins), lang.CmdPackageFlagAllowPlugins)
 	_ = packageRemoveCmd.MarkFlagRequired("confirm")
 }
 
@@ -473,9 +814,26 @@ func bindPublishFlags(v *viper.Viper) {
 	publishFlags := packagePublishCmd.Flags()
 	publishFlags.StringVar(&pkgConfig.PublishOpts.SigningKeyPath, "signing-key", v.GetString(common.VPkgPublishSigningKey), lang.CmdPackagePublishFlagSigningKey)
 	publishFlags.StringVar(&pkgConfig.PublishOpts.SigningKeyPassword, "signing-key-pass", v.GetString(common.VPkgPublishSigningKeyPassword), lang.CmdPackagePublishFlagSigningKeyPassword)
+	publishFlags.IntVar(&pkgConfig.PublishOpts.ChunkSizeMB, "chunk-size", v.GetInt(common.VPkgPublishChunkSize), lang.CmdPackagePublishFlagChunkSize)
+}
+
+func bindListFlags(v *viper.Viper) {
+	listFlags := packageListCmd.Flags()
+	listFlags.BoolVar(&packageListLocal, "local", false, lang.CmdPackageListFlagLocal)
+	listFlags.StringVar(&packageListRetainDir, "retain-dir", v.GetString(common.VPkgDeployRetainDir), lang.CmdPackageListFlagRetainDir)
 }
 
 func bindPullFlags(v *viper.Viper) {
 	pullFlags := packagePullCmd.Flags()
 	pullFlags.StringVarP(&pkgConfig.PullOpts.OutputDirectory, "output-directory", "o", v.GetString(common.VPkgPullOutputDir), lang.CmdPackagePullFlagOutputDirectory)
+	pullFlags.BoolVar(&packageResolveOnly, "resolve-only", false, lang.CmdPackageFlagResolveOnly)
+}
+
+func bindSBOMGenerateFlags(v *viper.Viper) {
+	sbomGenerateFlags := packageSBOMGenerateCmd.Flags()
+	sbomGenerateFlags.StringVarP(&pkgConfig.CreateOpts.Output, "output", "o", "", lang.CmdPackageSBOMGenerateFlagOutput)
+	sbomGenerateFlags.StringSliceVar(&pkgConfig.CreateOpts.SkipSBOMComponents, "skip-sbom-components", v.GetStringSlice(common.VPkgCreateSkipSbomComponents), lang.CmdPackageCreateFlagSkipSbomComponents)
+	sbomGenerateFlags.BoolVar(&pkgConfig.CreateOpts.CompressSBOM, "compress-sbom", v.GetBool(common.VPkgCreateCompressSbom), lang.CmdPackageCreateFlagCompressSbom)
+	sbomGenerateFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPath, "signing-key", v.GetString(common.VPkgCreateSigningKey), lang.CmdPackageSBOMGenerateFlagSigningKey)
+	sbomGenerateFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPassword, "signing-key-pass", v.GetString(common.VPkgCreateSigningKeyPassword), lang.CmdPackageSBOMGenerateFlagSigningKeyPass)
 }
@@ -10,6 +10,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -18,6 +19,7 @@ import (
 	"github.com/zarf-dev/zarf/src/cmd/tools"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/telemetry"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
@@ -28,6 +30,8 @@ var (
 	pkgConfig = types.PackagerConfig{}
 	// LogLevelCLI holds the log level as input from a command
 	LogLevelCLI string
+	// Verbosity holds the verbosity as input from a command, set by repeating -v
+	Verbosity int
 	// SkipLogFile is a flag to skip logging to a file
 	SkipLogFile bool
 	// NoColor is a flag to disable colors in output
@@ -58,7 +62,23 @@ var rootCmd = &cobra.Command{
 			skipLogFile = true
 		}
 
-		err := common.SetupCLI(LogLevelCLI, skipLogFile, NoColor)
+		err := common.SetupCLI(LogLevelCLI, Verbosity, skipLogFile, NoColor)
+		if err != nil {
+			return err
+		}
+		config.CommonOptions.ContextPolicy, err = common.GetContextPolicy(common.GetViper())
+		if err != nil {
+			return err
+		}
+		config.CommonOptions.RegistryRateLimit, err = common.GetRegistryRateLimit(common.GetViper())
+		if err != nil {
+			return err
+		}
+		config.CommonOptions.RegistryTLS, err = common.GetRegistryTLS(common.GetViper())
+		if err != nil {
+			return err
+		}
+		config.CommonOptions.RegistryAuth, err = common.GetRegistryAuth(common.GetViper())
 		if err != nil {
 			return err
 		}
@@ -87,7 +107,9 @@ var rootCmd = &cobra.Command{
 
 // Execute is the entrypoint for the CLI.
 func Execute(ctx context.Context) {
+	start := time.Now()
 	cmd, err := rootCmd.ExecuteContextC(ctx)
+	exportMetrics(ctx, cmd, err, time.Since(start))
 	if err == nil {
 		return
 	}
@@ -101,6 +123,35 @@ func Execute(ctx context.Context) {
 	os.Exit(1)
 }
 
+// exportMetrics exports the usage metrics of a command when a metrics endpoint is configured.
+func exportMetrics(ctx context.Context, cmd *cobra.Command, cmdErr error, duration time.Duration) {
+	if config.CommonOptions.MetricsEndpoint == "" || cmd == nil || !cmd.Runnable() {
+		return
+	}
+	// Only the command path is exported, vendored tools and informational commands are not tracked
+	comps := strings.Split(cmd.CommandPath(), " ")
+	if len(comps) < 2 || slices.Contains([]string{"tools", "version", "completion", "internal"}, comps[1]) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+	defer cancel()
+	err := telemetry.Export(ctx, telemetry.Config{
+		Endpoint: config.CommonOptions.MetricsEndpoint,
+		Protocol: config.CommonOptions.MetricsProtocol,
+		StateDir: config.GetAbsCachePath(),
+		Version:  config.CLIVersion,
+		Insecure: config.CommonOptions.Insecure,
+	}, telemetry.Operation{
+		Command:  strings.Join(comps[1:], " "),
+		Failed:   cmdErr != nil,
+		Duration: duration,
+	})
+	if err != nil {
+		message.Warnf(lang.RootCmdWarnMetricsExport, err.Error())
+	}
+}
+
 func init() {
 	// Add the tools commands
 	tools.Include(rootCmd)
@@ -113,6 +164,7 @@ func init() {
 	v := common.InitViper()
 
 	rootCmd.PersistentFlags().StringVarP(&LogLevelCLI, "log-level", "l", v.GetString(common.VLogLevel), lang.RootCmdFlagLogLevel)
+	rootCmd.PersistentFlags().CountVarP(&Verbosity, "verbose", "v", lang.RootCmdFlagVerbose)
 	rootCmd.PersistentFlags().StringVarP(&config.CLIArch, "architecture", "a", v.GetString(common.VArchitecture), lang.RootCmdFlagArch)
 	rootCmd.PersistentFlags().BoolVar(&SkipLogFile, "no-log-file", v.GetBool(common.VNoLogFile), lang.RootCmdFlagSkipLogFile)
 	rootCmd.PersistentFlags().BoolVar(&message.NoProgress, "no-progress", v.GetBool(common.VNoProgress), lang.RootCmdFlagNoProgress)
@@ -120,4 +172,9 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", v.GetString(common.VZarfCache), lang.RootCmdFlagCachePath)
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.TempDirectory, "tmpdir", v.GetString(common.VTmpDir), lang.RootCmdFlagTempDir)
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.Insecure, "insecure", v.GetBool(common.VInsecure), lang.RootCmdFlagInsecure)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.KubeProxy, "kube-proxy", v.GetString(common.VKubeProxy), lang.RootCmdFlagKubeProxy)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.ConfirmContext, "confirm-context", v.GetString(common.VConfirmContext), lang.RootCmdFlagConfirmContext)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.StateKeyPath, "state-key", v.GetString(common.VStateKey), lang.RootCmdFlagStateKey)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.MetricsEndpoint, "metrics-endpoint", v.GetString(common.VMetricsEndpoint), lang.RootCmdFlagMetricsEndpoint)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.MetricsProtocol, "metrics-protocol", v.GetString(common.VMetricsProtocol), lang.RootCmdFlagMetricsProtocol)
 }
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cmd contains the CLI commands for Zarf.
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zarf-dev/zarf/src/cmd/common"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/builder"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	serveAddress            string
+	serveToken              string
+	serveTLSCert            string
+	serveTLSKey             string
+	serveSigningKey         string
+	serveSigningKeyPassword string
+	serveImagePolicy        string
+	serveMaxUploadSizeMB    int
+)
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   lang.CmdServeShort,
+	Long:    lang.CmdServeLong,
+	Example: lang.CmdServeExample,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if (serveTLSCert == "") != (serveTLSKey == "") {
+			return errors.New(lang.CmdServeErrTLS)
+		}
+		if serveSigningKey != "" && serveTLSCert == "" {
+			return errors.New(lang.CmdServeErrSigningKeyTLS)
+		}
+		token := serveToken
+		if token == "" {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				return err
+			}
+			token = hex.EncodeToString(b)
+			message.Notef(lang.CmdServeGeneratedToken, token)
+		}
+
+		// Builds run unattended, there is no one to confirm prompts.
+		config.CommonOptions.Confirm = true
+		build := func(ctx context.Context, opts types.ZarfCreateOptions, arch string) error {
+			opts.SigningKeyPath = serveSigningKey
+			opts.SigningKeyPassword = serveSigningKeyPassword
+			opts.ImagePolicyPath = serveImagePolicy
+			previousArch := config.CLIArch
+			config.CLIArch = arch
+			defer func() {
+				config.CLIArch = previousArch
+			}()
+
+			pkgClient, err := packager.New(&types.PackagerConfig{CreateOpts: opts, PkgOpts: pkgConfig.PkgOpts})
+			if err != nil {
+				return err
+			}
+			defer pkgClient.ClearTempPaths()
+			return pkgClient.Create(ctx)
+		}
+
+		listener, err := net.Listen("tcp", serveAddress)
+		if err != nil {
+			return fmt.Errorf("unable to listen on %s: %w", serveAddress, err)
+		}
+		srv := &http.Server{
+			Handler:           builder.NewHandler(token, int64(serveMaxUploadSizeMB)*1024*1024, build),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		ctx := cmd.Context()
+		go func() {
+			<-ctx.Done()
+			srv.Close()
+		}()
+
+		scheme := "http"
+		if serveTLSCert != "" {
+			scheme = "https"
+		}
+		message.Successf(lang.CmdServeServing, scheme, listener.Addr().String())
+		message.Note(lang.CmdServeExit)
+		if serveTLSCert != "" {
+			err = srv.ServeTLS(listener, serveTLSCert, serveTLSKey)
+		} else {
+			err = srv.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	v := common.InitViper()
+
+	rootCmd.AddCommand(serveCmd)
+
+	serveFlags := serveCmd.Flags()
+	serveFlags.StringVar(&serveAddress, "address", v.GetString(common.VServeAddress), lang.CmdServeFlagAddress)
+	serveFlags.StringVar(&serveToken, "token", v.GetString(common.VServeToken), lang.CmdServeFlagToken)
+	serveFlags.StringVar(&serveTLSCert, "tls-cert", v.GetString(common.VServeTLSCert), lang.CmdServeFlagTLSCert)
+	serveFlags.StringVar(&serveTLSKey, "tls-key", v.GetString(common.VServeTLSKey), lang.CmdServeFlagTLSKey)
+	serveFlags.StringVar(&serveSigningKey, "signing-key", v.GetString(common.VServeSigningKey), lang.CmdServeFlagSigningKey)
+	serveFlags.StringVar(&serveSigningKeyPassword, "signing-key-pass", v.GetString(common.VServeSigningKeyPassword), lang.CmdServeFlagSigningKeyPassword)
+	serveFlags.StringVar(&serveImagePolicy, "image-policy", v.GetString(common.VPkgCreateImagePolicy), lang.CmdServeFlagImagePolicy)
+	serveFlags.IntVar(&serveMaxUploadSizeMB, "max-upload-size", v.GetInt(common.VServeMaxUploadSize), lang.CmdServeFlagMaxUploadSize)
+	serveFlags.IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package tools contains the CLI commands for Zarf.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+var (
+	simulateFilenames []string
+	simulateNamespace string
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: lang.CmdToolsAgentShort,
+}
+
+var agentSimulateCmd = &cobra.Command{
+	Use:     "simulate",
+	Short:   lang.CmdToolsAgentSimulateShort,
+	Long:    lang.CmdToolsAgentSimulateLong,
+	Example: lang.CmdToolsAgentSimulateExample,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+
+		objs := []*unstructured.Unstructured{}
+		for _, filename := range simulateFilenames {
+			var b []byte
+			var err error
+			if filename == "-" {
+				b, err = io.ReadAll(os.Stdin)
+			} else {
+				b, err = os.ReadFile(filename)
+			}
+			if err != nil {
+				return err
+			}
+			fileObjs, err := utils.SplitYAML(b)
+			if err != nil {
+				return fmt.Errorf("unable to read the manifests in %s: %w", filename, err)
+			}
+			objs = append(objs, fileObjs...)
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+
+		mutations, err := agent.SimulateMutations(ctx, c, objs, simulateNamespace)
+		if err != nil {
+			return err
+		}
+		denied := 0
+		for _, mutation := range mutations {
+			switch {
+			case mutation.Skipped != "":
+				fmt.Fprintf(os.Stdout, lang.CmdToolsAgentSimulateSkipped+"\n", mutation.Kind, mutation.Namespace, mutation.Name, mutation.Skipped)
+			case mutation.Err != nil:
+				denied++
+				fmt.Fprintf(os.Stdout, lang.CmdToolsAgentSimulateDenied+"\n", mutation.Kind, mutation.Namespace, mutation.Name, mutation.Webhook, mutation.Err)
+			case len(mutation.Changes) == 0:
+				fmt.Fprintf(os.Stdout, lang.CmdToolsAgentSimulateUnchanged+"\n", mutation.Kind, mutation.Namespace, mutation.Name, mutation.Webhook)
+			default:
+				fmt.Fprintf(os.Stdout, lang.CmdToolsAgentSimulateMutated+"\n", mutation.Kind, mutation.Namespace, mutation.Name, mutation.Webhook)
+				for _, change := range mutation.Changes {
+					fmt.Fprintf(os.Stdout, "    %s: %s -> %s\n", change.Path, formatSimulatedValue(change.Before), formatSimulatedValue(change.After))
+				}
+			}
+		}
+		if denied > 0 {
+			return fmt.Errorf(lang.CmdToolsAgentSimulateErrDenied, denied)
+		}
+		return nil
+	},
+}
+
+// formatSimulatedValue formats a value of a simulated change, strings as they are and anything else as JSON.
+func formatSimulatedValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return v
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
+
+func init() {
+	toolsCmd.AddCommand(agentCmd)
+	agentCmd.AddCommand(agentSimulateCmd)
+	agentSimulateCmd.Flags().StringSliceVarP(&simulateFilenames, "filename", "f", []string{}, lang.CmdToolsAgentSimulateFlagFilename)
+	agentSimulateCmd.Flags().StringVarP(&simulateNamespace, "namespace", "n", "default", lang.CmdToolsAgentSimulateFlagNamespace)
+	agentSimulateCmd.MarkFlagRequired("filename")
+}
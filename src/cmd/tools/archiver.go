@@ -61,11 +61,11 @@ var archiverDecompressCmd = &cobra.Command{
 			if err != nil {
 				return err
 			}
-			if strings.HasSuffix(path, ".tar") {
+			if strings.HasSuffix(path, ".tar") || info.Name() == layout.SBOMCompressedTar {
 				dst := filepath.Join(strings.TrimSuffix(path, ".tar"), "..")
 				// Unpack sboms.tar differently since it has a different folder structure than components
-				if info.Name() == layout.SBOMTar {
-					dst = strings.TrimSuffix(path, ".tar")
+				if info.Name() == layout.SBOMTar || info.Name() == layout.SBOMCompressedTar {
+					dst = strings.TrimSuffix(strings.TrimSuffix(path, ".zst"), ".tar")
 				}
 				err := archiver.Unarchive(path, dst)
 				if err != nil {
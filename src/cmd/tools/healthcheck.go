@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package tools contains the CLI commands for Zarf.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+)
+
+var (
+	healthcheckNamespaces   []string
+	healthcheckInjectorDisk string
+	healthcheckOutput       string
+	healthcheckHost         bool
+)
+
+// healthcheckResult is the JSON output of zarf tools healthcheck.
+type healthcheckResult struct {
+	Passed bool                  `json:"passed"`
+	Checks []cluster.HealthCheck `json:"checks"`
+}
+
+var healthcheckCmd = &cobra.Command{
+	Use:     "healthcheck",
+	Short:   lang.CmdToolsHealthcheckShort,
+	Long:    lang.CmdToolsHealthcheckLong,
+	Example: lang.CmdToolsHealthcheckExample,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+
+		if healthcheckOutput != "text" && healthcheckOutput != "json" {
+			return fmt.Errorf(lang.CmdToolsHealthcheckErrOutput, healthcheckOutput)
+		}
+		injectorDisk, err := resource.ParseQuantity(healthcheckInjectorDisk)
+		if err != nil {
+			return fmt.Errorf(lang.CmdToolsHealthcheckErrInjectorDisk, healthcheckInjectorDisk, err)
+		}
+
+		var checks []cluster.HealthCheck
+		if healthcheckHost {
+			checks = cluster.RunHostPreflightChecks()
+		} else {
+			timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+			defer cancel()
+			c, err := cluster.NewClusterWithWait(timeoutCtx)
+			if err != nil {
+				return err
+			}
+
+			checks = c.RunHealthChecks(ctx, cluster.HealthCheckOptions{
+				Architecture: config.GetArch(),
+				InjectorDisk: injectorDisk,
+				Namespaces:   healthcheckNamespaces,
+			})
+		}
+		failed := 0
+		for _, check := range checks {
+			if check.Status == cluster.HealthCheckFail {
+				failed++
+			}
+		}
+
+		if healthcheckOutput == "json" {
+			b, err := json.MarshalIndent(healthcheckResult{Passed: failed == 0, Checks: checks}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, string(b))
+		} else {
+			cluster.PrintHealthChecks(checks)
+		}
+
+		if failed > 0 && healthcheckHost {
+			return fmt.Errorf(lang.CmdToolsHealthcheckErrHostFailed, failed)
+		}
+		if failed > 0 {
+			return fmt.Errorf(lang.CmdToolsHealthcheckErrFailed, failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	toolsCmd.AddCommand(healthcheckCmd)
+	healthcheckCmd.Flags().StringSliceVar(&healthcheckNamespaces, "namespaces", []string{cluster.ZarfNamespaceName}, lang.CmdToolsHealthcheckFlagNamespaces)
+	healthcheckCmd.Flags().StringVar(&healthcheckInjectorDisk, "injector-disk", cluster.DefaultInjectorDisk.String(), lang.CmdToolsHealthcheckFlagInjectorDisk)
+	healthcheckCmd.Flags().BoolVar(&healthcheckHost, "host", false, lang.CmdToolsHealthcheckFlagHost)
+	healthcheckCmd.Flags().StringVarP(&healthcheckOutput, "output", "o", "text", lang.CmdToolsHealthcheckFlagOutput)
+}
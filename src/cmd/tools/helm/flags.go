@@ -99,6 +99,8 @@ func addKlogFlags(fs *pflag.FlagSet) {
 		}
 		newflag := pflag.PFlagFromGoFlag(fl)
 		newflag.Hidden = true
+		// -v is the verbosity of Zarf, the klog verbosity is still available as --v
+		newflag.Shorthand = ""
 		fs.AddFlag(newflag)
 	})
 }
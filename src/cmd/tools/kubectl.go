@@ -5,11 +5,14 @@
 package tools
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	kubeCLI "k8s.io/component-base/cli"
 	kubeCmd "k8s.io/kubectl/pkg/cmd"
@@ -27,6 +30,12 @@ func init() {
 
 	// Only load this command if it is being called directly.
 	if common.IsVendorCmd(os.Args, []string{"kubectl", "k"}) {
+		if err := checkKubectlContext(os.Args); err != nil {
+			// Kubectl is run before the root command, so the error is printed the way kubectl prints its own
+			fmt.Fprintln(os.Stderr, "error:", err.Error())
+			os.Exit(1)
+		}
+
 		// Add the kubectl command to the tools command.
 		kubectlCmd = kubeCmd.NewDefaultKubectlCommand()
 
@@ -41,3 +50,37 @@ func init() {
 
 	toolsCmd.AddCommand(kubectlCmd)
 }
+
+// checkKubectlContext returns an error when the kubeconfig context kubectl targets is not allowed by the context policy
+// of the config file, reading the --context and --kubeconfig flags from the args like kubectl does.
+func checkKubectlContext(args []string) error {
+	v := common.NewVendorViper()
+	policy, err := common.GetContextPolicy(v)
+	if err != nil {
+		return err
+	}
+	if !policy.IsSet() {
+		return nil
+	}
+	context, err := cluster.CurrentContext(kubectlFlagValue(args, "kubeconfig"), kubectlFlagValue(args, "context"))
+	if err != nil {
+		return err
+	}
+	return cluster.CheckContextPolicy(policy, context, v.GetString(common.VConfirmContext))
+}
+
+// kubectlFlagValue returns the value of a string flag given as --name=value or --name value in the args.
+func kubectlFlagValue(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			return ""
+		}
+		if value, ok := strings.CutPrefix(arg, "--"+name+"="); ok {
+			return value
+		}
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package tools contains the CLI commands for Zarf.
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/trust"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+var (
+	trustRootKeys        []string
+	trustRootPackageKeys []string
+	trustRootThreshold   int
+	trustRootVersion     int
+	trustRootExpires     time.Duration
+	trustRootSigningKey  string
+	trustRootKeyPassword string
+	trustRootTrusted     string
+)
+
+var trustRootCmd = &cobra.Command{
+	Use:   "trust-root",
+	Short: lang.CmdToolsTrustRootShort,
+	Long:  lang.CmdToolsTrustRootLong,
+}
+
+var trustRootCreateCmd = &cobra.Command{
+	Use:     "create ROOT_FILE",
+	Short:   lang.CmdToolsTrustRootCreateShort,
+	Example: lang.CmdToolsTrustRootCreateExample,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		rootKeys, err := readKeys(trustRootKeys)
+		if err != nil {
+			return err
+		}
+		packageKeys, err := readKeys(trustRootPackageKeys)
+		if err != nil {
+			return err
+		}
+		root, err := trust.New(trustRootVersion, time.Now().Add(trustRootExpires), rootKeys, trustRootThreshold, packageKeys)
+		if err != nil {
+			return err
+		}
+		if err := writeTrustRoot(args[0], root); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsTrustRootCreated, trustRootVersion, args[0], trustRootThreshold)
+		return nil
+	},
+}
+
+var trustRootSignCmd = &cobra.Command{
+	Use:   "sign ROOT_FILE",
+	Short: lang.CmdToolsTrustRootSignShort,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		root, err := readTrustRoot(args[0])
+		if err != nil {
+			return err
+		}
+
+		tmp, err := utils.MakeTempDir("")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		msg, err := root.SignedBytes()
+		if err != nil {
+			return err
+		}
+		msgPath := filepath.Join(tmp, "root-signed.json")
+		if err := os.WriteFile(msgPath, msg, helpers.ReadWriteUser); err != nil {
+			return err
+		}
+		passwordFunc := func(_ bool) ([]byte, error) {
+			if trustRootKeyPassword != "" {
+				return []byte(trustRootKeyPassword), nil
+			}
+			return interactive.PromptSigPassword()
+		}
+		sig, err := utils.CosignSignBlob(msgPath, filepath.Join(tmp, "root-signed.json.sig"), trustRootSigningKey, passwordFunc)
+		if err != nil {
+			return fmt.Errorf("unable to sign the trust root: %w", err)
+		}
+		trusted := []*trust.Root{}
+		if trustRootTrusted != "" {
+			t, err := readTrustRoot(trustRootTrusted)
+			if err != nil {
+				return err
+			}
+			trusted = append(trusted, t)
+		}
+		keyID, err := root.AddSignature(sig, trusted...)
+		if err != nil {
+			return err
+		}
+
+		if err := writeTrustRoot(args[0], root); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsTrustRootSigned, args[0], keyID)
+		return nil
+	},
+}
+
+var trustRootVerifyCmd = &cobra.Command{
+	Use:   "verify ROOT_FILE",
+	Short: lang.CmdToolsTrustRootVerifyShort,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		root, err := readTrustRoot(args[0])
+		if err != nil {
+			return err
+		}
+		if trustRootTrusted == "" {
+			if err := root.Verify(time.Now()); err != nil {
+				return err
+			}
+			message.Successf(lang.CmdToolsTrustRootVerified, root.Signed.Version, root.Signed.Expires.Format(time.RFC3339))
+			return nil
+		}
+		trusted, err := readTrustRoot(trustRootTrusted)
+		if err != nil {
+			return err
+		}
+		if err := root.VerifyUpdate(trusted, time.Now()); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsTrustRootVerifiedUpdate, root.Signed.Version, trusted.Signed.Version)
+		return nil
+	},
+}
+
+func init() {
+	toolsCmd.AddCommand(trustRootCmd)
+	trustRootCmd.AddCommand(trustRootCreateCmd)
+	trustRootCmd.AddCommand(trustRootSignCmd)
+	trustRootCmd.AddCommand(trustRootVerifyCmd)
+
+	trustRootCreateCmd.Flags().StringArrayVar(&trustRootKeys, "root-key", nil, lang.CmdToolsTrustRootCreateFlagRootKey)
+	trustRootCreateCmd.Flags().StringArrayVar(&trustRootPackageKeys, "package-key", nil, lang.CmdToolsTrustRootCreateFlagPackageKey)
+	trustRootCreateCmd.Flags().IntVar(&trustRootThreshold, "threshold", 1, lang.CmdToolsTrustRootCreateFlagThreshold)
+	trustRootCreateCmd.Flags().IntVar(&trustRootVersion, "version", 1, lang.CmdToolsTrustRootCreateFlagVersion)
+	trustRootCreateCmd.Flags().DurationVar(&trustRootExpires, "expires", 365*24*time.Hour, lang.CmdToolsTrustRootCreateFlagExpires)
+	_ = trustRootCreateCmd.MarkFlagRequired("root-key")
+	_ = trustRootCreateCmd.MarkFlagRequired("package-key")
+
+	trustRootSignCmd.Flags().StringVarP(&trustRootSigningKey, "key", "k", "", lang.CmdToolsTrustRootSignFlagKey)
+	trustRootSignCmd.Flags().StringVar(&trustRootKeyPassword, "key-pass", "", lang.CmdToolsTrustRootSignFlagKeyPass)
+	trustRootSignCmd.Flags().StringVar(&trustRootTrusted, "trusted", "", lang.CmdToolsTrustRootSignFlagTrusted)
+	_ = trustRootSignCmd.MarkFlagRequired("key")
+
+	trustRootVerifyCmd.Flags().StringVar(&trustRootTrusted, "trusted", "", lang.CmdToolsTrustRootVerifyFlagTrusted)
+}
+
+func readKeys(paths []string) ([][]byte, error) {
+	keys := [][]byte{}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, b)
+	}
+	return keys, nil
+}
+
+func readTrustRoot(path string) (*trust.Root, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return trust.Parse(b)
+}
+
+func writeTrustRoot(path string, root *trust.Root) error {
+	b, err := root.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), helpers.ReadAllWriteUser)
+}
@@ -5,21 +5,26 @@
 package tools
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"k8s.io/client-go/tools/clientcmd"
 
 	// Import to initialize client auth plugins.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
 var (
-	waitTimeout   string
-	waitNamespace string
+	waitTimeout    string
+	waitNamespace  string
+	waitExpression string
 )
 
 var waitForCmd = &cobra.Command{
@@ -29,7 +34,7 @@ var waitForCmd = &cobra.Command{
 	Long:    lang.CmdToolsWaitForLong,
 	Example: lang.CmdToolsWaitForExample,
 	Args:    cobra.MinimumNArgs(1),
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse the timeout string
 		timeout, err := time.ParseDuration(waitTimeout)
 		if err != nil {
@@ -50,17 +55,57 @@ var waitForCmd = &cobra.Command{
 			condition = args[2]
 		}
 
-		// Execute the wait command.
-		if err := utils.ExecuteWait(waitTimeout, waitNamespace, condition, kind, identifier, timeout); err != nil {
+		// Handle network endpoints.
+		switch kind {
+		case "http", "https", "tcp":
+			if waitExpression != "" {
+				return errors.New(lang.CmdToolsWaitForErrExpressionNetwork)
+			}
+			return utils.WaitForNetworkEndpoint(kind, identifier, condition, timeout)
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+		c, err := cluster.NewCluster()
+		if err != nil {
+			return err
+		}
+
+		opts := cluster.WaitOptions{
+			Kind:       kind,
+			Name:       identifier,
+			Namespace:  waitNamespace,
+			Condition:  condition,
+			Expression: waitExpression,
+		}
+		if opts.Namespace == "" {
+			opts.Namespace = kubeconfigNamespace()
+		}
+		spinner := message.NewProgressSpinner("Waiting for %s.", opts)
+		defer spinner.Stop()
+		if err := c.WaitFor(ctx, opts); err != nil {
 			return err
 		}
-		return err
+		spinner.Successf("Waited for %s.", opts)
+		return nil
 	},
 }
 
+// kubeconfigNamespace returns the namespace of the current kubeconfig context, like kubectl does when no namespace is given.
+func kubeconfigNamespace() string {
+	namespace, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{}).Namespace()
+	if err != nil {
+		return ""
+	}
+	return namespace
+}
+
 func init() {
 	toolsCmd.AddCommand(waitForCmd)
 	waitForCmd.Flags().StringVar(&waitTimeout, "timeout", "5m", lang.CmdToolsWaitForFlagTimeout)
 	waitForCmd.Flags().StringVarP(&waitNamespace, "namespace", "n", "", lang.CmdToolsWaitForFlagNamespace)
+	waitForCmd.Flags().StringVar(&waitExpression, "expression", "", lang.CmdToolsWaitForFlagExpression)
 	waitForCmd.Flags().BoolVar(&message.NoProgress, "no-progress", false, lang.RootCmdFlagNoProgress)
 }
@@ -5,11 +5,15 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
@@ -23,18 +27,33 @@ import (
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/gitea"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
 var subAltNames []string
+var getCredsFormat string
+var clearCacheImages bool
+var clearCacheOCI bool
+var clearCacheRepos bool
+var clearCacheOlderThan time.Duration
+var clearCacheMaxSizeMB int
+var cacheGCOlderThan time.Duration
+var cacheGCMaxSizeMB int
 var outputDirectory string
+var downloadInitOpts types.ZarfPackageOptions
 var updateCredsInitOpts types.ZarfInitOptions
+var updateCredsDryRun bool
+var stateVerifySign bool
+var statePassphrase string
 
 var deprecatedGetGitCredsCmd = &cobra.Command{
 	Use:    "get-git-password",
@@ -73,6 +92,19 @@ var getCredsCmd = &cobra.Command{
 			return errors.New("Zarf state secret did not load properly")
 		}
 
+		if getCredsFormat != "" {
+			key := ""
+			if len(args) > 0 {
+				key = args[0]
+			}
+			out, err := message.FormatCredentials(state, key, getCredsFormat)
+			if err != nil {
+				return err
+			}
+			fmt.Print(out)
+			return nil
+		}
+
 		if len(args) > 0 {
 			// If a component name is provided, only show that component's credentials
 			message.PrintComponentCredential(state, args[0])
@@ -100,6 +132,9 @@ var updateCredsCmd = &cobra.Command{
 				return fmt.Errorf("invalid service key specified, valid keys are: %s, %s, and %s", message.RegistryKey, message.GitKey, message.ArtifactKey)
 			}
 		}
+		if _, err := updateCredsInitOpts.ArtifactServer.ParsePushTokenLifetime(); err != nil {
+			return err
+		}
 
 		ctx := cmd.Context()
 
@@ -118,11 +153,19 @@ var updateCredsCmd = &cobra.Command{
 		if oldState.Distro == "" {
 			return errors.New("Zarf state secret did not load properly")
 		}
+		updateCredsInitOpts.RegistryInfo.AdditionalPullCredentials, err = common.GetAdditionalPullCredentials(common.GetViper())
+		if err != nil {
+			return err
+		}
 		newState, err := cluster.MergeZarfState(oldState, updateCredsInitOpts, args)
 		if err != nil {
 			return fmt.Errorf("unable to update Zarf credentials: %w", err)
 		}
 
+		if updateCredsDryRun {
+			return printUpdateCredsDryRun(ctx, c, oldState, newState, args)
+		}
+
 		message.PrintCredentialUpdates(oldState, newState, args)
 
 		confirm := config.CommonOptions.Confirm
@@ -155,28 +198,7 @@ var updateCredsCmd = &cobra.Command{
 
 			// Update artifact token (if internal)
 			if slices.Contains(args, message.ArtifactKey) && newState.ArtifactServer.PushToken == "" && newState.ArtifactServer.IsInternal() {
-				tunnel, err := c.NewTunnel(cluster.ZarfNamespaceName, cluster.SvcResource, cluster.ZarfGitServerName, "", 0, cluster.ZarfGitServerPort)
-				if err != nil {
-					return err
-				}
-				_, err = tunnel.Connect(cmd.Context())
-				if err != nil {
-					return err
-				}
-				defer tunnel.Close()
-				tunnelURL := tunnel.HTTPEndpoint()
-				giteaClient, err := gitea.NewClient(tunnelURL, oldState.GitServer.PushUsername, oldState.GitServer.PushPassword)
-				if err != nil {
-					return err
-				}
-				err = tunnel.Wrap(func() error {
-					tokenSha1, err := giteaClient.CreatePackageRegistryToken(ctx)
-					if err != nil {
-						return err
-					}
-					newState.ArtifactServer.PushToken = tokenSha1
-					return nil
-				})
+				err := c.CreateArtifactToken(ctx, newState, oldState.GitServer)
 				if err != nil {
 					// Warn if we couldn't actually update the git server (it might not be installed and we should try to continue)
 					message.Warnf(lang.CmdToolsUpdateCredsUnableCreateToken, err.Error())
@@ -242,16 +264,415 @@ var updateCredsCmd = &cobra.Command{
 	},
 }
 
+// printUpdateCredsDryRun prints the changes update-creds would make to the cluster without making them.
+func printUpdateCredsDryRun(ctx context.Context, c *cluster.Cluster, oldState, newState *types.ZarfState, services []string) error {
+	stateDiff, err := message.CredentialStateDiff(oldState, newState)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, lang.CmdToolsUpdateCredsDryRunState+"\n", cluster.ZarfNamespaceName, cluster.ZarfStateSecretName)
+	fmt.Fprint(os.Stdout, stateDiff)
+
+	secretChanges := []string{}
+	if slices.Contains(services, message.RegistryKey) {
+		secrets, err := c.GetOutdatedZarfManagedImageSecrets(ctx, newState)
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			secretChanges = append(secretChanges, fmt.Sprintf("~ secret %s/%s (%s)", secret.Namespace, secret.Name, secret.Type))
+		}
+	}
+	if slices.Contains(services, message.GitKey) {
+		secrets, err := c.GetOutdatedZarfManagedGitSecrets(ctx, newState)
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			secretChanges = append(secretChanges, fmt.Sprintf("~ secret %s/%s (%s)", secret.Namespace, secret.Name, secret.Type))
+		}
+	}
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintln(os.Stdout, lang.CmdToolsUpdateCredsDryRunSecrets)
+	printDryRunChanges(secretChanges)
+
+	serviceChanges := []string{}
+	registryChanged := oldState.RegistryInfo.PushUsername != newState.RegistryInfo.PushUsername ||
+		oldState.RegistryInfo.PushPassword != newState.RegistryInfo.PushPassword ||
+		oldState.RegistryInfo.PullUsername != newState.RegistryInfo.PullUsername ||
+		oldState.RegistryInfo.PullPassword != newState.RegistryInfo.PullPassword
+	if slices.Contains(services, message.RegistryKey) && newState.RegistryInfo.IsInternal() && registryChanged {
+		serviceChanges = append(serviceChanges, fmt.Sprintf("~ helm release %s/%s: secrets.htpasswd", cluster.ZarfNamespaceName, cluster.ZarfRegistryName))
+	}
+	gitChanged := oldState.GitServer.PushUsername != newState.GitServer.PushUsername ||
+		oldState.GitServer.PushPassword != newState.GitServer.PushPassword ||
+		oldState.GitServer.PullUsername != newState.GitServer.PullUsername ||
+		oldState.GitServer.PullPassword != newState.GitServer.PullPassword
+	if slices.Contains(services, message.GitKey) && newState.GitServer.IsInternal() && gitChanged {
+		serviceChanges = append(serviceChanges, fmt.Sprintf("~ git server users %s and %s", newState.GitServer.PushUsername, newState.GitServer.PullUsername))
+	}
+	if slices.Contains(services, message.ArtifactKey) && newState.ArtifactServer.PushToken == "" && newState.ArtifactServer.IsInternal() {
+		serviceChanges = append(serviceChanges, fmt.Sprintf("+ artifact server push token for %s", newState.GitServer.PushUsername))
+	}
+	agentChanged := !bytes.Equal(oldState.AgentTLS.CA, newState.AgentTLS.CA) ||
+		!bytes.Equal(oldState.AgentTLS.Cert, newState.AgentTLS.Cert) ||
+		!bytes.Equal(oldState.AgentTLS.Key, newState.AgentTLS.Key)
+	if slices.Contains(services, message.AgentKey) && agentChanged {
+		serviceChanges = append(serviceChanges, fmt.Sprintf("~ helm release of chart raw-init-zarf-agent-zarf-agent in %s: agent TLS secret and webhook CA bundle", cluster.ZarfNamespaceName))
+		serviceChanges = append(serviceChanges, fmt.Sprintf("~ deployment %s/agent-hook: rolling restart", cluster.ZarfNamespaceName))
+	}
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintln(os.Stdout, lang.CmdToolsUpdateCredsDryRunServices)
+	printDryRunChanges(serviceChanges)
+
+	return nil
+}
+
+func printDryRunChanges(changes []string) {
+	if len(changes) == 0 {
+		fmt.Fprintln(os.Stdout, lang.CmdToolsUpdateCredsDryRunNoChanges)
+		return
+	}
+	for _, change := range changes {
+		fmt.Fprintln(os.Stdout, change)
+	}
+}
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: lang.CmdToolsStateShort,
+}
+
+var stateVerifyCmd = &cobra.Command{
+	Use:     "verify",
+	Short:   lang.CmdToolsStateVerifyShort,
+	Long:    lang.CmdToolsStateVerifyLong,
+	Example: lang.CmdToolsStateVerifyExample,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+
+		if stateVerifySign {
+			if err := c.SignZarfState(ctx); err != nil {
+				return fmt.Errorf("unable to sign the Zarf state: %w", err)
+			}
+			message.Successf(lang.CmdToolsStateVerifySigned, cluster.ZarfNamespaceName, cluster.ZarfStateSecretName)
+		}
+
+		if c.StateKey == nil {
+			message.Warn(lang.CmdToolsStateVerifyNoStateKey)
+		}
+		if err := c.VerifyZarfState(ctx); err != nil {
+			return fmt.Errorf(lang.CmdToolsStateVerifyErrState, cluster.ZarfNamespaceName, cluster.ZarfStateSecretName, err)
+		}
+		message.Successf(lang.CmdToolsStateVerifyStateValid, cluster.ZarfNamespaceName, cluster.ZarfStateSecretName)
+
+		state, err := c.LoadZarfState(ctx)
+		if err != nil {
+			return err
+		}
+		imageSecrets, err := c.GetOutdatedZarfManagedImageSecrets(ctx, state)
+		if err != nil {
+			return err
+		}
+		gitSecrets, err := c.GetOutdatedZarfManagedGitSecrets(ctx, state)
+		if err != nil {
+			return err
+		}
+		mismatched := append(imageSecrets, gitSecrets...)
+		for _, secret := range mismatched {
+			message.Warnf(lang.CmdToolsStateVerifySecretMismatch, secret.Namespace, secret.Name)
+		}
+		if len(mismatched) > 0 {
+			return fmt.Errorf(lang.CmdToolsStateVerifyErrSecrets, len(mismatched))
+		}
+		message.Success(lang.CmdToolsStateVerifySecretsValid)
+		return nil
+	},
+}
+
+var stateBackupCmd = &cobra.Command{
+	Use:     "backup FILE",
+	Short:   lang.CmdToolsStateBackupShort,
+	Long:    lang.CmdToolsStateBackupLong,
+	Example: lang.CmdToolsStateBackupExample,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		passphrase, err := getStatePassphrase(true)
+		if err != nil {
+			return err
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+		backup, err := c.BackupState(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to back up the Zarf state: %w", err)
+		}
+		b, err := cluster.EncryptStateBackup(backup, passphrase)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[0], b, helpers.ReadWriteUser); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsStateBackupSuccess, args[0], len(backup.PackageSecrets), len(backup.PullSecrets))
+		return nil
+	},
+}
+
+var stateRestoreCmd = &cobra.Command{
+	Use:     "restore FILE",
+	Short:   lang.CmdToolsStateRestoreShort,
+	Long:    lang.CmdToolsStateRestoreLong,
+	Example: lang.CmdToolsStateRestoreExample,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		b, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		passphrase, err := getStatePassphrase(false)
+		if err != nil {
+			return err
+		}
+		backup, err := cluster.DecryptStateBackup(b, passphrase)
+		if err != nil {
+			return err
+		}
+		message.Notef(lang.CmdToolsStateRestoreSummary, backup.CreatedAt.Format(time.RFC3339), len(backup.PackageSecrets), len(backup.PullSecrets))
+
+		confirm := config.CommonOptions.Confirm
+		if !confirm {
+			prompt := &survey.Confirm{
+				Message: lang.CmdToolsStateRestoreConfirm,
+			}
+			if err := survey.AskOne(prompt, &confirm); err != nil {
+				return fmt.Errorf("confirm selection canceled: %w", err)
+			}
+		}
+		if !confirm {
+			return nil
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+		if err := c.RestoreState(ctx, backup); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsStateRestoreSuccess, args[0])
+		return nil
+	},
+}
+
+// getStatePassphrase returns the passphrase of a state backup from the --passphrase flag or by prompting for it.
+func getStatePassphrase(confirm bool) ([]byte, error) {
+	if statePassphrase != "" {
+		return []byte(statePassphrase), nil
+	}
+	var passphrase string
+	if err := survey.AskOne(&survey.Password{Message: lang.CmdToolsStatePassphrasePrompt}, &passphrase); err != nil {
+		return nil, fmt.Errorf(lang.CmdToolsStateErrPassphrase, err)
+	}
+	if passphrase == "" {
+		return nil, errors.New(lang.CmdToolsStateErrPassphraseEmpty)
+	}
+	if confirm {
+		var doubleCheck string
+		if err := survey.AskOne(&survey.Password{Message: lang.CmdToolsStatePassphrasePromptAgain}, &doubleCheck); err != nil {
+			return nil, fmt.Errorf(lang.CmdToolsStateErrPassphrase, err)
+		}
+		if passphrase != doubleCheck {
+			return nil, errors.New(lang.CmdToolsStateErrPassphraseMismatch)
+		}
+	}
+	return []byte(passphrase), nil
+}
+
 var clearCacheCmd = &cobra.Command{
 	Use:     "clear-cache",
 	Aliases: []string{"c"},
 	Short:   lang.CmdToolsClearCacheShort,
+	Long:    lang.CmdToolsClearCacheLong,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cachePath := config.GetAbsCachePath()
+		message.Notef(lang.CmdToolsClearCacheDir, cachePath)
+
+		pruneOpts := utils.CachePruneOptions{
+			OlderThan: clearCacheOlderThan,
+			MaxSize:   int64(clearCacheMaxSizeMB) * 1000 * 1000,
+		}
+		isPrune := pruneOpts.OlderThan > 0 || pruneOpts.MaxSize > 0
+
+		if !clearCacheImages && !clearCacheOCI && !clearCacheRepos && !isPrune {
+			if err := os.RemoveAll(cachePath); err != nil {
+				return fmt.Errorf("unable to clear the cache directory %s: %w", cachePath, err)
+			}
+			message.Successf(lang.CmdToolsClearCacheSuccess, cachePath)
+			return nil
+		}
+
+		// When no cache type is selected, age and size based pruning applies to all of them
+		for _, cache := range selectCaches(cachePath, clearCacheImages, clearCacheOCI, clearCacheRepos) {
+			freed, err := clearCache(cache, isPrune, pruneOpts)
+			if err != nil {
+				return err
+			}
+			if !isPrune {
+				message.Successf(lang.CmdToolsClearCacheSuccessType, cache.name, cachePath)
+				continue
+			}
+			message.Successf(lang.CmdToolsClearCachePruned, utils.ByteFormat(float64(freed), 2), cache.name, cachePath)
+		}
+		return nil
+	},
+}
+
+// cacheType is a type of entries in the cache directory.
+type cacheType struct {
+	name string
+	// The directory that is removed to clear the cache
+	root string
+	// The directories the entries are stored in, which are pruned
+	dirs []string
+}
+
+// selectCaches returns the types of entries in the cache directory, or only the selected types when any are selected.
+func selectCaches(cachePath string, images, oci, repos bool) []cacheType {
+	all := !images && !oci && !repos
+	caches := []cacheType{}
+	if images || all {
+		caches = append(caches, cacheType{
+			name: layout.ImagesDir,
+			root: filepath.Join(cachePath, layout.ImagesDir),
+			dirs: []string{filepath.Join(cachePath, layout.ImagesDir)},
+		})
+	}
+	if oci || all {
+		caches = append(caches, cacheType{
+			name: "oci",
+			root: filepath.Join(cachePath, "oci"),
+			dirs: []string{filepath.Join(cachePath, "oci", "blobs", "sha256"), filepath.Join(cachePath, "oci", "dirs")},
+		})
+	}
+	if repos || all {
+		caches = append(caches, cacheType{
+			name: layout.ReposDir,
+			root: filepath.Join(cachePath, "git-imports"),
+			dirs: []string{filepath.Join(cachePath, "git-imports")},
+		})
+	}
+	return caches
+}
+
+// clearCache removes all entries of a cache, or prunes its entries that match the options, returning the bytes freed
+// by pruning.
+func clearCache(cache cacheType, prune bool, pruneOpts utils.CachePruneOptions) (int64, error) {
+	if !prune {
+		if err := os.RemoveAll(cache.root); err != nil {
+			return 0, fmt.Errorf("unable to clear the %s cache: %w", cache.name, err)
+		}
+		return 0, nil
+	}
+	var freed int64
+	for _, dir := range cache.dirs {
+		dirFreed, err := utils.PruneCacheDir(dir, pruneOpts)
+		freed += dirFreed
+		if err != nil {
+			return freed, fmt.Errorf("unable to prune the %s cache: %w", cache.name, err)
+		}
+	}
+	return freed, nil
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: lang.CmdToolsCacheShort,
+	Long:  lang.CmdToolsCacheLong,
+}
+
+var cacheGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: lang.CmdToolsCacheGCShort,
+	Long:  lang.CmdToolsCacheGCLong,
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cachePath := config.GetAbsCachePath()
+		message.Notef(lang.CmdToolsClearCacheDir, cachePath)
+
+		pruneOpts := utils.CachePruneOptions{
+			OlderThan: cacheGCOlderThan,
+			MaxSize:   int64(cacheGCMaxSizeMB) * 1000 * 1000,
+		}
+		freed, err := images.GarbageCollectLayerCache(filepath.Join(cachePath, layout.ImagesDir), pruneOpts)
+		if err != nil {
+			return fmt.Errorf("unable to garbage collect the image layer cache: %w", err)
+		}
+		for _, cache := range selectCaches(cachePath, false, true, true) {
+			cacheFreed, err := clearCache(cache, true, pruneOpts)
+			freed += cacheFreed
+			if err != nil {
+				return err
+			}
+		}
+		message.Successf(lang.CmdToolsCacheGCSuccess, utils.ByteFormat(float64(freed), 2), cachePath)
+		return nil
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: lang.CmdToolsCacheStatsShort,
+	Args:  cobra.NoArgs,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		message.Notef(lang.CmdToolsClearCacheDir, config.GetAbsCachePath())
-		if err := os.RemoveAll(config.GetAbsCachePath()); err != nil {
-			return fmt.Errorf("unable to clear the cache directory %s: %w", config.GetAbsCachePath(), err)
+		cachePath := config.GetAbsCachePath()
+		message.Notef(lang.CmdToolsClearCacheDir, cachePath)
+
+		cacheDirs := selectCaches(cachePath, false, false, false)
+		header := []string{"Cache", "Entries", "Size", "Last Used", "Least Recently Used"}
+		data := [][]string{}
+		for _, cacheDir := range cacheDirs {
+			total := utils.CacheStats{}
+			for _, dir := range cacheDir.dirs {
+				stats, err := utils.CacheDirStats(dir)
+				if err != nil {
+					return fmt.Errorf("unable to read the %s cache: %w", cacheDir.name, err)
+				}
+				total.Entries += stats.Entries
+				total.Size += stats.Size
+				if stats.LastUsed.After(total.LastUsed) {
+					total.LastUsed = stats.LastUsed
+				}
+				if !stats.LeastRecentlyUsed.IsZero() && (total.LeastRecentlyUsed.IsZero() || stats.LeastRecentlyUsed.Before(total.LeastRecentlyUsed)) {
+					total.LeastRecentlyUsed = stats.LeastRecentlyUsed
+				}
+			}
+			lastUsed, leastRecentlyUsed := "-", "-"
+			if total.Entries > 0 {
+				lastUsed = total.LastUsed.Format(time.DateTime)
+				leastRecentlyUsed = total.LeastRecentlyUsed.Format(time.DateTime)
+			}
+			data = append(data, []string{cacheDir.name, strconv.Itoa(total.Entries), utils.ByteFormat(float64(total.Size), 2), lastUsed, leastRecentlyUsed})
 		}
-		message.Successf(lang.CmdToolsClearCacheSuccess, config.GetAbsCachePath())
+		message.Table(header, data)
 		return nil
 	},
 }
@@ -259,14 +680,35 @@ var clearCacheCmd = &cobra.Command{
 var downloadInitCmd = &cobra.Command{
 	Use:   "download-init",
 	Short: lang.CmdToolsDownloadInitShort,
+	Long:  lang.CmdToolsDownloadInitLong,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		url := zoci.GetInitPackageURL(config.CLIVersion)
-		remote, err := zoci.NewRemote(url, oci.PlatformForArch(config.GetArch()))
-		if err != nil {
-			return fmt.Errorf("unable to download the init package: %w", err)
+		var source sources.PackageSource
+		if downloadInitOpts.PackageSource == "" {
+			if downloadInitOpts.Shasum != "" {
+				return errors.New(lang.CmdToolsDownloadInitErrShasumWithoutFrom)
+			}
+			url := zoci.GetInitPackageURL(config.CLIVersion)
+			remote, err := zoci.NewRemote(url, oci.PlatformForArch(config.GetArch()))
+			if err != nil {
+				return fmt.Errorf("unable to download the init package: %w", err)
+			}
+			source = &sources.OCISource{Remote: remote}
+		} else {
+			if sources.Identify(downloadInitOpts.PackageSource) == "oci" {
+				url, err := zoci.GetInitPackageURLFromMirror(downloadInitOpts.PackageSource, config.CLIVersion)
+				if err != nil {
+					return fmt.Errorf("unable to download the init package: %w", err)
+				}
+				downloadInitOpts.PackageSource = url
+			}
+			message.Notef(lang.CmdToolsDownloadInitFromMirror, downloadInitOpts.PackageSource)
+			var err error
+			source, err = sources.New(&downloadInitOpts)
+			if err != nil {
+				return fmt.Errorf("unable to download the init package: %w", err)
+			}
 		}
-		source := &sources.OCISource{Remote: remote}
-		_, err = source.Collect(cmd.Context(), outputDirectory)
+		_, err := source.Collect(cmd.Context(), outputDirectory)
 		if err != nil {
 			return fmt.Errorf("unable to download the init package: %w", err)
 		}
@@ -375,11 +817,14 @@ func init() {
 
 	toolsCmd.AddCommand(deprecatedGetGitCredsCmd)
 	toolsCmd.AddCommand(getCredsCmd)
+	getCredsCmd.Flags().StringVar(&getCredsFormat, "format", "", lang.CmdToolsGetCredsFlagFormat)
 
 	toolsCmd.AddCommand(updateCredsCmd)
 
 	// Always require confirm flag (no viper)
 	updateCredsCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdToolsUpdateCredsConfirmFlag)
+	updateCredsCmd.Flags().BoolVar(&updateCredsDryRun, "dry-run", false, lang.CmdToolsUpdateCredsDryRunFlag)
+	updateCredsCmd.MarkFlagsMutuallyExclusive("confirm", "dry-run")
 
 	// Flags for using an external Git server
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.GitServer.Address, "git-url", v.GetString(common.VInitGitURL), lang.CmdInitFlagGitURL)
@@ -399,14 +844,38 @@ func init() {
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.Address, "artifact-url", v.GetString(common.VInitArtifactURL), lang.CmdInitFlagArtifactURL)
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.PushUsername, "artifact-push-username", v.GetString(common.VInitArtifactPushUser), lang.CmdInitFlagArtifactPushUser)
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.PushToken, "artifact-push-token", v.GetString(common.VInitArtifactPushToken), lang.CmdInitFlagArtifactPushToken)
+	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.PushTokenLifetime, "artifact-push-token-lifetime", v.GetString(common.VInitArtifactPushTokenLifetime), lang.CmdInitFlagArtifactPushTokenLifetime)
 
 	updateCredsCmd.Flags().SortFlags = true
 
+	toolsCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateVerifyCmd)
+	stateVerifyCmd.Flags().BoolVar(&stateVerifySign, "sign", false, lang.CmdToolsStateVerifyFlagSign)
+	stateCmd.AddCommand(stateBackupCmd)
+	stateBackupCmd.Flags().StringVar(&statePassphrase, "passphrase", "", lang.CmdToolsStateFlagPassphrase)
+	stateCmd.AddCommand(stateRestoreCmd)
+	stateRestoreCmd.Flags().StringVar(&statePassphrase, "passphrase", "", lang.CmdToolsStateFlagPassphrase)
+	stateRestoreCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdToolsStateRestoreFlagConfirm)
+
 	toolsCmd.AddCommand(clearCacheCmd)
 	clearCacheCmd.Flags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", config.ZarfDefaultCachePath, lang.CmdToolsClearCacheFlagCachePath)
+	clearCacheCmd.Flags().BoolVar(&clearCacheImages, "images", false, lang.CmdToolsClearCacheFlagImages)
+	clearCacheCmd.Flags().BoolVar(&clearCacheOCI, "oci", false, lang.CmdToolsClearCacheFlagOCI)
+	clearCacheCmd.Flags().BoolVar(&clearCacheRepos, "repos", false, lang.CmdToolsClearCacheFlagRepos)
+	clearCacheCmd.Flags().DurationVar(&clearCacheOlderThan, "older-than", 0, lang.CmdToolsClearCacheFlagOlderThan)
+	clearCacheCmd.Flags().IntVar(&clearCacheMaxSizeMB, "max-size", 0, lang.CmdToolsClearCacheFlagMaxSize)
+
+	toolsCmd.AddCommand(cacheCmd)
+	cacheCmd.PersistentFlags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", config.ZarfDefaultCachePath, lang.CmdToolsClearCacheFlagCachePath)
+	cacheCmd.AddCommand(cacheGCCmd)
+	cacheGCCmd.Flags().DurationVar(&cacheGCOlderThan, "older-than", 0, lang.CmdToolsClearCacheFlagOlderThan)
+	cacheGCCmd.Flags().IntVar(&cacheGCMaxSizeMB, "max-size", 0, lang.CmdToolsClearCacheFlagMaxSize)
+	cacheCmd.AddCommand(cacheStatsCmd)
 
 	toolsCmd.AddCommand(downloadInitCmd)
 	downloadInitCmd.Flags().StringVarP(&outputDirectory, "output-directory", "o", "", lang.CmdToolsDownloadInitFlagOutputDirectory)
+	downloadInitCmd.Flags().StringVar(&downloadInitOpts.PackageSource, "from", "", lang.CmdToolsDownloadInitFlagFrom)
+	downloadInitCmd.Flags().StringVar(&downloadInitOpts.Shasum, "shasum", "", lang.CmdToolsDownloadInitFlagShasum)
 
 	toolsCmd.AddCommand(generatePKICmd)
 	generatePKICmd.Flags().StringArrayVar(&subAltNames, "sub-alt-name", []string{}, lang.CmdToolsGenPkiFlagAltName)
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package tools contains the CLI commands for Zarf.
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestSelectCaches(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		images   bool
+		oci      bool
+		repos    bool
+		expected []string
+	}{
+		{
+			name:     "all caches",
+			expected: []string{"images", "oci", "repos"},
+		},
+		{
+			name:     "images",
+			images:   true,
+			expected: []string{"images"},
+		},
+		{
+			name:     "repos",
+			repos:    true,
+			expected: []string{"repos"},
+		},
+		{
+			name:     "oci and repos",
+			oci:      true,
+			repos:    true,
+			expected: []string{"oci", "repos"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			names := []string{}
+			for _, cache := range selectCaches("/cache", tt.images, tt.oci, tt.repos) {
+				names = append(names, cache.name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestClearCacheRepos(t *testing.T) {
+	t.Parallel()
+
+	cachePath := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, path := range []string{
+		filepath.Join(cachePath, "git-imports", "old-repo"),
+		filepath.Join(cachePath, "git-imports", "new-repo"),
+		filepath.Join(cachePath, "images", "sha256-layer"),
+	} {
+		require.NoError(t, os.MkdirAll(path, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(path, "file"), []byte("content"), 0o600))
+	}
+	require.NoError(t, os.Chtimes(filepath.Join(cachePath, "git-imports", "old-repo"), old, old))
+	require.NoError(t, os.Chtimes(filepath.Join(cachePath, "images", "sha256-layer"), old, old))
+
+	caches := selectCaches(cachePath, false, false, true)
+	require.Len(t, caches, 1)
+
+	// Pruning only removes the repositories that were not used recently
+	freed, err := clearCache(caches[0], true, utils.CachePruneOptions{OlderThan: 24 * time.Hour})
+	require.NoError(t, err)
+	require.Positive(t, freed)
+	require.NoDirExists(t, filepath.Join(cachePath, "git-imports", "old-repo"))
+	require.DirExists(t, filepath.Join(cachePath, "git-imports", "new-repo"))
+	require.DirExists(t, filepath.Join(cachePath, "images", "sha256-layer"))
+
+	// Clearing removes every repository and leaves the other caches
+	_, err = clearCache(caches[0], false, utils.CachePruneOptions{})
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(cachePath, "git-imports"))
+	require.DirExists(t, filepath.Join(cachePath, "images", "sha256-layer"))
+}
+
+func TestDownloadInitFromMirror(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		//nolint:errcheck // ignore
+		rw.Write([]byte("not the init package"))
+	}))
+	t.Cleanup(srv.Close)
+
+	opts, dir := downloadInitOpts, outputDirectory
+	t.Cleanup(func() {
+		downloadInitOpts, outputDirectory = opts, dir
+	})
+	downloadInitCmd.SetContext(testutil.TestContext(t))
+
+	t.Run("shasum without --from", func(t *testing.T) {
+		downloadInitOpts = types.ZarfPackageOptions{Shasum: "sha256"}
+		err := downloadInitCmd.RunE(downloadInitCmd, nil)
+		require.EqualError(t, err, lang.CmdToolsDownloadInitErrShasumWithoutFrom)
+	})
+
+	t.Run("shasum mismatch", func(t *testing.T) {
+		outputDirectory = t.TempDir()
+		downloadInitOpts = types.ZarfPackageOptions{
+			PackageSource: srv.URL + "/zarf-init-amd64.tar.zst",
+			Shasum:        "930f4d5a191812e57b39bd60fca789ace07ec5acd36d63e1047604c8bdf998a3",
+		}
+		err := downloadInitCmd.RunE(downloadInitCmd, nil)
+		require.ErrorContains(t, err, "shasum mismatch")
+		entries, err := os.ReadDir(outputDirectory)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
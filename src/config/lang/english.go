@@ -45,17 +45,24 @@ const (
 	RootCmdLong  = "Zarf eliminates the complexity of air gap software delivery for Kubernetes clusters and cloud native workloads\n" +
 		"using a declarative packaging strategy to support DevSecOps in offline and semi-connected environments."
 
-	RootCmdFlagLogLevel    = "Log level when running Zarf. Valid options are: warn, info, debug, trace"
-	RootCmdFlagArch        = "Architecture for OCI images and Zarf packages"
-	RootCmdFlagSkipLogFile = "Disable log file creation"
-	RootCmdFlagNoProgress  = "Disable fancy UI progress bars, spinners, logos, etc"
-	RootCmdFlagNoColor     = "Disable colors in output"
-	RootCmdFlagCachePath   = "Specify the location of the Zarf cache directory"
-	RootCmdFlagTempDir     = "Specify the temporary directory to use for intermediate files"
-	RootCmdFlagInsecure    = "Allow access to insecure registries and disable other recommended security enforcements such as package checksum and signature validation. This flag should only be used if you have a specific reason and accept the reduced security posture."
-
-	RootCmdDeprecatedDeploy = "Deprecated: Please use \"zarf package deploy %s\" to deploy this package.  This warning will be removed in Zarf v1.0.0."
-	RootCmdDeprecatedCreate = "Deprecated: Please use \"zarf package create\" to create this package.  This warning will be removed in Zarf v1.0.0."
+	RootCmdFlagLogLevel        = "Log level when running Zarf. Valid options are: warn, info, debug, trace"
+	RootCmdFlagVerbose         = "Increase the detail of the output, repeat for more: -v shows the debug messages of components, -vv also shows the Helm debug log and rendered chart manifests, -vvv also logs HTTP requests to registries and git servers with secrets redacted"
+	RootCmdFlagArch            = "Architecture for OCI images and Zarf packages"
+	RootCmdFlagSkipLogFile     = "Disable log file creation"
+	RootCmdFlagNoProgress      = "Disable fancy UI progress bars, spinners, logos, etc"
+	RootCmdFlagNoColor         = "Disable colors in output"
+	RootCmdFlagCachePath       = "Specify the location of the Zarf cache directory"
+	RootCmdFlagTempDir         = "Specify the temporary directory to use for intermediate files"
+	RootCmdFlagConfirmContext  = "Name of the kubeconfig context to target when it is protected by the context_policy of the config file, instead of typing its name at a prompt"
+	RootCmdFlagStateKey        = "Path to an operator-held key file of at least 32 bytes that the Zarf state is additionally signed and verified with, so changes made by cluster users without it are detected"
+	RootCmdFlagKubeProxy       = "Proxy to connect to the Kubernetes API server through, for clusters that are only reachable through a bastion host. Valid options are an http://, https:// or socks5:// proxy URL, or an ssh://[user@]host[:port] jump host"
+	RootCmdFlagMetricsEndpoint = "URL of an in-enclave Prometheus pushgateway or OTLP/HTTP collector to export anonymous usage metrics (operation counts, durations and package sizes) to. Metrics are only exported when this is set"
+	RootCmdFlagMetricsProtocol = "Protocol to export usage metrics with. Valid options are: pushgateway, otlp"
+	RootCmdFlagInsecure        = "Allow access to insecure registries and disable other recommended security enforcements such as package checksum and signature validation. This flag should only be used if you have a specific reason and accept the reduced security posture."
+
+	RootCmdDeprecatedDeploy  = "Deprecated: Please use \"zarf package deploy %s\" to deploy this package.  This warning will be removed in Zarf v1.0.0."
+	RootCmdWarnMetricsExport = "Unable to export usage metrics: %s"
+	RootCmdDeprecatedCreate  = "Deprecated: Please use \"zarf package create\" to create this package.  This warning will be removed in Zarf v1.0.0."
 
 	// zarf connect
 	CmdConnectShort = "Accesses services or pods deployed in the cluster"
@@ -68,23 +75,96 @@ const (
 		"the name you will pass into the 'zarf connect' command.\n\n" +
 		"Even if the packages you deploy don't define their own shortcut connection options, you can use the command flags " +
 		"to connect into specific resources. You can read the command flag descriptions below to get a better idea how to connect " +
-		"to whatever resource you are trying to connect to."
+		"to whatever resource you are trying to connect to.\n\n" +
+		"Targets can also be declared in the connect.definitions section of the config file, with the namespace, resource, ports and " +
+		"whether to only print the URL of the tunnel, so they are connected to by name without memorizing flags. A definition that " +
+		"only sets some of these refines the 'zarf.dev/connect-name' label or the built-in target of the same name.\n\n" +
+		"Pass several targets, or a profile of targets from the config file with --profile, to keep a tunnel open to each of them in one process. " +
+		"Append :LOCAL_PORT to a target to bind its tunnel to that port.\n\n" +
+		"Services whose first port is UDP, or resources connected to with --protocol udp, are reached through a relay pod Zarf runs in the " +
+		"zarf namespace, as Kubernetes port forwards only support TCP. Their tunnels are printed as udp:// URLs and not opened in a browser."
+	CmdConnectExample = `
+# Connect to the Zarf registry:
+$ zarf connect registry
+
+# Connect to the registry, the git server and a package's UI at once, binding the UI to port 9898:
+$ zarf connect registry git podinfo:9898 --cli-only
+
+# Connect to the grafana target declared in the connect.definitions section of the config file:
+$ zarf connect grafana
+
+# Connect to the targets of the dev profile in the config file:
+$ zarf connect --profile dev
+
+# Serve a package's UI with HTTPS on local port 8443, for web UIs and OIDC flows that refuse plain http:
+$ zarf connect podinfo:8443 --local-tls
+
+# Connect to the UDP port of a DNS service on local port 5353:
+$ zarf connect --name coredns --namespace kube-system --remote-port 53 --protocol udp --local-port 5353
+
+# Connect to port 8080 of any ready pod labeled app=foo, following the pods as they are rescheduled:
+$ zarf connect --type pod --selector app=foo --namespace foo --remote-port 8080
+
+# Print the local port and URL of the tunnel as JSON for other tools to consume:
+$ zarf connect git --background -o json
+`
 
 	// zarf connect list
-	CmdConnectListShort = "Lists all available connection shortcuts"
-
-	CmdConnectFlagName       = "Specify the resource name.  E.g. name=unicorns or name=unicorn-pod-7448499f4d-b5bk6. Ignored if connect-name is supplied."
-	CmdConnectFlagNamespace  = "Specify the namespace.  E.g. namespace=default. Ignored if connect-name is supplied."
-	CmdConnectFlagType       = "Specify the resource type.  E.g. type=svc or type=pod. Ignored if connect-name is supplied."
-	CmdConnectFlagLocalPort  = "(Optional, autogenerated if not provided) Specify the local port to bind to.  E.g. local-port=42000."
-	CmdConnectFlagRemotePort = "Specify the remote port of the resource to bind to.  E.g. remote-port=8080. Ignored if connect-name is supplied."
-	CmdConnectFlagCliOnly    = "Disable browser auto-open"
+	CmdConnectListShort   = "Lists all available connection shortcuts"
+	CmdConnectStatusShort = "Lists the zarf connect processes running in the background"
+	CmdConnectStopShort   = "Stops zarf connect processes running in the background"
+
+	CmdConnectFlagName         = "Specify the resource name.  E.g. name=unicorns or name=unicorn-pod-7448499f4d-b5bk6. Ignored if connect-name is supplied."
+	CmdConnectFlagNamespace    = "Specify the namespace.  E.g. namespace=default. Ignored if connect-name is supplied."
+	CmdConnectFlagType         = "Specify the resource type.  E.g. type=svc or type=pod. Ignored if connect-name is supplied."
+	CmdConnectFlagSelector     = "Connect to a ready pod matching the label selector instead of a named pod, following the pods as they are replaced by re-establishing lost tunnels.  E.g. selector=app=foo. Requires type=pod, ignored if connect-name is supplied."
+	CmdConnectFlagLocalPort    = "(Optional, autogenerated if not provided) Specify the local port to bind to.  E.g. local-port=42000."
+	CmdConnectFlagRemotePort   = "Specify the remote port of the resource to bind to.  E.g. remote-port=8080. Ignored if connect-name is supplied."
+	CmdConnectFlagCliOnly      = "Disable browser auto-open"
+	CmdConnectFlagProfile      = "Connect to the targets of a profile in the connect.profiles section of the config file"
+	CmdConnectFlagLocalTLS     = "Serve the local port of the tunnel with HTTPS, using a certificate for localhost and 127.0.0.1 signed by a CA kept in the Zarf cache unless --local-tls-cert is set"
+	CmdConnectFlagLocalTLSCert = "Path to the PEM encoded certificate to serve the local port with, implies --local-tls"
+	CmdConnectFlagLocalTLSKey  = "Path to the PEM encoded private key of --local-tls-cert"
+	CmdConnectFlagReconnect    = "Re-establish tunnels that are lost, e.g. during a rolling restart of the pods behind them, retrying with exponential backoff instead of exiting"
+	CmdConnectFlagBackground   = "Keep the tunnels open in a background process and return once they are established, see 'zarf connect status' and 'zarf connect stop'"
+	CmdConnectFlagProtocol     = "Specify the protocol of the remote port, tcp or udp. Ignored if connect-name is supplied, the protocol of the service port is used instead."
+	CmdConnectFlagOutput       = "Output format (text|json). json prints the target, namespace, resource, ports and URL of each tunnel as a JSON object, or an array of them for multiple targets, and implies --cli-only"
+	CmdConnectListFlagOutput   = "Output format (text|json)"
+
+	CmdConnectFlagEphemeralCredentials        = "Issue read-only credentials for the 'git' or 'registry' target that are revoked when the tunnel closes, instead of sharing the long-lived push credentials. The internal registry restarts when its credentials are added and removed"
+	CmdConnectEphemeralCredsUnsupported       = "ephemeral credentials can only be issued for the 'git' and 'registry' targets"
+	CmdConnectEphemeralCredsValid             = "until the tunnel closes"
+	CmdConnectEphemeralCredsRegistryExternal  = "ephemeral credentials can only be issued by the internal Zarf registry"
+	CmdConnectEphemeralCredsRegistryRestart   = "Adding the ephemeral registry credentials, the registry restarts to read them"
+	CmdConnectEphemeralCredsRegistryRevoked   = "Removed the ephemeral registry credentials of %s, the registry restarted without them"
+	CmdConnectEphemeralCredsRegistryRevokeErr = "Unable to remove the ephemeral registry credentials of %s, run 'zarf tools update-creds registry' to revoke them with the other registry credentials"
+	CmdConnectEphemeralCredsRevoked           = "Revoked the ephemeral git access token %s"
+	CmdConnectEphemeralCredsRevokeErr         = "Unable to revoke the ephemeral git access token %s, delete it from the settings of the %s Gitea user"
 
 	CmdConnectPreparingTunnel = "Preparing a tunnel to connect to %s"
 	CmdConnectEstablishedCLI  = "Tunnel established at %s, waiting for user to interrupt (ctrl-c to end)"
 	CmdConnectEstablishedWeb  = "Tunnel established at %s, opening your default web browser (ctrl-c to end)"
 	CmdConnectTunnelClosed    = "Tunnel to %s successfully closed due to user interrupt"
 
+	CmdConnectPreparingTunnels     = "Preparing tunnels to connect to %d targets"
+	CmdConnectEstablishedMultiple  = "%d tunnels established, waiting for user to interrupt (ctrl-c to end)"
+	CmdConnectTunnelsClosed        = "%d tunnels successfully closed due to user interrupt"
+	CmdConnectErrLocalPortMultiple = "the --local-port flag can only be used with a single target, append :LOCAL_PORT to each target instead"
+	CmdConnectLocalTLSCA           = "Serving the tunnels with HTTPS, trust the CA at %s to avoid certificate warnings"
+	CmdConnectErrProtocol          = "invalid protocol %q, must be tcp or udp"
+	CmdConnectErrSelectorName      = "the --selector and --name flags can not be used together"
+	CmdConnectErrSelectorType      = "the --selector flag can only be used with --type pod, not --type %s"
+	CmdConnectErrOutput            = "unsupported output format %q, use text or json"
+
+	CmdConnectReconnecting          = "Lost the tunnel to %s, re-establishing it: %s"
+	CmdConnectReconnected           = "Re-established the tunnel to %s at %s"
+	CmdConnectBackgroundStarted     = "Tunnels running in the background as %s (PID %d), stop them with 'zarf connect stop %s'"
+	CmdConnectErrBackgroundRunning  = "zarf connect is already running in the background as %s, stop it with 'zarf connect stop %s' first"
+	CmdConnectErrBackgroundExited   = "zarf connect exited before its tunnels were established, see the log at %s"
+	CmdConnectErrBackgroundNotFound = "no zarf connect is running in the background as %s, see 'zarf connect status'"
+	CmdConnectBackgroundNone        = "No zarf connect processes are running in the background"
+	CmdConnectBackgroundStopped     = "Stopped zarf connect %s"
+
 	// zarf destroy
 	CmdDestroyShort = "Tears down Zarf and removes its components from the environment"
 	CmdDestroyLong  = "Tear down Zarf.\n\n" +
@@ -93,7 +173,7 @@ const (
 		"searching through /opt/zarf for any scripts that start with 'zarf-clean-' and executing them. " +
 		"Since this is a cleanup operation, Zarf will not stop the teardown if one of the scripts produce " +
 		"an error.\n\n" +
-		"If Zarf did not deploy your k8s cluster, this command will delete the Zarf namespace, delete secrets " +
+		"If Zarf did not deploy your k8s cluster, this command will delete the Zarf namespace and the ZarfState custom resource definition, delete secrets " +
 		"and labels that only Zarf cares about, and optionally uninstall components that Zarf deployed onto " +
 		"the cluster. Since this is a cleanup operation, Zarf will not stop the uninstalls if one of the " +
 		"resources produce an error while being deleted."
@@ -134,12 +214,26 @@ $ zarf init --git-push-password={PASSWORD} --git-push-username={USERNAME} --git-
 # Initializing w/ an external artifact server:
 $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNAME} --artifact-url={URL}
 
+# Initializing w/ a user provided PKI for the Zarf agent:
+$ zarf init --agent-tls-ca=ca.crt --agent-tls-cert=tls.crt --agent-tls-key=tls.key
+
+# Initializing declaratively w/ every option set in the [init] section of a zarf-config file:
+$ ZARF_CONFIG=zarf-init-config.toml zarf init --confirm
+
 # NOTE: Not specifying a pull username/password will use the push user for pulling as well.
 `
 
-	CmdInitErrValidateGit      = "the 'git-push-username' and 'git-push-password' flags must be provided if the 'git-url' flag is provided"
-	CmdInitErrValidateRegistry = "the 'registry-push-username' and 'registry-push-password' flags must be provided if the 'registry-url' flag is provided"
-	CmdInitErrValidateArtifact = "the 'artifact-push-username' and 'artifact-push-token' flags must be provided if the 'artifact-url' flag is provided"
+	CmdInitErrValidateGit             = "the 'git-push-username' and 'git-push-password' flags must be provided if the 'git-url' flag is provided"
+	CmdInitErrValidateRegistry        = "the 'registry-push-username' and 'registry-push-password' flags must be provided if the 'registry-url' flag is provided"
+	CmdInitErrValidateArtifact        = "the 'artifact-push-username' and 'artifact-push-token' flags must be provided if the 'artifact-url' flag is provided"
+	CmdInitErrValidateAgentTLS        = "the 'agent-tls-ca', 'agent-tls-cert' and 'agent-tls-key' flags must all be provided when providing a PKI for the Zarf agent"
+	CmdInitErrValidateRegCredProvider = "the 'registry-credential-provider' flag can only be used with the 'registry-url' flag"
+	CmdInitErrValidateRegPullNS       = "the 'registry-pull-per-namespace' flag is only supported by the internal registry and can not be used with the 'registry-url' flag"
+	CmdInitErrValidateRegHA           = "the 'registry-replicas' and 'registry-s3-*' flags are only supported by the internal registry and can not be used with the 'registry-url' flag"
+	CmdInitErrValidateRegReplicas     = "the 'registry-replicas' flag can not be negative"
+	CmdInitErrValidateRegS3           = "the 'registry-s3-bucket' and 'registry-s3-region' flags must be provided to store the images of the internal registry in a bucket"
+	CmdInitErrValidateRegS3Keys       = "the 'registry-s3-access-key' and 'registry-s3-secret-key' flags must be provided together"
+	CmdInitErrValidateSecretNS        = "the 'secret-namespaces-allow' and 'secret-namespaces-deny' flags must be valid glob patterns: %w"
 
 	CmdInitPullAsk       = "It seems the init package could not be found locally, but can be pulled from oci://%s"
 	CmdInitPullNote      = "Note: This will require an internet connection."
@@ -158,17 +252,36 @@ $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNA
 	CmdInitFlagGitPullUser = "Username for pull-only access to the git server"
 	CmdInitFlagGitPullPass = "Password for the pull-only user to access the git server"
 
-	CmdInitFlagRegURL      = "External registry url address to use for this Zarf cluster"
-	CmdInitFlagRegNodePort = "Nodeport to access a registry internal to the k8s cluster. Between [30000-32767]"
-	CmdInitFlagRegPushUser = "Username to access to the registry Zarf is configured to use"
-	CmdInitFlagRegPushPass = "Password for the push-user to connect to the registry"
-	CmdInitFlagRegPullUser = "Username for pull-only access to the registry"
-	CmdInitFlagRegPullPass = "Password for the pull-only user to access the registry"
-	CmdInitFlagRegSecret   = "Registry secret value"
-
-	CmdInitFlagArtifactURL       = "[alpha] External artifact registry url to use for this Zarf cluster"
-	CmdInitFlagArtifactPushUser  = "[alpha] Username to access to the artifact registry Zarf is configured to use. User must be able to upload package artifacts."
-	CmdInitFlagArtifactPushToken = "[alpha] API Token for the push-user to access the artifact registry"
+	CmdInitFlagRegURL              = "External registry url address to use for this Zarf cluster"
+	CmdInitFlagRegNodePort         = "Nodeport to access a registry internal to the k8s cluster. Between [30000-32767]"
+	CmdInitFlagRegPushUser         = "Username to access to the registry Zarf is configured to use"
+	CmdInitFlagRegPushPass         = "Password for the push-user to connect to the registry"
+	CmdInitFlagRegPullUser         = "Username for pull-only access to the registry"
+	CmdInitFlagRegPullPass         = "Password for the pull-only user to access the registry"
+	CmdInitFlagRegSecret           = "Registry secret value"
+	CmdInitFlagRegCredProvider     = "Cloud credential provider (ecr, acr or gcr) to issue the short-lived credentials of the external registry with instead of the push and pull users"
+	CmdInitFlagRegPullPerNamespace = "Give the image pull secret of every namespace its own pull-only credentials instead of the shared pull user. Only supported by the internal registry"
+	CmdInitFlagRegReplicas         = "Number of replicas of the internal registry, more than one requires a bucket or a ReadWriteMany PVC (--set REGISTRY_PVC_ACCESS_MODE=ReadWriteMany) for the replicas to share"
+	CmdInitFlagRegS3Bucket         = "S3-compatible bucket the internal registry stores images in instead of a PVC"
+	CmdInitFlagRegS3Region         = "Region of the bucket the internal registry stores images in"
+	CmdInitFlagRegS3Endpoint       = "Endpoint of the S3-compatible service (e.g. MinIO) the bucket is in, AWS S3 is used when not set"
+	CmdInitFlagRegS3RootDirectory  = "Prefix within the bucket that the internal registry stores images under"
+	CmdInitFlagRegS3AccessKey      = "Access key to the bucket, the credentials of the registry's service account or node are used when not set"
+	CmdInitFlagRegS3SecretKey      = "Secret key to the bucket"
+
+	CmdInitFlagArtifactURL               = "[alpha] External artifact registry url to use for this Zarf cluster"
+	CmdInitFlagArtifactPushUser          = "[alpha] Username to access to the artifact registry Zarf is configured to use. User must be able to upload package artifacts."
+	CmdInitFlagArtifactPushToken         = "[alpha] API Token for the push-user to access the artifact registry"
+	CmdInitFlagArtifactPushTokenLifetime = "[alpha] How long the push token of the artifact registry is used for before it expires and is rotated (e.g. 720h), tokens never expire when not set"
+
+	CmdInitFlagAgentTLSCA   = "Path to the certificate authority the Zarf agent certificate is signed by, a PKI is generated if not provided"
+	CmdInitFlagAgentTLSCert = "Path to the TLS certificate for the Zarf agent, valid for agent-hook.zarf.svc"
+	CmdInitFlagAgentTLSKey  = "Path to the private key of the Zarf agent TLS certificate"
+
+	CmdInitFlagSecretNamespacesAllow = "Glob patterns of the namespaces Zarf-managed image and git pull secrets are written to, every namespace when not provided.  E.g. --secret-namespaces-allow='team-*'"
+	CmdInitFlagSecretNamespacesDeny  = "Glob patterns of the namespaces Zarf-managed image and git pull secrets are never written to, taking precedence over the allowed namespaces"
+
+	CmdInitFlagSkipPreflight = "Skip the host preflight checks of the kernel modules, cgroups, SELinux, AppArmor, disk space and ports run before installing the bundled K3s"
 
 	// zarf internal
 	CmdInternalShort = "Internal tools used by zarf"
@@ -205,24 +318,56 @@ $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNA
 	CmdInternalUpdateGiteaPVCErr          = "Unable to update the existing Gitea persistent volume claim."
 	CmdInternalFlagUpdateGiteaPVCRollback = "Roll back previous Gitea persistent volume claim updates."
 
+	CmdInternalRefreshRegistryCredentialsShort = "Refreshes the short-lived credentials of a cloud registry"
+	CmdInternalRefreshRegistryCredentialsLong  = "Issues new credentials for the external registry with the credential provider set during 'zarf init', " +
+		"saves them to the Zarf state and updates the image pull secrets of the cluster with them."
+
+	CmdInternalRotateArtifactTokenShort = "Rotates the push token of the internal artifact registry before it expires"
+	CmdInternalRotateArtifactTokenLong  = "Creates a new push token for the artifact registry in Gitea when the current one expires within the duration set with --before, " +
+		"deletes the previous token and saves the new one to the Zarf state. Tokens of an external artifact registry are not rotated, a warning is printed when they are about to expire."
+
+	CmdInternalFlagRotateArtifactTokenBefore = "Rotate the token when it expires within this duration"
+
+	CmdInternalUDPRelayShort = "Relays UDP datagrams framed over TCP port forwards to a UDP address"
+	CmdInternalUDPRelayLong  = "Accepts the TCP connections 'zarf connect' port forwards to this pod and relays the datagrams framed on them to the target UDP address, " +
+		"as Kubernetes port forwards only support TCP. This is run in a pod created by 'zarf connect' for UDP services."
+
+	CmdInternalFlagUDPRelayListen = "Address to accept the TCP connections on"
+	CmdInternalFlagUDPRelayTarget = "UDP address to relay the datagrams to"
+
 	CmdInternalIsValidHostnameShort = "Checks if the current machine's hostname is RFC1123 compliant"
 
 	CmdInternalCrc32Short = "Generates a decimal CRC32 for the given text"
 
 	// zarf package
-	CmdPackageShort             = "Zarf package commands for creating, deploying, and inspecting packages"
-	CmdPackageFlagConcurrency   = "Number of concurrent layer operations to perform when interacting with a remote package."
-	CmdPackageFlagFlagPublicKey = "Path to public key file for validating signed packages"
-	CmdPackageFlagRetries       = "Number of retries to perform for Zarf deploy operations like git/image pushes or Helm installs"
+	CmdPackageShort                 = "Zarf package commands for creating, deploying, and inspecting packages"
+	CmdPackageFlagConcurrency       = "Number of concurrent layer operations to perform when interacting with a remote package."
+	CmdPackageFlagImageConcurrency  = "Number of images to pull and save at once on create, and of image layers to push at once on deploy."
+	CmdPackageFlagFlagPublicKey     = "Path to public key file for validating signed packages"
+	CmdPackageFlagDecryptIdentity   = "Path to an age identity file to decrypt encrypted packages with, can be repeated"
+	CmdPackageFlagDecryptPassphrase = "Passphrase to decrypt packages encrypted with --encrypt-passphrase"
+	CmdPackageFlagRetries           = "Number of retries to perform for Zarf deploy operations like git/image pushes or Helm installs"
+	CmdPackageFlagAllowPlugins      = "Run the plugin executables of the package without listing them and asking first, required with --confirm when the package has plugins"
 
 	CmdPackageCreateShort = "Creates a Zarf package from a given directory or the current directory"
 	CmdPackageCreateLong  = "Builds an archive of resources and dependencies defined by the 'zarf.yaml' in the specified directory.\n" +
 		"Private registries and repositories are accessed via credentials in your local '~/.docker/config.json', " +
-		"'~/.git-credentials' and '~/.netrc'.\n"
+		"'~/.git-credentials' and '~/.netrc'.\n\n" +
+		"The directory can also be a git build context such as 'https://github.com/org/repo//path?ref=v1.2.3', which is " +
+		"cloned with its submodules to create the package from the zarf.yaml at the path within the repository.\n"
 
 	CmdPackageDeployShort = "Deploys a Zarf package from a local file or URL (runs offline)"
 	CmdPackageDeployLong  = "Unpacks resources and dependencies from a Zarf package archive and deploys them onto the target system.\n" +
-		"Kubernetes clusters are accessed via credentials in your current kubecontext defined in '~/.kube/config'"
+		"Kubernetes clusters are accessed via credentials in your current kubecontext defined in '~/.kube/config'\n\n" +
+		"A PACKAGE_SOURCE of '-' reads the package archive from stdin as it is extracted, which requires --confirm."
+	CmdPackageDeployExample = `
+# Deploy a package archive
+$ zarf package deploy zarf-package-dos-games-amd64-1.0.0.tar.zst --confirm
+
+# Deploy a package archive streamed over ssh without copying it to the host first
+$ ssh jump-host cat zarf-package-dos-games-amd64-1.0.0.tar.zst | zarf package deploy - --confirm
+`
+	CmdPackageDeployErrStdinConfirm = "a package read from stdin can only be deployed with --confirm since prompts cannot be answered"
 
 	CmdPackageMirrorShort = "Mirrors a Zarf package's internal resources to specified image registries and git repositories"
 	CmdPackageMirrorLong  = "Unpacks resources and dependencies from a Zarf package archive and mirrors them into the specified\n" +
@@ -250,33 +395,96 @@ $ zarf package mirror-resources <your-package.tar.zst> \
 	CmdPackageInspectShort = "Displays the definition of a Zarf package (runs offline)"
 	CmdPackageInspectLong  = "Displays the 'zarf.yaml' definition for the specified package and optionally allows SBOMs to be viewed"
 
+	CmdPackageInspectLayersShort    = "Reports how image layers are shared across the images in a Zarf package (runs offline)"
+	CmdPackageInspectLayersLong     = "Reports the images that contribute the most unique data to a package, the layers shared between images, and hints for images that could be consolidated to shrink the package"
+	CmdPackageInspectLayersNoImages = "This package does not contain any images"
+	CmdPackageInspectLayersTotal    = "%d images using %s of distinct layers"
+
+	CmdPackageSBOMShort = "Manages the SBOMs of a Zarf package"
+
+	CmdPackageSBOMGenerateShort   = "Generates the SBOMs for a Zarf package that was created with --skip-sbom, or regenerates them (runs offline)"
+	CmdPackageSBOMGenerateLong    = "Generates the SBOMs for a local Zarf package tarball and rewrites the package with them. Use this to move SBOM cataloging out of 'zarf package create' for image-heavy packages. Signed packages must be re-signed with --signing-key since the package checksums change."
+	CmdPackageSBOMGenerateExample = `
+# Create a package without SBOMs and generate them later
+$ zarf package create . --skip-sbom
+$ zarf package sbom generate zarf-package-my-package-amd64.tar.zst
+
+# Generate compressed SBOMs into a different directory, re-signing the package
+$ zarf package sbom generate zarf-package-my-package-amd64.tar.zst --compress-sbom -o ./out --signing-key cosign.key
+`
+	CmdPackageSBOMGenerateFlagOutput           = "Specify the output directory for the rewritten Zarf package (defaults to the directory of the source package)"
+	CmdPackageSBOMGenerateFlagSigningKey       = "Path to a private key file for re-signing the package, required if the package was signed"
+	CmdPackageSBOMGenerateFlagSigningKeyPass   = "Password to the private key file used for re-signing the package"
+	CmdPackageSBOMGenerateErrSource            = "SBOMs can only be generated for local package tarballs, pull the package first with 'zarf package pull'"
+	CmdPackageSBOMGenerateErrSigned            = "the package is signed and rewriting it will invalidate the signature, provide --signing-key to re-sign it"
+	CmdPackageSBOMGenerateSuccess              = "Generated SBOMs for package %q, written to %s"
+	CmdPackageSBOMGenerateSkipComponentMissing = "Component %q passed to skip SBOM generation does not exist in the package"
+
 	CmdPackageListShort         = "Lists out all of the packages that have been deployed to the cluster (runs offline)"
 	CmdPackageListNoPackageWarn = "Unable to get the packages deployed to the cluster"
+	CmdPackageListFlagLocal     = "List the archives of deployed packages retained on this host with --retain instead of the packages deployed to the cluster"
+	CmdPackageListFlagRetainDir = "Directory the archives of deployed packages are retained in, defaults to the deployed-packages directory of the Zarf cache"
+	CmdPackageListNoneRetained  = "No package archives are retained in %s"
 
 	CmdPackageCreateFlagConfirm               = "Confirm package creation without prompting"
 	CmdPackageCreateFlagSet                   = "Specify package variables to set on the command line (KEY=value)"
+	CmdPackageCreateFlagSetFile               = "Specify package variables to set to the contents of a file, for large values such as certificates or scripts (KEY=path)"
 	CmdPackageCreateFlagOutput                = "Specify the output (either a directory or an oci:// URL) for the created Zarf package"
 	CmdPackageCreateFlagSbom                  = "View SBOM contents after creating the package"
 	CmdPackageCreateFlagSbomOut               = "Specify an output directory for the SBOMs from the created Zarf package"
-	CmdPackageCreateFlagSkipSbom              = "Skip generating SBOM for this package"
+	CmdPackageCreateFlagSkipSbom              = "Skip generating SBOM for this package, SBOMs can be generated later with 'zarf package sbom generate'"
+	CmdPackageCreateFlagScanVulnerabilities   = "Scan the SBOMs of the package for vulnerabilities with a grype vulnerability database and store the report with the SBOMs"
+	CmdPackageCreateFlagVulnerabilityDB       = "Path to a grype vulnerability database archive to scan with without network access, by default the latest database is downloaded to the Zarf cache"
+	CmdPackageCreateFlagFailOnSeverity        = "Fail package creation when a vulnerability at or above this severity is found (critical, high, medium, low, negligible or unknown), implies --scan-vulnerabilities"
+	CmdPackageCreateErrEncryptOCI             = "encrypted packages can only be created as tarballs, not published to an OCI registry"
+	CmdPackageCreateErrScanSkipSBOM           = "vulnerability scanning needs the SBOMs of the package and cannot be used with --skip-sbom"
+	CmdPackageCreateFlagSkipSbomComponents    = "Comma-separated list of components to leave out of SBOM generation, images that are shared with other components are still cataloged"
+	CmdPackageCreateFlagCompressSbom          = "Store the SBOMs zstd compressed in their own layer (sboms.tar.zst), older versions of Zarf will not find SBOMs stored this way"
+	CmdPackageCreateFlagAllPlatforms          = "Include every platform of images tagged with an image index, keeping the index digest, instead of only the image for the package architecture. Images pinned to the digest of an index always include every platform"
+	CmdPackageCreateFlagEstargz               = "Convert the gzip layers of images to eStargz so nodes with a lazy-pulling snapshotter can start containers before their layers are fully pulled from the Zarf registry. This changes image digests, so images referenced by digest or kept with their index are not converted"
+	CmdPackageCreateFlagRecompressZstd        = "Recompress the gzip layers of images with zstd to shrink the package and speed up decompression on deploy. This changes image digests, so images referenced by digest or kept with their index are not recompressed, and nodes need containerd 1.5+ to run them"
+	CmdPackageCreateFlagImagePolicy           = "Path to an organizational image policy file with glob patterns of allowed and denied registries and images and whether images must be pinned by digest. Every violation fails package create"
+	CmdPackageCreateFlagComponentConcurrency  = "Number of components to assemble at once, each in its own workspace. Components with create actions or plugins are always assembled on their own and in order"
+	CmdPackageCreateFlagIncludeReferrers      = "Include the cosign signatures, attestations and SBOMs and the OCI referrers attached to images in the package so they are pushed to the registry on deploy"
+	CmdPackageCreateFlagContainerdAddress     = "Containerd socket to load local images from when they are not found on a remote or in docker (defaults to trying /run/containerd/containerd.sock and /run/k3s/containerd/containerd.sock)"
+	CmdPackageCreateFlagContainerdNamespace   = "Containerd namespace to load local images from when they are not found on a remote or in docker (defaults to trying 'default' and 'k8s.io')"
+	CmdPackageCreateFlagMaxCacheSize          = "Specify the maximum size of the image layer cache in megabytes, the least recently used layers are pruned after pulling images. Use 0 to disable pruning."
+	CmdPackageCreateFlagEncrypt               = "Encrypt the package tarball for an age recipient (age1...), can be repeated. The tarball is written with an additional .age extension."
+	CmdPackageCreateFlagEncryptPassphrase     = "Encrypt the package tarball with a passphrase instead of age recipients. The tarball is written with an additional .age extension."
 	CmdPackageCreateFlagMaxPackageSize        = "Specify the maximum size of the package in megabytes, packages larger than this will be split into multiple parts to be loaded onto smaller media (i.e. DVDs). Use 0 to disable splitting."
 	CmdPackageCreateFlagSigningKey            = "Path to private key file for signing packages"
 	CmdPackageCreateFlagSigningKeyPassword    = "Password to the private key file used for signing packages"
 	CmdPackageCreateFlagDeprecatedKey         = "[Deprecated] Path to private key file for signing packages (use --signing-key instead)"
 	CmdPackageCreateFlagDeprecatedKeyPassword = "[Deprecated] Password to the private key file used for signing packages (use --signing-key-pass instead)"
 	CmdPackageCreateFlagDifferential          = "[beta] Build a package that only contains the differential changes from local resources and differing remote resources from the specified previously built package"
-	CmdPackageCreateFlagRegistryOverride      = "Specify a map of domains to override on package create when pulling images (e.g. --registry-override docker.io=dockerio-reg.enterprise.intranet)"
+	CmdPackageCreateFlagDifferentialFrom      = "[beta] Build a differential package against the specified published package (oci://), which also leaves out the image layers the published package has and can only be deployed where it is deployed"
+	CmdPackageCreateFlagRegistryOverride      = "Specify a map of domains to override on package create when pulling images (e.g. --registry-override docker.io=dockerio-reg.enterprise.intranet), overrides are tried before the registry_mirrors of the config file and the upstream registry"
+	CmdPackageCreateFlagRemoteBuilder         = "URL of a remote builder running 'zarf serve' to create the package on, the package directory is sent to it and the created package is saved to the output directory"
+	CmdPackageCreateFlagRemoteBuilderToken    = "Token to authenticate to the remote builder with"
+	CmdPackageCreateErrRemoteBuilderToken     = "a token is required to use a remote builder, set it with --remote-builder-token or ZARF_PACKAGE_CREATE_REMOTE_BUILDER_TOKEN"
 	CmdPackageCreateFlagFlavor                = "The flavor of components to include in the resulting package (i.e. have a matching or empty \"only.flavor\" key)"
 	CmdPackageCreateCleanPathErr              = "Invalid characters in Zarf cache path, defaulting to %s"
 
 	CmdPackageDeployFlagConfirm                        = "Confirms package deployment without prompting. ONLY use with packages you trust. Skips prompts to review SBOM, configure variables, select optional components and review potential breaking changes."
 	CmdPackageDeployFlagAdoptExistingResources         = "Adopts any pre-existing K8s resources into the Helm charts managed by Zarf. ONLY use when you have existing deployments you want Zarf to takeover."
+	CmdPackageDeployFlagAllowShared                    = "Allow the package to deploy to Helm releases and namespaces owned by another deployed package. ONLY use when the packages are meant to share them."
 	CmdPackageDeployFlagSet                            = "Specify deployment variables to set on the command line (KEY=value)"
 	CmdPackageDeployFlagComponents                     = "Comma-separated list of components to deploy.  Adding this flag will skip the prompts for selected components.  Globbing component names with '*' and deselecting 'default' components with a leading '-' are also supported."
 	CmdPackageDeployFlagShasum                         = "Shasum of the package to deploy. Required if deploying a remote package and \"--insecure\" is not provided"
+	CmdPackageDeployFlagFaultInject                    = "[Dev] Comma separated list of points to inject failures at while deploying (registry-push, helm-timeout, tunnel-drop), each optionally followed by ':<count>' or ':always' (e.g. registry-push:2,helm-timeout)"
+	CmdPackageDeployFlagAnswers                        = "Answers file written by a previous deployment with \"--export-answers\" to take the components and variable values from. Values set with \"--set\" and \"--components\" take precedence"
+	CmdPackageDeployFlagRetain                         = "Retain the archive of the deployed package on this host with its SHA256 checksum, to roll back to or audit the exact artifact that was deployed"
+	CmdPackageDeployFlagRetainDir                      = "Directory to retain the archives of deployed packages in, defaults to the deployed-packages directory of the Zarf cache"
+	CmdPackageDeployFlagRetainMax                      = "Number of the most recently deployed archives of each package to retain, older archives are deleted to reclaim disk (0 retains all)"
+	CmdPackageDeployFlagTrustRoot                      = "Path to the trust root a cluster without one is pinned to, required to deploy the first trust root to a cluster"
+	CmdPackageDeployFlagExportAnswers                  = "Write the selected components and the values of all variables set during this deployment to an answers file, to record how the package was deployed and to repeat the deployment with \"--answers\" (may contain sensitive values)"
+	CmdPackageDeployFlagHelmDebugDir                   = "Directory to write the rendered manifests and computed values of Helm charts that fail to render, to help debug them (may contain sensitive values)"
+	CmdPackageDeployFlagFromClusterCache               = "Deploy the package named by PACKAGE_SOURCE from the in-cluster package cache (requires the zarf-package-cache init component). Defaults to the deployed version, or specify one to roll back to as <name>:<version>"
 	CmdPackageDeployFlagSget                           = "[Deprecated] Path to public sget key file for remote packages signed via cosign. This flag will be removed in v1.0.0 please use the --key flag instead."
 	CmdPackageDeployFlagSkipWebhooks                   = "[alpha] Skip waiting for external webhooks to execute as each package component is deployed"
+	CmdPackageDeployFlagRequireAgent                   = "Fail instead of warning when the Zarf agent would not rewrite the image references of the package, as if the package set metadata.requireAgent"
 	CmdPackageDeployFlagTimeout                        = "Timeout for Helm operations such as installs and rollbacks"
+	CmdPackageDeployFlagSkip                           = "Comma-separated list of deploy phases to skip for every component (images, repos, artifacts, charts), to recover a partial deployment without repeating the phases that already succeeded. Charts also covers manifests"
 	CmdPackageDeployValidateArchitectureErr            = "this package architecture is %s, but the target cluster only has the %s architecture(s). These architectures must be compatible when \"images\" are present"
 	CmdPackageDeployValidateLastNonBreakingVersionWarn = "The version of this Zarf binary '%s' is less than the LastNonBreakingVersion of '%s'. You may need to upgrade your Zarf version to at least '%s' to deploy this package"
 	CmdPackageDeployInvalidCLIVersionWarn              = "CLIVersion is set to '%s' which can cause issues with package creation and deployment. To avoid such issues, please set the value to the valid semantic version for this version of Zarf."
@@ -288,20 +496,60 @@ $ zarf package mirror-resources <your-package.tar.zst> \
 	CmdPackageInspectFlagSbomOut    = "Specify an output directory for the SBOMs from the inspected Zarf package"
 	CmdPackageInspectFlagListImages = "List images in the package (prints to stdout)"
 
+	CmdPackageVerifyShort   = "Verifies the signature and provenance of a Zarf package"
+	CmdPackageVerifyLong    = "Verifies the signature of a Zarf package with the provided key, and with --provenance the SLSA provenance attached to a package published to a remote registry"
+	CmdPackageVerifyExample = `
+# Verify the signature of a package
+$ zarf package verify zarf-package-dos-games-amd64-1.0.0.tar.zst --key cosign.pub
+
+# Verify the signature and the SLSA provenance of a published package
+$ zarf package verify oci://ghcr.io/defenseunicorns/packages/dos-games:1.0.0 --key cosign.pub --provenance
+`
+	CmdPackageVerifyFlagProvenance = "Verify the SLSA provenance attached to the package, which is only attached to packages in remote registries"
+	CmdPackageVerifyErrProvenance  = "the provenance of a package can only be verified in a remote registry, %q is not prefixed with 'oci://'"
+	CmdPackageVerifySuccess        = "Verified %s"
+	CmdPackageVerifyProvenance     = "Verified the SLSA provenance of %s"
+
 	CmdPackageRemoveShort          = "Removes a Zarf package that has been deployed already (runs offline)"
 	CmdPackageRemoveFlagConfirm    = "REQUIRED. Confirm the removal action to prevent accidental deletions"
 	CmdPackageRemoveFlagComponents = "Comma-separated list of components to remove.  This list will be respected regardless of a component's 'required' or 'default' status.  Globbing component names with '*' and deselecting components with a leading '-' are also supported."
 
-	CmdPackagePublishShort   = "Publishes a Zarf package to a remote registry"
+	CmdPackagePublishShort = "Publishes a Zarf package to a remote registry"
+	CmdPackagePublishLong  = "Publishes a Zarf package to a remote registry.\n\n" +
+		"Pass several repositories to mirror the package to each of them concurrently. The digest of the package in each " +
+		"registry is printed once they are all done, and a failed registry does not stop the others. Registries are " +
+		"authenticated to with the credentials in the package.publish.credentials section of the config file, or the " +
+		"Docker credential store."
 	CmdPackagePublishExample = `
 # Publish a package to a remote registry
 $ zarf package publish my-package.tar oci://my-registry.com/my-namespace
 
+# Publish a package to several registries at once
+$ zarf package publish my-package.tar oci://my-registry.com/my-namespace oci://mirror.example.com/zarf
+
 # Publish a skeleton package to a remote registry
 $ zarf package publish ./path/to/dir oci://my-registry.com/my-namespace
 `
 	CmdPackagePublishFlagSigningKey         = "Path to a private key file for signing or re-signing packages with a new key"
 	CmdPackagePublishFlagSigningKeyPassword = "Password to the private key file used for publishing packages"
+	CmdPackagePublishFlagChunkSize          = "Specify the size in megabytes of the chunks layers larger than it are uploaded in, a failed chunk is resumed rather than restarting the layer. Use 0 to upload layers in one request."
+
+	CmdPackageCopyShort = "Copies a Zarf package from one remote registry to another"
+	CmdPackageCopyLong  = "Copies a Zarf package with all of its architectures, signatures and SBOMs from one remote registry to another, " +
+		"such as to promote a package from a staging to a production registry.\n\n" +
+		"The layers of the package are streamed between the registries without writing the package to disk, layers the " +
+		"destination already has are skipped, and layers are mounted rather than copied when both repositories are in " +
+		"the same registry. The package is tagged with the tag of the destination repository, or the tag of the source " +
+		"when the destination has none. The destination is authenticated to with the credentials in the " +
+		"package.publish.credentials section of the config file, or the Docker credential store."
+	CmdPackageCopyExample = `
+# Promote a package from a staging registry to a production registry
+$ zarf package copy oci://staging.example.com/zarf/dos-games:1.0.0 oci://registry.example.com/zarf/dos-games
+
+# Copy a package to another repository of the same registry under another tag
+$ zarf package copy oci://registry.example.com/staging/dos-games:1.0.0 oci://registry.example.com/production/dos-games:stable
+`
+	CmdPackageCopyErrOCI = "%q must be prefixed with 'oci://'"
 
 	CmdPackagePullShort   = "Pulls a Zarf package from a remote registry and save to the local file system"
 	CmdPackagePullExample = `
@@ -318,6 +566,9 @@ $ zarf package pull oci://ghcr.io/defenseunicorns/packages/dos-games:1.0.0 -a sk
 	CmdPackageChoose                = "Choose or type the package file"
 	CmdPackageClusterSourceFallback = "%q does not satisfy any current sources, assuming it is a package deployed to a cluster"
 	CmdPackageInvalidSource         = "Unable to identify source from %q: %s"
+	CmdPackageResolvedVersion       = "Resolved %s to %s"
+	CmdPackageFlagResolveOnly       = "Print the OCI package source pinned to the version selected by its semver range (e.g. :^1.2 or :latest-stable) and the digest of that version, for lockfiles, without deploying, pulling or inspecting it"
+	CmdPackageErrResolveOnly        = "--resolve-only requires an OCI package source (oci://)"
 
 	// zarf dev (prepare is an alias for dev)
 	CmdDevShort = "Commands useful for developing packages"
@@ -338,7 +589,8 @@ $ zarf package pull oci://ghcr.io/defenseunicorns/packages/dos-games:1.0.0 -a sk
 
 	CmdDevFindImagesShort = "Evaluates components in a Zarf file to identify images specified in their helm charts and manifests"
 	CmdDevFindImagesLong  = "Evaluates components in a Zarf file to identify images specified in their helm charts and manifests.\n\n" +
-		"Components that have repos that host helm charts can be processed by providing the --repo-chart-path."
+		"Components that have repos that host helm charts can be processed by providing the --repo-chart-path.\n\n" +
+		"With --from-cluster, the unique images running in the connected cluster are listed instead, pinned to the digests they run, to snapshot an existing environment into a package."
 
 	CmdDevGenerateConfigShort = "Generates a config file for Zarf"
 	CmdDevGenerateConfigLong  = "Generates a Zarf config file for controlling how the Zarf CLI operates. Optionally accepts a filename to write the config to.\n\n" +
@@ -346,17 +598,67 @@ $ zarf package pull oci://ghcr.io/defenseunicorns/packages/dos-games:1.0.0 -a sk
 		"Accepted extensions are json, toml, yaml.\n\n" +
 		"NOTE: This file must not already exist. If no filename is provided, the config will be written to the current working directory as zarf-config.toml."
 
-	CmdDevFlagExtractPath          = `The path inside of an archive to use to calculate the sha256sum (i.e. for use with "files.extractPath")`
-	CmdDevFlagSet                  = "Specify package variables to set on the command line (KEY=value). Note, if using a config file, this will be set by [package.create.set]."
-	CmdDevFlagRepoChartPath        = `If git repos hold helm charts, often found with gitops tools, specify the chart path, e.g. "/" or "/chart"`
-	CmdDevFlagGitAccount           = "User or organization name for the git account that the repos are created under."
-	CmdDevFlagKubeVersion          = "Override the default helm template KubeVersion when performing a package chart template"
-	CmdDevFlagFindImagesRegistry   = "Override the ###ZARF_REGISTRY### value"
-	CmdDevFlagFindImagesWhy        = "Prints the source manifest for the specified image"
-	CmdDevFlagFindImagesSkipCosign = "Skip searching for cosign artifacts related to discovered images"
+	CmdDevFlagExtractPath           = `The path inside of an archive to use to calculate the sha256sum (i.e. for use with "files.extractPath")`
+	CmdDevFlagSet                   = "Specify package variables to set on the command line (KEY=value). Note, if using a config file, this will be set by [package.create.set]."
+	CmdDevFlagRepoChartPath         = `If git repos hold helm charts, often found with gitops tools, specify the chart path, e.g. "/" or "/chart"`
+	CmdDevFlagGitAccount            = "User or organization name for the git account that the repos are created under."
+	CmdDevFlagKubeVersion           = "Override the default helm template KubeVersion when performing a package chart template"
+	CmdDevFlagFindImagesRegistry    = "Override the ###ZARF_REGISTRY### value"
+	CmdDevFlagFindImagesWhy         = "Prints the source manifest for the specified image"
+	CmdDevFlagFindImagesSkipCosign  = "Skip searching for cosign artifacts related to discovered images"
+	CmdDevFlagFindImagesUpdate      = "Add newly discovered images to the components in the zarf.yaml in place, pinned to their current digests. Possible images and cosign artifacts are only printed"
+	CmdDevFlagFindImagesFromCluster = "List the unique images running in the connected cluster, pinned to their digests, instead of the images of a package"
+	CmdDevFlagFindImagesNamespace   = "Namespaces to list the running images of with --from-cluster, defaults to every namespace"
+	CmdDevFindImagesErrFromCluster  = "--from-cluster lists the images of the cluster and can not be used with a package or --update"
+	CmdDevFindImagesErrNamespace    = "--namespace can only be used with --from-cluster"
+
+	CmdDevLockShort = "Resolves the images and skeleton imports of a Zarf package definition to digests and writes them to zarf-lock.yaml and zarf-compose-lock.yaml"
+	CmdDevLockLong  = "Resolves the tag of every image in a Zarf package definition to the digest it currently points to and writes them to a zarf-lock.yaml file beside the zarf.yaml.\n\n" +
+		"When a zarf-lock.yaml file is present, 'zarf package create' errors if an image is missing from it or if its tag no longer resolves to the locked digest. " +
+		"Images pinned by digest and images loaded from tarballs are not locked.\n\n" +
+		"The OCI skeleton packages components are imported from are resolved again, selecting the highest version that satisfies a semver range in place of their tag, " +
+		"and written with their digests to a zarf-compose-lock.yaml file. When it is present, 'zarf package create' composes the locked packages and errors if an import is missing from it."
 
 	CmdDevLintShort = "Lints the given package for valid schema and recommended practices"
-	CmdDevLintLong  = "Verifies the package schema, checks if any variables won't be evaluated, and checks for unpinned images/repos/files"
+	CmdDevLintLong  = "Verifies the package schema, checks if any variables won't be evaluated, and checks for unpinned images/repos/files.\n\n" +
+		"Findings are reported with their line and column in the zarf.yaml, the path of the failing key, the keyword of the schema it failed and a suggested fix where there is one. " +
+		"With --output json or sarif the findings are written to stdout for editors and CI systems to annotate."
+	CmdDevLintExample = `
+# Lint the package in the current directory
+$ zarf dev lint
+
+# Write the findings as SARIF, for example to upload to GitHub code scanning
+$ zarf dev lint --output sarif > zarf-lint.sarif
+`
+	CmdDevLintFlagOutput = "Output format (table|json|sarif). json and sarif write the findings to stdout"
+	CmdDevLintErrOutput  = "unsupported output format %q, use table, json or sarif"
+
+	CmdDevValidateShort = "Validates a zarf.yaml against the schema and for mistakes before a package is created"
+	CmdDevValidateLong  = "Validates a zarf.yaml, or the zarf.yaml in a directory, without pulling any images, charts or repos. " +
+		"In addition to the schema it checks the package rules that 'zarf package create' enforces, the syntax of image references, " +
+		"that local charts, values files, manifests, kustomizations, files and imports exist, and that the variables and constants the package references are declared.\n\n" +
+		"Imported components are validated when the package is created."
+	CmdDevValidateExample = `
+# Validate the zarf.yaml in the current directory
+$ zarf dev validate
+
+# Validate a zarf.yaml
+$ zarf dev validate packages/podinfo/zarf.yaml
+`
+	CmdDevValidateErr     = "%s is not valid"
+	CmdDevValidateSuccess = "%s is valid"
+
+	CmdDevSchemaShort = "Prints the zarf.yaml JSON schema for editors"
+	CmdDevSchemaLong  = "Prints the JSON schema of zarf.yaml for this version of Zarf, or downloads the schema of another released version, " +
+		"for editors to complete and validate package definitions as they are written."
+	CmdDevSchemaExample = `
+# Write the schema of this version of Zarf for an editor
+$ zarf dev schema > zarf.schema.json
+
+# Print the schema of a released version of Zarf
+$ zarf dev schema --version v0.36.1
+`
+	CmdDevSchemaFlagVersion = "The released version of Zarf to print the schema of (default: this version)"
 
 	// zarf tools
 	CmdToolsShort = "Collection of additional tools to make airgap easier"
@@ -482,13 +784,40 @@ zarf tools yq e '.a.b = "cool"' -i file.yaml
 	CmdToolsHelmShort = "Subset of the Helm CLI included with Zarf to help manage helm charts."
 	CmdToolsHelmLong  = "Subset of the Helm CLI that includes the repo and dependency commands for managing helm charts destined for the air gap."
 
-	CmdToolsClearCacheShort         = "Clears the configured git and image cache directory"
+	CmdToolsClearCacheShort = "Clears the configured git and image cache directory"
+	CmdToolsClearCacheLong  = "Clears the configured git and image cache directory.\n\n" +
+		"By default the entire cache is removed. Use --images, --oci or --repos to only clear the image layer cache, the remote component cache or the cache of git repositories, " +
+		"and --older-than or --max-size to only prune the least recently used entries."
 	CmdToolsClearCacheDir           = "Cache directory set to: %s"
 	CmdToolsClearCacheSuccess       = "Successfully cleared the cache from %s"
+	CmdToolsClearCacheSuccessType   = "Successfully cleared the %s cache from %s"
+	CmdToolsClearCachePruned        = "Successfully pruned %s from the %s cache in %s"
 	CmdToolsClearCacheFlagCachePath = "Specify the location of the Zarf artifact cache (images and git repositories)"
-
-	CmdToolsDownloadInitShort               = "Downloads the init package for the current Zarf version into the specified directory"
-	CmdToolsDownloadInitFlagOutputDirectory = "Specify a directory to place the init package in."
+	CmdToolsClearCacheFlagImages    = "Only clear the image layer cache"
+	CmdToolsClearCacheFlagOCI       = "Only clear the cache of components imported from OCI skeleton packages"
+	CmdToolsClearCacheFlagRepos     = "Only clear the cache of git repositories that components are imported from"
+	CmdToolsClearCacheFlagOlderThan = "Only remove cache entries that have not been used for longer than this duration (e.g. 720h)"
+	CmdToolsClearCacheFlagMaxSize   = "Remove the least recently used cache entries until the cache is at most this size in megabytes"
+
+	CmdToolsCacheShort = "Inspects and garbage collects the Zarf cache"
+	CmdToolsCacheLong  = "Inspects and garbage collects the Zarf cache.\n\n" +
+		"Image layers are stored once in the cache by their digest and hard linked into the packages that are being created, " +
+		"so packages built on the same base images share a single copy of their layers."
+	CmdToolsCacheGCShort = "Removes abandoned downloads and unused entries from the cache"
+	CmdToolsCacheGCLong  = "Removes image layer downloads that were interrupted more than an hour ago from the cache, " +
+		"then removes the entries that have not been used for longer than --older-than and the least recently used entries until the cache is at most --max-size."
+	CmdToolsCacheGCSuccess  = "Successfully freed %s from the cache in %s"
+	CmdToolsCacheStatsShort = "Shows the number of entries, size and last use of each cache"
+
+	CmdToolsDownloadInitShort = "Downloads the init package for the current Zarf version into the specified directory"
+	CmdToolsDownloadInitLong  = "Downloads the init package for the current Zarf version into the specified directory.\n\n" +
+		"By default the init package is pulled from the upstream Zarf OCI repository. " +
+		"Use --from to pull it from an OCI mirror (oci://) or an HTTP(S) mirror instead, optionally verified with --shasum."
+	CmdToolsDownloadInitFlagOutputDirectory  = "Specify a directory to place the init package in."
+	CmdToolsDownloadInitFlagFrom             = "Alternate location to download the init package from, either an OCI repository (oci://, the Zarf version is used as the tag if none is given) or an HTTP(S) URL"
+	CmdToolsDownloadInitFlagShasum           = "SHA256 checksum to verify the init package against (the manifest digest for OCI, the tarball checksum for HTTP(S))"
+	CmdToolsDownloadInitFromMirror           = "Downloading the init package from %s"
+	CmdToolsDownloadInitErrShasumWithoutFrom = "--shasum can only be used together with --from"
 
 	CmdToolsGenPkiShort       = "Generates a Certificate Authority and PKI chain of trust for the given host"
 	CmdToolsGenPkiSuccess     = "Successfully created a chain of trust for %s"
@@ -504,9 +833,47 @@ zarf tools yq e '.a.b = "cool"' -i file.yaml
 
 	CmdToolsSbomShort = "Generates a Software Bill of Materials (SBOM) for the given package"
 
+	CmdToolsTrustRootShort = "Creates, signs and verifies trust roots of the keys that sign packages"
+	CmdToolsTrustRootLong  = "A trust root lists the public keys that sign packages and the root keys that sign the trust root itself. " +
+		"Distributed in the zarf-trust-root component of an init package, it lets 'zarf package deploy' verify signed packages without --key. " +
+		"A new version of the trust root, e.g. one that rotates keys, is only trusted by a cluster when a threshold of the root keys of the version it trusts signed it."
+	CmdToolsTrustRootCreateShort   = "Creates an unsigned trust root of root keys and package signing keys"
+	CmdToolsTrustRootCreateExample = `
+# Create the first version of a trust root and sign it with both root keys:
+$ zarf tools trust-root create root.json --root-key root-a.pub --root-key root-b.pub --threshold 2 --package-key cosign.pub
+$ zarf tools trust-root sign root.json --key root-a.key
+$ zarf tools trust-root sign root.json --key root-b.key
+
+# Rotate root key root-a to root-c in version 2, signed by the root keys of version 1 and version 2:
+$ zarf tools trust-root create root-v2.json --version 2 --root-key root-b.pub --root-key root-c.pub --threshold 2 --package-key cosign.pub
+$ zarf tools trust-root sign root-v2.json --key root-a.key --trusted root.json
+$ zarf tools trust-root sign root-v2.json --key root-b.key
+$ zarf tools trust-root sign root-v2.json --key root-c.key
+$ zarf tools trust-root verify root-v2.json --trusted root.json
+`
+	CmdToolsTrustRootCreateFlagRootKey    = "Path to the PEM encoded public key of a root key that signs the trust root, can be repeated"
+	CmdToolsTrustRootCreateFlagPackageKey = "Path to the PEM encoded public key of a key that signs packages, can be repeated"
+	CmdToolsTrustRootCreateFlagThreshold  = "Number of root keys that have to sign the trust root"
+	CmdToolsTrustRootCreateFlagVersion    = "Version of the trust root, every rotation must increase it"
+	CmdToolsTrustRootCreateFlagExpires    = "Time until the trust root expires, packages can't be verified with an expired trust root"
+	CmdToolsTrustRootCreated              = "Created version %d of the trust root at %s, sign it with %d root keys"
+	CmdToolsTrustRootSignShort            = "Signs a trust root with a cosign private key"
+	CmdToolsTrustRootSignFlagKey          = "Path to the cosign private key of a root key to sign the trust root with"
+	CmdToolsTrustRootSignFlagKeyPass      = "Password of the private key"
+	CmdToolsTrustRootSignFlagTrusted      = "Path to the trusted trust root the trust root rotates, to sign with one of its root keys"
+	CmdToolsTrustRootSigned               = "Signed the trust root at %s with key %s"
+	CmdToolsTrustRootVerifyShort          = "Verifies that a trust root is signed by its root keys, or can replace the trusted trust root"
+	CmdToolsTrustRootVerifyFlagTrusted    = "Path to the trusted trust root to verify that the trust root can replace"
+	CmdToolsTrustRootVerified             = "Version %d of the trust root is signed by its root keys and valid until %s"
+	CmdToolsTrustRootVerifiedUpdate       = "Version %d of the trust root can replace the trusted version %d"
+
 	CmdToolsWaitForShort = "Waits for a given Kubernetes resource to be ready"
 	CmdToolsWaitForLong  = "By default Zarf will wait for all Kubernetes resources to be ready before completion of a component during a deployment.\n" +
 		"This command can be used to wait for a Kubernetes resources to exist and be ready that may be created by a Gitops tool or a Kubernetes operator.\n" +
+		"Resources are watched until they meet the condition: exists (the default), current (fully reconciled as computed by kstatus), " +
+		"the type of a status condition optionally followed by =<status>, or a JSONPath and its value. " +
+		"The --expression flag additionally requires a CEL expression over the resource to be true, " +
+		"with its apiVersion, kind, metadata, spec, status and data fields as variables and the whole resource as object.\n" +
 		"You can also wait for arbitrary network endpoints using REST or TCP checks.\n\n"
 	CmdToolsWaitForExample = `
 # Wait for Kubernetes resources:
@@ -518,6 +885,8 @@ $ zarf tools wait-for svc zarf-docker-registry exists -n zarf           #  wait
 $ zarf tools wait-for svc zarf-docker-registry -n zarf                  #  same as above, except exists is the default condition
 $ zarf tools wait-for crd addons.k3s.cattle.io                          #  wait for crd addons.k3s.cattle.io to exist
 $ zarf tools wait-for sts test-sts '{.status.availableReplicas}'=23     #  wait for statefulset test-sts to have 23 available replicas
+$ zarf tools wait-for deployment podinfo current -n podinfo             #  wait for deployment podinfo in namespace podinfo to be fully reconciled
+$ zarf tools wait-for deploy podinfo --expression 'status.readyReplicas == spec.replicas'  #  wait for all replicas of deployment podinfo to be ready
 
 # Wait for network endpoints:
 $ zarf tools wait-for http localhost:8080 200                           #  wait for a 200 response from http://localhost:8080
@@ -526,8 +895,10 @@ $ zarf tools wait-for https 1.1.1.1 200                                 #  wait
 $ zarf tools wait-for http google.com                                   #  wait for any 2xx response from http://google.com
 $ zarf tools wait-for http google.com success                           #  wait for any 2xx response from http://google.com
 `
-	CmdToolsWaitForFlagTimeout   = "Specify the timeout duration for the wait command."
-	CmdToolsWaitForFlagNamespace = "Specify the namespace of the resources to wait for."
+	CmdToolsWaitForFlagTimeout          = "Specify the timeout duration for the wait command."
+	CmdToolsWaitForFlagNamespace        = "Specify the namespace of the resources to wait for."
+	CmdToolsWaitForFlagExpression       = "A CEL expression over the resources that must evaluate to true, such as 'status.readyReplicas == spec.replicas'."
+	CmdToolsWaitForErrExpressionNetwork = "the --expression flag is only supported when waiting for Kubernetes resources"
 
 	CmdToolsKubectlDocs = "Kubectl command. See https://kubernetes.io/docs/reference/kubectl/overview/ for more information."
 
@@ -543,7 +914,13 @@ $ zarf tools get-creds registry-readonly
 $ zarf tools get-creds git
 $ zarf tools get-creds git-readonly
 $ zarf tools get-creds artifact
+
+# Export Zarf credentials as ready to use auth files:
+$ zarf tools get-creds --format dockerconfig > config.json
+$ zarf tools get-creds git --format netrc >> ~/.netrc
+$ eval "$(zarf tools get-creds --format env)"
 `
+	CmdToolsGetCredsFlagFormat = "Output the credentials as a ready to use auth file instead of a table, valid formats are: dockerconfig, netrc, env"
 
 	CmdToolsUpdateCredsShort   = "Updates the credentials for deployed Zarf services. Pass a service key to update credentials for a single service"
 	CmdToolsUpdateCredsLong    = "Updates the credentials for deployed Zarf services. Pass a service key to update credentials for a single service. i.e. 'zarf tools update-creds registry'"
@@ -571,9 +948,17 @@ $ zarf tools update-creds registry --registry-push-username={USERNAME} --registr
 $ zarf tools update-creds git --git-push-username={USERNAME} --git-push-password={PASSWORD}
 $ zarf tools update-creds artifact --artifact-push-username={USERNAME} --artifact-push-token={PASSWORD}
 
+# Review every change that would be made without prompting or applying it:
+$ zarf tools update-creds --dry-run > update-creds.diff
+
 # NOTE: Not specifying a pull username/password will keep the previous pull username/password.
 `
 	CmdToolsUpdateCredsConfirmFlag          = "Confirm updating credentials without prompting"
+	CmdToolsUpdateCredsDryRunFlag           = "Print the changes that would be made to the Zarf state, secrets and Helm release values (prints to stdout) without prompting or applying them"
+	CmdToolsUpdateCredsDryRunState          = "# Zarf state (%s/%s)"
+	CmdToolsUpdateCredsDryRunSecrets        = "# Zarf-managed secrets"
+	CmdToolsUpdateCredsDryRunServices       = "# Helm release values and services"
+	CmdToolsUpdateCredsDryRunNoChanges      = "No changes"
 	CmdToolsUpdateCredsConfirmProvided      = "Confirm flag specified, continuing without prompting."
 	CmdToolsUpdateCredsConfirmContinue      = "Continue with these changes?"
 	CmdToolsUpdateCredsUnableCreateToken    = "Unable to create the new Gitea artifact token: %s"
@@ -582,6 +967,143 @@ $ zarf tools update-creds artifact --artifact-push-username={USERNAME} --artifac
 	CmdToolsUpdateCredsUnableUpdateAgent    = "Unable to update Zarf Agent TLS secrets: %s"
 	CmdToolsUpdateCredsUnableUpdateCreds    = "Unable to update Zarf credentials"
 
+	CmdToolsStateShort       = "Commands for inspecting, backing up and restoring the Zarf state stored in the cluster"
+	CmdToolsStateVerifyShort = "Verifies the integrity of the Zarf state and the Zarf-managed secrets"
+	CmdToolsStateVerifyLong  = "Verifies that the signature of the Zarf state matches its contents and that the Zarf-managed image pull and git secrets in every namespace match the Zarf state.\n\n" +
+		"The Zarf state is signed with a key stored in the zarf/zarf-state-signing-key secret whenever Zarf saves it. Anyone who can change the Zarf state can usually read that secret and sign their changes, so this signature only detects accidental corruption.\n\n" +
+		"To detect changes made by other cluster users, keep a key file of at least 32 bytes off the cluster and pass it with --state-key (or state_key in the Zarf config) to every Zarf command that changes the state. " +
+		"The state is then also signed with that key, and verifying with it fails when the state was changed by anyone who does not hold it, including Zarf components running in the cluster that rotate credentials."
+	CmdToolsStateVerifyExample = `
+# Verify the Zarf state and the Zarf-managed secrets:
+$ zarf tools state verify
+
+# Accept changes made to the Zarf state outside of Zarf after reviewing it with kubectl get zarfstate zarf-state -n zarf -o yaml:
+$ zarf tools state verify --sign
+
+# Sign and verify the Zarf state with an operator-held key:
+$ zarf tools state verify --sign --state-key ~/.zarf/state.key
+$ zarf tools state verify --state-key ~/.zarf/state.key
+`
+	CmdToolsStateVerifyFlagSign       = "Sign the current contents of the Zarf state before verifying, accepting any changes made to it outside of Zarf"
+	CmdToolsStateVerifySigned         = "Signed the Zarf state %s/%s"
+	CmdToolsStateVerifyStateValid     = "The signature of the Zarf state %s/%s is valid"
+	CmdToolsStateVerifyNoStateKey     = "No --state-key was given, only accidental corruption of the Zarf state is detected"
+	CmdToolsStateVerifyErrState       = "unable to verify the Zarf state %s/%s: %w"
+	CmdToolsStateVerifySecretMismatch = "The Zarf-managed secret %s/%s does not match the Zarf state"
+	CmdToolsStateVerifyErrSecrets     = "%d Zarf-managed secrets do not match the Zarf state, run 'zarf tools update-creds' to restore them"
+	CmdToolsStateVerifySecretsValid   = "All Zarf-managed secrets match the Zarf state"
+
+	CmdToolsStateBackupShort = "Backs up the Zarf state, deployed packages and Zarf-managed secrets to an encrypted file"
+	CmdToolsStateBackupLong  = "Exports the Zarf state with its credentials, the secrets that record the packages deployed to the cluster and the Zarf-managed image pull and git secrets of every namespace to a file. " +
+		"The file is encrypted with a passphrase, which is prompted for when --passphrase is not set. Restore it into a rebuilt cluster with 'zarf tools state restore'."
+	CmdToolsStateBackupExample = `
+# Back up the Zarf control data of the cluster:
+$ zarf tools state backup zarf-state.backup
+
+# Back up without a prompt, e.g. from a scheduled job:
+$ zarf tools state backup zarf-state.backup --passphrase "$ZARF_BACKUP_PASSPHRASE"
+`
+	CmdToolsStateBackupSuccess = "Backed up the Zarf state, %[2]d deployed packages and %[3]d Zarf-managed secrets to %[1]s"
+
+	CmdToolsStateRestoreShort = "Restores the Zarf state, deployed packages and Zarf-managed secrets from an encrypted backup"
+	CmdToolsStateRestoreLong  = "Restores a backup made with 'zarf tools state backup' into the cluster, replacing its Zarf state and the records of its deployed packages. " +
+		"The Zarf namespace is created if needed. Zarf-managed secrets are only restored to namespaces that exist, the rest are created when packages are deployed to them."
+	CmdToolsStateRestoreExample = `
+# Restore the Zarf control data into a rebuilt cluster:
+$ zarf tools state restore zarf-state.backup
+`
+	CmdToolsStateRestoreSummary     = "The backup was made at %s and holds the Zarf state, %d deployed packages and %d Zarf-managed secrets"
+	CmdToolsStateRestoreConfirm     = "Replace the Zarf state of the cluster with the backup?"
+	CmdToolsStateRestoreFlagConfirm = "Confirm the restore without prompting"
+	CmdToolsStateRestoreSuccess     = "Restored the Zarf state from %s"
+
+	CmdToolsStateFlagPassphrase        = "Passphrase the backup is encrypted with, prompted for when not set"
+	CmdToolsStatePassphrasePrompt      = "Backup passphrase: "
+	CmdToolsStatePassphrasePromptAgain = "Backup passphrase again: "
+	CmdToolsStateErrPassphrase         = "unable to get the backup passphrase: %w"
+	CmdToolsStateErrPassphraseEmpty    = "a passphrase is required to encrypt or decrypt the backup"
+	CmdToolsStateErrPassphraseMismatch = "the passphrases do not match"
+
+	CmdToolsHealthcheckShort = "Checks whether a cluster is ready for Zarf before running init or deploying packages"
+	CmdToolsHealthcheckLong  = "Checks the Kubernetes version skew between the API server and Zarf, the storage classes, the architectures of the nodes, the disk space of the nodes for the injector, pod security restrictions and the reachability of admission webhooks. Nothing is created or changed in the cluster.\n\n" +
+		"Each check passes, warns or fails. The command fails when any check fails. Checks that can not be completed, e.g. due to RBAC, warn.\n\n" +
+		"With --host, checks whether this host is ready to install the K3s bundled with the init package instead: the kernel modules, cgroups, SELinux, AppArmor, the free space of the paths K3s writes to and the ports it listens on. " +
+		"These checks also run before the k3s component is deployed by zarf init, and give a remediation for every problem they find."
+	CmdToolsHealthcheckExample = `
+# Check whether the current cluster is ready for zarf init:
+$ zarf tools healthcheck
+
+# Check the cluster for an arm64 package with a large seed registry image and print the results as JSON:
+$ zarf tools healthcheck -a arm64 --injector-disk 1Gi -o json
+
+# Check whether this host is ready for zarf init --components k3s:
+$ zarf tools healthcheck --host
+`
+	CmdToolsHealthcheckFlagNamespaces   = "Namespaces to check the pod security admission level of"
+	CmdToolsHealthcheckFlagInjectorDisk = "Ephemeral storage a node needs to run the injector during init"
+	CmdToolsHealthcheckFlagOutput       = "Output format (text|json)"
+	CmdToolsHealthcheckFlagHost         = "Check whether this host is ready to install the K3s bundled with the init package instead of checking a cluster"
+	CmdToolsHealthcheckErrOutput        = "unsupported output format %q, use text or json"
+	CmdToolsHealthcheckErrInjectorDisk  = "unable to parse the injector disk %q: %w"
+	CmdToolsHealthcheckErrFailed        = "%d cluster health checks failed"
+	CmdToolsHealthcheckErrHostFailed    = "%d host preflight checks failed"
+
+	CmdToolsAgentShort         = "Commands for working with the Zarf agent"
+	CmdToolsAgentSimulateShort = "Shows how the Zarf agent would mutate Kubernetes manifests without deploying them"
+	CmdToolsAgentSimulateLong  = "Runs the mutations of the Zarf agent locally against Kubernetes manifests using the Zarf state and namespaces of the current cluster, and shows the fields the agent would rewrite. Nothing is created or changed in the cluster.\n\n" +
+		"Workloads are simulated by mutating their pod template the way the agent mutates the pods they create. Objects that the agent would leave as they are show the reason they are skipped."
+	CmdToolsAgentSimulateExample = `
+# Show how the Zarf agent would mutate a deployment:
+$ zarf tools agent simulate -f deployment.yaml
+
+# Simulate the manifests rendered by a Helm chart in the podinfo namespace:
+$ helm template podinfo ./chart | zarf tools agent simulate -f - -n podinfo
+`
+	CmdToolsAgentSimulateFlagFilename  = "Manifest files to simulate, use - to read from stdin"
+	CmdToolsAgentSimulateFlagNamespace = "Namespace of the objects in the manifests that do not set one"
+	CmdToolsAgentSimulateMutated       = "~ %s %s/%s (%s)"
+	CmdToolsAgentSimulateUnchanged     = "= %s %s/%s (%s): no changes"
+	CmdToolsAgentSimulateSkipped       = "= %s %s/%s: %s"
+	CmdToolsAgentSimulateDenied        = "! %s %s/%s (%s): denied by the agent: %s"
+	CmdToolsAgentSimulateErrDenied     = "the agent would deny %d of the objects"
+
+	// zarf serve
+	CmdServeShort = "Runs a remote builder that creates packages for 'zarf package create --remote-builder'"
+	CmdServeLong  = "Runs a remote builder on this host that creates packages sent to it by 'zarf package create --remote-builder', so packages can be authored on machines that cannot build them.\n\n" +
+		"The package directory is streamed to the builder, which pulls the images and other resources of the package and streams the created package back. " +
+		"Clients authenticate with a token, which is generated and printed on start when one is not set. Packages are signed with the signing key of the builder.\n\n" +
+		"A builder token grants both code execution on the builder, as the onCreate actions of the package run there, and, with --signing-key, " +
+		"the signature of the builder on any package. Only hand it to those trusted with both. A signing key requires TLS so the token is not sent in the clear."
+	CmdServeExample = `
+# Run a remote builder reachable from other hosts
+$ ZARF_SERVE_TOKEN=<token> zarf serve --address 0.0.0.0:8090 --tls-cert cert.pem --tls-key key.pem
+
+# Create the package in the current directory on the remote builder
+$ ZARF_PACKAGE_CREATE_REMOTE_BUILDER_TOKEN=<token> zarf package create --remote-builder https://builder.example.com:8090
+`
+	CmdServeFlagAddress            = "Address the remote builder listens on"
+	CmdServeFlagToken              = "Token clients must present to create packages, generated when empty"
+	CmdServeFlagTLSCert            = "Path to the TLS certificate to serve the remote builder with"
+	CmdServeFlagTLSKey             = "Path to the key of the TLS certificate to serve the remote builder with"
+	CmdServeFlagSigningKey         = "Path to the private key file packages created by the remote builder are signed with"
+	CmdServeFlagSigningKeyPassword = "Password to the private key file packages created by the remote builder are signed with"
+	CmdServeFlagImagePolicy        = "Path to an organizational image policy file that every package created by the builder must satisfy, the image policy of clients is not used"
+	CmdServeFlagMaxUploadSize      = "Largest package directory, in megabytes, that the remote builder accepts"
+	CmdServeErrTLS                 = "both --tls-cert and --tls-key must be set to serve with TLS"
+	CmdServeErrSigningKeyTLS       = "--signing-key requires --tls-cert and --tls-key, the builder token must not be sent in the clear to a builder that signs packages"
+	CmdServeGeneratedToken         = "Generated the remote builder token: %s"
+	CmdServeServing                = "Serving the remote builder at %s://%s"
+	CmdServeExit                   = "Press Ctrl+C to stop the remote builder"
+
+	// zarf docs
+	CmdDocsShort = "Serves the CLI reference and zarf.yaml schema documentation for this version of Zarf"
+	CmdDocsLong  = "Serves the CLI reference and zarf.yaml schema documentation embedded in this Zarf binary on a local web server.\n\n" +
+		"The documentation always matches the running binary and does not require access to the internet, making it suitable for air-gapped environments."
+	CmdDocsFlagServe   = "Start a local web server hosting the documentation"
+	CmdDocsFlagAddress = "Address the documentation web server listens on"
+	CmdDocsServing     = "Serving the Zarf documentation at http://%s"
+	CmdDocsExit        = "Press Ctrl+C to stop serving the documentation"
+
 	// zarf version
 	CmdVersionShort = "Shows the version of the running Zarf binary"
 	CmdVersionLong  = "Displays the version of the Zarf release that the current binary was built from."
@@ -618,6 +1140,53 @@ const (
 const (
 	PkgCreateErrDifferentialSameVersion = "unable to create differential package. Please ensure the differential package version and reference package version are not the same. The package version must be incremented"
 	PkgCreateErrDifferentialNoVersion   = "unable to create differential package. Please ensure both package versions are set"
+	PkgCreateErrDifferentialFromOCI     = "unable to create differential package. %q must be a published package prefixed with 'oci://'"
+	PkgCreateErrImagePolicy             = "%d image policy violation(s) found"
+	PkgCreateErrImageLockMissing        = "%d image(s) missing from %s, run 'zarf dev lock' to update it: %s"
+	PkgCreateErrComposeLockMissing      = "the skeleton package %s is missing from %s, run 'zarf dev lock' to update it"
+	PkgCreateErrSetFile                 = "unable to read the file of the package template %s: %w"
+	PkgCreateErrSetFileConflict         = "the package template %s is set by both --set and --set-file"
+	PkgCreateResolvedSkeletonVersion    = "Resolved the skeleton package %s to %s"
+	PkgCreateErrImageSignature          = "unable to verify the cosign signature of image %s (%s): %w"
+)
+
+// Package deploy
+const (
+	PkgDeployErrAnswers             = "unable to read the answers file %s: %w"
+	PkgDeployWarnAnswersPackage     = "The answers file %s was exported from a deployment of package %q, not %q"
+	PkgDeployWarnAnswersSensitive   = "The answers file %s contains the values of sensitive variables, store it securely"
+	PkgDeploySuccessAnswersExported = "Exported the answers of this deployment to %s"
+	PkgDeployErrOwnership           = "the package would take over resources of other deployed packages (%s), deploy with --allow-shared if the packages are meant to share them"
+	PkgDeployWarnShared             = "The package shares resources with other deployed packages: %s"
+	PkgDeployErrAgentUnhealthy      = "the Zarf agent is required to rewrite the image references of this package: %w"
+	PkgDeployWarnAgentUnhealthy     = "The Zarf agent may not rewrite the image references of this package, its pods could pull from the original registries instead of the Zarf registry: %s"
+	PkgDeployErrDifferentialBase    = "this differential package requires version %s of %q to be deployed first"
+	PkgDeployErrDifferentialVersion = "this differential package requires version %s of %q to be deployed first, version %s is deployed"
+	PkgDeployErrSkipPhase           = "unable to skip the %q deploy phase, only %s can be skipped"
+	PkgDeployWarnSkipPhases         = "Skipping the %s phases of every component, the package will only be partially deployed"
+	PkgDeploySkippedPhase           = "Skipped the %s phase of component %q"
+	PkgDeployWarnImageMap           = "Unable to record the digests of the pushed images in the image map of the package: %s"
+	PkgDeployHostPreflight          = "Checking whether this host is ready for K3s"
+	PkgDeployWarnSkipHostPreflight  = "Skipping the host preflight checks, the K3s install may fail on this host"
+	PkgDeployErrHostPreflight       = "%d host preflight checks failed, fix the problems reported above or skip the checks with --skip-preflight"
+	PkgDeployErrTrustRoot           = "unable to validate the package signature with the trust root of the cluster, distribute a new trust root in an init package or provide a key with --key: %w"
+	PkgDeployTrustRoot              = "Updating the trust root of the package signing keys"
+	PkgDeployTrustRootUpdated       = "The cluster trusts version %d of the trust root with %d package signing keys"
+	PkgDeployErrTrustRootFile       = "the %s component must have exactly one file, the trust root"
+	PkgDeployRetaining              = "Retaining the archive of %s"
+	PkgDeployRetained               = "Retained the archive of %s at %s (sha256:%s)"
+	PkgDeployErrRetain              = "Unable to retain the archive of %s on this host: %s"
+	PkgPluginsTitle                 = "Plugins"
+	PkgPluginsHelp                  = "executables from the PATH that the package runs with your permissions"
+	PkgPluginsPrompt                = "Run these plugins?"
+	PkgPluginsErrNotAllowed         = "the package runs the plugins %s, review them with 'zarf package inspect' and allow them with --allow-plugins"
+	PkgPluginsErrDeclined           = "the plugins of the package were not allowed to run"
+)
+
+// Package find images
+const (
+	PkgFindClusterImages      = "Listing the images running in the cluster"
+	PkgFindClusterImagesFound = "Found %d images running in the cluster"
 )
 
 // Collection of reusable error messages.
@@ -629,5 +1198,6 @@ var (
 
 // Collection of reusable warn messages.
 var (
-	WarnSGetDeprecation = "Using sget to download resources is being deprecated and will removed in the v1.0.0 release of Zarf. Please publish the packages as OCI artifacts instead."
+	WarnFaultInjectEnabled = "Fault injection is enabled (%s), failures will be simulated during this deployment"
+	WarnSGetDeprecation    = "Using sget to download resources is being deprecated and will removed in the v1.0.0 release of Zarf. Please publish the packages as OCI artifacts instead."
 )
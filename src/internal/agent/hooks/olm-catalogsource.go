@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// catalogSource is the subset of an OLM CatalogSource the agent mutates.
+type catalogSource struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Image   string   `json:"image,omitempty"`
+		Secrets []string `json:"secrets,omitempty"`
+	} `json:"spec"`
+}
+
+// NewCatalogSourceMutationHook creates a new instance of the OLM catalog source mutation hook.
+func NewCatalogSourceMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCatalogSource(ctx, r, cluster)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCatalogSource(ctx, r, cluster)
+		},
+	}
+}
+
+// mutateCatalogSource mutates the image of a grpc catalog source to point to the registry defined in the ZarfState.
+func mutateCatalogSource(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+	src := catalogSource{}
+	if err := json.Unmarshal(r.Object.Raw, &src); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	// Catalog sources backed by a configmap or an address do not pull an image
+	if src.Spec.Image == "" || (src.Labels != nil && src.Labels["zarf-agent"] == "patched") {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	zarfState, err := cluster.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catalogs referenced on a mirror were packaged under their upstream name
+	image, err := transform.UpstreamImageRef(src.Spec.Image, zarfState.RegistryMirrors)
+	if err != nil {
+		return nil, fmt.Errorf("unable to transform the catalog source image: %w", err)
+	}
+	patchedImage, err := transform.ImageTransformHost(zarfState.RegistryInfo.Address, image)
+	if err != nil {
+		return nil, fmt.Errorf("unable to transform the catalog source image: %w", err)
+	}
+
+	message.Debugf("original catalog source image of (%s) got mutated to (%s)", src.Spec.Image, patchedImage)
+
+	secrets := src.Spec.Secrets
+	if !slices.Contains(secrets, config.ZarfImagePullSecretName) {
+		secrets = append(secrets, config.ZarfImagePullSecretName)
+	}
+
+	patches := []operations.PatchOperation{
+		operations.ReplacePatchOperation("/spec/image", patchedImage),
+		operations.AddPatchOperation("/spec/secrets", secrets),
+		getLabelPatch(src.Labels),
+	}
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createCatalogSourceAdmissionRequest(t *testing.T, op v1.Operation, catalogSource map[string]any) *v1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(catalogSource)
+	require.NoError(t, err)
+	return &v1.AdmissionRequest{
+		Operation: op,
+		Object: runtime.RawExtension{
+			Raw: raw,
+		},
+	}
+}
+
+func TestCatalogSourceMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []admissionTest{
+		{
+			name: "should not mutate when agent patched",
+			admissionReq: createCatalogSourceAdmissionRequest(t, v1.Update, map[string]any{
+				"metadata": map[string]any{
+					"name":   "already-patched",
+					"labels": map[string]string{"zarf-agent": "patched"},
+				},
+				"spec": map[string]any{
+					"sourceType": "grpc",
+					"image":      "quay.io/operatorhubio/catalog:latest",
+				},
+			}),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "should not mutate without an image",
+			admissionReq: createCatalogSourceAdmissionRequest(t, v1.Create, map[string]any{
+				"metadata": map[string]any{"name": "address"},
+				"spec": map[string]any{
+					"sourceType": "grpc",
+					"address":    "catalog.operators.svc:50051",
+				},
+			}),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "bad image",
+			admissionReq: createCatalogSourceAdmissionRequest(t, v1.Create, map[string]any{
+				"metadata": map[string]any{"name": "bad-image"},
+				"spec": map[string]any{
+					"sourceType": "grpc",
+					"image":      "quay.io/operatorhubio/catalog:-bad",
+				},
+			}),
+			errContains: "unable to transform the catalog source image",
+			code:        http.StatusInternalServerError,
+		},
+		{
+			name: "should be mutated",
+			admissionReq: createCatalogSourceAdmissionRequest(t, v1.Create, map[string]any{
+				"metadata": map[string]any{
+					"name":   "mutate-this",
+					"labels": map[string]string{"app": "catalog"},
+				},
+				"spec": map[string]any{
+					"sourceType": "grpc",
+					"image":      "quay.io/operatorhubio/catalog:latest",
+					"secrets":    []string{"upstream-pull-secret"},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/image",
+					"127.0.0.1:31999/operatorhubio/catalog:latest-zarf-1453264",
+				),
+				operations.AddPatchOperation(
+					"/spec/secrets",
+					[]string{"upstream-pull-secret", config.ZarfImagePullSecretName},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"app":        "catalog",
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should be mutated from a registry mirror",
+			admissionReq: createCatalogSourceAdmissionRequest(t, v1.Update, map[string]any{
+				"metadata": map[string]any{"name": "mirrored"},
+				"spec": map[string]any{
+					"sourceType": "grpc",
+					"image":      "mirror.example.com/dockerhub/operatorhubio/catalog:latest",
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/image",
+					"127.0.0.1:31999/operatorhubio/catalog:latest-zarf-2964936943",
+				),
+				operations.AddPatchOperation(
+					"/spec/secrets",
+					[]string{config.ZarfImagePullSecretName},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	ctx := context.Background()
+	state := &types.ZarfState{
+		RegistryInfo:    types.RegistryInfo{Address: "127.0.0.1:31999"},
+		RegistryMirrors: map[string][]string{"docker.io": {"mirror.example.com/dockerhub"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := createTestClientWithZarfState(ctx, t, state)
+			handler := admission.NewHandler().Serve(NewCatalogSourceMutationHook(ctx, c))
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
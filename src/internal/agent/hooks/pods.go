@@ -64,6 +64,14 @@ func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Clu
 		return nil, err
 	}
 	registryURL := state.RegistryInfo.Address
+	transformImage := func(image string) (string, error) {
+		// Images referenced on a mirror were packaged under their upstream name
+		image, err := transform.UpstreamImageRef(image, state.RegistryMirrors)
+		if err != nil {
+			return "", err
+		}
+		return transform.ImageTransformHost(registryURL, image)
+	}
 
 	var patches []operations.PatchOperation
 
@@ -79,7 +87,7 @@ func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Clu
 	// update the image host for each init container
 	for idx, container := range pod.Spec.InitContainers {
 		path := fmt.Sprintf("/spec/initContainers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+		replacement, err := transformImage(container.Image)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +98,7 @@ func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Clu
 	// update the image host for each ephemeral container
 	for idx, container := range pod.Spec.EphemeralContainers {
 		path := fmt.Sprintf("/spec/ephemeralContainers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+		replacement, err := transformImage(container.Image)
 		if err != nil {
 			return nil, err
 		}
@@ -101,7 +109,7 @@ func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Clu
 	// update the image host for each normal container
 	for idx, container := range pod.Spec.Containers {
 		path := fmt.Sprintf("/spec/containers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+		replacement, err := transformImage(container.Image)
 		if err != nil {
 			return nil, err
 		}
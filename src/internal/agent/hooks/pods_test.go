@@ -37,11 +37,45 @@ func TestPodMutationWebhook(t *testing.T) {
 
 	ctx := context.Background()
 
-	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+	state := &types.ZarfState{
+		RegistryInfo:    types.RegistryInfo{Address: "127.0.0.1:31999"},
+		RegistryMirrors: map[string][]string{"docker.io": {"mirror.example.com/dockerhub"}},
+	}
 	c := createTestClientWithZarfState(ctx, t, state)
 	handler := admission.NewHandler().Serve(NewPodMutationHook(ctx, c))
 
 	tests := []admissionTest{
+		{
+			name: "pod with an image on a registry mirror should be mutated to the upstream image",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "mirror.example.com/dockerhub/library/nginx"}},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx": "mirror.example.com/dockerhub/library/nginx",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
 		{
 			name: "pod with label should be mutated",
 			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
@@ -0,0 +1,311 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package agent holds the mutating webhook server.
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/hooks"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+)
+
+// Labels the webhooks of the agent select objects and namespaces with.
+const (
+	agentLabel             = "zarf.dev/agent"
+	argoSecretTypeLabel    = "argocd.argoproj.io/secret-type"
+	k3sServiceControlLabel = "svccontroller.k3s.cattle.io/svcname"
+)
+
+// Mutation is what the agent would do to an object on admission.
+type Mutation struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Webhook is the webhook of the agent that admits the object, empty when no webhook admits it.
+	Webhook string
+	// Skipped is the reason the agent leaves the object as it is.
+	Skipped string
+	// Err is the error the agent would deny the object with.
+	Err error
+	// Changes are the fields the agent would rewrite.
+	Changes []Change
+}
+
+// Change is a field the agent would rewrite, Before is nil when the field is added and After is nil when it is removed.
+type Change struct {
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+// webhook is a webhook of the agent and the objects it admits.
+type webhook struct {
+	name  string
+	hook  operations.Hook
+	match func(obj *unstructured.Unstructured) bool
+}
+
+// podTemplatePaths are the paths of the pod templates of the workloads whose pods the agent mutates.
+var podTemplatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template"},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template"},
+}
+
+// SimulateMutations runs the mutations of the agent against objects using the Zarf state and namespaces of the cluster,
+// without creating or changing anything in the cluster. The pods of workloads are simulated from their pod template.
+// Objects without a namespace are simulated in defaultNamespace.
+func SimulateMutations(ctx context.Context, c *cluster.Cluster, objs []*unstructured.Unstructured, defaultNamespace string) ([]Mutation, error) {
+	webhooks := agentWebhooks(ctx, c)
+	namespaceLabels := map[string]map[string]string{}
+	mutations := []Mutation{}
+	for _, obj := range objs {
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		if _, ok := namespaceLabels[namespace]; !ok {
+			ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return nil, err
+			}
+			namespaceLabels[namespace] = nil
+			if err == nil {
+				namespaceLabels[namespace] = ns.Labels
+			}
+		}
+
+		mutation, err := simulateMutation(obj, namespace, namespaceLabels[namespace], webhooks)
+		if err != nil {
+			return nil, fmt.Errorf("unable to simulate the mutation of %s %s/%s: %w", obj.GetKind(), namespace, obj.GetName(), err)
+		}
+		mutations = append(mutations, mutation)
+	}
+	return mutations, nil
+}
+
+// agentWebhooks returns the webhooks of the MutatingWebhookConfiguration of the agent.
+func agentWebhooks(ctx context.Context, c *cluster.Cluster) []webhook {
+	isKind := func(group, kind string) func(obj *unstructured.Unstructured) bool {
+		return func(obj *unstructured.Unstructured) bool {
+			gvk := obj.GroupVersionKind()
+			return gvk.Group == group && gvk.Kind == kind
+		}
+	}
+	return []webhook{
+		{
+			name: "agent-pod.zarf.dev",
+			hook: hooks.NewPodMutationHook(ctx, c),
+			match: func(obj *unstructured.Unstructured) bool {
+				_, hasK3sLabel := obj.GetLabels()[k3sServiceControlLabel]
+				return isKind("", "Pod")(obj) && !hasK3sLabel
+			},
+		},
+		{name: "agent-flux-ocirepo.zarf.dev", hook: hooks.NewOCIRepositoryMutationHook(ctx, c), match: isKind("source.toolkit.fluxcd.io", "OCIRepository")},
+		{name: "agent-flux-helmrepo.zarf.dev", hook: hooks.NewHelmRepositoryMutationHook(ctx, c), match: isKind("source.toolkit.fluxcd.io", "HelmRepository")},
+		{name: "agent-flux-gitrepo.zarf.dev", hook: hooks.NewGitRepositoryMutationHook(ctx, c), match: isKind("source.toolkit.fluxcd.io", "GitRepository")},
+		{name: "agent-argocd-application.zarf.dev", hook: hooks.NewApplicationMutationHook(ctx, c), match: isKind("argoproj.io", "Application")},
+		{
+			name: "agent-argocd-repository.zarf.dev",
+			hook: hooks.NewRepositorySecretMutationHook(ctx, c),
+			match: func(obj *unstructured.Unstructured) bool {
+				return isKind("", "Secret")(obj) && obj.GetLabels()[argoSecretTypeLabel] == "repository"
+			},
+		},
+		{name: "agent-olm-catalogsource.zarf.dev", hook: hooks.NewCatalogSourceMutationHook(ctx, c), match: isKind("operators.coreos.com", "CatalogSource")},
+	}
+}
+
+func simulateMutation(obj *unstructured.Unstructured, namespace string, namespaceLabels map[string]string, webhooks []webhook) (Mutation, error) {
+	mutation := Mutation{Kind: obj.GetKind(), Namespace: namespace, Name: obj.GetName()}
+
+	// The agent admits the pods of workloads, which it sees as their pod template
+	admitted := obj.DeepCopy()
+	pathPrefix := ""
+	if templatePath, ok := podTemplatePaths[obj.GroupVersionKind().GroupKind()]; ok {
+		template, found, err := unstructured.NestedMap(obj.Object, templatePath...)
+		if err != nil {
+			return Mutation{}, err
+		}
+		if found {
+			admitted = &unstructured.Unstructured{Object: template}
+			admitted.SetAPIVersion("v1")
+			admitted.SetKind("Pod")
+			if admitted.GetName() == "" {
+				admitted.SetName(obj.GetName())
+			}
+			pathPrefix = "/" + strings.Join(templatePath, "/")
+		}
+	}
+	admitted.SetNamespace(namespace)
+	if err := mergeStringData(admitted); err != nil {
+		return Mutation{}, err
+	}
+
+	var wh *webhook
+	for i := range webhooks {
+		if webhooks[i].match(admitted) {
+			wh = &webhooks[i]
+			break
+		}
+	}
+	if wh == nil {
+		mutation.Skipped = "not admitted by the agent"
+		return mutation, nil
+	}
+	mutation.Webhook = wh.name
+
+	if namespace == "kube-system" {
+		mutation.Skipped = "the agent never mutates objects in kube-system"
+		return mutation, nil
+	}
+	if slices.Contains([]string{"skip", "ignore"}, namespaceLabels[agentLabel]) {
+		mutation.Skipped = fmt.Sprintf("namespace %s is labeled %s=%s", namespace, agentLabel, namespaceLabels[agentLabel])
+		return mutation, nil
+	}
+	if value := admitted.GetLabels()[agentLabel]; slices.Contains([]string{"skip", "ignore"}, value) {
+		mutation.Skipped = fmt.Sprintf("labeled %s=%s", agentLabel, value)
+		return mutation, nil
+	}
+
+	raw, err := admitted.MarshalJSON()
+	if err != nil {
+		return Mutation{}, err
+	}
+	gvk := admitted.GroupVersionKind()
+	result, err := wh.hook.Execute(&v1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Namespace: namespace,
+		Name:      admitted.GetName(),
+		Operation: v1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	})
+	if err != nil {
+		mutation.Err = err
+		return mutation, nil
+	}
+	if !result.Allowed {
+		mutation.Err = fmt.Errorf("denied: %s", result.Msg)
+		return mutation, nil
+	}
+
+	// Compare the patch against the admitted object as JSON values
+	var before map[string]interface{}
+	if err := json.Unmarshal(raw, &before); err != nil {
+		return Mutation{}, err
+	}
+	for _, op := range result.PatchOps {
+		b, err := json.Marshal(op.Value)
+		if err != nil {
+			return Mutation{}, err
+		}
+		var after interface{}
+		if err := json.Unmarshal(b, &after); err != nil {
+			return Mutation{}, err
+		}
+		if op.Op == "remove" {
+			after = nil
+		}
+		current, _ := lookupPointer(before, op.Path)
+		mutation.Changes = append(mutation.Changes, diffValues(pathPrefix+op.Path, current, after)...)
+	}
+	return mutation, nil
+}
+
+// mergeStringData merges the stringData of a secret into its data like the API server does before admitting it.
+func mergeStringData(obj *unstructured.Unstructured) error {
+	if obj.GroupVersionKind().GroupKind() != (schema.GroupKind{Kind: "Secret"}) {
+		return nil
+	}
+	stringData, found, err := unstructured.NestedStringMap(obj.Object, "stringData")
+	if err != nil || !found {
+		return err
+	}
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]string{}
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	unstructured.RemoveNestedField(obj.Object, "stringData")
+	return unstructured.SetNestedStringMap(obj.Object, data, "data")
+}
+
+// lookupPointer returns the value at a JSON pointer in obj.
+func lookupPointer(obj interface{}, pointer string) (interface{}, bool) {
+	current := obj
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// diffValues returns the changes from before to after at path, comparing maps key by key.
+func diffValues(path string, before, after interface{}) []Change {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := []string{}
+		for k := range beforeMap {
+			keys = append(keys, k)
+		}
+		for k := range afterMap {
+			if _, ok := beforeMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		changes := []Change{}
+		for _, k := range keys {
+			escaped := strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+			changes = append(changes, diffValues(path+"/"+escaped, beforeMap[k], afterMap[k])...)
+		}
+		return changes
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []Change{{Path: path, Before: before, After: after}}
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const simulateManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+spec:
+  template:
+    metadata:
+      labels:
+        app: podinfo
+    spec:
+      containers:
+        - name: podinfo
+          image: ghcr.io/stefanprodan/podinfo:6.4.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: podinfo
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: ignored
+  labels:
+    zarf.dev/agent: ignore
+spec:
+  containers:
+    - name: nginx
+      image: nginx
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: skipped-namespace
+  namespace: skipped
+spec:
+  containers:
+    - name: nginx
+      image: nginx
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: system
+  namespace: kube-system
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: nginx
+              image: nginx
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: argocd-repo
+  labels:
+    argocd.argoproj.io/secret-type: repository
+stringData:
+  url: https://example.com/podinfo.git
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: invalid
+spec:
+  containers:
+    - name: invalid
+      image: "INVALID:::"
+`
+
+func TestSimulateMutations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+	state := &types.ZarfState{
+		RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"},
+		GitServer:    types.GitServerInfo{Address: "https://git-server.com", PushUsername: "a-push-user"},
+	}
+	stateData, err := json.Marshal(state)
+	require.NoError(t, err)
+	_, err = c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: cluster.ZarfStateSecretName, Namespace: cluster.ZarfNamespaceName},
+		Data:       map[string][]byte{cluster.ZarfStateDataKey: stateData},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = c.Clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "skipped", Labels: map[string]string{"zarf.dev/agent": "skip"}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	objs, err := utils.SplitYAML([]byte(simulateManifests))
+	require.NoError(t, err)
+	mutations, err := SimulateMutations(ctx, c, objs, "podinfo")
+	require.NoError(t, err)
+	require.Len(t, mutations, len(objs))
+
+	tests := []struct {
+		name    string
+		webhook string
+		skipped string
+		errs    bool
+		changes []Change
+	}{
+		{
+			name:    "podinfo",
+			webhook: "agent-pod.zarf.dev",
+			changes: []Change{
+				{Path: "/spec/template/spec/imagePullSecrets", After: []interface{}{map[string]interface{}{"name": "private-registry"}}},
+				{Path: "/spec/template/spec/containers/0/image", Before: "ghcr.io/stefanprodan/podinfo:6.4.0", After: "127.0.0.1:31999/stefanprodan/podinfo:6.4.0-zarf-2985051089"},
+				{Path: "/spec/template/metadata/labels/zarf-agent", After: "patched"},
+				{Path: "/spec/template/metadata/annotations/zarf.dev~1original-image-podinfo", After: "ghcr.io/stefanprodan/podinfo:6.4.0"},
+			},
+		},
+		{
+			name:    "podinfo",
+			skipped: "not admitted by the agent",
+		},
+		{
+			name:    "ignored",
+			webhook: "agent-pod.zarf.dev",
+			skipped: "labeled zarf.dev/agent=ignore",
+		},
+		{
+			name:    "skipped-namespace",
+			webhook: "agent-pod.zarf.dev",
+			skipped: "namespace skipped is labeled zarf.dev/agent=skip",
+		},
+		{
+			name:    "system",
+			webhook: "agent-pod.zarf.dev",
+			skipped: "the agent never mutates objects in kube-system",
+		},
+		{
+			name:    "argocd-repo",
+			webhook: "agent-argocd-repository.zarf.dev",
+		},
+		{
+			name:    "invalid",
+			webhook: "agent-pod.zarf.dev",
+			errs:    true,
+		},
+	}
+	for i, tt := range tests {
+		mutation := mutations[i]
+		require.Equal(t, tt.name, mutation.Name)
+		require.Equal(t, tt.webhook, mutation.Webhook, tt.name)
+		require.Equal(t, tt.skipped, mutation.Skipped, tt.name)
+		if tt.errs {
+			require.Error(t, mutation.Err, tt.name)
+			continue
+		}
+		require.NoError(t, mutation.Err, tt.name)
+		if tt.changes != nil {
+			require.Equal(t, tt.changes, mutation.Changes)
+		}
+	}
+
+	// The argocd repository secret is rewritten to the git server
+	require.NotEmpty(t, mutations[5].Changes)
+	require.Equal(t, "podinfo", mutations[0].Namespace)
+	require.Equal(t, "kube-system", mutations[4].Namespace)
+}
@@ -42,6 +42,7 @@ func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
 	argocdRepositoryMutation := hooks.NewRepositorySecretMutationHook(ctx, cluster)
 	fluxHelmRepositoryMutation := hooks.NewHelmRepositoryMutationHook(ctx, cluster)
 	fluxOCIRepositoryMutation := hooks.NewOCIRepositoryMutationHook(ctx, cluster)
+	olmCatalogSourceMutation := hooks.NewCatalogSourceMutationHook(ctx, cluster)
 
 	// Routers
 	mux := http.NewServeMux()
@@ -51,6 +52,7 @@ func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
 	mux.Handle("/mutate/flux-ocirepository", admissionHandler.Serve(fluxOCIRepositoryMutation))
 	mux.Handle("/mutate/argocd-application", admissionHandler.Serve(argocdApplicationMutation))
 	mux.Handle("/mutate/argocd-repository", admissionHandler.Serve(argocdRepositoryMutation))
+	mux.Handle("/mutate/olm-catalogsource", admissionHandler.Serve(olmCatalogSourceMutation))
 
 	return startServer(ctx, httpPort, mux)
 }
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package background keeps records of the zarf connect processes that run in the background.
+package background
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const recordExt = ".json"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Record describes a zarf connect process that runs in the background.
+type Record struct {
+	Name    string    `json:"name"`
+	PID     int       `json:"pid"`
+	URLs    []string  `json:"urls"`
+	Started time.Time `json:"started"`
+	LogFile string    `json:"logFile"`
+	// Endpoints is the JSON output of the process for --output json
+	Endpoints json.RawMessage `json:"endpoints,omitempty"`
+}
+
+// Running returns whether the process of the record is still running.
+func (r Record) Running() bool {
+	return processRunning(r.PID)
+}
+
+// Name returns a record name for the targets that is safe to use as a filename.
+func Name(targets ...string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(strings.Join(targets, "-")), "-")
+	return strings.Trim(name, "-.")
+}
+
+// LogPath returns the path of the log file of the record with the name in the directory.
+func LogPath(dir, name string) string {
+	return filepath.Join(dir, name+".log")
+}
+
+// Start runs the current executable with the arguments and environment in a process detached from this one, writing
+// its output to the log.
+func Start(args, env []string, log io.Writer) (*exec.Cmd, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.Env = env
+	cmd.Stdout = log
+	cmd.Stderr = log
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+// Save writes the record to the directory.
+func Save(dir string, record Record) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so the record is never read half written
+	tmp := filepath.Join(dir, "."+record.Name+recordExt)
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, record.Name+recordExt))
+}
+
+// Load reads the record with the name from the directory, returning an error that matches fs.ErrNotExist when there
+// is none.
+func Load(dir, name string) (Record, error) {
+	b, err := os.ReadFile(filepath.Join(dir, name+recordExt))
+	if err != nil {
+		return Record{}, err
+	}
+	var record Record
+	if err := json.Unmarshal(b, &record); err != nil {
+		return Record{}, fmt.Errorf("invalid record %s: %w", name, err)
+	}
+	return record, nil
+}
+
+// List returns the records in the directory sorted by name.
+func List(dir string) ([]Record, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), recordExt)
+		if !ok || entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		record, err := Load(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+	return records, nil
+}
+
+// Remove deletes the record with the name from the directory, it is not an error if there is none.
+func Remove(dir, name string) error {
+	err := os.Remove(filepath.Join(dir, name+recordExt))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Stop asks the process of the record to close its tunnels and waits up to the timeout for it to exit.
+func Stop(record Record, timeout time.Duration) error {
+	if !record.Running() {
+		return nil
+	}
+	if err := stopProcess(record.PID); err != nil {
+		return fmt.Errorf("unable to stop process %d: %w", record.PID, err)
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !record.Running() {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("process %d did not exit within %s", record.PID, timeout)
+}
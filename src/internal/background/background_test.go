@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package background
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		targets  []string
+		expected string
+	}{
+		{
+			name:     "single target",
+			targets:  []string{"REGISTRY"},
+			expected: "registry",
+		},
+		{
+			name:     "several targets",
+			targets:  []string{"git", "registry"},
+			expected: "git-registry",
+		},
+		{
+			name:     "unsafe characters",
+			targets:  []string{"../my app/"},
+			expected: "my-app",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, Name(tt.targets...))
+		})
+	}
+}
+
+func TestRecords(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	records, err := List(dir + "/missing")
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	started := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	registry := Record{Name: "registry", PID: os.Getpid(), URLs: []string{"http://127.0.0.1:31999"}, Started: started, LogFile: LogPath(dir, "registry")}
+	git := Record{Name: "git", PID: os.Getpid(), URLs: []string{"http://127.0.0.1:3000"}, Started: started, LogFile: LogPath(dir, "git")}
+	require.NoError(t, Save(dir, registry))
+	require.NoError(t, Save(dir, git))
+
+	record, err := Load(dir, "registry")
+	require.NoError(t, err)
+	require.Equal(t, registry, record)
+	require.True(t, record.Running())
+
+	records, err = List(dir)
+	require.NoError(t, err)
+	require.Equal(t, []Record{git, registry}, records)
+
+	require.NoError(t, Remove(dir, "registry"))
+	require.NoError(t, Remove(dir, "registry"))
+	_, err = Load(dir, "registry")
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on windows")
+	}
+	cmd := exec.Command("sleep", "60")
+	detach(cmd)
+	require.NoError(t, cmd.Start())
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	record := Record{Name: "sleep", PID: cmd.Process.Pid}
+	require.True(t, record.Running())
+	require.NoError(t, Stop(record, 10*time.Second))
+	require.False(t, record.Running())
+	require.False(t, Record{}.Running())
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build windows
+
+package background
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS process creation flag, which starts the process without a console.
+const detachedProcess = 0x00000008
+
+// detach starts the command without a console and in its own process group so it outlives the terminal it was
+// started from.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// stopProcess kills the process, as Windows can't deliver an interrupt to a process without a console.
+func stopProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package builder offloads package creation to a remote builder running `zarf serve`.
+package builder
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const (
+	// CreatePath is the path packages are created at on a remote builder.
+	CreatePath = "/v1/create"
+	// optionsField is the multipart field holding the create options.
+	optionsField = "options"
+	// contextField is the multipart field holding the gzipped tarball of the package directory.
+	contextField = "context"
+)
+
+// Options are the create options sent to a remote builder, the options that refer to local files or output stay local.
+type Options struct {
+	Architecture        string            `json:"architecture,omitempty"`
+	Flavor              string            `json:"flavor,omitempty"`
+	SetVariables        map[string]string `json:"setVariables,omitempty"`
+	SkipSBOM            bool              `json:"skipSBOM,omitempty"`
+	SkipSBOMComponents  []string          `json:"skipSBOMComponents,omitempty"`
+	CompressSBOM        bool              `json:"compressSBOM,omitempty"`
+	ScanVulnerabilities bool              `json:"scanVulnerabilities,omitempty"`
+	FailOnSeverity      string            `json:"failOnSeverity,omitempty"`
+	AllPlatforms        bool              `json:"allPlatforms,omitempty"`
+	IncludeReferrers    bool              `json:"includeReferrers,omitempty"`
+	RecompressZstd      bool              `json:"recompressZstd,omitempty"`
+	ConvertEstargz      bool              `json:"convertEstargz,omitempty"`
+	RegistryOverrides   map[string]string `json:"registryOverrides,omitempty"`
+}
+
+// NewOptions returns the options sent to a remote builder for the given local create options.
+func NewOptions(opts types.ZarfCreateOptions, arch string) Options {
+	return Options{
+		Architecture:        arch,
+		Flavor:              opts.Flavor,
+		SetVariables:        opts.SetVariables,
+		SkipSBOM:            opts.SkipSBOM,
+		SkipSBOMComponents:  opts.SkipSBOMComponents,
+		CompressSBOM:        opts.CompressSBOM,
+		ScanVulnerabilities: opts.ScanVulnerabilities,
+		FailOnSeverity:      opts.FailOnSeverity,
+		AllPlatforms:        opts.AllPlatforms,
+		IncludeReferrers:    opts.IncludeReferrers,
+		RecompressZstd:      opts.RecompressZstd,
+		ConvertEstargz:      opts.ConvertEstargz,
+		RegistryOverrides:   opts.RegistryOverrides,
+	}
+}
+
+// CreateOpts returns the create options the remote builder creates the package with.
+func (o Options) CreateOpts() types.ZarfCreateOptions {
+	return types.ZarfCreateOptions{
+		Flavor:              o.Flavor,
+		SetVariables:        o.SetVariables,
+		SkipSBOM:            o.SkipSBOM,
+		SkipSBOMComponents:  o.SkipSBOMComponents,
+		CompressSBOM:        o.CompressSBOM,
+		ScanVulnerabilities: o.ScanVulnerabilities,
+		FailOnSeverity:      o.FailOnSeverity,
+		AllPlatforms:        o.AllPlatforms,
+		IncludeReferrers:    o.IncludeReferrers,
+		RecompressZstd:      o.RecompressZstd,
+		ConvertEstargz:      o.ConvertEstargz,
+		RegistryOverrides:   o.RegistryOverrides,
+	}
+}
+
+// writeContext writes the files under dir to w as a gzipped tarball, following symlinks to files and skipping
+// previously created packages.
+func writeContext(w io.Writer, dir string) (err error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	defer func() {
+		err = errors.Join(err, tw.Close(), gw.Close())
+	}()
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), "zarf-package-") && !d.IsDir() {
+			message.Debugf("Not sending the package %s to the remote builder", rel)
+			return nil
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 && fi.IsDir() {
+			message.Warnf("Not sending the symlinked directory %s to the remote builder", rel)
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// readContext extracts a gzipped tarball written by writeContext into dir, rejecting entries outside of it.
+func readContext(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if filepath.IsAbs(name) || !filepath.IsLocal(name) {
+			return fmt.Errorf("the package directory contains the invalid path %s", hdr.Name)
+		}
+		dst := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dst, 0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm()|0o600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if err := errors.Join(err, f.Close()); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("the package directory contains %s which is not a file or directory", hdr.Name)
+		}
+	}
+}
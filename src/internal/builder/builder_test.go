@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package builder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	var received types.ZarfCreateOptions
+	var receivedArch string
+	build := func(_ context.Context, opts types.ZarfCreateOptions, arch string) error {
+		received = opts
+		receivedArch = arch
+		b, err := os.ReadFile(filepath.Join(opts.BaseDir, "zarf.yaml"))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(opts.BaseDir, "zarf-package-old-amd64.tar.zst")); err == nil {
+			return errors.New("previously created packages should not be sent")
+		}
+		if opts.Flavor == "broken" {
+			return errors.New("the flavor is broken")
+		}
+		if err := os.MkdirAll(opts.Output, 0o700); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(opts.Output, "zarf-package-test-arm64.tar.zst"), b, 0o600)
+	}
+	srv := httptest.NewServer(NewHandler("secret", 1024*1024, build))
+	t.Cleanup(srv.Close)
+
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "zarf.yaml"), []byte("kind: ZarfPackageConfig"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "manifests"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "manifests", "deployment.yaml"), []byte("kind: Deployment"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "zarf-package-old-amd64.tar.zst"), []byte("old"), 0o600))
+
+	outDir := t.TempDir()
+	opts := types.ZarfCreateOptions{
+		BaseDir:      baseDir,
+		Output:       outDir,
+		Flavor:       "upstream",
+		SetVariables: map[string]string{"VERSION": "1.0.0"},
+	}
+	path, err := Create(context.Background(), srv.URL, "secret", opts, "arm64")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(outDir, "zarf-package-test-arm64.tar.zst"), path)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "kind: ZarfPackageConfig", string(b))
+	require.Equal(t, "arm64", receivedArch)
+	require.Equal(t, "upstream", received.Flavor)
+	require.Equal(t, map[string]string{"VERSION": "1.0.0"}, received.SetVariables)
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Build failures are returned to the client.
+	opts.Flavor = "broken"
+	_, err = Create(context.Background(), srv.URL, "secret", opts, "arm64")
+	require.ErrorContains(t, err, "the flavor is broken")
+
+	_, err = Create(context.Background(), srv.URL, "wrong", opts, "arm64")
+	require.ErrorContains(t, err, "401 Unauthorized")
+
+	// Package directories larger than the builder accepts are rejected before they are built.
+	large := make([]byte, 2*1024*1024)
+	_, err = rand.Read(large)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "large.bin"), large, 0o600))
+	opts.Flavor = "upstream"
+	_, err = Create(context.Background(), srv.URL, "secret", opts, "arm64")
+	require.ErrorContains(t, err, "the package directory is larger than the 1048576 bytes the remote builder accepts")
+}
+
+func TestValidateCreateOpts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        types.ZarfCreateOptions
+		expectedErr string
+	}{
+		{
+			name: "local output",
+			opts: types.ZarfCreateOptions{Output: "build", Flavor: "upstream"},
+		},
+		{
+			name:        "oci output",
+			opts:        types.ZarfCreateOptions{Output: "oci://ghcr.io/zarf-dev/packages"},
+			expectedErr: "can only be output to a local directory",
+		},
+		{
+			name:        "signing key",
+			opts:        types.ZarfCreateOptions{SigningKeyPath: "cosign.key"},
+			expectedErr: "signed with the key of the builder",
+		},
+		{
+			name:        "differential",
+			opts:        types.ZarfCreateOptions{DifferentialPackagePath: "zarf-package-test-amd64-1.0.0.tar.zst"},
+			expectedErr: "differential packages cannot be created by a remote builder",
+		},
+		{
+			name:        "differential from a published package",
+			opts:        types.ZarfCreateOptions{DifferentialFrom: "oci://ghcr.io/zarf-dev/packages/test:1.0.0"},
+			expectedErr: "differential packages cannot be created by a remote builder",
+		},
+		{
+			name:        "git build context",
+			opts:        types.ZarfCreateOptions{BaseDir: "https://github.com/zarf-dev/zarf//examples/dos-games?ref=v0.38.0"},
+			expectedErr: "packages cannot be created by a remote builder from a git build context",
+		},
+		{
+			name:        "split",
+			opts:        types.ZarfCreateOptions{MaxPackageSizeMB: 100},
+			expectedErr: "cannot be split",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateCreateOpts(tt.opts)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		header      tar.Header
+		expectedErr string
+	}{
+		{
+			name:   "file",
+			header: tar.Header{Name: "manifests/deployment.yaml", Typeflag: tar.TypeReg, Mode: 0o644},
+		},
+		{
+			name:        "parent directory",
+			header:      tar.Header{Name: "../escape.yaml", Typeflag: tar.TypeReg, Mode: 0o644},
+			expectedErr: "invalid path ../escape.yaml",
+		},
+		{
+			name:        "absolute path",
+			header:      tar.Header{Name: "/etc/escape.yaml", Typeflag: tar.TypeReg, Mode: 0o644},
+			expectedErr: "invalid path /etc/escape.yaml",
+		},
+		{
+			name:        "symlink",
+			header:      tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+			expectedErr: "link which is not a file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+			require.NoError(t, tw.WriteHeader(&tt.header))
+			require.NoError(t, tw.Close())
+			require.NoError(t, gw.Close())
+
+			dir := t.TempDir()
+			err := readContext(&buf, dir)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				require.FileExists(t, filepath.Join(dir, tt.header.Name))
+				return
+			}
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
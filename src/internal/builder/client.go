@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package builder offloads package creation to a remote builder running `zarf serve`.
+package builder
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// ValidateCreateOpts returns an error for the create options that cannot be used with a remote builder.
+func ValidateCreateOpts(opts types.ZarfCreateOptions) error {
+	switch {
+	case git.IsBuildContext(opts.BaseDir):
+		return errors.New("packages cannot be created by a remote builder from a git build context")
+	case helpers.IsOCIURL(opts.Output):
+		return errors.New("packages created by a remote builder can only be output to a local directory")
+	case opts.DifferentialPackagePath != "" || opts.DifferentialFrom != "":
+		return errors.New("differential packages cannot be created by a remote builder")
+	case opts.SigningKeyPath != "":
+		return errors.New("packages created by a remote builder are signed with the key of the builder, not a local signing key")
+	case opts.ViewSBOM || opts.SBOMOutputDir != "":
+		return errors.New("the SBOMs of packages created by a remote builder can be viewed with `zarf package inspect --sbom`")
+	case opts.MaxPackageSizeMB > 0:
+		return errors.New("packages created by a remote builder cannot be split")
+	}
+	return nil
+}
+
+// Create streams the package directory at opts.BaseDir to the remote builder at url, which creates the package, and
+// saves the created package to opts.Output, returning its path.
+func Create(ctx context.Context, url, token string, opts types.ZarfCreateOptions, arch string) (string, error) {
+	if err := ValidateCreateOpts(opts); err != nil {
+		return "", err
+	}
+	outputDir := opts.Output
+	if outputDir == "" {
+		outputDir = "."
+	}
+
+	spinner := message.NewProgressSpinner("Sending %s to the remote builder at %s", opts.BaseDir, url)
+	defer spinner.Stop()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeRequest(mw, opts, arch))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(url, "/")+CreatePath, pr)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: config.CommonOptions.Insecure}
+	client := &http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to reach the remote builder: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return "", fmt.Errorf("the remote builder returned %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition"))
+	if err != nil {
+		return "", fmt.Errorf("the remote builder did not name the created package: %w", err)
+	}
+	name := filepath.Base(params["filename"])
+	if !strings.HasPrefix(name, "zarf-package-") {
+		return "", fmt.Errorf("the remote builder returned the unexpected file %q", params["filename"])
+	}
+
+	spinner.Updatef("Receiving %s from the remote builder", name)
+	if err := os.MkdirAll(outputDir, helpers.ReadWriteExecuteUser); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(outputDir, name)
+	f, err := os.CreateTemp(outputDir, name+".part-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, resp.Body)
+	if err := errors.Join(err, f.Close()); err != nil {
+		return "", fmt.Errorf("unable to receive the created package: %w", err)
+	}
+	if err := os.Rename(f.Name(), dst); err != nil {
+		return "", err
+	}
+
+	spinner.Successf("Package saved to %q", dst)
+	return dst, nil
+}
+
+// writeRequest writes the create options and the package directory as the parts of a create request.
+func writeRequest(mw *multipart.Writer, opts types.ZarfCreateOptions, arch string) error {
+	ow, err := mw.CreateFormField(optionsField)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(ow).Encode(NewOptions(opts, arch)); err != nil {
+		return err
+	}
+	cw, err := mw.CreateFormFile(contextField, "context.tar.gz")
+	if err != nil {
+		return err
+	}
+	if err := writeContext(cw, opts.BaseDir); err != nil {
+		return fmt.Errorf("unable to send the package directory: %w", err)
+	}
+	return mw.Close()
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package builder offloads package creation to a remote builder running `zarf serve`.
+package builder
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// BuildFunc creates the package described by opts for the given architecture.
+type BuildFunc func(ctx context.Context, opts types.ZarfCreateOptions, arch string) error
+
+type server struct {
+	token string
+	build BuildFunc
+	// Create requests larger than maxUploadSize bytes are rejected
+	maxUploadSize int64
+	// Package creation relies on process wide configuration so packages are created one at a time.
+	mu sync.Mutex
+}
+
+// NewHandler returns the handler of a remote builder that creates packages with build for clients presenting token,
+// accepting package directories of up to maxUploadSize bytes.
+func NewHandler(token string, maxUploadSize int64, build BuildFunc) http.Handler {
+	s := &server{
+		token:         token,
+		build:         build,
+		maxUploadSize: maxUploadSize,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+CreatePath, s.authorize(s.create))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		//nolint: errcheck // ignore
+		w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func (s *server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		expected := []byte("Bearer " + s.token)
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "invalid or missing builder token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *server) create(w http.ResponseWriter, r *http.Request) {
+	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+	srcDir := filepath.Join(tmpDir, "src")
+	outDir := filepath.Join(tmpDir, "out")
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+	opts, err := readRequest(r, srcDir)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, fmt.Sprintf("the package directory is larger than the %d bytes the remote builder accepts", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message.Infof("Creating a package for %s", r.RemoteAddr)
+	createOpts := opts.CreateOpts()
+	createOpts.BaseDir = srcDir
+	createOpts.Output = outDir
+	if err := s.build(r.Context(), createOpts, opts.Architecture); err != nil {
+		message.WarnErrf(err, "Failed to create the package for %s", r.RemoteAddr)
+		http.Error(w, fmt.Sprintf("failed to create package: %s", err.Error()), http.StatusUnprocessableEntity)
+		return
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil || len(entries) != 1 || entries[0].IsDir() {
+		http.Error(w, "the remote builder did not create a single package file", http.StatusInternalServerError)
+		return
+	}
+	pkgPath := filepath.Join(outDir, entries[0].Name())
+	f, err := os.Open(pkgPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", entries[0].Name()))
+	w.Header().Set("Content-Length", strconv.FormatInt(fi.Size(), 10))
+	if _, err := io.Copy(w, f); err != nil {
+		message.WarnErrf(err, "Failed to send the package to %s", r.RemoteAddr)
+		return
+	}
+	message.Successf("Sent %s to %s", entries[0].Name(), r.RemoteAddr)
+}
+
+// readRequest reads the create options and extracts the package directory of a create request into dir.
+func readRequest(r *http.Request, dir string) (Options, error) {
+	var opts Options
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return opts, err
+	}
+	var hasOptions, hasContext bool
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return opts, err
+		}
+		switch part.FormName() {
+		case optionsField:
+			if err := json.NewDecoder(part).Decode(&opts); err != nil {
+				return opts, fmt.Errorf("invalid create options: %w", err)
+			}
+			hasOptions = true
+		case contextField:
+			if err := readContext(part, dir); err != nil {
+				return opts, fmt.Errorf("invalid package directory: %w", err)
+			}
+			hasContext = true
+		}
+		part.Close()
+	}
+	if !hasOptions || !hasContext {
+		return opts, errors.New("the request must contain the create options and the package directory")
+	}
+	return opts, nil
+}
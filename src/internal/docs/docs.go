@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package docs renders and serves the Zarf CLI reference and package schema documentation.
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// SchemaPath is the path the raw zarf.yaml JSON schema is served from.
+const SchemaPath = "/schema/zarf.schema.json"
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }} - Zarf {{ .Version }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+td, th { border: 1px solid #ddd; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<p><a href="/">Zarf {{ .Version }} documentation</a></p>
+<h1>{{ .Title }}</h1>
+{{ if .Markdown }}<pre>{{ .Markdown }}</pre>{{ end }}
+{{ if .Links }}<ul>{{ range .Links }}<li><a href="{{ .Href }}">{{ .Text }}</a></li>{{ end }}</ul>{{ end }}
+{{ range .Definitions }}
+<h2 id="{{ .Name }}">{{ .Name }}</h2>
+<p>{{ .Description }}</p>
+{{ if .Properties }}<table>
+<tr><th>Property</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{ range .Properties }}<tr><td><code>{{ .Name }}</code></td><td>{{ if .Ref }}<a href="#{{ .Ref }}">{{ .Type }}</a>{{ else }}{{ .Type }}{{ end }}</td><td>{{ if .Required }}yes{{ end }}</td><td>{{ .Description }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ end }}
+</body>
+</html>
+`))
+
+type link struct {
+	Href string
+	Text string
+}
+
+type property struct {
+	Name        string
+	Type        string
+	Ref         string
+	Required    bool
+	Description string
+}
+
+type definition struct {
+	Name        string
+	Description string
+	Properties  []property
+}
+
+type page struct {
+	Title       string
+	Version     string
+	Markdown    string
+	Links       []link
+	Definitions []definition
+}
+
+type server struct {
+	version     string
+	commands    map[string]string
+	names       []string
+	schema      []byte
+	definitions []definition
+}
+
+// NewHandler returns an http.Handler serving the reference for every available command under root and the docs for the given zarf.yaml schema.
+func NewHandler(root *cobra.Command, version string, schema []byte) (http.Handler, error) {
+	commands, err := GenerateCommandDocs(root)
+	if err != nil {
+		return nil, err
+	}
+	definitions, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		version:     version,
+		commands:    commands,
+		schema:      schema,
+		definitions: definitions,
+	}
+	for name := range commands {
+		s.names = append(s.names, name)
+	}
+	sort.Strings(s.names)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /commands/{name}/", s.command)
+	mux.HandleFunc("GET /schema/{$}", s.schemaDocs)
+	mux.HandleFunc("GET "+SchemaPath, s.rawSchema)
+	return mux, nil
+}
+
+// GenerateCommandDocs renders the markdown reference for root and all of its available subcommands, keyed by the command's doc name (e.g. zarf_package_create).
+func GenerateCommandDocs(root *cobra.Command) (map[string]string, error) {
+	linkHandler := func(link string) string {
+		return "/commands/" + strings.TrimSuffix(link, ".md") + "/"
+	}
+	commands := map[string]string{}
+	var walk func(cmd *cobra.Command) error
+	walk = func(cmd *cobra.Command) error {
+		for _, c := range cmd.Commands() {
+			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		var buf bytes.Buffer
+		if err := doc.GenMarkdownCustom(cmd, &buf, linkHandler); err != nil {
+			return fmt.Errorf("unable to generate the docs for %s: %w", cmd.CommandPath(), err)
+		}
+		commands[docName(cmd)] = buf.String()
+		return nil
+	}
+	root.DisableAutoGenTag = true
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+func docName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_")
+}
+
+func parseSchema(schema []byte) ([]definition, error) {
+	var raw struct {
+		Defs map[string]struct {
+			Description string                     `json:"description"`
+			Required    []string                   `json:"required"`
+			Properties  map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(schema, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse the zarf.yaml schema: %w", err)
+	}
+
+	definitions := []definition{}
+	for name, def := range raw.Defs {
+		d := definition{Name: name, Description: def.Description}
+		for propName, propRaw := range def.Properties {
+			var prop struct {
+				Type        any    `json:"type"`
+				Ref         string `json:"$ref"`
+				Description string `json:"description"`
+				Items       *struct {
+					Type any    `json:"type"`
+					Ref  string `json:"$ref"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(propRaw, &prop); err != nil {
+				return nil, fmt.Errorf("unable to parse the schema property %s.%s: %w", name, propName, err)
+			}
+			p := property{
+				Name:        propName,
+				Type:        fmt.Sprint(prop.Type),
+				Description: prop.Description,
+			}
+			for _, req := range def.Required {
+				if req == propName {
+					p.Required = true
+				}
+			}
+			switch {
+			case prop.Ref != "":
+				p.Ref = strings.TrimPrefix(prop.Ref, "#/$defs/")
+				p.Type = p.Ref
+			case prop.Items != nil && prop.Items.Ref != "":
+				p.Ref = strings.TrimPrefix(prop.Items.Ref, "#/$defs/")
+				p.Type = "[]" + p.Ref
+			case prop.Items != nil:
+				p.Type = fmt.Sprintf("[]%v", prop.Items.Type)
+			case prop.Type == nil:
+				p.Type = "any"
+			}
+			d.Properties = append(d.Properties, p)
+		}
+		sort.Slice(d.Properties, func(i, j int) bool {
+			return d.Properties[i].Name < d.Properties[j].Name
+		})
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions, nil
+}
+
+func (s *server) render(w http.ResponseWriter, p page) {
+	p.Version = s.version
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *server) index(w http.ResponseWriter, _ *http.Request) {
+	links := []link{{Href: "/schema/", Text: "zarf.yaml package schema"}}
+	for _, name := range s.names {
+		links = append(links, link{Href: "/commands/" + name + "/", Text: strings.ReplaceAll(name, "_", " ")})
+	}
+	s.render(w, page{Title: "Zarf CLI reference", Links: links})
+}
+
+func (s *server) command(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	markdown, ok := s.commands[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	links := []link{}
+	for _, other := range s.names {
+		if strings.HasPrefix(other, name+"_") && !strings.Contains(strings.TrimPrefix(other, name+"_"), "_") {
+			links = append(links, link{Href: "/commands/" + other + "/", Text: strings.ReplaceAll(other, "_", " ")})
+		}
+	}
+	s.render(w, page{Title: strings.ReplaceAll(name, "_", " "), Markdown: markdown, Links: links})
+}
+
+func (s *server) schemaDocs(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, page{
+		Title:       "zarf.yaml package schema",
+		Links:       []link{{Href: SchemaPath, Text: "Raw JSON schema"}},
+		Definitions: s.definitions,
+	})
+}
+
+func (s *server) rawSchema(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(s.schema)
+}
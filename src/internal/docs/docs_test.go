@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package docs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	root := &cobra.Command{Use: "zarf"}
+	pkgCmd := &cobra.Command{Use: "package", Short: "Zarf package commands"}
+	createCmd := &cobra.Command{Use: "create", Short: "Creates a Zarf package", Run: func(_ *cobra.Command, _ []string) {}}
+	hiddenCmd := &cobra.Command{Use: "internal", Hidden: true, Run: func(_ *cobra.Command, _ []string) {}}
+	pkgCmd.AddCommand(createCmd)
+	root.AddCommand(pkgCmd, hiddenCmd)
+
+	schema := []byte(`{"$defs":{"ZarfMetadata":{"description":"Package metadata.","required":["name"],"properties":{"name":{"type":"string","description":"Name to identify this Zarf package."}}}}}`)
+	handler, err := NewHandler(root, "v0.0.1", schema)
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		contains     string
+	}{
+		{path: "/", expectedCode: http.StatusOK, contains: `href="/commands/zarf_package_create/"`},
+		{path: "/commands/zarf_package/", expectedCode: http.StatusOK, contains: "Zarf package commands"},
+		{path: "/commands/zarf_package_create/", expectedCode: http.StatusOK, contains: "Creates a Zarf package"},
+		{path: "/commands/zarf_internal/", expectedCode: http.StatusNotFound},
+		{path: "/schema/", expectedCode: http.StatusOK, contains: "Name to identify this Zarf package."},
+		{path: SchemaPath, expectedCode: http.StatusOK, contains: `"ZarfMetadata"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			resp, err := http.Get(srv.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedCode, resp.StatusCode)
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(b), tt.contains)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package faultinject simulates failures at fixed points of the deploy pipeline so that failure handling can be tested deterministically.
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// Point is a location in the deploy pipeline where a failure can be injected.
+type Point string
+
+// Points where failures can be injected.
+const (
+	// RegistryPush fails an image push to the registry
+	RegistryPush Point = "registry-push"
+	// HelmTimeout fails a helm install or upgrade as if it had timed out
+	HelmTimeout Point = "helm-timeout"
+	// TunnelDrop fails a request made through a tunnel as if the tunnel had dropped
+	TunnelDrop Point = "tunnel-drop"
+)
+
+// Points lists every point where a failure can be injected.
+var Points = []Point{RegistryPush, HelmTimeout, TunnelDrop}
+
+// always is the count that makes a point fail every time it is reached.
+const always = -1
+
+// ErrInjected is wrapped by every error returned for an injected failure.
+var ErrInjected = errors.New("injected fault")
+
+var (
+	mu        sync.Mutex
+	remaining = map[Point]int{}
+)
+
+// Enable configures the failures to inject from a comma separated list of points.
+//
+// Each point may be followed by the number of times it should fail before succeeding (e.g. registry-push:2)
+// or by "always" to fail every time it is reached. A point without a count fails once.
+func Enable(spec string) error {
+	faults := map[Point]int{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, countStr, hasCount := strings.Cut(entry, ":")
+		point := Point(name)
+		if !slices.Contains(Points, point) {
+			return fmt.Errorf("unknown fault injection point %q, valid points are: %s", name, pointNames())
+		}
+		count := 1
+		if hasCount {
+			if countStr == "always" {
+				count = always
+			} else {
+				n, err := strconv.Atoi(countStr)
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid fault injection count %q for %s, must be a positive integer or \"always\"", countStr, name)
+				}
+				count = n
+			}
+		}
+		faults[point] = count
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	remaining = faults
+	return nil
+}
+
+// Disable stops injecting failures.
+func Disable() {
+	mu.Lock()
+	defer mu.Unlock()
+	remaining = map[Point]int{}
+}
+
+// Trigger returns an error if a failure is configured for the given point and has not been used up.
+func Trigger(point Point) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	count, ok := remaining[point]
+	if !ok || count == 0 {
+		return nil
+	}
+	if count != always {
+		remaining[point] = count - 1
+	}
+	message.Debugf("Injecting %s fault", point)
+
+	switch point {
+	case HelmTimeout:
+		return fmt.Errorf("%w: %s: %w", ErrInjected, point, context.DeadlineExceeded)
+	default:
+		return fmt.Errorf("%w: %s", ErrInjected, point)
+	}
+}
+
+func pointNames() string {
+	names := []string{}
+	for _, point := range Points {
+		names = append(names, string(point))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package faultinject
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnable(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    map[Point]int
+		expectedErr string
+	}{
+		{
+			name:     "empty",
+			spec:     "",
+			expected: map[Point]int{},
+		},
+		{
+			name: "counts",
+			spec: "registry-push:2, helm-timeout,tunnel-drop:always",
+			expected: map[Point]int{
+				RegistryPush: 2,
+				HelmTimeout:  1,
+				TunnelDrop:   always,
+			},
+		},
+		{
+			name:        "unknown point",
+			spec:        "disk-full",
+			expectedErr: `unknown fault injection point "disk-full", valid points are: registry-push, helm-timeout, tunnel-drop`,
+		},
+		{
+			name:        "invalid count",
+			spec:        "registry-push:0",
+			expectedErr: `invalid fault injection count "0" for registry-push, must be a positive integer or "always"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(Disable)
+
+			err := Enable(tt.spec)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, remaining)
+		})
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	t.Cleanup(Disable)
+
+	err := Enable("registry-push:2,helm-timeout:always")
+	require.NoError(t, err)
+
+	for range 2 {
+		err = Trigger(RegistryPush)
+		require.ErrorIs(t, err, ErrInjected)
+	}
+	require.NoError(t, Trigger(RegistryPush))
+
+	for range 3 {
+		err = Trigger(HelmTimeout)
+		require.ErrorIs(t, err, ErrInjected)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	require.NoError(t, Trigger(TunnelDrop))
+
+	Disable()
+	require.NoError(t, Trigger(HelmTimeout))
+}
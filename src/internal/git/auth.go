@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const defaultGitUsername = "git"
+
+// AuthForURL returns the authentication of the credential with the longest URL prefix matching address, it returns nil
+// when no credential matches so that the credentials found for the host are used.
+func AuthForURL(credentials []types.GitCredential, address string) (transport.AuthMethod, error) {
+	var match *types.GitCredential
+	for i, credential := range credentials {
+		if strings.HasPrefix(address, credential.URL) && (match == nil || len(credential.URL) > len(match.URL)) {
+			match = &credentials[i]
+		}
+	}
+	if match == nil {
+		return nil, nil
+	}
+
+	username := match.Username
+	if username == "" {
+		username = defaultGitUsername
+	}
+	if match.SSHKey != "" {
+		auth, err := ssh.NewPublicKeysFromFile(username, config.GetAbsHomePath(match.SSHKey), match.SSHKeyPassword)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the ssh key for %s: %w", match.URL, err)
+		}
+		return auth, nil
+	}
+	return &http.BasicAuth{Username: username, Password: match.Password}, nil
+}
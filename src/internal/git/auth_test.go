@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestAuthForURL(t *testing.T) {
+	t.Parallel()
+
+	credentials := []types.GitCredential{
+		{URL: "https://git.example.com/", Password: "host-token"},
+		{URL: "https://git.example.com/my-org/", Username: "zarf", Password: "org-token"},
+		{URL: "ssh://git@git.example.com/", SSHKey: "does-not-exist"},
+	}
+	tests := []struct {
+		name         string
+		address      string
+		expectedAuth transport.AuthMethod
+		expectedErr  string
+	}{
+		{
+			name:         "longest prefix",
+			address:      "https://git.example.com/my-org/components.git@v1.0.0",
+			expectedAuth: &http.BasicAuth{Username: "zarf", Password: "org-token"},
+		},
+		{
+			name:         "default username",
+			address:      "https://git.example.com/other-org/components.git",
+			expectedAuth: &http.BasicAuth{Username: "git", Password: "host-token"},
+		},
+		{
+			name:    "no match",
+			address: "https://github.com/zarf-dev/zarf.git",
+		},
+		{
+			name:        "missing ssh key",
+			address:     "ssh://git@git.example.com/my-org/components.git",
+			expectedErr: "unable to load the ssh key for ssh://git@git.example.com/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth, err := AuthForURL(credentials, tt.address)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedAuth, auth)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// buildContextSchemes are the URL schemes of git repositories that packages can be created from.
+var buildContextSchemes = []string{"https://", "http://", "ssh://", "file://"}
+
+// BuildContext is a directory within a git repository that a package is created from, written as
+// <url>//<path>?ref=<ref>.
+type BuildContext struct {
+	// URL is the URL of the git repository.
+	URL string
+	// Ref is the tag, branch (refs/heads/<branch>) or commit to check out, the default branch when empty.
+	Ref string
+	// Path is the directory of the zarf.yaml within the repository, its root when empty.
+	Path string
+}
+
+// IsBuildContext returns whether the directory a package is created from is a git build context instead of a local
+// directory.
+func IsBuildContext(dir string) bool {
+	for _, scheme := range buildContextSchemes {
+		if strings.HasPrefix(dir, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBuildContext parses a git build context such as https://github.com/org/repo//path?ref=v1.2.3.
+func ParseBuildContext(address string) (BuildContext, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return BuildContext{}, fmt.Errorf("invalid git build context %s: %w", address, err)
+	}
+	query := u.Query()
+	ref := query.Get("ref")
+	query.Del("ref")
+	u.RawQuery = query.Encode()
+
+	// The path within the repository follows the first double slash after the host
+	repoPath, contextPath, _ := strings.Cut(u.Path, "//")
+	u.Path = repoPath
+	u.RawPath = ""
+	if contextPath != "" {
+		contextPath = path.Clean(contextPath)
+		if !filepath.IsLocal(filepath.FromSlash(contextPath)) {
+			return BuildContext{}, fmt.Errorf("the path %s of the git build context %s is not within the repository", contextPath, address)
+		}
+	}
+	if strings.Trim(repoPath, "/") == "" {
+		return BuildContext{}, fmt.Errorf("the git build context %s does not name a repository", address)
+	}
+
+	return BuildContext{
+		URL:  u.String(),
+		Ref:  ref,
+		Path: filepath.FromSlash(contextPath),
+	}, nil
+}
+
+// CloneBuildContext clones the repository of the build context with its submodules into rootPath, returning the
+// directory to create the package from.
+func CloneBuildContext(ctx context.Context, rootPath string, bc BuildContext, credentials []types.GitCredential) (string, error) {
+	address := bc.URL
+	if bc.Ref != "" {
+		address = fmt.Sprintf("%s@%s", bc.URL, bc.Ref)
+	}
+	auth, err := AuthForURL(credentials, bc.URL)
+	if err != nil {
+		return "", err
+	}
+
+	message.Debugf("Cloning %s to create the package from", address)
+	// A commit is not necessarily the tip of a branch or tag, so it can only be checked out from a full clone
+	repo, err := CloneWithAuth(ctx, rootPath, address, !plumbing.IsHash(bc.Ref), auth)
+	if err != nil {
+		return "", fmt.Errorf("unable to clone %s to create the package from: %w", address, err)
+	}
+	if err := repo.UpdateSubmodules(ctx, credentials); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(repo.Path(), bc.Path)
+	if _, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("unable to find the path %s in %s: %w", bc.Path, address, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/gitkit"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestParseBuildContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		address     string
+		expected    BuildContext
+		expectedErr string
+	}{
+		{
+			name:     "path and ref",
+			address:  "https://github.com/zarf-dev/zarf//examples/dos-games?ref=v0.38.0",
+			expected: BuildContext{URL: "https://github.com/zarf-dev/zarf", Ref: "v0.38.0", Path: filepath.Join("examples", "dos-games")},
+		},
+		{
+			name:     "repository root",
+			address:  "ssh://git@github.com/zarf-dev/zarf.git",
+			expected: BuildContext{URL: "ssh://git@github.com/zarf-dev/zarf.git"},
+		},
+		{
+			name:     "branch",
+			address:  "https://git.example.com/my-org/packages.git//podinfo/?ref=refs/heads/main",
+			expected: BuildContext{URL: "https://git.example.com/my-org/packages.git", Ref: "refs/heads/main", Path: "podinfo"},
+		},
+		{
+			name:     "other query parameters",
+			address:  "http://git.example.com/packages?private_token=token&ref=v1.0.0",
+			expected: BuildContext{URL: "http://git.example.com/packages?private_token=token", Ref: "v1.0.0"},
+		},
+		{
+			name:        "path outside of the repository",
+			address:     "https://github.com/zarf-dev/zarf//examples/../..",
+			expectedErr: "the path .. of the git build context https://github.com/zarf-dev/zarf//examples/../.. is not within the repository",
+		},
+		{
+			name:        "no repository",
+			address:     "https://github.com//examples",
+			expectedErr: "the git build context https://github.com//examples does not name a repository",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.True(t, IsBuildContext(tt.address))
+			bc, err := ParseBuildContext(tt.address)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, bc)
+		})
+	}
+
+	require.False(t, IsBuildContext("examples/dos-games"))
+	require.False(t, IsBuildContext("oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0"))
+}
+
+func TestCloneBuildContext(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	gitSrv := gitkit.New(gitkit.Config{
+		Dir:        t.TempDir(),
+		AutoCreate: true,
+		Auth:       true,
+	})
+	gitSrv.AuthFunc = func(cred gitkit.Credential, _ *gitkit.Request) (bool, error) {
+		return cred.Username == "zarf" && cred.Password == "token", nil
+	}
+	require.NoError(t, gitSrv.Setup())
+	srv := httptest.NewServer(http.HandlerFunc(gitSrv.ServeHTTP))
+	t.Cleanup(srv.Close)
+	auth := &githttp.BasicAuth{Username: "zarf", Password: "token"}
+
+	// The library is a submodule of the package repository
+	lib, err := git.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	libCommit := commitFiles(t, lib, map[string]string{"lib.txt": "library"})
+	_, err = lib.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{fmt.Sprintf("%s/lib.git", srv.URL)}})
+	require.NoError(t, err)
+	require.NoError(t, lib.Push(&git.PushOptions{RemoteName: "origin", Auth: auth}))
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	idx, err := repo.Storer.Index()
+	require.NoError(t, err)
+	idx.Entries = append(idx.Entries, &index.Entry{Name: "pkg/lib", Hash: libCommit, Mode: filemode.Submodule})
+	require.NoError(t, repo.Storer.SetIndex(idx))
+	commit := commitFiles(t, repo, map[string]string{
+		".gitmodules":   "[submodule \"lib\"]\n\tpath = pkg/lib\n\turl = ../lib.git\n",
+		"pkg/zarf.yaml": "kind: ZarfPackageConfig\nmetadata:\n  name: test\n",
+	})
+	_, err = repo.CreateTag("v1.0.0", commit, nil)
+	require.NoError(t, err)
+	// The default branch moves on after the tag
+	commitFiles(t, repo, map[string]string{"pkg/zarf.yaml": "kind: ZarfPackageConfig\nmetadata:\n  name: test\n  version: 2.0.0\n"})
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{fmt.Sprintf("%s/packages.git", srv.URL)}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"},
+	}))
+
+	credentials := []types.GitCredential{{URL: srv.URL + "/", Username: "zarf", Password: "token"}}
+	bc, err := ParseBuildContext(fmt.Sprintf("%s/packages.git//pkg?ref=v1.0.0", srv.URL))
+	require.NoError(t, err)
+	dir, err := CloneBuildContext(ctx, t.TempDir(), bc, credentials)
+	require.NoError(t, err)
+	require.Equal(t, "pkg", filepath.Base(dir))
+	require.FileExists(t, filepath.Join(dir, "lib", "lib.txt"))
+	b, err := os.ReadFile(filepath.Join(dir, "zarf.yaml"))
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "version")
+
+	bc.Path = "missing"
+	_, err = CloneBuildContext(ctx, t.TempDir(), bc, credentials)
+	require.ErrorContains(t, err, "unable to find the path missing in")
+}
+
+// commitFiles commits the files to the worktree of the repository, returning the commit.
+func commitFiles(t *testing.T, repo *git.Repository, files map[string]string) plumbing.Hash {
+	t.Helper()
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		f, err := w.Filesystem.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = w.Add(name)
+		require.NoError(t, err)
+	}
+	commit, err := w.Commit("Update", &git.CommitOptions{Author: &object.Signature{Email: "example@example.com"}})
+	require.NoError(t, err)
+	return commit
+}
@@ -9,12 +9,23 @@ import (
 	"strings"
 
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
 const onlineRemoteName = "online-upstream"
 const offlineRemoteName = "offline-downstream"
 const emptyRef = ""
 
+func init() {
+	// Log the requests to git servers at the highest verbosity
+	httpClient := githttp.NewClient(message.WireClient(nil))
+	client.InstallProtocol("http", httpClient)
+	client.InstallProtocol("https", httpClient)
+}
+
 // ParseRef parses the provided ref into a ReferenceName if it's not a hash.
 func ParseRef(r string) plumbing.ReferenceName {
 	// If not a full ref, assume it's a tag at this point.
@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // Open opens an existing local repository at the given path.
@@ -51,6 +54,12 @@ func Open(rootPath, address string) (*Repository, error) {
 
 // Clone clones a git repository to the given local path.
 func Clone(ctx context.Context, rootPath, address string, shallow bool) (*Repository, error) {
+	return CloneWithAuth(ctx, rootPath, address, shallow, nil)
+}
+
+// CloneWithAuth clones a git repository to the given local path, authenticating with auth instead of the credentials
+// found for the host when it is set.
+func CloneWithAuth(ctx context.Context, rootPath, address string, shallow bool, auth transport.AuthMethod) (*Repository, error) {
 	// Split the remote url and the zarf reference
 	gitURLNoRef, refPlain, err := transform.GitURLSplitRef(address)
 	if err != nil {
@@ -86,13 +95,16 @@ func Clone(ctx context.Context, rootPath, address string, shallow bool) (*Reposi
 	if shallow {
 		cloneOpts.Depth = 1
 	}
-	gitCred, err := utils.FindAuthForHost(gitURLNoRef)
-	if err != nil {
-		return nil, err
-	}
-	if gitCred != nil {
-		cloneOpts.Auth = &gitCred.Auth
+	if auth == nil {
+		gitCred, err := utils.FindAuthForHost(gitURLNoRef)
+		if err != nil {
+			return nil, err
+		}
+		if gitCred != nil {
+			auth = &gitCred.Auth
+		}
 	}
+	cloneOpts.Auth = auth
 	repo, err := git.PlainCloneContext(ctx, r.path, false, cloneOpts)
 	if err != nil {
 		message.Notef("Falling back to host 'git', failed to clone the repo %q with Zarf: %s", gitURLNoRef, err.Error())
@@ -109,9 +121,7 @@ func Clone(ctx context.Context, rootPath, address string, shallow bool) (*Reposi
 			RefSpecs:   []config.RefSpec{"refs/*:refs/*"},
 			Tags:       git.AllTags,
 		}
-		if gitCred != nil {
-			fetchOpts.Auth = &gitCred.Auth
-		}
+		fetchOpts.Auth = auth
 		if err := repo.FetchContext(ctx, fetchOpts); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
 			return nil, err
 		}
@@ -144,6 +154,74 @@ func (r *Repository) Path() string {
 	return r.path
 }
 
+// Head returns the hash of the commit the repository has checked out.
+func (r *Repository) Head() (string, error) {
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return "", fmt.Errorf("not a valid git repo or unable to open: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the HEAD of the git repo: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+// UpdateSubmodules initializes and checks out the submodules of the repository recursively, authenticating to each
+// with the credential matching its URL.
+func (r *Repository) UpdateSubmodules(ctx context.Context, credentials []types.GitCredential) error {
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return fmt.Errorf("not a valid git repo or unable to open: %w", err)
+	}
+	tree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("unable to load the git repo: %w", err)
+	}
+	submodules, err := tree.Submodules()
+	if err != nil {
+		return fmt.Errorf("unable to read the submodules of the git repo: %w", err)
+	}
+	for _, submodule := range submodules {
+		address := submodule.Config().URL
+		// Relative submodules are hosted next to the repository
+		if strings.HasPrefix(address, "./") || strings.HasPrefix(address, "../") {
+			remote, err := repo.Remote(onlineRemoteName)
+			if err != nil {
+				return fmt.Errorf("unable to find the git remote: %w", err)
+			}
+			u, err := url.Parse(remote.Config().URLs[0])
+			if err != nil {
+				return fmt.Errorf("unable to parse the git remote: %w", err)
+			}
+			u.Path = path.Join(u.Path, address)
+			address = u.String()
+		}
+		auth, err := AuthForURL(credentials, address)
+		if err != nil {
+			return err
+		}
+		if auth == nil {
+			gitCred, err := utils.FindAuthForHost(address)
+			if err != nil {
+				return err
+			}
+			if gitCred != nil {
+				auth = &gitCred.Auth
+			}
+		}
+		err = submodule.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+			Auth:              auth,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update the submodule %s: %w", submodule.Config().Name, err)
+		}
+	}
+	return nil
+}
+
 // Push pushes the repository to the remote git server.
 func (r *Repository) Push(ctx context.Context, address, username, password string) error {
 	repo, err := git.PlainOpen(r.path)
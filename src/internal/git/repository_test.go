@@ -59,7 +59,7 @@ func TestRepository(t *testing.T) {
 	newFile.Close()
 	_, err = w.Add(filePath)
 	require.NoError(t, err)
-	_, err = w.Commit("Initial commit", &git.CommitOptions{
+	commit, err := w.Commit("Initial commit", &git.CommitOptions{
 		Author: &object.Signature{
 			Email: "example@example.com",
 		},
@@ -78,6 +78,9 @@ func TestRepository(t *testing.T) {
 	repo, err := Clone(ctx, rootPath, repoAddress, false)
 	require.NoError(t, err)
 	require.Equal(t, filepath.Join(rootPath, expectedPath), repo.Path())
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.Equal(t, commit.String(), head)
 
 	repo, err = Open(rootPath, repoAddress)
 	require.NoError(t, err)
@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
 const artifactTokenName = "zarf-artifact-registry-token"
@@ -35,7 +37,7 @@ func NewClient(endpoint, username, password string) (*Client, error) {
 	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
 	httpClient := &http.Client{
 		Timeout:   10 * time.Second,
-		Transport: transport,
+		Transport: message.WireTransport(transport),
 	}
 	client := &Client{
 		httpClient: httpClient,
@@ -156,18 +158,26 @@ func (g *Client) CreatePackageRegistryToken(ctx context.Context) (string, error)
 	}
 
 	// Create the new token.
+	return g.CreateAccessToken(ctx, artifactTokenName, []string{"read:user", "read:package", "write:package"})
+}
+
+// CreateAccessToken creates an access token with the given name and scopes for the client's user.
+func (g *Client) CreateAccessToken(ctx context.Context, name string, scopes []string) (string, error) {
 	createTokensData := map[string]interface{}{
-		"name":   artifactTokenName,
-		"scopes": []string{"read:user", "read:package", "write:package"},
+		"name":   name,
+		"scopes": scopes,
 	}
 	body, err := json.Marshal(createTokensData)
 	if err != nil {
 		return "", err
 	}
-	b, _, err = g.DoRequest(ctx, http.MethodPost, fmt.Sprintf("/api/v1/users/%s/tokens", g.username), body)
+	b, statusCode, err := g.DoRequest(ctx, http.MethodPost, fmt.Sprintf("/api/v1/users/%s/tokens", g.username), body)
 	if err != nil {
 		return "", err
 	}
+	if statusCode != http.StatusCreated {
+		return "", fmt.Errorf("unable to create access token %s: unexpected status code %d", name, statusCode)
+	}
 	createTokenResponse := struct {
 		Sha1 string `json:"sha1"`
 	}{}
@@ -178,6 +188,18 @@ func (g *Client) CreatePackageRegistryToken(ctx context.Context) (string, error)
 	return createTokenResponse.Sha1, nil
 }
 
+// DeleteAccessToken revokes the access token with the given name of the client's user.
+func (g *Client) DeleteAccessToken(ctx context.Context, name string) error {
+	_, statusCode, err := g.DoRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/users/%s/tokens/%s", g.username, url.PathEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusNoContent && statusCode != http.StatusNotFound {
+		return fmt.Errorf("unable to delete access token %s: unexpected status code %d", name, statusCode)
+	}
+	return nil
+}
+
 // AddReadOnlyUserToRepository adds a read only user to a repository.
 func (g *Client) AddReadOnlyUserToRepository(ctx context.Context, repo, username string) error {
 	addCollabData := map[string]string{
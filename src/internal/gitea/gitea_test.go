@@ -4,6 +4,13 @@
 package gitea
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,3 +25,61 @@ func TestNewClient(t *testing.T) {
 	require.Equal(t, "foo", c.username)
 	require.Equal(t, "bar", c.password)
 }
+
+func TestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	tokens := map[string][]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "zarf-git-read-user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/users/zarf-git-read-user/tokens":
+			req := struct {
+				Name   string   `json:"name"`
+				Scopes []string `json:"scopes"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tokens[req.Name] = req.Scopes
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"name":%q,"sha1":"token-sha"}`, req.Name)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v1/users/zarf-git-read-user/tokens/"):
+			name := strings.TrimPrefix(r.URL.Path, "/api/v1/users/zarf-git-read-user/tokens/")
+			if _, ok := tokens[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(tokens, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL, "zarf-git-read-user", "secret")
+	require.NoError(t, err)
+	token, err := c.CreateAccessToken(context.Background(), "zarf-connect-test", []string{"read:repository"})
+	require.NoError(t, err)
+	require.Equal(t, "token-sha", token)
+	require.Equal(t, map[string][]string{"zarf-connect-test": {"read:repository"}}, tokens)
+
+	require.NoError(t, c.DeleteAccessToken(context.Background(), "zarf-connect-test"))
+	require.Empty(t, tokens)
+	// Deleting a token that is already gone is not an error
+	require.NoError(t, c.DeleteAccessToken(context.Background(), "zarf-connect-test"))
+
+	c, err = NewClient(srv.URL, "zarf-git-read-user", "wrong")
+	require.NoError(t, err)
+	_, err = c.CreateAccessToken(context.Background(), "zarf-connect-test", []string{"read:repository"})
+	require.EqualError(t, err, "unable to create access token zarf-connect-test: unexpected status code 401")
+}
@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package artifacts resolves the Python, NPM and Maven packages of components and publishes them to an artifact server.
+package artifacts
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// The package ecosystems artifacts can be resolved from.
+const (
+	TypePyPI  = "pypi"
+	TypeNPM   = "npm"
+	TypeMaven = "maven"
+)
+
+// The sources packages are downloaded from when an artifact set does not set one.
+const (
+	DefaultPyPISource  = "https://pypi.org"
+	DefaultNPMSource   = "https://registry.npmjs.org"
+	DefaultMavenSource = "https://repo.maven.apache.org/maven2"
+)
+
+// IndexFile is the name of the index of the resolved artifacts in the artifacts directory of a component.
+const IndexFile = "index.json"
+
+// ErrUnknownType is returned for artifacts of an ecosystem that is not supported.
+var ErrUnknownType = errors.New("unknown artifact type")
+
+// Index lists the files of the resolved artifacts of a component.
+type Index struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Artifact is a resolved file of a package.
+type Artifact struct {
+	// The package ecosystem of the artifact.
+	Type string `json:"type"`
+	// The name of the package, groupId:artifactId for maven.
+	Name string `json:"name"`
+	// The version of the package.
+	Version string `json:"version"`
+	// The path of the file relative to the artifacts directory.
+	Path string `json:"path"`
+	// The SHA256 checksum of the file.
+	SHA256 string `json:"sha256"`
+	// The version manifest of npm packages, published together with the tarball.
+	Manifest json.RawMessage `json:"manifest,omitempty"`
+}
+
+// Validate checks that the artifact set is of a known type and that its packages are pinned to exact versions.
+func Validate(set v1alpha1.ZarfArtifacts) error {
+	var errs []error
+	for _, pkg := range set.Packages {
+		var err error
+		switch set.Type {
+		case TypePyPI:
+			_, _, err = parsePyPIPackage(pkg)
+		case TypeNPM:
+			_, _, err = parseNPMPackage(pkg)
+		case TypeMaven:
+			_, err = parseMavenPackage(pkg)
+		default:
+			return fmt.Errorf("%w %q", ErrUnknownType, set.Type)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Resolve downloads the files of the packages of the artifact set into dir and returns them. Each file is verified
+// against the checksum published by the source. Metadata is downloaded into tmpDir.
+func Resolve(ctx context.Context, set v1alpha1.ZarfArtifacts, dir, tmpDir string, opts utils.DownloadOptions) ([]Artifact, error) {
+	if err := Validate(set); err != nil {
+		return nil, err
+	}
+	r := resolver{dir: dir, tmpDir: tmpDir, opts: opts}
+	var resolved []Artifact
+	for _, pkg := range set.Packages {
+		var artifacts []Artifact
+		var err error
+		switch set.Type {
+		case TypePyPI:
+			artifacts, err = r.resolvePyPI(ctx, sourceOrDefault(set.Source, DefaultPyPISource), pkg)
+		case TypeNPM:
+			artifacts, err = r.resolveNPM(ctx, sourceOrDefault(set.Source, DefaultNPMSource), pkg)
+		case TypeMaven:
+			artifacts, err = r.resolveMaven(ctx, sourceOrDefault(set.Source, DefaultMavenSource), pkg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve the %s package %s: %w", set.Type, pkg, err)
+		}
+		resolved = append(resolved, artifacts...)
+	}
+	return resolved, nil
+}
+
+// WriteIndex writes the index of the artifacts to dir, artifacts with the same path are only listed once.
+func WriteIndex(dir string, artifacts []Artifact) error {
+	index := Index{Artifacts: []Artifact{}}
+	seen := map[string]bool{}
+	for _, artifact := range artifacts {
+		if seen[artifact.Path] {
+			continue
+		}
+		seen[artifact.Path] = true
+		index.Artifacts = append(index.Artifacts, artifact)
+	}
+	// The index is not indented as that would reformat the npm manifests it holds
+	b, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, IndexFile), b, helpers.ReadWriteUser)
+}
+
+// ReadIndex reads the index of the artifacts in dir.
+func ReadIndex(dir string) (Index, error) {
+	b, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return Index{}, err
+	}
+	var index Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return Index{}, fmt.Errorf("unable to read the index of the artifacts: %w", err)
+	}
+	return index, nil
+}
+
+// resolver downloads packages into the artifacts directory of a component.
+type resolver struct {
+	dir    string
+	tmpDir string
+	opts   utils.DownloadOptions
+}
+
+// downloadJSON downloads the JSON document at src and decodes it into v.
+func (r resolver) downloadJSON(ctx context.Context, src string, v any) error {
+	f, err := os.CreateTemp(r.tmpDir, "metadata-*.json")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := downloadFile(ctx, src, f.Name(), r.opts); err != nil {
+		return err
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("unable to read the metadata at %s: %w", src, err)
+	}
+	return nil
+}
+
+// download downloads src to the path relative to the artifacts directory and returns the artifact for it, the file
+// is verified with the hash when the expected digest is not empty.
+func (r resolver) download(ctx context.Context, src string, artifact Artifact, h hash.Hash, expected string) (Artifact, error) {
+	dst := filepath.Join(r.dir, filepath.FromSlash(artifact.Path))
+	if err := downloadFile(ctx, src, dst, r.opts); err != nil {
+		return Artifact{}, err
+	}
+	if h != nil {
+		digest, err := fileDigest(dst, h)
+		if err != nil {
+			return Artifact{}, err
+		}
+		if !strings.EqualFold(hex.EncodeToString(digest), expected) {
+			return Artifact{}, fmt.Errorf("checksum mismatch for %s: expected %s, got %x", src, expected, digest)
+		}
+	}
+	sum, err := helpers.GetSHA256OfFile(dst)
+	if err != nil {
+		return Artifact{}, err
+	}
+	artifact.SHA256 = sum
+	return artifact, nil
+}
+
+// Publisher publishes artifacts with the package registry API of Gitea.
+type Publisher struct {
+	// The address of the package registry of the owner of the packages, e.g. http://gitea:3000/api/packages/zarf-git-user.
+	Address string
+	// The username and password, or access token, of a user that can publish packages.
+	Username string
+	Password string
+	// The client requests are sent with, http.DefaultClient when nil.
+	Client *http.Client
+}
+
+// Publish publishes the artifacts in dir and returns the number of files published, files that the artifact server
+// already has are skipped.
+func (p Publisher) Publish(ctx context.Context, dir string, artifacts []Artifact) (int, error) {
+	latest := latestNPMVersions(artifacts)
+	published := 0
+	for _, artifact := range artifacts {
+		var ok bool
+		var err error
+		switch artifact.Type {
+		case TypePyPI:
+			ok, err = p.publishPyPI(ctx, dir, artifact)
+		case TypeNPM:
+			ok, err = p.publishNPM(ctx, dir, artifact, latest[artifact.Name] == artifact.Version)
+		case TypeMaven:
+			ok, err = p.publishMaven(ctx, dir, artifact)
+		default:
+			err = fmt.Errorf("%w %q", ErrUnknownType, artifact.Type)
+		}
+		if err != nil {
+			return published, fmt.Errorf("unable to publish %s: %w", artifact.Path, err)
+		}
+		if ok {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// do sends a request to the artifact server and returns the status code and body of the response.
+func (p Publisher) do(ctx context.Context, method, path, contentType string, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(p.Address, "/")+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if p.Username != "" || p.Password != "" {
+		req.SetBasicAuth(p.Username, p.Password)
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, b, nil
+}
+
+// statusError returns the error for a response the artifact server rejected.
+func statusError(status int, body []byte) error {
+	return fmt.Errorf("the artifact server responded with %d %s: %s", status, http.StatusText(status), strings.TrimSpace(string(body)))
+}
+
+// downloadFile downloads src to dst. Package URLs often contain an @, as in npm scopes, which would be read as the
+// start of a checksum, so the URL is given an empty checksum that is not verified.
+func downloadFile(ctx context.Context, src, dst string, opts utils.DownloadOptions) error {
+	return utils.DownloadToFileWithOptions(ctx, src+"@", dst, "", opts)
+}
+
+func sourceOrDefault(source, def string) string {
+	if source == "" {
+		return def
+	}
+	return strings.TrimSuffix(source, "/")
+}
+
+func fileDigest(path string, h hash.Hash) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
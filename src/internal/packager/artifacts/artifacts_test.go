@@ -0,0 +1,394 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package artifacts
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		set         v1alpha1.ZarfArtifacts
+		expectedErr string
+	}{
+		{
+			name: "pinned packages",
+			set:  v1alpha1.ZarfArtifacts{Type: TypePyPI, Packages: []string{"requests==2.32.3", "Flask_Cors==4.0.1"}},
+		},
+		{
+			name:        "pypi range",
+			set:         v1alpha1.ZarfArtifacts{Type: TypePyPI, Packages: []string{"requests>=2.0"}},
+			expectedErr: `pypi package "requests>=2.0" must be pinned as name==version`,
+		},
+		{
+			name: "scoped npm package",
+			set:  v1alpha1.ZarfArtifacts{Type: TypeNPM, Packages: []string{"@types/node@20.14.2", "lodash@4.17.21"}},
+		},
+		{
+			name:        "npm range",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeNPM, Packages: []string{"lodash@^4.17.0"}},
+			expectedErr: `npm package "lodash@^4.17.0" must be pinned to an exact version`,
+		},
+		{
+			name:        "npm without version",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeNPM, Packages: []string{"@types/node"}},
+			expectedErr: `npm package "@types/node" must be pinned as name@version`,
+		},
+		{
+			name: "maven coordinates",
+			set:  v1alpha1.ZarfArtifacts{Type: TypeMaven, Packages: []string{"org.apache.commons:commons-lang3:3.14.0", "org.example:app:1.0.0:war:sources"}},
+		},
+		{
+			name:        "maven snapshot",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeMaven, Packages: []string{"org.example:app:1.0.0-SNAPSHOT"}},
+			expectedErr: `maven package "org.example:app:1.0.0-SNAPSHOT" must be pinned to a release version`,
+		},
+		{
+			name:        "maven without version",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeMaven, Packages: []string{"org.example:app"}},
+			expectedErr: `maven package "org.example:app" must be pinned as groupId:artifactId:version[:packaging[:classifier]]`,
+		},
+		{
+			name:        "unknown type",
+			set:         v1alpha1.ZarfArtifacts{Type: "cargo", Packages: []string{"serde@1.0.0"}},
+			expectedErr: `unknown artifact type "cargo"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tt.set)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestMavenCoordinateFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pkg      string
+		dir      string
+		expected []string
+	}{
+		{
+			name:     "jar",
+			pkg:      "org.apache.commons:commons-lang3:3.14.0",
+			dir:      "org/apache/commons/commons-lang3/3.14.0",
+			expected: []string{"commons-lang3-3.14.0.pom", "commons-lang3-3.14.0.jar"},
+		},
+		{
+			name:     "pom",
+			pkg:      "org.example:parent:2.0.0:pom",
+			dir:      "org/example/parent/2.0.0",
+			expected: []string{"parent-2.0.0.pom"},
+		},
+		{
+			name:     "classifier",
+			pkg:      "org.example:app:1.0.0:jar:sources",
+			dir:      "org/example/app/1.0.0",
+			expected: []string{"app-1.0.0.pom", "app-1.0.0-sources.jar"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			coordinate, err := parseMavenPackage(tt.pkg)
+			require.NoError(t, err)
+			require.Equal(t, tt.dir, coordinate.dir())
+			require.Equal(t, tt.expected, coordinate.files())
+		})
+	}
+}
+
+// newSourceServer serves a PyPI release, an npm package and a Maven artifact.
+func newSourceServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	wheel := []byte("requests wheel")
+	tarball := []byte("types node tarball")
+	jar := []byte("commons jar")
+	pom := []byte("<project></project>")
+	sha1Hex := func(b []byte) string {
+		sum := sha1.Sum(b)
+		return hex.EncodeToString(sum[:])
+	}
+	sha512Sum := sha512.Sum512(tarball)
+	sha256Sum := sha256.Sum256(wheel)
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/pypi/requests/2.32.3/json", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"urls":[{"filename":"requests-2.32.3-py3-none-any.whl","url":"%s/files/requests-2.32.3-py3-none-any.whl","digests":{"sha256":"%x"}}]}`, server.URL, sha256Sum)
+	})
+	mux.HandleFunc("/files/requests-2.32.3-py3-none-any.whl", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(wheel)
+	})
+	mux.HandleFunc("/@types%2fnode", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"versions":{"20.14.2":{"name":"@types/node","version":"20.14.2","dist":{"tarball":"%s/@types/node/-/node-20.14.2.tgz","integrity":"sha512-%s"}}}}`, server.URL, base64.StdEncoding.EncodeToString(sha512Sum[:]))
+	})
+	mux.HandleFunc("/@types/node/-/node-20.14.2.tgz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(tarball)
+	})
+	mux.HandleFunc("/maven/org/apache/commons/commons-lang3/3.14.0/", func(w http.ResponseWriter, r *http.Request) {
+		switch filepath.Base(r.URL.Path) {
+		case "commons-lang3-3.14.0.jar":
+			w.Write(jar)
+		case "commons-lang3-3.14.0.jar.sha1":
+			fmt.Fprintf(w, "%s  commons-lang3-3.14.0.jar", sha1Hex(jar))
+		case "commons-lang3-3.14.0.pom":
+			w.Write(pom)
+		case "commons-lang3-3.14.0.pom.sha1":
+			fmt.Fprint(w, sha1Hex(pom))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	// An artifact whose checksum does not match
+	mux.HandleFunc("/corrupt/org/example/app/1.0.0/", func(w http.ResponseWriter, r *http.Request) {
+		if filepath.Ext(r.URL.Path) == ".sha1" {
+			fmt.Fprint(w, sha1Hex([]byte("expected")))
+			return
+		}
+		w.Write([]byte("tampered"))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newSourceServer(t)
+
+	tests := []struct {
+		name        string
+		set         v1alpha1.ZarfArtifacts
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "pypi",
+			set:      v1alpha1.ZarfArtifacts{Type: TypePyPI, Source: server.URL, Packages: []string{"Requests==2.32.3"}},
+			expected: []string{"pypi/requests/2.32.3/requests-2.32.3-py3-none-any.whl"},
+		},
+		{
+			name:     "scoped npm package",
+			set:      v1alpha1.ZarfArtifacts{Type: TypeNPM, Source: server.URL, Packages: []string{"@types/node@20.14.2"}},
+			expected: []string{"npm/@types/node/20.14.2/node-20.14.2.tgz"},
+		},
+		{
+			name: "maven",
+			set:  v1alpha1.ZarfArtifacts{Type: TypeMaven, Source: server.URL + "/maven/", Packages: []string{"org.apache.commons:commons-lang3:3.14.0"}},
+			expected: []string{
+				"maven/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.pom",
+				"maven/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.jar",
+			},
+		},
+		{
+			name:        "checksum mismatch",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeMaven, Source: server.URL + "/corrupt", Packages: []string{"org.example:app:1.0.0"}},
+			expectedErr: "checksum mismatch",
+		},
+		{
+			name:        "missing version",
+			set:         v1alpha1.ZarfArtifacts{Type: TypeNPM, Source: server.URL, Packages: []string{"@types/node@1.0.0"}},
+			expectedErr: "the registry has no version 1.0.0",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			resolved, err := Resolve(ctx, tt.set, dir, t.TempDir(), utils.DownloadOptions{})
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			paths := []string{}
+			for _, artifact := range resolved {
+				paths = append(paths, artifact.Path)
+				require.FileExists(t, filepath.Join(dir, filepath.FromSlash(artifact.Path)))
+				sum, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(artifact.Path)))
+				require.NoError(t, err)
+				require.Equal(t, fmt.Sprintf("%x", sha256.Sum256(sum)), artifact.SHA256)
+			}
+			require.Equal(t, tt.expected, paths)
+
+			require.NoError(t, WriteIndex(dir, append(resolved, resolved...)))
+			index, err := ReadIndex(dir)
+			require.NoError(t, err)
+			require.Equal(t, resolved, index.Artifacts)
+		})
+	}
+}
+
+// artifactServer records the packages published to it like the package registry of Gitea.
+type artifactServer struct {
+	mu        sync.Mutex
+	files     map[string][]byte
+	npm       map[string]npmUpload
+	forbidden bool
+}
+
+func (s *artifactServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "zarf-git-user" || pass != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/packages/zarf-git-user/pypi":
+		file, header, err := r.FormFile("content")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(file)
+		if fmt.Sprintf("%x", sha256.Sum256(b)) != r.FormValue("sha256_digest") {
+			http.Error(w, "hash mismatch", http.StatusBadRequest)
+			return
+		}
+		key := fmt.Sprintf("pypi/%s/%s/%s", r.FormValue("name"), r.FormValue("version"), header.Filename)
+		if _, ok := s.files[key]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.files[key] = b
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && len(r.URL.Path) > len("/api/packages/zarf-git-user/npm/"):
+		upload, ok := s.npm[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(upload)
+	case r.Method == http.MethodPut && r.Header.Get("Content-Type") == "application/json":
+		var upload npmUpload
+		if err := json.NewDecoder(r.Body).Decode(&upload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		existing, ok := s.npm[r.URL.Path]
+		if !ok {
+			existing = npmUpload{Name: upload.Name, Versions: map[string]json.RawMessage{}, DistTags: map[string]string{}}
+		}
+		for v, manifest := range upload.Versions {
+			existing.Versions[v] = manifest
+		}
+		for tag, v := range upload.DistTags {
+			existing.DistTags[tag] = v
+		}
+		for name, attachment := range upload.Attachments {
+			s.files["npm/"+upload.Name+"/"+name] = attachment.Data
+		}
+		s.npm[r.URL.Path] = existing
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		key := r.URL.Path[len("/api/packages/zarf-git-user/"):]
+		if _, ok := s.files[key]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		s.files[key] = b
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source := newSourceServer(t)
+	dir := t.TempDir()
+	sets := []v1alpha1.ZarfArtifacts{
+		{Type: TypePyPI, Source: source.URL, Packages: []string{"requests==2.32.3"}},
+		{Type: TypeNPM, Source: source.URL, Packages: []string{"@types/node@20.14.2"}},
+		{Type: TypeMaven, Source: source.URL + "/maven", Packages: []string{"org.apache.commons:commons-lang3:3.14.0"}},
+	}
+	resolved := []Artifact{}
+	for _, set := range sets {
+		artifacts, err := Resolve(ctx, set, dir, t.TempDir(), utils.DownloadOptions{})
+		require.NoError(t, err)
+		resolved = append(resolved, artifacts...)
+	}
+
+	server := &artifactServer{files: map[string][]byte{}, npm: map[string]npmUpload{}}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	publisher := Publisher{Address: ts.URL + "/api/packages/zarf-git-user/", Username: "zarf-git-user", Password: "token"}
+
+	published, err := publisher.Publish(ctx, dir, resolved)
+	require.NoError(t, err)
+	require.Equal(t, 4, published)
+	require.Equal(t, []byte("requests wheel"), server.files["pypi/requests/2.32.3/requests-2.32.3-py3-none-any.whl"])
+	require.Equal(t, []byte("types node tarball"), server.files["npm/@types/node/node-20.14.2.tgz"])
+	require.Equal(t, []byte("commons jar"), server.files["maven/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.jar"])
+	require.Contains(t, server.files, "maven/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.pom")
+	upload := server.npm["/api/packages/zarf-git-user/npm/@types/node"]
+	require.Equal(t, "20.14.2", upload.DistTags["latest"])
+	require.JSONEq(t, string(resolved[1].Manifest), string(upload.Versions["20.14.2"]))
+
+	// Publishing again skips the files the artifact server already has
+	published, err = publisher.Publish(ctx, dir, resolved)
+	require.NoError(t, err)
+	require.Equal(t, 0, published)
+
+	publisher.Password = "wrong"
+	_, err = publisher.Publish(ctx, dir, resolved)
+	require.ErrorContains(t, err, "401 Unauthorized")
+}
+
+func TestLatestNPMVersions(t *testing.T) {
+	t.Parallel()
+
+	artifacts := []Artifact{
+		{Type: TypeNPM, Name: "lodash", Version: "4.17.21"},
+		{Type: TypeNPM, Name: "lodash", Version: "4.17.4"},
+		{Type: TypeNPM, Name: "lodash", Version: "5.0.0-beta.1"},
+		{Type: TypeNPM, Name: "@types/node", Version: "20.14.2"},
+		{Type: TypePyPI, Name: "requests", Version: "2.32.3"},
+	}
+	require.Equal(t, map[string]string{"lodash": "4.17.21", "@types/node": "20.14.2"}, latestNPMVersions(artifacts))
+}
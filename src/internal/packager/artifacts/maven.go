@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package artifacts
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var mavenPartRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// mavenCoordinate identifies a file of a Maven repository.
+type mavenCoordinate struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Packaging  string
+	Classifier string
+}
+
+// parseMavenPackage parses groupId:artifactId:version[:packaging[:classifier]] coordinates, the packaging defaults to jar.
+func parseMavenPackage(pkg string) (mavenCoordinate, error) {
+	parts := strings.Split(pkg, ":")
+	if len(parts) < 3 || len(parts) > 5 {
+		return mavenCoordinate{}, fmt.Errorf("maven package %q must be pinned as groupId:artifactId:version[:packaging[:classifier]]", pkg)
+	}
+	for _, part := range parts {
+		if !mavenPartRegex.MatchString(part) {
+			return mavenCoordinate{}, fmt.Errorf("maven package %q must be pinned as groupId:artifactId:version[:packaging[:classifier]]", pkg)
+		}
+	}
+	coordinate := mavenCoordinate{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2], Packaging: "jar"}
+	if strings.HasSuffix(coordinate.Version, "-SNAPSHOT") || coordinate.Version == "LATEST" || coordinate.Version == "RELEASE" {
+		return mavenCoordinate{}, fmt.Errorf("maven package %q must be pinned to a release version", pkg)
+	}
+	if len(parts) > 3 {
+		coordinate.Packaging = parts[3]
+	}
+	if len(parts) > 4 {
+		coordinate.Classifier = parts[4]
+	}
+	return coordinate, nil
+}
+
+// dir returns the directory of the version in the repository layout.
+func (c mavenCoordinate) dir() string {
+	return path.Join(strings.ReplaceAll(c.GroupID, ".", "/"), c.ArtifactID, c.Version)
+}
+
+// files returns the names of the files of the coordinate, the POM is always included as build tools need it to
+// resolve the artifact.
+func (c mavenCoordinate) files() []string {
+	base := fmt.Sprintf("%s-%s", c.ArtifactID, c.Version)
+	files := []string{base + ".pom"}
+	if c.Packaging == "pom" {
+		return files
+	}
+	if c.Classifier != "" {
+		base = fmt.Sprintf("%s-%s", base, c.Classifier)
+	}
+	return append(files, fmt.Sprintf("%s.%s", base, c.Packaging))
+}
+
+// resolveMaven downloads the POM and the file of the coordinate from the repository, verified with their SHA1 checksums.
+func (r resolver) resolveMaven(ctx context.Context, source, pkg string) ([]Artifact, error) {
+	coordinate, err := parseMavenPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := []Artifact{}
+	for _, file := range coordinate.files() {
+		src := fmt.Sprintf("%s/%s/%s", source, coordinate.dir(), file)
+		checksum, err := r.downloadChecksum(ctx, src+".sha1")
+		if err != nil {
+			return nil, err
+		}
+		artifact := Artifact{
+			Type:    TypeMaven,
+			Name:    coordinate.GroupID + ":" + coordinate.ArtifactID,
+			Version: coordinate.Version,
+			Path:    path.Join(TypeMaven, coordinate.dir(), file),
+		}
+		artifact, err = r.download(ctx, src, artifact, sha1.New(), checksum)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// downloadChecksum returns the hex digest of a Maven checksum file, which may be followed by the name of the file.
+func (r resolver) downloadChecksum(ctx context.Context, src string) (string, error) {
+	f, err := os.CreateTemp(r.tmpDir, "checksum-*")
+	if err != nil {
+		return "", err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := downloadFile(ctx, src, f.Name(), r.opts); err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the checksum %s is empty", src)
+	}
+	return fields[0], nil
+}
+
+// publishMaven uploads the file to its path in the repository layout, unless the artifact server already has it.
+func (p Publisher) publishMaven(ctx context.Context, dir string, artifact Artifact) (bool, error) {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(artifact.Path)))
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	status, body, err := p.do(ctx, http.MethodPut, "/"+artifact.Path, "application/octet-stream", f)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	}
+	return false, statusError(status, body)
+}
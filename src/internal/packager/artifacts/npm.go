@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var npmNameRegex = regexp.MustCompile(`^(@[a-z0-9~][a-z0-9._~-]*/)?[a-z0-9~][a-z0-9._~-]*$`)
+
+// npmPackument is the part of the registry metadata of a package that lists its versions.
+type npmPackument struct {
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+// npmManifest is the part of the version manifest of a package that describes its tarball.
+type npmManifest struct {
+	Dist struct {
+		Tarball   string `json:"tarball"`
+		Integrity string `json:"integrity"`
+		Shasum    string `json:"shasum"`
+	} `json:"dist"`
+}
+
+// npmUpload is the document the npm CLI publishes a version of a package with.
+type npmUpload struct {
+	ID          string                     `json:"_id"`
+	Name        string                     `json:"name"`
+	DistTags    map[string]string          `json:"dist-tags,omitempty"`
+	Versions    map[string]json.RawMessage `json:"versions"`
+	Attachments map[string]npmAttachment   `json:"_attachments"`
+}
+
+type npmAttachment struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+	Length      int    `json:"length"`
+}
+
+// parseNPMPackage returns the name and the version of a name@version package.
+func parseNPMPackage(pkg string) (string, string, error) {
+	idx := strings.LastIndex(pkg, "@")
+	if idx <= 0 || !npmNameRegex.MatchString(pkg[:idx]) {
+		return "", "", fmt.Errorf("npm package %q must be pinned as name@version", pkg)
+	}
+	if _, err := semver.StrictNewVersion(pkg[idx+1:]); err != nil {
+		return "", "", fmt.Errorf("npm package %q must be pinned to an exact version", pkg)
+	}
+	return pkg[:idx], pkg[idx+1:], nil
+}
+
+// resolveNPM downloads the tarball of the version of the package from the registry.
+func (r resolver) resolveNPM(ctx context.Context, source, pkg string) ([]Artifact, error) {
+	name, version, err := parseNPMPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	var packument npmPackument
+	// Registries expect the slash of scoped package names to be escaped
+	if err := r.downloadJSON(ctx, fmt.Sprintf("%s/%s", source, strings.Replace(name, "/", "%2f", 1)), &packument); err != nil {
+		return nil, err
+	}
+	raw, ok := packument.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("the registry has no version %s", version)
+	}
+	var manifest npmManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Dist.Tarball == "" {
+		return nil, fmt.Errorf("the version has no tarball")
+	}
+	h, expected, err := npmChecksum(manifest.Dist.Integrity, manifest.Dist.Shasum)
+	if err != nil {
+		return nil, err
+	}
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, raw); err != nil {
+		return nil, err
+	}
+	artifact := Artifact{
+		Type:     TypeNPM,
+		Name:     name,
+		Version:  version,
+		Path:     path.Join(TypeNPM, name, version, path.Base(manifest.Dist.Tarball)),
+		Manifest: compacted.Bytes(),
+	}
+	artifact, err = r.download(ctx, manifest.Dist.Tarball, artifact, h, expected)
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{artifact}, nil
+}
+
+// npmChecksum returns the hash and hex digest to verify a tarball with, the SHA512 subresource integrity is preferred
+// over the legacy SHA1 shasum.
+func npmChecksum(integrity, shasum string) (hash.Hash, string, error) {
+	for _, sri := range strings.Fields(integrity) {
+		digest, ok := strings.CutPrefix(sri, "sha512-")
+		if !ok {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid integrity %q: %w", sri, err)
+		}
+		return sha512.New(), hex.EncodeToString(b), nil
+	}
+	if shasum != "" {
+		return sha1.New(), shasum, nil
+	}
+	return nil, "", fmt.Errorf("the version has no checksum")
+}
+
+// publishNPM publishes the version of the package as the npm CLI does, unless the artifact server already has it. The
+// latest dist-tag is set for the latest version of the package in the artifacts.
+func (p Publisher) publishNPM(ctx context.Context, dir string, artifact Artifact, latest bool) (bool, error) {
+	status, body, err := p.do(ctx, http.MethodGet, "/npm/"+artifact.Name, "", nil)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		var packument npmPackument
+		if err := json.Unmarshal(body, &packument); err != nil {
+			return false, err
+		}
+		if _, ok := packument.Versions[artifact.Version]; ok {
+			return false, nil
+		}
+	case http.StatusNotFound:
+	default:
+		return false, statusError(status, body)
+	}
+
+	tarball, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(artifact.Path)))
+	if err != nil {
+		return false, err
+	}
+	upload := npmUpload{
+		ID:       artifact.Name,
+		Name:     artifact.Name,
+		Versions: map[string]json.RawMessage{artifact.Version: artifact.Manifest},
+		Attachments: map[string]npmAttachment{
+			path.Base(artifact.Path): {
+				ContentType: "application/octet-stream",
+				Data:        tarball,
+				Length:      len(tarball),
+			},
+		},
+	}
+	if latest {
+		upload.DistTags = map[string]string{"latest": artifact.Version}
+	}
+	b, err := json.Marshal(upload)
+	if err != nil {
+		return false, err
+	}
+	status, body, err = p.do(ctx, http.MethodPut, "/npm/"+artifact.Name, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return false, err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return false, statusError(status, body)
+	}
+	return true, nil
+}
+
+// latestNPMVersions returns the latest version of each npm package in the artifacts, releases are preferred over
+// prereleases as the npm CLI does.
+func latestNPMVersions(artifacts []Artifact) map[string]string {
+	latest := map[string]*semver.Version{}
+	for _, artifact := range artifacts {
+		if artifact.Type != TypeNPM {
+			continue
+		}
+		v, err := semver.NewVersion(artifact.Version)
+		if err != nil {
+			continue
+		}
+		current, ok := latest[artifact.Name]
+		if !ok || (current.Prerelease() != "" && v.Prerelease() == "") || ((current.Prerelease() == "") == (v.Prerelease() == "") && v.GreaterThan(current)) {
+			latest[artifact.Name] = v
+		}
+	}
+	versions := map[string]string{}
+	for name, v := range latest {
+		versions[name] = v.Original()
+	}
+	return versions
+}
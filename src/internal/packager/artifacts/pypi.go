@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	pypiNameRegex    = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	pypiVersionRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+!_-]*$`)
+	pypiSeparators   = regexp.MustCompile(`[-_.]+`)
+)
+
+// pypiRelease is the part of the PyPI JSON API response for a release that lists its files.
+type pypiRelease struct {
+	URLs []struct {
+		Filename string `json:"filename"`
+		URL      string `json:"url"`
+		Digests  struct {
+			SHA256 string `json:"sha256"`
+		} `json:"digests"`
+	} `json:"urls"`
+}
+
+// parsePyPIPackage returns the normalized name and the version of a name==version requirement.
+func parsePyPIPackage(pkg string) (string, string, error) {
+	name, version, ok := strings.Cut(pkg, "==")
+	name = strings.TrimSpace(name)
+	version = strings.TrimSpace(version)
+	if !ok || !pypiNameRegex.MatchString(name) || !pypiVersionRegex.MatchString(version) {
+		return "", "", fmt.Errorf("pypi package %q must be pinned as name==version", pkg)
+	}
+	// Names are normalized as in PEP 503 so requests, Requests and requests_ resolve to the same package
+	return pypiSeparators.ReplaceAllString(strings.ToLower(name), "-"), version, nil
+}
+
+// resolvePyPI downloads every file of the release of the package with the PyPI JSON API.
+func (r resolver) resolvePyPI(ctx context.Context, source, pkg string) ([]Artifact, error) {
+	name, version, err := parsePyPIPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	var release pypiRelease
+	if err := r.downloadJSON(ctx, fmt.Sprintf("%s/pypi/%s/%s/json", source, url.PathEscape(name), url.PathEscape(version)), &release); err != nil {
+		return nil, err
+	}
+	if len(release.URLs) == 0 {
+		return nil, fmt.Errorf("the release has no files")
+	}
+	artifacts := []Artifact{}
+	for _, file := range release.URLs {
+		artifact := Artifact{
+			Type:    TypePyPI,
+			Name:    name,
+			Version: version,
+			Path:    path.Join(TypePyPI, name, version, path.Base(file.Filename)),
+		}
+		artifact, err := r.download(ctx, file.URL, artifact, sha256.New(), file.Digests.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// publishPyPI uploads the file with the form of the legacy PyPI upload API, as twine does.
+func (p Publisher) publishPyPI(ctx context.Context, dir string, artifact Artifact) (bool, error) {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(artifact.Path)))
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writePyPIForm(form, f, artifact))
+	}()
+	status, body, err := p.do(ctx, http.MethodPost, "/pypi", form.FormDataContentType(), pr)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	}
+	return false, statusError(status, body)
+}
+
+func writePyPIForm(form *multipart.Writer, content io.Reader, artifact Artifact) error {
+	fields := [][2]string{
+		{":action", "file_upload"},
+		{"protocol_version", "1"},
+		{"name", artifact.Name},
+		{"version", artifact.Version},
+		{"sha256_digest", artifact.SHA256},
+	}
+	for _, field := range fields {
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile("content", path.Base(artifact.Path))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return err
+	}
+	return form.Close()
+}
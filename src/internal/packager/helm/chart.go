@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/faultinject"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -66,6 +67,10 @@ func (h *Helm) InstallOrUpgradeChart(ctx context.Context) (types.ConnectStrings,
 
 		spinner.Updatef("Checking for existing helm deployment")
 
+		if err := faultinject.Trigger(faultinject.HelmTimeout); err != nil {
+			return err
+		}
+
 		if errors.Is(histErr, driver.ErrReleaseNotFound) {
 			// No prior release, try to install it.
 			spinner.Updatef("Attempting chart installation")
@@ -84,12 +89,21 @@ func (h *Helm) InstallOrUpgradeChart(ctx context.Context) (types.ConnectStrings,
 		}
 
 		if err != nil {
+			// Templates render the same way on every attempt so there is no point in retrying.
+			if err := h.diagnoseRenderError(err); isRenderError(err) {
+				return retry.Unrecoverable(err)
+			}
 			return err
 		}
 
 		spinner.Success()
 		return nil
 	}, retry.Context(ctx), retry.Attempts(uint(h.retries)), retry.Delay(500*time.Millisecond))
+	// Nothing was applied when the chart failed to render, so there is no release to roll back.
+	var renderErr *ChartRenderError
+	if errors.As(err, &renderErr) {
+		return nil, "", renderErr
+	}
 	if err != nil {
 		releases, _ := histClient.Run(h.chart.ReleaseName)
 		previouslyDeployedVersion := 0
@@ -174,6 +188,9 @@ func (h *Helm) TemplateChart(ctx context.Context) (manifest string, chartValues
 	// Perform the loadedChart installation.
 	templatedChart, err := client.Run(loadedChart, chartValues)
 	if err != nil {
+		if err := h.diagnoseRenderError(err); isRenderError(err) {
+			return "", nil, err
+		}
 		return "", nil, fmt.Errorf("error generating helm chart template: %w", err)
 	}
 
@@ -0,0 +1,411 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+const (
+	notSetValue    = "<not set>"
+	sanitizedValue = "**sanitized**"
+	maxValueLength = 200
+)
+
+var (
+	// template: chart/templates/a.yaml:12:20: executing "chart/templates/a.yaml" at <.Values.a.b>: nil pointer evaluating interface {}.b
+	execErrorRegex = regexp.MustCompile(`template: ([^\s:]+):(\d+)(?::(\d+))?: executing "[^"]*" at <([^>]*)>: (.*)`)
+	// parse error at (chart/templates/a.yaml:5): function "foo" not defined
+	// execution error at (chart/templates/a.yaml:3:4): a value is required
+	locatedErrorRegex = regexp.MustCompile(`(?:parse|execution) error at \(([^\s:]+):(\d+)(?::(\d+))?\): (.*)`)
+	// parse error in (chart/templates/a.yaml): unexpected EOF
+	unlocatedErrorRegex = regexp.MustCompile(`(?:parse|execution) error in \(([^\s)]+)\): (.*)`)
+	// YAML parse error on chart/templates/a.yaml: error converting YAML to JSON: yaml: line 4: could not find expected ':'
+	yamlErrorRegex = regexp.MustCompile(`YAML parse error on ([^\s:]+): (?:error converting YAML to JSON: )?(?:yaml: line (\d+): )?(.*)`)
+	valuesRefRegex = regexp.MustCompile(`\.Values((?:\.[A-Za-z0-9_-]+)+)`)
+)
+
+// ChartRenderError is a failure to render the templates of a chart, annotated with where it failed and the values involved.
+type ChartRenderError struct {
+	// The name of the chart that failed to render
+	Chart string
+	// The template that failed, including the chart name prefix Helm uses (e.g. podinfo/templates/deployment.yaml)
+	Template string
+	// The line of the template (or of its rendered output for YAML errors) that failed, 0 when unknown
+	Line int
+	// The column of the template that failed, 0 when unknown
+	Column int
+	// Whether Line refers to the rendered output of the template rather than its source
+	Rendered bool
+	// The contents of the failing line
+	Source string
+	// The reason Helm gave for the failure
+	Reason string
+	// The chart values referenced at the failing location with their values
+	Values [][2]string
+	// The Zarf chart variables of the chart with their values
+	Variables [][2]string
+	// The directory the rendered output and values were written to, if any
+	DebugDir string
+
+	err error
+}
+
+// Error returns the structured error block describing the render failure.
+func (e *ChartRenderError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unable to render the helm chart %s: %s", e.Chart, e.Reason)
+	fmt.Fprintf(&sb, "\n  template:  %s", e.Template)
+	if e.Line > 0 {
+		location := strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location = fmt.Sprintf("%d:%d", e.Line, e.Column)
+		}
+		if e.Rendered {
+			location += " (of the rendered output)"
+		}
+		fmt.Fprintf(&sb, "\n  line:      %s", location)
+	}
+	if e.Source != "" {
+		fmt.Fprintf(&sb, "\n  source:    %s", e.Source)
+	}
+	if len(e.Values) > 0 {
+		sb.WriteString("\n  values:")
+		for _, v := range e.Values {
+			fmt.Fprintf(&sb, "\n    .Values.%s = %s", v[0], v[1])
+		}
+	}
+	if len(e.Variables) > 0 {
+		sb.WriteString("\n  variables:")
+		for _, v := range e.Variables {
+			fmt.Fprintf(&sb, "\n    %s = %s", v[0], v[1])
+		}
+	}
+	if e.DebugDir != "" {
+		fmt.Fprintf(&sb, "\n  rendered output and values written to %s", e.DebugDir)
+	}
+	return sb.String()
+}
+
+// Unwrap returns the original Helm error.
+func (e *ChartRenderError) Unwrap() error {
+	return e.err
+}
+
+// parseRenderError extracts the failing template location from a Helm render error, returning nil if err is not one.
+func parseRenderError(err error) *ChartRenderError {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	renderErr := &ChartRenderError{err: err}
+	if m := execErrorRegex.FindStringSubmatch(msg); m != nil {
+		renderErr.Template = m[1]
+		renderErr.Line, _ = strconv.Atoi(m[2])
+		renderErr.Column, _ = strconv.Atoi(m[3])
+		renderErr.Reason = fmt.Sprintf("at <%s>: %s", m[4], m[5])
+		return renderErr
+	}
+	if m := locatedErrorRegex.FindStringSubmatch(msg); m != nil {
+		renderErr.Template = m[1]
+		renderErr.Line, _ = strconv.Atoi(m[2])
+		renderErr.Column, _ = strconv.Atoi(m[3])
+		renderErr.Reason = m[4]
+		return renderErr
+	}
+	if m := unlocatedErrorRegex.FindStringSubmatch(msg); m != nil {
+		renderErr.Template = m[1]
+		renderErr.Reason = m[2]
+		return renderErr
+	}
+	if m := yamlErrorRegex.FindStringSubmatch(msg); m != nil {
+		renderErr.Template = m[1]
+		renderErr.Line, _ = strconv.Atoi(m[2])
+		renderErr.Rendered = renderErr.Line > 0
+		renderErr.Reason = m[3]
+		return renderErr
+	}
+	return nil
+}
+
+// diagnoseRenderError turns a Helm render error into a ChartRenderError with the failing source line, the values it
+// references and the chart variables, optionally writing what does render to the debug directory. Other errors are
+// returned as is.
+func (h *Helm) diagnoseRenderError(err error) error {
+	renderErr := parseRenderError(err)
+	if renderErr == nil {
+		return err
+	}
+	renderErr.Chart = h.chart.Name
+
+	loadedChart, chartValues, loadErr := h.loadChartData()
+	if loadErr != nil {
+		message.Debugf("Unable to load the chart %s to diagnose the render error: %s", h.chart.Name, loadErr.Error())
+		return renderErr
+	}
+	values, coalesceErr := chartutil.CoalesceValues(loadedChart, chartValues)
+	if coalesceErr != nil {
+		values = chartValues
+	}
+
+	// Render without a cluster so the output of a failed YAML parse can be located, and to fill the debug directory.
+	rendered := h.renderWithoutFailures(loadedChart, chartValues)
+	if renderErr.Rendered {
+		renderErr.Source = lineOf(rendered[renderErr.Template], renderErr.Line)
+	} else if tpl := findTemplate(loadedChart, renderErr.Template); tpl != nil {
+		renderErr.Source = lineOf(string(tpl.Data), renderErr.Line)
+	}
+
+	sensitive := h.sensitiveValues()
+	refs := valuesRefRegex.FindAllStringSubmatch(renderErr.Reason+"\n"+renderErr.Source, -1)
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		valuePath := strings.TrimPrefix(ref[1], ".")
+		if seen[valuePath] {
+			continue
+		}
+		seen[valuePath] = true
+		renderErr.Values = append(renderErr.Values, [2]string{valuePath, formatValue(lookupValue(values, valuePath), sensitive)})
+	}
+
+	for _, variable := range h.chart.Variables {
+		value := notSetValue
+		if h.variableConfig != nil {
+			if setVar, ok := h.variableConfig.GetSetVariable(variable.Name); ok && setVar != nil {
+				value = strconv.Quote(setVar.Value)
+				if setVar.Sensitive {
+					value = sanitizedValue
+				}
+			}
+		}
+		renderErr.Variables = append(renderErr.Variables, [2]string{fmt.Sprintf("%s (%s)", variable.Name, variable.Path), value})
+	}
+
+	if h.cfg != nil && h.cfg.DeployOpts.HelmDebugDir != "" {
+		dir := filepath.Join(h.cfg.DeployOpts.HelmDebugDir, h.chart.Name)
+		if dumpErr := writeRenderDebug(dir, rendered, values); dumpErr != nil {
+			message.WarnErrf(dumpErr, "Unable to write the rendered output of the chart %s to %s", h.chart.Name, dir)
+		} else {
+			renderErr.DebugDir = dir
+		}
+	}
+
+	return renderErr
+}
+
+// renderWithoutFailures renders the chart with the Helm engine and no cluster, dropping templates that fail to render
+// until the rest of the chart renders.
+func (h *Helm) renderWithoutFailures(loadedChart *chart.Chart, chartValues chartutil.Values) map[string]string {
+	releaseName := h.chart.ReleaseName
+	if releaseName == "" {
+		releaseName = h.chart.Name
+	}
+	caps := chartutil.DefaultCapabilities.Copy()
+	if h.kubeVersion != "" {
+		if kubeVersion, err := chartutil.ParseKubeVersion(h.kubeVersion); err == nil {
+			caps.KubeVersion = *kubeVersion
+		}
+	}
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: h.chart.Namespace, Revision: 1, IsInstall: true}
+
+	ch := cloneChart(loadedChart)
+	dropped := []string{}
+	for {
+		renderValues, err := chartutil.ToRenderValues(ch, chartValues, options, caps)
+		if err != nil {
+			return map[string]string{}
+		}
+		rendered, err := engine.Render(ch, renderValues)
+		if err == nil {
+			return rendered
+		}
+		renderErr := parseRenderError(err)
+		if renderErr == nil || slices.Contains(dropped, renderErr.Template) || !dropTemplate(ch, renderErr.Template) {
+			return map[string]string{}
+		}
+		dropped = append(dropped, renderErr.Template)
+	}
+}
+
+// writeRenderDebug writes the rendered templates and the computed values of a chart to dir.
+func writeRenderDebug(dir string, rendered map[string]string, values chartutil.Values) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for name, content := range rendered {
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, []byte(content), 0o600); err != nil {
+			return err
+		}
+	}
+	b, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "values.yaml"), b, 0o600)
+}
+
+// sensitiveValues returns the values of the sensitive Zarf variables so they are never printed.
+func (h *Helm) sensitiveValues() []string {
+	sensitive := []string{}
+	if h.variableConfig == nil {
+		return sensitive
+	}
+	for _, template := range h.variableConfig.GetAllTemplates() {
+		if template.Sensitive && template.Value != "" {
+			sensitive = append(sensitive, template.Value)
+		}
+	}
+	return sensitive
+}
+
+// findTemplate finds a template by the name Helm reports it under, descending into subcharts.
+func findTemplate(ch *chart.Chart, name string) *chart.File {
+	rel, ok := strings.CutPrefix(name, ch.Name()+"/")
+	if !ok {
+		return nil
+	}
+	for _, tpl := range ch.Templates {
+		if tpl.Name == rel {
+			return tpl
+		}
+	}
+	if sub, ok := strings.CutPrefix(rel, "charts/"); ok {
+		for _, dep := range ch.Dependencies() {
+			if tpl := findTemplate(dep, sub); tpl != nil {
+				return tpl
+			}
+		}
+	}
+	return nil
+}
+
+// dropTemplate removes a template by the name Helm reports it under, returning whether it was found.
+func dropTemplate(ch *chart.Chart, name string) bool {
+	rel, ok := strings.CutPrefix(name, ch.Name()+"/")
+	if !ok {
+		return false
+	}
+	for i, tpl := range ch.Templates {
+		if tpl.Name == rel {
+			ch.Templates = slices.Delete(ch.Templates, i, i+1)
+			return true
+		}
+	}
+	if sub, ok := strings.CutPrefix(rel, "charts/"); ok {
+		for _, dep := range ch.Dependencies() {
+			if dropTemplate(dep, sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cloneChart copies a chart and its subcharts deep enough that templates can be dropped without changing the original.
+func cloneChart(ch *chart.Chart) *chart.Chart {
+	c := &chart.Chart{
+		Raw:       ch.Raw,
+		Metadata:  ch.Metadata,
+		Lock:      ch.Lock,
+		Templates: slices.Clone(ch.Templates),
+		Values:    ch.Values,
+		Schema:    ch.Schema,
+		Files:     ch.Files,
+	}
+	deps := []*chart.Chart{}
+	for _, dep := range ch.Dependencies() {
+		deps = append(deps, cloneChart(dep))
+	}
+	c.SetDependencies(deps...)
+	return c
+}
+
+// lookupValue returns the value at a dot separated path, or nil if any part of it is not set.
+func lookupValue(values map[string]any, valuePath string) any {
+	var current any = values
+	for _, key := range strings.Split(valuePath, ".") {
+		var table map[string]any
+		switch t := current.(type) {
+		case map[string]any:
+			table = t
+		case chartutil.Values:
+			table = t
+		default:
+			return nil
+		}
+		next, ok := table[key]
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// formatValue prints a value on a single line, hiding it if it contains the value of a sensitive variable.
+func formatValue(value any, sensitive []string) string {
+	if value == nil {
+		return notSetValue
+	}
+	var out string
+	if s, ok := value.(string); ok {
+		out = strconv.Quote(s)
+	} else {
+		b, err := yaml.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		b, err = yaml.YAMLToJSON(b)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		out = string(b)
+	}
+	for _, s := range sensitive {
+		if strings.Contains(out, s) {
+			return sanitizedValue
+		}
+	}
+	if len(out) > maxValueLength {
+		out = out[:maxValueLength] + "..."
+	}
+	return out
+}
+
+// lineOf returns the trimmed line n (1-indexed) of s, or an empty string if there is none.
+func lineOf(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[n-1])
+}
+
+// isRenderError reports whether err is a chart render failure, these fail the same way on every attempt.
+func isRenderError(err error) bool {
+	var renderErr *ChartRenderError
+	return errors.As(err, &renderErr)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  color: {{ .Values.ui.color }}
+`
+
+func TestDiagnoseRenderError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		template         string
+		values           map[string]any
+		expectedTemplate string
+		expectedLine     int
+		expectedRendered bool
+		expectedSource   string
+		expectedReason   string
+		expectedValues   [][2]string
+	}{
+		{
+			name:             "nil pointer",
+			template:         "name: {{ .Values.image.repository.name }}\n",
+			expectedTemplate: "podinfo/templates/broken.yaml",
+			expectedLine:     1,
+			expectedSource:   "name: {{ .Values.image.repository.name }}",
+			expectedReason:   "at <.Values.image.repository.name>: nil pointer evaluating interface {}.repository",
+			expectedValues:   [][2]string{{"image.repository.name", notSetValue}},
+		},
+		{
+			name:             "required value",
+			template:         "kind: ConfigMap\nname: {{ required \"a tag is required\" .Values.image.tag }}\n",
+			values:           map[string]any{"image": map[string]any{}},
+			expectedTemplate: "podinfo/templates/broken.yaml",
+			expectedLine:     2,
+			expectedSource:   `name: {{ required "a tag is required" .Values.image.tag }}`,
+			expectedReason:   "a tag is required",
+			expectedValues:   [][2]string{{"image.tag", notSetValue}},
+		},
+		{
+			name:             "undefined function",
+			template:         "kind: ConfigMap\n\nname: {{ nope .Values.image }}\n",
+			expectedTemplate: "podinfo/templates/broken.yaml",
+			expectedLine:     3,
+			expectedSource:   "name: {{ nope .Values.image }}",
+			expectedReason:   `function "nope" not defined`,
+			expectedValues:   [][2]string{{"image", `{"repository":"ghcr.io/stefanprodan/podinfo"}`}},
+			values:           map[string]any{"image": map[string]any{"repository": "ghcr.io/stefanprodan/podinfo"}},
+		},
+		{
+			name:             "invalid yaml",
+			template:         "kind: ConfigMap\nmetadata:\n  name: {{ .Values.name }}\n data: {}\n",
+			values:           map[string]any{"name": "podinfo"},
+			expectedTemplate: "podinfo/templates/broken.yaml",
+			expectedLine:     3,
+			expectedRendered: true,
+			expectedSource:   "name: podinfo",
+			expectedReason:   "did not find expected key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			debugDir := t.TempDir()
+			h := newTestHelm(t, tt.template, tt.values, debugDir)
+			err := h.diagnoseRenderError(renderTestChart(t, h))
+			require.True(t, isRenderError(err), err.Error())
+
+			var renderErr *ChartRenderError
+			require.ErrorAs(t, err, &renderErr)
+			require.Equal(t, "podinfo", renderErr.Chart)
+			require.Equal(t, tt.expectedTemplate, renderErr.Template)
+			require.Equal(t, tt.expectedLine, renderErr.Line)
+			require.Equal(t, tt.expectedRendered, renderErr.Rendered)
+			require.Equal(t, tt.expectedSource, renderErr.Source)
+			require.Contains(t, renderErr.Reason, tt.expectedReason)
+			require.Equal(t, tt.expectedValues, renderErr.Values)
+			require.Equal(t, [][2]string{{"UI_COLOR (ui.color)", `"blue"`}, {"PASSWORD (auth.password)", sanitizedValue}}, renderErr.Variables)
+
+			// The templates that do render and the computed values are written to the debug directory.
+			require.Equal(t, filepath.Join(debugDir, "podinfo"), renderErr.DebugDir)
+			b, err := os.ReadFile(filepath.Join(renderErr.DebugDir, "podinfo", "templates", "configmap.yaml"))
+			require.NoError(t, err)
+			require.Contains(t, string(b), "color: blue")
+			_, err = os.Stat(filepath.Join(renderErr.DebugDir, "values.yaml"))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDiagnoseRenderErrorPassthrough(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHelm(t, "", nil, "")
+	err := h.diagnoseRenderError(os.ErrNotExist)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.False(t, isRenderError(err))
+}
+
+func TestFormatValue(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, notSetValue, formatValue(nil, nil))
+	require.Equal(t, `"blue"`, formatValue("blue", nil))
+	require.Equal(t, "3", formatValue(3, nil))
+	require.Equal(t, `{"user":"admin"}`, formatValue(map[string]any{"user": "admin"}, nil))
+	require.Equal(t, sanitizedValue, formatValue(map[string]any{"password": "hunter2"}, []string{"hunter2"}))
+}
+
+func newTestHelm(t *testing.T, template string, values map[string]any, debugDir string) *Helm {
+	t.Helper()
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "podinfo", Version: "6.4.0"},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte(configMapTemplate)},
+			{Name: "templates/broken.yaml", Data: []byte(template)},
+		},
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	values["ui"] = map[string]any{"color": "blue"}
+
+	variableConfig := variables.New("", nil, nil)
+	variableConfig.SetVariable("UI_COLOR", "blue", false, false, v1alpha1.RawVariableType)
+	variableConfig.SetVariable("PASSWORD", "hunter2", true, false, v1alpha1.RawVariableType)
+
+	h := New(v1alpha1.ZarfChart{
+		Name:      "podinfo",
+		Namespace: "podinfo",
+		Variables: []v1alpha1.ZarfChartVariable{
+			{Name: "UI_COLOR", Path: "ui.color"},
+			{Name: "PASSWORD", Path: "auth.password"},
+		},
+	}, "", "", WithVariableConfig(variableConfig))
+	h.cfg = &types.PackagerConfig{DeployOpts: types.ZarfDeployOptions{HelmDebugDir: debugDir}}
+	h.chartOverride = ch
+	h.valuesOverrides = values
+	return h
+}
+
+// renderTestChart renders the chart the way a Helm install does, returning the error Helm would.
+func renderTestChart(t *testing.T, h *Helm) error {
+	t.Helper()
+
+	renderValues, err := chartutil.ToRenderValues(h.chartOverride, h.valuesOverrides, chartutil.ReleaseOptions{Name: "podinfo", Namespace: "podinfo"}, chartutil.DefaultCapabilities)
+	require.NoError(t, err)
+	rendered, err := engine.Render(h.chartOverride, renderValues)
+	if err != nil {
+		return err
+	}
+	_, _, err = releaseutil.SortManifests(rendered, nil, releaseutil.InstallOrder)
+	require.Error(t, err)
+	return err
+}
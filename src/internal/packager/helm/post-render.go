@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/config"
@@ -93,6 +94,7 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error re-rendering helm output: %w", err)
 	}
+	r.debugManifests(resources)
 
 	finalManifestsOutput := bytes.NewBuffer(nil)
 
@@ -106,6 +108,10 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 		if err := r.adoptAndUpdateNamespaces(ctx); err != nil {
 			return nil, err
 		}
+
+		if err := r.addNamespacePullCredentials(ctx); err != nil {
+			return nil, err
+		}
 	} else {
 		for _, resource := range resources {
 			fmt.Fprintf(finalManifestsOutput, "---\n# Source: %s\n%s\n", resource.Name, resource.Content)
@@ -116,6 +122,47 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 	return finalManifestsOutput, nil
 }
 
+// debugManifests prints the manifests rendered from the chart at the chart verbosity, with the data of secrets and the
+// values of sensitive variables hidden.
+func (r *renderer) debugManifests(resources []releaseutil.Manifest) {
+	if !message.IsVerbose(message.VerbosityChart) {
+		return
+	}
+	sensitive := r.sensitiveValues()
+	for _, resource := range resources {
+		content := resource.Content
+		if resource.Head != nil && resource.Head.Kind == "Secret" {
+			content = redactSecretData(content)
+		}
+		for _, s := range sensitive {
+			content = strings.ReplaceAll(content, s, sanitizedValue)
+		}
+		message.Debugf("Rendered %s of the chart %s:\n%s", resource.Name, r.chart.Name, content)
+	}
+}
+
+// redactSecretData returns the manifest of a secret with the values of its data and stringData hidden.
+func redactSecretData(manifest string) string {
+	secret := map[string]any{}
+	if err := yaml.Unmarshal([]byte(manifest), &secret); err != nil {
+		return "# The data of the secret is hidden as it could not be parsed"
+	}
+	for _, key := range []string{"data", "stringData"} {
+		data, ok := secret[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		for name := range data {
+			data[name] = sanitizedValue
+		}
+	}
+	b, err := yaml.Marshal(secret)
+	if err != nil {
+		return "# The data of the secret is hidden as it could not be parsed"
+	}
+	return string(b)
+}
+
 func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 	c := r.cluster
 	namespaceList, err := r.cluster.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
@@ -125,14 +172,17 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 	for name, namespace := range r.namespaces {
 		// Check to see if this namespace already exists
 		var existingNamespace bool
+		var owner string
 		for _, serverNamespace := range namespaceList.Items {
 			if serverNamespace.Name == name {
 				existingNamespace = true
+				owner = serverNamespace.Annotations[cluster.PackageOwnerAnnotation]
 			}
 		}
 
 		if !existingNamespace {
-			// This is a new namespace, add it
+			// This is a new namespace, add it and record the package as its owner
+			namespace.Annotations = cluster.SetNamespaceOwner(namespace.Annotations, name, r.cfg.Pkg.Metadata.Name)
 			_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
 			if err != nil {
 				return fmt.Errorf("unable to create the missing namespace %s", name)
@@ -143,7 +193,11 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 			if slices.Contains([]string{"default", "kube-node-lease", "kube-public", "kube-system"}, name) {
 				message.Warnf("Refusing to adopt the initial namespace: %s", name)
 			} else {
-				// This is an existing namespace to adopt
+				// This is an existing namespace to adopt, the package owns it unless another package already does
+				if owner == "" {
+					owner = r.cfg.Pkg.Metadata.Name
+				}
+				namespace.Annotations = cluster.SetNamespaceOwner(namespace.Annotations, name, owner)
 				_, err := c.Clientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
 				if err != nil {
 					return fmt.Errorf("unable to adopt the existing namespace %s", name)
@@ -156,6 +210,11 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 			continue
 		}
 
+		if !cluster.ZarfSecretsAllowed(r.state, name) {
+			message.Debugf("Skipping the Zarf-managed secrets for the %s namespace, it is not allowed by the Zarf state", name)
+			continue
+		}
+
 		// Create the secret
 		validRegistrySecret, err := c.GenerateRegistryPullCreds(ctx, name, config.ZarfImagePullSecretName, r.state.RegistryInfo)
 		if err != nil {
@@ -207,6 +266,36 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 	return nil
 }
 
+// addNamespacePullCredentials records the namespaces that were given their own pull-only registry credentials in the
+// state and updates the registry to accept them.
+func (r *renderer) addNamespacePullCredentials(ctx context.Context) error {
+	if !r.state.RegistryInfo.NamespacedPullCredentials {
+		return nil
+	}
+	added := []string{}
+	for name := range r.namespaces {
+		if !cluster.ZarfSecretsAllowed(r.state, name) || slices.Contains(r.state.RegistryInfo.PullNamespaces, name) {
+			continue
+		}
+		added = append(added, name)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	slices.Sort(added)
+	r.state.RegistryInfo.PullNamespaces = append(r.state.RegistryInfo.PullNamespaces, added...)
+	if err := r.cluster.SaveZarfState(ctx, r.state); err != nil {
+		return fmt.Errorf("unable to save the registry credentials of the namespaces %s: %w", strings.Join(added, ", "), err)
+	}
+
+	// The registry only reads its htpasswd file on start, so it is restarted before the pods of the release pull from it
+	registry := *r.Helm
+	if err := registry.UpdateZarfRegistryValues(ctx); err != nil {
+		return fmt.Errorf("unable to add the registry credentials of the namespaces %s: %w", strings.Join(added, ", "), err)
+	}
+	return nil
+}
+
 func (r *renderer) editHelmResources(ctx context.Context, resources []releaseutil.Manifest, finalManifestsOutput *bytes.Buffer) error {
 	dc, err := dynamic.NewForConfig(r.cluster.RestConfig)
 	if err != nil {
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactSecretData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name: "data and stringData",
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: podinfo
+data:
+  password: c2VjcmV0
+stringData:
+  token: secret
+`,
+			expected: `apiVersion: v1
+data:
+  password: '**sanitized**'
+kind: Secret
+metadata:
+  name: podinfo
+stringData:
+  token: '**sanitized**'
+`,
+		},
+		{
+			name:     "invalid manifest",
+			manifest: "data: [",
+			expected: "# The data of the secret is hidden as it could not be parsed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, redactSecretData(tt.manifest))
+		})
+	}
+}
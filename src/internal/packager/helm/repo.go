@@ -6,8 +6,10 @@ package helm
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -155,7 +157,7 @@ func (h *Helm) DownloadPublishedChart(ctx context.Context, cosignKeyPath string)
 
 	// Handle OCI registries
 	if registry.IsOCI(h.chart.URL) {
-		regClient, err = registry.NewClient(registry.ClientOptEnableCache(true))
+		regClient, err = newRegistryClient()
 		if err != nil {
 			return fmt.Errorf("unable to create the new registry client: %w", err)
 		}
@@ -201,6 +203,10 @@ func (h *Helm) DownloadPublishedChart(ctx context.Context, cosignKeyPath string)
 			getter.WithBasicAuth(username, password),
 		},
 	}
+	// OCI charts are pulled with the registry client so they connect to the registry with its TLS configuration
+	if regClient != nil {
+		chartDownloader.Options = append(chartDownloader.Options, getter.WithRegistryClient(regClient))
+	}
 
 	// Download the file into a temp directory since we don't control what name helm creates here
 	temp := filepath.Join(h.chartPath, "temp")
@@ -277,10 +283,21 @@ func (h *Helm) packageValues(ctx context.Context, cosignKeyPath string) error {
 	return nil
 }
 
+// newRegistryClient returns a client for OCI registries that connects to each registry with its TLS configuration from
+// the config file.
+func newRegistryClient() (*registry.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: config.CommonOptions.Insecure} //nolint:gosec // the user asked for insecure connections
+	return registry.NewClient(
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptHTTPClient(&http.Client{Transport: utils.RegistryTLSTransport(transport)}),
+	)
+}
+
 // buildChartDependencies builds the helm chart dependencies
 func (h *Helm) buildChartDependencies() error {
 	// Download and build the specified dependencies
-	regClient, err := registry.NewClient(registry.ClientOptEnableCache(true))
+	regClient, err := newRegistryClient()
 	if err != nil {
 		return fmt.Errorf("unable to create a new registry client: %w", err)
 	}
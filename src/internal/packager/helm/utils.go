@@ -8,6 +8,8 @@ import (
 	"fmt"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -15,6 +17,8 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 
 	"helm.sh/helm/v3/pkg/chart/loader"
 )
@@ -69,8 +73,33 @@ func (h *Helm) createActionConfig(namespace string, spinner *message.Spinner) er
 	// Set the namespace for helm
 	h.settings.SetNamespace(namespace)
 
+	// Route the API server traffic of helm through the same proxy as the cluster
+	proxy, err := cluster.KubeProxyFunc(config.CommonOptions.KubeProxy)
+	if err != nil {
+		return err
+	}
+	if flags, ok := h.settings.RESTClientGetter().(*genericclioptions.ConfigFlags); ok && proxy != nil {
+		wrapConfig := flags.WrapConfigFn
+		flags.WrapConfigFn = func(restConfig *rest.Config) *rest.Config {
+			if wrapConfig != nil {
+				restConfig = wrapConfig(restConfig)
+			}
+			restConfig.Proxy = proxy
+			return restConfig
+		}
+	}
+
+	// Show the debug log of helm, not only the latest line of it in the spinner, at the chart verbosity
+	debugLog := spinner.Updatef
+	if message.IsVerbose(message.VerbosityChart) {
+		debugLog = func(format string, a ...any) {
+			message.Debugf("helm: "+format, a...)
+			spinner.Updatef(format, a...)
+		}
+	}
+
 	// Setup K8s connection
-	err := actionConfig.Init(h.settings.RESTClientGetter(), namespace, "", spinner.Updatef)
+	err = actionConfig.Init(h.settings.RESTClientGetter(), namespace, "", debugLog)
 
 	// Set the actionConfig is the received Helm pointer
 	h.actionConfig = actionConfig
@@ -7,6 +7,7 @@ package helm
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"helm.sh/helm/v3/pkg/action"
@@ -22,22 +23,17 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
-	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 // UpdateZarfRegistryValues updates the Zarf registry deployment with the new state values
 func (h *Helm) UpdateZarfRegistryValues(ctx context.Context) error {
-	pushUser, err := utils.GetHtpasswdString(h.state.RegistryInfo.PushUsername, h.state.RegistryInfo.PushPassword)
+	htpasswd, err := template.RegistryHtpasswdEntries(h.state.RegistryInfo)
 	if err != nil {
-		return fmt.Errorf("error generating htpasswd string: %w", err)
-	}
-	pullUser, err := utils.GetHtpasswdString(h.state.RegistryInfo.PullUsername, h.state.RegistryInfo.PullPassword)
-	if err != nil {
-		return fmt.Errorf("error generating htpasswd string: %w", err)
+		return err
 	}
 	registryValues := map[string]interface{}{
 		"secrets": map[string]interface{}{
-			"htpasswd": fmt.Sprintf("%s\n%s", pushUser, pullUser),
+			"htpasswd": strings.Join(htpasswd, "\n"),
 		},
 	}
 	h.chart = v1alpha1.ZarfChart{
@@ -112,7 +108,7 @@ func (h *Helm) UpdateZarfAgentValues(ctx context.Context) error {
 					Value: agentImage.Tag,
 				},
 			})
-			applicationTemplates, err := template.GetZarfTemplates("zarf-agent", h.state)
+			applicationTemplates, err := template.GetZarfTemplates("zarf-agent", h.state, nil)
 			if err != nil {
 				return fmt.Errorf("error setting up the templates: %w", err)
 			}
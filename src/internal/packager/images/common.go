@@ -5,19 +5,29 @@
 package images
 
 import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
 	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
+// DefaultConcurrency is the number of images pulled and saved at once when no concurrency is configured.
+const DefaultConcurrency = 10
+
 // PullConfig is the configuration for pulling images.
 type PullConfig struct {
 	DestinationDirectory string
@@ -28,7 +38,34 @@ type PullConfig struct {
 
 	RegistryOverrides map[string]string
 
+	// RegistryMirrors are tried, in order, before the upstream registry of each image
+	RegistryMirrors types.RegistryMirrors
+
+	// Proxy is the proxy images are pulled through, the proxy environment variables are used when it is not set
+	Proxy types.ProxyConfig
+
 	CacheDirectory string
+
+	// Digests are the digests images are locked to by reference, pulling errors when an image resolves to another digest
+	Digests map[string]string
+
+	// CacheMaxSize is the size in bytes the layer cache is pruned to after pulling, zero disables pruning
+	CacheMaxSize int64
+
+	// AllPlatforms saves the full image index for images that resolve to one instead of only the image for Arch
+	AllPlatforms bool
+
+	// ContainerdAddress is the containerd socket local images are loaded from, the default and k3s sockets are tried when empty
+	ContainerdAddress string
+
+	// ContainerdNamespace is the containerd namespace local images are loaded from, the default and k8s.io namespaces are tried when empty
+	ContainerdNamespace string
+
+	// Concurrency is the number of images fetched and saved at once, DefaultConcurrency is used when zero
+	Concurrency int
+
+	// Progress receives the progress of the pull, it is rendered in the terminal when nil
+	Progress ProgressReporter
 }
 
 // PushConfig is the configuration for pushing images.
@@ -44,6 +81,12 @@ type PushConfig struct {
 	Arch string
 
 	Retries int
+
+	// Concurrency is the number of layers of an image pushed at once, crane's default is used when zero
+	Concurrency int
+
+	// Progress receives the progress of the push, it is rendered in the terminal when nil
+	Progress ProgressReporter
 }
 
 // NoopOpt is a no-op option for crane.
@@ -74,12 +117,26 @@ func CommonOpts(arch string) []crane.Option {
 
 	opts = append(opts,
 		crane.WithUserAgent("zarf"),
+		crane.WithAuthFromKeychain(utils.Keychain),
 		crane.WithNoClobber(true),
 		crane.WithJobs(1),
 	)
+
+	// The transport skips TLS verification like crane would with --insecure
+	transport := remote.DefaultTransport.(*http.Transport).Clone()
+	if config.CommonOptions.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint: gosec
+	}
+	opts = append(opts, crane.WithTransport(registryTransport(transport)))
 	return opts
 }
 
+// registryTransport wraps the transport to registries so it connects to each registry with its TLS configuration, backs
+// off when a registry throttles requests, and logs the requests at the highest verbosity.
+func registryTransport(transport *http.Transport) http.RoundTripper {
+	return utils.RateLimitTransport(message.WireTransport(utils.RegistryTLSTransport(transport)))
+}
+
 // WithBasicAuth returns an option for crane that sets basic auth.
 func WithBasicAuth(username, password string) crane.Option {
 	return crane.WithAuth(authn.FromConfig(authn.AuthConfig{
@@ -98,7 +155,35 @@ func WithPushAuth(ri types.RegistryInfo) crane.Option {
 	return WithBasicAuth(ri.PushUsername, ri.PushPassword)
 }
 
-func createPushOpts(cfg PushConfig, pb *message.ProgressBar) []crane.Option {
+// uploadBackoff is how each blob and manifest upload is retried before the push of the image is retried, so a dropped
+// connection only uploads the blob it interrupted again.
+var uploadBackoff = remote.Backoff{
+	Duration: time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// withUploadRetries retries blob and manifest uploads that failed because of the connection to the registry.
+func withUploadRetries(o *crane.Options) {
+	o.Remote = append(o.Remote, remote.WithRetryBackoff(uploadBackoff), remote.WithRetryPredicate(isRetryableUploadError))
+}
+
+// isRetryableUploadError returns whether an upload failed because of the connection to the registry.
+func isRetryableUploadError(err error) bool {
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+	for _, connErr := range []error{io.EOF, io.ErrUnexpectedEOF, net.ErrClosed, syscall.EPIPE, syscall.ECONNRESET, syscall.ECONNREFUSED} {
+		if errors.Is(err, connErr) {
+			return true
+		}
+	}
+	return false
+}
+
+func createPushOpts(cfg PushConfig, pw helpers.ProgressWriter) []crane.Option {
 	opts := CommonOpts(cfg.Arch)
 	opts = append(opts, WithPushAuth(cfg.RegInfo))
 
@@ -107,9 +192,12 @@ func createPushOpts(cfg PushConfig, pb *message.ProgressBar) []crane.Option {
 	// TODO (@WSTARR) This is set to match the TLSHandshakeTimeout to potentially mitigate effects of https://github.com/zarf-dev/zarf/issues/1444
 	transport.ResponseHeaderTimeout = 10 * time.Second
 
-	transportWithProgressBar := helpers.NewTransport(transport, pb)
+	transportWithProgressBar := helpers.NewTransport(registryTransport(transport), pw)
 
-	opts = append(opts, crane.WithTransport(transportWithProgressBar))
+	opts = append(opts, crane.WithTransport(transportWithProgressBar), withUploadRetries)
+	if cfg.Concurrency > 0 {
+		opts = append(opts, crane.WithJobs(cfg.Concurrency))
+	}
 
 	return opts
 }
@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/compression"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// zstdCompressionLevel is the zstd level layers are recompressed with, the zstd default.
+const zstdCompressionLevel = 3
+
+// ociMediaTypes are the OCI equivalents of the Docker media types, as zstd layers are only valid in OCI manifests.
+var ociMediaTypes = map[types.MediaType]types.MediaType{
+	types.DockerManifestSchema2:   types.OCIManifestSchema1,
+	types.DockerConfigJSON:        types.OCIConfigJSON,
+	types.DockerUncompressedLayer: types.OCIUncompressedLayer,
+	types.DockerForeignLayer:      types.OCIRestrictedLayer,
+}
+
+// RecompressZstd recompresses the gzip layers of the images in the OCI layout at path with zstd, replacing the images
+// in the layout and returning them. Images referenced by digest and images saved with their index are left as they
+// are since recompressing changes their digest.
+func RecompressZstd(ctx context.Context, path string, imgs map[transform.Image]v1.Image, concurrency int) (map[transform.Image]v1.Image, error) {
+	zlayers := newLayerConverter(func(layer v1.Layer) (v1.Layer, error) {
+		return tarball.LayerFromOpener(layer.Uncompressed,
+			tarball.WithCompression(compression.ZStd),
+			tarball.WithCompressionLevel(zstdCompressionLevel),
+			tarball.WithMediaType(types.OCILayerZStd))
+	})
+	return convertImages(ctx, path, imgs, concurrency, zlayers.image, "Recompressing image layers with zstd", "Recompressed image layers with zstd")
+}
+
+// imageConverter converts an image, returning the image itself when there is nothing to convert and the size of the
+// converted layers before and after.
+type imageConverter func(img v1.Image) (v1.Image, [2]int64, error)
+
+// convertImages converts the images in the OCI layout at path with convert, replacing the images in the layout and
+// returning them. Images referenced by digest and images saved with their index are left as they are since converting
+// them changes their digest.
+func convertImages(ctx context.Context, path string, imgs map[transform.Image]v1.Image, concurrency int, convert imageConverter, title, done string) (map[transform.Image]v1.Image, error) {
+	lp, err := clayout.FromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	spinner := message.NewProgressSpinner(title)
+	defer spinner.Stop()
+
+	// Images with the same digest share a single converted image.
+	byDigest := map[v1.Hash][]transform.Image{}
+	converted := map[transform.Image]v1.Image{}
+	for info, img := range imgs {
+		converted[info] = img
+		if info.Digest != "" {
+			message.Debugf("Not converting %s as it is referenced by digest", info.Reference)
+			continue
+		}
+		idx, err := utils.LoadOCIImageIndex(path, info)
+		if err != nil {
+			return nil, err
+		}
+		if idx != nil {
+			message.Debugf("Not converting %s as it is saved with its image index", info.Reference)
+			continue
+		}
+		digest, err := img.Digest()
+		if err != nil {
+			return nil, err
+		}
+		byDigest[digest] = append(byDigest[digest], info)
+	}
+
+	var mu sync.Mutex
+	var before, after int64
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for digest, infos := range byDigest {
+		digest, infos := digest, infos
+		eg.Go(func() error {
+			if err := ectx.Err(); err != nil {
+				return err
+			}
+			img := imgs[infos[0]]
+			zimg, saved, err := convert(img)
+			if err != nil {
+				return fmt.Errorf("unable to convert %s: %w", infos[0].Reference, err)
+			}
+			if zimg == img {
+				return nil
+			}
+			if err := lp.WriteImage(zimg); err != nil {
+				return fmt.Errorf("unable to save the converted %s: %w", infos[0].Reference, err)
+			}
+			desc, err := partial.Descriptor(zimg)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err := lp.RemoveDescriptors(match.Digests(digest)); err != nil {
+				return err
+			}
+			for _, info := range infos {
+				d := *desc
+				d.Annotations = map[string]string{
+					ocispec.AnnotationBaseImageName: info.Reference,
+				}
+				if err := lp.AppendDescriptor(d); err != nil {
+					return err
+				}
+				converted[info] = zimg
+			}
+			before += saved[0]
+			after += saved[1]
+			spinner.Updatef("Converted %s", infos[0].Reference)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	spinner.Successf("%s from %s to %s", done, utils.ByteFormat(float64(before), 2), utils.ByteFormat(float64(after), 2))
+	return converted, nil
+}
+
+// layerConverter converts each layer once so layers shared between images stay shared.
+type layerConverter struct {
+	convert func(layer v1.Layer) (v1.Layer, error)
+	// changesContent is whether converted layers hold different uncompressed content, which changes their diff IDs
+	changesContent bool
+
+	mu     sync.Mutex
+	layers map[v1.Hash]func() (v1.Layer, error)
+}
+
+func newLayerConverter(convert func(layer v1.Layer) (v1.Layer, error)) *layerConverter {
+	return &layerConverter{convert: convert, layers: map[v1.Hash]func() (v1.Layer, error){}}
+}
+
+// get returns the converted layer of the gzip layer with digest h.
+func (c *layerConverter) get(h v1.Hash, layer v1.Layer) (v1.Layer, error) {
+	c.mu.Lock()
+	convert, ok := c.layers[h]
+	if !ok {
+		convert = sync.OnceValues(func() (v1.Layer, error) {
+			return c.convert(layer)
+		})
+		c.layers[h] = convert
+	}
+	c.mu.Unlock()
+	return convert()
+}
+
+// image converts the gzip layers of img.
+func (c *layerConverter) image(img v1.Image) (v1.Image, [2]int64, error) {
+	return recompressImage(img, c)
+}
+
+// recompressImage returns img with its gzip layers converted and the size of those layers before and after, or img
+// itself when it has no gzip layers.
+func recompressImage(img v1.Image, zlayers *layerConverter) (v1.Image, [2]int64, error) {
+	sizes := [2]int64{}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, sizes, err
+	}
+	manifest = manifest.DeepCopy()
+
+	layers := map[v1.Hash]v1.Layer{}
+	diffIDs := map[int]v1.Hash{}
+	for i, desc := range manifest.Layers {
+		if desc.MediaType != types.DockerLayer && desc.MediaType != types.OCILayer {
+			if mt, ok := ociMediaTypes[desc.MediaType]; ok {
+				manifest.Layers[i].MediaType = mt
+			}
+			continue
+		}
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, sizes, err
+		}
+		zlayer, err := zlayers.get(desc.Digest, layer)
+		if err != nil {
+			return nil, sizes, err
+		}
+		zdesc, err := partial.Descriptor(zlayer)
+		if err != nil {
+			return nil, sizes, err
+		}
+		for k, v := range desc.Annotations {
+			if zdesc.Annotations == nil {
+				zdesc.Annotations = map[string]string{}
+			}
+			zdesc.Annotations[k] = v
+		}
+		zdesc.URLs = desc.URLs
+		if zlayers.changesContent {
+			diffID, err := zlayer.DiffID()
+			if err != nil {
+				return nil, sizes, err
+			}
+			diffIDs[i] = diffID
+		}
+		manifest.Layers[i] = *zdesc
+		layers[zdesc.Digest] = zlayer
+		sizes[0] += desc.Size
+		sizes[1] += zdesc.Size
+	}
+	if len(layers) == 0 {
+		return img, sizes, nil
+	}
+
+	config, err := img.RawConfigFile()
+	if err != nil {
+		return nil, sizes, err
+	}
+	if len(diffIDs) > 0 {
+		config, err = replaceDiffIDs(img, diffIDs)
+		if err != nil {
+			return nil, sizes, err
+		}
+		digest, size, err := v1.SHA256(bytes.NewReader(config))
+		if err != nil {
+			return nil, sizes, err
+		}
+		manifest.Config.Digest = digest
+		manifest.Config.Size = size
+	}
+
+	if mt, ok := ociMediaTypes[manifest.MediaType]; ok {
+		manifest.MediaType = mt
+	}
+	if mt, ok := ociMediaTypes[manifest.Config.MediaType]; ok {
+		manifest.Config.MediaType = mt
+	}
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, sizes, err
+	}
+	zimg, err := partial.CompressedToImage(&recompressedImage{base: img, config: config, manifest: b, mediaType: manifest.MediaType, layers: layers})
+	if err != nil {
+		return nil, sizes, err
+	}
+	return zimg, sizes, nil
+}
+
+// recompressedImage is an image whose manifest references recompressed layers in place of the layers of its base.
+type recompressedImage struct {
+	base      v1.Image
+	config    []byte
+	manifest  []byte
+	mediaType types.MediaType
+	layers    map[v1.Hash]v1.Layer
+}
+
+// RawConfigFile returns the config of the base image with the diff IDs of the converted layers.
+func (i *recompressedImage) RawConfigFile() ([]byte, error) {
+	return i.config, nil
+}
+
+// replaceDiffIDs returns the config of img with the diff IDs of the layers at the indexes of diffIDs replaced.
+func replaceDiffIDs(img v1.Image, diffIDs map[int]v1.Hash) ([]byte, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	cfg = cfg.DeepCopy()
+	for i, diffID := range diffIDs {
+		if i >= len(cfg.RootFS.DiffIDs) {
+			return nil, fmt.Errorf("the config of the image has no diff ID for layer %d", i)
+		}
+		cfg.RootFS.DiffIDs[i] = diffID
+	}
+	return json.Marshal(cfg)
+}
+
+// MediaType returns the media type of the manifest.
+func (i *recompressedImage) MediaType() (types.MediaType, error) {
+	return i.mediaType, nil
+}
+
+// RawManifest returns the manifest referencing the recompressed layers.
+func (i *recompressedImage) RawManifest() ([]byte, error) {
+	return i.manifest, nil
+}
+
+// LayerByDigest returns a recompressed layer, or a layer of the base image that was not recompressed.
+func (i *recompressedImage) LayerByDigest(h v1.Hash) (partial.CompressedLayer, error) {
+	if layer, ok := i.layers[h]; ok {
+		return layer, nil
+	}
+	return i.base.LayerByDigest(h)
+}
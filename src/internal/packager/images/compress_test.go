@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+func TestRecompressZstd(t *testing.T) {
+	t.Parallel()
+
+	base, err := random.Layer(4096, types.DockerLayer)
+	require.NoError(t, err)
+	newImage := func(t *testing.T) v1.Image {
+		t.Helper()
+		app, err := random.Layer(1024, types.DockerLayer)
+		require.NoError(t, err)
+		img, err := mutate.AppendLayers(empty.Image, base, app)
+		require.NoError(t, err)
+		return img
+	}
+
+	dir := t.TempDir()
+	lp, err := clayout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	imgs := map[transform.Image]v1.Image{}
+	for _, ref := range []string{"docker.io/library/app:1.0.0", "docker.io/library/app:1.0.1", "docker.io/library/pinned:1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000"} {
+		refInfo, err := transform.ParseImageRef(ref)
+		require.NoError(t, err)
+		img := newImage(t)
+		err = lp.AppendImage(img, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: refInfo.Reference}))
+		require.NoError(t, err)
+		imgs[refInfo] = img
+	}
+
+	recompressed, err := RecompressZstd(context.Background(), dir, imgs, 2)
+	require.NoError(t, err)
+	require.Len(t, recompressed, len(imgs))
+
+	sharedLayers := map[v1.Hash]int{}
+	for refInfo, img := range imgs {
+		zimg := recompressed[refInfo]
+		if refInfo.Digest != "" {
+			require.Equal(t, img, zimg)
+			continue
+		}
+
+		// The image is replaced in the layout with an OCI manifest of zstd layers holding the same content.
+		saved, err := utils.LoadOCIImage(dir, refInfo)
+		require.NoError(t, err)
+		savedDigest, err := saved.Digest()
+		require.NoError(t, err)
+		zDigest, err := zimg.Digest()
+		require.NoError(t, err)
+		require.Equal(t, zDigest, savedDigest)
+
+		mt, err := saved.MediaType()
+		require.NoError(t, err)
+		require.Equal(t, types.OCIManifestSchema1, mt)
+		manifest, err := saved.Manifest()
+		require.NoError(t, err)
+		require.Equal(t, types.OCIConfigJSON, manifest.Config.MediaType)
+		for _, desc := range manifest.Layers {
+			require.Equal(t, types.OCILayerZStd, desc.MediaType)
+			sharedLayers[desc.Digest]++
+		}
+
+		diffIDs, err := img.ConfigFile()
+		require.NoError(t, err)
+		zDiffIDs, err := saved.ConfigFile()
+		require.NoError(t, err)
+		require.Equal(t, diffIDs.RootFS.DiffIDs, zDiffIDs.RootFS.DiffIDs)
+		layers, err := saved.Layers()
+		require.NoError(t, err)
+		for i, layer := range layers {
+			diffID, err := layer.DiffID()
+			require.NoError(t, err)
+			require.Equal(t, diffIDs.RootFS.DiffIDs[i], diffID)
+		}
+	}
+	// The base layer is recompressed once and stays shared between the images.
+	require.Len(t, sharedLayers, 3)
+
+	idx, err := lp.ImageIndex()
+	require.NoError(t, err)
+	idxManifest, err := idx.IndexManifest()
+	require.NoError(t, err)
+	require.Len(t, idxManifest.Manifests, 3)
+}
+
+func TestPullZstd(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	layer, err := random.Layer(1024, types.OCILayerZStd)
+	require.NoError(t, err)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, types.OCIConfigJSON)
+	ref := fmt.Sprintf("%s/zstd:1.0.0", host)
+	err = crane.Push(img, ref, crane.Insecure)
+	require.NoError(t, err)
+
+	refInfo, err := transform.ParseImageRef(ref)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	pulled, err := Pull(context.Background(), PullConfig{
+		DestinationDirectory: filepath.Join(dir, "images"),
+		ImageList:            []transform.Image{refInfo},
+		Arch:                 "amd64",
+		CacheDirectory:       filepath.Join(dir, "cache"),
+	})
+	require.NoError(t, err)
+	require.Len(t, pulled, 1)
+
+	saved, err := utils.LoadOCIImage(filepath.Join(dir, "images"), refInfo)
+	require.NoError(t, err)
+	manifest, err := saved.Manifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Layers, 1)
+	require.Equal(t, types.OCILayerZStd, manifest.Layers[0].MediaType)
+	expected, err := layer.DiffID()
+	require.NoError(t, err)
+	savedLayers, err := saved.Layers()
+	require.NoError(t, err)
+	diffID, err := savedLayers[0].DiffID()
+	require.NoError(t, err)
+	require.Equal(t, expected, diffID)
+}
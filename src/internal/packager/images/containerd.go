@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/images/archive"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/platforms"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+var (
+	// containerdSockets are the sockets tried when no containerd address is configured, the default socket and the one embedded in k3s
+	containerdSockets = []string{"/run/containerd/containerd.sock", "/run/k3s/containerd/containerd.sock"}
+	// containerdNamespaces are the namespaces tried when no containerd namespace is configured, the one used by nerdctl and ctr and the one used by the CRI
+	containerdNamespaces = []string{"default", "k8s.io"}
+)
+
+// containerdImage exports an image from the local containerd content store into a tarball in tmpDir and loads it.
+func containerdImage(ctx context.Context, ref string, cfg PullConfig, tmpDir string) (v1.Image, error) {
+	refInfo, err := transform.ParseImageRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	sockets := containerdSockets
+	if cfg.ContainerdAddress != "" {
+		sockets = []string{cfg.ContainerdAddress}
+	}
+	nss := containerdNamespaces
+	if cfg.ContainerdNamespace != "" {
+		nss = []string{cfg.ContainerdNamespace}
+	}
+
+	errs := []error{}
+	for _, socket := range sockets {
+		if _, err := os.Stat(socket); err != nil {
+			errs = append(errs, fmt.Errorf("containerd socket %s is not available: %w", socket, err))
+			continue
+		}
+		client, err := containerd.New(socket)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to connect to containerd at %s: %w", socket, err))
+			continue
+		}
+		defer client.Close()
+
+		for _, ns := range nss {
+			nsCtx := namespaces.WithNamespace(ctx, ns)
+			if _, err := client.GetImage(nsCtx, refInfo.Reference); err != nil {
+				errs = append(errs, fmt.Errorf("image %s not found in containerd namespace %s at %s: %w", refInfo.Reference, ns, socket, err))
+				continue
+			}
+
+			message.Debugf("Exporting %s from containerd namespace %s at %s", refInfo.Reference, ns, socket)
+			img, err := exportContainerdImage(nsCtx, client, refInfo.Reference, cfg.Arch, tmpDir)
+			if err != nil {
+				return nil, fmt.Errorf("unable to export %s from containerd namespace %s: %w", refInfo.Reference, ns, err)
+			}
+			return img, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to load %s from containerd: %w", refInfo.Reference, errors.Join(errs...))
+}
+
+func exportContainerdImage(ctx context.Context, client *containerd.Client, name, arch, tmpDir string) (v1.Image, error) {
+	f, err := os.CreateTemp(tmpDir, "containerd-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	platform := platforms.Only(ocispec.Platform{OS: "linux", Architecture: arch})
+	err = client.Export(ctx, f, archive.WithImage(client.ImageService(), name), archive.WithPlatform(platform))
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return tarball.ImageFromPath(filepath.Clean(f.Name()), nil)
+}
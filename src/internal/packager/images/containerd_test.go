@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerdImageMissingSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "containerd.sock")
+	cfg := PullConfig{
+		Arch:                "amd64",
+		ContainerdAddress:   socket,
+		ContainerdNamespace: "default",
+	}
+	_, err := containerdImage(context.Background(), "ghcr.io/zarf-dev/doom-game:0.0.1", cfg, t.TempDir())
+	require.ErrorContains(t, err, "failed to load ghcr.io/zarf-dev/doom-game:0.0.1 from containerd")
+	require.ErrorContains(t, err, "containerd socket "+socket+" is not available")
+}
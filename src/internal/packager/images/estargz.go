@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/containerd/stargz-snapshotter/estargz"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// ConvertEstargz converts the gzip layers of the images in the OCI layout at path to eStargz, replacing the images in
+// the layout and returning them. eStargz layers are still valid gzip layers, but they carry a table of contents that
+// lets lazy-pulling snapshotters start containers before their layers are fully pulled. Images referenced by digest and
+// images saved with their index are left as they are since converting changes their digest.
+func ConvertEstargz(ctx context.Context, path string, imgs map[transform.Image]v1.Image, concurrency int) (map[transform.Image]v1.Image, error) {
+	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	elayers := newLayerConverter(func(layer v1.Layer) (v1.Layer, error) {
+		return estargzLayer(layer, tmpDir)
+	})
+	elayers.changesContent = true
+	return convertImages(ctx, path, imgs, concurrency, elayers.image, "Converting image layers to eStargz", "Converted image layers to eStargz")
+}
+
+// estargzLayer converts layer to eStargz, staging the uncompressed and converted layer in tmpDir.
+func estargzLayer(layer v1.Layer, tmpDir string) (v1.Layer, error) {
+	// Building the table of contents needs random access to the uncompressed layer
+	tarFile, err := os.CreateTemp(tmpDir, "layer-*.tar")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tarFile.Name())
+	defer tarFile.Close()
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	size, err := io.Copy(tarFile, rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	compression := &estargzCompression{estargz.NewGzipCompressor(), &estargz.GzipDecompressor{}}
+	blob, err := estargz.Build(io.NewSectionReader(tarFile, 0, size), estargz.WithCompression(compression))
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	blobFile, err := os.CreateTemp(tmpDir, "layer-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer blobFile.Close()
+	if _, err := io.Copy(blobFile, blob); err != nil {
+		return nil, err
+	}
+	if err := blobFile.Close(); err != nil {
+		return nil, err
+	}
+
+	converted, err := tarball.LayerFromFile(blobFile.Name(), tarball.WithMediaType(types.OCILayer))
+	if err != nil {
+		return nil, err
+	}
+	diffID, err := v1.NewHash(blob.DiffID().String())
+	if err != nil {
+		return nil, err
+	}
+	return &convertedEstargzLayer{
+		Layer:  converted,
+		diffID: diffID,
+		annotations: map[string]string{
+			estargz.TOCJSONDigestAnnotation: blob.TOCDigest().String(),
+		},
+	}, nil
+}
+
+// convertedEstargzLayer is an eStargz layer with the annotation of the digest of its table of contents.
+type convertedEstargzLayer struct {
+	v1.Layer
+	diffID      v1.Hash
+	annotations map[string]string
+}
+
+// DiffID returns the digest of the uncompressed layer that was computed while it was built.
+func (l *convertedEstargzLayer) DiffID() (v1.Hash, error) {
+	return l.diffID, nil
+}
+
+// Descriptor returns the descriptor of the layer with the eStargz annotations.
+func (l *convertedEstargzLayer) Descriptor() (*v1.Descriptor, error) {
+	digest, err := l.Digest()
+	if err != nil {
+		return nil, err
+	}
+	size, err := l.Size()
+	if err != nil {
+		return nil, err
+	}
+	mt, err := l.MediaType()
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Descriptor{MediaType: mt, Size: size, Digest: digest, Annotations: l.annotations}, nil
+}
+
+// estargzCompression is the gzip compression of eStargz with a footer that does not depend on how compress/flate
+// encodes empty input, which newer Go releases no longer encode as the stored block the fixed size footer needs.
+type estargzCompression struct {
+	*estargz.GzipCompressor
+	*estargz.GzipDecompressor
+}
+
+// WriteTOCAndFooter writes the table of contents as a gzip member followed by the footer pointing at it.
+func (c *estargzCompression) WriteTOCAndFooter(w io.Writer, off int64, toc *estargz.JTOC, diffHash hash.Hash) (digest.Digest, error) {
+	tocJSON, err := json.MarshalIndent(toc, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	gw := io.Writer(gz)
+	if diffHash != nil {
+		gw = io.MultiWriter(gz, diffHash)
+	}
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: estargz.TOCTarName, Size: int64(len(tocJSON))}); err != nil {
+		return "", err
+	}
+	if _, err := tw.Write(tocJSON); err != nil {
+		return "", err
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	if _, err := w.Write(estargzFooter(off)); err != nil {
+		return "", err
+	}
+	return digest.FromBytes(tocJSON), nil
+}
+
+// estargzFooter returns the footer of an eStargz layer, an empty gzip member of estargz.FooterSize bytes holding the
+// offset of the table of contents in its extra field.
+func estargzFooter(tocOff int64) []byte {
+	subfield := fmt.Sprintf("%016xSTARGZ", tocOff)
+	extra := binary.LittleEndian.AppendUint16([]byte{'S', 'G'}, uint16(len(subfield)))
+	extra = append(extra, subfield...)
+
+	// Header with the deflate method, the FEXTRA flag, no modification time and an unknown OS
+	footer := []byte{0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff}
+	footer = binary.LittleEndian.AppendUint16(footer, uint16(len(extra)))
+	footer = append(footer, extra...)
+	// An empty final stored block
+	footer = append(footer, 0x01, 0x00, 0x00, 0xff, 0xff)
+	// The CRC-32 and size of the empty contents
+	return append(footer, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/stargz-snapshotter/estargz"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+func TestConvertEstargz(t *testing.T) {
+	t.Parallel()
+
+	base, err := random.Layer(4096, types.DockerLayer)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	lp, err := clayout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	imgs := map[transform.Image]v1.Image{}
+	for _, ref := range []string{"docker.io/library/app:1.0.0", "docker.io/library/app:1.0.1", "docker.io/library/pinned:1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000"} {
+		refInfo, err := transform.ParseImageRef(ref)
+		require.NoError(t, err)
+		app, err := random.Layer(1024, types.DockerLayer)
+		require.NoError(t, err)
+		img, err := mutate.AppendLayers(empty.Image, base, app)
+		require.NoError(t, err)
+		err = lp.AppendImage(img, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: refInfo.Reference}))
+		require.NoError(t, err)
+		imgs[refInfo] = img
+	}
+
+	converted, err := ConvertEstargz(context.Background(), dir, imgs, 2)
+	require.NoError(t, err)
+	require.Len(t, converted, len(imgs))
+
+	sharedLayers := map[v1.Hash]int{}
+	for refInfo, img := range imgs {
+		eimg := converted[refInfo]
+		if refInfo.Digest != "" {
+			require.Equal(t, img, eimg)
+			continue
+		}
+
+		// The image is replaced in the layout with an OCI manifest of eStargz layers
+		saved, err := utils.LoadOCIImage(dir, refInfo)
+		require.NoError(t, err)
+		savedDigest, err := saved.Digest()
+		require.NoError(t, err)
+		eDigest, err := eimg.Digest()
+		require.NoError(t, err)
+		require.Equal(t, eDigest, savedDigest)
+
+		manifest, err := saved.Manifest()
+		require.NoError(t, err)
+		require.Equal(t, types.OCIManifestSchema1, manifest.MediaType)
+		cfg, err := saved.ConfigFile()
+		require.NoError(t, err)
+		for i, desc := range manifest.Layers {
+			require.Equal(t, types.OCILayer, desc.MediaType)
+			sharedLayers[desc.Digest]++
+
+			// The layer carries a valid table of contents matching its annotation
+			blob, err := os.Open(filepath.Join(dir, "blobs", desc.Digest.Algorithm, desc.Digest.Hex))
+			require.NoError(t, err)
+			t.Cleanup(func() { blob.Close() })
+			r, err := estargz.Open(io.NewSectionReader(blob, 0, desc.Size))
+			require.NoError(t, err)
+			require.Equal(t, desc.Annotations[estargz.TOCJSONDigestAnnotation], r.TOCDigest().String())
+
+			// The config has the diff IDs of the converted layers
+			layer, err := saved.LayerByDigest(desc.Digest)
+			require.NoError(t, err)
+			rc, err := layer.Uncompressed()
+			require.NoError(t, err)
+			diffID, _, err := v1.SHA256(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			require.Equal(t, cfg.RootFS.DiffIDs[i], diffID)
+		}
+	}
+	// The base layer is converted once and stays shared between the images
+	require.Len(t, sharedLayers, 3)
+}
+
+func TestEstargzFooter(t *testing.T) {
+	t.Parallel()
+
+	footer := estargzFooter(4096)
+	require.Len(t, footer, estargz.FooterSize)
+
+	// The footer is a valid empty gzip member with the offset of the table of contents in its extra field
+	gz, err := gzip.NewReader(bytes.NewReader(footer))
+	require.NoError(t, err)
+	subfield := fmt.Sprintf("%016xSTARGZ", 4096)
+	require.Equal(t, append([]byte{'S', 'G', byte(len(subfield)), 0}, subfield...), gz.Extra)
+	b, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Empty(t, b)
+
+	_, tocOffset, _, err := (&estargz.GzipDecompressor{}).ParseFooter(footer)
+	require.NoError(t, err)
+	require.Equal(t, int64(4096), tocOffset)
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// LabelSelector selects images by a label of their config, an empty value matches any value of the label.
+type LabelSelector struct {
+	Key   string
+	Value string
+}
+
+// ParseLabelSelector parses a label selector of the form key=value or key.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	key, value, _ := strings.Cut(selector, "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return LabelSelector{}, fmt.Errorf("invalid label selector %q: must be of the form key=value or key", selector)
+	}
+	return LabelSelector{Key: key, Value: strings.TrimSpace(value)}, nil
+}
+
+func (s LabelSelector) String() string {
+	if s.Value == "" {
+		return s.Key
+	}
+	return s.Key + "=" + s.Value
+}
+
+// Matches returns whether the labels of an image are selected.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	value, ok := labels[s.Key]
+	return ok && (s.Value == "" || s.Value == value)
+}
+
+// LocalImagesByLabel returns the names of the images in the local Docker and containerd daemons with a label that matches the selector.
+//
+// The containerd address and namespace default to the sockets and namespaces tried when loading local images from containerd.
+func LocalImagesByLabel(ctx context.Context, selector LabelSelector, containerdAddress, containerdNamespace string) ([]string, error) {
+	errs := []error{}
+	found := []string{}
+	available := false
+
+	dockerImages, err := dockerImagesByLabel(ctx, selector)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		available = true
+		found = append(found, dockerImages...)
+	}
+
+	sockets := containerdSockets
+	if containerdAddress != "" {
+		sockets = []string{containerdAddress}
+	}
+	nss := containerdNamespaces
+	if containerdNamespace != "" {
+		nss = []string{containerdNamespace}
+	}
+	for _, socket := range sockets {
+		containerdImages, err := containerdImagesByLabel(ctx, selector, socket, nss)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		available = true
+		found = append(found, containerdImages...)
+	}
+
+	if !available {
+		return nil, fmt.Errorf("unable to list local images with label %s: %w", selector, errors.Join(errs...))
+	}
+	for _, err := range errs {
+		message.Debug(err)
+	}
+	slices.Sort(found)
+	return slices.Compact(found), nil
+}
+
+// dockerImagesByLabel lists the tagged images in the local docker daemon with a label that matches the selector.
+func dockerImagesByLabel(ctx context.Context, selector LabelSelector) ([]string, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("docker not available: %w", err)
+	}
+	defer cli.Close()
+
+	summaries, err := cli.ImageList(ctx, types.ImageListOptions{Filters: filters.NewArgs(filters.Arg("label", selector.String()))})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list images in docker: %w", err)
+	}
+	found := []string{}
+	for _, summary := range summaries {
+		for _, tag := range summary.RepoTags {
+			if tag == "<none>:<none>" {
+				continue
+			}
+			found = append(found, tag)
+		}
+	}
+	return found, nil
+}
+
+// containerdImagesByLabel lists the named images in the namespaces of a containerd socket with a label that matches the selector.
+func containerdImagesByLabel(ctx context.Context, selector LabelSelector, socket string, nss []string) ([]string, error) {
+	if _, err := os.Stat(socket); err != nil {
+		return nil, fmt.Errorf("containerd socket %s is not available: %w", socket, err)
+	}
+	client, err := containerd.New(socket)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to containerd at %s: %w", socket, err)
+	}
+	defer client.Close()
+
+	found := []string{}
+	for _, ns := range nss {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		imgs, err := client.ListImages(nsCtx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list images in containerd namespace %s at %s: %w", ns, socket, err)
+		}
+		for _, img := range imgs {
+			// The CRI also records images by their digest, only images with a name can be referenced in a package
+			if strings.HasPrefix(img.Name(), "sha256:") || strings.Contains(img.Name(), "@") {
+				continue
+			}
+			spec, err := img.Spec(nsCtx)
+			if err != nil {
+				message.Debugf("Unable to read the config of %s in containerd namespace %s: %s", img.Name(), ns, err.Error())
+				continue
+			}
+			if selector.Matches(spec.Config.Labels) {
+				found = append(found, img.Name())
+			}
+		}
+	}
+	return found, nil
+}
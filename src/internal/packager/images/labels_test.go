@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		selector string
+		labels   map[string]string
+		matches  bool
+		err      string
+	}{
+		{
+			name:     "key and value",
+			selector: "com.example.build=123",
+			labels:   map[string]string{"com.example.build": "123"},
+			matches:  true,
+		},
+		{
+			name:     "different value",
+			selector: "com.example.build=123",
+			labels:   map[string]string{"com.example.build": "456"},
+			matches:  false,
+		},
+		{
+			name:     "key only",
+			selector: "com.example.build",
+			labels:   map[string]string{"com.example.build": "456"},
+			matches:  true,
+		},
+		{
+			name:     "missing label",
+			selector: "com.example.build",
+			labels:   map[string]string{"org.opencontainers.image.source": "https://github.com/zarf-dev/zarf"},
+			matches:  false,
+		},
+		{
+			name:     "no key",
+			selector: "=123",
+			err:      `invalid label selector "=123"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			selector, err := ParseLabelSelector(tt.selector)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err+": must be of the form key=value or key")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.selector, selector.String())
+			require.Equal(t, tt.matches, selector.Matches(tt.labels))
+		})
+	}
+}
+
+func TestLocalImagesByLabelUnavailable(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(dir, "docker.sock"))
+
+	socket := filepath.Join(dir, "containerd.sock")
+	_, err := LocalImagesByLabel(context.Background(), LabelSelector{Key: "com.example.build", Value: "123"}, socket, "default")
+	require.ErrorContains(t, err, "unable to list local images with label com.example.build=123")
+	require.ErrorContains(t, err, "containerd socket "+socket+" is not available")
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// nearlyIdenticalThreshold is the share of layer bytes two images must have in common to be reported as nearly identical.
+const nearlyIdenticalThreshold = 0.8
+
+// ImageLayerUsage describes the size an image contributes to a package.
+type ImageLayerUsage struct {
+	Reference string
+	// TotalSize is the size of all of the image's layers
+	TotalSize int64
+	// UniqueSize is the size of the layers that no other image in the package uses
+	UniqueSize int64
+}
+
+// SharedLayer is a layer that is used by more than one image.
+type SharedLayer struct {
+	Digest string
+	Size   int64
+	Images []string
+}
+
+// LayerReport describes how layers are shared across the images in an OCI layout.
+type LayerReport struct {
+	// Images sorted by their unique size, largest first
+	Images []ImageLayerUsage
+	// SharedLayers sorted by size, largest first
+	SharedLayers []SharedLayer
+	// TotalSize is the size of all distinct layers
+	TotalSize int64
+	// Hints are suggestions for reducing the size of the package
+	Hints []string
+}
+
+// AnalyzeLayers reports how the layers of the images in the OCI layout at the given path are shared.
+func AnalyzeLayers(path string) (LayerReport, error) {
+	report := LayerReport{}
+
+	lp, err := clayout.FromPath(path)
+	if err != nil {
+		return report, err
+	}
+	idx, err := lp.ImageIndex()
+	if err != nil {
+		return report, err
+	}
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return report, err
+	}
+
+	layerSizes := map[string]int64{}
+	layerImages := map[string][]string{}
+	imageLayers := map[string][]string{}
+	refs := []string{}
+	for _, desc := range idxManifest.Manifests {
+		// Image indexes are stored next to the image for the package architecture
+		if desc.MediaType.IsIndex() {
+			continue
+		}
+		ref := desc.Annotations[ocispec.AnnotationBaseImageName]
+		if ref == "" {
+			ref = desc.Digest.String()
+		}
+		img, err := lp.Image(desc.Digest)
+		if err != nil {
+			return report, fmt.Errorf("unable to read image %s: %w", ref, err)
+		}
+		manifest, err := img.Manifest()
+		if err != nil {
+			return report, fmt.Errorf("unable to read the manifest of image %s: %w", ref, err)
+		}
+		refs = append(refs, ref)
+		seen := map[string]bool{}
+		for _, layer := range manifest.Layers {
+			digest := layer.Digest.String()
+			if seen[digest] {
+				continue
+			}
+			seen[digest] = true
+			layerSizes[digest] = layer.Size
+			layerImages[digest] = append(layerImages[digest], ref)
+			imageLayers[ref] = append(imageLayers[ref], digest)
+		}
+	}
+
+	for digest, size := range layerSizes {
+		report.TotalSize += size
+		if len(layerImages[digest]) > 1 {
+			images := layerImages[digest]
+			sort.Strings(images)
+			report.SharedLayers = append(report.SharedLayers, SharedLayer{Digest: digest, Size: size, Images: images})
+		}
+	}
+	sort.Slice(report.SharedLayers, func(i, j int) bool {
+		if report.SharedLayers[i].Size == report.SharedLayers[j].Size {
+			return report.SharedLayers[i].Digest < report.SharedLayers[j].Digest
+		}
+		return report.SharedLayers[i].Size > report.SharedLayers[j].Size
+	})
+
+	for _, ref := range refs {
+		usage := ImageLayerUsage{Reference: ref}
+		for _, digest := range imageLayers[ref] {
+			usage.TotalSize += layerSizes[digest]
+			if len(layerImages[digest]) == 1 {
+				usage.UniqueSize += layerSizes[digest]
+			}
+		}
+		report.Images = append(report.Images, usage)
+	}
+	sort.SliceStable(report.Images, func(i, j int) bool {
+		return report.Images[i].UniqueSize > report.Images[j].UniqueSize
+	})
+
+	sort.Strings(refs)
+	for i, a := range refs {
+		for _, b := range refs[i+1:] {
+			if hint := similarImagesHint(a, b, imageLayers, layerSizes); hint != "" {
+				report.Hints = append(report.Hints, hint)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// similarImagesHint returns a hint when two images of the same repository share most of their layers.
+func similarImagesHint(a, b string, imageLayers map[string][]string, layerSizes map[string]int64) string {
+	refA, errA := name.ParseReference(a)
+	refB, errB := name.ParseReference(b)
+	if errA != nil || errB != nil || refA.Context().String() != refB.Context().String() {
+		return ""
+	}
+
+	inA := map[string]bool{}
+	var sizeA, sizeB, shared int64
+	for _, digest := range imageLayers[a] {
+		inA[digest] = true
+		sizeA += layerSizes[digest]
+	}
+	for _, digest := range imageLayers[b] {
+		sizeB += layerSizes[digest]
+		if inA[digest] {
+			shared += layerSizes[digest]
+		}
+	}
+	larger := max(sizeA, sizeB)
+	if larger == 0 {
+		return ""
+	}
+	ratio := float64(shared) / float64(larger)
+	if shared == sizeA && shared == sizeB {
+		return fmt.Sprintf("%s and %s have identical layers, consider only including one of them", a, b)
+	}
+	if ratio >= nearlyIdenticalThreshold {
+		return fmt.Sprintf("%s and %s share %.0f%% of their layers, consider standardizing on a single tag", a, b, ratio*100)
+	}
+	return ""
+}
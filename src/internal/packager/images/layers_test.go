@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"bytes"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeLayers(t *testing.T) {
+	t.Parallel()
+
+	base := static.NewLayer(bytes.Repeat([]byte("b"), 1000), types.OCILayer)
+	appV1 := static.NewLayer(bytes.Repeat([]byte("1"), 100), types.OCILayer)
+	appV2 := static.NewLayer(bytes.Repeat([]byte("2"), 100), types.OCILayer)
+	other := static.NewLayer(bytes.Repeat([]byte("o"), 500), types.OCILayer)
+
+	newImage := func(t *testing.T, layers ...v1.Layer) v1.Image {
+		t.Helper()
+		img, err := mutate.AppendLayers(empty.Image, layers...)
+		require.NoError(t, err)
+		return img
+	}
+
+	dir := t.TempDir()
+	lp, err := clayout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	images := map[string]v1.Image{
+		"docker.io/library/app:1.0.0":   newImage(t, base, appV1),
+		"docker.io/library/app:1.0.1":   newImage(t, base, appV2),
+		"docker.io/library/other:1.0.0": newImage(t, other),
+	}
+	for ref, img := range images {
+		err := lp.AppendImage(img, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: ref}))
+		require.NoError(t, err)
+	}
+
+	report, err := AnalyzeLayers(dir)
+	require.NoError(t, err)
+
+	baseSize, err := base.Size()
+	require.NoError(t, err)
+	otherSize, err := other.Size()
+	require.NoError(t, err)
+	appSize, err := appV1.Size()
+	require.NoError(t, err)
+
+	require.Equal(t, baseSize+otherSize+2*appSize, report.TotalSize)
+	require.Len(t, report.SharedLayers, 1)
+	require.Equal(t, baseSize, report.SharedLayers[0].Size)
+	require.Equal(t, []string{"docker.io/library/app:1.0.0", "docker.io/library/app:1.0.1"}, report.SharedLayers[0].Images)
+
+	require.Len(t, report.Images, 3)
+	require.Equal(t, "docker.io/library/other:1.0.0", report.Images[0].Reference)
+	require.Equal(t, otherSize, report.Images[0].UniqueSize)
+	require.Equal(t, appSize, report.Images[1].UniqueSize)
+	require.Equal(t, baseSize+appSize, report.Images[1].TotalSize)
+
+	require.Len(t, report.Hints, 1)
+	require.Contains(t, report.Hints[0], "docker.io/library/app:1.0.0 and docker.io/library/app:1.0.1 share")
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// imageSource is a reference an image can be fetched from along with the crane options to fetch it with.
+type imageSource struct {
+	ref  string
+	opts []crane.Option
+}
+
+// imageSources returns the references to fetch an image from in order: registry overrides, then the mirrors of its
+// upstream registry (most specific upstream first) and finally the upstream registry itself. Mirrors are connected to
+// through proxy like the other sources.
+func imageSources(refInfo transform.Image, overrides map[string]string, mirrors types.RegistryMirrors, proxy types.ProxyConfig, opts []crane.Option) ([]imageSource, error) {
+	sources := []imageSource{}
+	for k, v := range overrides {
+		if strings.HasPrefix(refInfo.Reference, k) {
+			sources = append(sources, imageSource{ref: strings.Replace(refInfo.Reference, k, v, 1), opts: opts})
+		}
+	}
+
+	upstreams := []string{}
+	for upstream := range mirrors {
+		upstreams = append(upstreams, upstream)
+	}
+	sort.Slice(upstreams, func(i, j int) bool {
+		if len(upstreams[i]) != len(upstreams[j]) {
+			return len(upstreams[i]) > len(upstreams[j])
+		}
+		return upstreams[i] < upstreams[j]
+	})
+	for _, upstream := range upstreams {
+		for _, mirror := range mirrors[upstream] {
+			ref, ok := transform.MirrorImageRef(refInfo, upstream, mirror.Address)
+			if !ok {
+				continue
+			}
+			mirrorOpts, err := withMirror(mirror, proxy, opts)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, imageSource{ref: ref, opts: mirrorOpts})
+		}
+	}
+
+	return append(sources, imageSource{ref: refInfo.Reference, opts: opts}), nil
+}
+
+// withMirror returns the crane options to connect to a mirror with its own CA and credentials.
+func withMirror(mirror types.RegistryMirror, proxy types.ProxyConfig, opts []crane.Option) ([]crane.Option, error) {
+	mirrorOpts := slices.Clone(opts)
+	if mirror.CAFile != "" {
+		transport, err := newPullTransport(proxy, mirror.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA bundle for mirror %s: %w", mirror.Address, err)
+		}
+		mirrorOpts = append(mirrorOpts, crane.WithTransport(registryTransport(transport)))
+	}
+	if mirror.Username != "" {
+		mirrorOpts = append(mirrorOpts, WithBasicAuth(mirror.Username, mirror.Password))
+	}
+	return mirrorOpts, nil
+}
+
+// getFromSources fetches the descriptor of an image from the first source that has it.
+func getFromSources(sources []imageSource) (*remote.Descriptor, imageSource, error) {
+	errs := []error{}
+	for _, src := range sources {
+		desc, err := crane.Get(src.ref, src.opts...)
+		if err == nil {
+			return desc, src, nil
+		}
+		message.Debugf("Unable to fetch %s: %s", src.ref, err.Error())
+		errs = append(errs, err)
+	}
+	return nil, imageSource{}, errors.Join(errs...)
+}
+
+// Head returns the descriptor of an image, looking it up on the mirrors of its upstream registry before the upstream registry.
+func Head(ref string, mirrors types.RegistryMirrors) (*v1.Descriptor, error) {
+	refInfo, err := transform.ParseImageRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := imageSources(refInfo, nil, mirrors, types.ProxyConfig{}, WithGlobalInsecureFlag())
+	if err != nil {
+		return nil, err
+	}
+	errs := []error{}
+	for _, src := range sources {
+		desc, err := crane.Head(src.ref, src.opts...)
+		if err == nil {
+			return desc, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestImageSources(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		image        string
+		overrides    map[string]string
+		mirrors      types.RegistryMirrors
+		expectedRefs []string
+		expectedErr  string
+	}{
+		{
+			name:         "no mirrors",
+			image:        "nginx:1.25",
+			expectedRefs: []string{"docker.io/library/nginx:1.25"},
+		},
+		{
+			name:  "mirrors in order before upstream",
+			image: "nginx:1.25",
+			mirrors: types.RegistryMirrors{
+				"docker.io": {{Address: "mirror.example.com"}, {Address: "backup.example.com/dockerhub"}},
+				"ghcr.io":   {{Address: "mirror.example.com/ghcr"}},
+			},
+			expectedRefs: []string{
+				"mirror.example.com/library/nginx:1.25",
+				"backup.example.com/dockerhub/library/nginx:1.25",
+				"docker.io/library/nginx:1.25",
+			},
+		},
+		{
+			name:  "most specific upstream first",
+			image: "nginx:1.25",
+			mirrors: types.RegistryMirrors{
+				"docker.io":         {{Address: "mirror.example.com/dockerhub"}},
+				"docker.io/library": {{Address: "mirror.example.com/official"}},
+			},
+			expectedRefs: []string{
+				"mirror.example.com/official/nginx:1.25",
+				"mirror.example.com/dockerhub/library/nginx:1.25",
+				"docker.io/library/nginx:1.25",
+			},
+		},
+		{
+			name:      "overrides before mirrors",
+			image:     "nginx:1.25",
+			overrides: map[string]string{"docker.io": "override.example.com"},
+			mirrors: types.RegistryMirrors{
+				"docker.io": {{Address: "mirror.example.com"}},
+			},
+			expectedRefs: []string{
+				"override.example.com/library/nginx:1.25",
+				"mirror.example.com/library/nginx:1.25",
+				"docker.io/library/nginx:1.25",
+			},
+		},
+		{
+			name:  "missing CA bundle",
+			image: "nginx:1.25",
+			mirrors: types.RegistryMirrors{
+				"docker.io": {{Address: "mirror.example.com", CAFile: filepath.Join("testdata", "missing.pem")}},
+			},
+			expectedErr: "unable to read the CA bundle for mirror mirror.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			refInfo, err := transform.ParseImageRef(tt.image)
+			require.NoError(t, err)
+			sources, err := imageSources(refInfo, tt.overrides, tt.mirrors, types.ProxyConfig{}, nil)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			refs := []string{}
+			for _, src := range sources {
+				refs = append(refs, src.ref)
+			}
+			require.Equal(t, tt.expectedRefs, refs)
+		})
+	}
+}
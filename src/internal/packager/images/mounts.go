@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// blobMounts maps the layers pushed to a registry to the repository they were pushed to, so the layers images share
+// are mounted from that repository instead of being uploaded to every repository again.
+//
+// Repositories are stored without their registry as the address of the registry changes when a tunnel to it is
+// reconnected.
+type blobMounts map[v1.Hash]string
+
+// image returns img with the layers that were pushed to other repositories of the registry of dst mountable from them.
+func (m blobMounts) image(img v1.Image, dst name.Repository) v1.Image {
+	return &mountableImage{Image: img, mounts: m, dst: dst}
+}
+
+// index returns idx with the layers of its images that were pushed to other repositories of the registry of dst
+// mountable from them.
+func (m blobMounts) index(idx v1.ImageIndex, dst name.Repository) v1.ImageIndex {
+	return &mountableIndex{imageIndex: idx, mounts: m, dst: dst}
+}
+
+// recordImage records that the layers of img were pushed to dst.
+func (m blobMounts) recordImage(img v1.Image, dst name.Repository) error {
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		digest, err := layer.Digest()
+		if err != nil {
+			return err
+		}
+		m[digest] = dst.RepositoryStr()
+	}
+	return nil
+}
+
+// recordIndex records that the layers of every image of idx were pushed to dst.
+func (m blobMounts) recordIndex(idx v1.ImageIndex, dst name.Repository) error {
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+	for _, desc := range idxManifest.Manifests {
+		switch {
+		case desc.MediaType.IsIndex():
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if err := m.recordIndex(child, dst); err != nil {
+				return err
+			}
+		case desc.MediaType.IsImage():
+			img, err := idx.Image(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if err := m.recordImage(img, dst); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mountable returns layer as a layer mountable from the repository it was pushed to when that is not dst.
+func (m blobMounts) mountable(layer v1.Layer, dst name.Repository) (v1.Layer, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return nil, err
+	}
+	repo, ok := m[digest]
+	if !ok || repo == dst.RepositoryStr() {
+		return layer, nil
+	}
+	return &remote.MountableLayer{
+		Layer:     layer,
+		Reference: dst.Registry.Repo(repo).Digest(digest.String()),
+	}, nil
+}
+
+type mountableImage struct {
+	v1.Image
+	mounts blobMounts
+	dst    name.Repository
+}
+
+// Layers returns the layers of the image, mountable from the repositories they were pushed to.
+func (i *mountableImage) Layers() ([]v1.Layer, error) {
+	layers, err := i.Image.Layers()
+	if err != nil {
+		return nil, err
+	}
+	mountable := make([]v1.Layer, 0, len(layers))
+	for _, layer := range layers {
+		ml, err := i.mounts.mountable(layer, i.dst)
+		if err != nil {
+			return nil, err
+		}
+		mountable = append(mountable, ml)
+	}
+	return mountable, nil
+}
+
+// imageIndex lets mountableIndex embed an image index while overriding its ImageIndex method.
+type imageIndex = v1.ImageIndex
+
+type mountableIndex struct {
+	imageIndex
+	mounts blobMounts
+	dst    name.Repository
+}
+
+// Image returns the image of the index with the given digest with mountable layers.
+func (i *mountableIndex) Image(h v1.Hash) (v1.Image, error) {
+	img, err := i.imageIndex.Image(h)
+	if err != nil {
+		return nil, err
+	}
+	return i.mounts.image(img, i.dst), nil
+}
+
+// ImageIndex returns the child index of the index with the given digest with mountable layers.
+func (i *mountableIndex) ImageIndex(h v1.Hash) (v1.ImageIndex, error) {
+	idx, err := i.imageIndex.ImageIndex(h)
+	if err != nil {
+		return nil, err
+	}
+	return i.mounts.index(idx, i.dst), nil
+}
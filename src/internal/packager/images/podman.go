@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// podmanRootfulSocket is the socket of the system wide Podman API service.
+const podmanRootfulSocket = "/run/podman/podman.sock"
+
+// podmanSockets returns the Podman API sockets to try, an explicit CONTAINER_HOST first, then the rootless socket of the current user and finally the rootful socket.
+func podmanSockets() []string {
+	sockets := []string{}
+	if host, ok := strings.CutPrefix(os.Getenv("CONTAINER_HOST"), "unix://"); ok && host != "" {
+		sockets = append(sockets, host)
+	}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = filepath.Join("/run/user", strconv.Itoa(os.Getuid()))
+	}
+	sockets = append(sockets, filepath.Join(runtimeDir, "podman", "podman.sock"), podmanRootfulSocket)
+	return sockets
+}
+
+// podmanImage loads an image through the Docker compatible API of a local Podman service.
+func podmanImage(ctx context.Context, ref string, sockets []string) (v1.Image, error) {
+	errs := []error{}
+	for _, socket := range sockets {
+		if _, err := os.Stat(socket); err != nil {
+			errs = append(errs, fmt.Errorf("podman socket %s is not available: %w", socket, err))
+			continue
+		}
+		cli, err := client.NewClientWithOpts(client.WithHost("unix://"+socket), client.WithAPIVersionNegotiation())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to connect to podman at %s: %w", socket, err))
+			continue
+		}
+
+		rawImg, _, err := cli.ImageInspectWithRaw(ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("image %s not found in podman at %s: %w", ref, socket, err))
+			cli.Close()
+			continue
+		}
+
+		// Podman resolves short names to the name it stored the image under (e.g. localhost/app:1.0 for locally built images),
+		// which is the name it must be saved by.
+		reference, err := name.ParseReference(ref)
+		if err != nil {
+			return nil, err
+		}
+		if len(rawImg.RepoTags) > 0 {
+			reference, err = name.ParseReference(rawImg.RepoTags[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		message.Debugf("Loading %s from podman at %s as %s", ref, socket, reference)
+		img, err := daemon.Image(reference, daemon.WithClient(cli), daemon.WithContext(ctx), daemon.WithUnbufferedOpener())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s from podman at %s: %w", ref, socket, err)
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("failed to load %s from podman: %w", ref, errors.Join(errs...))
+}
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodmanSockets(t *testing.T) {
+	t.Setenv("CONTAINER_HOST", "unix:///tmp/podman/custom.sock")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+
+	sockets := podmanSockets()
+	expected := []string{"/tmp/podman/custom.sock", "/run/user/1000/podman/podman.sock", podmanRootfulSocket}
+	require.Equal(t, expected, sockets)
+
+	// Remote podman connections can not be used to load local images
+	t.Setenv("CONTAINER_HOST", "ssh://core@localhost:22/run/podman/podman.sock")
+	sockets = podmanSockets()
+	require.Equal(t, expected[1:], sockets)
+}
+
+func TestPodmanImageMissingSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "podman.sock")
+	_, err := podmanImage(context.Background(), "localhost/app:1.0", []string{socket})
+	require.ErrorContains(t, err, "failed to load localhost/app:1.0 from podman")
+	require.ErrorContains(t, err, "podman socket "+socket+" is not available")
+}
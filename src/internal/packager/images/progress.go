@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// ProgressPhase is a step of pulling or pushing images that progress is reported for.
+type ProgressPhase string
+
+const (
+	// PhaseFetch resolves the manifests of the images to pull, progress is counted in images
+	PhaseFetch ProgressPhase = "fetch"
+	// PhaseSave writes the pulled images to the destination directory, progress is counted in bytes
+	PhaseSave ProgressPhase = "save"
+	// PhasePush pushes the images to the registry, progress is counted in bytes
+	PhasePush ProgressPhase = "push"
+)
+
+// ProgressEventType is the kind of a progress event.
+type ProgressEventType string
+
+const (
+	// ProgressStarted is reported once when a phase starts
+	ProgressStarted ProgressEventType = "started"
+	// ProgressUpdated is reported as a phase makes progress
+	ProgressUpdated ProgressEventType = "updated"
+	// ProgressCompleted is reported once when a phase completes
+	ProgressCompleted ProgressEventType = "completed"
+	// ProgressFailed is reported once when a phase stops without completing
+	ProgressFailed ProgressEventType = "failed"
+)
+
+// ProgressEvent is the progress of a phase of pulling or pushing images.
+type ProgressEvent struct {
+	Phase ProgressPhase
+	Type  ProgressEventType
+	// Image is the reference of the image the event is about, it is empty for events about the whole phase
+	Image string
+	// Complete is the progress made so far in the unit of the phase
+	Complete int64
+	// Total is the expected progress of the phase in the unit of the phase
+	Total int64
+	// Message is a human readable description of the event, it is empty when only Complete changed
+	Message string
+}
+
+// ProgressReporter receives the progress of pulling and pushing images, Report may be called concurrently.
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// ProgressReporterFunc is a function that implements ProgressReporter.
+type ProgressReporterFunc func(event ProgressEvent)
+
+// Report calls f with the event.
+func (f ProgressReporterFunc) Report(event ProgressEvent) {
+	f(event)
+}
+
+// DiscardProgress is a ProgressReporter that ignores every event.
+var DiscardProgress ProgressReporter = ProgressReporterFunc(func(ProgressEvent) {})
+
+// NewTerminalProgressReporter returns a ProgressReporter that renders progress as spinners and progress bars in the terminal.
+//
+// It is used when no ProgressReporter is configured.
+func NewTerminalProgressReporter() ProgressReporter {
+	return &terminalReporter{}
+}
+
+type terminalReporter struct {
+	mu      sync.Mutex
+	spinner *message.Spinner
+	bar     *message.ProgressBar
+}
+
+func (r *terminalReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.Phase == PhaseFetch {
+		switch event.Type {
+		case ProgressStarted:
+			r.spinner = message.NewProgressSpinner("%s", event.Message)
+		case ProgressUpdated:
+			if r.spinner != nil {
+				r.spinner.Updatef("%s", event.Message)
+			}
+		case ProgressCompleted:
+			if r.spinner != nil {
+				r.spinner.Successf("%s", event.Message)
+			}
+			r.spinner = nil
+		case ProgressFailed:
+			if r.spinner != nil {
+				r.spinner.Stop()
+			}
+			r.spinner = nil
+		}
+		return
+	}
+
+	switch event.Type {
+	case ProgressStarted:
+		if r.bar != nil {
+			r.bar.Close()
+		}
+		r.bar = message.NewProgressBar(event.Total, event.Message)
+	case ProgressUpdated:
+		if r.bar == nil {
+			return
+		}
+		if event.Message == "" {
+			r.bar.Add(int(event.Complete) - r.bar.GetCurrent())
+			return
+		}
+		r.bar.Update(event.Complete, event.Message)
+	case ProgressCompleted:
+		if r.bar != nil {
+			r.bar.Successf("%s", event.Message)
+		}
+		r.bar = nil
+	case ProgressFailed:
+		if r.bar != nil {
+			r.bar.Close()
+		}
+		r.bar = nil
+	}
+}
+
+// progressTracker reports the progress of a single phase.
+type progressTracker struct {
+	reporter ProgressReporter
+	phase    ProgressPhase
+	total    int64
+	complete atomic.Int64
+	message  atomic.Value
+	done     atomic.Bool
+}
+
+// startProgress reports the start of a phase, the returned tracker must be stopped once the phase ends.
+func startProgress(reporter ProgressReporter, phase ProgressPhase, total int64, msg string) *progressTracker {
+	if reporter == nil {
+		reporter = NewTerminalProgressReporter()
+	}
+	t := &progressTracker{
+		reporter: reporter,
+		phase:    phase,
+		total:    total,
+	}
+	t.message.Store(msg)
+	reporter.Report(ProgressEvent{Phase: phase, Type: ProgressStarted, Total: total, Message: msg})
+	return t
+}
+
+// update reports the progress made so far.
+func (t *progressTracker) update(image string, complete int64, msg string) {
+	t.complete.Store(complete)
+	t.message.Store(msg)
+	t.reporter.Report(ProgressEvent{Phase: t.phase, Type: ProgressUpdated, Image: image, Complete: complete, Total: t.total, Message: msg})
+}
+
+// add reports n more units of progress.
+func (t *progressTracker) add(n int64) {
+	complete := t.complete.Add(n)
+	t.reporter.Report(ProgressEvent{Phase: t.phase, Type: ProgressUpdated, Complete: complete, Total: t.total})
+}
+
+// success reports the completion of the phase.
+func (t *progressTracker) success(format string, a ...any) {
+	if t.done.Swap(true) {
+		return
+	}
+	t.reporter.Report(ProgressEvent{Phase: t.phase, Type: ProgressCompleted, Complete: t.total, Total: t.total, Message: fmt.Sprintf(format, a...)})
+}
+
+// stop reports the failure of the phase if it has not completed.
+func (t *progressTracker) stop() {
+	if t.done.Swap(true) {
+		return
+	}
+	msg, _ := t.message.Load().(string)
+	t.reporter.Report(ProgressEvent{Phase: t.phase, Type: ProgressFailed, Complete: t.complete.Load(), Total: t.total, Message: msg})
+}
+
+// trackDirSize reports the size of dir as the progress of the tracker until ctx is done.
+func (t *progressTracker) trackDirSize(ctx context.Context, dir string, msg string) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			size, err := helpers.GetDirSize(dir)
+			if err != nil {
+				message.Debugf("unable to get updated progress: %s", err.Error())
+				continue
+			}
+			t.update("", size, fmt.Sprintf("%s (%s of %s)", msg, utils.ByteFormat(float64(size), 2), utils.ByteFormat(float64(t.total), 2)))
+		}
+	}
+}
+
+// progressWriter reports the bytes written through an http transport as the progress of a tracker.
+type progressWriter struct {
+	tracker *progressTracker
+}
+
+var _ helpers.ProgressWriter = progressWriter{}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.tracker.add(int64(len(p)))
+	return len(p), nil
+}
+
+func (w progressWriter) Close() error {
+	return nil
+}
+
+func (w progressWriter) Updatef(format string, a ...any) {
+	w.tracker.update("", w.tracker.complete.Load(), fmt.Sprintf(format, a...))
+}
+
+func (w progressWriter) Successf(format string, a ...any) {
+	w.tracker.success(format, a...)
+}
+
+func (w progressWriter) Failf(_ string, _ ...any) {
+	w.tracker.stop()
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []ProgressEvent
+}
+
+func (r *recordingReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// lifecycle returns the started, completed and failed events of a phase in order.
+func (r *recordingReporter) lifecycle(phase ProgressPhase) []ProgressEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := []ProgressEventType{}
+	for _, event := range r.events {
+		if event.Phase == phase && event.Type != ProgressUpdated {
+			types = append(types, event.Type)
+		}
+	}
+	return types
+}
+
+func (r *recordingReporter) updates(phase ProgressPhase) []ProgressEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := []ProgressEvent{}
+	for _, event := range r.events {
+		if event.Phase == phase && event.Type == ProgressUpdated {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	img, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	ref := fmt.Sprintf("%s/progress:1.0.0", host)
+	require.NoError(t, crane.Push(img, ref, crane.Insecure))
+	refInfo, err := transform.ParseImageRef(ref)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	pullReporter := &recordingReporter{}
+	_, err = Pull(context.Background(), PullConfig{
+		DestinationDirectory: filepath.Join(dir, "images"),
+		ImageList:            []transform.Image{refInfo},
+		Arch:                 "amd64",
+		CacheDirectory:       filepath.Join(dir, "cache"),
+		Progress:             pullReporter,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ProgressEventType{ProgressStarted, ProgressCompleted}, pullReporter.lifecycle(PhaseFetch))
+	require.Equal(t, []ProgressEventType{ProgressStarted, ProgressCompleted}, pullReporter.lifecycle(PhaseSave))
+	fetchUpdates := pullReporter.updates(PhaseFetch)
+	require.Len(t, fetchUpdates, 1)
+	require.Equal(t, ref, fetchUpdates[0].Image)
+	require.Equal(t, int64(1), fetchUpdates[0].Complete)
+	require.Equal(t, int64(1), fetchUpdates[0].Total)
+
+	pushReporter := &recordingReporter{}
+	_, err = Push(context.Background(), PushConfig{
+		SourceDirectory: filepath.Join(dir, "images"),
+		ImageList:       []transform.Image{refInfo},
+		RegInfo:         types.RegistryInfo{Address: host},
+		NoChecksum:      true,
+		Arch:            "amd64",
+		Retries:         1,
+		Progress:        pushReporter,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ProgressEventType{ProgressStarted, ProgressCompleted}, pushReporter.lifecycle(PhasePush))
+	pushUpdates := pushReporter.updates(PhasePush)
+	require.NotEmpty(t, pushUpdates)
+	require.Equal(t, ref, pushUpdates[0].Image)
+	require.Greater(t, pushUpdates[len(pushUpdates)-1].Complete, int64(0))
+}
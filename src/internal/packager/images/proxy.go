@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// proxyFunc returns the proxy of each request following the per-registry rules of proxy before its proxies.
+func proxyFunc(proxy types.ProxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	registries := map[string]*url.URL{}
+	for registry, rule := range proxy.Registries {
+		if rule.NoProxy || rule.Proxy == "" {
+			registries[registry] = nil
+			continue
+		}
+		u, err := url.Parse(rule.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q for registry %s: %w", rule.Proxy, registry, err)
+		}
+		registries[registry] = u
+	}
+	fallback := (&httpproxy.Config{
+		HTTPProxy:  proxy.HTTPProxy,
+		HTTPSProxy: proxy.HTTPSProxy,
+		NoProxy:    strings.Join(proxy.NoProxy, ","),
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		if u, ok := registries[req.URL.Host]; ok {
+			return u, nil
+		}
+		if u, ok := registries[req.URL.Hostname()]; ok {
+			return u, nil
+		}
+		return fallback(req.URL)
+	}, nil
+}
+
+// newPullTransport returns a transport that connects through proxy when it is set, trusting the system CAs along with
+// the CA bundles at caFiles.
+func newPullTransport(proxy types.ProxyConfig, caFiles ...string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy.IsSet() {
+		pf, err := proxyFunc(proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = pf
+	}
+
+	if proxy.CAFile != "" {
+		caFiles = append(caFiles, proxy.CAFile)
+	}
+	var pool *x509.CertPool
+	for _, caFile := range caFiles {
+		if caFile == "" {
+			continue
+		}
+		if pool == nil {
+			var err error
+			pool, err = x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		if err := utils.AppendCAFile(pool, caFile); err != nil {
+			return nil, err
+		}
+	}
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            pool,
+		InsecureSkipVerify: config.CommonOptions.Insecure,
+	}
+	return transport, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestProxyFunc(t *testing.T) {
+	t.Parallel()
+
+	proxy := types.ProxyConfig{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://secure-proxy.example.com:3128",
+		NoProxy:    []string{".internal.example.com", "10.0.0.0/8"},
+		Registries: map[string]types.RegistryProxy{
+			"ghcr.io":                   {Proxy: "http://ghcr-proxy.example.com:3128"},
+			"registry.example.com:5000": {NoProxy: true},
+		},
+	}
+	pf, err := proxyFunc(proxy)
+	require.NoError(t, err)
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://index.docker.io/v2/", expected: "http://secure-proxy.example.com:3128"},
+		{url: "http://insecure.example.com/v2/", expected: "http://proxy.example.com:3128"},
+		{url: "https://ghcr.io/v2/", expected: "http://ghcr-proxy.example.com:3128"},
+		{url: "https://registry.example.com:5000/v2/", expected: ""},
+		{url: "https://registry.example.com/v2/", expected: "http://secure-proxy.example.com:3128"},
+		{url: "https://mirror.internal.example.com/v2/", expected: ""},
+		{url: "https://10.1.2.3/v2/", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			u, err := pf(req)
+			require.NoError(t, err)
+			if tt.expected == "" {
+				require.Nil(t, u)
+				return
+			}
+			require.Equal(t, tt.expected, u.String())
+		})
+	}
+
+	_, err = proxyFunc(types.ProxyConfig{Registries: map[string]types.RegistryProxy{"ghcr.io": {Proxy: "http://[::1"}}})
+	require.ErrorContains(t, err, "invalid proxy \"http://[::1\" for registry ghcr.io")
+}
+
+func TestNewPullTransport(t *testing.T) {
+	t.Parallel()
+
+	transport, err := newPullTransport(types.ProxyConfig{})
+	require.NoError(t, err)
+	require.Nil(t, transport.TLSClientConfig.RootCAs)
+
+	_, err = newPullTransport(types.ProxyConfig{HTTPSProxy: "http://proxy.example.com:3128", CAFile: filepath.Join("testdata", "missing.pem")})
+	require.ErrorContains(t, err, "unable to read the CA bundle")
+}
@@ -13,6 +13,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -40,24 +41,31 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func checkForIndex(refInfo transform.Image, desc *remote.Descriptor) error {
-	if refInfo.Digest != "" && desc != nil && types.MediaType(desc.MediaType).IsIndex() {
-		var idx v1.IndexManifest
-		if err := json.Unmarshal(desc.Manifest, &idx); err != nil {
-			return fmt.Errorf("unable to unmarshal index.json: %w", err)
-		}
-		lines := []string{"The following images are available in the index:"}
-		name := refInfo.Name
-		if refInfo.Tag != "" {
-			name += ":" + refInfo.Tag
-		}
-		for _, desc := range idx.Manifests {
-			lines = append(lines, fmt.Sprintf("image - %s@%s with platform %s", name, desc.Digest.String(), desc.Platform.String()))
+// platformFromIndex returns the descriptor of the linux image for the given architecture when the descriptor is an
+// image index, or nil when it is not.
+func platformFromIndex(refInfo transform.Image, desc *remote.Descriptor, arch string) (*v1.Descriptor, error) {
+	if desc == nil || !types.MediaType(desc.MediaType).IsIndex() {
+		return nil, nil
+	}
+	var idx v1.IndexManifest
+	if err := json.Unmarshal(desc.Manifest, &idx); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal index.json: %w", err)
+	}
+	for _, manifest := range idx.Manifests {
+		if manifest.Platform != nil && manifest.Platform.OS == "linux" && manifest.Platform.Architecture == arch {
+			return &manifest, nil
 		}
-		imageOptions := strings.Join(lines, "\n")
-		return fmt.Errorf("%s resolved to an OCI image index which is not supported by Zarf, select a specific platform to use: %s", refInfo.Reference, imageOptions)
 	}
-	return nil
+	lines := []string{"The following images are available in the index:"}
+	name := refInfo.Name
+	if refInfo.Tag != "" {
+		name += ":" + refInfo.Tag
+	}
+	for _, manifest := range idx.Manifests {
+		lines = append(lines, fmt.Sprintf("image - %s@%s with platform %s", name, manifest.Digest.String(), manifest.Platform.String()))
+	}
+	imageOptions := strings.Join(lines, "\n")
+	return nil, fmt.Errorf("%s resolved to an OCI image index without a linux/%s image, select a specific platform to use: %s", refInfo.Reference, arch, imageOptions)
 }
 
 // Pull pulls all of the images from the given config.
@@ -80,20 +88,51 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		return nil, err
 	}
 
-	spinner := message.NewProgressSpinner("Fetching info for %d images. %s", imageCount, longer)
-	defer spinner.Stop()
+	fetchProgress := startProgress(cfg.Progress, PhaseFetch, int64(imageCount), fmt.Sprintf("Fetching info for %d images. %s", imageCount, longer))
+	defer fetchProgress.stop()
 
 	logs.Warn.SetOutput(&message.DebugWriter{})
 	logs.Progress.SetOutput(&message.DebugWriter{})
 
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
 	eg, ectx := errgroup.WithContext(ctx)
-	eg.SetLimit(10)
+	eg.SetLimit(concurrency)
 
 	var shaLock sync.Mutex
 	shas := map[string]bool{}
+	cacheEntries := []string{}
 	opts := CommonOpts(cfg.Arch)
+	if cfg.Proxy.IsSet() {
+		transport, err := newPullTransport(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, crane.WithTransport(registryTransport(transport)))
+	}
+
+	// Images exported from containerd are read from a tarball until they are saved
+	var containerdTmpDir string
+	var containerdTmpDirErr error
+	var containerdTmpDirOnce sync.Once
+	getContainerdTmpDir := func() (string, error) {
+		containerdTmpDirOnce.Do(func() {
+			containerdTmpDir, containerdTmpDirErr = utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		})
+		return containerdTmpDir, containerdTmpDirErr
+	}
+	containerdRefs := map[transform.Image]bool{}
+	defer func() {
+		if containerdTmpDir != "" {
+			os.RemoveAll(containerdTmpDir)
+		}
+	}()
 
 	fetched := map[transform.Image]v1.Image{}
+	fetchedIndexes := map[transform.Image]v1.ImageIndex{}
 
 	var counter, totalBytes atomic.Int64
 
@@ -101,7 +140,7 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		refInfo := refInfo
 		eg.Go(func() error {
 			idx := counter.Add(1)
-			spinner.Updatef("Fetching image info (%d of %d)", idx, imageCount)
+			fetchProgress.update(refInfo.Reference, idx, fmt.Sprintf("Fetching image info (%d of %d)", idx, imageCount))
 
 			ref := refInfo.Reference
 			for k, v := range cfg.RegistryOverrides {
@@ -112,6 +151,9 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 
 			var img v1.Image
 			var desc *remote.Descriptor
+			// The reference layers are downloaded from when the image comes from a registry, and the options to download them with
+			var remoteRef string
+			imgOpts := opts
 
 			// load from local fs if it's a tarball
 			if strings.HasSuffix(ref, ".tar") || strings.HasSuffix(ref, ".tar.gz") || strings.HasSuffix(ref, ".tgz") {
@@ -124,58 +166,95 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 				if err != nil {
 					return fmt.Errorf("failed to parse reference: %w", err)
 				}
-				desc, err = crane.Get(ref, opts...)
+				sources, err := imageSources(refInfo, cfg.RegistryOverrides, cfg.RegistryMirrors, cfg.Proxy, opts)
+				if err != nil {
+					return err
+				}
+				lockedDigest, locked := cfg.Digests[refInfo.Reference]
+				var src imageSource
+				desc, src, err = getFromSources(sources)
 				if err != nil {
 					if strings.Contains(err.Error(), "unexpected status code 429 Too Many Requests") {
 						return fmt.Errorf("rate limited by registry: %w", err)
 					}
+					// Local images cannot be compared to the digest they are locked to
+					if locked {
+						return fmt.Errorf("unable to find the locked image %s on a remote: %w", refInfo.Reference, err)
+					}
 
 					message.Warnf("Falling back to local 'docker', failed to find the manifest on a remote: %s", err.Error())
 
-					// Attempt to connect to the local docker daemon.
-					cli, err := client.NewClientWithOpts(client.FromEnv)
+					img, err = dockerImage(ectx, ref, reference)
 					if err != nil {
-						return fmt.Errorf("docker not available: %w", err)
+						message.Warnf("Falling back to local 'podman', failed to load the image from docker: %s", err.Error())
+						img, err = podmanImage(ectx, ref, podmanSockets())
 					}
-					cli.NegotiateAPIVersion(ectx)
-
-					// Inspect the image to get the size.
-					rawImg, _, err := cli.ImageInspectWithRaw(ectx, ref)
 					if err != nil {
-						return err
+						message.Warnf("Falling back to local 'containerd', failed to load the image from podman: %s", err.Error())
+
+						tmpDir, err := getContainerdTmpDir()
+						if err != nil {
+							return err
+						}
+						img, err = containerdImage(ectx, ref, cfg, tmpDir)
+						if err != nil {
+							return err
+						}
+						shaLock.Lock()
+						containerdRefs[refInfo] = true
+						shaLock.Unlock()
 					}
-
-					// Warn the user if the image is large.
-					if rawImg.Size > 750*1000*1000 {
-						message.Warnf("%s is %s and may take a very long time to load via docker. "+
-							"See https://docs.zarf.dev/faq for suggestions on how to improve large local image loading operations.",
-							ref, utils.ByteFormat(float64(rawImg.Size), 2))
+				} else {
+					if locked && desc.Digest.String() != lockedDigest {
+						return fmt.Errorf("%s resolved to %s instead of the locked digest %s, the tag changed since it was locked", refInfo.Reference, desc.Digest, lockedDigest)
 					}
-
-					// Use unbuffered opener to avoid OOM Kill issues https://github.com/zarf-dev/zarf/issues/1214.
-					// This will also take forever to load large images.
-					img, err = daemon.Image(reference, daemon.WithUnbufferedOpener())
-					if err != nil {
-						return fmt.Errorf("failed to load from docker daemon: %w", err)
+					if src.ref != refInfo.Reference {
+						message.Debugf("Pulling %s from %s", refInfo.Reference, src.ref)
 					}
-				} else {
-					img, err = crane.Pull(ref, opts...)
+					imgOpts = src.opts
+					img, err = crane.Pull(src.ref, imgOpts...)
 					if err != nil {
 						return fmt.Errorf("unable to pull image %s: %w", refInfo.Reference, err)
 					}
+					remoteRef = src.ref
 				}
 			}
 
-			if err := checkForIndex(refInfo, desc); err != nil {
+			platformDesc, err := platformFromIndex(refInfo, desc, cfg.Arch)
+			if err != nil {
 				return err
 			}
+			// Workloads reference images pinned to the digest of an index by that digest, and a registry only accepts
+			// an index with all of the images it references, so the whole index is kept for them too
+			if platformDesc != nil && (cfg.AllPlatforms || refInfo.Digest != "") {
+				if !cfg.AllPlatforms {
+					message.Debugf("%s is pinned to the digest of an image index, keeping every platform of the index", refInfo.Reference)
+				}
+				idx, err := desc.ImageIndex()
+				if err != nil {
+					return fmt.Errorf("unable to read the image index for %s: %w", refInfo.Reference, err)
+				}
+				size, err := indexSize(idx)
+				if err != nil {
+					return fmt.Errorf("unable to get the size of the image index for %s: %w", refInfo.Reference, err)
+				}
+				totalBytes.Add(size)
+				shaLock.Lock()
+				fetchedIndexes[refInfo] = idx
+				shaLock.Unlock()
+			}
 
 			cacheImg, err := utils.OnlyHasImageLayers(img)
 			if err != nil {
 				return err
 			}
 			if cacheImg {
-				img = cache.Image(img, cache.NewFilesystemCache(cfg.CacheDirectory))
+				// Layers pulled from a registry are downloaded resumably so interrupted pulls pick up where they left off
+				if remoteRef != "" {
+					img = cache.Image(img, newResumableCache(ctx, cfg.CacheDirectory, remoteRef, imgOpts))
+				} else {
+					img = cache.Image(img, cache.NewFilesystemCache(cfg.CacheDirectory))
+				}
 			}
 
 			manifest, err := img.Manifest()
@@ -199,6 +278,10 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 
 				if _, ok := shas[digest.Hex]; !ok {
 					shas[digest.Hex] = true
+					cacheEntries = append(cacheEntries, cacheFileName(digest))
+					if diffID, err := layer.DiffID(); err == nil {
+						cacheEntries = append(cacheEntries, cacheFileName(diffID))
+					}
 					size, err := layer.Size()
 					if err != nil {
 						return fmt.Errorf("unable to get size for image layer: %w", err)
@@ -221,16 +304,19 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		return nil, err
 	}
 
-	spinner.Successf("Fetched info for %d images", imageCount)
+	fetchProgress.success("Fetched info for %d images", imageCount)
 
-	doneSaving := make(chan error)
 	updateText := fmt.Sprintf("Pulling %d images", imageCount)
-	go utils.RenderProgressBarForLocalDirWrite(cfg.DestinationDirectory, totalBytes.Load(), doneSaving, updateText, updateText)
+	saveProgress := startProgress(cfg.Progress, PhaseSave, totalBytes.Load(), fmt.Sprintf("%s (%s of %s)", updateText, utils.ByteFormat(0, 2), utils.ByteFormat(float64(totalBytes.Load()), 2)))
+	defer saveProgress.stop()
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	defer stopProgress()
+	go saveProgress.trackDirSize(progressCtx, cfg.DestinationDirectory, updateText)
 
 	toPull := maps.Clone(fetched)
 
 	err = retry.Do(func() error {
-		saved, err := SaveConcurrent(ctx, cranePath, toPull)
+		saved, err := SaveConcurrent(ctx, cranePath, toPull, concurrency)
 		for k := range saved {
 			delete(toPull, k)
 		}
@@ -250,9 +336,18 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		}
 	}
 
-	// Send a signal to the progress bar that we're done and wait for the thread to finish
-	doneSaving <- nil
-	<-doneSaving
+	// Image indexes are saved alongside the platform image so that the index digest can be pushed as-is
+	for refInfo, idx := range fetchedIndexes {
+		annotations := map[string]string{
+			ocispec.AnnotationBaseImageName: refInfo.Reference,
+		}
+		if err := cranePath.AppendIndex(idx, clayout.WithAnnotations(annotations)); err != nil {
+			return nil, fmt.Errorf("unable to save the image index for %s: %w", refInfo.Reference, err)
+		}
+	}
+
+	stopProgress()
+	saveProgress.success("%s (%s)", updateText, utils.ByteFormat(float64(totalBytes.Load()), 2))
 
 	// Needed because when pulling from the local docker daemon, while using the docker containerd runtime
 	// Crane incorrectly names the blob of the docker image config to a sha that does not match the contents
@@ -279,9 +374,101 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		return nil, err
 	}
 
+	// Reload images exported from containerd from the layout since their tarballs are removed once pulling is done
+	for refInfo := range containerdRefs {
+		img, err := utils.LoadOCIImage(cfg.DestinationDirectory, refInfo)
+		if err != nil {
+			return nil, err
+		}
+		fetched[refInfo] = img
+	}
+
+	if cfg.CacheDirectory != "" {
+		linked, err := LinkLayers(cfg.DestinationDirectory, cfg.CacheDirectory)
+		if err != nil {
+			message.WarnErr(err, "Failed to share the pulled layers with the image layer cache")
+		} else if linked > 0 {
+			message.Debugf("Linked %s of layers from the image layer cache", utils.ByteFormat(float64(linked), 2))
+		}
+		if err := utils.TouchCacheEntries(cfg.CacheDirectory, cacheEntries...); err != nil {
+			message.WarnErr(err, "Failed to mark the pulled layers as recently used in the cache")
+		}
+		if cfg.CacheMaxSize > 0 {
+			freed, err := utils.PruneCacheDir(cfg.CacheDirectory, utils.CachePruneOptions{MaxSize: cfg.CacheMaxSize})
+			if err != nil {
+				message.WarnErr(err, "Failed to prune the image layer cache")
+			} else if freed > 0 {
+				message.Debugf("Pruned %s from the image layer cache", utils.ByteFormat(float64(freed), 2))
+			}
+		}
+	}
+
 	return fetched, nil
 }
 
+// dockerImage loads an image from the local docker daemon.
+func dockerImage(ctx context.Context, ref string, reference name.Reference) (v1.Image, error) {
+	// Attempt to connect to the local docker daemon.
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, fmt.Errorf("docker not available: %w", err)
+	}
+	cli.NegotiateAPIVersion(ctx)
+
+	// Inspect the image to get the size.
+	rawImg, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Warn the user if the image is large.
+	if rawImg.Size > 750*1000*1000 {
+		message.Warnf("%s is %s and may take a very long time to load via docker. "+
+			"See https://docs.zarf.dev/faq for suggestions on how to improve large local image loading operations.",
+			ref, utils.ByteFormat(float64(rawImg.Size), 2))
+	}
+
+	// Use unbuffered opener to avoid OOM Kill issues https://github.com/zarf-dev/zarf/issues/1214.
+	// This will also take forever to load large images.
+	img, err := daemon.Image(reference, daemon.WithUnbufferedOpener())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from docker daemon: %w", err)
+	}
+	return img, nil
+}
+
+// indexSize returns the size of the layers and configs of every image in an index.
+func indexSize(idx v1.ImageIndex) (int64, error) {
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, desc := range idxManifest.Manifests {
+		if !desc.MediaType.IsImage() {
+			continue
+		}
+		img, err := idx.Image(desc.Digest)
+		if err != nil {
+			return 0, err
+		}
+		imgSize, err := calcImgSize(img)
+		if err != nil {
+			return 0, err
+		}
+		size += imgSize
+	}
+	return size, nil
+}
+
+// cacheFileName returns the name the filesystem cache uses to store the layer with the given hash.
+func cacheFileName(h v1.Hash) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("%s-%s", h.Algorithm, h.Hex)
+	}
+	return h.String()
+}
+
 // CleanupInProgressLayers removes incomplete layers from the cache.
 func CleanupInProgressLayers(ctx context.Context, img v1.Image) error {
 	layers, err := img.Layers()
@@ -338,14 +525,14 @@ func SaveSequential(ctx context.Context, cl clayout.Path, m map[transform.Image]
 	return saved, nil
 }
 
-// SaveConcurrent saves images in a concurrent, bounded manner.
-func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image) (map[transform.Image]v1.Image, error) {
+// SaveConcurrent saves images in a concurrent manner, bounded to concurrency images at once.
+func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image, concurrency int) (map[transform.Image]v1.Image, error) {
 	saved := map[transform.Image]v1.Image{}
 
 	var mu sync.Mutex
 
 	eg, ectx := errgroup.WithContext(ctx)
-	eg.SetLimit(10)
+	eg.SetLimit(concurrency)
 
 	for info, img := range m {
 		info, img := info, img
@@ -8,47 +8,63 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 )
 
-func TestCheckForIndex(t *testing.T) {
+func TestPlatformFromIndex(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
-		name        string
-		ref         string
-		file        string
-		expectedErr string
+		name           string
+		ref            string
+		file           string
+		arch           string
+		expectedDigest string
+		expectedErr    string
 	}{
 		{
-			name:        "index sha",
-			ref:         "ghcr.io/zarf-dev/zarf/agent:v0.32.6@sha256:05a82656df5466ce17c3e364c16792ae21ce68438bfe06eeab309d0520c16b48",
-			file:        "agent-index.json",
-			expectedErr: "%s resolved to an OCI image index which is not supported by Zarf, select a specific platform to use",
+			name:           "index sha",
+			ref:            "ghcr.io/zarf-dev/zarf/agent:v0.32.6@sha256:05a82656df5466ce17c3e364c16792ae21ce68438bfe06eeab309d0520c16b48",
+			file:           "agent-index.json",
+			arch:           "amd64",
+			expectedDigest: "sha256:454bf871b5d826b6a31ab14c983583ae9d9e30c2036606b500368c5b552d8fdf",
 		},
 		{
-			name:        "docker manifest list",
-			ref:         "defenseunicorns/zarf-game@sha256:0b694ca1c33afae97b7471488e07968599f1d2470c629f76af67145ca64428af",
-			file:        "game-index.json",
-			expectedErr: "%s resolved to an OCI image index which is not supported by Zarf, select a specific platform to use",
+			name:           "docker manifest list",
+			ref:            "defenseunicorns/zarf-game@sha256:0b694ca1c33afae97b7471488e07968599f1d2470c629f76af67145ca64428af",
+			file:           "game-index.json",
+			arch:           "arm64",
+			expectedDigest: "sha256:e4d27fe4b7bf6d5cb7ef02ed0d33ec0846796c09d6ed4bd94c8b946119a01b09",
 		},
 		{
-			name:        "image manifest",
-			ref:         "ghcr.io/zarf-dev/zarf/agent:v0.32.6",
-			file:        "agent-manifest.json",
-			expectedErr: "",
+			name:        "missing platform",
+			ref:         "ghcr.io/zarf-dev/zarf/agent:v0.32.6@sha256:05a82656df5466ce17c3e364c16792ae21ce68438bfe06eeab309d0520c16b48",
+			file:        "agent-index.json",
+			arch:        "s390x",
+			expectedErr: "%s resolved to an OCI image index without a linux/s390x image, select a specific platform to use",
 		},
 		{
-			name:        "image manifest sha'd",
-			ref:         "ghcr.io/zarf-dev/zarf/agent:v0.32.6@sha256:b3fabdc7d4ecd0f396016ef78da19002c39e3ace352ea0ae4baa2ce9d5958376",
-			file:        "agent-manifest.json",
-			expectedErr: "",
+			name: "image manifest",
+			ref:  "ghcr.io/zarf-dev/zarf/agent:v0.32.6",
+			file: "agent-manifest.json",
+			arch: "amd64",
+		},
+		{
+			name: "image manifest sha'd",
+			ref:  "ghcr.io/zarf-dev/zarf/agent:v0.32.6@sha256:b3fabdc7d4ecd0f396016ef78da19002c39e3ace352ea0ae4baa2ce9d5958376",
+			file: "agent-manifest.json",
+			arch: "amd64",
 		},
 	}
 
@@ -69,7 +85,7 @@ func TestCheckForIndex(t *testing.T) {
 				},
 				Manifest: manifest,
 			}
-			err = checkForIndex(refInfo, desc)
+			platformDesc, err := platformFromIndex(refInfo, desc, tc.arch)
 			if tc.expectedErr != "" {
 				require.ErrorContains(t, err, fmt.Sprintf(tc.expectedErr, refInfo.Reference))
 				// Ensure the error message contains the digest of the manifests the user can use
@@ -79,6 +95,11 @@ func TestCheckForIndex(t *testing.T) {
 				return
 			}
 			require.NoError(t, err)
+			if tc.expectedDigest == "" {
+				require.Nil(t, platformDesc)
+				return
+			}
+			require.Equal(t, tc.expectedDigest, platformDesc.Digest.String())
 		})
 	}
 }
@@ -106,3 +127,40 @@ func TestPull(t *testing.T) {
 		require.Empty(t, dir)
 	})
 }
+
+func TestPullLockedDigest(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	ref := fmt.Sprintf("%s/app:1.0.0", strings.TrimPrefix(srv.URL, "http://"))
+	refInfo, err := transform.ParseImageRef(ref)
+	require.NoError(t, err)
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(img, ref, crane.Insecure))
+	digest, err := img.Digest()
+	require.NoError(t, err)
+
+	pull := func(t *testing.T, lockedDigest string) error {
+		t.Helper()
+		dir := t.TempDir()
+		_, err := Pull(context.Background(), PullConfig{
+			DestinationDirectory: filepath.Join(dir, "images"),
+			ImageList:            []transform.Image{refInfo},
+			Arch:                 "amd64",
+			CacheDirectory:       filepath.Join(dir, "cache"),
+			Digests:              map[string]string{refInfo.Reference: lockedDigest},
+		})
+		return err
+	}
+	require.NoError(t, pull(t, digest.String()))
+
+	// The tag is moved to another image after it was locked
+	drifted, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(drifted, ref, crane.Insecure))
+	err = pull(t, digest.String())
+	require.ErrorContains(t, err, fmt.Sprintf("instead of the locked digest %s", digest))
+}
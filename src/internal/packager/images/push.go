@@ -13,31 +13,61 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/logs"
+	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 
+	"github.com/zarf-dev/zarf/src/internal/faultinject"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
-// Push pushes images to a registry.
-func Push(ctx context.Context, cfg PushConfig) error {
+// Push pushes images to a registry, returning the references of the pushed images by digest on the registry keyed by
+// their original references.
+func Push(ctx context.Context, cfg PushConfig) (map[string]string, error) {
 	logs.Warn.SetOutput(&message.DebugWriter{})
 	logs.Progress.SetOutput(&message.DebugWriter{})
 
 	toPush := map[transform.Image]v1.Image{}
+	indexes := map[transform.Image]v1.ImageIndex{}
+	// Digests of the images, kept as toPush only holds the images that are left to push
+	digests := map[transform.Image]string{}
 	var totalSize int64
 	// Build an image list from the references
 	for _, refInfo := range cfg.ImageList {
 		img, err := utils.LoadOCIImage(cfg.SourceDirectory, refInfo)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		toPush[refInfo] = img
+		idx, err := utils.LoadOCIImageIndex(cfg.SourceDirectory, refInfo)
+		if err != nil {
+			return nil, err
+		}
+		if idx != nil {
+			indexes[refInfo] = idx
+			idxDigest, err := idx.Digest()
+			if err != nil {
+				return nil, err
+			}
+			digests[refInfo] = idxDigest.String()
+			idxSize, err := indexSize(idx)
+			if err != nil {
+				return nil, err
+			}
+			totalSize += idxSize
+			continue
+		}
+		imgDigest, err := img.Digest()
+		if err != nil {
+			return nil, err
+		}
+		digests[refInfo] = imgDigest.String()
 		imgSize, err := calcImgSize(img)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		totalSize += imgSize
 	}
@@ -53,8 +83,17 @@ func Push(ctx context.Context, cfg PushConfig) error {
 		registryURL = cfg.RegInfo.Address
 	)
 
-	progress := message.NewProgressBar(totalSize, fmt.Sprintf("Pushing %d images", len(toPush)))
-	defer progress.Close()
+	// Blobs are mounted from the repositories they were pushed to by earlier images and destinations that were
+	// pushed are not pushed again when the push is retried after the connection to the registry dropped
+	mounts := blobMounts{}
+	checksumPushed := map[transform.Image]bool{}
+
+	var progress *progressTracker
+	defer func() {
+		if progress != nil {
+			progress.stop()
+		}
+	}()
 
 	err = retry.Do(func() error {
 		c, _ := cluster.NewCluster()
@@ -68,15 +107,37 @@ func Push(ctx context.Context, cfg PushConfig) error {
 			}
 		}
 
-		progress = message.NewProgressBar(totalSize, fmt.Sprintf("Pushing %d images", len(toPush)))
-		pushOptions := createPushOpts(cfg, progress)
+		if progress != nil {
+			progress.stop()
+		}
+		progress = startProgress(cfg.Progress, PhasePush, totalSize, fmt.Sprintf("Pushing %d images", len(toPush)))
+		pushOptions := crane.GetOptions(createPushOpts(cfg, progressWriter{tracker: progress})...)
 
-		pushImage := func(img v1.Image, name string) error {
+		pushImage := func(refInfo transform.Image, img v1.Image, dst string) error {
+			if err := faultinject.Trigger(faultinject.RegistryPush); err != nil {
+				return err
+			}
+			ref, err := name.ParseReference(dst, pushOptions.Name...)
+			if err != nil {
+				return fmt.Errorf("failed to parse reference %s: %w", dst, err)
+			}
+			push := func() error {
+				if idx, ok := indexes[refInfo]; ok {
+					if err := remote.WriteIndex(ref, mounts.index(idx, ref.Context()), pushOptions.Remote...); err != nil {
+						return err
+					}
+					return mounts.recordIndex(idx, ref.Context())
+				}
+				if err := remote.Write(ref, mounts.image(img, ref.Context()), pushOptions.Remote...); err != nil {
+					return err
+				}
+				return mounts.recordImage(img, ref.Context())
+			}
 			if tunnel != nil {
-				return tunnel.Wrap(func() error { return crane.Push(img, name, pushOptions...) })
+				return tunnel.Wrap(push)
 			}
 
-			return crane.Push(img, name, pushOptions...)
+			return push()
 		}
 
 		pushed := []transform.Image{}
@@ -87,24 +148,31 @@ func Push(ctx context.Context, cfg PushConfig) error {
 		}()
 		for refInfo, img := range toPush {
 			refTruncated := helpers.Truncate(refInfo.Reference, 55, true)
-			progress.Updatef(fmt.Sprintf("Pushing %s", refTruncated))
+			progress.update(refInfo.Reference, progress.complete.Load(), fmt.Sprintf("Pushing %s", refTruncated))
 
 			size, err := calcImgSize(img)
 			if err != nil {
 				return err
 			}
+			if idx, ok := indexes[refInfo]; ok {
+				size, err = indexSize(idx)
+				if err != nil {
+					return err
+				}
+			}
 
 			// If this is not a no checksum image push it for use with the Zarf agent
-			if !cfg.NoChecksum {
+			if !cfg.NoChecksum && !checksumPushed[refInfo] {
 				offlineNameCRC, err := transform.ImageTransformHost(registryURL, refInfo.Reference)
 				if err != nil {
 					return err
 				}
 
-				if err = pushImage(img, offlineNameCRC); err != nil {
+				if err = pushImage(refInfo, img, offlineNameCRC); err != nil {
 					return err
 				}
 
+				checksumPushed[refInfo] = true
 				totalSize -= size
 			}
 
@@ -117,7 +185,7 @@ func Push(ctx context.Context, cfg PushConfig) error {
 
 			message.Debugf("push %s -> %s)", refInfo.Reference, offlineName)
 
-			if err = pushImage(img, offlineName); err != nil {
+			if err = pushImage(refInfo, img, offlineName); err != nil {
 				return err
 			}
 
@@ -127,12 +195,33 @@ func Push(ctx context.Context, cfg PushConfig) error {
 		return nil
 	}, retry.Context(ctx), retry.Attempts(uint(cfg.Retries)), retry.Delay(500*time.Millisecond))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	progress.Successf("Pushed %d images", len(cfg.ImageList))
+	progress.success("Pushed %d images", len(cfg.ImageList))
 
-	return nil
+	return digestReferences(cfg.RegInfo.Address, cfg.ImageList, digests)
+}
+
+// digestReferences returns the references by digest on the registry of the images keyed by their original references.
+func digestReferences(registryAddress string, imageList []transform.Image, digests map[transform.Image]string) (map[string]string, error) {
+	references := map[string]string{}
+	for _, refInfo := range imageList {
+		digest, ok := digests[refInfo]
+		if !ok {
+			continue
+		}
+		offlineName, err := transform.ImageTransformHostWithoutChecksum(registryAddress, refInfo.Reference)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := name.ParseReference(offlineName, name.WeakValidation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reference %s: %w", offlineName, err)
+		}
+		references[refInfo.Reference] = fmt.Sprintf("%s@%s", ref.Context().Name(), digest)
+	}
+	return references, nil
 }
 
 func calcImgSize(img v1.Image) (int64, error) {
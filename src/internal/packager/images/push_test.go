@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// recordingRegistry is a registry that records the requests it receives and can fail them.
+type recordingRegistry struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	// fail returns whether to drop the connection or the status code to fail a request with
+	fail    func(r *http.Request) (drop bool, status int)
+	handler http.Handler
+}
+
+func (rr *recordingRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rr.mu.Lock()
+	rr.requests = append(rr.requests, r)
+	drop, status := false, 0
+	if rr.fail != nil {
+		drop, status = rr.fail(r)
+	}
+	rr.mu.Unlock()
+	if drop {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	rr.handler.ServeHTTP(w, r)
+}
+
+func (rr *recordingRegistry) count(method, pathPrefix string, match func(r *http.Request) bool) int {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	n := 0
+	for _, r := range rr.requests {
+		if r.Method == method && strings.HasPrefix(r.URL.Path, pathPrefix) && (match == nil || match(r)) {
+			n++
+		}
+	}
+	return n
+}
+
+// pullTestImages pushes imgs to a source registry and pulls them into a package image layout.
+func pullTestImages(t *testing.T, imgs map[string]v1.Image) (string, []transform.Image) {
+	t.Helper()
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	refs := []transform.Image{}
+	for repo, img := range imgs {
+		ref := fmt.Sprintf("%s/%s:1.0.0", host, repo)
+		require.NoError(t, crane.Push(img, ref, crane.Insecure))
+		refInfo, err := transform.ParseImageRef(ref)
+		require.NoError(t, err)
+		refs = append(refs, refInfo)
+	}
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "images")
+	_, err := Pull(context.Background(), PullConfig{
+		DestinationDirectory: dir,
+		CacheDirectory:       filepath.Join(tmp, "cache"),
+		ImageList:            refs,
+		Arch:                 "amd64",
+		Progress:             DiscardProgress,
+	})
+	require.NoError(t, err)
+	return dir, refs
+}
+
+func TestPushMountsSharedLayers(t *testing.T) {
+	t.Parallel()
+
+	base, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	extra, err := random.Layer(1024, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	require.NoError(t, err)
+	derived, err := mutate.AppendLayers(base, extra)
+	require.NoError(t, err)
+	dir, refs := pullTestImages(t, map[string]v1.Image{"base": base, "derived": derived})
+
+	dst := &recordingRegistry{
+		handler: registry.New(),
+		fail: func(r *http.Request) (bool, int) {
+			// The test registry shares blobs between repositories, report them missing so they are mounted
+			if r.Method == http.MethodHead && strings.Contains(r.URL.Path, "/blobs/") {
+				return false, http.StatusNotFound
+			}
+			return false, 0
+		},
+	}
+	srv := httptest.NewServer(dst)
+	t.Cleanup(srv.Close)
+
+	_, err = Push(context.Background(), PushConfig{
+		SourceDirectory: dir,
+		ImageList:       refs,
+		RegInfo:         types.RegistryInfo{Address: strings.TrimPrefix(srv.URL, "http://")},
+		NoChecksum:      true,
+		Arch:            "amd64",
+		Retries:         1,
+		Progress:        DiscardProgress,
+	})
+	require.NoError(t, err)
+
+	// The layers of the base image are mounted into whichever repository is pushed second
+	mounted := dst.count(http.MethodPost, "/v2/", func(r *http.Request) bool {
+		return r.URL.Query().Get("mount") != "" && r.URL.Query().Get("from") != ""
+	})
+	require.Equal(t, 2, mounted)
+}
+
+func TestPushResumes(t *testing.T) {
+	t.Parallel()
+
+	img, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	dir, refs := pullTestImages(t, map[string]v1.Image{"resume": img})
+
+	droppedUpload := false
+	failedTag := false
+	dst := &recordingRegistry{
+		handler: registry.New(),
+		fail: func(r *http.Request) (bool, int) {
+			// Drop the connection of the first blob upload
+			if r.Method == http.MethodPatch && !droppedUpload {
+				droppedUpload = true
+				return true, 0
+			}
+			// Fail the first push of the tag without a checksum so the image push is retried
+			if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/manifests/1.0.0") && !failedTag {
+				failedTag = true
+				return false, http.StatusBadRequest
+			}
+			return false, 0
+		},
+	}
+	srv := httptest.NewServer(dst)
+	t.Cleanup(srv.Close)
+
+	pushed, err := Push(context.Background(), PushConfig{
+		SourceDirectory: dir,
+		ImageList:       refs,
+		RegInfo:         types.RegistryInfo{Address: strings.TrimPrefix(srv.URL, "http://")},
+		Arch:            "amd64",
+		Retries:         2,
+		Progress:        DiscardProgress,
+	})
+	require.NoError(t, err)
+
+	// The dropped blob upload is retried on its own, the other blobs are uploaded once
+	layers, err := img.Layers()
+	require.NoError(t, err)
+	require.Equal(t, len(layers)+2, dst.count(http.MethodPatch, "/v2/resume/blobs/uploads/", nil))
+	// The tag with a checksum is not pushed again when the image push is retried
+	require.Equal(t, 1, dst.count(http.MethodPut, "/v2/resume/manifests/1.0.0-zarf-", nil))
+	require.Equal(t, 2, dst.count(http.MethodPut, "/v2/resume/manifests/1.0.0", func(r *http.Request) bool {
+		return strings.HasSuffix(r.URL.Path, "/manifests/1.0.0")
+	}))
+
+	// The pushed image is returned by its digest on the registry
+	host := strings.TrimPrefix(srv.URL, "http://")
+	digest, err := crane.Digest(host+"/resume:1.0.0", crane.Insecure)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{refs[0].Reference: host + "/resume@" + digest}, pushed)
+}
+
+func TestPushDigestPinnedIndex(t *testing.T) {
+	t.Parallel()
+
+	src := httptest.NewServer(registry.New())
+	t.Cleanup(src.Close)
+	amd64, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	arm64, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+	)
+	idxDigest, err := idx.Digest()
+	require.NoError(t, err)
+	tagRef := fmt.Sprintf("%s/app:1.0.0", strings.TrimPrefix(src.URL, "http://"))
+	ref, err := name.ParseReference(tagRef, name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(ref, idx))
+	refInfo, err := transform.ParseImageRef(fmt.Sprintf("%s@%s", tagRef, idxDigest))
+	require.NoError(t, err)
+
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "images")
+	_, err = Pull(context.Background(), PullConfig{
+		DestinationDirectory: dir,
+		CacheDirectory:       filepath.Join(tmp, "cache"),
+		ImageList:            []transform.Image{refInfo},
+		Arch:                 "amd64",
+		Progress:             DiscardProgress,
+	})
+	require.NoError(t, err)
+
+	dst := httptest.NewServer(registry.New())
+	t.Cleanup(dst.Close)
+	host := strings.TrimPrefix(dst.URL, "http://")
+	pushed, err := Push(context.Background(), PushConfig{
+		SourceDirectory: dir,
+		ImageList:       []transform.Image{refInfo},
+		RegInfo:         types.RegistryInfo{Address: host},
+		Arch:            "amd64",
+		Retries:         1,
+		Progress:        DiscardProgress,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{refInfo.Reference: host + "/app@" + idxDigest.String()}, pushed)
+
+	// The index is pushed under the digest the Zarf agent rewrites workloads to
+	agentRef, err := transform.ImageTransformHost(host, refInfo.Reference)
+	require.NoError(t, err)
+	desc, err := crane.Head(agentRef, crane.Insecure)
+	require.NoError(t, err)
+	require.Equal(t, idxDigest, desc.Digest)
+	require.True(t, desc.MediaType.IsIndex())
+}
+
+func TestIsRetryableUploadError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{
+			name:      "dropped connection",
+			err:       fmt.Errorf("PATCH http://127.0.0.1:31999/v2/resume/blobs/uploads/1: %w", io.EOF),
+			retryable: true,
+		},
+		{
+			name:      "reset connection",
+			err:       &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+			retryable: true,
+		},
+		{
+			name:      "refused connection",
+			err:       &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			retryable: true,
+		},
+		{
+			name:      "unavailable registry",
+			err:       &transport.Error{StatusCode: http.StatusServiceUnavailable},
+			retryable: true,
+		},
+		{
+			name:      "unauthorized",
+			err:       &transport.Error{StatusCode: http.StatusUnauthorized},
+			retryable: false,
+		},
+		{
+			name:      "other",
+			err:       errors.New("manifest invalid"),
+			retryable: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.retryable, isRetryableUploadError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// cosignTagSuffixes are the tag suffixes cosign stores signatures, attestations and attached SBOMs under.
+var cosignTagSuffixes = []string{"sig", "att", "sbom"}
+
+// FindReferrers returns the references of the supply chain artifacts attached to an image, these are the cosign
+// signature, attestation and SBOM tags of the image and the manifests listing the image as their subject in the OCI
+// referrers API. The references are returned on the repository of the image so they are pulled and pushed like it.
+func FindReferrers(ctx context.Context, refInfo transform.Image, registryOverrides map[string]string, opts ...crane.Option) ([]string, error) {
+	o := crane.GetOptions(append(opts, crane.WithContext(ctx))...)
+
+	lookup := refInfo.Reference
+	for k, v := range registryOverrides {
+		if strings.HasPrefix(refInfo.Reference, k) {
+			lookup = strings.Replace(refInfo.Reference, k, v, 1)
+		}
+	}
+	lookupRef, err := name.ParseReference(lookup, o.Name...)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := name.NewRepository(refInfo.Name, o.Name...)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := remote.Head(lookupRef, o.Remote...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve the digest of %s: %w", refInfo.Reference, err)
+	}
+
+	referrers := []string{}
+	for _, suffix := range cosignTagSuffixes {
+		tag := fmt.Sprintf("%s-%s.%s", desc.Digest.Algorithm, desc.Digest.Hex, suffix)
+		_, err := remote.Head(lookupRef.Context().Tag(tag), o.Remote...)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to check for the cosign artifact %s of %s: %w", tag, refInfo.Reference, err)
+		}
+		referrers = append(referrers, repo.Tag(tag).String())
+	}
+
+	idx, err := remote.Referrers(lookupRef.Context().Digest(desc.Digest.String()), o.Remote...)
+	if err != nil {
+		// Not every registry supports the referrers API or its tag fallback, this should not fail the pull.
+		message.Warnf("Unable to list the OCI referrers of %s: %s", refInfo.Reference, err.Error())
+		return referrers, nil
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, referrer := range manifest.Manifests {
+		// Only manifests can be stored and pushed as package images.
+		if referrer.MediaType.IsIndex() {
+			message.Debugf("Skipping the referrer %s of %s as it is an index", referrer.Digest, refInfo.Reference)
+			continue
+		}
+		referrers = append(referrers, repo.Digest(referrer.Digest.String()).String())
+	}
+	return referrers, nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
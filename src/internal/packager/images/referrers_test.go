@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+func TestFindReferrers(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+	opts := []crane.Option{crane.Insecure}
+
+	push := func(t *testing.T, img v1.Image, ref string) v1.Hash {
+		t.Helper()
+		err := crane.Push(img, ref, opts...)
+		require.NoError(t, err)
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		return digest
+	}
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	digest := push(t, img, fmt.Sprintf("%s/app:1.0.0", host))
+
+	sig, err := random.Image(64, 1)
+	require.NoError(t, err)
+	push(t, sig, fmt.Sprintf("%s/app:sha256-%s.sig", host, digest.Hex))
+
+	ref, err := name.ParseReference(fmt.Sprintf("%s/app:1.0.0", host), name.Insecure)
+	require.NoError(t, err)
+	desc, err := remote.Head(ref)
+	require.NoError(t, err)
+	attestation, err := random.Image(64, 1)
+	require.NoError(t, err)
+	attestation = mutate.Subject(attestation, *desc).(v1.Image)
+	attestationDigest := push(t, attestation, fmt.Sprintf("%s/app:attestation", host))
+
+	refInfo, err := transform.ParseImageRef(fmt.Sprintf("%s/app:1.0.0", host))
+	require.NoError(t, err)
+	referrers, err := FindReferrers(context.Background(), refInfo, nil, opts...)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		fmt.Sprintf("%s/app:sha256-%s.sig", host, digest.Hex),
+		fmt.Sprintf("%s/app@%s", host, attestationDigest),
+	}, referrers)
+
+	// Lookups follow registry overrides while the referrers stay on the original repository.
+	refInfo, err = transform.ParseImageRef("registry.example.com/app:1.0.0")
+	require.NoError(t, err)
+	referrers, err = FindReferrers(context.Background(), refInfo, map[string]string{"registry.example.com": host}, opts...)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		fmt.Sprintf("registry.example.com/app:sha256-%s.sig", digest.Hex),
+		fmt.Sprintf("registry.example.com/app@%s", attestationDigest),
+	}, referrers)
+}
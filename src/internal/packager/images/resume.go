@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/cache"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// partialSuffix is appended to the cache file name of a layer while it is being downloaded.
+const partialSuffix = ".partial"
+
+// resumableCache is a filesystem cache that downloads missing layers from the registry into a partial file,
+// so a download that is interrupted continues from where it left off the next time the layer is pulled.
+type resumableCache struct {
+	cache.Cache
+	path    string
+	fetcher *blobFetcher
+}
+
+// newResumableCache returns a resumable filesystem cache at path for layers of the remote image ref.
+func newResumableCache(ctx context.Context, path, ref string, opts []crane.Option) cache.Cache {
+	return &resumableCache{
+		Cache: cache.NewFilesystemCache(path),
+		path:  path,
+		fetcher: &blobFetcher{
+			ctx:  ctx,
+			ref:  ref,
+			opts: crane.GetOptions(opts...),
+		},
+	}
+}
+
+// Put wraps the layer so its compressed contents are downloaded resumably into the cache.
+func (c *resumableCache) Put(l v1.Layer) (v1.Layer, error) {
+	cached, err := c.Cache.Put(l)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := l.Digest()
+	if err != nil {
+		return nil, err
+	}
+	size, err := l.Size()
+	if err != nil {
+		return nil, err
+	}
+	return &resumableLayer{
+		Layer:  cached,
+		cache:  c,
+		digest: digest,
+		size:   size,
+	}, nil
+}
+
+type resumableLayer struct {
+	v1.Layer
+	cache  *resumableCache
+	digest v1.Hash
+	size   int64
+}
+
+// Compressed replays the bytes already in the layer's partial file and streams the rest from the registry,
+// moving the partial file into the cache once the whole layer has been read and verified.
+func (l *resumableLayer) Compressed() (io.ReadCloser, error) {
+	if err := os.MkdirAll(l.cache.path, 0o700); err != nil {
+		return nil, err
+	}
+	finalPath := filepath.Join(l.cache.path, cacheFileName(l.digest))
+	partialPath := finalPath + partialSuffix
+	f, err := os.OpenFile(partialPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if offset >= l.size {
+		offset = 0
+	}
+
+	rc, start, err := l.cache.fetcher.fetch(l.digest, offset)
+	if err != nil {
+		f.Close()
+		message.Debugf("Unable to download layer %s resumably, downloading it in full: %s", l.digest, err.Error())
+		return l.Layer.Compressed()
+	}
+	if start > 0 {
+		message.Debugf("Resuming the download of layer %s at %s of %s", l.digest,
+			utils.ByteFormat(float64(start), 2), utils.ByteFormat(float64(l.size), 2))
+	}
+	if err := f.Truncate(start); err != nil {
+		rc.Close()
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		rc.Close()
+		f.Close()
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	existing := io.NewSectionReader(f, 0, start)
+	return &resumableReader{
+		r:           io.TeeReader(io.MultiReader(existing, io.TeeReader(rc, f)), hasher),
+		body:        rc,
+		file:        f,
+		hasher:      hasher,
+		layer:       l,
+		partialPath: partialPath,
+		finalPath:   finalPath,
+	}, nil
+}
+
+type resumableReader struct {
+	r           io.Reader
+	body        io.ReadCloser
+	file        *os.File
+	hasher      hash.Hash
+	read        int64
+	layer       *resumableLayer
+	partialPath string
+	finalPath   string
+	closeOnce   sync.Once
+	closeErr    error
+}
+
+func (rr *resumableReader) Read(b []byte) (int, error) {
+	n, err := rr.r.Read(b)
+	rr.read += int64(n)
+	if errors.Is(err, io.EOF) {
+		if finishErr := rr.finish(); finishErr != nil {
+			return n, finishErr
+		}
+	}
+	return n, err
+}
+
+// finish verifies the downloaded layer and moves it from its partial file into the cache.
+func (rr *resumableReader) finish() error {
+	if err := rr.Close(); err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(rr.hasher.Sum(nil))
+	if rr.read != rr.layer.size || sum != rr.layer.digest.Hex {
+		if err := os.Remove(rr.partialPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return fmt.Errorf("downloaded layer %s does not match its digest, got %d bytes with sha256:%s", rr.layer.digest, rr.read, sum)
+	}
+	return os.Rename(rr.partialPath, rr.finalPath)
+}
+
+// Close closes the download, the partial file is kept so an incomplete download can be resumed.
+func (rr *resumableReader) Close() error {
+	rr.closeOnce.Do(func() {
+		rr.closeErr = errors.Join(rr.body.Close(), rr.file.Close())
+	})
+	return rr.closeErr
+}
+
+// blobFetcher downloads blobs from a registry with HTTP range requests.
+type blobFetcher struct {
+	ctx  context.Context
+	ref  string
+	opts crane.Options
+
+	once   sync.Once
+	repo   name.Repository
+	client *http.Client
+	err    error
+}
+
+func (bf *blobFetcher) init() error {
+	bf.once.Do(func() {
+		reference, err := name.ParseReference(bf.ref, bf.opts.Name...)
+		if err != nil {
+			bf.err = err
+			return
+		}
+		bf.repo = reference.Context()
+		auth, err := bf.opts.Keychain.Resolve(bf.repo)
+		if err != nil {
+			bf.err = err
+			return
+		}
+		rt, err := transport.NewWithContext(bf.ctx, bf.repo.Registry, auth, bf.opts.Transport, []string{bf.repo.Scope(transport.PullScope)})
+		if err != nil {
+			bf.err = err
+			return
+		}
+		bf.client = &http.Client{Transport: rt}
+	})
+	return bf.err
+}
+
+// fetch requests the blob starting at offset, returning the body and the offset it actually starts at
+// which is zero when the registry does not support range requests.
+func (bf *blobFetcher) fetch(h v1.Hash, offset int64) (io.ReadCloser, int64, error) {
+	if err := bf.init(); err != nil {
+		return nil, 0, err
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", bf.repo.Scheme(), bf.repo.RegistryStr(), bf.repo.RepositoryStr(), h)
+	req, err := http.NewRequestWithContext(bf.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := bf.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := transport.CheckError(resp, http.StatusOK, http.StatusPartialContent); err != nil {
+		resp.Body.Close()
+		return nil, 0, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, 0, nil
+	}
+	var start, end, total int64
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil || start != offset {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("unexpected content range %q for offset %d", resp.Header.Get("Content-Range"), offset)
+	}
+	return resp.Body, start, nil
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableCache(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("zarf"), 4096)
+	layer := static.NewLayer(content, types.OCILayer)
+	digest, err := layer.Digest()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	ranges := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/zarf/test/blobs/"+digest.String() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		http.ServeContent(w, r, "blob", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(srv.Close)
+	ref := strings.TrimPrefix(srv.URL, "http://") + "/zarf/test:0.0.1"
+
+	tests := []struct {
+		name          string
+		partial       []byte
+		expectedRange string
+		expectedErr   string
+	}{
+		{
+			name:          "no partial download",
+			expectedRange: "",
+		},
+		{
+			name:          "resumes a partial download",
+			partial:       content[:1000],
+			expectedRange: "bytes=1000-",
+		},
+		{
+			name:          "corrupt partial download",
+			partial:       bytes.Repeat([]byte("x"), 1000),
+			expectedRange: "bytes=1000-",
+			expectedErr:   "does not match its digest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			finalPath := filepath.Join(cacheDir, cacheFileName(digest))
+			if tt.partial != nil {
+				require.NoError(t, os.WriteFile(finalPath+partialSuffix, tt.partial, 0o600))
+			}
+
+			mu.Lock()
+			ranges = []string{}
+			mu.Unlock()
+
+			c := newResumableCache(context.Background(), cacheDir, ref, []crane.Option{crane.Insecure})
+			cached, err := c.Put(layer)
+			require.NoError(t, err)
+			rc, err := cached.Compressed()
+			require.NoError(t, err)
+			b, err := io.ReadAll(rc)
+			require.NoError(t, rc.Close())
+
+			mu.Lock()
+			require.Equal(t, []string{tt.expectedRange}, ranges)
+			mu.Unlock()
+			require.NoFileExists(t, finalPath+partialSuffix)
+
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.NoFileExists(t, finalPath)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, content, b)
+			cachedContent, err := os.ReadFile(finalPath)
+			require.NoError(t, err)
+			require.Equal(t, content, cachedContent)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// SquashedFromAnnotation is the manifest annotation recording the digest of the image a squashed image was built from.
+const SquashedFromAnnotation = "dev.zarf.image.squashed-from"
+
+// SquashImages squashes the layers of the images in the OCI layout at path that squash returns true for into a single
+// layer, replacing the images in the layout and returning all images. Images referenced by digest and images saved with
+// their index are left as they are since squashing changes their digest.
+func SquashImages(ctx context.Context, path string, imgs map[transform.Image]v1.Image, squash func(info transform.Image) bool, concurrency int) (map[transform.Image]v1.Image, error) {
+	selected := map[transform.Image]v1.Image{}
+	for info, img := range imgs {
+		if squash(info) {
+			selected[info] = img
+		}
+	}
+	if len(selected) == 0 {
+		return imgs, nil
+	}
+
+	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	squashed, err := convertImages(ctx, path, selected, concurrency, func(img v1.Image) (v1.Image, [2]int64, error) {
+		return squashImage(img, tmpDir)
+	}, "Squashing image layers", "Squashed image layers")
+	if err != nil {
+		return nil, err
+	}
+	all := map[transform.Image]v1.Image{}
+	for info, img := range imgs {
+		all[info] = img
+	}
+	for info, img := range squashed {
+		all[info] = img
+	}
+	return all, nil
+}
+
+// squashImage returns img with its filesystem flattened into a single gzip layer staged in tmpDir and the size of its
+// layers before and after, or img itself when it has a single layer.
+func squashImage(img v1.Image, tmpDir string) (v1.Image, [2]int64, error) {
+	sizes := [2]int64{}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, sizes, err
+	}
+	if len(layers) <= 1 {
+		return img, sizes, nil
+	}
+	for _, layer := range layers {
+		size, err := layer.Size()
+		if err != nil {
+			return nil, sizes, err
+		}
+		sizes[0] += size
+	}
+
+	// Extract applies the whiteouts of each layer, leaving the filesystem the image runs with
+	tarFile, err := os.CreateTemp(tmpDir, "squashed-*.tar")
+	if err != nil {
+		return nil, sizes, err
+	}
+	defer tarFile.Close()
+	rc := mutate.Extract(img)
+	_, err = io.Copy(tarFile, rc)
+	rc.Close()
+	if err != nil {
+		return nil, sizes, err
+	}
+	if err := tarFile.Close(); err != nil {
+		return nil, sizes, err
+	}
+	layer, err := tarball.LayerFromFile(tarFile.Name(), tarball.WithMediaType(types.OCILayer))
+	if err != nil {
+		return nil, sizes, err
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return nil, sizes, err
+	}
+	sizes[1] = size
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, sizes, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, sizes, err
+	}
+	cfg = cfg.DeepCopy()
+	cfg.RootFS.DiffIDs = nil
+	cfg.History = nil
+	base, err := mutate.ConfigFile(mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), types.OCIConfigJSON), cfg)
+	if err != nil {
+		return nil, sizes, err
+	}
+	squashed, err := mutate.Append(base, mutate.Addendum{
+		Layer: layer,
+		History: v1.History{
+			Created:   cfg.Created,
+			CreatedBy: fmt.Sprintf("zarf squash of %s", digest),
+		},
+	})
+	if err != nil {
+		return nil, sizes, err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, sizes, err
+	}
+	annotations := map[string]string{}
+	for k, v := range manifest.Annotations {
+		annotations[k] = v
+	}
+	annotations[SquashedFromAnnotation] = digest.String()
+	return mutate.Annotations(squashed, annotations).(v1.Image), sizes, nil
+}
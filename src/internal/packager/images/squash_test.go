@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// tarLayer returns a gzip layer holding files with their contents.
+func tarLayer(t *testing.T, files map[string]string) v1.Layer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	}, tarball.WithMediaType(types.DockerLayer))
+	require.NoError(t, err)
+	return layer
+}
+
+func TestSquashImages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	lp, err := clayout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	img, err := mutate.AppendLayers(empty.Image,
+		tarLayer(t, map[string]string{"etc/removed": "removed", "etc/kept": "old"}),
+		tarLayer(t, map[string]string{"etc/.wh.removed": "", "etc/kept": "new", "bin/app": "app"}),
+	)
+	require.NoError(t, err)
+	img, err = mutate.Config(img, v1.Config{Env: []string{"APP=1"}})
+	require.NoError(t, err)
+	other, err := mutate.AppendLayers(empty.Image, tarLayer(t, map[string]string{"a": "a"}), tarLayer(t, map[string]string{"b": "b"}))
+	require.NoError(t, err)
+
+	imgs := map[transform.Image]v1.Image{}
+	for ref, image := range map[string]v1.Image{"docker.io/library/app:1.0.0": img, "docker.io/library/other:1.0.0": other} {
+		refInfo, err := transform.ParseImageRef(ref)
+		require.NoError(t, err)
+		require.NoError(t, lp.AppendImage(image, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: refInfo.Reference})))
+		imgs[refInfo] = image
+	}
+
+	squashed, err := SquashImages(context.Background(), dir, imgs, func(info transform.Image) bool {
+		return info.Name == "library/app"
+	}, 2)
+	require.NoError(t, err)
+	require.Len(t, squashed, len(imgs))
+
+	for refInfo, original := range imgs {
+		simg := squashed[refInfo]
+		if refInfo.Name != "library/app" {
+			require.Equal(t, original, simg)
+			continue
+		}
+		layers, err := simg.Layers()
+		require.NoError(t, err)
+		require.Len(t, layers, 1)
+
+		// The whiteout and overwritten file of the upper layer are applied
+		rc, err := layers[0].Uncompressed()
+		require.NoError(t, err)
+		files := map[string]string{}
+		tr := tar.NewReader(rc)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			b, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = string(b)
+		}
+		require.NoError(t, rc.Close())
+		require.Equal(t, map[string]string{"etc/kept": "new", "bin/app": "app"}, files)
+
+		cfg, err := simg.ConfigFile()
+		require.NoError(t, err)
+		require.Equal(t, []string{"APP=1"}, cfg.Config.Env)
+		require.Len(t, cfg.RootFS.DiffIDs, 1)
+		require.Len(t, cfg.History, 1)
+
+		manifest, err := simg.Manifest()
+		require.NoError(t, err)
+		require.Equal(t, types.OCIManifestSchema1, manifest.MediaType)
+		digest, err := original.Digest()
+		require.NoError(t, err)
+		require.Equal(t, digest.String(), manifest.Annotations[SquashedFromAnnotation])
+	}
+
+	// The layout references the squashed image in place of the original
+	idx, err := lp.ImageIndex()
+	require.NoError(t, err)
+	idxManifest, err := idx.IndexManifest()
+	require.NoError(t, err)
+	digests := []v1.Hash{}
+	for _, desc := range idxManifest.Manifests {
+		digests = append(digests, desc.Digest)
+	}
+	for refInfo := range imgs {
+		digest, err := squashed[refInfo].Digest()
+		require.NoError(t, err)
+		require.Contains(t, digests, digest)
+	}
+	require.Len(t, digests, len(imgs))
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// stalePartialAge is how long a partial layer download must be untouched before it is garbage collected.
+const stalePartialAge = time.Hour
+
+// LinkLayers stores the layers of the images in the OCI layout at layoutPath once in the layer cache at cacheDir.
+//
+// Layers already in the cache replace their copy in the layout with a hard link and layers missing from the cache are
+// hard linked into it, so that every package build shares a single copy of a layer. Layers are left as copies when
+// the cache and the layout are on different filesystems. It returns the number of bytes no longer stored twice.
+func LinkLayers(layoutPath, cacheDir string) (int64, error) {
+	layers, err := layoutLayers(layoutPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := helpers.CreateDirectory(cacheDir, helpers.ReadExecuteAllWriteUser); err != nil {
+		return 0, err
+	}
+
+	var linked int64
+	for _, layer := range layers {
+		blobPath := filepath.Join(layoutPath, "blobs", layer.Algorithm, layer.Hex)
+		blobInfo, err := os.Stat(blobPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Foreign layers are not saved in the layout
+			continue
+		}
+		if err != nil {
+			return linked, err
+		}
+		cachePath := filepath.Join(cacheDir, cacheFileName(layer))
+		cacheInfo, err := os.Stat(cachePath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			err = os.Link(blobPath, cachePath)
+		case err != nil:
+			return linked, err
+		case os.SameFile(blobInfo, cacheInfo):
+			continue
+		case blobInfo.Size() != cacheInfo.Size():
+			message.Debugf("Not linking layer %s as its size in the cache does not match", layer)
+			continue
+		default:
+			err = replaceWithLink(cachePath, blobPath)
+			if err == nil {
+				linked += blobInfo.Size()
+			}
+		}
+		if err != nil {
+			// Every layer is on the same filesystem so there is no point in trying the others
+			message.Debugf("Unable to link layers to the cache at %s, keeping copies: %s", cacheDir, err.Error())
+			return linked, nil
+		}
+	}
+	return linked, nil
+}
+
+// replaceWithLink atomically replaces the file at path with a hard link to target.
+func replaceWithLink(target, path string) error {
+	tmp := path + ".link"
+	if err := os.Link(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
+
+// layoutLayers returns the digests of the layers of every image in an OCI layout, including the images of saved indexes.
+func layoutLayers(layoutPath string) ([]v1.Hash, error) {
+	lp, err := clayout.FromPath(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := lp.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[v1.Hash]bool{}
+	layers := []v1.Hash{}
+	var walk func(idx v1.ImageIndex) error
+	walk = func(idx v1.ImageIndex) error {
+		idxManifest, err := idx.IndexManifest()
+		if err != nil {
+			return err
+		}
+		for _, desc := range idxManifest.Manifests {
+			if seen[desc.Digest] {
+				continue
+			}
+			seen[desc.Digest] = true
+			switch {
+			case desc.MediaType.IsIndex():
+				child, err := idx.ImageIndex(desc.Digest)
+				if err != nil {
+					return err
+				}
+				if err := walk(child); err != nil {
+					return err
+				}
+			case desc.MediaType.IsImage():
+				img, err := idx.Image(desc.Digest)
+				if err != nil {
+					return err
+				}
+				manifest, err := img.Manifest()
+				if err != nil {
+					return fmt.Errorf("unable to read the manifest of image %s: %w", desc.Digest, err)
+				}
+				for _, layer := range manifest.Layers {
+					if seen[layer.Digest] {
+						continue
+					}
+					seen[layer.Digest] = true
+					layers = append(layers, layer.Digest)
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(idx); err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
+// GarbageCollectLayerCache removes abandoned partial downloads from the layer cache at cacheDir and then prunes it
+// with the given options, returning the number of bytes freed.
+func GarbageCollectLayerCache(cacheDir string, opts utils.CachePruneOptions) (int64, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), partialSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return freed, err
+		}
+		// Partial downloads that were written to recently may still be in progress
+		if time.Since(info.ModTime()) < stalePartialAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return freed, err
+		}
+		freed += info.Size()
+	}
+	pruned, err := utils.PruneCacheDir(cacheDir, opts)
+	return freed + pruned, err
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+func TestLinkLayers(t *testing.T) {
+	t.Parallel()
+
+	img, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	layers, err := img.Layers()
+	require.NoError(t, err)
+
+	writeLayout := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		lp, err := clayout.Write(dir, empty.Index)
+		require.NoError(t, err)
+		require.NoError(t, lp.AppendImage(img))
+		return dir
+	}
+	requireLinked := func(t *testing.T, layoutPath, cacheDir string) {
+		t.Helper()
+		for _, layer := range layers {
+			digest, err := layer.Digest()
+			require.NoError(t, err)
+			blobInfo, err := os.Stat(filepath.Join(layoutPath, "blobs", digest.Algorithm, digest.Hex))
+			require.NoError(t, err)
+			cacheInfo, err := os.Stat(filepath.Join(cacheDir, cacheFileName(digest)))
+			require.NoError(t, err)
+			require.True(t, os.SameFile(blobInfo, cacheInfo))
+		}
+	}
+
+	cacheDir := filepath.Join(t.TempDir(), "images")
+
+	// Layers missing from the cache are linked into it
+	first := writeLayout(t)
+	linked, err := LinkLayers(first, cacheDir)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), linked)
+	requireLinked(t, first, cacheDir)
+
+	// Layers already in the cache replace the copies of other layouts
+	second := writeLayout(t)
+	linked, err = LinkLayers(second, cacheDir)
+	require.NoError(t, err)
+	var expected int64
+	for _, layer := range layers {
+		size, err := layer.Size()
+		require.NoError(t, err)
+		expected += size
+	}
+	require.Equal(t, expected, linked)
+	requireLinked(t, second, cacheDir)
+
+	// The layout is still a valid image after linking
+	lp, err := clayout.FromPath(second)
+	require.NoError(t, err)
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	linkedImg, err := lp.Image(digest)
+	require.NoError(t, err)
+	linkedLayers, err := linkedImg.Layers()
+	require.NoError(t, err)
+	for _, layer := range linkedLayers {
+		rc, err := layer.Compressed()
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+
+	// Removing a layout keeps the layers in the cache
+	require.NoError(t, os.RemoveAll(first))
+	requireLinked(t, second, cacheDir)
+}
+
+func TestGarbageCollectLayerCache(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	old := time.Now().Add(-2 * time.Hour)
+	files := map[string]time.Time{
+		"a":         old,
+		"b.partial": old,
+		"c.partial": time.Now(),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(cacheDir, name)
+		require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	freed, err := GarbageCollectLayerCache(cacheDir, utils.CachePruneOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), freed)
+	require.FileExists(t, filepath.Join(cacheDir, "a"))
+	require.NoFileExists(t, filepath.Join(cacheDir, "b.partial"))
+	require.FileExists(t, filepath.Join(cacheDir, "c.partial"))
+
+	freed, err = GarbageCollectLayerCache(cacheDir, utils.CachePruneOptions{OlderThan: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), freed)
+	require.NoFileExists(t, filepath.Join(cacheDir, "a"))
+
+	freed, err = GarbageCollectLayerCache(filepath.Join(cacheDir, "missing"), utils.CachePruneOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), freed)
+}
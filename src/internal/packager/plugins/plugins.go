@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package plugins runs the plugin executables that handle custom component behavior.
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// ExecutablePrefix is the prefix of the name of plugin executables, the plugin database-dump is run as zarf-plugin-database-dump.
+const ExecutablePrefix = "zarf-plugin-"
+
+// APIVersion is the version of the request sent to plugins.
+const APIVersion = "zarf.dev/plugin/v1alpha1"
+
+// Phase is the stage of a package lifecycle a plugin is run for.
+type Phase string
+
+// The phases plugins are run for, passed as the only argument of the plugin executable.
+const (
+	// PhaseCreate packages what the plugin needs into its directory.
+	PhaseCreate Phase = "create"
+	// PhaseDeploy applies what the plugin packaged.
+	PhaseDeploy Phase = "deploy"
+	// PhaseRemove removes what the plugin deployed.
+	PhaseRemove Phase = "remove"
+)
+
+// Request is the JSON document written to the standard input of a plugin executable.
+type Request struct {
+	// The version of the request.
+	APIVersion string `json:"apiVersion"`
+	// The phase the plugin is run for.
+	Phase Phase `json:"phase"`
+	// The name of the package on deploy and remove.
+	Package string `json:"package,omitempty"`
+	// The name of the component.
+	Component string `json:"component"`
+	// The configuration of the plugin from the package definition.
+	Config map[string]any `json:"config,omitempty"`
+	// The directory of the plugin in the package, files written to it on create are available in it on deploy.
+	Dir string `json:"dir,omitempty"`
+	// The directory of the package definition on create, relative paths in the configuration are relative to it.
+	BaseDir string `json:"baseDir,omitempty"`
+	// The architecture of the package.
+	Architecture string `json:"architecture,omitempty"`
+	// The values of the package variables on deploy and remove.
+	Variables map[string]string `json:"variables,omitempty"`
+	// How to reach the cluster and the Zarf services on deploy and remove when the plugin requires the cluster.
+	Cluster *ClusterInfo `json:"cluster,omitempty"`
+}
+
+// ClusterInfo is the part of the Zarf state a plugin that requires the cluster is given. It holds pull credentials only,
+// the push credentials, the artifact token and the agent TLS keypair are never sent to plugins.
+type ClusterInfo struct {
+	// The kubeconfig context Zarf deploys to.
+	KubeContext string `json:"kubeContext,omitempty"`
+	// The registry images are pulled from.
+	Registry Endpoint `json:"registry"`
+	// The git server repositories are read from.
+	Git Endpoint `json:"git"`
+}
+
+// Endpoint is the address of a Zarf service and the read-only credentials to it.
+type Endpoint struct {
+	// The address of the service.
+	Address string `json:"address"`
+	// The username of the read-only user.
+	Username string `json:"username,omitempty"`
+	// The password of the read-only user.
+	Password string `json:"password,omitempty"`
+}
+
+// NewClusterInfo returns what a plugin is given of the cluster the kubeconfig context points to and its Zarf state.
+func NewClusterInfo(kubeContext string, state *types.ZarfState) *ClusterInfo {
+	return &ClusterInfo{
+		KubeContext: kubeContext,
+		Registry: Endpoint{
+			Address:  state.RegistryInfo.Address,
+			Username: state.RegistryInfo.PullUsername,
+			Password: state.RegistryInfo.PullPassword,
+		},
+		Git: Endpoint{
+			Address:  state.GitServer.Address,
+			Username: state.GitServer.PullUsername,
+			Password: state.GitServer.PullPassword,
+		},
+	}
+}
+
+// Executable returns the path of the executable that runs the plugin.
+func Executable(name string) (string, error) {
+	path, err := exec.LookPath(ExecutablePrefix + name)
+	if err != nil {
+		return "", fmt.Errorf("the plugin %s is not installed, %s%s was not found in the PATH", name, ExecutablePrefix, name)
+	}
+	return path, nil
+}
+
+// Run runs the executable of the plugin for the phase of the request, streaming its output to the terminal.
+func Run(ctx context.Context, plugin v1alpha1.ZarfComponentPlugin, req Request) error {
+	path, err := Executable(plugin.Name)
+	if err != nil {
+		return err
+	}
+	req.APIVersion = APIVersion
+	req.Config = plugin.Config
+	if !plugin.RequiresCluster {
+		req.Cluster = nil
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	message.Debugf("Running the %s phase of the plugin %s with %s", req.Phase, plugin.Name, path)
+	// #nosec G204 -- the plugin executable is chosen by the package author and installed by the user
+	cmd := exec.CommandContext(ctx, path, string(req.Phase))
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("the %s phase of the plugin %s failed with exit code %d", req.Phase, plugin.Name, exitErr.ExitCode())
+		}
+		return fmt.Errorf("unable to run the plugin %s: %w", plugin.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// The test plugin writes the phase it was run for and the request it was sent to the directory of the request.
+const testPlugin = `#!/bin/sh
+set -e
+request=$(cat)
+dir=$(echo "$request" | sed -n 's/.*"dir":"\([^"]*\)".*/\1/p')
+echo "$1" > "$dir/phase"
+echo "$request" > "$dir/request.json"
+if [ "$1" = "remove" ]; then
+  echo "unable to remove" >&2
+  exit 3
+fi
+`
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugin is a shell script")
+	}
+
+	bin := t.TempDir()
+	err := os.WriteFile(filepath.Join(bin, ExecutablePrefix+"database-dump"), []byte(testPlugin), 0o700)
+	require.NoError(t, err)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		name            string
+		plugin          v1alpha1.ZarfComponentPlugin
+		phase           Phase
+		expectedCluster bool
+		expectedError   string
+	}{
+		{
+			name:   "create",
+			plugin: v1alpha1.ZarfComponentPlugin{Name: "database-dump", Config: map[string]any{"database": "orders"}},
+			phase:  PhaseCreate,
+		},
+		{
+			name:            "deploy with the cluster",
+			plugin:          v1alpha1.ZarfComponentPlugin{Name: "database-dump", Config: map[string]any{"database": "orders"}, RequiresCluster: true},
+			phase:           PhaseDeploy,
+			expectedCluster: true,
+		},
+		{
+			name:   "deploy without the cluster",
+			plugin: v1alpha1.ZarfComponentPlugin{Name: "database-dump"},
+			phase:  PhaseDeploy,
+		},
+		{
+			name:          "failing plugin",
+			plugin:        v1alpha1.ZarfComponentPlugin{Name: "database-dump"},
+			phase:         PhaseRemove,
+			expectedError: "the remove phase of the plugin database-dump failed with exit code 3",
+		},
+		{
+			name:          "missing plugin",
+			plugin:        v1alpha1.ZarfComponentPlugin{Name: "vm-image"},
+			phase:         PhaseCreate,
+			expectedError: "the plugin vm-image is not installed, zarf-plugin-vm-image was not found in the PATH",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			req := Request{
+				Phase:     tt.phase,
+				Package:   "test",
+				Component: "database",
+				Dir:       dir,
+				Cluster:   &ClusterInfo{KubeContext: "k3d-zarf"},
+			}
+			err := Run(context.Background(), tt.plugin, req)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			phase, err := os.ReadFile(filepath.Join(dir, "phase"))
+			require.NoError(t, err)
+			require.Equal(t, string(tt.phase)+"\n", string(phase))
+			b, err := os.ReadFile(filepath.Join(dir, "request.json"))
+			require.NoError(t, err)
+			received := Request{}
+			err = json.Unmarshal(b, &received)
+			require.NoError(t, err)
+			require.Equal(t, APIVersion, received.APIVersion)
+			require.Equal(t, tt.phase, received.Phase)
+			require.Equal(t, "database", received.Component)
+			require.Equal(t, tt.plugin.Config, received.Config)
+			require.Equal(t, tt.expectedCluster, received.Cluster != nil)
+		})
+	}
+}
+
+func TestNewClusterInfo(t *testing.T) {
+	t.Parallel()
+
+	state := &types.ZarfState{
+		RegistryInfo: types.RegistryInfo{
+			Address:      "127.0.0.1:31999",
+			PushUsername: "zarf-push",
+			PushPassword: "push-password",
+			PullUsername: "zarf-pull",
+			PullPassword: "pull-password",
+		},
+		GitServer: types.GitServerInfo{
+			Address:      "http://zarf-gitea-http.zarf.svc.cluster.local:3000",
+			PushUsername: "zarf-git-user",
+			PushPassword: "git-push-password",
+			PullUsername: "zarf-git-read-user",
+			PullPassword: "git-pull-password",
+		},
+		ArtifactServer: types.ArtifactServerInfo{PushToken: "artifact-token"},
+		AgentTLS:       types.GeneratedPKI{Key: []byte("agent-key")},
+	}
+	info := NewClusterInfo("k3d-zarf", state)
+	expected := &ClusterInfo{
+		KubeContext: "k3d-zarf",
+		Registry:    Endpoint{Address: "127.0.0.1:31999", Username: "zarf-pull", Password: "pull-password"},
+		Git:         Endpoint{Address: "http://zarf-gitea-http.zarf.svc.cluster.local:3000", Username: "zarf-git-read-user", Password: "git-pull-password"},
+	}
+	require.Equal(t, expected, info)
+
+	// None of the push credentials or keys reach the plugin
+	b, err := json.Marshal(info)
+	require.NoError(t, err)
+	for _, secret := range []string{"push-password", "git-push-password", "artifact-token", "agent-key", "YWdlbnQta2V5"} {
+		require.NotContains(t, string(b), secret)
+	}
+}
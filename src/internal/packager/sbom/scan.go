@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sbom contains tools for generating SBOMs.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anchore/clio"
+	"github.com/anchore/grype/cmd/grype/cli/options"
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/source"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// VulnerabilityReport is the report of the vulnerabilities found in the SBOMs of a package.
+type VulnerabilityReport struct {
+	// Database is the vulnerability database the SBOMs were scanned with
+	Database VulnerabilityDatabase `json:"database"`
+	// Artifacts are the vulnerabilities found in the SBOM of each image and component
+	Artifacts []ArtifactVulnerabilities `json:"artifacts"`
+}
+
+// VulnerabilityDatabase describes the vulnerability database the SBOMs were scanned with.
+type VulnerabilityDatabase struct {
+	Built         time.Time `json:"built"`
+	SchemaVersion int       `json:"schemaVersion"`
+}
+
+// ArtifactVulnerabilities are the vulnerabilities found in the SBOM of an image or component.
+type ArtifactVulnerabilities struct {
+	// Name is the image reference or the name of the component SBOM
+	Name            string          `json:"name"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Vulnerability is a vulnerability of a package in an SBOM.
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Severity string   `json:"severity"`
+	Package  string   `json:"package"`
+	Version  string   `json:"version"`
+	Type     string   `json:"type"`
+	FixedIn  []string `json:"fixedIn,omitempty"`
+}
+
+// Severities are the severities of vulnerabilities from the most to the least severe.
+var Severities = []string{"critical", "high", "medium", "low", "negligible", "unknown"}
+
+// ValidateSeverity returns an error if severity is not the severity of a vulnerability.
+func ValidateSeverity(severity string) error {
+	if !slices.Contains(Severities, strings.ToLower(severity)) {
+		return fmt.Errorf("invalid severity %q, must be one of %s", severity, strings.Join(Severities, ", "))
+	}
+	return nil
+}
+
+// Count returns the number of vulnerabilities of each severity in the artifact.
+func (a ArtifactVulnerabilities) Count() map[string]int {
+	counts := map[string]int{}
+	for _, v := range a.Vulnerabilities {
+		counts[v.Severity]++
+	}
+	return counts
+}
+
+// AtOrAbove returns the number of vulnerabilities in the report that are at least as severe as severity.
+func (r *VulnerabilityReport) AtOrAbove(severity string) int {
+	threshold := vulnerability.ParseSeverity(severity)
+	count := 0
+	for _, artifact := range r.Artifacts {
+		for _, v := range artifact.Vulnerabilities {
+			if vulnerability.ParseSeverity(v.Severity) >= threshold {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ScanVulnerabilities scans the SBOMs in dir for vulnerabilities with the grype vulnerability database cached in the
+// Zarf cache and writes the report into dir. When dbArchive is set it is imported into the cache and scanned with as it
+// is, so scans run without network access, otherwise the cached database is updated first.
+func ScanVulnerabilities(dir, dbArchive string) (*VulnerabilityReport, error) {
+	spinner := message.NewProgressSpinner("Loading the vulnerability database")
+	defer spinner.Stop()
+
+	dbConfig := options.DefaultDatabase(clio.Identification{Name: "zarf"})
+	dbConfig.Dir = filepath.Join(config.GetAbsCachePath(), "grype-db")
+	update := dbArchive == ""
+	if dbArchive != "" {
+		// An imported database is as recent as the user decided to bring over
+		dbConfig.ValidateAge = false
+		curator, err := db.NewCurator(dbConfig.ToCuratorConfig())
+		if err != nil {
+			return nil, err
+		}
+		if err := curator.ImportFrom(dbArchive); err != nil {
+			return nil, fmt.Errorf("unable to import the vulnerability database %s: %w", dbArchive, err)
+		}
+	}
+	vulnStore, status, closer, err := grype.LoadVulnerabilityDB(dbConfig.ToCuratorConfig(), update)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the vulnerability database: %w", err)
+	}
+	defer closer.Close()
+	if status.Err != nil {
+		return nil, fmt.Errorf("unable to load the vulnerability database: %w", status.Err)
+	}
+
+	spinner.Updatef("Scanning the SBOMs for vulnerabilities")
+	report, err := scanSBOMs(dir, vulnStore)
+	if err != nil {
+		return nil, err
+	}
+	report.Database = VulnerabilityDatabase{Built: status.Built, SchemaVersion: status.SchemaVersion}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, layout.VulnerabilityReport), b, helpers.ReadWriteUser); err != nil {
+		return nil, err
+	}
+	spinner.Successf("Scanned the SBOMs for vulnerabilities")
+	return report, nil
+}
+
+// scanSBOMs scans the syft JSON SBOMs in dir for vulnerabilities in vulnStore.
+func scanSBOMs(dir string, vulnStore *store.Store) (*VulnerabilityReport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	matcher := grype.DefaultVulnerabilityMatcher(*vulnStore)
+	report := &VulnerabilityReport{Artifacts: []ArtifactVulnerabilities{}}
+	for _, path := range paths {
+		if filepath.Base(path) == layout.VulnerabilityReport {
+			continue
+		}
+		packages, pkgContext, s, err := pkg.Provide("sbom:"+path, pkg.ProviderConfig{SynthesisConfig: pkg.SynthesisConfig{GenerateMissingCPEs: true}})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the SBOM %s: %w", path, err)
+		}
+		matches, _, err := matcher.FindMatches(packages, pkgContext)
+		if err != nil {
+			return nil, fmt.Errorf("unable to scan the SBOM %s: %w", path, err)
+		}
+
+		artifact := ArtifactVulnerabilities{Name: strings.TrimSuffix(filepath.Base(path), ".json"), Vulnerabilities: []Vulnerability{}}
+		if metadata, ok := s.Source.Metadata.(source.StereoscopeImageSourceMetadata); ok {
+			artifact.Name = metadata.UserInput
+		}
+		for _, m := range matches.Sorted() {
+			metadata, err := vulnStore.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			severity := "unknown"
+			if metadata != nil && metadata.Severity != "" {
+				severity = strings.ToLower(metadata.Severity)
+			}
+			artifact.Vulnerabilities = append(artifact.Vulnerabilities, Vulnerability{
+				ID:       m.Vulnerability.ID,
+				Severity: severity,
+				Package:  m.Package.Name,
+				Version:  m.Package.Version,
+				Type:     string(m.Package.Type),
+				FixedIn:  m.Vulnerability.Fix.Versions,
+			})
+		}
+		// List the most severe vulnerabilities first
+		slices.SortStableFunc(artifact.Vulnerabilities, func(a, b Vulnerability) int {
+			return int(vulnerability.ParseSeverity(b.Severity)) - int(vulnerability.ParseSeverity(a.Severity))
+		})
+		report.Artifacts = append(report.Artifacts, artifact)
+	}
+	return report, nil
+}
+
+// PrintVulnerabilityReport prints the number of vulnerabilities of each severity in the artifacts of the report.
+func PrintVulnerabilityReport(report *VulnerabilityReport) {
+	header := append([]string{"Artifact"}, Severities...)
+	data := [][]string{}
+	for _, artifact := range report.Artifacts {
+		counts := artifact.Count()
+		row := []string{artifact.Name}
+		for _, severity := range Severities {
+			row = append(row, fmt.Sprintf("%d", counts[severity]))
+		}
+		data = append(data, row)
+	}
+	message.Table(header, data)
+}
+
+// PrintVulnerabilities prints the vulnerabilities in the report that are at least as severe as severity.
+func PrintVulnerabilities(report *VulnerabilityReport, severity string) {
+	threshold := vulnerability.ParseSeverity(severity)
+	data := [][]string{}
+	for _, artifact := range report.Artifacts {
+		for _, v := range artifact.Vulnerabilities {
+			if vulnerability.ParseSeverity(v.Severity) >= threshold {
+				data = append(data, []string{artifact.Name, v.ID, v.Severity, v.Package, v.Version, strings.Join(v.FixedIn, ", ")})
+			}
+		}
+	}
+	message.Table([]string{"Artifact", "Vulnerability", "Severity", "Package", "Version", "Fixed In"}, data)
+}
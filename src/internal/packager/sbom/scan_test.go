@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	grypeDb "github.com/anchore/grype/grype/db/v5"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/store"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+// fakeVulnerabilityProvider is a vulnerability database of vulnerabilities by package name.
+type fakeVulnerabilityProvider struct {
+	vulnerabilities map[string][]vulnerability.Vulnerability
+	severities      map[string]string
+}
+
+func (f fakeVulnerabilityProvider) Get(_, _ string) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (f fakeVulnerabilityProvider) GetByDistro(_ *distro.Distro, _ grypePkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (f fakeVulnerabilityProvider) GetByLanguage(_ pkg.Language, p grypePkg.Package) ([]vulnerability.Vulnerability, error) {
+	return f.vulnerabilities[p.Name], nil
+}
+
+func (f fakeVulnerabilityProvider) GetByCPE(_ cpe.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (f fakeVulnerabilityProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: f.severities[id]}, nil
+}
+
+func (f fakeVulnerabilityProvider) GetRules(_ string) ([]match.IgnoreRule, error) {
+	return nil, nil
+}
+
+func writeTestSBOM(t *testing.T, path string, src source.Description, packages ...pkg.Package) {
+	t.Helper()
+	collection := pkg.NewCollection()
+	for _, p := range packages {
+		p.SetID()
+		collection.Add(p)
+	}
+	b, err := format.Encode(sbom.SBOM{
+		Descriptor: sbom.Descriptor{Name: "zarf"},
+		Source:     src,
+		Artifacts:  sbom.Artifacts{Packages: collection},
+	}, syftjson.NewFormatEncoder())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+}
+
+func TestScanSBOMs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestSBOM(t, filepath.Join(dir, "ghcr.io_example_app_1.0.0.json"),
+		source.Description{Name: "ghcr.io/example/app:1.0.0", Metadata: source.StereoscopeImageSourceMetadata{UserInput: "ghcr.io/example/app:1.0.0"}},
+		pkg.Package{Name: "lodash", Version: "4.17.20", Type: pkg.NpmPkg, Language: pkg.JavaScript, PURL: "pkg:npm/lodash@4.17.20"},
+		pkg.Package{Name: "express", Version: "4.19.2", Type: pkg.NpmPkg, Language: pkg.JavaScript, PURL: "pkg:npm/express@4.19.2"},
+	)
+	writeTestSBOM(t, filepath.Join(dir, "zarf-component-files.json"),
+		source.Description{Name: "files", Metadata: source.DirectorySourceMetadata{Path: "files"}},
+		pkg.Package{Name: "minimist", Version: "1.2.5", Type: pkg.NpmPkg, Language: pkg.JavaScript, PURL: "pkg:npm/minimist@1.2.5"},
+	)
+	// A previous report is not scanned as an SBOM
+	require.NoError(t, os.WriteFile(filepath.Join(dir, layout.VulnerabilityReport), []byte("{}"), 0o600))
+
+	provider := fakeVulnerabilityProvider{
+		vulnerabilities: map[string][]vulnerability.Vulnerability{
+			"lodash": {{
+				ID:         "GHSA-35jh-r3h4-6jhm",
+				Namespace:  "github:language:javascript",
+				Constraint: version.MustGetConstraint("<4.17.21", version.UnknownFormat),
+				Fix:        vulnerability.Fix{Versions: []string{"4.17.21"}, State: grypeDb.FixedState},
+			}},
+			"express": {{
+				ID:         "GHSA-qw6h-vgh9-j6wx",
+				Namespace:  "github:language:javascript",
+				Constraint: version.MustGetConstraint("<4.20.0", version.UnknownFormat),
+				Fix:        vulnerability.Fix{Versions: []string{"4.20.0"}, State: grypeDb.FixedState},
+			}},
+			"minimist": {{
+				ID:         "GHSA-xvch-5gv4-984h",
+				Namespace:  "github:language:javascript",
+				Constraint: version.MustGetConstraint("<1.2.6", version.UnknownFormat),
+				Fix:        vulnerability.Fix{Versions: []string{"1.2.6"}, State: grypeDb.FixedState},
+			}},
+		},
+		severities: map[string]string{
+			"GHSA-35jh-r3h4-6jhm": "High",
+			"GHSA-qw6h-vgh9-j6wx": "Low",
+			"GHSA-xvch-5gv4-984h": "Critical",
+		},
+	}
+	report, err := scanSBOMs(dir, &store.Store{Provider: provider, MetadataProvider: provider, ExclusionProvider: provider})
+	require.NoError(t, err)
+
+	expected := []ArtifactVulnerabilities{
+		{
+			Name: "ghcr.io/example/app:1.0.0",
+			Vulnerabilities: []Vulnerability{
+				{ID: "GHSA-35jh-r3h4-6jhm", Severity: "high", Package: "lodash", Version: "4.17.20", Type: "npm", FixedIn: []string{"4.17.21"}},
+				{ID: "GHSA-qw6h-vgh9-j6wx", Severity: "low", Package: "express", Version: "4.19.2", Type: "npm", FixedIn: []string{"4.20.0"}},
+			},
+		},
+		{
+			Name: "zarf-component-files",
+			Vulnerabilities: []Vulnerability{
+				{ID: "GHSA-xvch-5gv4-984h", Severity: "critical", Package: "minimist", Version: "1.2.5", Type: "npm", FixedIn: []string{"1.2.6"}},
+			},
+		},
+	}
+	require.Equal(t, expected, report.Artifacts)
+	require.Equal(t, map[string]int{"low": 1, "high": 1}, report.Artifacts[0].Count())
+}
+
+func TestVulnerabilityReportAtOrAbove(t *testing.T) {
+	t.Parallel()
+
+	report := &VulnerabilityReport{
+		Artifacts: []ArtifactVulnerabilities{
+			{Name: "a", Vulnerabilities: []Vulnerability{{Severity: "critical"}, {Severity: "low"}}},
+			{Name: "b", Vulnerabilities: []Vulnerability{{Severity: "high"}, {Severity: "unknown"}}},
+		},
+	}
+	tests := []struct {
+		severity string
+		expected int
+	}{
+		{severity: "critical", expected: 1},
+		{severity: "high", expected: 2},
+		{severity: "medium", expected: 2},
+		{severity: "low", expected: 3},
+		{severity: "unknown", expected: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, ValidateSeverity(tt.severity))
+			require.Equal(t, tt.expected, report.AtOrAbove(tt.severity))
+		})
+	}
+
+	require.Error(t, ValidateSeverity("severe"))
+}
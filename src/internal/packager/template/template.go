@@ -8,6 +8,8 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
@@ -40,8 +42,8 @@ func GetZarfVariableConfig() *variables.VariableConfig {
 		slog.New(message.ZarfHandler{}))
 }
 
-// GetZarfTemplates returns the template keys and values to be used for templating.
-func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap map[string]*variables.TextTemplate, err error) {
+// GetZarfTemplates returns the template keys and values to be used for templating, including the given cluster facts when they are not nil.
+func GetZarfTemplates(componentName string, state *types.ZarfState, facts *types.ClusterFacts) (templateMap map[string]*variables.TextTemplate, err error) {
 	templateMap = make(map[string]*variables.TextTemplate)
 
 	if state != nil {
@@ -57,6 +59,11 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 			"REGISTRY_AUTH_PUSH": regInfo.PushPassword,
 			"REGISTRY_AUTH_PULL": regInfo.PullPassword,
 
+			// Proxy info
+			"HTTP_PROXY":  state.Proxy.HTTPProxy,
+			"HTTPS_PROXY": state.Proxy.HTTPSProxy,
+			"NO_PROXY":    state.Proxy.NoProxy,
+
 			// Git server info
 			"GIT_PUSH":      gitInfo.PushUsername,
 			"GIT_AUTH_PUSH": gitInfo.PushPassword,
@@ -66,6 +73,19 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 
 		builtinMap[depMarker] = config.GetDataInjectionMarker()
 
+		// Cluster facts that could not be looked up are left untemplated
+		if facts != nil {
+			if facts.NodeCount > 0 {
+				builtinMap["CLUSTER_NODE_COUNT"] = strconv.Itoa(facts.NodeCount)
+			}
+			if facts.K8sVersion != "" {
+				builtinMap["CLUSTER_K8S_VERSION"] = facts.K8sVersion
+			}
+			if facts.DefaultStorageClass != "" {
+				builtinMap["CLUSTER_DEFAULT_STORAGE_CLASS"] = facts.DefaultStorageClass
+			}
+		}
+
 		// Don't template component-specific variables for every component
 		switch componentName {
 		case "zarf-agent":
@@ -82,6 +102,13 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 			}
 			builtinMap["HTPASSWD"] = htpasswd
 			builtinMap["REGISTRY_SECRET"] = regInfo.Secret
+			builtinMap["REGISTRY_REPLICAS"] = strconv.Itoa(max(regInfo.Replicas, 1))
+			builtinMap["REGISTRY_S3_BUCKET"] = regInfo.S3.Bucket
+			builtinMap["REGISTRY_S3_REGION"] = regInfo.S3.Region
+			builtinMap["REGISTRY_S3_ENDPOINT"] = regInfo.S3.Endpoint
+			builtinMap["REGISTRY_S3_ROOT_DIRECTORY"] = regInfo.S3.RootDirectory
+			builtinMap["REGISTRY_S3_ACCESS_KEY"] = regInfo.S3.AccessKey
+			builtinMap["REGISTRY_S3_SECRET_KEY"] = regInfo.S3.SecretKey
 		}
 
 		// Iterate over any custom variables and add them to the mappings for templating
@@ -93,7 +120,8 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 
 			if key == "REGISTRY_SECRET" || key == "HTPASSWD" ||
 				key == "AGENT_CA" || key == "AGENT_KEY" || key == "AGENT_CRT" || key == "GIT_AUTH_PULL" ||
-				key == "GIT_AUTH_PUSH" || key == "REGISTRY_AUTH_PULL" || key == "REGISTRY_AUTH_PUSH" {
+				key == "GIT_AUTH_PUSH" || key == "REGISTRY_AUTH_PULL" || key == "REGISTRY_AUTH_PUSH" ||
+				key == "REGISTRY_S3_ACCESS_KEY" || key == "REGISTRY_S3_SECRET_KEY" {
 				// Sanitize any builtin templates that are sensitive
 				templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_%s###", key))].Sensitive = true
 			}
@@ -109,20 +137,46 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 func generateHtpasswd(regInfo *types.RegistryInfo) (string, error) {
 	// Only calculate this for internal registries to allow longer external passwords
 	if regInfo.IsInternal() {
-		pushUser, err := utils.GetHtpasswdString(regInfo.PushUsername, regInfo.PushPassword)
+		entries, err := RegistryHtpasswdEntries(*regInfo)
 		if err != nil {
-			return "", fmt.Errorf("error generating htpasswd string: %w", err)
+			return "", err
 		}
+		return strings.Join(entries, "\\n"), nil
+	}
+
+	return "", nil
+}
 
-		pullUser, err := utils.GetHtpasswdString(regInfo.PullUsername, regInfo.PullPassword)
+// RegistryHtpasswdEntries returns the htpasswd entries of the push user, the pull user, the pull-only credentials of
+// every namespace that has been given its own and the pull credentials of open zarf connect tunnels.
+func RegistryHtpasswdEntries(regInfo types.RegistryInfo) ([]string, error) {
+	credentials := [][2]string{
+		{regInfo.PushUsername, regInfo.PushPassword},
+		{regInfo.PullUsername, regInfo.PullPassword},
+	}
+	if regInfo.NamespacedPullCredentials {
+		for _, namespace := range regInfo.PullNamespaces {
+			username, password := regInfo.NamespacePullCredentials(namespace)
+			credentials = append(credentials, [2]string{username, password})
+		}
+	}
+	entries := []string{}
+	for _, c := range credentials {
+		entry, err := utils.GetHtpasswdString(c[0], c[1])
 		if err != nil {
-			return "", fmt.Errorf("error generating htpasswd string: %w", err)
+			return nil, fmt.Errorf("error generating htpasswd string: %w", err)
 		}
-
-		return fmt.Sprintf("%s\\n%s", pushUser, pullUser), nil
+		entries = append(entries, entry)
 	}
-
-	return "", nil
+	usernames := []string{}
+	for username := range regInfo.TunnelPullEntries {
+		usernames = append(usernames, username)
+	}
+	slices.Sort(usernames)
+	for _, username := range usernames {
+		entries = append(entries, regInfo.TunnelPullEntries[username])
+	}
+	return entries, nil
 }
 
 func debugPrintTemplateMap(templateMap map[string]*variables.TextTemplate) {
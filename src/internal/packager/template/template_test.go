@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestGetZarfTemplatesClusterFacts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		facts    *types.ClusterFacts
+		expected map[string]string
+	}{
+		{
+			name:     "no cluster facts",
+			facts:    nil,
+			expected: map[string]string{},
+		},
+		{
+			name: "all cluster facts",
+			facts: &types.ClusterFacts{
+				NodeCount:           3,
+				K8sVersion:          "v1.30.2",
+				DefaultStorageClass: "local-path",
+			},
+			expected: map[string]string{
+				"###ZARF_CLUSTER_NODE_COUNT###":            "3",
+				"###ZARF_CLUSTER_K8S_VERSION###":           "v1.30.2",
+				"###ZARF_CLUSTER_DEFAULT_STORAGE_CLASS###": "local-path",
+			},
+		},
+		{
+			name: "missing cluster facts are not templated",
+			facts: &types.ClusterFacts{
+				K8sVersion: "v1.30.2",
+			},
+			expected: map[string]string{
+				"###ZARF_CLUSTER_K8S_VERSION###": "v1.30.2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			templateMap, err := GetZarfTemplates("test", &types.ZarfState{}, tt.facts)
+			require.NoError(t, err)
+			clusterTemplates := map[string]string{}
+			for _, key := range []string{"###ZARF_CLUSTER_NODE_COUNT###", "###ZARF_CLUSTER_K8S_VERSION###", "###ZARF_CLUSTER_DEFAULT_STORAGE_CLASS###"} {
+				if tmpl, ok := templateMap[key]; ok {
+					clusterTemplates[key] = tmpl.Value
+				}
+			}
+			require.Equal(t, tt.expected, clusterTemplates)
+		})
+	}
+}
+
+func TestGetZarfTemplatesRegistryHA(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		registryInfo types.RegistryInfo
+		expected     map[string]string
+	}{
+		{
+			name:         "single replica on a PVC",
+			registryInfo: types.RegistryInfo{Address: "127.0.0.1:31999", NodePort: 31999},
+			expected: map[string]string{
+				"###ZARF_REGISTRY_REPLICAS###":    "1",
+				"###ZARF_REGISTRY_S3_BUCKET###":   "",
+				"###ZARF_REGISTRY_S3_REGION###":   "",
+				"###ZARF_REGISTRY_S3_ENDPOINT###": "",
+			},
+		},
+		{
+			name: "replicas on an S3-compatible bucket",
+			registryInfo: types.RegistryInfo{
+				Address:  "127.0.0.1:31999",
+				NodePort: 31999,
+				Replicas: 3,
+				S3: types.RegistryS3Storage{
+					Bucket:   "zarf-registry",
+					Region:   "us-east-1",
+					Endpoint: "https://minio.example.com",
+				},
+			},
+			expected: map[string]string{
+				"###ZARF_REGISTRY_REPLICAS###":    "3",
+				"###ZARF_REGISTRY_S3_BUCKET###":   "zarf-registry",
+				"###ZARF_REGISTRY_S3_REGION###":   "us-east-1",
+				"###ZARF_REGISTRY_S3_ENDPOINT###": "https://minio.example.com",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			templateMap, err := GetZarfTemplates("zarf-registry", &types.ZarfState{RegistryInfo: tt.registryInfo}, nil)
+			require.NoError(t, err)
+			registryTemplates := map[string]string{}
+			for key := range tt.expected {
+				registryTemplates[key] = templateMap[key].Value
+			}
+			require.Equal(t, tt.expected, registryTemplates)
+			require.True(t, templateMap["###ZARF_REGISTRY_S3_SECRET_KEY###"].Sensitive)
+		})
+	}
+}
+
+func TestRegistryHtpasswdEntries(t *testing.T) {
+	t.Parallel()
+
+	regInfo := types.RegistryInfo{
+		PushUsername: "push-user",
+		PushPassword: "push-password",
+		PullUsername: "pull-user",
+		PullPassword: "pull-password",
+	}
+	entries, err := RegistryHtpasswdEntries(regInfo)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// Namespaces are only given their own entries when every namespace has its own credentials
+	regInfo.PullNamespaces = []string{"zarf", "team-a"}
+	entries, err = RegistryHtpasswdEntries(regInfo)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	regInfo.NamespacedPullCredentials = true
+	entries, err = RegistryHtpasswdEntries(regInfo)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	expected := map[string]string{
+		"push-user": "push-password",
+		"pull-user": "pull-password",
+	}
+	for _, namespace := range regInfo.PullNamespaces {
+		username, password := regInfo.NamespacePullCredentials(namespace)
+		expected[username] = password
+	}
+	for _, entry := range entries {
+		username, hash, ok := strings.Cut(entry, ":")
+		require.True(t, ok)
+		password, ok := expected[username]
+		require.True(t, ok, "unexpected user %s", username)
+		require.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)))
+		delete(expected, username)
+	}
+	require.Empty(t, expected)
+
+	// The entries of open tunnels are already hashed and are appended sorted by username
+	regInfo.TunnelPullEntries = map[string]string{
+		"zarf-connect-b": "zarf-connect-b:hash-b",
+		"zarf-connect-a": "zarf-connect-a:hash-a",
+	}
+	entries, err = RegistryHtpasswdEntries(regInfo)
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+	require.Equal(t, []string{"zarf-connect-a:hash-a", "zarf-connect-b:hash-b"}, entries[4:])
+}
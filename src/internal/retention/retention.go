@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package retention keeps the archives of deployed packages on the deploy host with an index of their checksums.
+package retention
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+)
+
+// IndexFile is the name of the index of the retained archives in the retention directory.
+const IndexFile = "index.json"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Index lists the retained archives, most recently deployed first.
+type Index struct {
+	Packages []Entry `json:"packages"`
+}
+
+// Entry describes a retained package archive.
+type Entry struct {
+	Name         string `json:"name"`
+	Version      string `json:"version,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	// Source the package was deployed from, e.g. a path, URL or OCI reference
+	Source string `json:"source,omitempty"`
+	// Path of the archive relative to the retention directory
+	Path     string    `json:"path"`
+	SHA256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	Deployed time.Time `json:"deployed"`
+}
+
+// Load reads the index of the retention directory, the index is empty when nothing has been retained.
+func Load(dir string) (Index, error) {
+	index := Index{Packages: []Entry{}}
+	b, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return Index{}, err
+	}
+	if err := json.Unmarshal(b, &index); err != nil {
+		return Index{}, fmt.Errorf("unable to read the index of the retained packages: %w", err)
+	}
+	return index, nil
+}
+
+// Retain copies the archive into the retention directory and records it in the index. An archive that is already
+// retained is not copied again, its entry is updated instead. When keep is positive only the keep most recently
+// deployed archives of the package are retained, the older archives are deleted and returned.
+func Retain(dir, archive string, entry Entry, keep int) (Entry, []Entry, error) {
+	index, err := Load(dir)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+	sum, err := helpers.GetSHA256OfFile(archive)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+	info, err := os.Stat(archive)
+	if err != nil {
+		return Entry{}, nil, err
+	}
+	entry.SHA256 = sum
+	entry.Size = info.Size()
+	if entry.Deployed.IsZero() {
+		entry.Deployed = time.Now()
+	}
+	entry.Deployed = entry.Deployed.UTC()
+
+	retained := []Entry{}
+	for _, e := range index.Packages {
+		if e.SHA256 == sum {
+			entry.Path = e.Path
+			continue
+		}
+		retained = append(retained, e)
+	}
+	if entry.Path == "" {
+		entry.Path = filepath.Join(dirName(entry.Name), fmt.Sprintf("%s-%s", sum[:12], filepath.Base(archive)))
+		if err := copyFile(archive, filepath.Join(dir, entry.Path)); err != nil {
+			return Entry{}, nil, fmt.Errorf("unable to retain %s: %w", archive, err)
+		}
+	}
+	index.Packages = append([]Entry{entry}, retained...)
+	sort.SliceStable(index.Packages, func(i, j int) bool {
+		return index.Packages[i].Deployed.After(index.Packages[j].Deployed)
+	})
+
+	pruned := []Entry{}
+	if keep > 0 {
+		kept := []Entry{}
+		count := map[string]int{}
+		for _, e := range index.Packages {
+			if e.Name != entry.Name {
+				kept = append(kept, e)
+				continue
+			}
+			count[e.Name]++
+			if count[e.Name] > keep {
+				pruned = append(pruned, e)
+				continue
+			}
+			kept = append(kept, e)
+		}
+		index.Packages = kept
+	}
+
+	// The index is saved before the pruned archives are deleted so it never lists a missing archive
+	if err := save(dir, index); err != nil {
+		return Entry{}, nil, err
+	}
+	for _, e := range pruned {
+		if err := os.Remove(filepath.Join(dir, e.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Entry{}, nil, err
+		}
+	}
+	return entry, pruned, nil
+}
+
+// dirName returns a directory name for the package that is safe to use on any filesystem.
+func dirName(name string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+func save(dir string, index Index) error {
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so the index is never read half written
+	tmp := filepath.Join(dir, IndexFile+".tmp")
+	if err := os.WriteFile(tmp, b, helpers.ReadWriteUser); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, IndexFile))
+}
+
+func copyFile(src, dst string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(dst), helpers.ReadExecuteAllWriteUser); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp)
+		}
+	}()
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
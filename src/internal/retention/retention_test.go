@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetain(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	archive := func(name, content string) string {
+		path := filepath.Join(src, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	v1 := archive("zarf-package-podinfo-amd64-1.0.0.tar.zst", "podinfo 1.0.0")
+	v2 := archive("zarf-package-podinfo-amd64-2.0.0.tar.zst", "podinfo 2.0.0")
+	v3 := archive("zarf-package-podinfo-amd64-3.0.0.tar.zst", "podinfo 3.0.0")
+	other := archive("zarf-package-other-amd64.tar.zst", "other")
+	deployed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	dir := t.TempDir()
+	index, err := Load(dir)
+	require.NoError(t, err)
+	require.Empty(t, index.Packages)
+
+	first, pruned, err := Retain(dir, v1, Entry{Name: "podinfo", Version: "1.0.0", Source: v1, Deployed: deployed}, 2)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+	require.Len(t, first.SHA256, 64)
+	require.Equal(t, int64(len("podinfo 1.0.0")), first.Size)
+	require.Equal(t, filepath.Join("podinfo", first.SHA256[:12]+"-zarf-package-podinfo-amd64-1.0.0.tar.zst"), first.Path)
+	b, err := os.ReadFile(filepath.Join(dir, first.Path))
+	require.NoError(t, err)
+	require.Equal(t, "podinfo 1.0.0", string(b))
+
+	_, _, err = Retain(dir, other, Entry{Name: "other", Deployed: deployed.Add(time.Minute)}, 2)
+	require.NoError(t, err)
+	_, _, err = Retain(dir, v2, Entry{Name: "podinfo", Version: "2.0.0", Deployed: deployed.Add(2 * time.Minute)}, 2)
+	require.NoError(t, err)
+
+	// Deploying a retained archive again updates its entry instead of copying it
+	again, pruned, err := Retain(dir, v1, Entry{Name: "podinfo", Version: "1.0.0", Deployed: deployed.Add(3 * time.Minute)}, 2)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+	require.Equal(t, first.Path, again.Path)
+
+	// The least recently deployed archive of the package is deleted to keep two
+	third, pruned, err := Retain(dir, v3, Entry{Name: "podinfo", Version: "3.0.0", Deployed: deployed.Add(4 * time.Minute)}, 2)
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	require.Equal(t, "2.0.0", pruned[0].Version)
+	require.NoFileExists(t, filepath.Join(dir, pruned[0].Path))
+
+	index, err = Load(dir)
+	require.NoError(t, err)
+	versions := []string{}
+	for _, e := range index.Packages {
+		versions = append(versions, e.Name+"@"+e.Version)
+	}
+	require.Equal(t, []string{"podinfo@3.0.0", "podinfo@1.0.0", "other@"}, versions)
+	require.Equal(t, third, index.Packages[0])
+}
+
+func TestDirName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pkgName  string
+		expected string
+	}{
+		{
+			name:     "package name",
+			pkgName:  "podinfo",
+			expected: "podinfo",
+		},
+		{
+			name:     "unsafe characters",
+			pkgName:  "../My Package/",
+			expected: "my-package",
+		},
+		{
+			name:     "empty",
+			pkgName:  "..",
+			expected: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, dirName(tt.pkgName))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package telemetry exports anonymous usage metrics of Zarf operations to a user-specified collector.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"time"
+
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpMetricsPath is the default path of the metrics receiver of an OTLP/HTTP collector.
+const otlpMetricsPath = "/v1/metrics"
+
+// pushOTLP exports the cumulative metrics of this instance to an OTLP/HTTP collector.
+func pushOTLP(ctx context.Context, client *http.Client, cfg Config, st *state, now time.Time) error {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid metrics endpoint %s: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = otlpMetricsPath
+	}
+
+	body, err := proto.Marshal(otlpRequest(cfg, st, now))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to export metrics to %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to export metrics to %s: unexpected status %s: %s", endpoint, resp.Status, msg)
+	}
+	return nil
+}
+
+func otlpRequest(cfg Config, st *state, now time.Time) *collectorpb.ExportMetricsServiceRequest {
+	start := uint64(st.Start.UnixNano())
+	end := uint64(now.UnixNano())
+	sum := func(name, description, unit string, value func(t totals) *metricspb.NumberDataPoint) *metricspb.Metric {
+		points := []*metricspb.NumberDataPoint{}
+		for _, t := range st.Totals {
+			point := value(t)
+			point.Attributes = []*commonpb.KeyValue{stringAttribute("command", t.Command), stringAttribute("result", t.Result)}
+			point.StartTimeUnixNano = start
+			point.TimeUnixNano = end
+			points = append(points, point)
+		}
+		return &metricspb.Metric{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				DataPoints:             points,
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			}},
+		}
+	}
+
+	metrics := []*metricspb.Metric{
+		sum("zarf.operations", "Number of Zarf operations that ran.", "{operation}", func(t totals) *metricspb.NumberDataPoint {
+			return &metricspb.NumberDataPoint{Value: &metricspb.NumberDataPoint_AsInt{AsInt: t.Count}}
+		}),
+		sum("zarf.operation.duration", "Time spent running Zarf operations.", "s", func(t totals) *metricspb.NumberDataPoint {
+			return &metricspb.NumberDataPoint{Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: t.DurationSeconds}}
+		}),
+		sum("zarf.package.size", "Size of the packages Zarf operations created or deployed.", "By", func(t totals) *metricspb.NumberDataPoint {
+			return &metricspb.NumberDataPoint{Value: &metricspb.NumberDataPoint_AsInt{AsInt: t.PackageBytes}}
+		}),
+	}
+
+	return &collectorpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				stringAttribute("service.name", "zarf"),
+				stringAttribute("service.version", cfg.Version),
+				stringAttribute("service.instance.id", st.InstanceID),
+				stringAttribute("os.type", runtime.GOOS),
+				stringAttribute("host.arch", runtime.GOARCH),
+			}},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: "github.com/zarf-dev/zarf", Version: cfg.Version},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
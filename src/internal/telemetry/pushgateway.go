@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package telemetry exports anonymous usage metrics of Zarf operations to a user-specified collector.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Names of the exported metrics.
+const (
+	metricOperations       = "zarf_operations_total"
+	metricDurationSeconds  = "zarf_operation_duration_seconds_total"
+	metricPackageBytes     = "zarf_package_bytes_total"
+	metricBuildInfo        = "zarf_build_info"
+	pushgatewayJob         = "zarf"
+	pushgatewayInstanceKey = "instance"
+)
+
+// pushGateway replaces the metrics of this instance on a Prometheus pushgateway.
+func pushGateway(ctx context.Context, client *http.Client, cfg Config, st *state) error {
+	labels := []string{"command", "result"}
+	operations := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricOperations,
+		Help: "Number of Zarf operations that ran.",
+	}, labels)
+	duration := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricDurationSeconds,
+		Help: "Time spent running Zarf operations.",
+	}, labels)
+	packageSize := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: metricPackageBytes,
+		Help: "Size of the packages Zarf operations created or deployed.",
+	}, labels)
+	buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricBuildInfo,
+		Help: "Version and platform of the Zarf instance.",
+	}, []string{"version", "os", "arch"})
+
+	for _, t := range st.Totals {
+		operations.WithLabelValues(t.Command, t.Result).Add(float64(t.Count))
+		duration.WithLabelValues(t.Command, t.Result).Add(t.DurationSeconds)
+		packageSize.WithLabelValues(t.Command, t.Result).Add(float64(t.PackageBytes))
+	}
+	buildInfo.WithLabelValues(cfg.Version, runtime.GOOS, runtime.GOARCH).Set(1)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(operations, duration, packageSize, buildInfo)
+
+	err := push.New(cfg.Endpoint, pushgatewayJob).
+		Grouping(pushgatewayInstanceKey, st.InstanceID).
+		Gatherer(registry).
+		Client(client).
+		PushContext(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to push metrics to %s: %w", cfg.Endpoint, err)
+	}
+	return nil
+}
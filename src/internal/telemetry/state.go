@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package telemetry exports anonymous usage metrics of Zarf operations to a user-specified collector.
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+)
+
+// stateFile is the file the metrics are kept in, so the exported counters grow across runs of the CLI.
+const stateFile = "telemetry.json"
+
+// totals are the cumulative metrics of a command with a result.
+type totals struct {
+	Command         string  `json:"command"`
+	Result          string  `json:"result"`
+	Count           int64   `json:"count"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	PackageBytes    int64   `json:"packageBytes"`
+}
+
+// state are the metrics of this Zarf instance.
+type state struct {
+	// InstanceID is a random ID that tells the instances of a fleet apart without identifying them
+	InstanceID string    `json:"instanceID"`
+	Start      time.Time `json:"start"`
+	Totals     []totals  `json:"totals"`
+}
+
+func loadState(dir string) (*state, error) {
+	st := &state{}
+	b, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read the metrics state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, st); err != nil {
+			return nil, fmt.Errorf("unable to read the metrics state: %w", err)
+		}
+	}
+	if st.InstanceID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		st.InstanceID = hex.EncodeToString(id)
+		st.Start = time.Now().UTC()
+	}
+	return st, nil
+}
+
+func (st *state) save(dir string) error {
+	if err := helpers.CreateDirectory(dir, helpers.ReadExecuteAllWriteUser); err != nil {
+		return err
+	}
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, stateFile), b, helpers.ReadWriteUser); err != nil {
+		return fmt.Errorf("unable to write the metrics state: %w", err)
+	}
+	return nil
+}
+
+func (st *state) record(op Operation, packageBytes int64) {
+	result := ResultSuccess
+	if op.Failed {
+		result = ResultFailure
+	}
+	for i := range st.Totals {
+		if st.Totals[i].Command == op.Command && st.Totals[i].Result == result {
+			st.Totals[i].Count++
+			st.Totals[i].DurationSeconds += op.Duration.Seconds()
+			st.Totals[i].PackageBytes += packageBytes
+			return
+		}
+	}
+	st.Totals = append(st.Totals, totals{
+		Command:         op.Command,
+		Result:          result,
+		Count:           1,
+		DurationSeconds: op.Duration.Seconds(),
+		PackageBytes:    packageBytes,
+	})
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package telemetry exports anonymous usage metrics of Zarf operations to a user-specified collector.
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Protocols that metrics can be exported with.
+const (
+	ProtocolPushgateway = "pushgateway"
+	ProtocolOTLP        = "otlp"
+)
+
+// Resulting states of an operation.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Config is the configuration of a metrics export.
+type Config struct {
+	// Endpoint is the URL of the Prometheus pushgateway or OTLP/HTTP collector
+	Endpoint string
+	// Protocol is the protocol to export the metrics with
+	Protocol string
+	// StateDir is the directory the cumulative metrics and the anonymous instance ID are kept in
+	StateDir string
+	// Version is the version of Zarf that ran the operation
+	Version string
+	// Insecure skips the verification of the endpoint's certificate
+	Insecure bool
+}
+
+// Operation is a Zarf command that ran.
+//
+// Only the command path is recorded, arguments, flags, package names and hostnames are never exported.
+type Operation struct {
+	Command  string
+	Failed   bool
+	Duration time.Duration
+}
+
+var packageBytes atomic.Int64
+
+// RecordPackageSize records the size of a package the current operation created or deployed.
+func RecordPackageSize(size int64) {
+	packageBytes.Add(size)
+}
+
+// Export adds the operation to the metrics kept in the state directory and exports them to the endpoint.
+func Export(ctx context.Context, cfg Config, op Operation) error {
+	if cfg.Protocol != ProtocolPushgateway && cfg.Protocol != ProtocolOTLP {
+		return fmt.Errorf("unsupported metrics protocol %q, must be one of %s or %s", cfg.Protocol, ProtocolPushgateway, ProtocolOTLP)
+	}
+
+	st, err := loadState(cfg.StateDir)
+	if err != nil {
+		return err
+	}
+	st.record(op, packageBytes.Swap(0))
+	if err := st.save(cfg.StateDir); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if cfg.Insecure {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // the user asked for insecure connections
+		client.Transport = transport
+	}
+
+	if cfg.Protocol == ProtocolOTLP {
+		return pushOTLP(ctx, client, cfg, st, time.Now())
+	}
+	return pushGateway(ctx, client, cfg, st)
+}
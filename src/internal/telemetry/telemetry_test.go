@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector records the last request it received.
+type collector struct {
+	path        string
+	contentType string
+	body        []byte
+}
+
+func newCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+	c := &collector{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.path = r.URL.Path
+		c.contentType = r.Header.Get("Content-Type")
+		c.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv.URL
+}
+
+func TestStateRecord(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	st, err := loadState(dir)
+	require.NoError(t, err)
+	require.Len(t, st.InstanceID, 32)
+
+	st.record(Operation{Command: "package deploy", Duration: 2 * time.Second}, 100)
+	st.record(Operation{Command: "package deploy", Duration: time.Second}, 50)
+	st.record(Operation{Command: "package deploy", Failed: true, Duration: time.Second}, 0)
+	require.NoError(t, st.save(dir))
+
+	loaded, err := loadState(dir)
+	require.NoError(t, err)
+	require.Equal(t, st.InstanceID, loaded.InstanceID)
+	require.Equal(t, []totals{
+		{Command: "package deploy", Result: ResultSuccess, Count: 2, DurationSeconds: 3, PackageBytes: 150},
+		{Command: "package deploy", Result: ResultFailure, Count: 1, DurationSeconds: 1},
+	}, loaded.Totals)
+}
+
+func TestExportPushgateway(t *testing.T) {
+	t.Parallel()
+
+	c, endpoint := newCollector(t)
+	cfg := Config{Endpoint: endpoint, Protocol: ProtocolPushgateway, StateDir: t.TempDir(), Version: "v0.0.1"}
+	require.NoError(t, Export(context.Background(), cfg, Operation{Command: "package create", Duration: time.Second}))
+	require.NoError(t, Export(context.Background(), cfg, Operation{Command: "package create", Duration: time.Second}))
+
+	st, err := loadState(cfg.StateDir)
+	require.NoError(t, err)
+	require.Equal(t, "/metrics/job/zarf/instance/"+st.InstanceID, c.path)
+	body := string(c.body)
+	require.Contains(t, body, metricOperations)
+	require.Contains(t, body, metricDurationSeconds)
+	require.Contains(t, body, metricPackageBytes)
+	require.Contains(t, body, metricBuildInfo)
+	require.Contains(t, body, "package create")
+	require.Contains(t, body, "v0.0.1")
+}
+
+// TestExportOTLP does not run in parallel as the other exports would take the recorded package size.
+func TestExportOTLP(t *testing.T) {
+	c, endpoint := newCollector(t)
+	cfg := Config{Endpoint: endpoint, Protocol: ProtocolOTLP, StateDir: t.TempDir(), Version: "v0.0.1"}
+	RecordPackageSize(2048)
+	require.NoError(t, Export(context.Background(), cfg, Operation{Command: "package deploy", Duration: time.Second}))
+
+	require.Equal(t, otlpMetricsPath, c.path)
+	require.Equal(t, "application/x-protobuf", c.contentType)
+	req := &collectorpb.ExportMetricsServiceRequest{}
+	require.NoError(t, proto.Unmarshal(c.body, req))
+	require.Len(t, req.ResourceMetrics, 1)
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 3)
+	require.Equal(t, "zarf.operations", metrics[0].Name)
+	points := metrics[0].GetSum().DataPoints
+	require.Len(t, points, 1)
+	require.Equal(t, int64(1), points[0].GetAsInt())
+	require.Equal(t, int64(2048), metrics[2].GetSum().DataPoints[0].GetAsInt())
+	require.True(t, metrics[0].GetSum().IsMonotonic)
+}
+
+func TestExportErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "collector unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name     string
+		protocol string
+		expected string
+	}{
+		{
+			name:     "unsupported protocol",
+			protocol: "statsd",
+			expected: "unsupported metrics protocol",
+		},
+		{
+			name:     "pushgateway unavailable",
+			protocol: ProtocolPushgateway,
+			expected: "unable to push metrics",
+		},
+		{
+			name:     "collector unavailable",
+			protocol: ProtocolOTLP,
+			expected: "collector unavailable",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := Config{Endpoint: srv.URL, Protocol: tt.protocol, StateDir: t.TempDir()}
+			err := Export(context.Background(), cfg, Operation{Command: "package create"})
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Names of the resources of the Zarf agent
+const (
+	ZarfAgentWebhookName    = "zarf"
+	ZarfAgentServiceName    = "agent-hook"
+	ZarfAgentPodWebhookName = "agent-pod.zarf.dev"
+)
+
+// CheckAgentHealth returns the reasons the Zarf agent would not mutate the resources of a deployment: its mutating
+// webhook is missing, a webhook ignores failures so resources are created unmutated while the agent is down, or the
+// agent has no ready endpoints to serve the webhook.
+func (c *Cluster) CheckAgentHealth(ctx context.Context) error {
+	webhookConfig, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, ZarfAgentWebhookName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return fmt.Errorf("the %s mutating webhook configuration of the Zarf agent does not exist", ZarfAgentWebhookName)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the mutating webhook configuration of the Zarf agent: %w", err)
+	}
+
+	errs := []error{}
+	hasPodWebhook := false
+	for _, webhook := range webhookConfig.Webhooks {
+		if webhook.Name == ZarfAgentPodWebhookName {
+			hasPodWebhook = true
+		}
+		if webhook.FailurePolicy != nil && *webhook.FailurePolicy == admissionregistrationv1.Ignore {
+			errs = append(errs, fmt.Errorf("the %s webhook has the failure policy %s, resources are not mutated while the agent is unavailable", webhook.Name, admissionregistrationv1.Ignore))
+		}
+	}
+	if !hasPodWebhook {
+		errs = append(errs, fmt.Errorf("the %s webhook is missing, the images of pods are not rewritten", ZarfAgentPodWebhookName))
+	}
+
+	endpointSlices, err := c.Clientset.DiscoveryV1().EndpointSlices(ZarfNamespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, ZarfAgentServiceName),
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to get the endpoints of the Zarf agent: %w", err))
+		return errors.Join(errs...)
+	}
+	if !hasReadyEndpoint(endpointSlices.Items) {
+		errs = append(errs, fmt.Errorf("the %s service has no ready endpoints, the Zarf agent is not serving", ZarfAgentServiceName))
+	}
+	return errors.Join(errs...)
+}
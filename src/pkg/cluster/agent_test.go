@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestCheckAgentHealth(t *testing.T) {
+	t.Parallel()
+
+	ignore := admissionregistrationv1.Ignore
+	fail := admissionregistrationv1.Fail
+	ready := true
+	notReady := false
+
+	tests := []struct {
+		name         string
+		webhooks     []admissionregistrationv1.MutatingWebhook
+		noWebhook    bool
+		endpoints    []discoveryv1.Endpoint
+		expectedErrs []string
+	}{
+		{
+			name:      "healthy",
+			webhooks:  []admissionregistrationv1.MutatingWebhook{{Name: ZarfAgentPodWebhookName, FailurePolicy: &fail}},
+			endpoints: []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		},
+		{
+			name:         "missing webhook configuration",
+			noWebhook:    true,
+			expectedErrs: []string{"the zarf mutating webhook configuration of the Zarf agent does not exist"},
+		},
+		{
+			name: "unhealthy",
+			webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "agent-flux-gitrepo.zarf.dev", FailurePolicy: &ignore},
+			},
+			endpoints: []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}}},
+			expectedErrs: []string{
+				"the agent-flux-gitrepo.zarf.dev webhook has the failure policy Ignore",
+				"the agent-pod.zarf.dev webhook is missing",
+				"the agent-hook service has no ready endpoints",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			cs := fake.NewSimpleClientset()
+			c := &Cluster{Clientset: cs}
+			if !tt.noWebhook {
+				webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: ZarfAgentWebhookName},
+					Webhooks:   tt.webhooks,
+				}
+				_, err := cs.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(ctx, webhookConfig, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "agent-hook-abcde",
+					Namespace: ZarfNamespaceName,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: ZarfAgentServiceName},
+				},
+				Endpoints: tt.endpoints,
+			}
+			_, err := cs.DiscoveryV1().EndpointSlices(ZarfNamespaceName).Create(ctx, endpointSlice, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			err = c.CheckAgentHealth(ctx)
+			if len(tt.expectedErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, expectedErr := range tt.expectedErrs {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/internal/gitea"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// CreateArtifactToken creates a new push token for the internal artifact server in Gitea as the push user of the git
+// server, and sets it in the state along with when it was created and expires. The previous token is deleted.
+func (c *Cluster) CreateArtifactToken(ctx context.Context, state *types.ZarfState, gitServer types.GitServerInfo) error {
+	tunnel, err := c.NewTunnel(ZarfNamespaceName, SvcResource, ZarfGitServerName, "", 0, ZarfGitServerPort)
+	if err != nil {
+		return err
+	}
+	_, err = tunnel.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+	giteaClient, err := gitea.NewClient(tunnel.HTTPEndpoint(), gitServer.PushUsername, gitServer.PushPassword)
+	if err != nil {
+		return err
+	}
+	return tunnel.Wrap(func() error {
+		token, err := giteaClient.CreatePackageRegistryToken(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to create an artifact registry token for Gitea: %w", err)
+		}
+		return state.ArtifactServer.SetPushToken(token, time.Now())
+	})
+}
+
+// RotateArtifactToken replaces the push token of the internal artifact server in the Zarf state with a new one when
+// it expires within the duration, so that clients reading the token from the state never use an expired one.
+func (c *Cluster) RotateArtifactToken(ctx context.Context, before time.Duration) error {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	artifactServer := state.ArtifactServer
+	if !artifactServer.IsInternal() {
+		if artifactServer.PushTokenExpiresWithin(before) {
+			message.Warnf("The push token of the artifact server %s expires at %s, it is not managed by Zarf and must be updated with 'zarf tools update-creds artifact'",
+				artifactServer.Address, artifactServer.PushTokenExpiresAt.Format(time.RFC3339))
+			return nil
+		}
+		message.Infof("The artifact server %s is not managed by Zarf, there is no token to rotate", artifactServer.Address)
+		return nil
+	}
+	if artifactServer.PushToken != "" && !artifactServer.PushTokenExpiresWithin(before) {
+		message.Infof("The push token of the artifact server does not expire within %s, there is no token to rotate", before)
+		return nil
+	}
+
+	if err := c.CreateArtifactToken(ctx, state, state.GitServer); err != nil {
+		return err
+	}
+	if err := c.SaveZarfState(ctx, state); err != nil {
+		return fmt.Errorf("unable to save the rotated artifact server push token: %w", err)
+	}
+	if state.ArtifactServer.PushTokenExpiresAt != nil {
+		message.Infof("Rotated the push token of the artifact server, it expires at %s", state.ArtifactServer.PushTokenExpiresAt.Format(time.RFC3339))
+		return nil
+	}
+	message.Infof("Rotated the push token of the artifact server")
+	return nil
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestArtifactPushTokenExpiry(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 500, time.UTC)
+	expiresAt := created.Truncate(time.Second).Add(720 * time.Hour)
+	tests := []struct {
+		name          string
+		lifetime      string
+		token         string
+		expectedError string
+		expiresAt     *time.Time
+	}{
+		{
+			name:  "no lifetime",
+			token: "token",
+		},
+		{
+			name:      "lifetime",
+			lifetime:  "720h",
+			token:     "token",
+			expiresAt: &expiresAt,
+		},
+		{
+			name:     "empty token",
+			lifetime: "720h",
+		},
+		{
+			name:          "invalid lifetime",
+			lifetime:      "30 days",
+			token:         "token",
+			expectedError: `invalid artifact push token lifetime "30 days": time: unknown unit " days" in duration "30 days"`,
+		},
+		{
+			name:          "negative lifetime",
+			lifetime:      "-1h",
+			token:         "token",
+			expectedError: `invalid artifact push token lifetime "-1h": the lifetime must be positive`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			as := types.ArtifactServerInfo{PushTokenLifetime: tt.lifetime}
+			err := as.SetPushToken(tt.token, created)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.token, as.PushToken)
+			if tt.token == "" {
+				require.Nil(t, as.PushTokenCreatedAt)
+			} else {
+				require.Equal(t, created.Truncate(time.Second), *as.PushTokenCreatedAt)
+			}
+			require.Equal(t, tt.expiresAt, as.PushTokenExpiresAt)
+		})
+	}
+
+	as := types.ArtifactServerInfo{PushTokenLifetime: "24h"}
+	require.NoError(t, as.SetPushToken("token", time.Now()))
+	require.False(t, as.PushTokenExpiresWithin(time.Hour))
+	require.True(t, as.PushTokenExpiresWithin(72*time.Hour))
+	require.False(t, types.ArtifactServerInfo{PushToken: "token"}.PushTokenExpiresWithin(72*time.Hour))
+}
+
+func TestRotateArtifactToken(t *testing.T) {
+	t.Parallel()
+
+	later := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	soon := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name           string
+		artifactServer types.ArtifactServerInfo
+	}{
+		{
+			name: "internal token does not expire",
+			artifactServer: types.ArtifactServerInfo{
+				Address:   types.ZarfInClusterArtifactServiceURL,
+				PushToken: "token",
+			},
+		},
+		{
+			name: "internal token does not expire soon",
+			artifactServer: types.ArtifactServerInfo{
+				Address:            types.ZarfInClusterArtifactServiceURL,
+				PushToken:          "token",
+				PushTokenExpiresAt: &later,
+			},
+		},
+		{
+			name: "external token expires soon",
+			artifactServer: types.ArtifactServerInfo{
+				Address:            "https://artifacts.example.com",
+				PushToken:          "token",
+				PushTokenExpiresAt: &soon,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			c := newZarfStateTestCluster()
+			state := testZarfState()
+			state.ArtifactServer = tt.artifactServer
+			require.NoError(t, c.SaveZarfState(ctx, state))
+
+			// Tokens are left alone without a tunnel to Gitea being opened
+			err := c.RotateArtifactToken(ctx, 72*time.Hour)
+			require.NoError(t, err)
+			loaded, err := c.LoadZarfState(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.artifactServer, loaded.ArtifactServer)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const (
+	// stateBackupMagic starts every encrypted state backup and versions its format
+	stateBackupMagic   = "ZARF-STATE-BACKUP-V1"
+	stateBackupSaltLen = 16
+	// scrypt parameters recommended for interactive use
+	stateBackupScryptN = 1 << 15
+	stateBackupScryptR = 8
+	stateBackupScryptP = 1
+)
+
+// ErrStateBackupPassphrase is returned when a state backup can not be decrypted with the passphrase.
+var ErrStateBackupPassphrase = errors.New("unable to decrypt the state backup, the passphrase is incorrect or the backup is corrupt")
+
+// StateBackup is the Zarf control data of a cluster needed to restore it into a rebuilt cluster.
+type StateBackup struct {
+	// Time the backup was made
+	CreatedAt time.Time `json:"createdAt"`
+	// Zarf state, including its credentials
+	State *types.ZarfState `json:"state"`
+	// Secrets that record the packages deployed to the cluster
+	PackageSecrets []corev1.Secret `json:"packageSecrets"`
+	// Zarf-managed image and git pull secrets of every namespace
+	PullSecrets []corev1.Secret `json:"pullSecrets"`
+}
+
+// BackupState returns the Zarf state, the deployed package secrets and the Zarf-managed pull secrets of the cluster.
+func (c *Cluster) BackupState(ctx context.Context) (*StateBackup, error) {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	backup := &StateBackup{
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+		State:          state,
+		PackageSecrets: []corev1.Secret{},
+		PullSecrets:    []corev1.Secret{},
+	}
+
+	packageSecrets, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).List(ctx, metav1.ListOptions{LabelSelector: ZarfPackageInfoLabel})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range packageSecrets.Items {
+		if !strings.HasPrefix(secret.Name, config.ZarfPackagePrefix) {
+			continue
+		}
+		backup.PackageSecrets = append(backup.PackageSecrets, backupSecret(secret))
+	}
+
+	pullSecrets, err := c.Clientset.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=zarf", ZarfManagedByLabel)})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range pullSecrets.Items {
+		if secret.Name != config.ZarfImagePullSecretName && secret.Name != config.ZarfGitServerSecretName {
+			continue
+		}
+		backup.PullSecrets = append(backup.PullSecrets, backupSecret(secret))
+	}
+	for _, secrets := range [][]corev1.Secret{backup.PackageSecrets, backup.PullSecrets} {
+		slices.SortFunc(secrets, func(a, b corev1.Secret) int {
+			return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+		})
+	}
+	return backup, nil
+}
+
+// backupSecret returns the parts of a secret needed to recreate it in another cluster.
+func backupSecret(secret corev1.Secret) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			Labels:    secret.Labels,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+}
+
+// RestoreState saves the Zarf state of a backup to the cluster and recreates its deployed package and pull secrets.
+// Pull secrets of namespaces that do not exist in the cluster are skipped.
+func (c *Cluster) RestoreState(ctx context.Context, backup *StateBackup) error {
+	if backup.State == nil {
+		return errors.New("the state backup does not contain a Zarf state")
+	}
+	_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, NewZarfManagedNamespace(ZarfNamespaceName), metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create the Zarf namespace: %w", err)
+	}
+	if err := c.SaveZarfState(ctx, backup.State); err != nil {
+		return fmt.Errorf("unable to restore the Zarf state: %w", err)
+	}
+
+	for _, secret := range backup.PackageSecrets {
+		if err := c.restoreSecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+	for _, secret := range backup.PullSecrets {
+		_, err := c.Clientset.CoreV1().Namespaces().Get(ctx, secret.Namespace, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			message.Warnf("Skipping the secret %s/%s as its namespace does not exist, it is created when a package is deployed to the namespace", secret.Namespace, secret.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.restoreSecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSecret creates the secret of a backup, replacing the secret of the same name when it exists.
+func (c *Cluster) restoreSecret(ctx context.Context, secret corev1.Secret) error {
+	_, err := c.Clientset.CoreV1().Secrets(secret.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = c.Clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, &secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to restore the secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// EncryptStateBackup returns the state backup compressed and encrypted with AES-256-GCM under a key derived from the
+// passphrase with scrypt.
+func EncryptStateBackup(backup *StateBackup, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("a passphrase is required to encrypt the state backup")
+	}
+	b, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	var plaintext bytes.Buffer
+	gz := gzip.NewWriter(&plaintext)
+	if _, err := gz.Write(b); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, stateBackupSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := stateBackupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := []byte(stateBackupMagic)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext.Bytes(), []byte(stateBackupMagic)), nil
+}
+
+// DecryptStateBackup returns the state backup encrypted with EncryptStateBackup.
+func DecryptStateBackup(b []byte, passphrase []byte) (*StateBackup, error) {
+	rest, ok := bytes.CutPrefix(b, []byte(stateBackupMagic))
+	if !ok {
+		return nil, errors.New("the file is not a Zarf state backup")
+	}
+	if len(rest) < stateBackupSaltLen {
+		return nil, ErrStateBackupPassphrase
+	}
+	salt, rest := rest[:stateBackupSaltLen], rest[stateBackupSaltLen:]
+	aead, err := stateBackupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrStateBackupPassphrase
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(stateBackupMagic))
+	if err != nil {
+		return nil, ErrStateBackupPassphrase
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	backup := &StateBackup{}
+	if err := json.Unmarshal(data, backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// stateBackupCipher returns the AES-256-GCM cipher keyed by the passphrase and salt.
+func stateBackupCipher(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, stateBackupScryptN, stateBackupScryptR, stateBackupScryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+func TestStateBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	packageSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            config.ZarfPackagePrefix + "podinfo",
+			Namespace:       ZarfNamespaceName,
+			Labels:          map[string]string{ZarfPackageInfoLabel: "podinfo"},
+			ResourceVersion: "42",
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{deployedPackageDataKey: []byte(`{"name":"podinfo"}`)},
+	}
+	pullSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ZarfImagePullSecretName,
+			Namespace: "podinfo",
+			Labels:    map[string]string{ZarfManagedByLabel: "zarf"},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+	}
+	missingNamespaceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ZarfGitServerSecretName,
+			Namespace: "removed",
+			Labels:    map[string]string{ZarfManagedByLabel: "zarf"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"username": []byte("pull-user")},
+	}
+	unmanagedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-secret",
+			Namespace: "podinfo",
+			Labels:    map[string]string{ZarfManagedByLabel: "zarf"},
+		},
+	}
+	c := newZarfStateTestCluster(packageSecret, pullSecret, missingNamespaceSecret, unmanagedSecret)
+	state := testZarfState()
+	require.NoError(t, c.SaveZarfState(ctx, state))
+
+	backup, err := c.BackupState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, backup.State)
+	require.Len(t, backup.PackageSecrets, 1)
+	require.Empty(t, backup.PackageSecrets[0].ResourceVersion)
+	require.Len(t, backup.PullSecrets, 2)
+	require.Equal(t, config.ZarfImagePullSecretName, backup.PullSecrets[0].Name)
+	require.Equal(t, "removed", backup.PullSecrets[1].Namespace)
+
+	b, err := EncryptStateBackup(backup, []byte("passphrase"))
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "registry-push-password")
+	_, err = DecryptStateBackup(b, []byte("wrong"))
+	require.ErrorIs(t, err, ErrStateBackupPassphrase)
+	_, err = DecryptStateBackup([]byte("kind: ZarfPackageConfig"), []byte("passphrase"))
+	require.EqualError(t, err, "the file is not a Zarf state backup")
+	decrypted, err := DecryptStateBackup(b, []byte("passphrase"))
+	require.NoError(t, err)
+	require.Equal(t, backup, decrypted)
+
+	// The backup is restored into a rebuilt cluster that only has the namespace of the package
+	rebuilt := newZarfStateTestCluster(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "podinfo"}})
+	err = rebuilt.RestoreState(ctx, decrypted)
+	require.NoError(t, err)
+	loaded, err := rebuilt.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+	require.NoError(t, rebuilt.VerifyZarfState(ctx))
+	restoredPackage, err := rebuilt.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, packageSecret.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, packageSecret.Data, restoredPackage.Data)
+	require.Equal(t, packageSecret.Labels, restoredPackage.Labels)
+	restoredPull, err := rebuilt.Clientset.CoreV1().Secrets("podinfo").Get(ctx, pullSecret.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, pullSecret.Data, restoredPull.Data)
+	_, err = rebuilt.Clientset.CoreV1().Secrets("removed").Get(ctx, missingNamespaceSecret.Name, metav1.GetOptions{})
+	require.Error(t, err)
+}
@@ -12,13 +12,16 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 
 	"github.com/avast/retry-go/v4"
 	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
 
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
@@ -27,13 +30,21 @@ const (
 	DefaultTimeout = 30 * time.Second
 	// AgentLabel is used to give instructions to the Zarf agent
 	AgentLabel = "zarf.dev/agent"
+	// FieldManagerName is the field manager Zarf uses for server-side apply
+	FieldManagerName = "zarf"
 )
 
 // Cluster Zarf specific cluster management functions.
 type Cluster struct {
-	Clientset  kubernetes.Interface
+	Clientset kubernetes.Interface
+	// Dynamic is used for the ZarfState custom resource, the state is kept in the legacy zarf-state secret when it is nil
+	Dynamic    dynamic.Interface
 	RestConfig *rest.Config
 	Watcher    watcher.StatusWatcher
+	// StateKey is an operator-held key the Zarf state is additionally signed with when it is set
+	StateKey []byte
+
+	services *serviceCache
 }
 
 // NewClusterWithWait creates a new Cluster instance and waits for the given timeout for the cluster to be ready.
@@ -76,18 +87,50 @@ func NewClusterWithWait(ctx context.Context) (*Cluster, error) {
 // NewCluster creates a new Cluster instance and validates connection to the cluster by fetching the Kubernetes version.
 func NewCluster() (*Cluster, error) {
 	clusterErr := errors.New("unable to connect to the cluster")
-	clientset, config, err := pkgkubernetes.ClientAndConfig()
+	if config.CommonOptions.ContextPolicy.IsSet() {
+		context, err := CurrentContext("", "")
+		if err != nil {
+			return nil, errors.Join(clusterErr, err)
+		}
+		if err := CheckContextPolicy(config.CommonOptions.ContextPolicy, context, config.CommonOptions.ConfirmContext); err != nil {
+			return nil, err
+		}
+	}
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), nil).ClientConfig()
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
+	}
+	proxy, err := KubeProxyFunc(config.CommonOptions.KubeProxy)
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
+	}
+	// A proxy-url in the kubeconfig is used when no proxy is set
+	if proxy != nil {
+		restConfig.Proxy = proxy
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
 	}
-	watcher, err := pkgkubernetes.WatcherForConfig(config)
+	watcher, err := pkgkubernetes.WatcherForConfig(restConfig)
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
+	}
+	stateKey, err := ReadStateKey(config.CommonOptions.StateKeyPath)
+	if err != nil {
+		return nil, err
+	}
 	c := &Cluster{
 		Clientset:  clientset,
-		RestConfig: config,
+		Dynamic:    dynamicClient,
+		RestConfig: restConfig,
 		Watcher:    watcher,
+		StateKey:   stateKey,
+		services:   newServiceCache(serviceCacheTTL),
 	}
 	// Dogsled the version output. We just want to ensure no errors were returned to validate cluster connection.
 	_, err = c.Clientset.Discovery().ServerVersion()
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// Protected contexts are only prompted for once per process
+	confirmedContextsMu sync.Mutex
+	confirmedContexts   = map[string]bool{}
+)
+
+// CurrentContext returns the name of the context of the kubeconfig that is targeted, which is the current context of
+// the kubeconfig at the path, or the default kubeconfig when it is empty, unless the override is set.
+func CurrentContext(kubeconfig, override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	raw, err := rules.Load()
+	if err != nil {
+		return "", err
+	}
+	return raw.CurrentContext, nil
+}
+
+// CheckContextPolicy returns an error when the kubeconfig context may not be targeted under the policy. A protected
+// context is targeted when confirmed is its name, or when its name is typed at a prompt when stdin is a terminal.
+func CheckContextPolicy(policy types.ContextPolicy, context, confirmed string) error {
+	return checkContextPolicy(policy, context, confirmed, func(context string) (bool, error) {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return false, nil
+		}
+		return interactive.PromptContext(context)
+	})
+}
+
+func checkContextPolicy(policy types.ContextPolicy, context, confirmed string, prompt func(string) (bool, error)) error {
+	if !policy.Allows(context) {
+		return fmt.Errorf("the kubeconfig context %q is not allowed by the context policy, the allowed contexts are %s", context, strings.Join(policy.Allowed, ", "))
+	}
+	if !policy.Protects(context) || confirmed == context {
+		return nil
+	}
+
+	confirmedContextsMu.Lock()
+	defer confirmedContextsMu.Unlock()
+	if confirmedContexts[context] {
+		return nil
+	}
+	ok, err := prompt(context)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the kubeconfig context %q is protected by the context policy, pass --confirm-context %s or set ZARF_CONFIRM_CONTEXT=%s to target it", context, context, context)
+	}
+	confirmedContexts[context] = true
+	return nil
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestCheckContextPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := types.ContextPolicy{
+		Allowed:   []string{"kind-*", "prod-*"},
+		Protected: []string{"prod-*"},
+	}
+	tests := []struct {
+		name          string
+		policy        types.ContextPolicy
+		context       string
+		confirmed     string
+		typed         string
+		expectedError string
+	}{
+		{
+			name:    "no policy",
+			context: "anything",
+		},
+		{
+			name:    "allowed",
+			policy:  policy,
+			context: "kind-zarf",
+		},
+		{
+			name:          "not allowed",
+			policy:        policy,
+			context:       "staging-east",
+			expectedError: `the kubeconfig context "staging-east" is not allowed by the context policy, the allowed contexts are kind-*, prod-*`,
+		},
+		{
+			name:      "protected and confirmed",
+			policy:    policy,
+			context:   "prod-east",
+			confirmed: "prod-east",
+		},
+		{
+			name:    "protected and typed",
+			policy:  policy,
+			context: "prod-west",
+			typed:   "prod-west",
+		},
+		{
+			name:          "protected and confirmed another context",
+			policy:        policy,
+			context:       "prod-north",
+			confirmed:     "prod-east",
+			typed:         "prod",
+			expectedError: `the kubeconfig context "prod-north" is protected by the context policy, pass --confirm-context prod-north or set ZARF_CONFIRM_CONTEXT=prod-north to target it`,
+		},
+		{
+			name:    "only protected",
+			policy:  types.ContextPolicy{Protected: []string{"prod-*"}},
+			context: "kind-zarf",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, tt.policy.Validate())
+			err := checkContextPolicy(tt.policy, tt.context, tt.confirmed, func(context string) (bool, error) {
+				return tt.typed == context, nil
+			})
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	require.EqualError(t, types.ContextPolicy{Allowed: []string{"prod-["}}.Validate(), `invalid context pattern "prod-[": syntax error in pattern`)
+}
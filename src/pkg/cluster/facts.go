@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// Annotations that mark a StorageClass as the cluster default
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// GetClusterFacts returns details of the cluster for use in templates and actions.
+// Facts that can not be looked up (e.g. due to RBAC) are left empty and their errors are returned alongside the rest.
+func (c *Cluster) GetClusterFacts(ctx context.Context) (types.ClusterFacts, error) {
+	facts := types.ClusterFacts{}
+	errs := []error{}
+
+	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to list the nodes: %w", err))
+	} else {
+		facts.NodeCount = len(nodeList.Items)
+	}
+
+	version, err := c.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to get the Kubernetes version: %w", err))
+	} else {
+		facts.K8sVersion = version.GitVersion
+	}
+
+	storageClassList, err := c.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to list the storage classes: %w", err))
+	} else {
+		for _, sc := range storageClassList.Items {
+			if sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+				facts.DefaultStorageClass = sc.Name
+				break
+			}
+		}
+	}
+
+	return facts, errors.Join(errs...)
+}
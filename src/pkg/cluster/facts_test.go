@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestGetClusterFacts(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	cs := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "slow"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{
+			Name:        "fast",
+			Annotations: map[string]string{defaultStorageClassAnnotation: "true"},
+		}},
+	)
+	cs.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.2"}
+	c := &Cluster{Clientset: cs}
+
+	facts, err := c.GetClusterFacts(ctx)
+	require.NoError(t, err)
+	expected := types.ClusterFacts{
+		NodeCount:           2,
+		K8sVersion:          "v1.30.2",
+		DefaultStorageClass: "fast",
+	}
+	require.Equal(t, expected, facts)
+}
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// HealthCheckStatus is the outcome of a cluster health check.
+type HealthCheckStatus string
+
+// The outcomes of a cluster health check, from best to worst.
+const (
+	HealthCheckPass HealthCheckStatus = "pass"
+	HealthCheckWarn HealthCheckStatus = "warn"
+	HealthCheckFail HealthCheckStatus = "fail"
+)
+
+// Names of the cluster health checks.
+const (
+	HealthCheckVersionSkew  = "version-skew"
+	HealthCheckStorageClass = "storage-class"
+	HealthCheckArchitecture = "architecture"
+	HealthCheckInjectorDisk = "injector-disk"
+	HealthCheckPodSecurity  = "pod-security"
+	HealthCheckWebhooks     = "webhooks"
+)
+
+const (
+	podSecurityEnforceLabel   = "pod-security.kubernetes.io/enforce"
+	podSecurityPolicyResource = "podsecuritypolicies"
+)
+
+// DefaultInjectorDisk is the ephemeral storage a node needs to run the injector with the default seed registry image.
+var DefaultInjectorDisk = resource.MustParse("256Mi")
+
+// HealthCheck is the outcome of one check of whether a cluster is ready for Zarf.
+type HealthCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+	// Whether the check passed, or how badly it did not
+	Status HealthCheckStatus `json:"status"`
+	// What was found, and how to fix it when the check did not pass
+	Message string `json:"message"`
+	// Commands or changes that fix the problem, given by the host preflight checks that did not pass
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// HealthCheckOptions configures the checks of whether a cluster is ready for Zarf.
+type HealthCheckOptions struct {
+	// Architecture of the package to deploy, which at least one node must have
+	Architecture string
+	// Ephemeral storage a node needs to run the injector during init
+	InjectorDisk resource.Quantity
+	// Namespaces whose pod security admission level is checked
+	Namespaces []string
+}
+
+// RunHealthChecks checks whether the cluster is ready for Zarf to init it or deploy packages to it. Checks that can
+// not be completed, e.g. due to RBAC, warn rather than fail.
+func (c *Cluster) RunHealthChecks(ctx context.Context, opts HealthCheckOptions) []HealthCheck {
+	return []HealthCheck{
+		c.checkVersionSkew(),
+		c.checkStorageClass(ctx),
+		c.checkArchitecture(ctx, opts.Architecture),
+		c.checkInjectorDisk(ctx, opts.InjectorDisk),
+		c.checkPodSecurity(ctx, opts.Namespaces),
+		c.checkWebhooks(ctx),
+	}
+}
+
+// HealthChecksPassed returns whether none of the checks failed.
+func HealthChecksPassed(checks []HealthCheck) bool {
+	for _, check := range checks {
+		if check.Status == HealthCheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// PrintHealthChecks prints the outcomes of the checks as a table, with a remediation column when any check has one.
+func PrintHealthChecks(checks []HealthCheck) {
+	header := []string{"Status", "Check", "Message"}
+	hasRemediation := slices.ContainsFunc(checks, func(check HealthCheck) bool { return check.Remediation != "" })
+	if hasRemediation {
+		header = append(header, "Remediation")
+	}
+	rows := [][]string{}
+	for _, check := range checks {
+		row := []string{strings.ToUpper(string(check.Status)), check.Name, check.Message}
+		if hasRemediation {
+			row = append(row, check.Remediation)
+		}
+		rows = append(rows, row)
+	}
+	message.Table(header, rows)
+}
+
+func newHealthCheck(name string, status HealthCheckStatus, format string, a ...any) HealthCheck {
+	return HealthCheck{Name: name, Status: status, Message: fmt.Sprintf(format, a...)}
+}
+
+// clientVersion returns the Kubernetes version of the client libraries Zarf was built with.
+func clientVersion() (*version.Version, error) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, fmt.Errorf("the build information of Zarf is not available")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "k8s.io/client-go" {
+			continue
+		}
+		// client-go v0.X.Y is released with Kubernetes v1.X.Y
+		v, err := version.ParseSemantic(dep.Version)
+		if err != nil {
+			return nil, err
+		}
+		return version.MajorMinor(1, v.Minor()), nil
+	}
+	return nil, fmt.Errorf("zarf was not built with k8s.io/client-go")
+}
+
+// checkVersionSkew checks that the Kubernetes version of the API server is within one minor version of the client
+// libraries Zarf was built with, as supported by the Kubernetes version skew policy.
+func (c *Cluster) checkVersionSkew() HealthCheck {
+	serverInfo, err := c.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return newHealthCheck(HealthCheckVersionSkew, HealthCheckWarn, "unable to get the Kubernetes version of the API server: %s", err)
+	}
+	server, err := version.ParseGeneric(serverInfo.GitVersion)
+	if err != nil {
+		return newHealthCheck(HealthCheckVersionSkew, HealthCheckWarn, "unable to parse the Kubernetes version %s of the API server: %s", serverInfo.GitVersion, err)
+	}
+	client, err := clientVersion()
+	if err != nil {
+		return newHealthCheck(HealthCheckVersionSkew, HealthCheckWarn, "unable to get the Kubernetes version Zarf was built for: %s", err)
+	}
+	skew := int(server.Minor()) - int(client.Minor())
+	if server.Major() != client.Major() || skew > 1 || skew < -1 {
+		return newHealthCheck(HealthCheckVersionSkew, HealthCheckWarn, "the API server runs Kubernetes %s, which is more than one minor version from the Kubernetes v%s Zarf was built for", serverInfo.GitVersion, client)
+	}
+	return newHealthCheck(HealthCheckVersionSkew, HealthCheckPass, "the API server runs Kubernetes %s and Zarf was built for Kubernetes v%s", serverInfo.GitVersion, client)
+}
+
+// checkStorageClass checks that a default storage class exists for the volumes of the registry and git server.
+func (c *Cluster) checkStorageClass(ctx context.Context) HealthCheck {
+	storageClasses, err := c.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return newHealthCheck(HealthCheckStorageClass, HealthCheckWarn, "unable to list the storage classes: %s", err)
+	}
+	if len(storageClasses.Items) == 0 {
+		return newHealthCheck(HealthCheckStorageClass, HealthCheckFail, "the cluster has no storage classes, the persistent volume claims of the registry and git server can not be bound")
+	}
+	for _, sc := range storageClasses.Items {
+		if sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			return newHealthCheck(HealthCheckStorageClass, HealthCheckPass, "the default storage class is %s", sc.Name)
+		}
+	}
+	return newHealthCheck(HealthCheckStorageClass, HealthCheckWarn, "none of the %d storage classes is the default, set the storage class of the registry and git server with --storage-class", len(storageClasses.Items))
+}
+
+// checkArchitecture checks that at least one node has the architecture of the package.
+func (c *Cluster) checkArchitecture(ctx context.Context, arch string) HealthCheck {
+	if arch == "" {
+		return newHealthCheck(HealthCheckArchitecture, HealthCheckPass, "no package architecture to check")
+	}
+	nodes, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return newHealthCheck(HealthCheckArchitecture, HealthCheckWarn, "unable to list the nodes: %s", err)
+	}
+	architectures := []string{}
+	for _, node := range nodes.Items {
+		if !slices.Contains(architectures, node.Status.NodeInfo.Architecture) {
+			architectures = append(architectures, node.Status.NodeInfo.Architecture)
+		}
+	}
+	if !slices.Contains(architectures, arch) {
+		return newHealthCheck(HealthCheckArchitecture, HealthCheckFail, "no node has the %s architecture, the nodes have %s", arch, strings.Join(architectures, ", "))
+	}
+	return newHealthCheck(HealthCheckArchitecture, HealthCheckPass, "nodes with the %s architecture are available", arch)
+}
+
+// checkInjectorDisk checks that a schedulable node without disk pressure has enough ephemeral storage for the injector.
+func (c *Cluster) checkInjectorDisk(ctx context.Context, required resource.Quantity) HealthCheck {
+	if required.IsZero() {
+		required = DefaultInjectorDisk
+	}
+	nodes, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return newHealthCheck(HealthCheckInjectorDisk, HealthCheckWarn, "unable to list the nodes: %s", err)
+	}
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable || hasBlockingTaints(node.Spec.Taints) || hasDiskPressure(node) {
+			continue
+		}
+		storage, ok := node.Status.Allocatable[corev1.ResourceEphemeralStorage]
+		if !ok || storage.Cmp(required) >= 0 {
+			return newHealthCheck(HealthCheckInjectorDisk, HealthCheckPass, "the node %s can run the injector", node.Name)
+		}
+	}
+	return newHealthCheck(HealthCheckInjectorDisk, HealthCheckFail, "no schedulable node without disk pressure has %s of ephemeral storage for the injector", required.String())
+}
+
+func hasDiskPressure(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPodSecurity checks that pod security admission and pod security policies do not reject the pods of Zarf.
+func (c *Cluster) checkPodSecurity(ctx context.Context, namespaces []string) HealthCheck {
+	problems := []string{}
+	for _, name := range namespaces {
+		namespace, err := c.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return newHealthCheck(HealthCheckPodSecurity, HealthCheckWarn, "unable to get the namespace %s: %s", name, err)
+		}
+		if level := namespace.Labels[podSecurityEnforceLabel]; level == "restricted" {
+			problems = append(problems, fmt.Sprintf("the namespace %s enforces the %s pod security standard, which rejects pods that do not run as non-root with a restricted security context", name, level))
+		}
+	}
+
+	resources, err := c.Clientset.Discovery().ServerResourcesForGroupVersion("policy/v1beta1")
+	if err == nil {
+		for _, r := range resources.APIResources {
+			if r.Name == podSecurityPolicyResource {
+				problems = append(problems, "the cluster serves pod security policies, which may reject the pods of Zarf unless a policy allows them")
+				break
+			}
+		}
+	} else if !kerrors.IsNotFound(err) {
+		return newHealthCheck(HealthCheckPodSecurity, HealthCheckWarn, "unable to check whether the cluster serves pod security policies: %s", err)
+	}
+
+	if len(problems) > 0 {
+		return newHealthCheck(HealthCheckPodSecurity, HealthCheckWarn, "%s", strings.Join(problems, "; "))
+	}
+	return newHealthCheck(HealthCheckPodSecurity, HealthCheckPass, "no pod security restrictions found")
+}
+
+// checkWebhooks checks that the services of the admission webhooks that reject requests when they fail have ready
+// endpoints, as otherwise the resources they intercept can not be created.
+func (c *Cluster) checkWebhooks(ctx context.Context) HealthCheck {
+	services := map[string][]string{}
+	addService := func(webhookName string, failurePolicy *admissionregistrationv1.FailurePolicyType, clientConfig admissionregistrationv1.WebhookClientConfig) {
+		// Webhooks called by URL can not be checked, and those that ignore failures do not block requests
+		if clientConfig.Service == nil || (failurePolicy != nil && *failurePolicy == admissionregistrationv1.Ignore) {
+			return
+		}
+		key := clientConfig.Service.Namespace + "/" + clientConfig.Service.Name
+		services[key] = append(services[key], webhookName)
+	}
+
+	mutating, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return newHealthCheck(HealthCheckWebhooks, HealthCheckWarn, "unable to list the mutating webhook configurations: %s", err)
+	}
+	for _, webhookConfig := range mutating.Items {
+		for _, webhook := range webhookConfig.Webhooks {
+			addService(webhook.Name, webhook.FailurePolicy, webhook.ClientConfig)
+		}
+	}
+	validating, err := c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return newHealthCheck(HealthCheckWebhooks, HealthCheckWarn, "unable to list the validating webhook configurations: %s", err)
+	}
+	for _, webhookConfig := range validating.Items {
+		for _, webhook := range webhookConfig.Webhooks {
+			addService(webhook.Name, webhook.FailurePolicy, webhook.ClientConfig)
+		}
+	}
+
+	keys := []string{}
+	for key := range services {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	unreachable := []string{}
+	for _, key := range keys {
+		namespace, name, _ := strings.Cut(key, "/")
+		endpointSlices, err := c.Clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, name),
+		})
+		if err != nil {
+			return newHealthCheck(HealthCheckWebhooks, HealthCheckWarn, "unable to get the endpoints of the service %s: %s", key, err)
+		}
+		if !hasReadyEndpoint(endpointSlices.Items) {
+			unreachable = append(unreachable, fmt.Sprintf("%s (%s)", key, strings.Join(services[key], ", ")))
+		}
+	}
+	if len(unreachable) > 0 {
+		return newHealthCheck(HealthCheckWebhooks, HealthCheckFail, "the services of these webhooks have no ready endpoints, so the requests they intercept are rejected: %s", strings.Join(unreachable, "; "))
+	}
+	return newHealthCheck(HealthCheckWebhooks, HealthCheckPass, "the services of all %d webhooks that reject requests on failure are reachable", len(keys))
+}
+
+func hasReadyEndpoint(endpointSlices []discoveryv1.EndpointSlice) bool {
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true
+			}
+		}
+	}
+	return false
+}
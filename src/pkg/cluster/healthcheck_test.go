@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func healthCheckNode(name, arch string, storage string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{Architecture: arch},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse(storage),
+			},
+		},
+	}
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	client, err := clientVersion()
+	require.NoError(t, err)
+	failurePolicy := admissionregistrationv1.Fail
+	ready := false
+
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		serverVersion  string
+		podSecurityAPI bool
+		opts           HealthCheckOptions
+		expected       map[string]HealthCheckStatus
+	}{
+		{
+			name: "ready cluster",
+			objects: []runtime.Object{
+				healthCheckNode("node", "amd64", "10Gi"),
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local-path", Annotations: map[string]string{defaultStorageClassAnnotation: "true"}}},
+			},
+			serverVersion: fmt.Sprintf("v1.%d.2", client.Minor()-1),
+			opts:          HealthCheckOptions{Architecture: "amd64", Namespaces: []string{ZarfNamespaceName}},
+			expected: map[string]HealthCheckStatus{
+				HealthCheckVersionSkew:  HealthCheckPass,
+				HealthCheckStorageClass: HealthCheckPass,
+				HealthCheckArchitecture: HealthCheckPass,
+				HealthCheckInjectorDisk: HealthCheckPass,
+				HealthCheckPodSecurity:  HealthCheckPass,
+				HealthCheckWebhooks:     HealthCheckPass,
+			},
+		},
+		{
+			name: "unready cluster",
+			objects: []runtime.Object{
+				healthCheckNode("node", "arm64", "100Mi"),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ZarfNamespaceName, Labels: map[string]string{podSecurityEnforceLabel: "restricted"}}},
+				&admissionregistrationv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+					Webhooks: []admissionregistrationv1.ValidatingWebhook{
+						{
+							Name:          "validate.policy.example.com",
+							FailurePolicy: &failurePolicy,
+							ClientConfig: admissionregistrationv1.WebhookClientConfig{
+								Service: &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "webhook"},
+							},
+						},
+					},
+				},
+				&discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{Name: "webhook-abc", Namespace: "policy", Labels: map[string]string{discoveryv1.LabelServiceName: "webhook"}},
+					Endpoints:  []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+				},
+			},
+			serverVersion:  fmt.Sprintf("v1.%d.0", client.Minor()+2),
+			podSecurityAPI: true,
+			opts:           HealthCheckOptions{Architecture: "amd64", Namespaces: []string{ZarfNamespaceName}},
+			expected: map[string]HealthCheckStatus{
+				HealthCheckVersionSkew:  HealthCheckWarn,
+				HealthCheckStorageClass: HealthCheckFail,
+				HealthCheckArchitecture: HealthCheckFail,
+				HealthCheckInjectorDisk: HealthCheckFail,
+				HealthCheckPodSecurity:  HealthCheckWarn,
+				HealthCheckWebhooks:     HealthCheckFail,
+			},
+		},
+		{
+			name: "no default storage class or architecture",
+			objects: []runtime.Object{
+				healthCheckNode("node", "amd64", "10Gi"),
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local-path"}},
+			},
+			serverVersion: fmt.Sprintf("v1.%d.0", client.Minor()),
+			expected: map[string]HealthCheckStatus{
+				HealthCheckVersionSkew:  HealthCheckPass,
+				HealthCheckStorageClass: HealthCheckWarn,
+				HealthCheckArchitecture: HealthCheckPass,
+				HealthCheckInjectorDisk: HealthCheckPass,
+				HealthCheckPodSecurity:  HealthCheckPass,
+				HealthCheckWebhooks:     HealthCheckPass,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cs := fake.NewSimpleClientset(tt.objects...)
+			discovery, ok := cs.Discovery().(*fakediscovery.FakeDiscovery)
+			require.True(t, ok)
+			discovery.FakedServerVersion = &version.Info{GitVersion: tt.serverVersion}
+			if tt.podSecurityAPI {
+				discovery.Resources = []*metav1.APIResourceList{
+					{GroupVersion: "policy/v1beta1", APIResources: []metav1.APIResource{{Name: podSecurityPolicyResource}}},
+				}
+			}
+			c := &Cluster{Clientset: cs}
+
+			checks := c.RunHealthChecks(context.Background(), tt.opts)
+			statuses := map[string]HealthCheckStatus{}
+			for _, check := range checks {
+				require.NotEmpty(t, check.Message)
+				statuses[check.Name] = check.Status
+			}
+			require.Equal(t, tt.expected, statuses)
+			require.Equal(t, !hasFailedStatus(tt.expected), HealthChecksPassed(checks))
+		})
+	}
+}
+
+func hasFailedStatus(statuses map[string]HealthCheckStatus) bool {
+	for _, status := range statuses {
+		if status == HealthCheckFail {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Names of the host preflight checks.
+const (
+	HostPreflightKernelModules = "kernel-modules"
+	HostPreflightCgroups       = "cgroups"
+	HostPreflightSELinux       = "selinux"
+	HostPreflightAppArmor      = "apparmor"
+	HostPreflightDiskSpace     = "disk-space"
+	HostPreflightPorts         = "ports"
+)
+
+// k3sDataDir is where K3s keeps its state, its images and the volumes of the local-path provisioner.
+const k3sDataDir = "/var/lib/rancher/k3s"
+
+// k3sSELinuxPolicy is installed by the k3s-selinux package that K3s needs on hosts that enforce SELinux.
+const k3sSELinuxPolicy = "/usr/share/selinux/packages/k3s.pp"
+
+var (
+	// k3sKernelModules are the kernel modules K3s loads when it starts.
+	k3sKernelModules = []string{"overlay", "br_netfilter", "nf_conntrack"}
+	// k3sCgroupControllers are the cgroup controllers the kubelet of K3s needs.
+	k3sCgroupControllers = []string{"cpu", "memory", "pids"}
+	// k3sPorts are the ports K3s listens on with its default arguments.
+	k3sPorts = []hostPort{
+		{Port: 6443, Protocol: "tcp", Purpose: "the Kubernetes API server"},
+		{Port: 10250, Protocol: "tcp", Purpose: "the kubelet"},
+		{Port: 8472, Protocol: "udp", Purpose: "flannel VXLAN"},
+	}
+	// k3sDiskRequirements are the free space the paths K3s writes to need, those on the same filesystem share it.
+	k3sDiskRequirements = []hostDiskRequirement{
+		{Path: "/var/lib/rancher", Required: resource.MustParse("10Gi")},
+		{Path: "/var/lib/kubelet", Required: resource.MustParse("1Gi")},
+		{Path: "/usr/sbin", Required: resource.MustParse("256Mi")},
+	}
+)
+
+type hostPort struct {
+	Port     int
+	Protocol string
+	Purpose  string
+}
+
+type hostDiskRequirement struct {
+	Path     string
+	Required resource.Quantity
+}
+
+// filesystemInfo identifies the filesystem a path is on and the space available on it.
+type filesystemInfo struct {
+	ID   uint64
+	Free uint64
+}
+
+// hostPreflight reads the state of the host, the functions are replaced in tests.
+type hostPreflight struct {
+	// root is prefixed to every path read from the host
+	root     string
+	statFS   func(path string) (filesystemInfo, error)
+	listen   func(protocol string, port int) error
+	lookPath func(file string) (string, error)
+}
+
+// RunHostPreflightChecks checks whether the host is ready to install the K3s bundled with the init package, like
+// kubeadm preflight. Checks that do not pass come with a remediation.
+func RunHostPreflightChecks() []HealthCheck {
+	h := hostPreflight{
+		root:     "/",
+		statFS:   statFS,
+		listen:   listenOnPort,
+		lookPath: exec.LookPath,
+	}
+	return h.run()
+}
+
+func (h hostPreflight) run() []HealthCheck {
+	return []HealthCheck{
+		h.checkKernelModules(),
+		h.checkCgroups(),
+		h.checkSELinux(),
+		h.checkAppArmor(),
+		h.checkDiskSpace(),
+		h.checkPorts(),
+	}
+}
+
+func newHostPreflightCheck(name string, status HealthCheckStatus, remediation string, format string, a ...any) HealthCheck {
+	check := newHealthCheck(name, status, format, a...)
+	check.Remediation = remediation
+	return check
+}
+
+func (h hostPreflight) path(path string) string {
+	return filepath.Join(h.root, path)
+}
+
+func (h hostPreflight) exists(path string) bool {
+	_, err := os.Stat(h.path(path))
+	return err == nil
+}
+
+func (h hostPreflight) readFile(path string) (string, error) {
+	b, err := os.ReadFile(h.path(path))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// checkKernelModules checks that the kernel modules K3s needs are loaded, built in or available for it to load.
+func (h hostPreflight) checkKernelModules() HealthCheck {
+	release, err := h.readFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return newHostPreflightCheck(HostPreflightKernelModules, HealthCheckWarn, "", "unable to get the release of the running kernel: %s", err)
+	}
+	builtin := h.kernelModuleNames(filepath.Join("/lib/modules", release, "modules.builtin"))
+	available := h.kernelModuleNames(filepath.Join("/lib/modules", release, "modules.dep"))
+
+	missing := []string{}
+	loadable := []string{}
+	for _, module := range k3sKernelModules {
+		switch {
+		case h.exists(filepath.Join("/sys/module", module)) || slices.Contains(builtin, module):
+		case slices.Contains(available, module):
+			loadable = append(loadable, module)
+		default:
+			missing = append(missing, module)
+		}
+	}
+
+	if len(missing) > 0 {
+		return newHostPreflightCheck(HostPreflightKernelModules, HealthCheckFail,
+			fmt.Sprintf("install the kernel modules of kernel %s (e.g. the linux-modules-extra-%s package) or boot a kernel that has them", release, release),
+			"the kernel modules %s are neither loaded nor available for kernel %s", strings.Join(missing, ", "), release)
+	}
+	if len(loadable) > 0 {
+		if _, err := h.lookPath("modprobe"); err != nil {
+			return newHostPreflightCheck(HostPreflightKernelModules, HealthCheckFail,
+				"install kmod, or load the modules before init and on every boot by adding them to /etc/modules-load.d/k3s.conf",
+				"the kernel modules %s are not loaded and K3s can not load them without modprobe", strings.Join(loadable, ", "))
+		}
+		return newHealthCheck(HostPreflightKernelModules, HealthCheckPass, "the kernel modules %s are available and loaded by K3s when it starts", strings.Join(loadable, ", "))
+	}
+	return newHealthCheck(HostPreflightKernelModules, HealthCheckPass, "the kernel modules %s are loaded", strings.Join(k3sKernelModules, ", "))
+}
+
+// kernelModuleNames returns the names of the kernel modules listed in a modules.dep or modules.builtin file.
+func (h hostPreflight) kernelModuleNames(path string) []string {
+	f, err := os.Open(h.path(path))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	names := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines start with the path of a module, e.g. kernel/net/bridge/br_netfilter.ko.zst: kernel/net/bridge/bridge.ko.zst
+		modulePath, _, _ := strings.Cut(scanner.Text(), ":")
+		name, _, _ := strings.Cut(filepath.Base(strings.TrimSpace(modulePath)), ".ko")
+		names = append(names, strings.ReplaceAll(name, "-", "_"))
+	}
+	return names
+}
+
+// checkCgroups checks that the host uses cgroup v2 with the controllers the kubelet needs.
+func (h hostPreflight) checkCgroups() HealthCheck {
+	enableControllers := "enable the cgroup controllers by adding cgroup_enable=memory cgroup_memory=1 to the kernel command line (e.g. /boot/cmdline.txt on Raspberry Pi OS) and reboot"
+
+	controllers, err := h.readFile("/sys/fs/cgroup/cgroup.controllers")
+	if err == nil {
+		missing := []string{}
+		for _, controller := range k3sCgroupControllers {
+			if !slices.Contains(strings.Fields(controllers), controller) {
+				missing = append(missing, controller)
+			}
+		}
+		if len(missing) > 0 {
+			return newHostPreflightCheck(HostPreflightCgroups, HealthCheckFail, enableControllers, "the cgroup v2 controllers %s are not enabled", strings.Join(missing, ", "))
+		}
+		return newHealthCheck(HostPreflightCgroups, HealthCheckPass, "the host uses cgroup v2 with the %s controllers", strings.Join(k3sCgroupControllers, ", "))
+	}
+
+	if !h.exists("/sys/fs/cgroup") {
+		return newHostPreflightCheck(HostPreflightCgroups, HealthCheckFail, "mount the cgroup2 filesystem at /sys/fs/cgroup", "no cgroup filesystem is mounted at /sys/fs/cgroup")
+	}
+	missing := []string{}
+	for _, controller := range k3sCgroupControllers {
+		if !h.exists(filepath.Join("/sys/fs/cgroup", controller)) {
+			missing = append(missing, controller)
+		}
+	}
+	if len(missing) > 0 {
+		return newHostPreflightCheck(HostPreflightCgroups, HealthCheckFail, enableControllers, "the cgroup v1 controllers %s are not mounted", strings.Join(missing, ", "))
+	}
+	return newHostPreflightCheck(HostPreflightCgroups, HealthCheckWarn,
+		"switch to cgroup v2 by adding systemd.unified_cgroup_hierarchy=1 to the kernel command line and reboot",
+		"the host uses cgroup v1, which Kubernetes has deprecated")
+}
+
+// checkSELinux checks that the SELinux policy of K3s is installed when SELinux is enforcing.
+func (h hostPreflight) checkSELinux() HealthCheck {
+	enforce, err := h.readFile("/sys/fs/selinux/enforce")
+	if err != nil {
+		return newHealthCheck(HostPreflightSELinux, HealthCheckPass, "SELinux is disabled")
+	}
+	if enforce != "1" {
+		return newHealthCheck(HostPreflightSELinux, HealthCheckPass, "SELinux is permissive")
+	}
+	if !h.exists(k3sSELinuxPolicy) {
+		return newHostPreflightCheck(HostPreflightSELinux, HealthCheckWarn,
+			"install the container-selinux and k3s-selinux packages and add --selinux to K3S_ARGS, or make SELinux permissive with setenforce 0",
+			"SELinux is enforcing and the k3s-selinux policy is not installed, so the containers of K3s may be denied access to their files")
+	}
+	return newHealthCheck(HostPreflightSELinux, HealthCheckPass, "SELinux is enforcing and the k3s-selinux policy is installed")
+}
+
+// checkAppArmor checks that containerd can load the AppArmor profile of the containers when AppArmor is enabled.
+func (h hostPreflight) checkAppArmor() HealthCheck {
+	enabled, err := h.readFile("/sys/module/apparmor/parameters/enabled")
+	if err != nil || enabled != "Y" {
+		return newHealthCheck(HostPreflightAppArmor, HealthCheckPass, "AppArmor is disabled")
+	}
+	if _, err := h.lookPath("apparmor_parser"); err != nil {
+		return newHostPreflightCheck(HostPreflightAppArmor, HealthCheckFail,
+			"install the apparmor package (apparmor-parser on SUSE) that provides apparmor_parser",
+			"AppArmor is enabled and apparmor_parser is not installed, so containerd can not load the profile of the containers")
+	}
+	return newHealthCheck(HostPreflightAppArmor, HealthCheckPass, "AppArmor is enabled and apparmor_parser is installed")
+}
+
+// checkDiskSpace checks that the filesystems of the paths K3s writes to have enough free space for all of them.
+func (h hostPreflight) checkDiskSpace() HealthCheck {
+	type filesystemUse struct {
+		paths    []string
+		required resource.Quantity
+		free     uint64
+	}
+	filesystems := map[uint64]*filesystemUse{}
+	ids := []uint64{}
+	for _, requirement := range k3sDiskRequirements {
+		// The path is created by the install, so measure the closest directory that exists
+		existing := requirement.Path
+		for !h.exists(existing) && existing != filepath.Dir(existing) {
+			existing = filepath.Dir(existing)
+		}
+		fs, err := h.statFS(h.path(existing))
+		if err != nil {
+			return newHostPreflightCheck(HostPreflightDiskSpace, HealthCheckWarn, "", "unable to get the free space of %s: %s", existing, err)
+		}
+		use, ok := filesystems[fs.ID]
+		if !ok {
+			use = &filesystemUse{free: fs.Free}
+			filesystems[fs.ID] = use
+			ids = append(ids, fs.ID)
+		}
+		use.paths = append(use.paths, requirement.Path)
+		use.required.Add(requirement.Required)
+	}
+
+	problems := []string{}
+	for _, id := range ids {
+		use := filesystems[id]
+		if resource.NewQuantity(int64(use.free), resource.BinarySI).Cmp(use.required) < 0 {
+			problems = append(problems, fmt.Sprintf("the filesystem of %s has %s free and needs %s", strings.Join(use.paths, ", "), resource.NewQuantity(int64(use.free), resource.BinarySI).String(), use.required.String()))
+		}
+	}
+	if len(problems) > 0 {
+		return newHostPreflightCheck(HostPreflightDiskSpace, HealthCheckFail,
+			"free up space on the filesystems or mount larger volumes at the paths, the images and volumes of the cluster are stored under /var/lib/rancher",
+			"%s", strings.Join(problems, "; "))
+	}
+	return newHealthCheck(HostPreflightDiskSpace, HealthCheckPass, "the filesystems of the paths K3s writes to have enough free space")
+}
+
+// checkPorts checks that the ports K3s listens on are not in use.
+func (h hostPreflight) checkPorts() HealthCheck {
+	inUse := []string{}
+	for _, port := range k3sPorts {
+		if err := h.listen(port.Protocol, port.Port); err != nil {
+			inUse = append(inUse, fmt.Sprintf("%d/%s (%s)", port.Port, port.Protocol, port.Purpose))
+		}
+	}
+	if len(inUse) == 0 {
+		return newHealthCheck(HostPreflightPorts, HealthCheckPass, "the ports K3s listens on are available")
+	}
+	// Running init again on a host where K3s is already installed finds the ports in use by K3s itself
+	if h.exists(k3sDataDir) {
+		return newHostPreflightCheck(HostPreflightPorts, HealthCheckWarn,
+			"if K3s is not the process listening on them, find the processes with ss -tulpn and stop them",
+			"the ports %s are in use, possibly by the K3s already installed on this host", strings.Join(inUse, ", "))
+	}
+	return newHostPreflightCheck(HostPreflightPorts, HealthCheckFail,
+		"find the processes listening on them with ss -tulpn and stop them, or uninstall the Kubernetes distribution already on this host",
+		"the ports %s are in use", strings.Join(inUse, ", "))
+}
+
+// listenOnPort returns an error when the port can not be listened on, e.g. because it is in use.
+func listenOnPort(protocol string, port int) error {
+	address := net.JoinHostPort("", strconv.Itoa(port))
+	switch protocol {
+	case "udp":
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case "tcp":
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return err
+		}
+		return listener.Close()
+	default:
+		return fmt.Errorf("unsupported protocol %s", protocol)
+	}
+}
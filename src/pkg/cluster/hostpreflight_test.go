@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readyHost returns the files of a host that passes every preflight check.
+func readyHost() map[string]string {
+	return map[string]string{
+		"/proc/sys/kernel/osrelease":                    "6.8.0-45-generic\n",
+		"/lib/modules/6.8.0-45-generic/modules.builtin": "kernel/fs/overlayfs/overlay.ko\n",
+		"/lib/modules/6.8.0-45-generic/modules.dep":     "kernel/net/bridge/br_netfilter.ko.zst: kernel/net/bridge/bridge.ko.zst\nkernel/net/netfilter/nf_conntrack.ko.zst:\n",
+		"/sys/module/nf_conntrack/refcnt":               "3\n",
+		"/sys/fs/cgroup/cgroup.controllers":             "cpuset cpu io memory hugetlb pids rdma misc\n",
+		"/var/lib/kubelet/.keep":                        "",
+	}
+}
+
+func TestHostPreflightChecks(t *testing.T) {
+	t.Parallel()
+
+	const gi = uint64(1) << 30
+	tests := []struct {
+		name        string
+		files       map[string]string
+		remove      []string
+		filesystems map[string]filesystemInfo
+		inUse       []int
+		noAppArmor  bool
+		noModprobe  bool
+		expected    map[string]HealthCheckStatus
+		remediation string
+	}{
+		{
+			name:  "ready host",
+			files: readyHost(),
+			expected: map[string]HealthCheckStatus{
+				HostPreflightKernelModules: HealthCheckPass,
+				HostPreflightCgroups:       HealthCheckPass,
+				HostPreflightSELinux:       HealthCheckPass,
+				HostPreflightAppArmor:      HealthCheckPass,
+				HostPreflightDiskSpace:     HealthCheckPass,
+				HostPreflightPorts:         HealthCheckPass,
+			},
+		},
+		{
+			name:        "missing kernel module",
+			files:       map[string]string{"/lib/modules/6.8.0-45-generic/modules.dep": "kernel/net/netfilter/nf_conntrack.ko.zst:\n"},
+			expected:    map[string]HealthCheckStatus{HostPreflightKernelModules: HealthCheckFail},
+			remediation: "install the kernel modules of kernel 6.8.0-45-generic (e.g. the linux-modules-extra-6.8.0-45-generic package) or boot a kernel that has them",
+		},
+		{
+			name:       "no modprobe",
+			noModprobe: true,
+			expected:   map[string]HealthCheckStatus{HostPreflightKernelModules: HealthCheckFail},
+		},
+		{
+			name:        "memory controller disabled",
+			files:       map[string]string{"/sys/fs/cgroup/cgroup.controllers": "cpuset cpu io pids\n"},
+			expected:    map[string]HealthCheckStatus{HostPreflightCgroups: HealthCheckFail},
+			remediation: "enable the cgroup controllers by adding cgroup_enable=memory cgroup_memory=1 to the kernel command line (e.g. /boot/cmdline.txt on Raspberry Pi OS) and reboot",
+		},
+		{
+			name:   "cgroup v1",
+			files:  map[string]string{"/sys/fs/cgroup/cpu/tasks": "", "/sys/fs/cgroup/memory/tasks": "", "/sys/fs/cgroup/pids/tasks": ""},
+			remove: []string{"/sys/fs/cgroup/cgroup.controllers"},
+			expected: map[string]HealthCheckStatus{
+				HostPreflightCgroups: HealthCheckWarn,
+			},
+		},
+		{
+			name:     "SELinux enforcing without the policy",
+			files:    map[string]string{"/sys/fs/selinux/enforce": "1"},
+			expected: map[string]HealthCheckStatus{HostPreflightSELinux: HealthCheckWarn},
+		},
+		{
+			name:     "SELinux enforcing with the policy",
+			files:    map[string]string{"/sys/fs/selinux/enforce": "1", k3sSELinuxPolicy: ""},
+			expected: map[string]HealthCheckStatus{HostPreflightSELinux: HealthCheckPass},
+		},
+		{
+			name:       "AppArmor without apparmor_parser",
+			files:      map[string]string{"/sys/module/apparmor/parameters/enabled": "Y\n"},
+			noAppArmor: true,
+			expected:   map[string]HealthCheckStatus{HostPreflightAppArmor: HealthCheckFail},
+		},
+		{
+			name:       "AppArmor disabled without apparmor_parser",
+			files:      map[string]string{"/sys/module/apparmor/parameters/enabled": "N\n"},
+			noAppArmor: true,
+			expected:   map[string]HealthCheckStatus{HostPreflightAppArmor: HealthCheckPass},
+		},
+		{
+			name:        "shared filesystem without enough space",
+			filesystems: map[string]filesystemInfo{"/": {ID: 1, Free: 11 * gi}},
+			expected:    map[string]HealthCheckStatus{HostPreflightDiskSpace: HealthCheckFail},
+		},
+		{
+			name:        "separate filesystems",
+			files:       map[string]string{"/var/lib/rancher/.keep": ""},
+			filesystems: map[string]filesystemInfo{"/": {ID: 1, Free: 2 * gi}, "/var/lib/rancher": {ID: 2, Free: 10 * gi}},
+			expected:    map[string]HealthCheckStatus{HostPreflightDiskSpace: HealthCheckPass},
+		},
+		{
+			name:        "port in use",
+			inUse:       []int{6443},
+			expected:    map[string]HealthCheckStatus{HostPreflightPorts: HealthCheckFail},
+			remediation: "find the processes listening on them with ss -tulpn and stop them, or uninstall the Kubernetes distribution already on this host",
+		},
+		{
+			name:     "port in use by the installed K3s",
+			files:    map[string]string{k3sDataDir + "/server/token": ""},
+			inUse:    []int{6443, 10250},
+			expected: map[string]HealthCheckStatus{HostPreflightPorts: HealthCheckWarn},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			files := readyHost()
+			for path, content := range tt.files {
+				files[path] = content
+			}
+			for _, path := range tt.remove {
+				delete(files, path)
+			}
+			for path, content := range files {
+				require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0o644))
+			}
+			filesystems := map[string]filesystemInfo{"/": {ID: 1, Free: 100 * gi}}
+			for path, fs := range tt.filesystems {
+				filesystems[path] = fs
+			}
+
+			h := hostPreflight{
+				root: root,
+				statFS: func(path string) (filesystemInfo, error) {
+					rel, err := filepath.Rel(root, path)
+					require.NoError(t, err)
+					// Every path is on the filesystem mounted at its closest parent
+					for p := filepath.Join("/", rel); ; p = filepath.Dir(p) {
+						if fs, ok := filesystems[p]; ok {
+							return fs, nil
+						}
+					}
+				},
+				listen: func(_ string, port int) error {
+					for _, p := range tt.inUse {
+						if p == port {
+							return errors.New("address already in use")
+						}
+					}
+					return nil
+				},
+				lookPath: func(file string) (string, error) {
+					if (file == "apparmor_parser" && tt.noAppArmor) || (file == "modprobe" && tt.noModprobe) {
+						return "", errors.New("not found")
+					}
+					return "/usr/sbin/" + file, nil
+				},
+			}
+			checks := h.run()
+			require.Len(t, checks, 6)
+			for _, check := range checks {
+				expected, ok := tt.expected[check.Name]
+				if !ok {
+					expected = HealthCheckPass
+				}
+				require.Equal(t, expected, check.Status, "%s: %s", check.Name, check.Message)
+				if check.Status == HealthCheckPass {
+					require.Empty(t, check.Remediation, check.Name)
+				} else {
+					require.NotEmpty(t, check.Remediation, check.Name)
+				}
+				if tt.remediation != "" && check.Status != HealthCheckPass {
+					require.Equal(t, tt.remediation, check.Remediation)
+				}
+			}
+			require.Equal(t, !slices.ContainsFunc(checks, func(check HealthCheck) bool { return check.Status == HealthCheckFail }), HealthChecksPassed(checks))
+		})
+	}
+}
+
+func TestKernelModuleNames(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	content := "kernel/net/bridge/br_netfilter.ko.zst: kernel/net/bridge/bridge.ko.zst\nkernel/drivers/md/dm-mod.ko:\nkernel/fs/overlayfs/overlay.ko.xz:\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "modules.dep"), []byte(content), 0o644))
+	h := hostPreflight{root: root}
+	require.Equal(t, []string{"br_netfilter", "dm_mod", "overlay"}, h.kernelModuleNames("modules.dep"))
+	require.Empty(t, h.kernelModuleNames("missing"))
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build !windows
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// statFS returns the device of the filesystem the path is on and the space available on it to unprivileged users.
+func statFS(path string) (filesystemInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return filesystemInfo{}, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return filesystemInfo{}, fmt.Errorf("unable to get the device of %s", path)
+	}
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return filesystemInfo{}, err
+	}
+	return filesystemInfo{ID: uint64(stat.Dev), Free: uint64(fs.Bavail) * uint64(fs.Bsize)}, nil
+}
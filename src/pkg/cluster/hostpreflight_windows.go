@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build windows
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import "fmt"
+
+// statFS is not supported on Windows, where K3s can not be installed.
+func statFS(path string) (filesystemInfo, error) {
+	return filesystemInfo{}, fmt.Errorf("unable to get the free space of %s on windows", path)
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImageMapPrefix is the prefix of the name of the ConfigMap in the Zarf namespace that maps the original
+	// references of the images of a package to their references by digest in the Zarf registry.
+	ImageMapPrefix = "zarf-image-map-"
+	// ImageMapDataKey is the key of the JSON object of the image references in the data of the image map ConfigMap.
+	ImageMapDataKey = "images.json"
+)
+
+// UpdateImageMap adds the references by digest in the Zarf registry of the pushed images, keyed by their original
+// references, to the image map ConfigMap of the package, creating it on the first push of the package.
+func (c *Cluster) UpdateImageMap(ctx context.Context, packageName string, images map[string]string) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	cm, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Get(ctx, ImageMapPrefix+packageName, metav1.GetOptions{})
+	exists := err == nil
+	if kerrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ImageMapPrefix + packageName,
+				Namespace: ZarfNamespaceName,
+				Labels: map[string]string{
+					ZarfManagedByLabel:   "zarf",
+					ZarfPackageInfoLabel: packageName,
+				},
+			},
+		}
+	} else if err != nil {
+		return err
+	}
+
+	imageMap, err := decodeImageMap(cm)
+	if err != nil {
+		return err
+	}
+	maps.Copy(imageMap, images)
+	b, err := json.Marshal(imageMap)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[ImageMapDataKey] = string(b)
+
+	if exists {
+		_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Update(ctx, cm, metav1.UpdateOptions{})
+	} else {
+		_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update the image map %s: %w", cm.Name, err)
+	}
+	return nil
+}
+
+// GetImageMap returns the references by digest in the Zarf registry of the images of the package, keyed by their
+// original references.
+func (c *Cluster) GetImageMap(ctx context.Context, packageName string) (map[string]string, error) {
+	cm, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Get(ctx, ImageMapPrefix+packageName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return decodeImageMap(cm)
+}
+
+// DeleteImageMap deletes the image map ConfigMap of the package, ignoring packages that do not have one.
+func (c *Cluster) DeleteImageMap(ctx context.Context, packageName string) error {
+	err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Delete(ctx, ImageMapPrefix+packageName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func decodeImageMap(cm *corev1.ConfigMap) (map[string]string, error) {
+	imageMap := map[string]string{}
+	data, ok := cm.Data[ImageMapDataKey]
+	if !ok {
+		return imageMap, nil
+	}
+	if err := json.Unmarshal([]byte(data), &imageMap); err != nil {
+		return nil, fmt.Errorf("unable to read the image map %s: %w", cm.Name, err)
+	}
+	return imageMap, nil
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestImageMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+
+	// Nothing is created for components without images
+	require.NoError(t, c.UpdateImageMap(ctx, "podinfo", nil))
+	_, err := c.GetImageMap(ctx, "podinfo")
+	require.Error(t, err)
+
+	require.NoError(t, c.UpdateImageMap(ctx, "podinfo", map[string]string{
+		"ghcr.io/stefanprodan/podinfo:6.4.0": "127.0.0.1:31999/stefanprodan/podinfo@sha256:57a654ace69ec02ba8973093b6a786faa15640575fbf0dbb603db55aca2ccec8",
+	}))
+	require.NoError(t, c.UpdateImageMap(ctx, "podinfo", map[string]string{
+		"ghcr.io/stefanprodan/podinfo:6.4.0": "127.0.0.1:31999/stefanprodan/podinfo@sha256:4aa3b934d79b4d6b09e4ac9ed3f2e2df4bf2a1a6d2ff5ac7f7fd9c2ecf6c9b1a",
+		"docker.io/library/redis:7.2-alpine": "127.0.0.1:31999/library/redis@sha256:c1e88455c85225310bbea54816e9c3f4b5295815e6dbf80c34d40afc6df28275",
+	}))
+
+	cm, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Get(ctx, "zarf-image-map-podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "podinfo", cm.Labels[ZarfPackageInfoLabel])
+	require.JSONEq(t, `{
+		"docker.io/library/redis:7.2-alpine": "127.0.0.1:31999/library/redis@sha256:c1e88455c85225310bbea54816e9c3f4b5295815e6dbf80c34d40afc6df28275",
+		"ghcr.io/stefanprodan/podinfo:6.4.0": "127.0.0.1:31999/stefanprodan/podinfo@sha256:4aa3b934d79b4d6b09e4ac9ed3f2e2df4bf2a1a6d2ff5ac7f7fd9c2ecf6c9b1a"
+	}`, cm.Data[ImageMapDataKey])
+
+	imageMap, err := c.GetImageMap(ctx, "podinfo")
+	require.NoError(t, err)
+	require.Len(t, imageMap, 2)
+
+	require.NoError(t, c.DeleteImageMap(ctx, "podinfo"))
+	require.NoError(t, c.DeleteImageMap(ctx, "podinfo"))
+	_, err = c.GetImageMap(ctx, "podinfo")
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// originalImageAnnotationPrefix prefixes the annotations the Zarf agent records the original image of each container
+// of a pod in before rewriting it to the Zarf registry.
+const originalImageAnnotationPrefix = "zarf.dev/original-image-"
+
+// ListRunningImages returns the unique images of the containers of the running pods in the namespaces, or in every
+// namespace when none are given, pinned to the digests the containers run. Images the Zarf agent rewrote to the Zarf
+// registry are listed by their original reference.
+func (c *Cluster) ListRunningImages(ctx context.Context, namespaces []string) ([]string, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	images := []string{}
+	for _, namespace := range namespaces {
+		podList, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range podList.Items {
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			for _, image := range runningPodImages(pod) {
+				if !slices.Contains(images, image) {
+					images = append(images, image)
+				}
+			}
+		}
+	}
+	slices.Sort(images)
+	return images, nil
+}
+
+// runningPodImages returns the images of the containers and init containers of the pod, pinned to the digests of
+// their statuses.
+func runningPodImages(pod corev1.Pod) []string {
+	digests := map[string]string{}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		digests[status.Name] = imageIDDigest(status.ImageID)
+	}
+	images := []string{}
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		image := container.Image
+		if original, ok := pod.Annotations[originalImageAnnotationPrefix+container.Name]; ok {
+			image = original
+		}
+		ref, err := transform.ParseImageRef(image)
+		if err != nil {
+			message.Debugf("Skipping the image %s of container %s of pod %s/%s: %s", image, container.Name, pod.Namespace, pod.Name, err.Error())
+			continue
+		}
+		reference := ref.Reference
+		if ref.Digest == "" && digests[container.Name] != "" {
+			reference = fmt.Sprintf("%s@%s", reference, digests[container.Name])
+		}
+		images = append(images, reference)
+	}
+	return images
+}
+
+// imageIDDigest returns the digest of the image ID of a container status, e.g. sha256:abc for
+// docker.io/library/nginx@sha256:abc. Runtimes that report the ID of the image config instead have no digest.
+func imageIDDigest(imageID string) string {
+	_, digest, ok := strings.Cut(imageID, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") {
+		return ""
+	}
+	return digest
+}
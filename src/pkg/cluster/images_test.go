@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListRunningImages(t *testing.T) {
+	t.Parallel()
+
+	const (
+		nginxDigest = "sha256:a484819eb60211f5299034ac80f6a681b06f89e65866ce91f356ed7c72af059c"
+		redisDigest = "sha256:f9a1d9d05de7a4d53e2b3ff5d7e5c9a6e1d2a7f3b5e0c8d4a9b6f7e1c2d3a4b5"
+	)
+	pod := func(namespace, name string, phase corev1.PodPhase, annotations map[string]string, containers ...corev1.Container) *corev1.Pod {
+		statuses := []corev1.ContainerStatus{}
+		for _, container := range containers {
+			imageID := ""
+			switch container.Name {
+			case "nginx":
+				imageID = "docker.io/library/nginx@" + nginxDigest
+			case "redis":
+				imageID = "docker-pullable://redis@" + redisDigest
+			case "config-id":
+				imageID = "sha256:5f2b8c1d4e7a9b3c6d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c"
+			}
+			statuses = append(statuses, corev1.ContainerStatus{Name: container.Name, ImageID: imageID})
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: containers},
+			Status:     corev1.PodStatus{Phase: phase, ContainerStatuses: statuses},
+		}
+	}
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(
+			pod("web", "nginx-a", corev1.PodRunning, nil, corev1.Container{Name: "nginx", Image: "nginx:1.25"}),
+			pod("web", "nginx-b", corev1.PodRunning, nil, corev1.Container{Name: "nginx", Image: "nginx:1.25"}),
+			// The Zarf agent rewrote the image to the Zarf registry
+			pod("cache", "redis", corev1.PodRunning,
+				map[string]string{"zarf.dev/original-image-redis": "redis:7.2-alpine"},
+				corev1.Container{Name: "redis", Image: "127.0.0.1:31999/library/redis:7.2-alpine-zarf-1234567890"},
+				corev1.Container{Name: "config-id", Image: "ghcr.io/stefanprodan/podinfo:6.4.0"},
+			),
+			pod("cache", "pending", corev1.PodPending, nil, corev1.Container{Name: "pending", Image: "busybox:1.36"}),
+			pod("other", "pinned", corev1.PodRunning, nil, corev1.Container{Name: "nginx", Image: "nginx@" + nginxDigest}),
+		),
+	}
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		expected   []string
+	}{
+		{
+			name:       "namespaces",
+			namespaces: []string{"web", "cache"},
+			expected: []string{
+				"docker.io/library/nginx:1.25@" + nginxDigest,
+				"docker.io/library/redis:7.2-alpine@" + redisDigest,
+				"ghcr.io/stefanprodan/podinfo:6.4.0",
+			},
+		},
+		{
+			name: "all namespaces",
+			expected: []string{
+				"docker.io/library/nginx:1.25@" + nginxDigest,
+				"docker.io/library/nginx@" + nginxDigest,
+				"docker.io/library/redis:7.2-alpine@" + redisDigest,
+				"ghcr.io/stefanprodan/podinfo:6.4.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			images, err := c.ListRunningImages(context.Background(), tt.namespaces)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, images)
+		})
+	}
+}
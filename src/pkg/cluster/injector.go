@@ -106,6 +106,7 @@ func (c *Cluster) StartInjection(ctx context.Context, tmpDir, imagesDir string,
 	if err != nil {
 		return err
 	}
+	c.ClearServiceCache()
 	// TODO: Remove use of passing data through global variables.
 	config.ZarfSeedPort = fmt.Sprintf("%d", svc.Spec.Ports[0].NodePort)
 
@@ -136,6 +137,7 @@ func (c *Cluster) StopInjection(ctx context.Context) error {
 	if err != nil && !kerrors.IsNotFound(err) {
 		return err
 	}
+	c.ClearServiceCache()
 	err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Delete(ctx, "rust-binary", metav1.DeleteOptions{})
 	if err != nil && !kerrors.IsNotFound(err) {
 		return err
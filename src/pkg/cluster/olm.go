@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// The Operator Lifecycle Manager (OLM) resources Zarf manages operators with.
+var (
+	OperatorGroupGVR         = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1", Resource: "operatorgroups"}
+	CatalogSourceGVR         = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "catalogsources"}
+	SubscriptionGVR          = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "subscriptions"}
+	ClusterServiceVersionGVR = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "clusterserviceversions"}
+)
+
+// OperatorGroups returns the names of the operator groups in a namespace, OLM does not install operators into namespaces
+// with more than one.
+func (c *Cluster) OperatorGroups(ctx context.Context, namespace string) ([]string, error) {
+	client, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := client.Resource(OperatorGroupGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the operator groups in %s, is OLM installed?: %w", namespace, err)
+	}
+	names := []string{}
+	for _, group := range groups.Items {
+		names = append(names, group.GetName())
+	}
+	return names, nil
+}
+
+// WaitForOperator waits until the catalog of the operator is serving, its subscription has installed a ClusterServiceVersion
+// and that ClusterServiceVersion has succeeded.
+func (c *Cluster) WaitForOperator(ctx context.Context, operator v1alpha1.ZarfOperator, timeout time.Duration) error {
+	client, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return err
+	}
+	return waitForOperator(ctx, client, operator, timeout, time.Second)
+}
+
+func waitForOperator(ctx context.Context, client dynamic.Interface, operator v1alpha1.ZarfOperator, timeout, delay time.Duration) error {
+	spinner := message.NewProgressSpinner("Waiting for operator %s to be installed", operator.Name)
+	defer spinner.Stop()
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	err := retry.Do(func() error {
+		lastErr = checkOperator(waitCtx, client, operator, spinner)
+		return lastErr
+	}, retry.Context(waitCtx), retry.Attempts(0), retry.DelayType(retry.FixedDelay), retry.Delay(delay), retry.LastErrorOnly(true))
+	if err != nil {
+		if waitCtx.Err() != nil && lastErr != nil {
+			return fmt.Errorf("timed out waiting for operator %s to be installed: %w", operator.Name, lastErr)
+		}
+		return err
+	}
+
+	spinner.Successf("Operator %s is installed", operator.Name)
+	return nil
+}
+
+// checkOperator returns an error describing why the operator is not installed yet, unrecoverable when it failed to install.
+func checkOperator(ctx context.Context, client dynamic.Interface, operator v1alpha1.ZarfOperator, spinner *message.Spinner) error {
+	catalog, err := client.Resource(CatalogSourceGVR).Namespace(operator.Namespace).Get(ctx, operator.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	state, _, _ := unstructured.NestedString(catalog.Object, "status", "connectionState", "lastObservedState")
+	if state != "READY" {
+		spinner.Updatef("Waiting for the catalog of operator %s to be ready", operator.Name)
+		return fmt.Errorf("catalog source %s/%s is %q instead of READY", operator.Namespace, operator.Name, state)
+	}
+
+	subscription, err := client.Resource(SubscriptionGVR).Namespace(operator.Namespace).Get(ctx, operator.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	installedCSV, _, _ := unstructured.NestedString(subscription.Object, "status", "installedCSV")
+	if installedCSV == "" {
+		spinner.Updatef("Waiting for the subscription of operator %s to install a version", operator.Name)
+		return fmt.Errorf("subscription %s/%s has not installed a version%s", operator.Namespace, operator.Name, failingConditions(subscription))
+	}
+
+	csv, err := client.Resource(ClusterServiceVersionGVR).Namespace(operator.Namespace).Get(ctx, installedCSV, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return fmt.Errorf("cluster service version %s/%s does not exist yet", operator.Namespace, installedCSV)
+	}
+	if err != nil {
+		return err
+	}
+	phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return nil
+	case "Failed":
+		reason, _, _ := unstructured.NestedString(csv.Object, "status", "message")
+		return retry.Unrecoverable(fmt.Errorf("cluster service version %s/%s failed: %s", operator.Namespace, installedCSV, reason))
+	default:
+		spinner.Updatef("Waiting for operator %s version %s to succeed", operator.Name, installedCSV)
+		return fmt.Errorf("cluster service version %s/%s is %q instead of Succeeded", operator.Namespace, installedCSV, phase)
+	}
+}
+
+// failingConditions returns the message of the conditions of an OLM resource that are blocking it, prefixed for an error.
+func failingConditions(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	msg := ""
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		switch condition["type"] {
+		case "CatalogSourcesUnhealthy", "ResolutionFailed", "InstallPlanFailed", "BundleUnpackFailed":
+			msg += fmt.Sprintf(": %s %v", condition["type"], condition["message"])
+		}
+	}
+	return msg
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func olmTestObject(apiVersion, kind, name string, status map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "operators",
+		},
+		"status": status,
+	}}
+}
+
+func TestWaitForOperator(t *testing.T) {
+	t.Parallel()
+
+	operator := v1alpha1.ZarfOperator{Name: "etcd", Namespace: "operators", CatalogImage: "quay.io/operatorhubio/catalog:latest"}
+	readyCatalog := olmTestObject(CatalogSourceGVR.GroupVersion().String(), "CatalogSource", "etcd", map[string]any{
+		"connectionState": map[string]any{"lastObservedState": "READY"},
+	})
+	installedSubscription := olmTestObject(SubscriptionGVR.GroupVersion().String(), "Subscription", "etcd", map[string]any{
+		"installedCSV": "etcdoperator.v0.9.4",
+	})
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		timeout     time.Duration
+		expectedErr string
+	}{
+		{
+			name: "installed",
+			objects: []runtime.Object{
+				readyCatalog,
+				installedSubscription,
+				olmTestObject(ClusterServiceVersionGVR.GroupVersion().String(), "ClusterServiceVersion", "etcdoperator.v0.9.4", map[string]any{
+					"phase": "Succeeded",
+				}),
+			},
+			timeout: 5 * time.Second,
+		},
+		{
+			name: "failed",
+			objects: []runtime.Object{
+				readyCatalog,
+				installedSubscription,
+				olmTestObject(ClusterServiceVersionGVR.GroupVersion().String(), "ClusterServiceVersion", "etcdoperator.v0.9.4", map[string]any{
+					"phase":   "Failed",
+					"message": "install strategy failed",
+				}),
+			},
+			timeout:     5 * time.Second,
+			expectedErr: "cluster service version operators/etcdoperator.v0.9.4 failed: install strategy failed",
+		},
+		{
+			name: "unresolvable",
+			objects: []runtime.Object{
+				readyCatalog,
+				olmTestObject(SubscriptionGVR.GroupVersion().String(), "Subscription", "etcd", map[string]any{
+					"conditions": []any{
+						map[string]any{"type": "ResolutionFailed", "status": "True", "message": "no operators found in channel alpha"},
+						map[string]any{"type": "CatalogSourcesUnhealthy", "status": "False", "message": "all available catalogsources are healthy"},
+					},
+				}),
+			},
+			timeout:     100 * time.Millisecond,
+			expectedErr: "timed out waiting for operator etcd to be installed: subscription operators/etcd has not installed a version: ResolutionFailed no operators found in channel alpha",
+		},
+		{
+			name: "catalog not ready",
+			objects: []runtime.Object{
+				olmTestObject(CatalogSourceGVR.GroupVersion().String(), "CatalogSource", "etcd", map[string]any{
+					"connectionState": map[string]any{"lastObservedState": "TRANSIENT_FAILURE"},
+				}),
+			},
+			timeout:     100 * time.Millisecond,
+			expectedErr: "timed out waiting for operator etcd to be installed: catalog source operators/etcd is \"TRANSIENT_FAILURE\" instead of READY",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := fake.NewSimpleDynamicClient(runtime.NewScheme(), tt.objects...)
+			err := waitForOperator(context.Background(), client, operator, tt.timeout, 10*time.Millisecond)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
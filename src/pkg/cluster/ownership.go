@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// PackageOwnerAnnotation records the name of the package that created a namespace.
+const PackageOwnerAnnotation = "zarf.dev/package"
+
+// unownedNamespaces are never owned by a package, as every package may deploy to them.
+var unownedNamespaces = []string{"default", "kube-node-lease", "kube-public", "kube-system", ZarfNamespaceName}
+
+// OwnershipConflict is a Helm release or namespace that a package would take over from another deployed package.
+type OwnershipConflict struct {
+	// The kind of resource, a helm release or a namespace.
+	Kind string
+	// The name of the resource, namespace/name for Helm releases.
+	Name string
+	// The name of the deployed package that owns the resource.
+	Owner string
+}
+
+func (oc OwnershipConflict) String() string {
+	return fmt.Sprintf("%s %s is owned by the package %s", oc.Kind, oc.Name, oc.Owner)
+}
+
+// SetNamespaceOwner records the package as the owner of a namespace it is creating, unless everyone may deploy to it.
+func SetNamespaceOwner(annotations map[string]string, namespace, packageName string) map[string]string {
+	if slices.Contains(unownedNamespaces, namespace) || packageName == "" {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[PackageOwnerAnnotation] == "" {
+		annotations[PackageOwnerAnnotation] = packageName
+	}
+	return annotations
+}
+
+// FindOwnershipConflicts returns the Helm releases and namespaces the package would deploy to that are owned by other
+// deployed packages. Helm releases are owned by the package whose deployment record lists them, namespaces by the
+// package recorded in their annotation, as long as that package is still deployed.
+func (c *Cluster) FindOwnershipConflicts(ctx context.Context, packageName string, releases []types.InstalledChart, namespaces []string) ([]OwnershipConflict, error) {
+	deployedPackages, err := c.GetDeployedZarfPackages(ctx)
+	if deployedPackages == nil {
+		return nil, err
+	}
+	if err != nil {
+		// Records that can not be read are skipped rather than failing every deployment
+		message.Debugf("Unable to read the records of some deployed packages: %s", err.Error())
+	}
+
+	conflicts := []OwnershipConflict{}
+	deployed := map[string]bool{}
+	for _, deployedPackage := range deployedPackages {
+		deployed[deployedPackage.Name] = true
+		if deployedPackage.Name == packageName {
+			continue
+		}
+		for _, component := range deployedPackage.DeployedComponents {
+			for _, chart := range component.InstalledCharts {
+				if !slices.Contains(releases, chart) {
+					continue
+				}
+				conflicts = append(conflicts, OwnershipConflict{
+					Kind:  "helm release",
+					Name:  chart.Namespace + "/" + chart.ChartName,
+					Owner: deployedPackage.Name,
+				})
+			}
+		}
+	}
+
+	for _, name := range namespaces {
+		if slices.Contains(unownedNamespaces, name) {
+			continue
+		}
+		namespace, err := c.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		owner := namespace.Annotations[PackageOwnerAnnotation]
+		if owner == "" || owner == packageName || !deployed[owner] {
+			continue
+		}
+		conflicts = append(conflicts, OwnershipConflict{Kind: "namespace", Name: name, Owner: owner})
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind < conflicts[j].Kind
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts, nil
+}
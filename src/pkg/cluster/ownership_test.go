@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestSetNamespaceOwner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		namespace   string
+		expected    map[string]string
+	}{
+		{
+			name:      "new namespace",
+			namespace: "podinfo",
+			expected:  map[string]string{PackageOwnerAnnotation: "podinfo-pkg"},
+		},
+		{
+			name:        "owned namespace",
+			annotations: map[string]string{PackageOwnerAnnotation: "other", "foo": "bar"},
+			namespace:   "podinfo",
+			expected:    map[string]string{PackageOwnerAnnotation: "other", "foo": "bar"},
+		},
+		{
+			name:      "unowned namespace",
+			namespace: "default",
+			expected:  nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, SetNamespaceOwner(tt.annotations, tt.namespace, "podinfo-pkg"))
+		})
+	}
+}
+
+func TestFindOwnershipConflicts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+
+	packages := []types.DeployedPackage{
+		{
+			Name: "podinfo",
+			DeployedComponents: []types.DeployedComponent{
+				{Name: "podinfo", InstalledCharts: []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}}},
+			},
+		},
+		{
+			Name: "other",
+			DeployedComponents: []types.DeployedComponent{
+				{Name: "other", InstalledCharts: []types.InstalledChart{{Namespace: "shared", ChartName: "app"}}},
+			},
+		},
+	}
+	for _, p := range packages {
+		b, err := json.Marshal(p)
+		require.NoError(t, err)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.ZarfPackagePrefix + p.Name,
+				Namespace: ZarfNamespaceName,
+				Labels:    map[string]string{ZarfPackageInfoLabel: p.Name},
+			},
+			Data: map[string][]byte{"data": b},
+		}
+		_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Create(ctx, secret, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	owners := map[string]string{
+		"shared":  "other",
+		"podinfo": "podinfo",
+		"removed": "removed-package",
+		"default": "other",
+	}
+	for name, owner := range owners {
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{PackageOwnerAnnotation: owner},
+			},
+		}
+		_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name        string
+		packageName string
+		releases    []types.InstalledChart
+		namespaces  []string
+		expected    []OwnershipConflict
+	}{
+		{
+			name:        "redeploy of the owner",
+			packageName: "podinfo",
+			releases:    []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}},
+			namespaces:  []string{"podinfo"},
+			expected:    []OwnershipConflict{},
+		},
+		{
+			name:        "owned by another package",
+			packageName: "podinfo",
+			releases:    []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}, {Namespace: "shared", ChartName: "app"}},
+			namespaces:  []string{"podinfo", "shared"},
+			expected: []OwnershipConflict{
+				{Kind: "helm release", Name: "shared/app", Owner: "other"},
+				{Kind: "namespace", Name: "shared", Owner: "other"},
+			},
+		},
+		{
+			name:        "same release name in another namespace",
+			packageName: "new",
+			releases:    []types.InstalledChart{{Namespace: "new", ChartName: "podinfo"}},
+			namespaces:  []string{"new"},
+			expected:    []OwnershipConflict{},
+		},
+		{
+			name:        "owner removed and unowned namespaces",
+			packageName: "new",
+			namespaces:  []string{"removed", "default", "missing"},
+			expected:    []OwnershipConflict{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conflicts, err := c.FindOwnershipConflicts(ctx, tt.packageName, tt.releases, tt.namespaces)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, conflicts)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HasPackageCache returns whether the optional in-cluster package cache has been deployed.
+func (c *Cluster) HasPackageCache(ctx context.Context) (bool, error) {
+	_, err := c.Clientset.CoreV1().Services(ZarfNamespaceName).Get(ctx, ZarfPackageCacheName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ConnectToPackageCache opens a tunnel to the in-cluster package cache.
+func (c *Cluster) ConnectToPackageCache(ctx context.Context) (*Tunnel, error) {
+	tunnel, err := c.NewTunnel(ZarfNamespaceName, SvcResource, ZarfPackageCacheName, "", 0, ZarfPackageCachePort)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tunnel.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return tunnel, nil
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestHasPackageCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+
+	ok, err := c.HasPackageCache(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ZarfPackageCacheName,
+			Namespace: ZarfNamespaceName,
+		},
+	}
+	_, err = c.Clientset.CoreV1().Services(ZarfNamespaceName).Create(ctx, svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	ok, err = c.HasPackageCache(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
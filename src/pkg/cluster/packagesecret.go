@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// Keys of the deployed package in the data of its secret.
+const (
+	deployedPackageDataKey           = "data"
+	deployedPackageCompressedDataKey = "data.gz"
+)
+
+const (
+	// deployedPackageCompressThreshold is the size above which deployed packages are stored compressed, smaller ones
+	// are stored as is so they can still be read by earlier versions of Zarf
+	deployedPackageCompressThreshold = 256 * 1024
+	// maxSecretSize is the most data Kubernetes stores in a secret
+	maxSecretSize = 1024 * 1024
+	// deployedPackageWarnSize is the stored size above which Zarf warns that a deployed package is nearing maxSecretSize
+	deployedPackageWarnSize = maxSecretSize * 3 / 4
+)
+
+// DeployedPackageSecretData returns the data of the secret that records the deployed package, compressing it when it
+// is large and returning an error when it can not be stored in a secret.
+func DeployedPackageSecretData(deployedPackage *types.DeployedPackage) (map[string][]byte, error) {
+	data, err := encodeDeployedPackage(deployedPackage)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDeployedPackageSize(deployedPackage.Name, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// encodeDeployedPackage returns the secret data that records the deployed package, compressing it when it is large.
+func encodeDeployedPackage(deployedPackage *types.DeployedPackage) (map[string][]byte, error) {
+	b, err := json.Marshal(deployedPackage)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) <= deployedPackageCompressThreshold {
+		return map[string][]byte{deployedPackageDataKey: b}, nil
+	}
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gz.Write(b); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	message.Debugf("Compressed the deployed package %s from %d to %d bytes", deployedPackage.Name, len(b), buf.Len())
+	return map[string][]byte{deployedPackageCompressedDataKey: buf.Bytes()}, nil
+}
+
+// decodeDeployedPackage returns the deployed package recorded in the data of its secret.
+func decodeDeployedPackage(data map[string][]byte) (*types.DeployedPackage, error) {
+	b, ok := data[deployedPackageDataKey]
+	if compressed, isCompressed := data[deployedPackageCompressedDataKey]; isCompressed {
+		gz, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		b, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, err
+		}
+	} else if !ok {
+		return nil, fmt.Errorf("the secret does not contain a %q or %q key", deployedPackageDataKey, deployedPackageCompressedDataKey)
+	}
+	deployedPackage := &types.DeployedPackage{}
+	if err := json.Unmarshal(b, deployedPackage); err != nil {
+		return nil, err
+	}
+	return deployedPackage, nil
+}
+
+// checkDeployedPackageSize returns an error when the secret data of a deployed package can not be stored, and warns
+// when it is nearing that limit.
+func checkDeployedPackageSize(name string, data map[string][]byte) error {
+	size := 0
+	for k, v := range data {
+		size += len(k) + len(v)
+	}
+	if size > maxSecretSize {
+		return fmt.Errorf("the deployed package %s is %d bytes after compression, which exceeds the %d byte limit of Kubernetes secrets", name, size, maxSecretSize)
+	}
+	if size > deployedPackageWarnSize {
+		message.Warnf("The record of the deployed package %s is %d bytes, nearing the %d byte limit of Kubernetes secrets. Consider splitting the package into smaller packages.", name, size, maxSecretSize)
+	}
+	return nil
+}
+
+// CompactDeployedPackages rewrites the secrets of deployed packages recorded by earlier versions of Zarf that are above
+// the size at which they are now compressed.
+func (c *Cluster) CompactDeployedPackages(ctx context.Context) error {
+	secrets, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).List(ctx, metav1.ListOptions{LabelSelector: ZarfPackageInfoLabel})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if !strings.HasPrefix(secret.Name, config.ZarfPackagePrefix) || len(secret.Data[deployedPackageDataKey]) <= deployedPackageCompressThreshold {
+			continue
+		}
+		if err := c.compactDeployedPackage(ctx, secret); err != nil {
+			return fmt.Errorf("unable to compact the secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// compactDeployedPackage stores the deployed package of a secret compressed.
+func (c *Cluster) compactDeployedPackage(ctx context.Context, secret corev1.Secret) error {
+	deployedPackage, err := decodeDeployedPackage(secret.Data)
+	if err != nil {
+		return err
+	}
+	data, err := encodeDeployedPackage(deployedPackage)
+	if err != nil {
+		return err
+	}
+	before := len(secret.Data[deployedPackageDataKey])
+	secret.Data = data
+	if _, err := c.Clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, &secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	message.Debugf("Compacted the secret %s/%s from %d to %d bytes", secret.Namespace, secret.Name, before, len(data[deployedPackageCompressedDataKey]))
+	return nil
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func deployedPackageWithDescription(description string) *types.DeployedPackage {
+	return &types.DeployedPackage{
+		Name: "large",
+		Data: v1alpha1.ZarfPackage{
+			Metadata: v1alpha1.ZarfMetadata{
+				Name:        "large",
+				Description: description,
+			},
+		},
+		Generation: 1,
+	}
+}
+
+func TestDeployedPackageSecretData(t *testing.T) {
+	t.Parallel()
+
+	random := make([]byte, maxSecretSize)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		description   string
+		expectedKey   string
+		expectedError string
+	}{
+		{
+			name:        "small package",
+			description: "small",
+			expectedKey: deployedPackageDataKey,
+		},
+		{
+			name:        "large package",
+			description: strings.Repeat("large", maxSecretSize),
+			expectedKey: deployedPackageCompressedDataKey,
+		},
+		{
+			name:          "too large after compression",
+			description:   base64.StdEncoding.EncodeToString(random),
+			expectedError: "exceeds the 1048576 byte limit of Kubernetes secrets",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deployedPackage := deployedPackageWithDescription(tt.description)
+			data, err := DeployedPackageSecretData(deployedPackage)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, data, 1)
+			require.Contains(t, data, tt.expectedKey)
+
+			decoded, err := decodeDeployedPackage(data)
+			require.NoError(t, err)
+			require.Equal(t, deployedPackage, decoded)
+		})
+	}
+}
+
+func TestCompactDeployedPackages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	large := deployedPackageWithDescription(strings.Repeat("large", maxSecretSize/8))
+	largeData, err := json.Marshal(large)
+	require.NoError(t, err)
+	small := deployedPackageWithDescription("small")
+	small.Name = "small"
+	smallData, err := json.Marshal(small)
+	require.NoError(t, err)
+	secret := func(name string, data []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.ZarfPackagePrefix + name,
+				Namespace: ZarfNamespaceName,
+				Labels:    map[string]string{ZarfPackageInfoLabel: name},
+			},
+			Data: map[string][]byte{deployedPackageDataKey: data},
+		}
+	}
+	c := &Cluster{Clientset: fake.NewSimpleClientset(secret("large", largeData), secret("small", smallData))}
+
+	err = c.CompactDeployedPackages(ctx)
+	require.NoError(t, err)
+
+	largeSecret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, config.ZarfPackagePrefix+"large", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, largeSecret.Data, deployedPackageDataKey)
+	require.Less(t, len(largeSecret.Data[deployedPackageCompressedDataKey]), len(largeData))
+	smallSecret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, config.ZarfPackagePrefix+"small", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, smallData, smallSecret.Data[deployedPackageDataKey])
+
+	deployedPackages, err := c.GetDeployedZarfPackages(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.DeployedPackage{*large, *small}, deployedPackages)
+}
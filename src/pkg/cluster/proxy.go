@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+var (
+	// jumpProxies are the local proxies of the SSH jump hosts connected to by this process, keyed by jump host.
+	jumpProxies   = map[string]*url.URL{}
+	jumpProxiesMu sync.Mutex
+)
+
+// KubeProxyFunc returns the proxy function to route API server traffic through, nil is returned when proxy is empty.
+// The proxy is an http://, https:// or socks5:// proxy URL, or an ssh://[user@]host[:port] jump host. Traffic through a
+// jump host is sent to a proxy on localhost that dials the API server over SSH, so that port forwarding tunnels work
+// the same as requests.
+func KubeProxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kube proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return http.ProxyURL(u), nil
+	case "ssh":
+		local, err := jumpProxy(u)
+		if err != nil {
+			return nil, err
+		}
+		return http.ProxyURL(local), nil
+	default:
+		return nil, fmt.Errorf("invalid kube proxy %s, the scheme must be one of http, https, socks5 or ssh", u.Redacted())
+	}
+}
+
+// jumpProxy returns the URL of a local proxy that connects through an SSH jump host, starting it if needed.
+func jumpProxy(u *url.URL) (*url.URL, error) {
+	jumpProxiesMu.Lock()
+	defer jumpProxiesMu.Unlock()
+
+	if local, ok := jumpProxies[u.String()]; ok {
+		return local, nil
+	}
+	client, err := dialJumpHost(u)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	dial := func(_ context.Context, network, address string) (net.Conn, error) {
+		return client.Dial(network, address)
+	}
+	server := &http.Server{
+		Handler:           newConnectProxy(dial),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		// The proxy is used until the process exits
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			message.Debugf("The proxy to the jump host %s stopped: %s", u.Host, err)
+		}
+	}()
+	local := &url.URL{Scheme: "http", Host: listener.Addr().String()}
+	jumpProxies[u.String()] = local
+	return local, nil
+}
+
+// dialJumpHost connects to an SSH jump host, authenticating with the password in the URL, the key at the identity
+// query parameter, the SSH agent and the default keys in ~/.ssh. Host keys are verified against ~/.ssh/known_hosts
+// unless --insecure is set.
+func dialJumpHost(u *url.URL) (*ssh.Client, error) {
+	username := u.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		username = current.Username
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	auth := []ssh.AuthMethod{}
+	if password, ok := u.User.Password(); ok {
+		auth = append(auth, ssh.Password(password))
+	}
+	keyPaths := []string{}
+	if identity := u.Query().Get("identity"); identity != "" {
+		keyPaths = append(keyPaths, identity)
+	}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		keyPaths = append(keyPaths, filepath.Join(home, ".ssh", name))
+	}
+	signers := []ssh.Signer{}
+	for _, keyPath := range keyPaths {
+		b, err := os.ReadFile(keyPath)
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			message.Debugf("Skipping the SSH key %s: %s", keyPath, err)
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		auth = append(auth, ssh.PublicKeys(signers...))
+	}
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	hostKeyCallback := ssh.InsecureIgnoreHostKey() //nolint:gosec // Host keys are only ignored with --insecure
+	if !config.CommonOptions.Insecure {
+		hostKeyCallback, err = knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the known hosts to verify the jump host %s: %w", u.Host, err)
+		}
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "22")
+	}
+	client, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         DefaultTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the jump host %s: %w", u.Host, err)
+	}
+	return client, nil
+}
+
+// connectProxy is an HTTP proxy that dials its upstream connections with a custom dial function.
+type connectProxy struct {
+	dial    func(ctx context.Context, network, address string) (net.Conn, error)
+	forward *httputil.ReverseProxy
+}
+
+func newConnectProxy(dial func(ctx context.Context, network, address string) (net.Conn, error)) *connectProxy {
+	return &connectProxy{
+		dial: dial,
+		forward: &httputil.ReverseProxy{
+			// Proxied requests already hold the absolute URL of the upstream
+			Rewrite:   func(*httputil.ProxyRequest) {},
+			Transport: &http.Transport{DialContext: dial},
+		},
+	}
+}
+
+// ServeHTTP tunnels CONNECT requests and forwards plain HTTP requests to their upstream.
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		p.forward.ServeHTTP(w, r)
+		return
+	}
+	upstream, err := p.dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "the connection can not be tunneled", http.StatusInternalServerError)
+		return
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		upstream.Close()
+		conn.Close()
+		return
+	}
+	go func() {
+		defer upstream.Close()
+		defer conn.Close()
+		// The buffered reader holds anything the client sent after the CONNECT request
+		_, _ = io.Copy(upstream, buf)
+	}()
+	go func() {
+		defer upstream.Close()
+		defer conn.Close()
+		_, _ = io.Copy(conn, upstream)
+	}()
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
+	"github.com/chrismellard/docker-credential-acr-env/pkg/credhelper"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// RegistryCredentialProvider issues short-lived credentials for a cloud registry.
+type RegistryCredentialProvider interface {
+	// Credentials returns the username and password to authenticate to the registry with.
+	Credentials(ctx context.Context, registry string) (string, string, error)
+	// Lifetime returns how long the credentials are valid for once issued.
+	Lifetime() time.Duration
+}
+
+// keychainCredentialProvider issues credentials from a keychain, such as a cloud registry credential helper.
+type keychainCredentialProvider struct {
+	keychain authn.Keychain
+	lifetime time.Duration
+}
+
+// Credentials returns the username and password the keychain resolves for the registry.
+func (p keychainCredentialProvider) Credentials(ctx context.Context, registry string) (string, string, error) {
+	reg, err := name.NewRegistry(registry)
+	if err != nil {
+		return "", "", err
+	}
+	authenticator, err := authn.Resolve(ctx, p.keychain, reg)
+	if err != nil {
+		return "", "", err
+	}
+	cfg, err := authn.Authorization(ctx, authenticator)
+	if err != nil {
+		return "", "", err
+	}
+	if cfg.Username == "" && cfg.Password == "" {
+		return "", "", fmt.Errorf("no credentials were issued for %s", registry)
+	}
+	return cfg.Username, cfg.Password, nil
+}
+
+// Lifetime returns how long the credentials are valid for once issued.
+func (p keychainCredentialProvider) Lifetime() time.Duration {
+	return p.lifetime
+}
+
+// RegistryCredentialProviders are the cloud registry credential providers by name, their credentials are issued to
+// the identity of the cluster workload or machine they are requested from (e.g. EKS pod identity or GKE workload identity).
+var RegistryCredentialProviders = map[string]RegistryCredentialProvider{
+	// ECR authorization tokens are valid for 12 hours
+	"ecr": keychainCredentialProvider{authn.NewKeychainFromHelper(ecr.NewECRHelper(ecr.WithLogger(io.Discard))), 12 * time.Hour},
+	// ACR refresh tokens are valid for 3 hours
+	"acr": keychainCredentialProvider{authn.NewKeychainFromHelper(credhelper.NewACRCredentialsHelper()), 3 * time.Hour},
+	// GCR and Artifact Registry access tokens are valid for 1 hour
+	"gcr": keychainCredentialProvider{google.Keychain, time.Hour},
+}
+
+// GetRegistryCredentialProvider returns the registry credential provider with the name.
+func GetRegistryCredentialProvider(providerName string) (RegistryCredentialProvider, error) {
+	provider, ok := RegistryCredentialProviders[providerName]
+	if !ok {
+		names := []string{}
+		for n := range RegistryCredentialProviders {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown registry credential provider %q, valid providers are %s", providerName, strings.Join(names, ", "))
+	}
+	return provider, nil
+}
+
+// IssueRegistryCredentials sets the push and pull credentials of the registry to new ones from its credential provider.
+func IssueRegistryCredentials(ctx context.Context, registryInfo *types.RegistryInfo) (time.Time, error) {
+	provider, err := GetRegistryCredentialProvider(registryInfo.CredentialProvider)
+	if err != nil {
+		return time.Time{}, err
+	}
+	issued := time.Now()
+	// Credentials are issued for the registry host, the address may include a repository path
+	registry, _, _ := strings.Cut(registryInfo.Address, "/")
+	username, password, err := provider.Credentials(ctx, registry)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to get credentials for %s from the %s credential provider: %w", registry, registryInfo.CredentialProvider, err)
+	}
+	registryInfo.PushUsername = username
+	registryInfo.PushPassword = password
+	registryInfo.PullUsername = username
+	registryInfo.PullPassword = password
+	return issued.Add(provider.Lifetime()), nil
+}
+
+// RefreshRegistryCredentials replaces the short-lived credentials of a cloud registry in the Zarf state and the
+// Zarf-managed image secrets with new ones from its credential provider before they expire.
+func (c *Cluster) RefreshRegistryCredentials(ctx context.Context) error {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	if state.RegistryInfo.CredentialProvider == "" {
+		message.Infof("The registry %s does not use a credential provider, there are no credentials to refresh", state.RegistryInfo.Address)
+		return nil
+	}
+
+	expiry, err := IssueRegistryCredentials(ctx, &state.RegistryInfo)
+	if err != nil {
+		return err
+	}
+	if err := c.SaveZarfState(ctx, state); err != nil {
+		return fmt.Errorf("unable to save the refreshed registry credentials: %w", err)
+	}
+	if err := c.UpdateZarfManagedImageSecrets(ctx, state); err != nil {
+		return err
+	}
+	message.Infof("Refreshed the credentials of %s, they expire at %s", state.RegistryInfo.Address, expiry.UTC().Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+type fakeCredentialProvider struct {
+	registry string
+}
+
+func (p *fakeCredentialProvider) Credentials(_ context.Context, registry string) (string, string, error) {
+	p.registry = registry
+	return "AWS", "refreshed-token", nil
+}
+
+func (*fakeCredentialProvider) Lifetime() time.Duration {
+	return time.Hour
+}
+
+func TestRefreshRegistryCredentials(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	provider := &fakeCredentialProvider{}
+	RegistryCredentialProviders["fake"] = provider
+	t.Cleanup(func() {
+		delete(RegistryCredentialProviders, "fake")
+	})
+
+	cs := fake.NewSimpleClientset()
+	c := &Cluster{Clientset: cs}
+	for _, name := range []string{ZarfNamespaceName, "app"} {
+		_, err := cs.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	state := &types.ZarfState{
+		Distro: "eks",
+		RegistryInfo: types.RegistryInfo{
+			Address:            "123456789012.dkr.ecr.us-east-1.amazonaws.com/zarf",
+			PushUsername:       "AWS",
+			PushPassword:       "expired-token",
+			PullUsername:       "AWS",
+			PullPassword:       "expired-token",
+			CredentialProvider: "fake",
+		},
+	}
+	require.NoError(t, c.SaveZarfState(ctx, state))
+	secret, err := c.GenerateRegistryPullCreds(ctx, "app", config.ZarfImagePullSecretName, state.RegistryInfo)
+	require.NoError(t, err)
+	_, err = cs.CoreV1().Secrets("app").Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, c.RefreshRegistryCredentials(ctx))
+	require.Equal(t, "123456789012.dkr.ecr.us-east-1.amazonaws.com", provider.registry)
+
+	refreshed, err := c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "refreshed-token", refreshed.RegistryInfo.PushPassword)
+	require.Equal(t, "refreshed-token", refreshed.RegistryInfo.PullPassword)
+
+	secret, err = cs.CoreV1().Secrets("app").Get(ctx, config.ZarfImagePullSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	dockerConfig := DockerConfig{}
+	require.NoError(t, json.Unmarshal(secret.Data[".dockerconfigjson"], &dockerConfig))
+	auth := base64.StdEncoding.EncodeToString([]byte("AWS:refreshed-token"))
+	require.Equal(t, auth, dockerConfig.Auths[state.RegistryInfo.Address].Auth)
+
+	_, err = GetRegistryCredentialProvider("unknown")
+	require.ErrorContains(t, err, `unknown registry credential provider "unknown", valid providers are acr, ecr, fake, gcr`)
+}
@@ -9,12 +9,20 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1ac "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/avast/retry-go/v4"
 
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -37,7 +45,8 @@ type DockerConfigEntryWithAuth struct {
 // GenerateRegistryPullCreds generates a secret containing the registry credentials.
 func (c *Cluster) GenerateRegistryPullCreds(ctx context.Context, namespace, name string, registryInfo types.RegistryInfo) (*corev1.Secret, error) {
 	// Auth field must be username:password and base64 encoded
-	fieldValue := registryInfo.PullUsername + ":" + registryInfo.PullPassword
+	username, password := registryInfo.NamespacePullCredentials(namespace)
+	fieldValue := username + ":" + password
 	authEncodedValue := base64.StdEncoding.EncodeToString([]byte(fieldValue))
 
 	dockerConfigJSON := DockerConfig{
@@ -49,19 +58,27 @@ func (c *Cluster) GenerateRegistryPullCreds(ctx context.Context, namespace, name
 		},
 	}
 
-	serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
-	if err != nil {
+	// Build zarf-docker-registry service address string
+	svc, err := c.ResolveServiceAddress(ctx, registryInfo.Address)
+	if err != nil && !errors.Is(err, ErrServiceNotFound) {
 		return nil, err
 	}
-	// Build zarf-docker-registry service address string
-	svc, port, err := serviceInfoFromNodePortURL(serviceList.Items, registryInfo.Address)
 	if err == nil {
-		kubeDNSRegistryURL := fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, port)
-		dockerConfigJSON.Auths[kubeDNSRegistryURL] = DockerConfigEntryWithAuth{
+		dockerConfigJSON.Auths[svc.InClusterAddress()] = DockerConfigEntryWithAuth{
 			Auth: authEncodedValue,
 		}
 	}
 
+	// Credentials of other registries never replace those of the Zarf registry
+	for _, credential := range registryInfo.AdditionalPullCredentials {
+		if _, ok := dockerConfigJSON.Auths[credential.Address]; ok {
+			continue
+		}
+		dockerConfigJSON.Auths[credential.Address] = DockerConfigEntryWithAuth{
+			Auth: base64.StdEncoding.EncodeToString([]byte(credential.Username + ":" + credential.Password)),
+		}
+	}
+
 	// Convert to JSON
 	dockerConfigData, err := json.Marshal(dockerConfigJSON)
 	if err != nil {
@@ -112,95 +129,213 @@ func (c *Cluster) GenerateGitPullCreds(namespace, name string, gitServerInfo typ
 	return gitServerSecret
 }
 
+// ZarfSecretsAllowed returns true if Zarf-managed image and git pull secrets may be written to the namespace by state.
+// The Zarf namespace is always allowed as the init components pull with them.
+func ZarfSecretsAllowed(state *types.ZarfState, namespace string) bool {
+	return namespace == ZarfNamespaceName || state.SecretNamespaces.Allows(namespace)
+}
+
+// secretUpdateConcurrency is the number of namespaces that Zarf-managed secrets are checked and updated in at once.
+const secretUpdateConcurrency = 10
+
 // UpdateZarfManagedImageSecrets updates all Zarf-managed image secrets in all namespaces based on state
 func (c *Cluster) UpdateZarfManagedImageSecrets(ctx context.Context, state *types.ZarfState) error {
 	spinner := message.NewProgressSpinner("Updating existing Zarf-managed image secrets")
 	defer spinner.Stop()
 
-	namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
-	// Update all image pull secrets
-	for _, namespace := range namespaceList.Items {
-		currentRegistrySecret, err := c.Clientset.CoreV1().Secrets(namespace.Name).Get(ctx, config.ZarfImagePullSecretName, metav1.GetOptions{})
-		if kerrors.IsNotFound(err) {
-			continue
-		}
-		if err != nil {
-			return err
-		}
-		// Skip if namespace is skipped and secret is not managed by Zarf.
-		if currentRegistrySecret.Labels[ZarfManagedByLabel] != "zarf" && (namespace.Labels[AgentLabel] == "skip" || namespace.Labels[AgentLabel] == "ignore") {
-			continue
-		}
-		newRegistrySecret, err := c.GenerateRegistryPullCreds(ctx, namespace.Name, config.ZarfImagePullSecretName, state.RegistryInfo)
-		if err != nil {
-			return err
-		}
-		if maps.EqualFunc(currentRegistrySecret.Data, newRegistrySecret.Data, func(v1, v2 []byte) bool { return bytes.Equal(v1, v2) }) {
-			continue
-		}
-		spinner.Updatef("Updating existing Zarf-managed image secret for namespace: '%s'", namespace.Name)
-		_, err = c.Clientset.CoreV1().Secrets(newRegistrySecret.Namespace).Update(ctx, newRegistrySecret, metav1.UpdateOptions{})
-		if err != nil {
-			return err
-		}
+	// Secrets are updated in the namespaces they could be checked in even when others fail
+	secrets, outdatedErr := c.GetOutdatedZarfManagedImageSecrets(ctx, state)
+	spinner.Updatef("Updating %d existing Zarf-managed image secrets", len(secrets))
+	updateErr := c.applyZarfManagedSecrets(ctx, secrets)
+	if err := errors.Join(outdatedErr, updateErr); err != nil {
+		return fmt.Errorf("unable to update the Zarf-managed image secrets: %w", err)
 	}
 
-	spinner.Success()
+	spinner.Successf("Updated %d existing Zarf-managed image secrets", len(secrets))
 	return nil
 }
 
+// GetOutdatedZarfManagedImageSecrets returns the regenerated Zarf-managed image secrets for every namespace whose current secret does not match state.
+// The secrets of the namespaces that could be checked are returned alongside the errors of the others.
+func (c *Cluster) GetOutdatedZarfManagedImageSecrets(ctx context.Context, state *types.ZarfState) ([]*corev1.Secret, error) {
+	return c.outdatedZarfManagedSecrets(ctx, state, config.ZarfImagePullSecretName, func(namespace string, current *corev1.Secret) (*corev1.Secret, bool, error) {
+		newRegistrySecret, err := c.GenerateRegistryPullCreds(ctx, namespace, config.ZarfImagePullSecretName, state.RegistryInfo)
+		if err != nil {
+			return nil, false, err
+		}
+		outdated := !maps.EqualFunc(current.Data, newRegistrySecret.Data, func(v1, v2 []byte) bool { return bytes.Equal(v1, v2) })
+		return newRegistrySecret, outdated, nil
+	})
+}
+
 // UpdateZarfManagedGitSecrets updates all Zarf-managed git secrets in all namespaces based on state
 func (c *Cluster) UpdateZarfManagedGitSecrets(ctx context.Context, state *types.ZarfState) error {
 	spinner := message.NewProgressSpinner("Updating existing Zarf-managed git secrets")
 	defer spinner.Stop()
 
+	// Secrets are updated in the namespaces they could be checked in even when others fail
+	secrets, outdatedErr := c.GetOutdatedZarfManagedGitSecrets(ctx, state)
+	spinner.Updatef("Updating %d existing Zarf-managed git secrets", len(secrets))
+	updateErr := c.applyZarfManagedSecrets(ctx, secrets)
+	if err := errors.Join(outdatedErr, updateErr); err != nil {
+		return fmt.Errorf("unable to update the Zarf-managed git secrets: %w", err)
+	}
+
+	spinner.Successf("Updated %d existing Zarf-managed git secrets", len(secrets))
+	return nil
+}
+
+// GetOutdatedZarfManagedGitSecrets returns the regenerated Zarf-managed git secrets for every namespace whose current secret does not match state.
+// The secrets of the namespaces that could be checked are returned alongside the errors of the others.
+func (c *Cluster) GetOutdatedZarfManagedGitSecrets(ctx context.Context, state *types.ZarfState) ([]*corev1.Secret, error) {
+	return c.outdatedZarfManagedSecrets(ctx, state, config.ZarfGitServerSecretName, func(namespace string, current *corev1.Secret) (*corev1.Secret, bool, error) {
+		newGitSecret := c.GenerateGitPullCreds(namespace, config.ZarfGitServerSecretName, state.GitServer)
+		return newGitSecret, !maps.Equal(current.StringData, newGitSecret.StringData), nil
+	})
+}
+
+// outdatedZarfManagedSecrets checks the secret named name in every namespace state allows secrets in concurrently, returning the secrets that
+// generate reports as outdated sorted by namespace and the errors of every namespace joined.
+func (c *Cluster) outdatedZarfManagedSecrets(ctx context.Context, state *types.ZarfState, name string, generate func(namespace string, current *corev1.Secret) (*corev1.Secret, bool, error)) ([]*corev1.Secret, error) {
 	namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	namespaces := []corev1.Namespace{}
 	for _, namespace := range namespaceList.Items {
-		currentGitSecret, err := c.Clientset.CoreV1().Secrets(namespace.Name).Get(ctx, config.ZarfGitServerSecretName, metav1.GetOptions{})
-		if kerrors.IsNotFound(err) {
+		if isNamespaceTerminating(namespace) || !ZarfSecretsAllowed(state, namespace.Name) {
 			continue
 		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	var mu sync.Mutex
+	secrets := []*corev1.Secret{}
+	err = forEachConcurrently(namespaces, func(namespace corev1.Namespace) error {
+		currentSecret, err := c.Clientset.CoreV1().Secrets(namespace.Name).Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
 		if err != nil {
-			continue
+			return fmt.Errorf("unable to get the secret %s/%s: %w", namespace.Name, name, err)
 		}
 		// Skip if namespace is skipped and secret is not managed by Zarf.
-		if currentGitSecret.Labels[ZarfManagedByLabel] != "zarf" && (namespace.Labels[AgentLabel] == "skip" || namespace.Labels[AgentLabel] == "ignore") {
-			continue
+		if currentSecret.Labels[ZarfManagedByLabel] != "zarf" && (namespace.Labels[AgentLabel] == "skip" || namespace.Labels[AgentLabel] == "ignore") {
+			return nil
 		}
-		newGitSecret := c.GenerateGitPullCreds(namespace.Name, config.ZarfGitServerSecretName, state.GitServer)
-		if maps.Equal(currentGitSecret.StringData, newGitSecret.StringData) {
-			continue
+		newSecret, outdated, err := generate(namespace.Name, currentSecret)
+		if err != nil {
+			return fmt.Errorf("unable to generate the secret %s/%s: %w", namespace.Name, name, err)
+		}
+		if !outdated {
+			return nil
 		}
-		spinner.Updatef("Updating existing Zarf-managed git secret for namespace: %s", namespace.Name)
-		_, err = c.Clientset.CoreV1().Secrets(newGitSecret.Namespace).Update(ctx, newGitSecret, metav1.UpdateOptions{})
+		mu.Lock()
+		defer mu.Unlock()
+		secrets = append(secrets, newSecret)
+		return nil
+	})
+	slices.SortFunc(secrets, func(a, b *corev1.Secret) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+	return secrets, err
+}
+
+// applyZarfManagedSecrets updates existing Zarf-managed secrets concurrently, returning the errors of every secret joined.
+func (c *Cluster) applyZarfManagedSecrets(ctx context.Context, secrets []*corev1.Secret) error {
+	return forEachConcurrently(secrets, func(secret *corev1.Secret) error {
+		return c.applyZarfManagedSecret(ctx, secret)
+	})
+}
+
+// forEachConcurrently runs fn for every item with at most secretUpdateConcurrency running at once, unlike an errgroup
+// every item is run when one fails and the errors of all of them are returned joined.
+func forEachConcurrently[T any](items []T, fn func(T) error) error {
+	var mu sync.Mutex
+	errs := []error{}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, secretUpdateConcurrency)
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(item); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(item)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// applyZarfManagedSecret updates an existing Zarf-managed secret with server-side apply, preconditioned on the resource
+// version it is read at so a secret that was deleted concurrently is not recreated. Conflicts are read again and
+// retried with backoff alongside transient API errors, and secrets that were deleted or whose namespace is being
+// deleted are skipped.
+func (c *Cluster) applyZarfManagedSecret(ctx context.Context, secret *corev1.Secret) error {
+	err := retry.Do(func() error {
+		current, err := c.Clientset.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
+		secretApply := v1ac.Secret(secret.Name, secret.Namespace).
+			WithResourceVersion(current.ResourceVersion).
+			WithLabels(secret.Labels).
+			WithType(secret.Type).
+			WithData(secret.Data).
+			WithStringData(secret.StringData)
+		_, err = c.Clientset.CoreV1().Secrets(secret.Namespace).Apply(ctx, secretApply, metav1.ApplyOptions{Force: true, FieldManager: FieldManagerName})
+		return err
+	},
+		retry.Context(ctx),
+		retry.Attempts(5),
+		retry.Delay(500*time.Millisecond),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(isRetryableSecretError),
+	)
+	if kerrors.IsNotFound(err) || kerrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+		message.Debugf("Skipping the Zarf-managed secret %s/%s as it or its namespace was deleted", secret.Namespace, secret.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update the Zarf-managed secret %s/%s: %w", secret.Namespace, secret.Name, err)
 	}
-
-	spinner.Success()
 	return nil
 }
 
-// GetServiceInfoFromRegistryAddress gets the service info for a registry address if it is a NodePort
+// isRetryableSecretError returns true for errors that updating a secret can succeed after, conflicts with concurrent
+// changes and transient API server errors.
+func isRetryableSecretError(err error) bool {
+	return kerrors.IsConflict(err) ||
+		kerrors.IsServerTimeout(err) ||
+		kerrors.IsTimeout(err) ||
+		kerrors.IsTooManyRequests(err) ||
+		kerrors.IsInternalError(err) ||
+		kerrors.IsServiceUnavailable(err)
+}
+
+// isNamespaceTerminating returns true if the namespace is being deleted, secrets in it can no longer be reliably updated.
+func isNamespaceTerminating(namespace corev1.Namespace) bool {
+	return namespace.Status.Phase == corev1.NamespaceTerminating || namespace.DeletionTimestamp != nil
+}
+
+// GetServiceInfoFromRegistryAddress returns the in-cluster address of the service serving a registry address, or the
+// registry address as it is when it is not served by a service in the cluster.
 func (c *Cluster) GetServiceInfoFromRegistryAddress(ctx context.Context, stateRegistryAddress string) (string, error) {
-	serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return "", err
+	svc, err := c.ResolveServiceAddress(ctx, stateRegistryAddress)
+	if errors.Is(err, ErrServiceNotFound) {
+		message.Debugf("registry appears to not be served by a service in the cluster, using original address %q", stateRegistryAddress)
+		return stateRegistryAddress, nil
 	}
-
-	// If this is an internal service then we need to look it up and
-	svc, port, err := serviceInfoFromNodePortURL(serviceList.Items, stateRegistryAddress)
 	if err != nil {
-		message.Debugf("registry appears to not be a nodeport service, using original address %q", stateRegistryAddress)
-		return stateRegistryAddress, nil
+		return "", err
 	}
-
-	return fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, port), nil
+	return svc.InClusterAddress(), nil
 }
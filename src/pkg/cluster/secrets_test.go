@@ -4,12 +4,22 @@
 package cluster
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/test/testutil"
@@ -22,6 +32,7 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 	tests := []struct {
 		name               string
 		namespaceLabels    map[string]string
+		namespacePhase     corev1.NamespacePhase
 		secretLabels       map[string]string
 		updatedImageSecret bool
 		updatedGitSecret   bool
@@ -65,6 +76,13 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 			updatedImageSecret: true,
 			updatedGitSecret:   true,
 		},
+		{
+			name:           "terminating namespace",
+			namespacePhase: corev1.NamespaceTerminating,
+			secretLabels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,6 +95,9 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 					Name:   "test",
 					Labels: tt.namespaceLabels,
 				},
+				Status: corev1.NamespaceStatus{
+					Phase: tt.namespacePhase,
+				},
 			}
 			_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
 			require.NoError(t, err)
@@ -136,6 +157,21 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 					Address:      "127.0.0.1:30001",
 				},
 			}
+			outdatedImageSecrets, err := c.GetOutdatedZarfManagedImageSecrets(ctx, state)
+			require.NoError(t, err)
+			outdatedGitSecrets, err := c.GetOutdatedZarfManagedGitSecrets(ctx, state)
+			require.NoError(t, err)
+			if tt.updatedImageSecret {
+				require.Len(t, outdatedImageSecrets, 1)
+			} else {
+				require.Empty(t, outdatedImageSecrets)
+			}
+			if tt.updatedGitSecret {
+				require.Len(t, outdatedGitSecrets, 1)
+			} else {
+				require.Empty(t, outdatedGitSecrets)
+			}
+
 			err = c.UpdateZarfManagedImageSecrets(ctx, state)
 			require.NoError(t, err)
 			err = c.UpdateZarfManagedGitSecrets(ctx, state)
@@ -192,7 +228,6 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 					},
 				},
 				Type: corev1.SecretTypeOpaque,
-				Data: map[string][]byte{},
 				StringData: map[string]string{
 					"username": state.GitServer.PullUsername,
 					"password": state.GitServer.PullPassword,
@@ -205,3 +240,300 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateZarfManagedSecretsErrors(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	cs := fake.NewSimpleClientset()
+	c := &Cluster{Clientset: cs}
+	namespaces := []string{}
+	for i := range 25 {
+		name := fmt.Sprintf("test-%02d", i)
+		namespaces = append(namespaces, name)
+		_, err := cs.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.ZarfGitServerSecretName,
+				Namespace: name,
+				Labels:    map[string]string{ZarfManagedByLabel: "zarf"},
+			},
+		}
+		_, err = cs.CoreV1().Secrets(name).Create(ctx, secret, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	failing := map[string]bool{"test-03": true, "test-17": true}
+	cs.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failing[action.GetNamespace()] {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+
+	state := &types.ZarfState{
+		GitServer: types.GitServerInfo{
+			PullUsername: "pull-user",
+			PullPassword: "pull-password",
+		},
+	}
+	secrets, err := c.GetOutdatedZarfManagedGitSecrets(ctx, state)
+	require.ErrorContains(t, err, "unable to get the secret test-03/private-git-server: connection refused")
+	require.ErrorContains(t, err, "unable to get the secret test-17/private-git-server: connection refused")
+	require.Len(t, secrets, len(namespaces)-len(failing))
+	for i := 1; i < len(secrets); i++ {
+		require.Less(t, secrets[i-1].Namespace, secrets[i].Namespace)
+	}
+
+	// The secrets of the other namespaces are still updated
+	err = c.UpdateZarfManagedGitSecrets(ctx, state)
+	require.ErrorContains(t, err, "unable to update the Zarf-managed git secrets")
+	require.ErrorContains(t, err, "test-03")
+	require.ErrorContains(t, err, "test-17")
+	for _, name := range namespaces {
+		if failing[name] {
+			continue
+		}
+		secret, err := cs.CoreV1().Secrets(name).Get(ctx, config.ZarfGitServerSecretName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, state.GitServer.PullUsername, secret.StringData["username"])
+	}
+}
+
+func TestApplyZarfManagedSecret(t *testing.T) {
+	t.Parallel()
+
+	conflict := kerrors.NewConflict(corev1.Resource("secrets"), config.ZarfGitServerSecretName, errors.New("the object has been modified"))
+	tests := []struct {
+		name            string
+		deleted         bool
+		deletedOnApply  bool
+		applyErrs       []error
+		expectedApplies int
+		expectedErr     string
+	}{
+		{
+			name:            "apply",
+			expectedApplies: 1,
+		},
+		{
+			name:            "deleted before it is read",
+			deleted:         true,
+			expectedApplies: 0,
+		},
+		{
+			name:            "deleted after it is read",
+			deletedOnApply:  true,
+			applyErrs:       []error{conflict},
+			expectedApplies: 1,
+		},
+		{
+			name:            "server timeout",
+			applyErrs:       []error{kerrors.NewServerTimeout(corev1.Resource("secrets"), "patch", 1)},
+			expectedApplies: 2,
+		},
+		{
+			name:            "conflict",
+			applyErrs:       []error{conflict},
+			expectedApplies: 2,
+		},
+		{
+			name:            "forbidden",
+			applyErrs:       []error{kerrors.NewForbidden(corev1.Resource("secrets"), config.ZarfGitServerSecretName, errors.New("denied"))},
+			expectedApplies: 1,
+			expectedErr:     "unable to update the Zarf-managed secret test/private-git-server",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			cs := fake.NewSimpleClientset()
+			c := &Cluster{Clientset: cs}
+			if !tt.deleted {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            config.ZarfGitServerSecretName,
+						Namespace:       "test",
+						Labels:          map[string]string{ZarfManagedByLabel: "zarf"},
+						ResourceVersion: "42",
+					},
+				}
+				_, err := cs.CoreV1().Secrets("test").Create(ctx, secret, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			var mu sync.Mutex
+			applies := 0
+			applyErrs := tt.applyErrs
+			cs.PrependReactor("patch", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				applies++
+				// The apply is preconditioned on the resource version the secret was read at
+				patch := action.(k8stesting.PatchAction)
+				require.Equal(t, k8stypes.ApplyPatchType, patch.GetPatchType())
+				require.Contains(t, string(patch.GetPatch()), `"resourceVersion":"42"`)
+				if tt.deletedOnApply {
+					require.NoError(t, cs.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("secrets"), "test", config.ZarfGitServerSecretName))
+				}
+				if len(applyErrs) == 0 {
+					return false, nil, nil
+				}
+				err := applyErrs[0]
+				applyErrs = applyErrs[1:]
+				return true, nil, err
+			})
+
+			secret := c.GenerateGitPullCreds("test", config.ZarfGitServerSecretName, types.GitServerInfo{PullUsername: "pull-user"})
+			err := c.applyZarfManagedSecret(ctx, secret)
+			require.Equal(t, tt.expectedApplies, applies)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// A deleted secret is not recreated
+			updated, err := cs.CoreV1().Secrets("test").Get(ctx, config.ZarfGitServerSecretName, metav1.GetOptions{})
+			if tt.deleted || tt.deletedOnApply {
+				require.True(t, kerrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "pull-user", updated.StringData["username"])
+		})
+	}
+}
+
+func TestZarfManagedSecretsNamespaceFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   types.NamespaceFilter
+		expected []string
+	}{
+		{
+			name:     "every namespace",
+			expected: []string{"kube-system", "team-a", "team-b", "tenant-a", ZarfNamespaceName},
+		},
+		{
+			name:     "allow",
+			filter:   types.NamespaceFilter{Allow: []string{"team-*"}},
+			expected: []string{"team-a", "team-b", ZarfNamespaceName},
+		},
+		{
+			name:     "deny",
+			filter:   types.NamespaceFilter{Deny: []string{"tenant-*", "kube-system"}},
+			expected: []string{"team-a", "team-b", ZarfNamespaceName},
+		},
+		{
+			name:     "deny takes precedence over allow",
+			filter:   types.NamespaceFilter{Allow: []string{"team-*", "tenant-*"}, Deny: []string{"team-b"}},
+			expected: []string{"team-a", "tenant-a", ZarfNamespaceName},
+		},
+		{
+			name:     "zarf namespace is always allowed",
+			filter:   types.NamespaceFilter{Deny: []string{"*"}},
+			expected: []string{ZarfNamespaceName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			cs := fake.NewSimpleClientset()
+			c := &Cluster{Clientset: cs}
+			for _, name := range []string{"kube-system", "team-a", "team-b", "tenant-a", ZarfNamespaceName} {
+				_, err := cs.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+				require.NoError(t, err)
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      config.ZarfGitServerSecretName,
+						Namespace: name,
+						Labels:    map[string]string{ZarfManagedByLabel: "zarf"},
+					},
+				}
+				_, err = cs.CoreV1().Secrets(name).Create(ctx, secret, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			state := &types.ZarfState{
+				GitServer:        types.GitServerInfo{PullUsername: "pull-user"},
+				SecretNamespaces: tt.filter,
+			}
+			secrets, err := c.GetOutdatedZarfManagedGitSecrets(ctx, state)
+			require.NoError(t, err)
+			namespaces := []string{}
+			for _, secret := range secrets {
+				namespaces = append(namespaces, secret.Namespace)
+			}
+			require.Equal(t, tt.expected, namespaces)
+		})
+	}
+}
+
+func TestGenerateRegistryPullCredsPerNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	registryInfo := types.RegistryInfo{
+		PullUsername:              "pull-user",
+		PullPassword:              "pull-password",
+		Address:                   "127.0.0.1:30001",
+		NamespacedPullCredentials: true,
+	}
+
+	auths := map[string]string{}
+	for _, namespace := range []string{"team-a", "team-b"} {
+		secret, err := c.GenerateRegistryPullCreds(ctx, namespace, config.ZarfImagePullSecretName, registryInfo)
+		require.NoError(t, err)
+		dockerConfig := DockerConfig{}
+		require.NoError(t, json.Unmarshal(secret.Data[".dockerconfigjson"], &dockerConfig))
+		auth, err := base64.StdEncoding.DecodeString(dockerConfig.Auths[registryInfo.Address].Auth)
+		require.NoError(t, err)
+		username, password, ok := strings.Cut(string(auth), ":")
+		require.True(t, ok)
+		require.Equal(t, types.ZarfRegistryNamespacePullUserPrefix+namespace, username)
+		require.NotEqual(t, registryInfo.PullPassword, password)
+		auths[namespace] = password
+	}
+	require.NotEqual(t, auths["team-a"], auths["team-b"])
+
+	// The credentials are derived from the pull password so they are stable until it is rotated
+	_, password := registryInfo.NamespacePullCredentials("team-a")
+	require.Equal(t, auths["team-a"], password)
+	registryInfo.PullPassword = "rotated-pull-password"
+	_, password = registryInfo.NamespacePullCredentials("team-a")
+	require.NotEqual(t, auths["team-a"], password)
+}
+
+func TestGenerateRegistryPullCredsAdditional(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	registryInfo := types.RegistryInfo{
+		PullUsername: "pull-user",
+		PullPassword: "pull-password",
+		Address:      "127.0.0.1:30001",
+		AdditionalPullCredentials: []types.RegistryCredential{
+			{Address: "mirror.example.com", Username: "mirror-user", Password: "mirror-password"},
+			{Address: "127.0.0.1:30001", Username: "other-user", Password: "other-password"},
+		},
+	}
+	secret, err := c.GenerateRegistryPullCreds(ctx, "test", config.ZarfImagePullSecretName, registryInfo)
+	require.NoError(t, err)
+	dockerConfig := DockerConfig{}
+	require.NoError(t, json.Unmarshal(secret.Data[".dockerconfigjson"], &dockerConfig))
+	expected := DockerConfig{
+		Auths: DockerConfigEntry{
+			"127.0.0.1:30001":    DockerConfigEntryWithAuth{Auth: base64.StdEncoding.EncodeToString([]byte("pull-user:pull-password"))},
+			"mirror.example.com": DockerConfigEntryWithAuth{Auth: base64.StdEncoding.EncodeToString([]byte("mirror-user:mirror-password"))},
+		},
+	}
+	require.Equal(t, expected, dockerConfig)
+}
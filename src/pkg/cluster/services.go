@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrServiceNotFound is returned when an address is not served by a service in the cluster.
+var ErrServiceNotFound = errors.New("no service in the cluster serves the address")
+
+// serviceCacheTTL is how long a resolved address is reused before the services of the cluster are looked up again.
+const serviceCacheTTL = 30 * time.Second
+
+// ServiceAddress is the service in the cluster that serves an address reachable from outside of the cluster.
+type ServiceAddress struct {
+	Namespace string
+	Name      string
+	ClusterIP string
+	// Port is the port of the service that serves the address.
+	Port int
+}
+
+// InClusterAddress returns the address pods in the cluster reach the service on.
+func (s ServiceAddress) InClusterAddress() string {
+	host := s.ClusterIP
+	if host == "" || host == corev1.ClusterIPNone {
+		host = fmt.Sprintf("%s.%s.svc.cluster.local", s.Name, s.Namespace)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(s.Port))
+}
+
+// serviceCache caches the services resolved from addresses.
+type serviceCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]serviceCacheEntry
+}
+
+type serviceCacheEntry struct {
+	svc     ServiceAddress
+	err     error
+	expires time.Time
+}
+
+func newServiceCache(ttl time.Duration) *serviceCache {
+	return &serviceCache{ttl: ttl, entries: map[string]serviceCacheEntry{}}
+}
+
+func (sc *serviceCache) get(address string) (serviceCacheEntry, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry, ok := sc.entries[address]
+	if !ok || time.Now().After(entry.expires) {
+		return serviceCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (sc *serviceCache) set(address string, svc ServiceAddress, err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.entries[address] = serviceCacheEntry{svc: svc, err: err, expires: time.Now().Add(sc.ttl)}
+}
+
+func (sc *serviceCache) clear() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	clear(sc.entries)
+}
+
+// ResolveServiceAddress returns the service in the cluster that serves address, which is either a NodePort on localhost,
+// the ingress of a LoadBalancer service or the host of an Ingress. ErrServiceNotFound is returned when no service
+// serves the address. Results are cached for a short time on clusters created with NewCluster.
+func (c *Cluster) ResolveServiceAddress(ctx context.Context, address string) (ServiceAddress, error) {
+	if c.services != nil {
+		if entry, ok := c.services.get(address); ok {
+			return entry.svc, entry.err
+		}
+	}
+	svc, err := c.resolveServiceAddress(ctx, address)
+	// Only cache whether the address is served by a service, not errors talking to the cluster
+	if c.services != nil && (err == nil || errors.Is(err, ErrServiceNotFound)) {
+		c.services.set(address, svc, err)
+	}
+	return svc, err
+}
+
+// ClearServiceCache forgets the cached services of ResolveServiceAddress, it should be called after changing services.
+func (c *Cluster) ClearServiceCache() {
+	if c.services != nil {
+		c.services.clear()
+	}
+}
+
+func (c *Cluster) resolveServiceAddress(ctx context.Context, address string) (ServiceAddress, error) {
+	hostname, port, err := parseServiceAddress(address)
+	if err != nil {
+		return ServiceAddress{}, err
+	}
+	serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ServiceAddress{}, err
+	}
+
+	if svc, port, err := serviceInfoFromNodePortURL(serviceList.Items, address); err == nil {
+		return newServiceAddress(svc, port), nil
+	}
+	if svc, port, ok := serviceFromLoadBalancer(serviceList.Items, hostname, port); ok {
+		return newServiceAddress(svc, port), nil
+	}
+
+	// Ingresses are only served on the standard http ports
+	if port != 0 && port != 80 && port != 443 {
+		return ServiceAddress{}, fmt.Errorf("%w: %s", ErrServiceNotFound, address)
+	}
+	ingressList, err := c.Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ServiceAddress{}, err
+	}
+	if svc, port, ok := serviceFromIngress(ingressList.Items, serviceList.Items, hostname); ok {
+		return newServiceAddress(svc, port), nil
+	}
+	return ServiceAddress{}, fmt.Errorf("%w: %s", ErrServiceNotFound, address)
+}
+
+func newServiceAddress(svc corev1.Service, port int) ServiceAddress {
+	return ServiceAddress{Namespace: svc.Namespace, Name: svc.Name, ClusterIP: svc.Spec.ClusterIP, Port: port}
+}
+
+// parseServiceAddress returns the hostname and port of an address with or without a scheme, the port is 0 when the
+// address has none.
+func parseServiceAddress(address string) (string, int, error) {
+	// Docker registries don't use schemes
+	parsedURL, err := url.Parse(address)
+	if err != nil || parsedURL.Host == "" {
+		parsedURL, err = url.Parse("scheme://" + address)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	if parsedURL.Port() == "" {
+		return parsedURL.Hostname(), 0, nil
+	}
+	port, err := strconv.Atoi(parsedURL.Port())
+	if err != nil {
+		return "", 0, err
+	}
+	return parsedURL.Hostname(), port, nil
+}
+
+// serviceFromLoadBalancer returns the LoadBalancer service with an ingress on hostname and the port of the service
+// on port.
+func serviceFromLoadBalancer(services []corev1.Service, hostname string, port int) (corev1.Service, int, bool) {
+	if hostname == helpers.IPV4Localhost || hostname == "localhost" {
+		return corev1.Service{}, 0, false
+	}
+	for _, svc := range services {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != hostname && ingress.Hostname != hostname {
+				continue
+			}
+			for _, svcPort := range svc.Spec.Ports {
+				if int(svcPort.Port) == port || (port == 0 && len(svc.Spec.Ports) == 1) {
+					return svc, int(svcPort.Port), true
+				}
+			}
+		}
+	}
+	return corev1.Service{}, 0, false
+}
+
+// serviceFromIngress returns the backend service of the first path of the Ingress rule for hostname and the port of the
+// backend service.
+func serviceFromIngress(ingresses []networkingv1.Ingress, services []corev1.Service, hostname string) (corev1.Service, int, bool) {
+	for _, ingress := range ingresses {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != hostname || rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+				continue
+			}
+			backend := rule.HTTP.Paths[0].Backend.Service
+			if backend == nil {
+				continue
+			}
+			for _, svc := range services {
+				if svc.Namespace != ingress.Namespace || svc.Name != backend.Name {
+					continue
+				}
+				for _, svcPort := range svc.Spec.Ports {
+					if svcPort.Port == backend.Port.Number || (backend.Port.Name != "" && svcPort.Name == backend.Port.Name) {
+						return svc, int(svcPort.Port), true
+					}
+				}
+			}
+		}
+	}
+	return corev1.Service{}, 0, false
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveServiceAddress(t *testing.T) {
+	t.Parallel()
+
+	objects := []runtime.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "zarf-docker-registry", Namespace: "zarf"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: "10.43.0.10",
+				Ports:     []corev1.ServicePort{{Port: 5000, NodePort: 31999}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-lb", Namespace: "registry"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeLoadBalancer,
+				ClusterIP: "10.43.0.20",
+				Ports:     []corev1.ServicePort{{Port: 443}, {Port: 5000}},
+			},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "192.168.1.20"}, {Hostname: "lb.example.com"}}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "registry"},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: corev1.ClusterIPNone,
+				Ports:     []corev1.ServicePort{{Name: "http", Port: 8080}},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "registry"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{
+					Host: "registry.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: "registry",
+								Port: networkingv1.ServiceBackendPort{Name: "http"},
+							}},
+						}},
+					}},
+				}},
+			},
+		},
+	}
+	c := &Cluster{Clientset: fake.NewSimpleClientset(objects...)}
+
+	tests := []struct {
+		name            string
+		address         string
+		expected        ServiceAddress
+		expectedAddress string
+		expectedErr     error
+	}{
+		{
+			name:            "node port",
+			address:         "127.0.0.1:31999",
+			expected:        ServiceAddress{Namespace: "zarf", Name: "zarf-docker-registry", ClusterIP: "10.43.0.10", Port: 5000},
+			expectedAddress: "10.43.0.10:5000",
+		},
+		{
+			name:            "load balancer ip",
+			address:         "192.168.1.20:5000",
+			expected:        ServiceAddress{Namespace: "registry", Name: "registry-lb", ClusterIP: "10.43.0.20", Port: 5000},
+			expectedAddress: "10.43.0.20:5000",
+		},
+		{
+			name:            "load balancer hostname with scheme",
+			address:         "https://lb.example.com:443",
+			expected:        ServiceAddress{Namespace: "registry", Name: "registry-lb", ClusterIP: "10.43.0.20", Port: 443},
+			expectedAddress: "10.43.0.20:443",
+		},
+		{
+			name:            "ingress",
+			address:         "registry.example.com",
+			expected:        ServiceAddress{Namespace: "registry", Name: "registry", ClusterIP: corev1.ClusterIPNone, Port: 8080},
+			expectedAddress: "registry.registry.svc.cluster.local:8080",
+		},
+		{
+			name:        "ingress on a non http port",
+			address:     "registry.example.com:5000",
+			expectedErr: ErrServiceNotFound,
+		},
+		{
+			name:        "external registry",
+			address:     "ghcr.io",
+			expectedErr: ErrServiceNotFound,
+		},
+		{
+			name:        "unused node port",
+			address:     "127.0.0.1:31998",
+			expectedErr: ErrServiceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := c.ResolveServiceAddress(context.Background(), tt.address)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, svc)
+			require.Equal(t, tt.expectedAddress, svc.InClusterAddress())
+		})
+	}
+}
+
+func TestResolveServiceAddressCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &Cluster{Clientset: fake.NewSimpleClientset(), services: newServiceCache(time.Hour)}
+	_, err := c.ResolveServiceAddress(ctx, "127.0.0.1:31999")
+	require.ErrorIs(t, err, ErrServiceNotFound)
+
+	_, err = c.Clientset.CoreV1().Services("zarf").Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "zarf-docker-registry", Namespace: "zarf"},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeNodePort,
+			ClusterIP: "10.43.0.10",
+			Ports:     []corev1.ServicePort{{Port: 5000, NodePort: 31999}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// The address is resolved from the cache until it is cleared
+	_, err = c.ResolveServiceAddress(ctx, "127.0.0.1:31999")
+	require.ErrorIs(t, err, ErrServiceNotFound)
+	c.ClearServiceCache()
+	svc, err := c.ResolveServiceAddress(ctx, "127.0.0.1:31999")
+	require.NoError(t, err)
+	require.Equal(t, "10.43.0.10:5000", svc.InClusterAddress())
+}
@@ -5,11 +5,18 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +39,23 @@ const (
 	ZarfStateSecretName  = "zarf-state"
 	ZarfStateDataKey     = "state"
 	ZarfPackageInfoLabel = "package-deploy-info"
+
+	ZarfStateSignatureKey          = "signature"
+	ZarfStateKeySignatureKey       = "state-key-signature"
+	ZarfStateSignedAnnotation      = "zarf.dev/state-signed"
+	ZarfStateSigningKeySecretName  = "zarf-state-signing-key"
+	ZarfStateSigningKeyDataKey     = "key"
+	zarfStateSigningKeyLengthBytes = 32
+	zarfStateKeyMinLengthBytes     = 32
+)
+
+var (
+	// ErrStateSignatureMismatch is returned when the signature of the Zarf state does not match its contents.
+	ErrStateSignatureMismatch = errors.New("the Zarf state signature does not match its contents, the state may have been tampered with")
+	// ErrStateUnsigned is returned when the Zarf state has no signature.
+	ErrStateUnsigned = errors.New("the Zarf state is not signed")
+	// ErrStateKeyUnsigned is returned when the Zarf state has no signature made with the operator-held state key.
+	ErrStateKeyUnsigned = errors.New("the Zarf state is not signed with the state key, it was last saved by Zarf without the state key")
 )
 
 // InitZarfState initializes the Zarf state with the given temporary directory and init configs.
@@ -77,11 +101,15 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 		}
 
 		// Setup zarf agent PKI
-		agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
-		if err != nil {
-			return err
+		if len(initOptions.AgentTLS.CA) > 0 {
+			state.AgentTLS = initOptions.AgentTLS
+		} else {
+			agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
+			if err != nil {
+				return err
+			}
+			state.AgentTLS = agentTLS
 		}
-		state.AgentTLS = agentTLS
 
 		namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 		if err != nil {
@@ -150,8 +178,16 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 			return err
 		}
 		state.RegistryInfo = initOptions.RegistryInfo
+		if state.RegistryInfo.NamespacedPullCredentials {
+			// The init components pull from the Zarf namespace before the registry can be updated with new credentials
+			state.RegistryInfo.PullNamespaces = []string{ZarfNamespaceName}
+		}
 		initOptions.ArtifactServer.FillInEmptyValues()
 		state.ArtifactServer = initOptions.ArtifactServer
+		// The token of the internal artifact server is created once Gitea is deployed
+		if err := state.ArtifactServer.SetPushToken(state.ArtifactServer.PushToken, time.Now()); err != nil {
+			return err
+		}
 	} else {
 		if helpers.IsNotZeroAndNotEqual(initOptions.GitServer, state.GitServer) {
 			message.Warn("Detected a change in Git Server init options on a re-init. Ignoring... To update run:")
@@ -182,6 +218,20 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 		state.StorageClass = initOptions.StorageClass
 	}
 
+	if len(initOptions.RegistryMirrors) > 0 {
+		state.RegistryMirrors = initOptions.RegistryMirrors.Addresses()
+	}
+
+	if len(initOptions.SecretNamespaces.Allow) > 0 || len(initOptions.SecretNamespaces.Deny) > 0 {
+		state.SecretNamespaces = initOptions.SecretNamespaces
+	}
+
+	state.Proxy = types.ProxyInfo{
+		HTTPProxy:  initOptions.Proxy.HTTPProxy,
+		HTTPSProxy: initOptions.Proxy.HTTPSProxy,
+		NoProxy:    strings.Join(initOptions.Proxy.NoProxy, ","),
+	}
+
 	spinner.Success()
 
 	// Save the state back to K8s
@@ -192,19 +242,62 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 	return nil
 }
 
-// LoadZarfState returns the current zarf/zarf-state secret data or an empty ZarfState.
+// storedZarfState is the Zarf state as it is stored in the cluster.
+type storedZarfState struct {
+	state *types.ZarfState
+	// The serialized state that is signed
+	data []byte
+	// The secret the signature of the state is stored in
+	secret *corev1.Secret
+	// Whether the state is stored in the zarf-state secret rather than the ZarfState custom resource
+	legacy bool
+	// Whether the state has been signed before, so it must always have a signature and signing key
+	marked bool
+}
+
+// loadStoredZarfState reads the state from the ZarfState custom resource, falling back to the legacy zarf-state
+// secret when the custom resource does not exist.
+func (c *Cluster) loadStoredZarfState(ctx context.Context) (*storedZarfState, error) {
+	if c.Dynamic != nil {
+		obj, secret, err := c.getZarfStateResource(ctx)
+		if err == nil {
+			state, data, err := zarfStateFromResource(obj.Object["spec"], secret.Data)
+			if err != nil {
+				return nil, err
+			}
+			marked := obj.GetAnnotations()[ZarfStateSignedAnnotation] == "true"
+			return &storedZarfState{state: state, data: data, secret: secret, marked: marked}, nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var state *types.ZarfState
+	if err := json.Unmarshal(secret.Data[ZarfStateDataKey], &state); err != nil {
+		return nil, err
+	}
+	marked := secret.Annotations[ZarfStateSignedAnnotation] == "true"
+	return &storedZarfState{state: state, data: secret.Data[ZarfStateDataKey], secret: secret, legacy: true, marked: marked}, nil
+}
+
+// LoadZarfState returns the current Zarf state from the ZarfState custom resource, or the zarf/zarf-state secret of
+// clusters that have not been migrated to it.
 func (c *Cluster) LoadZarfState(ctx context.Context) (state *types.ZarfState, err error) {
 	stateErr := errors.New("failed to load the Zarf State from the cluster, has Zarf been initiated?")
-	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	stored, err := c.loadStoredZarfState(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", stateErr, err)
 	}
-	err = json.Unmarshal(secret.Data[ZarfStateDataKey], &state)
+	err = c.verifyStoredZarfState(ctx, stored, false)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", stateErr, err)
+		return nil, err
 	}
-	c.debugPrintZarfState(state)
-	return state, nil
+	c.debugPrintZarfState(stored.state)
+	return stored.state, nil
 }
 
 func (c *Cluster) sanitizeZarfState(state *types.ZarfState) *types.ZarfState {
@@ -221,6 +314,27 @@ func (c *Cluster) sanitizeZarfState(state *types.ZarfState) *types.ZarfState {
 	state.RegistryInfo.PushPassword = "**sanitized**"
 	state.RegistryInfo.PullPassword = "**sanitized**"
 	state.RegistryInfo.Secret = "**sanitized**"
+	if state.RegistryInfo.S3.AccessKey != "" {
+		state.RegistryInfo.S3.AccessKey = "**sanitized**"
+	}
+	if state.RegistryInfo.S3.SecretKey != "" {
+		state.RegistryInfo.S3.SecretKey = "**sanitized**"
+	}
+	// Copy the additional pull credentials so the passwords of the state being printed are not overwritten
+	if state.RegistryInfo.AdditionalPullCredentials != nil {
+		credentials := slices.Clone(state.RegistryInfo.AdditionalPullCredentials)
+		for i := range credentials {
+			credentials[i].Password = "**sanitized**"
+		}
+		state.RegistryInfo.AdditionalPullCredentials = credentials
+	}
+	if state.RegistryInfo.TunnelPullEntries != nil {
+		entries := map[string]string{}
+		for username := range state.RegistryInfo.TunnelPullEntries {
+			entries[username] = "**sanitized**"
+		}
+		state.RegistryInfo.TunnelPullEntries = entries
+	}
 
 	// Overwrite the ArtifactServer secret
 	state.ArtifactServer.PushToken = "**sanitized**"
@@ -242,10 +356,68 @@ func (c *Cluster) debugPrintZarfState(state *types.ZarfState) {
 	message.Debugf("ZarfState - %s", string(b))
 }
 
-// SaveZarfState takes a given state and persists it to the Zarf/zarf-state secret.
+// SaveZarfState takes a given state and persists it to the ZarfState custom resource, with its sensitive fields in the
+// zarf/zarf-state-credentials secret. A state still stored in the legacy zarf/zarf-state secret is migrated.
 func (c *Cluster) SaveZarfState(ctx context.Context, state *types.ZarfState) error {
 	c.debugPrintZarfState(state)
 
+	key, err := c.getZarfStateSigningKey(ctx, true)
+	if err != nil {
+		return fmt.Errorf("unable to sign the zarf state: %w", err)
+	}
+	if c.Dynamic == nil {
+		return c.saveLegacyZarfState(ctx, state, key)
+	}
+
+	spec, data, err := splitZarfState(state)
+	if err != nil {
+		return err
+	}
+	specMap, err := toUnstructuredMap(spec)
+	if err != nil {
+		return err
+	}
+	_, signed, err := zarfStateFromResource(specMap, data)
+	if err != nil {
+		return err
+	}
+	if err := c.ensureZarfStateCRD(ctx); err != nil {
+		return err
+	}
+	obj, err := c.saveZarfStateResource(ctx, specMap)
+	if err != nil {
+		return err
+	}
+	data[ZarfStateSignatureKey] = signZarfStateData(key, signed)
+	if c.StateKey != nil {
+		data[ZarfStateKeySignatureKey] = signZarfStateData(c.StateKey, signed)
+	}
+	secret := credentialsSecret(obj, data)
+	_, err = c.Clientset.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = c.Clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to save the credentials of the zarf state: %w", err)
+	}
+
+	// The state is now stored in the custom resource, so the legacy secret is removed
+	err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Delete(ctx, ZarfStateSecretName, metav1.DeleteOptions{})
+	if err == nil {
+		message.Debugf("Migrated the Zarf state from the %s/%s secret to the %s custom resource", ZarfNamespaceName, ZarfStateSecretName, ZarfStateKind)
+	} else if !kerrors.IsNotFound(err) {
+		return fmt.Errorf("unable to remove the legacy zarf state secret: %w", err)
+	}
+
+	// The status is informational, so failing to update it does not fail saving the state
+	if err := c.UpdateZarfStateStatus(ctx); err != nil {
+		message.Debugf("Unable to update the status of the Zarf state: %s", err.Error())
+	}
+	return nil
+}
+
+// saveLegacyZarfState persists the state to the zarf/zarf-state secret, for clusters without a dynamic client.
+func (c *Cluster) saveLegacyZarfState(ctx context.Context, state *types.ZarfState, key []byte) error {
 	data, err := json.Marshal(&state)
 	if err != nil {
 		return err
@@ -261,12 +433,19 @@ func (c *Cluster) SaveZarfState(ctx context.Context, state *types.ZarfState) err
 			Labels: map[string]string{
 				ZarfManagedByLabel: "zarf",
 			},
+			Annotations: map[string]string{
+				ZarfStateSignedAnnotation: "true",
+			},
 		},
 		Type: corev1.SecretTypeOpaque,
 		Data: map[string][]byte{
-			ZarfStateDataKey: data,
+			ZarfStateDataKey:      data,
+			ZarfStateSignatureKey: signZarfStateData(key, data),
 		},
 	}
+	if c.StateKey != nil {
+		secret.Data[ZarfStateKeySignatureKey] = signZarfStateData(c.StateKey, data)
+	}
 
 	// Attempt to create or update the secret and return.
 	_, err = c.Clientset.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
@@ -283,6 +462,165 @@ func (c *Cluster) SaveZarfState(ctx context.Context, state *types.ZarfState) err
 	return nil
 }
 
+// VerifyZarfState checks that the Zarf state is signed and that its signature matches its contents. When the cluster
+// has a state key, the state must also carry a matching signature made with it.
+func (c *Cluster) VerifyZarfState(ctx context.Context) error {
+	stored, err := c.loadStoredZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.verifyStoredZarfState(ctx, stored, true); err != nil {
+		return err
+	}
+	if c.StateKey == nil {
+		return nil
+	}
+	signature, ok := stored.secret.Data[ZarfStateKeySignatureKey]
+	if !ok {
+		return ErrStateKeyUnsigned
+	}
+	if !hmac.Equal(signature, signZarfStateData(c.StateKey, stored.data)) {
+		return fmt.Errorf("%w: the signature made with the state key does not match", ErrStateSignatureMismatch)
+	}
+	return nil
+}
+
+// SignZarfState signs the current contents of the Zarf state, accepting any changes made to it outside of Zarf.
+func (c *Cluster) SignZarfState(ctx context.Context) error {
+	stored, err := c.loadStoredZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := c.getZarfStateSigningKey(ctx, true)
+	if err != nil {
+		return err
+	}
+	secret := stored.secret
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[ZarfStateSignatureKey] = signZarfStateData(key, stored.data)
+	if c.StateKey != nil {
+		secret.Data[ZarfStateKeySignatureKey] = signZarfStateData(c.StateKey, stored.data)
+	} else {
+		delete(secret.Data, ZarfStateKeySignatureKey)
+	}
+	if stored.legacy {
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, ZarfStateSignedAnnotation, "true")
+	}
+	_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update the signature of the zarf state: %w", err)
+	}
+	if !stored.legacy {
+		return c.markZarfStateResourceSigned(ctx)
+	}
+	return nil
+}
+
+// verifyStoredZarfState checks the signature of the stored state against the signing key.
+// States written before signing was introduced have neither a signature, a signing key nor the signed annotation and
+// are only rejected when requireSignature is set.
+func (c *Cluster) verifyStoredZarfState(ctx context.Context, stored *storedZarfState, requireSignature bool) error {
+	signature, signed := stored.secret.Data[ZarfStateSignatureKey]
+	key, err := c.getZarfStateSigningKey(ctx, false)
+	if err != nil {
+		return err
+	}
+	if key == nil && !signed && stored.marked {
+		return fmt.Errorf("%w: the signature and the signing key secret %s/%s were removed from the zarf state", ErrStateSignatureMismatch, ZarfNamespaceName, ZarfStateSigningKeySecretName)
+	}
+	if key == nil {
+		if signed {
+			return fmt.Errorf("the Zarf state is signed but the signing key secret %s/%s does not exist", ZarfNamespaceName, ZarfStateSigningKeySecretName)
+		}
+		if requireSignature {
+			return ErrStateUnsigned
+		}
+		message.Debug("The Zarf state is not signed, it will be signed the next time it is saved")
+		return nil
+	}
+	if !signed {
+		return fmt.Errorf("%w: the signature was removed from the zarf state", ErrStateSignatureMismatch)
+	}
+	if !hmac.Equal(signature, signZarfStateData(key, stored.data)) {
+		return ErrStateSignatureMismatch
+	}
+	return nil
+}
+
+// getZarfStateSigningKey returns the key used to sign the Zarf state, generating it if it does not exist and create is set.
+// A nil key is returned when the key does not exist and create is not set.
+//
+// The key is stored in the zarf namespace next to the state, so anyone able to change the state can usually read it and
+// sign their changes. Its signature only detects accidental corruption, the operator-held state key detects tampering.
+func (c *Cluster) getZarfStateSigningKey(ctx context.Context, create bool) ([]byte, error) {
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSigningKeySecretName, metav1.GetOptions{})
+	if err == nil {
+		return secret.Data[ZarfStateSigningKeyDataKey], nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to get the zarf state signing key: %w", err)
+	}
+	if !create {
+		return nil, nil
+	}
+
+	key := make([]byte, zarfStateSigningKeyLengthBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ZarfStateSigningKeySecretName,
+			Namespace: ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ZarfStateSigningKeyDataKey: key,
+		},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Create(ctx, secret, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		// Another Zarf process created the key first.
+		return c.getZarfStateSigningKey(ctx, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the zarf state signing key: %w", err)
+	}
+	return key, nil
+}
+
+// ReadStateKey reads the operator-held key the Zarf state is additionally signed with. No key is returned when path is empty.
+func ReadStateKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the state key: %w", err)
+	}
+	key := bytes.TrimSpace(b)
+	if len(key) < zarfStateKeyMinLengthBytes {
+		return nil, fmt.Errorf("the state key %s must be at least %d bytes long", path, zarfStateKeyMinLengthBytes)
+	}
+	return key, nil
+}
+
+// signZarfStateData returns the hex encoded HMAC-SHA256 of the state data.
+func signZarfStateData(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return []byte(hex.EncodeToString(mac.Sum(nil)))
+}
+
 // MergeZarfState merges init options for provided services into the provided state to create a new state struct
 func MergeZarfState(oldState *types.ZarfState, initOptions types.ZarfInitOptions, services []string) (*types.ZarfState, error) {
 	newState := *oldState
@@ -290,6 +628,12 @@ func MergeZarfState(oldState *types.ZarfState, initOptions types.ZarfInitOptions
 	if slices.Contains(services, message.RegistryKey) {
 		// TODO: Replace use of reflections with explicit setting
 		newState.RegistryInfo = helpers.MergeNonZero(newState.RegistryInfo, initOptions.RegistryInfo)
+		// An empty list of additional pull credentials is not given, rather than removing the stored credentials
+		if len(initOptions.RegistryInfo.AdditionalPullCredentials) == 0 {
+			newState.RegistryInfo.AdditionalPullCredentials = oldState.RegistryInfo.AdditionalPullCredentials
+		}
+		// Rotating the registry credentials also revokes the ephemeral credentials of zarf connect tunnels
+		newState.RegistryInfo.TunnelPullEntries = nil
 
 		// Set the new passwords if they should be autogenerated
 		if newState.RegistryInfo.PushPassword == oldState.RegistryInfo.PushPassword && oldState.RegistryInfo.IsInternal() {
@@ -323,10 +667,15 @@ func MergeZarfState(oldState *types.ZarfState, initOptions types.ZarfInitOptions
 		// TODO: Replace use of reflections with explicit setting
 		newState.ArtifactServer = helpers.MergeNonZero(newState.ArtifactServer, initOptions.ArtifactServer)
 
-		// Set an empty token if it should be autogenerated
+		// Set an empty token if it should be autogenerated, otherwise record when a new token was given
 		if newState.ArtifactServer.PushToken == oldState.ArtifactServer.PushToken && oldState.ArtifactServer.IsInternal() {
 			newState.ArtifactServer.PushToken = ""
 		}
+		if newState.ArtifactServer.PushToken != oldState.ArtifactServer.PushToken {
+			if err := newState.ArtifactServer.SetPushToken(newState.ArtifactServer.PushToken, time.Now()); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if slices.Contains(services, message.AgentKey) {
 		agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
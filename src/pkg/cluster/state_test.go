@@ -8,6 +8,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -295,6 +297,45 @@ func TestMergeZarfStateRegistry(t *testing.T) {
 				Secret:       "",
 			},
 		},
+		{
+			name: "additional pull credentials are kept when none are given",
+			oldRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "ghcr.io", Username: "user", Password: "password"}},
+			},
+			expectedRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "ghcr.io", Username: "user", Password: "password"}},
+			},
+		},
+		{
+			name: "additional pull credentials are kept when an empty list is given",
+			oldRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "ghcr.io", Username: "user", Password: "password"}},
+			},
+			initRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{},
+			},
+			expectedRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "ghcr.io", Username: "user", Password: "password"}},
+			},
+		},
+		{
+			name: "additional pull credentials are replaced",
+			oldRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "ghcr.io", Username: "user", Password: "password"}},
+			},
+			initRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "quay.io", Username: "robot", Password: "token"}},
+			},
+			expectedRegistry: types.RegistryInfo{
+				AdditionalPullCredentials: []types.RegistryCredential{{Address: "quay.io", Username: "robot", Password: "token"}},
+			},
+		},
+		{
+			name: "tunnel pull credentials are revoked",
+			oldRegistry: types.RegistryInfo{
+				TunnelPullEntries: map[string]string{"zarf-connect-abc": "zarf-connect-abc:hash"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -311,6 +352,8 @@ func TestMergeZarfStateRegistry(t *testing.T) {
 			require.Equal(t, tt.expectedRegistry.Address, newState.RegistryInfo.Address)
 			require.Equal(t, tt.expectedRegistry.NodePort, newState.RegistryInfo.NodePort)
 			require.Equal(t, tt.expectedRegistry.Secret, newState.RegistryInfo.Secret)
+			require.Equal(t, tt.expectedRegistry.AdditionalPullCredentials, newState.RegistryInfo.AdditionalPullCredentials)
+			require.Equal(t, tt.expectedRegistry.TunnelPullEntries, newState.RegistryInfo.TunnelPullEntries)
 		})
 	}
 }
@@ -399,6 +442,7 @@ func TestMergeZarfStateArtifact(t *testing.T) {
 		initArtifactServer     types.ArtifactServerInfo
 		oldArtifactServer      types.ArtifactServerInfo
 		expectedArtifactServer types.ArtifactServerInfo
+		expectedExpiry         time.Duration
 	}{
 		{
 			name: "username is unmodified",
@@ -452,6 +496,39 @@ func TestMergeZarfStateArtifact(t *testing.T) {
 				Address:      "address",
 			},
 		},
+		{
+			name: "internal server token expiry is cleared until it is generated",
+			initArtifactServer: types.ArtifactServerInfo{
+				PushTokenLifetime: "720h",
+			},
+			oldArtifactServer: types.ArtifactServerInfo{
+				PushToken:          "foobar",
+				Address:            types.ZarfInClusterArtifactServiceURL,
+				PushTokenCreatedAt: &time.Time{},
+				PushTokenExpiresAt: &time.Time{},
+			},
+			expectedArtifactServer: types.ArtifactServerInfo{
+				Address:           types.ZarfInClusterArtifactServiceURL,
+				PushTokenLifetime: "720h",
+			},
+		},
+		{
+			name: "new token expiry is recorded",
+			initArtifactServer: types.ArtifactServerInfo{
+				PushToken:         "token",
+				PushTokenLifetime: "720h",
+			},
+			oldArtifactServer: types.ArtifactServerInfo{
+				PushToken: "foobar",
+				Address:   "address",
+			},
+			expectedArtifactServer: types.ArtifactServerInfo{
+				PushToken:         "token",
+				Address:           "address",
+				PushTokenLifetime: "720h",
+			},
+			expectedExpiry: 720 * time.Hour,
+		},
 		{
 			name: "empty init options not merged",
 			expectedArtifactServer: types.ArtifactServerInfo{
@@ -471,6 +548,17 @@ func TestMergeZarfStateArtifact(t *testing.T) {
 			}
 			newState, err := MergeZarfState(oldState, types.ZarfInitOptions{ArtifactServer: tt.initArtifactServer}, []string{message.ArtifactKey})
 			require.NoError(t, err)
+			// Tokens that are given are marked as created now
+			if newState.ArtifactServer.PushToken != "" && newState.ArtifactServer.PushToken != tt.oldArtifactServer.PushToken {
+				require.WithinDuration(t, time.Now(), *newState.ArtifactServer.PushTokenCreatedAt, 2*time.Second)
+				if tt.expectedExpiry > 0 {
+					require.Equal(t, newState.ArtifactServer.PushTokenCreatedAt.Add(tt.expectedExpiry), *newState.ArtifactServer.PushTokenExpiresAt)
+				} else {
+					require.Nil(t, newState.ArtifactServer.PushTokenExpiresAt)
+				}
+				newState.ArtifactServer.PushTokenCreatedAt = nil
+				newState.ArtifactServer.PushTokenExpiresAt = nil
+			}
 			require.Equal(t, tt.expectedArtifactServer, newState.ArtifactServer)
 		})
 	}
@@ -488,3 +576,252 @@ func TestMergeZarfStateAgent(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, oldState.AgentTLS, newState.AgentTLS)
 }
+
+func TestSanitizeZarfState(t *testing.T) {
+	t.Parallel()
+
+	state := &types.ZarfState{
+		AgentTLS: types.GeneratedPKI{CA: []byte("ca"), Cert: []byte("cert"), Key: []byte("key")},
+		GitServer: types.GitServerInfo{
+			Address:      "https://git.example.com",
+			PushPassword: "git-push",
+			PullPassword: "git-pull",
+		},
+		RegistryInfo: types.RegistryInfo{
+			Address:      "registry.example.com",
+			PushPassword: "registry-push",
+			PullPassword: "registry-pull",
+			Secret:       "registry-secret",
+			S3:           types.RegistryS3Storage{Bucket: "images", AccessKey: "access-key", SecretKey: "secret-key"},
+			AdditionalPullCredentials: []types.RegistryCredential{
+				{Address: "ghcr.io", Username: "user", Password: "ghcr-password"},
+				{Address: "quay.io", Username: "robot", Password: "quay-token"},
+			},
+		},
+		ArtifactServer: types.ArtifactServerInfo{PushToken: "artifact-token"},
+	}
+	c := &Cluster{}
+	copied := *state
+	sanitized := c.sanitizeZarfState(&copied)
+
+	b, err := json.Marshal(sanitized)
+	require.NoError(t, err)
+	for _, secret := range []string{"git-push", "git-pull", "registry-push", "registry-pull", "registry-secret", "access-key", "secret-key", "ghcr-password", "quay-token", "artifact-token"} {
+		require.NotContains(t, string(b), secret)
+	}
+	require.Equal(t, "images", sanitized.RegistryInfo.S3.Bucket)
+	require.Equal(t, []types.RegistryCredential{
+		{Address: "ghcr.io", Username: "user", Password: "**sanitized**"},
+		{Address: "quay.io", Username: "robot", Password: "**sanitized**"},
+	}, sanitized.RegistryInfo.AdditionalPullCredentials)
+	// The state that was printed keeps its passwords
+	require.Equal(t, "ghcr-password", state.RegistryInfo.AdditionalPullCredentials[0].Password)
+	require.Equal(t, "quay-token", state.RegistryInfo.AdditionalPullCredentials[1].Password)
+}
+
+func TestZarfStateSignature(t *testing.T) {
+	t.Parallel()
+
+	legacyStateData, err := json.Marshal(types.ZarfState{Distro: DistroIsK3d})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		modify            func(t *testing.T, cs *fake.Clientset)
+		expectedLoadErr   error
+		expectedVerifyErr error
+	}{
+		{
+			name: "unmodified state",
+		},
+		{
+			name: "modified state",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(context.Background(), ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				secret.Data[ZarfStateDataKey] = []byte(`{"registryInfo":{"address":"evil.example.com"}}`)
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(context.Background(), secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectedLoadErr:   ErrStateSignatureMismatch,
+			expectedVerifyErr: ErrStateSignatureMismatch,
+		},
+		{
+			name: "removed signature",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(context.Background(), ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				delete(secret.Data, ZarfStateSignatureKey)
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(context.Background(), secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectedLoadErr:   ErrStateSignatureMismatch,
+			expectedVerifyErr: ErrStateSignatureMismatch,
+		},
+		{
+			name: "removed signature and signing key",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				err := cs.CoreV1().Secrets(ZarfNamespaceName).Delete(context.Background(), ZarfStateSigningKeySecretName, metav1.DeleteOptions{})
+				require.NoError(t, err)
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(context.Background(), ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				delete(secret.Data, ZarfStateSignatureKey)
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(context.Background(), secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectedLoadErr:   ErrStateSignatureMismatch,
+			expectedVerifyErr: ErrStateSignatureMismatch,
+		},
+		{
+			name: "unsigned state from an earlier version",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				err := cs.CoreV1().Secrets(ZarfNamespaceName).Delete(context.Background(), ZarfStateSigningKeySecretName, metav1.DeleteOptions{})
+				require.NoError(t, err)
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(context.Background(), ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				secret.Data = map[string][]byte{ZarfStateDataKey: legacyStateData}
+				secret.Annotations = nil
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(context.Background(), secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectedVerifyErr: ErrStateUnsigned,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cs := fake.NewSimpleClientset()
+			c := &Cluster{
+				Clientset: cs,
+			}
+			err := c.SaveZarfState(ctx, &types.ZarfState{Distro: DistroIsK3d})
+			require.NoError(t, err)
+			if tt.modify != nil {
+				tt.modify(t, cs)
+			}
+
+			state, err := c.LoadZarfState(ctx)
+			require.ErrorIs(t, err, tt.expectedLoadErr)
+			if tt.expectedLoadErr == nil {
+				require.Equal(t, DistroIsK3d, state.Distro)
+			}
+			err = c.VerifyZarfState(ctx)
+			require.ErrorIs(t, err, tt.expectedVerifyErr)
+
+			// Signing accepts the current contents of the state.
+			err = c.SignZarfState(ctx)
+			require.NoError(t, err)
+			err = c.VerifyZarfState(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestZarfStateKeySignature(t *testing.T) {
+	t.Parallel()
+
+	stateKey := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		name              string
+		modify            func(t *testing.T, cs *fake.Clientset)
+		expectedVerifyErr error
+	}{
+		{
+			name: "unmodified state",
+		},
+		{
+			name: "modified and signed with the in-cluster key",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				ctx := context.Background()
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				secret.Data[ZarfStateDataKey] = []byte(`{"registryInfo":{"address":"evil.example.com"}}`)
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+				err = (&Cluster{Clientset: cs}).SignZarfState(ctx)
+				require.NoError(t, err)
+			},
+			expectedVerifyErr: ErrStateKeyUnsigned,
+		},
+		{
+			name: "modified keeping the state key signature",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				ctx := context.Background()
+				key, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSigningKeySecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				secret, err := cs.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+				require.NoError(t, err)
+				data := []byte(`{"registryInfo":{"address":"evil.example.com"}}`)
+				secret.Data[ZarfStateDataKey] = data
+				secret.Data[ZarfStateSignatureKey] = signZarfStateData(key.Data[ZarfStateSigningKeyDataKey], data)
+				_, err = cs.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectedVerifyErr: ErrStateSignatureMismatch,
+		},
+		{
+			name: "saved without the state key",
+			modify: func(t *testing.T, cs *fake.Clientset) {
+				err := (&Cluster{Clientset: cs}).SaveZarfState(context.Background(), &types.ZarfState{Distro: DistroIsKind})
+				require.NoError(t, err)
+			},
+			expectedVerifyErr: ErrStateKeyUnsigned,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			cs := fake.NewSimpleClientset()
+			c := &Cluster{
+				Clientset: cs,
+				StateKey:  stateKey,
+			}
+			err := c.SaveZarfState(ctx, &types.ZarfState{Distro: DistroIsK3d})
+			require.NoError(t, err)
+			if tt.modify != nil {
+				tt.modify(t, cs)
+			}
+
+			// The in-cluster signature still matches, only the state key detects the change.
+			_, err = c.LoadZarfState(ctx)
+			require.NoError(t, err)
+			err = c.VerifyZarfState(ctx)
+			require.ErrorIs(t, err, tt.expectedVerifyErr)
+
+			// Signing with the state key accepts the current contents of the state.
+			err = c.SignZarfState(ctx)
+			require.NoError(t, err)
+			err = c.VerifyZarfState(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestReadStateKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := ReadStateKey("")
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.key")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789abcdef0123456789abcdef\n"), 0o600))
+	key, err = ReadStateKey(path)
+	require.NoError(t, err)
+	require.Equal(t, []byte("0123456789abcdef0123456789abcdef"), key)
+
+	require.NoError(t, os.WriteFile(path, []byte("short"), 0o600))
+	_, err = ReadStateKey(path)
+	require.EqualError(t, err, fmt.Sprintf("the state key %s must be at least 32 bytes long", path))
+
+	_, err = ReadStateKey(filepath.Join(dir, "missing.key"))
+	require.ErrorContains(t, err, "unable to read the state key")
+}
@@ -0,0 +1,452 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// ZarfState custom resource constants.
+const (
+	ZarfStateGroup                 = "zarf.dev"
+	ZarfStateVersion               = "v1alpha1"
+	ZarfStateKind                  = "ZarfState"
+	ZarfStateResource              = "zarfstates"
+	ZarfStateCRDName               = ZarfStateResource + "." + ZarfStateGroup
+	ZarfStateCredentialsSecretName = "zarf-state-credentials"
+)
+
+// Keys of the sensitive fields of the Zarf state in the credentials secret.
+const (
+	stateKeyAgentCA                = "agent-tls-ca"
+	stateKeyAgentCert              = "agent-tls-cert"
+	stateKeyAgentKey               = "agent-tls-key"
+	stateKeyGitPushPassword        = "git-push-password"
+	stateKeyGitPullPassword        = "git-pull-password"
+	stateKeyRegistryPushPassword   = "registry-push-password"
+	stateKeyRegistryPullPassword   = "registry-pull-password"
+	stateKeyRegistrySecret         = "registry-secret"
+	stateKeyRegistryAdditionalPull = "registry-additional-pull-credentials"
+	stateKeyRegistryTunnelPull     = "registry-tunnel-pull-entries"
+	stateKeyRegistryS3AccessKey    = "registry-s3-access-key"
+	stateKeyRegistryS3SecretKey    = "registry-s3-secret-key"
+	stateKeyArtifactPushToken      = "artifact-push-token"
+)
+
+// The ZarfState custom resource can not be created until its definition is established.
+const (
+	zarfStateCRDEstablishedAttempts   = 30
+	zarfStateCRDEstablishedRetryDelay = time.Second
+)
+
+// ZarfStateGVR is the group, version and resource of the ZarfState custom resource.
+var ZarfStateGVR = schema.GroupVersionResource{Group: ZarfStateGroup, Version: ZarfStateVersion, Resource: ZarfStateResource}
+
+var crdGVR = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+
+// zarfStateCRD returns the definition of the ZarfState custom resource.
+func zarfStateCRD() *apiextensionsv1.CustomResourceDefinition {
+	preserveUnknownFields := true
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ZarfStateCRDName,
+			Labels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: ZarfStateGroup,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   ZarfStateResource,
+				Singular: "zarfstate",
+				Kind:     ZarfStateKind,
+				ListKind: ZarfStateKind + "List",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    ZarfStateVersion,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"spec":   {Type: "object", XPreserveUnknownFields: &preserveUnknownFields},
+								"status": {Type: "object", XPreserveUnknownFields: &preserveUnknownFields},
+							},
+						},
+					},
+					Subresources: &apiextensionsv1.CustomResourceSubresources{
+						Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{Name: "Distro", Type: "string", JSONPath: ".spec.distro"},
+						{Name: "Registry", Type: "string", JSONPath: ".spec.registryInfo.address"},
+						{Name: "Healthy", Type: "boolean", JSONPath: ".status.healthy"},
+						{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// splitZarfState returns a copy of the state without its sensitive fields, which are returned as secret data.
+func splitZarfState(state *types.ZarfState) (types.ZarfState, map[string][]byte, error) {
+	spec := *state
+	data := map[string][]byte{
+		stateKeyAgentCA:              state.AgentTLS.CA,
+		stateKeyAgentCert:            state.AgentTLS.Cert,
+		stateKeyAgentKey:             state.AgentTLS.Key,
+		stateKeyGitPushPassword:      []byte(state.GitServer.PushPassword),
+		stateKeyGitPullPassword:      []byte(state.GitServer.PullPassword),
+		stateKeyRegistryPushPassword: []byte(state.RegistryInfo.PushPassword),
+		stateKeyRegistryPullPassword: []byte(state.RegistryInfo.PullPassword),
+		stateKeyRegistrySecret:       []byte(state.RegistryInfo.Secret),
+		stateKeyRegistryS3AccessKey:  []byte(state.RegistryInfo.S3.AccessKey),
+		stateKeyRegistryS3SecretKey:  []byte(state.RegistryInfo.S3.SecretKey),
+		stateKeyArtifactPushToken:    []byte(state.ArtifactServer.PushToken),
+	}
+	if len(state.RegistryInfo.AdditionalPullCredentials) > 0 {
+		b, err := json.Marshal(state.RegistryInfo.AdditionalPullCredentials)
+		if err != nil {
+			return types.ZarfState{}, nil, err
+		}
+		data[stateKeyRegistryAdditionalPull] = b
+	}
+	if len(state.RegistryInfo.TunnelPullEntries) > 0 {
+		b, err := json.Marshal(state.RegistryInfo.TunnelPullEntries)
+		if err != nil {
+			return types.ZarfState{}, nil, err
+		}
+		data[stateKeyRegistryTunnelPull] = b
+	}
+
+	spec.AgentTLS = types.GeneratedPKI{}
+	spec.GitServer.PushPassword = ""
+	spec.GitServer.PullPassword = ""
+	spec.RegistryInfo.PushPassword = ""
+	spec.RegistryInfo.PullPassword = ""
+	spec.RegistryInfo.Secret = ""
+	spec.RegistryInfo.AdditionalPullCredentials = nil
+	spec.RegistryInfo.TunnelPullEntries = nil
+	spec.RegistryInfo.S3.AccessKey = ""
+	spec.RegistryInfo.S3.SecretKey = ""
+	spec.ArtifactServer.PushToken = ""
+	return spec, data, nil
+}
+
+// joinZarfState fills the sensitive fields of the state from the data of its credentials secret.
+func joinZarfState(state *types.ZarfState, data map[string][]byte) error {
+	// Empty values are read back from the secret as empty rather than nil, which would change the signed state
+	bytes := func(key string) []byte {
+		if len(data[key]) == 0 {
+			return nil
+		}
+		return data[key]
+	}
+	state.AgentTLS = types.GeneratedPKI{
+		CA:   bytes(stateKeyAgentCA),
+		Cert: bytes(stateKeyAgentCert),
+		Key:  bytes(stateKeyAgentKey),
+	}
+	state.GitServer.PushPassword = string(data[stateKeyGitPushPassword])
+	state.GitServer.PullPassword = string(data[stateKeyGitPullPassword])
+	state.RegistryInfo.PushPassword = string(data[stateKeyRegistryPushPassword])
+	state.RegistryInfo.PullPassword = string(data[stateKeyRegistryPullPassword])
+	state.RegistryInfo.Secret = string(data[stateKeyRegistrySecret])
+	state.RegistryInfo.S3.AccessKey = string(data[stateKeyRegistryS3AccessKey])
+	state.RegistryInfo.S3.SecretKey = string(data[stateKeyRegistryS3SecretKey])
+	state.ArtifactServer.PushToken = string(data[stateKeyArtifactPushToken])
+	state.RegistryInfo.AdditionalPullCredentials = nil
+	if b, ok := data[stateKeyRegistryAdditionalPull]; ok {
+		if err := json.Unmarshal(b, &state.RegistryInfo.AdditionalPullCredentials); err != nil {
+			return fmt.Errorf("unable to read the additional pull credentials of the zarf state: %w", err)
+		}
+	}
+	state.RegistryInfo.TunnelPullEntries = nil
+	if b, ok := data[stateKeyRegistryTunnelPull]; ok {
+		if err := json.Unmarshal(b, &state.RegistryInfo.TunnelPullEntries); err != nil {
+			return fmt.Errorf("unable to read the tunnel pull credentials of the zarf state: %w", err)
+		}
+	}
+	return nil
+}
+
+// zarfStateFromResource rebuilds the state from the spec of its custom resource and the data of its credentials secret,
+// returning it along with the serialized state its signature is computed from.
+func zarfStateFromResource(spec any, data map[string][]byte) (*types.ZarfState, []byte, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &types.ZarfState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, nil, err
+	}
+	if err := joinZarfState(state, data); err != nil {
+		return nil, nil, err
+	}
+	b, err = json.Marshal(state)
+	if err != nil {
+		return nil, nil, err
+	}
+	return state, b, nil
+}
+
+// toUnstructuredMap converts v to the map an unstructured object holds.
+func toUnstructuredMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// getZarfStateResource returns the ZarfState custom resource and its credentials secret.
+func (c *Cluster) getZarfStateResource(ctx context.Context) (*unstructured.Unstructured, *corev1.Secret, error) {
+	obj, err := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateCredentialsSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get the credentials of the zarf state: %w", err)
+	}
+	return obj, secret, nil
+}
+
+// ensureZarfStateCRD creates the ZarfState custom resource definition if it does not exist.
+func (c *Cluster) ensureZarfStateCRD(ctx context.Context) error {
+	// The definition exists when the custom resource does, which does not require access to cluster scoped resources
+	_, err := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	_, err = c.Dynamic.Resource(crdGVR).Get(ctx, ZarfStateCRDName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get the zarf state custom resource definition: %w", err)
+	}
+	crd, err := runtime.DefaultUnstructuredConverter.ToUnstructured(zarfStateCRD())
+	if err != nil {
+		return err
+	}
+	_, err = c.Dynamic.Resource(crdGVR).Create(ctx, &unstructured.Unstructured{Object: crd}, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create the zarf state custom resource definition: %w", err)
+	}
+	message.Debugf("Created the %s custom resource definition", ZarfStateCRDName)
+	return nil
+}
+
+// zarfStateClusterRoles are the cluster roles of the Zarf agent components that are granted access to the ZarfState
+// custom resource, each bound by a cluster role binding of the same name with a -binding suffix.
+var zarfStateClusterRoles = []string{"zarf-credential-refresh", "zarf-artifact-token-rotation"}
+
+// DeleteZarfStateResources deletes the ZarfState custom resource definition, and with it the ZarfState, and the cluster
+// roles granted access to it. They are cluster scoped so they are not deleted with the Zarf namespace.
+func (c *Cluster) DeleteZarfStateResources(ctx context.Context) error {
+	for _, name := range zarfStateClusterRoles {
+		err := c.Clientset.RbacV1().ClusterRoleBindings().Delete(ctx, name+"-binding", metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete the cluster role binding %s-binding: %w", name, err)
+		}
+		err = c.Clientset.RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete the cluster role %s: %w", name, err)
+		}
+	}
+	if c.Dynamic == nil {
+		return nil
+	}
+	err := c.Dynamic.Resource(crdGVR).Delete(ctx, ZarfStateCRDName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete the zarf state custom resource definition: %w", err)
+	}
+	return nil
+}
+
+// saveZarfStateResource creates or updates the spec of the ZarfState custom resource, retrying while its definition
+// is not yet established.
+func (c *Cluster) saveZarfStateResource(ctx context.Context, spec map[string]any) (*unstructured.Unstructured, error) {
+	client := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName)
+	var saved *unstructured.Unstructured
+	err := retry.Do(func() error {
+		existing, err := client.Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+			obj.SetAPIVersion(ZarfStateGVR.GroupVersion().String())
+			obj.SetKind(ZarfStateKind)
+			obj.SetName(ZarfStateSecretName)
+			obj.SetNamespace(ZarfNamespaceName)
+			obj.SetLabels(map[string]string{ZarfManagedByLabel: "zarf"})
+			obj.SetAnnotations(map[string]string{ZarfStateSignedAnnotation: "true"})
+			saved, err = client.Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		existing.Object["spec"] = spec
+		setSignedAnnotation(existing)
+		saved, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+		return err
+	}, retry.Context(ctx), retry.Attempts(zarfStateCRDEstablishedAttempts), retry.Delay(zarfStateCRDEstablishedRetryDelay),
+		retry.DelayType(retry.FixedDelay), retry.RetryIf(kerrors.IsNotFound), retry.LastErrorOnly(true))
+	if err != nil {
+		return nil, fmt.Errorf("unable to save the zarf state custom resource: %w", err)
+	}
+	return saved, nil
+}
+
+// markZarfStateResourceSigned records on the ZarfState custom resource that the state has been signed.
+func (c *Cluster) markZarfStateResourceSigned(ctx context.Context) error {
+	client := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName)
+	obj, err := client.Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if obj.GetAnnotations()[ZarfStateSignedAnnotation] == "true" {
+		return nil
+	}
+	setSignedAnnotation(obj)
+	_, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to mark the zarf state custom resource as signed: %w", err)
+	}
+	return nil
+}
+
+// setSignedAnnotation sets the annotation recording that the state has been signed, keeping any other annotations.
+func setSignedAnnotation(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ZarfStateSignedAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+}
+
+// UpdateZarfStateStatus refreshes the health of the workloads in the Zarf namespace in the status of the ZarfState
+// custom resource. It does nothing when the state is still stored in the legacy secret.
+func (c *Cluster) UpdateZarfStateStatus(ctx context.Context) error {
+	if c.Dynamic == nil {
+		return nil
+	}
+	client := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName)
+	obj, err := client.Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	status, err := c.zarfStateStatus(ctx)
+	if err != nil {
+		return err
+	}
+	obj.Object["status"], err = toUnstructuredMap(status)
+	if err != nil {
+		return err
+	}
+	if _, err := client.UpdateStatus(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("unable to update the status of the zarf state: %w", err)
+	}
+	return nil
+}
+
+// zarfStateStatus returns the readiness of the deployments and stateful sets in the Zarf namespace.
+func (c *Cluster) zarfStateStatus(ctx context.Context) (types.ZarfStateStatus, error) {
+	status := types.ZarfStateStatus{
+		Healthy:    true,
+		Components: []types.ComponentHealth{},
+		UpdatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	add := func(name, kind string, replicas *int32, readyReplicas int32) {
+		desired := int32(1)
+		if replicas != nil {
+			desired = *replicas
+		}
+		health := types.ComponentHealth{
+			Name:          name,
+			Kind:          kind,
+			Ready:         readyReplicas >= desired,
+			ReadyReplicas: readyReplicas,
+			Replicas:      desired,
+		}
+		status.Healthy = status.Healthy && health.Ready
+		status.Components = append(status.Components, health)
+	}
+
+	deployments, err := c.Clientset.AppsV1().Deployments(ZarfNamespaceName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return types.ZarfStateStatus{}, fmt.Errorf("unable to list the deployments in the zarf namespace: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		add(deployment.Name, "Deployment", deployment.Spec.Replicas, deployment.Status.ReadyReplicas)
+	}
+	statefulSets, err := c.Clientset.AppsV1().StatefulSets(ZarfNamespaceName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return types.ZarfStateStatus{}, fmt.Errorf("unable to list the stateful sets in the zarf namespace: %w", err)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		add(statefulSet.Name, "StatefulSet", statefulSet.Spec.Replicas, statefulSet.Status.ReadyReplicas)
+	}
+	return status, nil
+}
+
+// credentialsSecret returns the secret holding the sensitive fields of the state, owned by its custom resource.
+func credentialsSecret(owner *unstructured.Unstructured, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ZarfStateCredentialsSecretName,
+			Namespace: ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: owner.GetAPIVersion(),
+					Kind:       owner.GetKind(),
+					Name:       owner.GetName(),
+					UID:        owner.GetUID(),
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
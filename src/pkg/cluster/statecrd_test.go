@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func newZarfStateTestCluster(objects ...runtime.Object) *Cluster {
+	return &Cluster{
+		Clientset: fake.NewSimpleClientset(objects...),
+		Dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			ZarfStateGVR: ZarfStateKind + "List",
+			crdGVR:       "CustomResourceDefinitionList",
+		}),
+	}
+}
+
+func testZarfState() *types.ZarfState {
+	return &types.ZarfState{
+		Distro: DistroIsK3d,
+		AgentTLS: types.GeneratedPKI{
+			CA:   []byte("ca"),
+			Cert: []byte("cert"),
+			Key:  []byte("key"),
+		},
+		GitServer: types.GitServerInfo{
+			Address:      "https://git.example.com",
+			PushUsername: "push-user",
+			PushPassword: "git-push-password",
+			PullUsername: "pull-user",
+			PullPassword: "git-pull-password",
+		},
+		RegistryInfo: types.RegistryInfo{
+			Address:      "registry.example.com",
+			PushUsername: "push-user",
+			PushPassword: "registry-push-password",
+			PullUsername: "pull-user",
+			PullPassword: "registry-pull-password",
+			Secret:       "registry-secret",
+		},
+		ArtifactServer: types.ArtifactServerInfo{
+			Address:   "https://artifacts.example.com",
+			PushToken: "artifact-push-token",
+		},
+	}
+}
+
+func TestZarfStateResource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	replicas := int32(1)
+	c := newZarfStateTestCluster(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-hook", Namespace: ZarfNamespaceName},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "zarf-gitea", Namespace: ZarfNamespaceName},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	})
+	state := testZarfState()
+	err := c.SaveZarfState(ctx, state)
+	require.NoError(t, err)
+
+	_, err = c.Dynamic.Resource(crdGVR).Get(ctx, ZarfStateCRDName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// The sensitive fields are only stored in the credentials secret
+	obj, err := c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	spec, err := json.Marshal(obj.Object["spec"])
+	require.NoError(t, err)
+	for _, sensitive := range []string{"git-push-password", "git-pull-password", "registry-push-password", "registry-pull-password", "registry-secret", "artifact-push-token"} {
+		require.NotContains(t, string(spec), sensitive)
+	}
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateCredentialsSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "registry-push-password", string(secret.Data[stateKeyRegistryPushPassword]))
+	require.Equal(t, ZarfStateKind, secret.OwnerReferences[0].Kind)
+
+	loaded, err := c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+	require.NoError(t, c.VerifyZarfState(ctx))
+
+	// The gitea stateful set is not ready
+	obj, err = c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	b, err := json.Marshal(obj.Object["status"])
+	require.NoError(t, err)
+	status := types.ZarfStateStatus{}
+	require.NoError(t, json.Unmarshal(b, &status))
+	require.False(t, status.Healthy)
+	require.ElementsMatch(t, []types.ComponentHealth{
+		{Name: "agent-hook", Kind: "Deployment", Ready: true, ReadyReplicas: 1, Replicas: 1},
+		{Name: "zarf-gitea", Kind: "StatefulSet", Ready: false, ReadyReplicas: 0, Replicas: 1},
+	}, status.Components)
+
+	// Changes to the custom resource made outside of Zarf are detected
+	obj.Object["spec"].(map[string]any)["registryInfo"].(map[string]any)["address"] = "evil.example.com"
+	_, err = c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = c.LoadZarfState(ctx)
+	require.ErrorIs(t, err, ErrStateSignatureMismatch)
+	require.NoError(t, c.SignZarfState(ctx))
+	loaded, err = c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "evil.example.com", loaded.RegistryInfo.Address)
+
+	// Removing both the signature and the signing key does not turn the state back into an unsigned one
+	obj, err = c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "true", obj.GetAnnotations()[ZarfStateSignedAnnotation])
+	secret, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateCredentialsSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	delete(secret.Data, ZarfStateSignatureKey)
+	_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Delete(ctx, ZarfStateSigningKeySecretName, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	_, err = c.LoadZarfState(ctx)
+	require.ErrorIs(t, err, ErrStateSignatureMismatch)
+}
+
+func TestZarfStateResourceMigration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := testZarfState()
+	legacy := &Cluster{Clientset: fake.NewSimpleClientset()}
+	err := legacy.SaveZarfState(ctx, state)
+	require.NoError(t, err)
+	legacySecret, err := legacy.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	signingKey, err := legacy.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSigningKeySecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	c := newZarfStateTestCluster(legacySecret.DeepCopy(), signingKey.DeepCopy())
+
+	// The state is read from the legacy secret until it is saved again
+	loaded, err := c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+
+	err = c.SaveZarfState(ctx, loaded)
+	require.NoError(t, err)
+	_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+	_, err = c.Dynamic.Resource(ZarfStateGVR).Namespace(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	loaded, err = c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+	require.NoError(t, c.VerifyZarfState(ctx))
+}
+
+func TestDeleteZarfStateResources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newZarfStateTestCluster(
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "zarf-credential-refresh"}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "zarf-credential-refresh-binding"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	)
+	require.NoError(t, c.SaveZarfState(ctx, testZarfState()))
+
+	require.NoError(t, c.DeleteZarfStateResources(ctx))
+	_, err := c.Dynamic.Resource(crdGVR).Get(ctx, ZarfStateCRDName, metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+	_, err = c.Clientset.RbacV1().ClusterRoles().Get(ctx, "zarf-credential-refresh", metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+	_, err = c.Clientset.RbacV1().ClusterRoleBindings().Get(ctx, "zarf-credential-refresh-binding", metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+	_, err = c.Clientset.RbacV1().ClusterRoles().Get(ctx, "other", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// Deleting again does nothing
+	require.NoError(t, c.DeleteZarfStateResources(ctx))
+}
+
+func TestSplitZarfState(t *testing.T) {
+	t.Parallel()
+
+	state := testZarfState()
+	state.RegistryInfo.AdditionalPullCredentials = []types.RegistryCredential{}
+	state.RegistryInfo.TunnelPullEntries = map[string]string{"zarf-connect-abc": "zarf-connect-abc:hash"}
+	state.RegistryInfo.Replicas = 3
+	state.RegistryInfo.S3 = types.RegistryS3Storage{Bucket: "zarf-registry", Region: "us-east-1", AccessKey: "access-key", SecretKey: "secret-key"}
+	spec, data, err := splitZarfState(state)
+	require.NoError(t, err)
+	require.Equal(t, types.GeneratedPKI{}, spec.AgentTLS)
+	require.Empty(t, spec.RegistryInfo.PushPassword)
+	require.Nil(t, spec.RegistryInfo.TunnelPullEntries)
+	require.Equal(t, types.RegistryS3Storage{Bucket: "zarf-registry", Region: "us-east-1"}, spec.RegistryInfo.S3)
+
+	specMap, err := toUnstructuredMap(spec)
+	require.NoError(t, err)
+	joined, _, err := zarfStateFromResource(specMap, data)
+	require.NoError(t, err)
+	state.RegistryInfo.AdditionalPullCredentials = nil
+	require.Equal(t, state, joined)
+
+	// Secrets read back from the cluster hold empty values rather than nil ones
+	empty := &types.ZarfState{Distro: DistroIsK3d}
+	spec, data, err = splitZarfState(empty)
+	require.NoError(t, err)
+	for k, v := range data {
+		if v == nil {
+			data[k] = []byte{}
+		}
+	}
+	specMap, err = toUnstructuredMap(spec)
+	require.NoError(t, err)
+	joined, _, err = zarfStateFromResource(specMap, data)
+	require.NoError(t, err)
+	require.Equal(t, empty, joined)
+}
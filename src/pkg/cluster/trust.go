@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/trust"
+)
+
+const (
+	// TrustRootName is the name of the ConfigMap in the Zarf namespace that holds the trust root of the package
+	// signing keys, and of the init package component that distributes it.
+	TrustRootName = "zarf-trust-root"
+	// TrustRootDataKey is the key of the trust root in the data of the trust root ConfigMap.
+	TrustRootDataKey = "root.json"
+	// TrustRootVersionAnnotation records on the Zarf namespace the version of the trust root the cluster trusts, so a
+	// trust root that is removed or rolled back by a user who can only change the Zarf namespace is detected.
+	TrustRootVersionAnnotation = "zarf.dev/trust-root-version"
+)
+
+// ErrTrustRootNotPinned is returned when a cluster without a trust root is given one without pinning it.
+var ErrTrustRootNotPinned = errors.New("the cluster has no trust root yet, pin the initial trust root with --trust-root")
+
+// GetTrustRoot returns the trust root of the package signing keys of the cluster.
+func (c *Cluster) GetTrustRoot(ctx context.Context) (*trust.Root, error) {
+	cm, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Get(ctx, TrustRootName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return trust.Parse([]byte(cm.Data[TrustRootDataKey]))
+}
+
+// LoadTrustRoot returns the trust root of the cluster, or nil when the cluster has none. It fails when the trust root
+// does not match the version recorded on the Zarf namespace, as it was then removed, rolled back or replaced by someone
+// who can change the ConfigMap but not the namespace.
+func (c *Cluster) LoadTrustRoot(ctx context.Context) (*trust.Root, error) {
+	version, err := c.trustRootVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root, err := c.GetTrustRoot(ctx)
+	if kerrors.IsNotFound(err) && version == 0 {
+		return nil, nil
+	}
+	if kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("the cluster trusted version %d of the trust root but the %s/%s ConfigMap was removed, remove the %s annotation from the %s namespace to deploy a new trust root",
+			version, ZarfNamespaceName, TrustRootName, TrustRootVersionAnnotation, ZarfNamespaceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if root.Signed.Version != version {
+		return nil, fmt.Errorf("the cluster trusted version %d of the trust root but the %s/%s ConfigMap holds version %d, delete it and remove the %s annotation from the %s namespace to deploy a new trust root",
+			version, ZarfNamespaceName, TrustRootName, root.Signed.Version, TrustRootVersionAnnotation, ZarfNamespaceName)
+	}
+	return root, nil
+}
+
+// trustRootVersion returns the version of the trust root recorded on the Zarf namespace, 0 when none is recorded.
+func (c *Cluster) trustRootVersion(ctx context.Context) (int, error) {
+	ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, ZarfNamespaceName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	value, ok := ns.Annotations[TrustRootVersionAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation on the %s namespace: %w", TrustRootVersionAnnotation, ZarfNamespaceName, err)
+	}
+	return version, nil
+}
+
+// UpdateTrustRoot replaces the trust root of the cluster with the root, once a threshold of the root keys of the
+// current trust root signed it. A cluster without a trust root only accepts the pinned root, or a root the pinned root
+// can be updated to, so a package can not bring its own first trust root.
+func (c *Cluster) UpdateTrustRoot(ctx context.Context, root, pinned *trust.Root) error {
+	now := time.Now()
+	trusted, err := c.LoadTrustRoot(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case trusted != nil && root.Equal(trusted):
+		message.Debugf("The cluster already trusts version %d of the trust root", root.Signed.Version)
+		return nil
+	case trusted != nil:
+		err = root.VerifyUpdate(trusted, now)
+	case pinned == nil:
+		err = ErrTrustRootNotPinned
+	case root.Equal(pinned):
+		err = root.Verify(now)
+	default:
+		err = root.VerifyUpdate(pinned, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	b, err := root.Marshal()
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TrustRootName,
+			Namespace: ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+		},
+		Data: map[string]string{TrustRootDataKey: string(b)},
+	}
+	if trusted != nil {
+		_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Update(ctx, cm, metav1.UpdateOptions{})
+	} else {
+		_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update the trust root: %w", err)
+	}
+	return c.setTrustRootVersion(ctx, root.Signed.Version)
+}
+
+// setTrustRootVersion records the version of the trust root the cluster trusts on the Zarf namespace.
+func (c *Cluster) setTrustRootVersion(ctx context.Context, version int) error {
+	ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, ZarfNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to record the version of the trust root: %w", err)
+	}
+	metav1.SetMetaDataAnnotation(&ns.ObjectMeta, TrustRootVersionAnnotation, strconv.Itoa(version))
+	_, err = c.Clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to record the version of the trust root: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/pkg/trust"
+)
+
+func TestUpdateTrustRoot(t *testing.T) {
+	t.Parallel()
+
+	type key struct {
+		public  []byte
+		private ed25519.PrivateKey
+	}
+	newKey := func() key {
+		pub, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(t, err)
+		return key{public: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), private: private}
+	}
+	newRoot := func(version int, rootKey key, signers ...key) *trust.Root {
+		root, err := trust.New(version, time.Now().Add(time.Hour), [][]byte{rootKey.public}, 1, [][]byte{newKey().public})
+		require.NoError(t, err)
+		msg, err := root.SignedBytes()
+		require.NoError(t, err)
+		for _, signer := range signers {
+			// Keys that are not in the root can only sign it by hand
+			id, err := trust.KeyID(signer.public)
+			require.NoError(t, err)
+			root.Signatures = append(root.Signatures, trust.Signature{KeyID: id, Sig: base64.StdEncoding.EncodeToString(ed25519.Sign(signer.private, msg))})
+		}
+		return root
+	}
+
+	ctx := context.Background()
+	cs := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ZarfNamespaceName}})
+	c := &Cluster{Clientset: cs}
+	_, err := c.GetTrustRoot(ctx)
+	require.Error(t, err)
+	root, err := c.LoadTrustRoot(ctx)
+	require.NoError(t, err)
+	require.Nil(t, root)
+
+	keyA, keyB := newKey(), newKey()
+
+	// The first trust root has to be pinned and signed by its own root keys
+	first := newRoot(1, keyA, keyA)
+	require.ErrorIs(t, c.UpdateTrustRoot(ctx, first, nil), ErrTrustRootNotPinned)
+	require.EqualError(t, c.UpdateTrustRoot(ctx, newRoot(1, keyB, keyB), first), "trust root version 1 is not newer than the trusted version 1")
+	require.EqualError(t, c.UpdateTrustRoot(ctx, newRoot(1, keyA), newRoot(1, keyA)), "trust root version 1 is not signed by its own root keys: 0 of the 1 required signatures are valid")
+	require.NoError(t, c.UpdateTrustRoot(ctx, first, first))
+	require.NoError(t, c.UpdateTrustRoot(ctx, first, nil))
+
+	// Rotations have to be signed by the root keys of the trusted root
+	require.EqualError(t, c.UpdateTrustRoot(ctx, newRoot(2, keyB, keyB), nil), "trust root version 2 is not signed by the root keys of the trusted version 1: 0 of the 1 required signatures are valid")
+	rotated := newRoot(2, keyB, keyB, keyA)
+	require.NoError(t, c.UpdateTrustRoot(ctx, rotated, nil))
+	require.EqualError(t, c.UpdateTrustRoot(ctx, newRoot(1, keyA, keyA), nil), "trust root version 1 is not newer than the trusted version 2")
+
+	trusted, err := c.LoadTrustRoot(ctx)
+	require.NoError(t, err)
+	require.True(t, trusted.Equal(rotated))
+	require.Equal(t, rotated.PackageKeys(), trusted.PackageKeys())
+
+	// A trust root replaced in the ConfigMap alone is not trusted
+	b, err := newRoot(3, keyB, keyB).Marshal()
+	require.NoError(t, err)
+	cm, err := cs.CoreV1().ConfigMaps(ZarfNamespaceName).Get(ctx, TrustRootName, metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data[TrustRootDataKey] = string(b)
+	_, err = cs.CoreV1().ConfigMaps(ZarfNamespaceName).Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = c.LoadTrustRoot(ctx)
+	require.EqualError(t, err, "the cluster trusted version 2 of the trust root but the zarf/zarf-trust-root ConfigMap holds version 3, delete it and remove the zarf.dev/trust-root-version annotation from the zarf namespace to deploy a new trust root")
+
+	// A removed trust root is not replaced by a new first trust root
+	err = cs.CoreV1().ConfigMaps(ZarfNamespaceName).Delete(ctx, TrustRootName, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	_, err = c.LoadTrustRoot(ctx)
+	require.EqualError(t, err, "the cluster trusted version 2 of the trust root but the zarf/zarf-trust-root ConfigMap was removed, remove the zarf.dev/trust-root-version annotation from the zarf namespace to deploy a new trust root")
+	require.EqualError(t, c.UpdateTrustRoot(ctx, first, first), "the cluster trusted version 2 of the trust root but the zarf/zarf-trust-root ConfigMap was removed, remove the zarf.dev/trust-root-version annotation from the zarf namespace to deploy a new trust root")
+
+	// Removing the annotation, which needs access to the namespace itself, lets a new pinned trust root be deployed
+	ns, err := cs.CoreV1().Namespaces().Get(ctx, ZarfNamespaceName, metav1.GetOptions{})
+	require.NoError(t, err)
+	delete(ns.Annotations, TrustRootVersionAnnotation)
+	_, err = cs.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, c.UpdateTrustRoot(ctx, first, first))
+}
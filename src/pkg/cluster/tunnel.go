@@ -6,16 +6,23 @@ package cluster
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -23,6 +30,7 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/internal/faultinject"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -43,6 +51,9 @@ const (
 	ZarfRegistryPort  = 5000
 	ZarfGitServerName = "zarf-gitea-http"
 	ZarfGitServerPort = 3000
+
+	ZarfPackageCacheName = "zarf-package-cache"
+	ZarfPackageCachePort = 5000
 )
 
 // TunnelInfo is a struct that contains the necessary info to create a new Tunnel
@@ -52,7 +63,14 @@ type TunnelInfo struct {
 	Namespace    string
 	ResourceType string
 	ResourceName string
-	urlSuffix    string
+	// Label selector of the pods to connect to instead of the named pod, resolved again whenever the tunnel is
+	// established so the tunnel follows the pods when they are replaced
+	Selector string
+	// Protocol of the remote port, UDP ports are reached through a relay pod
+	Protocol corev1.Protocol
+	// TLS config to serve the local port with HTTPS, the local port serves the plain port forward when nil
+	TLSConfig *tls.Config
+	urlSuffix string
 }
 
 // ListConnections will return a list of all Zarf connect matches found in the cluster.
@@ -116,6 +134,77 @@ func (c *Cluster) NewTargetTunnelInfo(ctx context.Context, target string) (Tunne
 	return zt, err
 }
 
+// NewDefinitionTunnelInfo returns a new TunnelInfo object for a connect target declared in the config file. A
+// definition without a resource name or selector refines the built-in target or the zarf.dev/connect-name label of
+// the same name, its fields that are set take precedence over those found in the cluster.
+func (c *Cluster) NewDefinitionTunnelInfo(ctx context.Context, target string, def types.ConnectDefinition) (TunnelInfo, error) {
+	zt := TunnelInfo{
+		Namespace:    ZarfNamespaceName,
+		ResourceType: SvcResource,
+	}
+	if def.Name == "" && def.Selector == "" {
+		var err error
+		zt, err = c.NewTargetTunnelInfo(ctx, target)
+		if err != nil {
+			return TunnelInfo{}, err
+		}
+	}
+	if def.Namespace != "" {
+		zt.Namespace = def.Namespace
+	}
+	if def.Type != "" {
+		zt.ResourceType = def.Type
+	}
+	if def.Name != "" {
+		zt.ResourceName = def.Name
+	}
+	if def.Selector != "" {
+		zt.Selector = def.Selector
+		zt.ResourceName = ""
+	}
+	if def.RemotePort != 0 {
+		zt.RemotePort = def.RemotePort
+	}
+	if def.LocalPort != 0 {
+		zt.LocalPort = def.LocalPort
+	}
+	if def.URL != "" {
+		zt.urlSuffix = def.URL
+	}
+	if def.Protocol != "" {
+		switch protocol := corev1.Protocol(strings.ToUpper(def.Protocol)); protocol {
+		case corev1.ProtocolTCP, corev1.ProtocolUDP:
+			zt.Protocol = protocol
+		default:
+			return TunnelInfo{}, fmt.Errorf("invalid protocol %q of the connect definition %s, must be tcp or udp", def.Protocol, target)
+		}
+	}
+	if zt.ResourceName == "" && zt.Selector == "" {
+		return TunnelInfo{}, fmt.Errorf("the connect definition %s has no resource name or selector", target)
+	}
+	if zt.RemotePort < 1 {
+		return TunnelInfo{}, fmt.Errorf("the connect definition %s has no remote port", target)
+	}
+	return zt, nil
+}
+
+// ParseConnectTarget splits a connect target of the form TARGET[:LOCAL_PORT] into its name and local port, the port is
+// zero when it is not given.
+func ParseConnectTarget(target string) (string, int, error) {
+	name, port, ok := strings.Cut(target, ":")
+	if !ok {
+		return target, 0, nil
+	}
+	localPort, err := strconv.Atoi(port)
+	if err != nil || localPort < 1 || localPort > 65535 {
+		return "", 0, fmt.Errorf("invalid connect target %q, the local port must be a number between 1 and 65535", target)
+	}
+	if name == "" {
+		return "", 0, fmt.Errorf("invalid connect target %q, the name is empty", target)
+	}
+	return name, localPort, nil
+}
+
 // Connect will establish a tunnel to the specified target.
 func (c *Cluster) Connect(ctx context.Context, target string) (*Tunnel, error) {
 	zt, err := c.NewTargetTunnelInfo(ctx, target)
@@ -131,6 +220,24 @@ func (c *Cluster) ConnectTunnelInfo(ctx context.Context, zt TunnelInfo) (*Tunnel
 	if err != nil {
 		return nil, err
 	}
+	if zt.Selector != "" {
+		if zt.ResourceType != PodResource {
+			return nil, fmt.Errorf("a label selector can only be used to connect to pods, not to a %s", zt.ResourceType)
+		}
+		if zt.Protocol == corev1.ProtocolUDP {
+			return nil, errors.New("a label selector can not be used to connect to UDP ports")
+		}
+		if _, err := labels.Parse(zt.Selector); err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", zt.Selector, err)
+		}
+		tunnel.selector = zt.Selector
+	}
+	if zt.Protocol == corev1.ProtocolUDP {
+		tunnel.protocol = corev1.ProtocolUDP
+	}
+	if zt.TLSConfig != nil {
+		tunnel.EnableLocalTLS(zt.TLSConfig)
+	}
 
 	_, err = tunnel.Connect(ctx)
 	if err != nil {
@@ -152,15 +259,14 @@ func (c *Cluster) ConnectToZarfRegistryEndpoint(ctx context.Context, registryInf
 			return "", tunnel, err
 		}
 	} else {
-		serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
-		if err != nil {
+		svc, err := c.ResolveServiceAddress(ctx, registryInfo.Address)
+		if err != nil && !errors.Is(err, ErrServiceNotFound) {
 			return "", nil, err
 		}
-		svc, port, err := serviceInfoFromNodePortURL(serviceList.Items, registryInfo.Address)
 
-		// If this is a service (no error getting svcInfo), create a port-forward tunnel to that resource
+		// If this is a service in the cluster, create a port-forward tunnel to that resource
 		if err == nil {
-			if tunnel, err = c.NewTunnel(svc.Namespace, SvcResource, svc.Name, "", 0, port); err != nil {
+			if tunnel, err = c.NewTunnel(svc.Namespace, SvcResource, svc.Name, "", 0, svc.Port); err != nil {
 				return "", tunnel, err
 			}
 		}
@@ -206,6 +312,11 @@ func (c *Cluster) checkForZarfConnectLabel(ctx context.Context, name string) (Tu
 		zt.Namespace = svc.Namespace
 		// Only support a service with a single port.
 		zt.RemotePort = svc.Spec.Ports[0].TargetPort.IntValue()
+		// UDP ports are relayed to the service rather than port forwarded to one of its pods
+		if svc.Spec.Ports[0].Protocol == corev1.ProtocolUDP {
+			zt.Protocol = corev1.ProtocolUDP
+			zt.RemotePort = int(svc.Spec.Ports[0].Port)
+		}
 		// if targetPort == 0, look for Port (which is required)
 		if zt.RemotePort == 0 {
 			// TODO: Need a check for if container port is not found
@@ -295,9 +406,19 @@ var globalMutex sync.Mutex
 const (
 	PodResource = "pod"
 	SvcResource = "svc"
+
+	// Lost tunnels are re-established with exponential backoff and jitter, giving up after the attempts
+	reconnectAttempts = 10
+	reconnectDelay    = time.Second
+	reconnectMaxDelay = 30 * time.Second
 )
 
 // Tunnel is the main struct that configures and manages port forwarding tunnels to Kubernetes resources.
+//
+// A tunnel is created with NewTunnel or ConnectTunnelInfo, and can be embedded in other programs: Run connects it and
+// keeps it open until its context is done, while Connect, Wait and Close manage each step. The local port is picked
+// when it is zero, LocalPort and OnReady report it. Tunnels log to the default slog logger unless SetLogger is called,
+// they never print to the terminal.
 type Tunnel struct {
 	clientset    kubernetes.Interface
 	restConfig   *rest.Config
@@ -307,10 +428,29 @@ type Tunnel struct {
 	namespace    string
 	resourceType string
 	resourceName string
+	selector     string
 	urlSuffix    string
+	protocol     corev1.Protocol
 	stopChan     chan struct{}
 	readyChan    chan struct{}
 	errChan      chan error
+	// UDP tunnels relay datagrams from the local port through a TCP tunnel to a relay pod
+	relay      *Tunnel
+	relayPod   string
+	packetConn net.PacketConn
+	// Tunnels with local TLS serve HTTPS on the TLS port in front of the port forward
+	tlsConfig   *tls.Config
+	tlsListener net.Listener
+	tlsPort     int
+	// Called with the endpoint of the tunnel whenever it is connected or reconnected
+	onReady   func(TunnelEndpoint)
+	logger    *slog.Logger
+	done      chan struct{}
+	closeOnce sync.Once
+	// Reconnect re-establishes the port forward with establish and waits reconnectDelay between attempts unless these
+	// are set
+	reestablish    func(context.Context) (string, error)
+	reconnectDelay time.Duration
 }
 
 // NewTunnel will create a new Tunnel struct.
@@ -327,13 +467,73 @@ func (c *Cluster) NewTunnel(namespace, resourceType, resourceName, urlSuffix str
 		resourceType: resourceType,
 		resourceName: resourceName,
 		urlSuffix:    urlSuffix,
+		protocol:     corev1.ProtocolTCP,
 		stopChan:     make(chan struct{}, 1),
 		readyChan:    make(chan struct{}, 1),
+		logger:       slog.Default(),
+		done:         make(chan struct{}),
 	}, nil
 }
 
+// SetLogger sets the logger the tunnel logs to instead of the default slog logger. It must be called before Connect.
+func (tunnel *Tunnel) SetLogger(logger *slog.Logger) {
+	tunnel.logger = logger
+}
+
+// OnReady sets a function that is called with the endpoint of the tunnel whenever it is connected or reconnected, e.g.
+// to learn the local port that was picked. It must be called before Connect.
+func (tunnel *Tunnel) OnReady(fn func(TunnelEndpoint)) {
+	tunnel.onReady = fn
+}
+
+// Run connects the tunnel and keeps it open until the context is done, returning nil, or until the tunnel is lost,
+// returning the error it was lost with. The tunnel is closed when Run returns.
+func (tunnel *Tunnel) Run(ctx context.Context) error {
+	if _, err := tunnel.Connect(ctx); err != nil {
+		return err
+	}
+	defer tunnel.Close()
+	return tunnel.Wait(ctx)
+}
+
+// Wait blocks until the context is done or the tunnel is closed, returning nil, or until the tunnel is lost, returning
+// the error it was lost with. The tunnel is closed when the context is done, a lost tunnel can be re-established with
+// Reconnect before waiting again.
+func (tunnel *Tunnel) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		tunnel.Close()
+		return nil
+	case <-tunnel.Done():
+		return nil
+	case err := <-tunnel.ErrChan():
+		if err == nil {
+			return errors.New("the port forward stopped")
+		}
+		return err
+	}
+}
+
+// Done returns a channel that is closed when the tunnel is closed.
+func (tunnel *Tunnel) Done() <-chan struct{} {
+	return tunnel.done
+}
+
+// LocalPort returns the local port of the tunnel, the port picked for it when zero was requested. It is the port
+// served with HTTPS for tunnels with local TLS, and zero until the tunnel is connected when zero was requested.
+func (tunnel *Tunnel) LocalPort() int {
+	if tunnel.tlsListener != nil {
+		return tunnel.tlsPort
+	}
+	return tunnel.localPort
+}
+
 // Wrap takes a function that returns an error and wraps it to check for tunnel errors as well.
 func (tunnel *Tunnel) Wrap(function func() error) error {
+	if err := faultinject.Trigger(faultinject.TunnelDrop); err != nil {
+		return fmt.Errorf("lost connection to the tunnel: %w", err)
+	}
+
 	var err error
 	funcErrChan := make(chan error)
 
@@ -351,8 +551,20 @@ func (tunnel *Tunnel) Wrap(function func() error) error {
 
 // Connect will establish a tunnel to the specified target.
 func (tunnel *Tunnel) Connect(ctx context.Context) (string, error) {
+	establish := tunnel.establish
+	if tunnel.protocol == corev1.ProtocolUDP {
+		establish = tunnel.establishUDP
+	}
+	// The requested local port is served with TLS in front of a port forward to any open port
+	tlsPort := tunnel.localPort
+	if tunnel.tlsConfig != nil {
+		if tunnel.protocol == corev1.ProtocolUDP {
+			return "", errors.New("local TLS is not supported for UDP tunnels")
+		}
+		tunnel.localPort = 0
+	}
 	url, err := retry.DoWithData(func() (string, error) {
-		url, err := tunnel.establish(ctx)
+		url, err := establish(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -361,14 +573,111 @@ func (tunnel *Tunnel) Connect(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if tunnel.tlsConfig != nil {
+		if err := tunnel.serveLocalTLS(tlsPort); err != nil {
+			tunnel.Close()
+			return "", err
+		}
+		url = tunnel.FullURL()
+	}
+	tunnel.ready()
 	return url, nil
 }
 
+// Reconnect re-establishes the lost port forward of a connected tunnel on the same local port, retrying with exponential
+// backoff and jitter, e.g. while the pods behind a service are replaced during a rolling restart. The tunnel is
+// re-established to any ready pod of a service, and ErrChan returns the channel of the new port forward once it
+// succeeds.
+func (tunnel *Tunnel) Reconnect(ctx context.Context) error {
+	// The relay pod of a UDP tunnel sends datagrams to the service, so only its TCP tunnel is re-established
+	if tunnel.relay != nil {
+		if err := tunnel.relay.Reconnect(ctx); err != nil {
+			return err
+		}
+		tunnel.errChan = tunnel.relay.errChan
+		tunnel.ready()
+		return nil
+	}
+	establish := tunnel.establish
+	if tunnel.reestablish != nil {
+		establish = tunnel.reestablish
+	}
+	delay := reconnectDelay
+	if tunnel.reconnectDelay > 0 {
+		delay = tunnel.reconnectDelay
+	}
+	err := retry.Do(func() error {
+		_, err := establish(ctx)
+		return err
+	},
+		retry.Context(ctx),
+		retry.Attempts(reconnectAttempts),
+		retry.Delay(delay),
+		retry.MaxDelay(reconnectMaxDelay),
+		retry.MaxJitter(delay),
+		retry.DelayType(retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(n uint, err error) {
+			tunnel.debugf("Unable to re-establish the tunnel to %s (attempt %d): %s", tunnel.resource(), n+1, err.Error())
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	tunnel.ready()
+	return nil
+}
+
+// ready calls the OnReady function of the tunnel.
+func (tunnel *Tunnel) ready() {
+	if tunnel.onReady != nil {
+		tunnel.onReady(tunnel.Describe(""))
+	}
+}
+
+// debugf logs a debug message of the tunnel.
+func (tunnel *Tunnel) debugf(format string, a ...any) {
+	logger := tunnel.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Debug(fmt.Sprintf(format, a...))
+}
+
 // Endpoint returns the tunnel ip address and port (i.e. for docker registries)
 func (tunnel *Tunnel) Endpoint() string {
 	return fmt.Sprintf("%s:%d", helpers.IPV4Localhost, tunnel.localPort)
 }
 
+// TunnelEndpoint describes an established tunnel for tools that consume it instead of a person.
+type TunnelEndpoint struct {
+	Target       string          `json:"target,omitempty"`
+	Namespace    string          `json:"namespace"`
+	ResourceType string          `json:"resourceType"`
+	ResourceName string          `json:"resourceName,omitempty"`
+	Selector     string          `json:"selector,omitempty"`
+	RemotePort   int             `json:"remotePort"`
+	LocalPort    int             `json:"localPort"`
+	Protocol     corev1.Protocol `json:"protocol"`
+	URL          string          `json:"url"`
+}
+
+// Describe returns the endpoint of the tunnel to the target, the local port is the port of the URL which is served
+// with HTTPS for tunnels with local TLS.
+func (tunnel *Tunnel) Describe(target string) TunnelEndpoint {
+	return TunnelEndpoint{
+		Target:       target,
+		Namespace:    tunnel.namespace,
+		ResourceType: tunnel.resourceType,
+		ResourceName: tunnel.resourceName,
+		Selector:     tunnel.selector,
+		RemotePort:   tunnel.remotePort,
+		LocalPort:    tunnel.LocalPort(),
+		Protocol:     tunnel.protocol,
+		URL:          tunnel.FullURL(),
+	}
+}
+
 // ErrChan returns the tunnel's error channel
 func (tunnel *Tunnel) ErrChan() chan error {
 	return tunnel.errChan
@@ -379,14 +688,42 @@ func (tunnel *Tunnel) HTTPEndpoint() string {
 	return fmt.Sprintf("http://%s", tunnel.Endpoint())
 }
 
-// FullURL returns the tunnel endpoint as a HTTP URL string with the urlSuffix appended.
+// FullURL returns the tunnel endpoint as a HTTP URL string with the urlSuffix appended, as a HTTPS URL for tunnels with
+// local TLS, or as a UDP URL for UDP tunnels.
 func (tunnel *Tunnel) FullURL() string {
+	if tunnel.protocol == corev1.ProtocolUDP {
+		return fmt.Sprintf("udp://%s", tunnel.Endpoint())
+	}
+	if tunnel.tlsListener != nil {
+		return fmt.Sprintf("https://%s:%d%s", helpers.IPV4Localhost, tunnel.tlsPort, tunnel.urlSuffix)
+	}
 	return fmt.Sprintf("%s%s", tunnel.HTTPEndpoint(), tunnel.urlSuffix)
 }
 
-// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine.
+// Protocol returns the protocol of the tunnel.
+func (tunnel *Tunnel) Protocol() corev1.Protocol {
+	return tunnel.protocol
+}
+
+// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine. The relay pod of a
+// UDP tunnel is deleted. Closing a tunnel again does nothing.
 func (tunnel *Tunnel) Close() {
-	close(tunnel.stopChan)
+	tunnel.closeOnce.Do(func() {
+		close(tunnel.stopChan)
+		if tunnel.tlsListener != nil {
+			tunnel.tlsListener.Close()
+		}
+		if tunnel.packetConn != nil {
+			tunnel.packetConn.Close()
+		}
+		if tunnel.relay != nil {
+			tunnel.relay.Close()
+		}
+		tunnel.deleteRelayPod()
+		if tunnel.done != nil {
+			close(tunnel.done)
+		}
+	})
 }
 
 // establish opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct.
@@ -403,31 +740,29 @@ func (tunnel *Tunnel) establish(ctx context.Context) (string, error) {
 	// since there is a brief moment between `GetAvailablePort` and `forwarder.ForwardPorts` where the selected port
 	// is available for selection again.
 	if localPort == 0 {
-		message.Debugf("Requested local port is 0. Selecting an open port on host system")
+		tunnel.debugf("Requested local port is 0. Selecting an open port on host system")
 		localPort, err = helpers.GetAvailablePort()
 		if err != nil {
 			return "", fmt.Errorf("unable to find an available port: %w", err)
 		}
-		message.Debugf("Selected port %d", localPort)
+		tunnel.debugf("Selected port %d", localPort)
 		globalMutex.Lock()
 		defer globalMutex.Unlock()
 	}
 
-	msg := fmt.Sprintf("Opening tunnel %d -> %d for %s/%s in namespace %s",
+	tunnel.debugf("Opening tunnel %d -> %d for %s in namespace %s",
 		localPort,
 		tunnel.remotePort,
-		tunnel.resourceType,
-		tunnel.resourceName,
+		tunnel.resource(),
 		tunnel.namespace,
 	)
-	message.Debugf(msg)
 
 	// Find the pod to port forward to
 	podName, err := tunnel.getAttachablePodForResource(ctx)
 	if err != nil {
 		return "", fmt.Errorf("unable to find pod attached to given resource: %w", err)
 	}
-	message.Debugf("Selected pod %s to open port forward to", podName)
+	tunnel.debugf("Selected pod %s to open port forward to", podName)
 
 	// Build url to the port forward endpoint.
 	// Example: http://localhost:8080/api/v1/namespaces/helm/pods/tiller-deploy-9itlq/portforward.
@@ -440,7 +775,7 @@ func (tunnel *Tunnel) establish(ctx context.Context) (string, error) {
 		SubResource("portforward").
 		URL()
 
-	message.Debugf("Using URL %s to create portforward", portForwardCreateURL)
+	tunnel.debugf("Using URL %s to create portforward", portForwardCreateURL)
 
 	// Construct the spdy client required by the client-go portforward library.
 	transport, upgrader, err := spdy.RoundTripperFor(tunnel.restConfig)
@@ -449,8 +784,10 @@ func (tunnel *Tunnel) establish(ctx context.Context) (string, error) {
 	}
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", portForwardCreateURL)
 
-	// Construct a new PortForwarder struct that manages the instructed port forward tunnel.
+	// Construct a new PortForwarder struct that manages the instructed port forward tunnel. It closes the ready channel
+	// once it listens, so every port forward needs its own.
 	ports := []string{fmt.Sprintf("%d:%d", localPort, tunnel.remotePort)}
+	tunnel.readyChan = make(chan struct{}, 1)
 	portforwarder, err := portforward.New(dialer, ports, tunnel.stopChan, tunnel.readyChan, tunnel.out, tunnel.out)
 	if err != nil {
 		return "", fmt.Errorf("unable to create the port forward: %w", err)
@@ -458,7 +795,8 @@ func (tunnel *Tunnel) establish(ctx context.Context) (string, error) {
 
 	// Open the tunnel in a goroutine so that it is available in the background. Report errors to the main goroutine via
 	// a new channel.
-	errChan := make(chan error)
+	// The channel is buffered so the goroutine exits once the tunnel is closed, even when nothing reads the channel
+	errChan := make(chan error, 1)
 	go func() {
 		errChan <- portforwarder.ForwardPorts()
 	}()
@@ -475,16 +813,27 @@ func (tunnel *Tunnel) establish(ctx context.Context) (string, error) {
 		// Store the error channel to listen for errors
 		tunnel.errChan = errChan
 
-		message.Debugf("Creating port forwarding tunnel at %s", url)
+		tunnel.debugf("Creating port forwarding tunnel at %s", url)
 		return url, nil
 	}
 }
 
+// resource describes the resource the tunnel connects to for messages.
+func (tunnel *Tunnel) resource() string {
+	if tunnel.selector != "" {
+		return fmt.Sprintf("%s -l %s", tunnel.resourceType, tunnel.selector)
+	}
+	return fmt.Sprintf("%s/%s", tunnel.resourceType, tunnel.resourceName)
+}
+
 // getAttachablePodForResource will find a pod that can be port forwarded to the provided resource type and return
 // the name.
 func (tunnel *Tunnel) getAttachablePodForResource(ctx context.Context) (string, error) {
 	switch tunnel.resourceType {
 	case PodResource:
+		if tunnel.selector != "" {
+			return tunnel.getAttachablePodForSelector(ctx)
+		}
 		return tunnel.resourceName, nil
 	case SvcResource:
 		return tunnel.getAttachablePodForService(ctx)
@@ -516,3 +865,35 @@ func (tunnel *Tunnel) getAttachablePodForService(ctx context.Context) (string, e
 	}
 	return podList.Items[0].Name, nil
 }
+
+// getAttachablePodForSelector will find a ready pod that matches the label selector of the tunnel and is not being
+// deleted, and return the pod name.
+func (tunnel *Tunnel) getAttachablePodForSelector(ctx context.Context) (string, error) {
+	listOpt := metav1.ListOptions{
+		LabelSelector: tunnel.selector,
+		FieldSelector: fmt.Sprintf("status.phase=%s", corev1.PodRunning),
+	}
+	podList, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).List(ctx, listOpt)
+	if err != nil {
+		return "", err
+	}
+	pods := podList.Items
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && isPodReady(pod) {
+			return pod.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no ready pods match the selector %s in namespace %s", tunnel.selector, tunnel.namespace)
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
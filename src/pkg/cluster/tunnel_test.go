@@ -4,12 +4,18 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/zarf-dev/zarf/src/types"
@@ -167,3 +173,401 @@ func TestServiceInfoFromNodePortURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConnectTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		target        string
+		expectedName  string
+		expectedPort  int
+		expectedError string
+	}{
+		{
+			name:         "name only",
+			target:       "registry",
+			expectedName: "registry",
+		},
+		{
+			name:         "name and local port",
+			target:       "podinfo:9898",
+			expectedName: "podinfo",
+			expectedPort: 9898,
+		},
+		{
+			name:          "invalid port",
+			target:        "git:http",
+			expectedError: `invalid connect target "git:http", the local port must be a number between 1 and 65535`,
+		},
+		{
+			name:          "port out of range",
+			target:        "git:70000",
+			expectedError: `invalid connect target "git:70000", the local port must be a number between 1 and 65535`,
+		},
+		{
+			name:          "empty name",
+			target:        ":8080",
+			expectedError: `invalid connect target ":8080", the name is empty`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, port, err := ParseConnectTarget(tt.target)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedName, name)
+			require.Equal(t, tt.expectedPort, port)
+		})
+	}
+}
+
+func TestNewDefinitionTunnelInfo(t *testing.T) {
+	t.Parallel()
+
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "podinfo",
+				Name:        "podinfo",
+				Labels:      map[string]string{ZarfConnectLabelName: "podinfo"},
+				Annotations: map[string]string{ZarfConnectAnnotationURL: "/healthz"},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(9898)}},
+			},
+		}),
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		definition    types.ConnectDefinition
+		expected      TunnelInfo
+		expectedError string
+	}{
+		{
+			name:   "resource",
+			target: "grafana",
+			definition: types.ConnectDefinition{
+				Namespace:  "monitoring",
+				Name:       "grafana",
+				RemotePort: 3000,
+				LocalPort:  3000,
+				URL:        "/login",
+			},
+			expected: TunnelInfo{Namespace: "monitoring", ResourceType: SvcResource, ResourceName: "grafana", RemotePort: 3000, LocalPort: 3000, urlSuffix: "/login"},
+		},
+		{
+			name:   "selector",
+			target: "api",
+			definition: types.ConnectDefinition{
+				Namespace:  "api",
+				Type:       PodResource,
+				Selector:   "app=api",
+				RemotePort: 8080,
+			},
+			expected: TunnelInfo{Namespace: "api", ResourceType: PodResource, Selector: "app=api", RemotePort: 8080},
+		},
+		{
+			name:       "refines a connect label",
+			target:     "podinfo",
+			definition: types.ConnectDefinition{LocalPort: 9898},
+			expected:   TunnelInfo{Namespace: "podinfo", ResourceType: SvcResource, ResourceName: "podinfo", RemotePort: 9898, LocalPort: 9898, urlSuffix: "/healthz"},
+		},
+		{
+			name:       "refines a built-in target",
+			target:     "registry",
+			definition: types.ConnectDefinition{LocalPort: 5000},
+			expected:   TunnelInfo{Namespace: ZarfNamespaceName, ResourceType: SvcResource, ResourceName: ZarfRegistryName, RemotePort: ZarfRegistryPort, LocalPort: 5000, urlSuffix: "/v2/_catalog"},
+		},
+		{
+			name:          "no connect label",
+			target:        "missing",
+			definition:    types.ConnectDefinition{LocalPort: 8080},
+			expectedError: "problem looking for a zarf connect label in the cluster: no matching services found for missing",
+		},
+		{
+			name:          "no remote port",
+			target:        "grafana",
+			definition:    types.ConnectDefinition{Name: "grafana"},
+			expectedError: "the connect definition grafana has no remote port",
+		},
+		{
+			name:          "invalid protocol",
+			target:        "dns",
+			definition:    types.ConnectDefinition{Name: "dns", RemotePort: 53, Protocol: "sctp"},
+			expectedError: `invalid protocol "sctp" of the connect definition dns, must be tcp or udp`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			zt, err := c.NewDefinitionTunnelInfo(context.Background(), tt.target, tt.definition)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, zt)
+		})
+	}
+}
+
+func TestGetAttachablePodForSelector(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pod := func(name, app string, ready bool, deleting bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: name, Labels: map[string]string{"app": app}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+		if deleting {
+			now := metav1.Now()
+			p.DeletionTimestamp = &now
+		}
+		return p
+	}
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(
+			pod("foo-a", "foo", true, true),
+			pod("foo-b", "foo", false, false),
+			pod("foo-c", "foo", true, false),
+			pod("bar-a", "bar", true, false),
+		),
+	}
+
+	tunnel, err := c.NewTunnel("foo", PodResource, "", "", 0, 8080)
+	require.NoError(t, err)
+	tunnel.selector = "app=foo"
+	podName, err := tunnel.getAttachablePodForResource(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo-c", podName)
+	require.Equal(t, "pod -l app=foo", tunnel.resource())
+
+	// The selector is resolved again once the pod is gone
+	require.NoError(t, c.Clientset.CoreV1().Pods("foo").Delete(ctx, "foo-c", metav1.DeleteOptions{}))
+	_, err = tunnel.getAttachablePodForResource(ctx)
+	require.EqualError(t, err, "no ready pods match the selector app=foo in namespace foo")
+	_, err = c.Clientset.CoreV1().Pods("foo").Create(ctx, pod("foo-d", "foo", true, false), metav1.CreateOptions{})
+	require.NoError(t, err)
+	podName, err = tunnel.getAttachablePodForResource(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo-d", podName)
+}
+
+func TestConnectTunnelInfoSelector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		zt            TunnelInfo
+		expectedError string
+	}{
+		{
+			name:          "service",
+			zt:            TunnelInfo{Namespace: "foo", ResourceType: SvcResource, Selector: "app=foo", RemotePort: 8080},
+			expectedError: "a label selector can only be used to connect to pods, not to a svc",
+		},
+		{
+			name:          "udp",
+			zt:            TunnelInfo{Namespace: "foo", ResourceType: PodResource, Selector: "app=foo", RemotePort: 53, Protocol: corev1.ProtocolUDP},
+			expectedError: "a label selector can not be used to connect to UDP ports",
+		},
+		{
+			name:          "invalid selector",
+			zt:            TunnelInfo{Namespace: "foo", ResourceType: PodResource, Selector: "app in (foo", RemotePort: 8080},
+			expectedError: `invalid label selector "app in (foo": `,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Cluster{Clientset: fake.NewSimpleClientset()}
+			_, err := c.ConnectTunnelInfo(context.Background(), tt.zt)
+			require.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestTunnelDescribe(t *testing.T) {
+	t.Parallel()
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	tunnel, err := c.NewTunnel("zarf", SvcResource, "zarf-gitea-http", "/explore", 42000, 3000)
+	require.NoError(t, err)
+	require.Equal(t, TunnelEndpoint{
+		Target:       "git",
+		Namespace:    "zarf",
+		ResourceType: SvcResource,
+		ResourceName: "zarf-gitea-http",
+		RemotePort:   3000,
+		LocalPort:    42000,
+		Protocol:     corev1.ProtocolTCP,
+		URL:          "http://127.0.0.1:42000/explore",
+	}, tunnel.Describe("git"))
+
+	b, err := json.Marshal(tunnel.Describe(""))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"namespace":"zarf","resourceType":"svc","resourceName":"zarf-gitea-http","remotePort":3000,"localPort":42000,"protocol":"TCP","url":"http://127.0.0.1:42000/explore"}`, string(b))
+}
+
+func TestTunnelLifecycle(t *testing.T) {
+	t.Parallel()
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	newTunnel := func() *Tunnel {
+		tunnel, err := c.NewTunnel("zarf", SvcResource, "zarf-gitea-http", "", 42001, 3000)
+		require.NoError(t, err)
+		return tunnel
+	}
+
+	t.Run("closed when the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := newTunnel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NoError(t, tunnel.Wait(ctx))
+		require.Eventually(t, func() bool {
+			select {
+			case <-tunnel.Done():
+				return true
+			default:
+				return false
+			}
+		}, time.Second, 10*time.Millisecond)
+		// Closing again does nothing
+		tunnel.Close()
+	})
+
+	t.Run("lost", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel := newTunnel()
+		tunnel.errChan = make(chan error, 1)
+		tunnel.errChan <- errors.New("connection reset")
+		require.EqualError(t, tunnel.Wait(context.Background()), "connection reset")
+		tunnel.Close()
+		require.NoError(t, tunnel.Wait(context.Background()))
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		tunnel := newTunnel()
+		tunnel.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		endpoints := []TunnelEndpoint{}
+		tunnel.OnReady(func(endpoint TunnelEndpoint) {
+			endpoints = append(endpoints, endpoint)
+		})
+		tunnel.ready()
+		require.Len(t, endpoints, 1)
+		require.Equal(t, 42001, endpoints[0].LocalPort)
+		require.Equal(t, 42001, tunnel.LocalPort())
+
+		tunnel.debugf("Opening tunnel %d -> %d", tunnel.LocalPort(), 3000)
+		require.Contains(t, buf.String(), "Opening tunnel 42001 -> 3000")
+	})
+}
+
+func TestTunnelReconnect(t *testing.T) {
+	t.Parallel()
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	newTunnel := func(establish func(tunnel *Tunnel, attempt int) error) (*Tunnel, *[]time.Time) {
+		tunnel, err := c.NewTunnel("zarf", SvcResource, "zarf-gitea-http", "", 42002, 3000)
+		require.NoError(t, err)
+		tunnel.reconnectDelay = 10 * time.Millisecond
+		attempts := []time.Time{}
+		tunnel.reestablish = func(_ context.Context) (string, error) {
+			attempts = append(attempts, time.Now())
+			if err := establish(tunnel, len(attempts)); err != nil {
+				return "", err
+			}
+			return tunnel.FullURL(), nil
+		}
+		return tunnel, &attempts
+	}
+
+	t.Run("dropped connection", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel, attempts := newTunnel(func(tunnel *Tunnel, _ int) error {
+			tunnel.errChan = make(chan error, 1)
+			return nil
+		})
+		ready := 0
+		tunnel.OnReady(func(TunnelEndpoint) {
+			ready++
+		})
+		tunnel.errChan = make(chan error, 1)
+		tunnel.errChan <- errors.New("connection reset")
+		require.EqualError(t, tunnel.Wait(context.Background()), "connection reset")
+
+		require.NoError(t, tunnel.Reconnect(context.Background()))
+		require.Len(t, *attempts, 1)
+		require.Equal(t, 1, ready)
+		require.Equal(t, 42002, tunnel.LocalPort())
+
+		// The tunnel waits on the new port forward
+		tunnel.errChan <- errors.New("connection reset again")
+		require.EqualError(t, tunnel.Wait(context.Background()), "connection reset again")
+	})
+
+	t.Run("backoff until success", func(t *testing.T) {
+		t.Parallel()
+
+		tunnel, attempts := newTunnel(func(tunnel *Tunnel, attempt int) error {
+			if attempt < 3 {
+				return errors.New("no ready pods")
+			}
+			tunnel.errChan = make(chan error, 1)
+			return nil
+		})
+		require.NoError(t, tunnel.Reconnect(context.Background()))
+		require.Len(t, *attempts, 3)
+		// The delay doubles between attempts
+		require.GreaterOrEqual(t, (*attempts)[1].Sub((*attempts)[0]), 10*time.Millisecond)
+		require.GreaterOrEqual(t, (*attempts)[2].Sub((*attempts)[1]), 20*time.Millisecond)
+	})
+
+	t.Run("gives up when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tunnel, attempts := newTunnel(func(_ *Tunnel, attempt int) error {
+			if attempt == 2 {
+				cancel()
+			}
+			return errors.New("no ready pods")
+		})
+		ready := false
+		tunnel.OnReady(func(TunnelEndpoint) {
+			ready = true
+		})
+		require.Error(t, tunnel.Reconnect(ctx))
+		require.Len(t, *attempts, 2)
+		require.False(t, ready)
+	})
+}
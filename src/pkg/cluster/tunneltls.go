@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+)
+
+// EnableLocalTLS makes the tunnel serve HTTPS on its local port with the TLS config, terminating TLS in front of a
+// plain port forward. It must be called before Connect. Endpoint and HTTPEndpoint still return the plain port forward
+// for clients in this process, while FullURL returns the HTTPS endpoint.
+func (tunnel *Tunnel) EnableLocalTLS(tlsConfig *tls.Config) {
+	tunnel.tlsConfig = tlsConfig
+}
+
+// serveLocalTLS listens for TLS connections on the port and relays them to the plain port forward of the tunnel.
+func (tunnel *Tunnel) serveLocalTLS(port int) error {
+	listener, err := tls.Listen("tcp", net.JoinHostPort(helpers.IPV4Localhost, strconv.Itoa(port)), tunnel.tlsConfig)
+	if err != nil {
+		return fmt.Errorf("unable to listen for TLS connections on the local port: %w", err)
+	}
+	tunnel.tlsListener = listener
+	tunnel.tlsPort = listener.Addr().(*net.TCPAddr).Port
+	tunnel.debugf("Terminating TLS on port %d in front of the tunnel at %s", tunnel.tlsPort, tunnel.Endpoint())
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					tunnel.debugf("Unable to accept a TLS connection: %s", err.Error())
+				}
+				return
+			}
+			go tunnel.relayTLSConn(conn)
+		}
+	}()
+	return nil
+}
+
+// relayTLSConn copies the decrypted traffic of a TLS connection to and from the plain port forward of the tunnel.
+func (tunnel *Tunnel) relayTLSConn(conn net.Conn) {
+	defer conn.Close()
+	upstream, err := net.Dial("tcp", tunnel.Endpoint())
+	if err != nil {
+		tunnel.debugf("Unable to connect to the tunnel at %s: %s", tunnel.Endpoint(), err.Error())
+		return
+	}
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+)
+
+func TestTunnelLocalTLS(t *testing.T) {
+	t.Parallel()
+
+	// The plain server stands in for the port forward of the tunnel
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello from " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	generated, err := pki.LoadOrGeneratePKI(t.TempDir(), "localhost")
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(generated.Cert, generated.Key)
+	require.NoError(t, err)
+
+	tunnel := &Tunnel{
+		localPort: server.Listener.Addr().(*net.TCPAddr).Port,
+		urlSuffix: "/ui",
+		stopChan:  make(chan struct{}, 1),
+	}
+	tunnel.EnableLocalTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	require.NoError(t, tunnel.serveLocalTLS(0))
+	defer tunnel.Close()
+	require.Contains(t, tunnel.FullURL(), "https://127.0.0.1:")
+	require.Equal(t, server.URL, tunnel.HTTPEndpoint())
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(generated.CA))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+	resp, err := client.Get(tunnel.FullURL())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "hello from /ui", string(b))
+}
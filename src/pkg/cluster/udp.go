@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// UDP relay constants.
+const (
+	// ZarfUDPRelayPort is the TCP port the UDP relay pod listens on for port forwards
+	ZarfUDPRelayPort = 9053
+	udpRelayName     = "zarf-udp-relay"
+	// Datagrams are framed with their length over the TCP port forward as it does not preserve message boundaries
+	udpFrameHeaderLen = 2
+	maxUDPPayload     = 65535
+)
+
+// writeUDPFrame writes a datagram to the TCP stream prefixed with its length.
+func writeUDPFrame(w io.Writer, datagram []byte) error {
+	if len(datagram) > maxUDPPayload {
+		return fmt.Errorf("datagram of %d bytes exceeds the maximum UDP payload", len(datagram))
+	}
+	frame := make([]byte, udpFrameHeaderLen+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram)))
+	copy(frame[udpFrameHeaderLen:], datagram)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readUDPFrame reads a datagram written with writeUDPFrame from the TCP stream.
+func readUDPFrame(r io.Reader) ([]byte, error) {
+	header := make([]byte, udpFrameHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	datagram := make([]byte, binary.BigEndian.Uint16(header))
+	if _, err := io.ReadFull(r, datagram); err != nil {
+		return nil, err
+	}
+	return datagram, nil
+}
+
+// RelayUDP accepts TCP connections on the listen address and relays the datagrams framed on each of them to the
+// target UDP address, framing the replies back. It runs in the UDP relay pod until the context is cancelled.
+func RelayUDP(ctx context.Context, listen, target string) error {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", listen)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	message.Infof("Relaying UDP datagrams from %s to %s", listener.Addr(), target)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			udpConn, err := net.Dial("udp", target)
+			if err != nil {
+				message.Warnf("Unable to reach %s: %s", target, err.Error())
+				return
+			}
+			defer udpConn.Close()
+			go func() {
+				buf := make([]byte, maxUDPPayload)
+				for {
+					n, err := udpConn.Read(buf)
+					if err != nil {
+						conn.Close()
+						return
+					}
+					if err := writeUDPFrame(conn, buf[:n]); err != nil {
+						return
+					}
+				}
+			}()
+			for {
+				datagram, err := readUDPFrame(conn)
+				if err != nil {
+					return
+				}
+				if _, err := udpConn.Write(datagram); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// relayUDPToTCP reads datagrams from the local packet connection and relays them over a TCP connection to the address
+// per client, writing the datagrams framed back on that connection to the client. It returns when the packet
+// connection is closed.
+func relayUDPToTCP(packetConn net.PacketConn, address string, logger *slog.Logger) {
+	var mu sync.Mutex
+	clients := map[string]net.Conn{}
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range clients {
+			conn.Close()
+		}
+	}()
+
+	buf := make([]byte, maxUDPPayload)
+	for {
+		n, addr, err := packetConn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		conn, ok := clients[addr.String()]
+		if !ok {
+			conn, err = net.Dial("tcp", address)
+			if err != nil {
+				mu.Unlock()
+				logger.Debug(fmt.Sprintf("Unable to open a connection to the UDP relay for %s: %s", addr, err.Error()))
+				continue
+			}
+			clients[addr.String()] = conn
+			go func() {
+				defer func() {
+					mu.Lock()
+					delete(clients, addr.String())
+					mu.Unlock()
+					conn.Close()
+				}()
+				for {
+					datagram, err := readUDPFrame(conn)
+					if err != nil {
+						return
+					}
+					if _, err := packetConn.WriteTo(datagram, addr); err != nil {
+						return
+					}
+				}
+			}()
+		}
+		mu.Unlock()
+		if err := writeUDPFrame(conn, buf[:n]); err != nil {
+			logger.Debug(fmt.Sprintf("Unable to relay a datagram from %s: %s", addr, err.Error()))
+		}
+	}
+}
+
+// newUDPRelayPod returns the pod that relays framed datagrams from its TCP port to the target UDP address, running
+// the image of the Zarf agent.
+func newUDPRelayPod(image, target string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: udpRelayName + "-",
+			Namespace:    ZarfNamespaceName,
+			Labels: map[string]string{
+				"app":              udpRelayName,
+				ZarfManagedByLabel: "zarf",
+				// Don't mutate this pod, its image is already in the Zarf registry
+				AgentLabel: "ignore",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:    corev1.RestartPolicyNever,
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+			Containers: []corev1.Container{
+				{
+					Name:            "relay",
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command:         []string{"/zarf", "internal", "udp-relay", "--listen", fmt.Sprintf(":%d", ZarfUDPRelayPort), "--target", target, "--no-log-file"},
+					Ports:           []corev1.ContainerPort{{ContainerPort: ZarfUDPRelayPort, Protocol: corev1.ProtocolTCP}},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("10m"),
+							corev1.ResourceMemory: resource.MustParse("16Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("250m"),
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// udpRelayTarget returns the UDP address the relay pod sends the datagrams of the tunnel to.
+func (tunnel *Tunnel) udpRelayTarget(ctx context.Context) (string, error) {
+	switch tunnel.resourceType {
+	case SvcResource:
+		return net.JoinHostPort(fmt.Sprintf("%s.%s.svc", tunnel.resourceName, tunnel.namespace), strconv.Itoa(tunnel.remotePort)), nil
+	case PodResource:
+		pod, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Get(ctx, tunnel.resourceName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		if pod.Status.PodIP == "" {
+			return "", fmt.Errorf("pod %s has no IP address", tunnel.resourceName)
+		}
+		return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(tunnel.remotePort)), nil
+	default:
+		return "", fmt.Errorf("unknown resource type: %s", tunnel.resourceType)
+	}
+}
+
+// establishUDP starts a relay pod for the UDP target of the tunnel, opens a TCP tunnel to it and relays the datagrams
+// received on the local UDP port through it.
+func (tunnel *Tunnel) establishUDP(ctx context.Context) (string, error) {
+	target, err := tunnel.udpRelayTarget(ctx)
+	if err != nil {
+		return "", err
+	}
+	agent, err := tunnel.clientset.AppsV1().Deployments(ZarfNamespaceName).Get(ctx, "agent-hook", metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to find the Zarf agent to run the UDP relay with: %w", err)
+	}
+	pod, err := tunnel.clientset.CoreV1().Pods(ZarfNamespaceName).Create(ctx, newUDPRelayPod(agent.Spec.Template.Spec.Containers[0].Image, target), metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create the UDP relay pod: %w", err)
+	}
+	tunnel.relayPod = pod.Name
+	tunnel.debugf("Created the UDP relay pod %s for %s", pod.Name, target)
+
+	err = retry.Do(func() error {
+		pod, err := tunnel.clientset.CoreV1().Pods(ZarfNamespaceName).Get(ctx, tunnel.relayPod, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return fmt.Errorf("the UDP relay pod %s is %s", pod.Name, pod.Status.Phase)
+		}
+		return nil
+	}, retry.Context(ctx), retry.Attempts(60), retry.Delay(time.Second), retry.DelayType(retry.FixedDelay))
+	if err != nil {
+		tunnel.deleteRelayPod()
+		return "", err
+	}
+
+	relay := &Tunnel{
+		clientset:    tunnel.clientset,
+		restConfig:   tunnel.restConfig,
+		out:          tunnel.out,
+		remotePort:   ZarfUDPRelayPort,
+		namespace:    ZarfNamespaceName,
+		resourceType: PodResource,
+		resourceName: tunnel.relayPod,
+		protocol:     corev1.ProtocolTCP,
+		stopChan:     make(chan struct{}, 1),
+		readyChan:    make(chan struct{}, 1),
+		logger:       tunnel.logger,
+		done:         make(chan struct{}),
+	}
+	if _, err := relay.establish(ctx); err != nil {
+		tunnel.deleteRelayPod()
+		return "", err
+	}
+
+	packetConn, err := net.ListenPacket("udp", net.JoinHostPort(helpers.IPV4Localhost, strconv.Itoa(tunnel.localPort)))
+	if err != nil {
+		relay.Close()
+		tunnel.deleteRelayPod()
+		return "", fmt.Errorf("unable to listen on the local UDP port: %w", err)
+	}
+	logger := tunnel.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	go relayUDPToTCP(packetConn, relay.Endpoint(), logger)
+
+	tunnel.relay = relay
+	tunnel.packetConn = packetConn
+	tunnel.localPort = packetConn.LocalAddr().(*net.UDPAddr).Port
+	tunnel.errChan = relay.errChan
+	url := tunnel.FullURL()
+	tunnel.debugf("Creating UDP tunnel at %s", url)
+	return url, nil
+}
+
+// deleteRelayPod deletes the UDP relay pod of the tunnel, it is deleted with a fresh context as the tunnel is often
+// closed after the context of the command was cancelled.
+func (tunnel *Tunnel) deleteRelayPod() {
+	if tunnel.relayPod == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := tunnel.clientset.CoreV1().Pods(ZarfNamespaceName).Delete(ctx, tunnel.relayPod, metav1.DeleteOptions{})
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		tunnel.debugf("Unable to delete the UDP relay pod %s: %s", tunnel.relayPod, err.Error())
+	}
+	tunnel.relayPod = ""
+}
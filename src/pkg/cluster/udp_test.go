@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUDPFrame(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	for _, datagram := range [][]byte{[]byte("first"), {}, bytes.Repeat([]byte("a"), maxUDPPayload)} {
+		require.NoError(t, writeUDPFrame(&buf, datagram))
+	}
+	for _, expected := range [][]byte{[]byte("first"), {}, bytes.Repeat([]byte("a"), maxUDPPayload)} {
+		datagram, err := readUDPFrame(&buf)
+		require.NoError(t, err)
+		require.Equal(t, expected, datagram)
+	}
+	_, err := readUDPFrame(&buf)
+	require.Error(t, err)
+	require.EqualError(t, writeUDPFrame(&buf, make([]byte, maxUDPPayload+1)), "datagram of 65536 bytes exceeds the maximum UDP payload")
+}
+
+func TestRelayUDP(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The echo server stands in for the UDP service in the cluster
+	echo, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer echo.Close()
+	go func() {
+		buf := make([]byte, maxUDPPayload)
+		for {
+			n, addr, err := echo.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = echo.WriteTo(append([]byte("echo "), buf[:n]...), addr)
+		}
+	}()
+
+	// The relay stands in for the relay pod behind the TCP port forward
+	port, err := helpers.GetAvailablePort()
+	require.NoError(t, err)
+	relayAddress := fmt.Sprintf("127.0.0.1:%d", port)
+	go func() {
+		_ = RelayUDP(ctx, relayAddress, echo.LocalAddr().String())
+	}()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", relayAddress)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	local, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer local.Close()
+	go relayUDPToTCP(local, relayAddress, slog.Default())
+
+	client, err := net.Dial("udp", local.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	for _, msg := range []string{"first", "second"} {
+		_, err = client.Write([]byte(msg))
+		require.NoError(t, err)
+		require.NoError(t, client.SetReadDeadline(time.Now().Add(5*time.Second)))
+		buf := make([]byte, 64)
+		n, err := client.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, "echo "+msg, string(buf[:n]))
+	}
+}
+
+func TestUDPTunnelInfo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "dns",
+				Name:      "coredns",
+				Labels:    map[string]string{ZarfConnectLabelName: "dns"},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 53, TargetPort: intstr.FromInt(1053), Protocol: corev1.ProtocolUDP}},
+			},
+		}),
+	}
+	zt, err := c.NewTargetTunnelInfo(ctx, "dns")
+	require.NoError(t, err)
+	require.Equal(t, corev1.ProtocolUDP, zt.Protocol)
+	require.Equal(t, 53, zt.RemotePort)
+
+	tunnel, err := c.NewTunnel(zt.Namespace, zt.ResourceType, zt.ResourceName, "", 5353, zt.RemotePort)
+	require.NoError(t, err)
+	tunnel.protocol = zt.Protocol
+	require.Equal(t, "udp://127.0.0.1:5353", tunnel.FullURL())
+	target, err := tunnel.udpRelayTarget(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "coredns.dns.svc:53", target)
+
+	pod := newUDPRelayPod("127.0.0.1:31999/zarf-dev/zarf/agent:v0.38.1", target)
+	require.Equal(t, ZarfNamespaceName, pod.Namespace)
+	require.Equal(t, "ignore", pod.Labels[AgentLabel])
+	require.Equal(t, []string{"/zarf", "internal", "udp-relay", "--listen", ":9053", "--target", "coredns.dns.svc:53", "--no-log-file"}, pod.Spec.Containers[0].Command)
+}
@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// Conditions with a special meaning when waiting for resources.
+const (
+	// WaitConditionExists waits for the resources to exist, it is the default condition.
+	WaitConditionExists = "exists"
+	// WaitConditionCurrent waits for the resources to be fully reconciled as computed by kstatus.
+	WaitConditionCurrent = "current"
+)
+
+// WaitOptions describes the resources to wait for and what they must meet.
+type WaitOptions struct {
+	// The kind, resource or short name of the resources, such as Deployment, deployments or deploy.
+	Kind string
+	// The name of the resource or a label selector, all resources are waited for when empty.
+	Name string
+	// The namespace of namespaced resources, defaults to the default namespace.
+	Namespace string
+	// Exists, current, the type of a status condition optionally followed by =<status>, or a JSONPath and value such
+	// as {.status.availableReplicas}=2.
+	Condition string
+	// A CEL expression over the resource that must evaluate to true, such as status.readyReplicas == spec.replicas.
+	Expression string
+}
+
+// String returns a description of the resources and what they must meet for messages.
+func (o WaitOptions) String() string {
+	s := o.Kind
+	if o.Name != "" {
+		if strings.ContainsRune(o.Name, '=') {
+			s = fmt.Sprintf("%s with label `%s`", s, o.Name)
+		} else {
+			s = fmt.Sprintf("%s/%s", s, o.Name)
+		}
+	}
+	if o.Namespace != "" {
+		s = fmt.Sprintf("%s in namespace %s", s, o.Namespace)
+	}
+	condition := o.Condition
+	if condition == "" {
+		condition = WaitConditionExists
+	}
+	s = fmt.Sprintf("%s to be %s", s, condition)
+	if o.Expression != "" {
+		s = fmt.Sprintf("%s and match `%s`", s, o.Expression)
+	}
+	return s
+}
+
+// waitCondition returns whether a resource meets what is waited for.
+type waitCondition func(obj *unstructured.Unstructured) (bool, error)
+
+// isJSONPathWaitType checks if the condition is a JSONPath or condition.
+func isJSONPathWaitType(condition string) bool {
+	if len(condition) == 0 || condition[0] != '{' || !strings.Contains(condition, "=") || !strings.Contains(condition, "}") {
+		return false
+	}
+
+	return true
+}
+
+// newWaitCondition returns the condition and CEL expression of a wait as a single condition.
+func newWaitCondition(condition, expression string) (waitCondition, error) {
+	conditions := []waitCondition{}
+	switch {
+	case condition == "" || strings.EqualFold(condition, "exist") || strings.EqualFold(condition, WaitConditionExists):
+	case strings.EqualFold(condition, WaitConditionCurrent):
+		conditions = append(conditions, isCurrent)
+	case isJSONPathWaitType(condition):
+		jsonPathCondition, err := newJSONPathCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, jsonPathCondition)
+	default:
+		conditions = append(conditions, newStatusCondition(condition))
+	}
+	if expression != "" {
+		celCondition, err := newCELCondition(expression)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, celCondition)
+	}
+
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		for _, condition := range conditions {
+			met, err := condition(obj)
+			if err != nil || !met {
+				return false, err
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+// isCurrent returns whether the resource is fully reconciled.
+func isCurrent(obj *unstructured.Unstructured) (bool, error) {
+	result, err := status.Compute(obj)
+	if err != nil {
+		return false, err
+	}
+	if result.Status == status.FailedStatus {
+		message.Debugf("%s/%s failed: %s", obj.GetKind(), obj.GetName(), result.Message)
+	}
+	return result.Status == status.CurrentStatus, nil
+}
+
+// newStatusCondition returns a condition met when the resource has the status condition of the given type, with the
+// status True unless another one is given after an equals sign as in Ready=False.
+func newStatusCondition(condition string) waitCondition {
+	conditionType, conditionStatus, found := strings.Cut(condition, "=")
+	if !found {
+		conditionStatus = string(metav1.ConditionTrue)
+	}
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, err
+		}
+		for _, c := range conditions {
+			c, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			t, _ := c["type"].(string)
+			s, _ := c["status"].(string)
+			if strings.EqualFold(t, conditionType) {
+				return strings.EqualFold(s, conditionStatus), nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// newJSONPathCondition returns a condition met when the JSONPath of the resource has the given value.
+func newJSONPathCondition(condition string) (waitCondition, error) {
+	idx := strings.LastIndex(condition, "}=")
+	if idx < 0 {
+		return nil, fmt.Errorf("the JSONPath condition %s must be of the form {.path}=value", condition)
+	}
+	path := condition[:idx+1]
+	expected := strings.Trim(condition[idx+2:], `'"`)
+	j := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := j.Parse(path); err != nil {
+		return nil, fmt.Errorf("the JSONPath %s is not valid: %w", path, err)
+	}
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		results, err := j.FindResults(obj.Object)
+		if err != nil {
+			return false, err
+		}
+		if len(results) != 1 || len(results[0]) != 1 {
+			return false, nil
+		}
+		return fmt.Sprint(results[0][0].Interface()) == expected, nil
+	}, nil
+}
+
+// celTopLevelFields are the fields of a resource that are variables of CEL expressions, the whole resource is object.
+var celTopLevelFields = []string{"apiVersion", "kind", "metadata", "spec", "status", "data"}
+
+// newCELCondition returns a condition met when the CEL expression evaluates to true for the resource.
+func newCELCondition(expression string) (waitCondition, error) {
+	opts := []cel.EnvOption{cel.Variable("object", cel.DynType)}
+	for _, field := range celTopLevelFields {
+		opts = append(opts, cel.Variable(field, cel.DynType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("the expression %q is not valid CEL: %w", expression, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("the expression %q must evaluate to a bool, not %s", expression, ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		vars := map[string]any{"object": obj.Object}
+		for _, field := range celTopLevelFields {
+			value, ok := obj.Object[field]
+			if !ok {
+				value = map[string]any{}
+			}
+			vars[field] = value
+		}
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			// Fields that are not set yet, such as status.readyReplicas, fail the evaluation until they are
+			message.Debugf("Evaluating %q for %s/%s: %s", expression, obj.GetKind(), obj.GetName(), err)
+			return false, nil
+		}
+		met, ok := out.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("the expression %q evaluated to %v, not a bool", expression, out.Value())
+		}
+		return met, nil
+	}, nil
+}
+
+// resolveResource returns the resource of a kind, resource or short name and whether it is namespaced.
+func (c *Cluster) resolveResource(kind string) (schema.GroupVersionResource, bool, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(c.Clientset.Discovery())
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(groupResources), c.Clientset.Discovery(), func(string) {})
+	gvr, err := mapper.ResourceFor(schema.ParseGroupResource(strings.ToLower(kind)).WithVersion(""))
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// WaitFor watches the resources until they all meet the condition and expression or the context is done, the
+// resources are waited for to exist first and their kind to be served when it is a custom resource.
+func (c *Cluster) WaitFor(ctx context.Context, opts WaitOptions) error {
+	condition, err := newWaitCondition(opts.Condition, opts.Expression)
+	if err != nil {
+		return err
+	}
+
+	// The kind of custom resources may not be served until their CRD is created
+	var gvr schema.GroupVersionResource
+	var namespaced bool
+	for {
+		gvr, namespaced, err = c.resolveResource(opts.Kind)
+		if err == nil {
+			break
+		}
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		message.Debugf("Waiting for the kind %s to be served: %s", opts.Kind, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the kind %s to be served: %w", opts.Kind, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	namespace := ""
+	if namespaced {
+		namespace = opts.Namespace
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+	}
+	resource := c.Dynamic.Resource(gvr).Namespace(namespace)
+	listOpts := func(options *metav1.ListOptions) {
+		if strings.ContainsRune(opts.Name, '=') {
+			options.LabelSelector = opts.Name
+		} else if opts.Name != "" {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", opts.Name).String()
+		}
+	}
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			listOpts(&options)
+			return resource.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			listOpts(&options)
+			return resource.Watch(ctx, options)
+		},
+	}
+
+	// All matching resources must meet the condition, so the latest version of each one is kept
+	objs := map[string]*unstructured.Unstructured{}
+	allMet := func() (bool, error) {
+		if len(objs) == 0 {
+			return false, nil
+		}
+		for _, obj := range objs {
+			met, err := condition(obj)
+			if err != nil || !met {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	track := func(obj runtime.Object, deleted bool) {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || (opts.Name != "" && !strings.ContainsRune(opts.Name, '=') && u.GetName() != opts.Name) {
+			return
+		}
+		key := u.GetNamespace() + "/" + u.GetName()
+		if deleted {
+			delete(objs, key)
+			return
+		}
+		objs[key] = u
+	}
+	precondition := func(store cache.Store) (bool, error) {
+		for _, obj := range store.List() {
+			if obj, ok := obj.(runtime.Object); ok {
+				track(obj, false)
+			}
+		}
+		return allMet()
+	}
+	_, err = watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, precondition, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			track(event.Object, false)
+		case watch.Deleted:
+			track(event.Object, true)
+		case watch.Error:
+			return false, fmt.Errorf("unable to watch %s: %v", gvr.Resource, event.Object)
+		default:
+			return false, nil
+		}
+		return allMet()
+	})
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, watchtools.ErrWatchClosed) {
+			return fmt.Errorf("timed out waiting for %s: %w", opts, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func waitTestDeployment(name string, replicas, readyReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":       name,
+			"namespace":  "podinfo",
+			"generation": int64(1),
+			"labels":     map[string]any{"app": "podinfo"},
+		},
+		"spec": map[string]any{
+			"replicas": replicas,
+		},
+		"status": map[string]any{
+			"observedGeneration": int64(1),
+			"replicas":           replicas,
+			"updatedReplicas":    replicas,
+			"readyReplicas":      readyReplicas,
+			"availableReplicas":  readyReplicas,
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+				map[string]any{"type": "Progressing", "status": "True", "reason": "NewReplicaSetAvailable"},
+			},
+		},
+	}}
+}
+
+func newWaitTestCluster(objects ...runtime.Object) *Cluster {
+	cs := fake.NewSimpleClientset()
+	discovery := cs.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}, Verbs: []string{"get", "list", "watch"}},
+			},
+		},
+	}
+	return &Cluster{
+		Clientset: cs,
+		Dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			deploymentGVR: "DeploymentList",
+		}, objects...),
+	}
+}
+
+func TestIsJSONPathWaitType(t *testing.T) {
+	t.Parallel()
+
+	for _, waitType := range []string{"Ready", "delete", ""} {
+		require.False(t, isJSONPathWaitType(waitType), "Expected %s not to be a JSONPath wait type", waitType)
+	}
+	for _, waitType := range []string{
+		"{.status.availableReplicas}=1",
+		"{.status.containerStatuses[0].ready}=true",
+		"{.spec.containers[0].ports[0].containerPort}=80",
+		"{.spec.nodeName}=knode0",
+	} {
+		require.True(t, isJSONPathWaitType(waitType), "Expected %s to be a JSONPath wait type", waitType)
+	}
+}
+
+func TestWaitCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		condition     string
+		expression    string
+		obj           *unstructured.Unstructured
+		expected      bool
+		expectedError string
+	}{
+		{
+			name:     "exists",
+			obj:      waitTestDeployment("podinfo", 2, 0),
+			expected: true,
+		},
+		{
+			name:      "current",
+			condition: "current",
+			obj:       waitTestDeployment("podinfo", 2, 2),
+			expected:  true,
+		},
+		{
+			name:      "not current",
+			condition: "current",
+			obj:       waitTestDeployment("podinfo", 2, 1),
+		},
+		{
+			name:      "status condition",
+			condition: "available",
+			obj:       waitTestDeployment("podinfo", 2, 2),
+			expected:  true,
+		},
+		{
+			name:      "status condition with status",
+			condition: "Available=False",
+			obj:       waitTestDeployment("podinfo", 2, 2),
+		},
+		{
+			name:      "missing status condition",
+			condition: "Ready",
+			obj:       waitTestDeployment("podinfo", 2, 2),
+		},
+		{
+			name:      "JSONPath",
+			condition: "{.status.availableReplicas}=2",
+			obj:       waitTestDeployment("podinfo", 2, 2),
+			expected:  true,
+		},
+		{
+			name:      "unmet JSONPath",
+			condition: "{.status.availableReplicas}=2",
+			obj:       waitTestDeployment("podinfo", 2, 1),
+		},
+		{
+			name:       "expression",
+			expression: "status.readyReplicas == spec.replicas",
+			obj:        waitTestDeployment("podinfo", 2, 2),
+			expected:   true,
+		},
+		{
+			name:       "expression over the object",
+			expression: `object.metadata.labels.app == "podinfo" && kind == "Deployment"`,
+			obj:        waitTestDeployment("podinfo", 2, 2),
+			expected:   true,
+		},
+		{
+			name:       "unmet expression",
+			expression: "status.readyReplicas == spec.replicas",
+			obj:        waitTestDeployment("podinfo", 2, 1),
+		},
+		{
+			name:       "expression over a missing field",
+			expression: "status.unknown > 0",
+			obj:        waitTestDeployment("podinfo", 2, 2),
+		},
+		{
+			name:       "condition and expression",
+			condition:  "Available",
+			expression: "status.readyReplicas == spec.replicas",
+			obj:        waitTestDeployment("podinfo", 2, 1),
+		},
+		{
+			name:          "invalid expression",
+			expression:    "status.readyReplicas ==",
+			expectedError: "is not valid CEL",
+		},
+		{
+			name:          "expression that is not a bool",
+			expression:    "1 + 1",
+			expectedError: "must evaluate to a bool, not int",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			condition, err := newWaitCondition(tt.condition, tt.expression)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			met, err := condition(tt.obj)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, met)
+		})
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		update        *unstructured.Unstructured
+		opts          WaitOptions
+		expectedError string
+	}{
+		{
+			name:    "already met",
+			objects: []runtime.Object{waitTestDeployment("podinfo", 2, 2)},
+			opts:    WaitOptions{Kind: "Deployment", Name: "podinfo", Namespace: "podinfo", Expression: "status.readyReplicas == spec.replicas"},
+		},
+		{
+			name:    "met after an update",
+			objects: []runtime.Object{waitTestDeployment("podinfo", 2, 1)},
+			update:  waitTestDeployment("podinfo", 2, 2),
+			opts:    WaitOptions{Kind: "deploy", Name: "podinfo", Namespace: "podinfo", Condition: "current"},
+		},
+		{
+			name:   "met once created",
+			update: waitTestDeployment("podinfo", 2, 0),
+			opts:   WaitOptions{Kind: "deployments", Name: "podinfo", Namespace: "podinfo"},
+		},
+		{
+			name:    "all selected resources must be met",
+			objects: []runtime.Object{waitTestDeployment("podinfo", 2, 2), waitTestDeployment("podinfo-canary", 1, 0)},
+			opts:    WaitOptions{Kind: "Deployment", Name: "app=podinfo", Namespace: "podinfo", Expression: "status.readyReplicas == spec.replicas"},
+			expectedError: "timed out waiting for Deployment with label `app=podinfo` in namespace podinfo to be exists and match " +
+				"`status.readyReplicas == spec.replicas`",
+		},
+		{
+			name:          "never met",
+			objects:       []runtime.Object{waitTestDeployment("podinfo", 2, 1)},
+			opts:          WaitOptions{Kind: "Deployment", Name: "podinfo", Namespace: "podinfo", Condition: "{.status.readyReplicas}=2"},
+			expectedError: "timed out waiting for Deployment/podinfo in namespace podinfo to be {.status.readyReplicas}=2",
+		},
+		{
+			name:          "kind that is never served",
+			opts:          WaitOptions{Kind: "Widget", Name: "podinfo"},
+			expectedError: "timed out waiting for the kind Widget to be served",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			c := newWaitTestCluster(tt.objects...)
+			if tt.update != nil {
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					resource := c.Dynamic.Resource(deploymentGVR).Namespace(tt.update.GetNamespace())
+					if len(tt.objects) > 0 {
+						_, _ = resource.Update(ctx, tt.update, metav1.UpdateOptions{})
+						return
+					}
+					_, _ = resource.Create(ctx, tt.update, metav1.CreateOptions{})
+				}()
+			}
+
+			err := c.WaitFor(ctx, tt.opts)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
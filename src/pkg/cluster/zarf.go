@@ -6,7 +6,6 @@ package cluster
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -41,14 +40,13 @@ func (c *Cluster) GetDeployedZarfPackages(ctx context.Context) ([]types.Deployed
 		if !strings.HasPrefix(secret.Name, config.ZarfPackagePrefix) {
 			continue
 		}
-		var deployedPackage types.DeployedPackage
 		// Process the k8s secret into our internal structs
-		err := json.Unmarshal(secret.Data["data"], &deployedPackage)
+		deployedPackage, err := decodeDeployedPackage(secret.Data)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("unable to unmarshal the secret %s/%s", secret.Namespace, secret.Name))
 			continue
 		}
-		deployedPackages = append(deployedPackages, deployedPackage)
+		deployedPackages = append(deployedPackages, *deployedPackage)
 	}
 
 	return deployedPackages, errors.Join(errs...)
@@ -61,12 +59,7 @@ func (c *Cluster) GetDeployedPackage(ctx context.Context, packageName string) (*
 	if err != nil {
 		return nil, err
 	}
-	deployedPackage := &types.DeployedPackage{}
-	err = json.Unmarshal(secret.Data["data"], deployedPackage)
-	if err != nil {
-		return nil, err
-	}
-	return deployedPackage, nil
+	return decodeDeployedPackage(secret.Data)
 }
 
 // StripZarfLabelsAndSecretsFromNamespaces removes metadata and secrets from existing namespaces no longer manged by Zarf.
@@ -196,7 +189,7 @@ func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.Zarf
 		ComponentWebhooks:  componentWebhooks,
 	}
 
-	packageData, err := json.Marshal(deployedPackage)
+	packageData, err := DeployedPackageSecretData(deployedPackage)
 	if err != nil {
 		return nil, err
 	}
@@ -216,9 +209,7 @@ func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.Zarf
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"data": packageData,
-		},
+		Data: packageData,
 	}
 	updatedSecret, err := func() (*corev1.Secret, error) {
 		secret, err := c.Clientset.CoreV1().Secrets(deployedPackageSecret.Namespace).Create(ctx, deployedPackageSecret, metav1.CreateOptions{})
@@ -237,10 +228,7 @@ func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.Zarf
 	if err != nil {
 		return nil, fmt.Errorf("failed to record package deployment in secret '%s'", deployedPackageSecret.Name)
 	}
-	if err := json.Unmarshal(updatedSecret.Data["data"], &deployedPackage); err != nil {
-		return nil, err
-	}
-	return deployedPackage, nil
+	return decodeDeployedPackage(updatedSecret.Data)
 }
 
 // EnableRegHPAScaleDown enables the HPA scale down for the Zarf Registry.
@@ -35,3 +35,15 @@ func PromptVariable(variable v1alpha1.InteractiveVariable) (value string, err er
 
 	return value, survey.AskOne(prompt, &value)
 }
+
+// PromptContext prompts the user to type the name of a protected kubeconfig context to confirm it is targeted
+func PromptContext(context string) (bool, error) {
+	var name string
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("The kubeconfig context %q is protected, type its name to continue", context),
+	}
+	if err := survey.AskOne(prompt, &name); err != nil {
+		return false, err
+	}
+	return name == context, nil
+}
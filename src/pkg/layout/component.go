@@ -24,8 +24,10 @@ type ComponentPaths struct {
 	Charts         string
 	Values         string
 	Repos          string
+	Artifacts      string
 	Manifests      string
 	DataInjections string
+	Plugins        string
 }
 
 // Components contains paths for components.
@@ -112,12 +114,18 @@ func (c *Components) Unarchive(component v1alpha1.ZarfComponent) (err error) {
 	if len(component.Repos) > 0 {
 		cs.Repos = filepath.Join(cs.Base, ReposDir)
 	}
+	if len(component.Artifacts) > 0 {
+		cs.Artifacts = filepath.Join(cs.Base, ArtifactsDir)
+	}
 	if len(component.Manifests) > 0 {
 		cs.Manifests = filepath.Join(cs.Base, ManifestsDir)
 	}
 	if len(component.DataInjections) > 0 {
 		cs.DataInjections = filepath.Join(cs.Base, DataInjectionsDir)
 	}
+	if len(component.Plugins) > 0 {
+		cs.Plugins = filepath.Join(cs.Base, PluginsDir)
+	}
 	if c.Dirs == nil {
 		c.Dirs = make(map[string]*ComponentPaths)
 	}
@@ -137,6 +145,26 @@ func (c *Components) Unarchive(component v1alpha1.ZarfComponent) (err error) {
 	return os.Remove(tb)
 }
 
+// Move moves the directory of a component created in another components directory into this one.
+func (c *Components) Move(from *Components, component v1alpha1.ZarfComponent) (cp *ComponentPaths, err error) {
+	name := component.Name
+	if _, ok := from.Dirs[name]; !ok {
+		return nil, &fs.PathError{
+			Op:   "check dir map for",
+			Path: name,
+			Err:  ErrNotLoaded,
+		}
+	}
+	if err = helpers.CreateDirectory(c.Base, helpers.ReadWriteExecuteUser); err != nil {
+		return nil, err
+	}
+	if err = os.Rename(from.Dirs[name].Base, filepath.Join(c.Base, name)); err != nil {
+		return nil, err
+	}
+	delete(from.Dirs, name)
+	return c.Create(component)
+}
+
 // Create creates a new component directory structure.
 func (c *Components) Create(component v1alpha1.ZarfComponent) (cp *ComponentPaths, err error) {
 	name := component.Name
@@ -199,6 +227,13 @@ func (c *Components) Create(component v1alpha1.ZarfComponent) (cp *ComponentPath
 		}
 	}
 
+	if len(component.Artifacts) > 0 {
+		cp.Artifacts = filepath.Join(base, ArtifactsDir)
+		if err = helpers.CreateDirectory(cp.Artifacts, helpers.ReadWriteExecuteUser); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(component.Manifests) > 0 {
 		cp.Manifests = filepath.Join(base, ManifestsDir)
 		if err = helpers.CreateDirectory(cp.Manifests, helpers.ReadWriteExecuteUser); err != nil {
@@ -213,6 +248,13 @@ func (c *Components) Create(component v1alpha1.ZarfComponent) (cp *ComponentPath
 		}
 	}
 
+	if len(component.Plugins) > 0 {
+		cp.Plugins = filepath.Join(base, PluginsDir)
+		if err = helpers.CreateDirectory(cp.Plugins, helpers.ReadWriteExecuteUser); err != nil {
+			return nil, err
+		}
+	}
+
 	if c.Dirs == nil {
 		c.Dirs = make(map[string]*ComponentPaths)
 	}
@@ -12,19 +12,29 @@ const (
 	FilesDir          = "files"
 	ChartsDir         = "charts"
 	ReposDir          = "repos"
+	ArtifactsDir      = "artifacts"
 	ManifestsDir      = "manifests"
 	DataInjectionsDir = "data"
 	ValuesDir         = "values"
+	PluginsDir        = "plugins"
 
 	ZarfYAML  = "zarf.yaml"
 	Signature = "zarf.yaml.sig"
 	Checksums = "checksums.txt"
+	// ZarfLockYAML is the name of the lockfile pinning the images of a package definition to digests.
+	ZarfLockYAML = "zarf-lock.yaml"
+	// ZarfComposeLockYAML is the name of the lockfile pinning the OCI skeleton packages components are imported from.
+	ZarfComposeLockYAML = "zarf-compose-lock.yaml"
 
 	ImagesDir     = "images"
 	ComponentsDir = "components"
 
 	SBOMDir = "zarf-sbom"
 	SBOMTar = "sboms.tar"
+	// SBOMCompressedTar is the name of the SBOM tarball when it is stored zstd compressed.
+	SBOMCompressedTar = "sboms.tar.zst"
+	// VulnerabilityReport is the name of the report of the vulnerabilities found in the SBOMs, stored with the SBOMs.
+	VulnerabilityReport = "vulnerability-report.json"
 
 	IndexJSON = "index.json"
 	OCILayout = "oci-layout"
@@ -37,4 +47,6 @@ var (
 	ImagesBlobsDir = filepath.Join(ImagesDir, "blobs", "sha256")
 	// OCILayoutPath is the path to the oci-layout file
 	OCILayoutPath = filepath.Join(ImagesDir, OCILayout)
+	// SBOMTarballs are the names the SBOM tarball can have within a package.
+	SBOMTarballs = []string{SBOMTar, SBOMCompressedTar}
 )
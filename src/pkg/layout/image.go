@@ -5,6 +5,8 @@
 package layout
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 
 	"slices"
@@ -31,6 +33,18 @@ func (i *Images) AddBlob(blob string) {
 	}
 }
 
+// RemoveBlob removes a blob from the Images struct and deletes it.
+func (i *Images) RemoveBlob(blob string) error {
+	abs := filepath.Join(i.Base, "blobs", "sha256", blob)
+	i.Blobs = slices.DeleteFunc(i.Blobs, func(b string) bool {
+		return b == abs
+	})
+	if err := os.Remove(abs); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
 // AddV1Image adds a v1.Image to the Images struct.
 func (i *Images) AddV1Image(img v1.Image) error {
 	layers, err := img.Layers()
@@ -59,3 +73,39 @@ func (i *Images) AddV1Image(img v1.Image) error {
 
 	return nil
 }
+
+// AddV1ImageIndex adds a v1.ImageIndex and all of the images it references to the Images struct.
+func (i *Images) AddV1ImageIndex(idx v1.ImageIndex) error {
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+	for _, desc := range idxManifest.Manifests {
+		switch {
+		case desc.MediaType.IsImage():
+			img, err := idx.Image(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if err := i.AddV1Image(img); err != nil {
+				return err
+			}
+		case desc.MediaType.IsIndex():
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return err
+			}
+			if err := i.AddV1ImageIndex(child); err != nil {
+				return err
+			}
+		default:
+			i.AddBlob(desc.Digest.Hex)
+		}
+	}
+	idxSha, err := idx.Digest()
+	if err != nil {
+		return err
+	}
+	i.AddBlob(idxSha.Hex)
+	return nil
+}
@@ -5,7 +5,12 @@
 package layout
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"slices"
@@ -218,39 +223,165 @@ func (pp *PackagePaths) GenerateChecksums() (string, error) {
 
 // ArchivePackage creates an archive for a Zarf package.
 func (pp *PackagePaths) ArchivePackage(destinationTarball string, maxPackageSizeMB int) error {
-	spinner := message.NewProgressSpinner("Writing %s to %s", pp.Base, destinationTarball)
-	defer spinner.Stop()
+	return pp.archivePackage(destinationTarball, maxPackageSizeMB, nil, nil)
+}
+
+// ArchivePackageWithChecksums creates an archive for a Zarf package and generates its checksum file while the package
+// files are written to it, so they are only read once.
+//
+// finalize is called with the SHA256 checksum of the checksums.txt file once every other file is written and must
+// write the zarf.yaml and its signature, which are written to the end of the archive after the checksum file.
+func (pp *PackagePaths) ArchivePackageWithChecksums(destinationTarball string, maxPackageSizeMB int, finalize func(aggregateChecksum string) error) error {
+	return pp.archivePackage(destinationTarball, maxPackageSizeMB, nil, finalize)
+}
 
-	// Make the archive
-	archiveSrc := []string{pp.Base + string(os.PathSeparator)}
-	if err := archiver.Archive(archiveSrc, destinationTarball); err != nil {
+// ArchiveEncryptedPackageWithChecksums is ArchivePackageWithChecksums for an archive that is encrypted while it is
+// written, so the package is never written to the destination unencrypted. The encrypted archive is written to the
+// destination tarball with the utils.AgeSuffix extension, and split after it is encrypted.
+func (pp *PackagePaths) ArchiveEncryptedPackageWithChecksums(destinationTarball string, maxPackageSizeMB int, encrypt utils.Encryptor, finalize func(aggregateChecksum string) error) error {
+	return pp.archivePackage(destinationTarball, maxPackageSizeMB, encrypt, finalize)
+}
+
+func (pp *PackagePaths) archivePackage(destinationTarball string, maxPackageSizeMB int, encrypt utils.Encryptor, finalize func(aggregateChecksum string) error) (err error) {
+	format, err := archiver.ByExtension(destinationTarball)
+	if err != nil {
 		return fmt.Errorf("unable to create package: %w", err)
 	}
-	spinner.Updatef("Wrote %s to %s", pp.Base, destinationTarball)
+	tarball, ok := format.(archiver.Writer)
+	if !ok {
+		return fmt.Errorf("unable to create package: %s is not a supported archive format", destinationTarball)
+	}
 
-	fi, err := os.Stat(destinationTarball)
+	size, err := helpers.GetDirSize(pp.Base)
 	if err != nil {
-		return fmt.Errorf("unable to read the package archive: %w", err)
+		return fmt.Errorf("unable to read the package: %w", err)
+	}
+	if err := helpers.CreateDirectory(filepath.Dir(destinationTarball), helpers.ReadExecuteAllWriteUser); err != nil {
+		return fmt.Errorf("unable to create package: %w", err)
+	}
+	outputPath := destinationTarball
+	if encrypt != nil {
+		outputPath += utils.AgeSuffix
 	}
-	spinner.Successf("Package saved to %q", destinationTarball)
+	progressBar := message.NewProgressBar(size, fmt.Sprintf("Writing %s to %s", pp.Base, outputPath))
+	defer progressBar.Close()
 
-	// Convert Megabytes to bytes.
-	chunkSize := maxPackageSizeMB * 1000 * 1000
+	// Split the package while it is written when a chunk size was specified, converting Megabytes to bytes
+	chunkSize := int64(math.MaxInt64)
+	if maxPackageSizeMB > 0 {
+		chunkSize = int64(maxPackageSizeMB) * 1000 * 1000
+	}
+	out := newSplitWriter(outputPath, chunkSize)
+	defer func() {
+		if err != nil {
+			out.abort()
+		}
+	}()
+	var archive io.WriteCloser = out
+	if encrypt != nil {
+		archive, err = encrypt(out)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt package: %w", err)
+		}
+	}
+	if err := tarball.Create(archive); err != nil {
+		return fmt.Errorf("unable to create package: %w", err)
+	}
 
-	// If a chunk size was specified and the package is larger than the chunk size, split it into chunks.
-	if maxPackageSizeMB > 0 && fi.Size() > int64(chunkSize) {
-		if fi.Size()/int64(chunkSize) > 999 {
-			return fmt.Errorf("unable to split the package archive into multiple files: must be less than 1,000 files")
+	// The checksum file, zarf.yaml and signature depend on the checksums of every other file so they are written last
+	trailing := []string{Checksums, ZarfYAML, Signature}
+	checksummed := pp.Files()
+	checksums := []string{}
+	err = filepath.WalkDir(pp.Base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-		message.Notef("Package is larger than %dMB, splitting into multiple files", maxPackageSizeMB)
-		err := splitFile(destinationTarball, chunkSize)
+		rel, err := filepath.Rel(pp.Base, path)
 		if err != nil {
-			return fmt.Errorf("unable to split the package archive into multiple files: %w", err)
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." || slices.Contains(trailing, rel) {
+			return nil
+		}
+		if finalize == nil || d.IsDir() || checksummed[rel] == "" {
+			return writeArchiveFile(tarball, path, rel, progressBar, nil)
+		}
+		sum := sha256.New()
+		if err := writeArchiveFile(tarball, path, rel, progressBar, sum); err != nil {
+			return err
+		}
+		checksums = append(checksums, fmt.Sprintf("%x %s", sum.Sum(nil), rel))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create package: %w", err)
+	}
+
+	if finalize != nil {
+		slices.Sort(checksums)
+		checksumsData := []byte(strings.Join(checksums, "\n") + "\n")
+		if err := os.WriteFile(pp.Checksums, checksumsData, helpers.ReadWriteUser); err != nil {
+			return err
+		}
+		if err := finalize(fmt.Sprintf("%x", sha256.Sum256(checksumsData))); err != nil {
+			return err
+		}
+	}
+	for _, rel := range trailing {
+		path := filepath.Join(pp.Base, rel)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := writeArchiveFile(tarball, path, rel, progressBar, nil); err != nil {
+			return fmt.Errorf("unable to create package: %w", err)
+		}
+	}
+
+	if err := tarball.Close(); err != nil {
+		return fmt.Errorf("unable to create package: %w", err)
+	}
+	if encrypt != nil {
+		if err := archive.Close(); err != nil {
+			return fmt.Errorf("unable to encrypt package: %w", err)
 		}
 	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("unable to write the package archive: %w", err)
+	}
+	progressBar.Successf("Package saved to %q", outputPath)
 	return nil
 }
 
+// writeArchiveFile writes a file of the package to an archive, copying its contents to sum when it is not nil.
+func writeArchiveFile(tarball archiver.Writer, path, nameInArchive string, progressBar *message.ProgressBar, sum io.Writer) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	var contents io.ReadCloser
+	if info.Mode().IsRegular() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := io.Writer(progressBar)
+		if sum != nil {
+			w = io.MultiWriter(progressBar, sum)
+		}
+		contents = io.NopCloser(io.TeeReader(f, w))
+	}
+	return tarball.Write(archiver.File{
+		FileInfo: archiver.FileInfo{
+			FileInfo:   info,
+			CustomName: nameInArchive,
+			SourcePath: path,
+		},
+		ReadCloser: contents,
+	})
+}
+
 // AddImages sets the default image paths.
 func (pp *PackagePaths) AddImages() *PackagePaths {
 	pp.Images.Base = filepath.Join(pp.Base, ImagesDir)
@@ -289,7 +420,7 @@ func (pp *PackagePaths) SetFromPaths(paths []string) {
 			pp.Signature = filepath.Join(pp.Base, path)
 		case path == Checksums:
 			pp.Checksums = filepath.Join(pp.Base, path)
-		case path == SBOMTar:
+		case path == SBOMTar || path == SBOMCompressedTar:
 			pp.SBOMs.Path = filepath.Join(pp.Base, path)
 		case path == OCILayoutPath:
 			pp.Images.OCILayout = filepath.Join(pp.Base, path)
@@ -5,6 +5,10 @@
 package layout
 
 import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -12,6 +16,8 @@ import (
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 func TestPackageFiles(t *testing.T) {
@@ -165,6 +171,109 @@ func TestPackageFiles(t *testing.T) {
 	})
 }
 
+func TestArchivePackageWithChecksums(t *testing.T) {
+	t.Parallel()
+
+	pp := New(t.TempDir())
+	files := map[string]string{
+		"sboms.tar":         "sboms",
+		"components/c1.tar": "component",
+		"images/index.json": "{}",
+		"images/oci-layout": `{"imageLayoutVersion":"1.0.0"}`,
+		"images/blobs/sha256/" + strings.Repeat("1", 64): "blob",
+	}
+	paths := []string{}
+	for rel, contents := range files {
+		path := filepath.Join(pp.Base, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		paths = append(paths, filepath.FromSlash(rel))
+	}
+	pp.SetFromPaths(paths)
+
+	tarballPath := filepath.Join(t.TempDir(), "zarf-package-test-amd64.tar")
+	var aggregateChecksum string
+	err := pp.ArchivePackageWithChecksums(tarballPath, 0, func(checksum string) error {
+		aggregateChecksum = checksum
+		return os.WriteFile(pp.ZarfYAML, []byte("aggregateChecksum: "+checksum+"\n"), 0o600)
+	})
+	require.NoError(t, err)
+
+	// The streamed checksums match the ones generated by reading the files afterwards
+	streamed, err := os.ReadFile(pp.Checksums)
+	require.NoError(t, err)
+	expected, err := pp.GenerateChecksums()
+	require.NoError(t, err)
+	require.Equal(t, expected, aggregateChecksum)
+	generated, err := os.ReadFile(pp.Checksums)
+	require.NoError(t, err)
+	require.Equal(t, string(generated), string(streamed))
+
+	f, err := os.Open(tarballPath)
+	require.NoError(t, err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	names := []string{}
+	contents := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		b, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[hdr.Name] = string(b)
+	}
+	require.Equal(t, []string{Checksums, ZarfYAML}, names[len(names)-2:])
+	require.Equal(t, string(streamed), contents[Checksums])
+	require.Equal(t, "aggregateChecksum: "+aggregateChecksum+"\n", contents[ZarfYAML])
+	for rel, content := range files {
+		require.Equal(t, content, contents[rel])
+	}
+}
+
+func TestArchiveEncryptedPackageWithChecksums(t *testing.T) {
+	t.Parallel()
+
+	pp := New(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(pp.Base, "components"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(pp.Base, "components", "c1.tar"), []byte("component"), 0o600))
+	pp.SetFromPaths([]string{filepath.Join("components", "c1.tar")})
+
+	encrypt, err := utils.NewAgeEncryptor(nil, "passphrase")
+	require.NoError(t, err)
+	tarballPath := filepath.Join(t.TempDir(), "zarf-package-test-amd64.tar")
+	err = pp.ArchiveEncryptedPackageWithChecksums(tarballPath, 0, encrypt, func(checksum string) error {
+		return os.WriteFile(pp.ZarfYAML, []byte("aggregateChecksum: "+checksum+"\n"), 0o600)
+	})
+	require.NoError(t, err)
+
+	// Only the encrypted archive is written
+	require.NoFileExists(t, tarballPath)
+	encrypted, err := utils.IsAgeEncrypted(tarballPath + utils.AgeSuffix)
+	require.NoError(t, err)
+	require.True(t, encrypted)
+
+	decryptedPath := filepath.Join(t.TempDir(), "zarf-package-test-amd64.tar")
+	require.NoError(t, utils.DecryptAgeFile(tarballPath+utils.AgeSuffix, decryptedPath, nil, "passphrase"))
+	f, err := os.Open(decryptedPath)
+	require.NoError(t, err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	names := []string{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	require.Equal(t, []string{"components/", "components/c1.tar", Checksums, ZarfYAML}, names)
+}
+
 // normalizePath ensures that the filepaths being generated are normalized to the host OS.
 func normalizePath(path string) string {
 	if runtime.GOOS != "windows" {
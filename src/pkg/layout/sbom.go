@@ -5,10 +5,12 @@
 package layout
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/mholt/archiver/v3"
@@ -68,6 +70,39 @@ func (s *SBOMs) Archive() (err error) {
 	return os.RemoveAll(dir)
 }
 
+// Compress zstd compresses the package's archived SBOMs so they are stored separately from the rest of the package compression.
+func (s *SBOMs) Compress() (err error) {
+	if !s.IsTarball() {
+		return fmt.Errorf("unable to compress the SBOMs: %s is not a tarball", s.Path)
+	}
+	if strings.HasSuffix(s.Path, SBOMCompressedTar) {
+		return nil
+	}
+	tb := s.Path
+	compressed := filepath.Join(filepath.Dir(tb), SBOMCompressedTar)
+	if err := compressZstd(tb, compressed); err != nil {
+		return err
+	}
+	s.Path = compressed
+	return os.Remove(tb)
+}
+
+func compressZstd(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, out.Close())
+	}()
+	return archiver.NewZstd().Compress(in, out)
+}
+
 // StageSBOMViewFiles copies SBOM viewer HTML files to the Zarf SBOM directory.
 func (s *SBOMs) StageSBOMViewFiles() (sbomViewFiles, warnings []string, err error) {
 	if s.IsTarball() {
@@ -108,5 +143,5 @@ func (s *SBOMs) OutputSBOMFiles(outputDir, packageName string) (string, error) {
 
 // IsTarball returns true if the SBOMs are a tarball.
 func (s SBOMs) IsTarball() bool {
-	return !helpers.IsDir(s.Path) && filepath.Ext(s.Path) == ".tar"
+	return !helpers.IsDir(s.Path) && (filepath.Ext(s.Path) == ".tar" || strings.HasSuffix(s.Path, SBOMCompressedTar))
 }
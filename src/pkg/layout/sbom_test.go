@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSBOMsCompress(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	sboms := SBOMs{Path: filepath.Join(base, SBOMDir)}
+	require.NoError(t, os.MkdirAll(sboms.Path, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(sboms.Path, "sbom-viewer-test.html"), []byte("viewer"), 0o600))
+
+	require.Error(t, sboms.Compress())
+
+	require.NoError(t, sboms.Archive())
+	require.NoError(t, sboms.Compress())
+	require.Equal(t, filepath.Join(base, SBOMCompressedTar), sboms.Path)
+	require.True(t, sboms.IsTarball())
+	require.NoFileExists(t, filepath.Join(base, SBOMTar))
+
+	pp := New(base)
+	pp.SetFromPaths([]string{SBOMCompressedTar})
+	require.Equal(t, sboms.Path, pp.SBOMs.Path)
+	require.Contains(t, pp.Files(), SBOMCompressedTar)
+
+	require.NoError(t, sboms.Unarchive())
+	require.Equal(t, filepath.Join(base, SBOMDir), sboms.Path)
+	b, err := os.ReadFile(filepath.Join(sboms.Path, "sbom-viewer-test.html"))
+	require.NoError(t, err)
+	require.Equal(t, "viewer", string(b))
+}
@@ -9,7 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"hash"
 	"os"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -17,93 +17,110 @@ import (
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-// splitFile will split the file into chunks and remove the original file.
-func splitFile(srcPath string, chunkSize int) error {
-	srcFile, err := os.Open(srcPath)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-	fi, err := srcFile.Stat()
-	if err != nil {
-		return err
-	}
+// maxSplitParts is the most parts a package archive can be split into, the part000 header makes it less than 1,000 files.
+const maxSplitParts = 999
 
-	title := fmt.Sprintf("[0/%d] MB bytes written", fi.Size()/1000/1000)
-	progressBar := message.NewProgressBar(fi.Size(), title)
-	defer progressBar.Close()
+// splitWriter writes a package archive into parts of chunkSize bytes while it is being written, hashing it on the way so
+// the archive is never read back.
+//
+// Close renames the only part to the archive when it fit into a single part, otherwise it writes the part000 header.
+type splitWriter struct {
+	path      string
+	chunkSize int64
 
-	hash := sha256.New()
-	fileCount := 0
-	for {
-		path := fmt.Sprintf("%s.part%03d", srcPath, fileCount+1)
-		dstFile, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, helpers.ReadAllWriteUser)
-		if err != nil {
-			return err
-		}
-		defer dstFile.Close()
+	hash     hash.Hash
+	parts    []string
+	part     *os.File
+	partSize int64
+	size     int64
+}
 
-		written, copyErr := io.CopyN(dstFile, srcFile, int64(chunkSize))
-		if copyErr != nil && !errors.Is(copyErr, io.EOF) {
-			return err
-		}
-		progressBar.Add(int(written))
-		title := fmt.Sprintf("[%d/%d] MB bytes written", progressBar.GetCurrent()/1000/1000, fi.Size()/1000/1000)
-		progressBar.Updatef(title)
+func newSplitWriter(path string, chunkSize int64) *splitWriter {
+	return &splitWriter{path: path, chunkSize: chunkSize, hash: sha256.New()}
+}
 
-		_, err = dstFile.Seek(0, io.SeekStart)
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(hash, dstFile)
-		if err != nil {
-			return err
+func (sw *splitWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if sw.part == nil || sw.partSize == sw.chunkSize {
+			if err := sw.nextPart(); err != nil {
+				return written, err
+			}
 		}
-		err = dstFile.Close()
+		n := min(int64(len(p)), sw.chunkSize-sw.partSize)
+		m, err := sw.part.Write(p[:n])
+		sw.hash.Write(p[:m])
+		sw.partSize += int64(m)
+		sw.size += int64(m)
+		written += m
 		if err != nil {
-			return err
+			return written, err
 		}
+		p = p[m:]
+	}
+	return written, nil
+}
 
-		// EOF error could be returned on 0 bytes written.
-		if written == 0 {
-			err = os.Remove(path)
-			if err != nil {
-				return err
-			}
-			break
+func (sw *splitWriter) nextPart() error {
+	if sw.part != nil {
+		if err := sw.part.Close(); err != nil {
+			return err
 		}
+	}
+	if len(sw.parts) == maxSplitParts {
+		return errors.New("unable to split the package archive into multiple files: must be less than 1,000 files")
+	}
+	path := fmt.Sprintf("%s.part%03d", sw.path, len(sw.parts)+1)
+	part, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, helpers.ReadAllWriteUser)
+	if err != nil {
+		return err
+	}
+	sw.parts = append(sw.parts, path)
+	sw.part = part
+	sw.partSize = 0
+	return nil
+}
 
-		fileCount++
-		if errors.Is(copyErr, io.EOF) {
-			break
+// Close finishes the archive, leaving it in one file if it fit into a single part.
+func (sw *splitWriter) Close() error {
+	if sw.part != nil {
+		if err := sw.part.Close(); err != nil {
+			return err
 		}
+		sw.part = nil
 	}
 
-	// Remove original file
-	err = srcFile.Close()
-	if err != nil {
-		return err
+	if len(sw.parts) == 0 {
+		return os.WriteFile(sw.path, nil, helpers.ReadAllWriteUser)
 	}
-	err = os.Remove(srcPath)
-	if err != nil {
-		return err
+	if len(sw.parts) == 1 {
+		return os.Rename(sw.parts[0], sw.path)
 	}
 
-	// Write header file
 	data := types.ZarfSplitPackageData{
-		Count:     fileCount,
-		Bytes:     fi.Size(),
-		Sha256Sum: fmt.Sprintf("%x", hash.Sum(nil)),
+		Count:     len(sw.parts),
+		Bytes:     sw.size,
+		Sha256Sum: fmt.Sprintf("%x", sw.hash.Sum(nil)),
 	}
 	b, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("unable to marshal the split package data: %w", err)
 	}
-	path := fmt.Sprintf("%s.part000", srcPath)
+	path := fmt.Sprintf("%s.part000", sw.path)
 	if err := os.WriteFile(path, b, helpers.ReadAllWriteUser); err != nil {
 		return fmt.Errorf("unable to write the file %s: %w", path, err)
 	}
-	progressBar.Successf("Package split across %d files", fileCount+1)
-
+	message.Notef("Package is larger than %dMB, split across %d files", sw.chunkSize/1000/1000, len(sw.parts)+1)
 	return nil
 }
+
+// abort removes the parts written so far.
+func (sw *splitWriter) abort() {
+	if sw.part != nil {
+		sw.part.Close()
+		sw.part = nil
+	}
+	for _, part := range sw.parts {
+		_ = os.Remove(part)
+	}
+}
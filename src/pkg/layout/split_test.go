@@ -14,7 +14,7 @@ import (
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-func TestSplitFile(t *testing.T) {
+func TestSplitWriter(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -52,21 +52,19 @@ func TestSplitFile(t *testing.T) {
 			dir := t.TempDir()
 			name := "random"
 			p := filepath.Join(dir, name)
-			f, err := os.Create(p)
-			require.NoError(t, err)
 			b := make([]byte, tt.fileSize)
 			for i := range tt.fileSize {
 				b[i] = byte(tt.chunkSize)
 			}
-			require.NoError(t, err)
-			_, err = f.Write(b)
-			require.NoError(t, err)
-			f.Close()
-
-			err = splitFile(p, tt.chunkSize)
-			require.NoError(t, err)
+			sw := newSplitWriter(p, int64(tt.chunkSize))
+			// Write in pieces that do not line up with the parts
+			for i := 0; i < len(b); i += 7 {
+				_, err := sw.Write(b[i:min(i+7, len(b))])
+				require.NoError(t, err)
+			}
+			require.NoError(t, sw.Close())
 
-			_, err = os.Stat(p)
+			_, err := os.Stat(p)
 			require.ErrorIs(t, err, os.ErrNotExist)
 			entries, err := os.ReadDir(dir)
 			require.NoError(t, err)
@@ -94,3 +92,20 @@ func TestSplitFile(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitWriterSinglePart(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "package.tar.zst")
+	sw := newSplitWriter(p, 16)
+	_, err := sw.Write([]byte("sixteen bytes!!!"))
+	require.NoError(t, err)
+	require.NoError(t, sw.Close())
+
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	require.Equal(t, "sixteen bytes!!!", string(b))
+	entries, err := os.ReadDir(filepath.Dir(p))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
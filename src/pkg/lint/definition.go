@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// schemaURL is where the schema of a released version of Zarf is downloaded from.
+var schemaURL = fmt.Sprintf("https://raw.githubusercontent.com/%s/%%s/zarf.schema.json", config.GithubProject)
+
+// Schema returns the zarf.yaml schema of a version of Zarf, the embedded schema is returned for this version.
+func Schema(ctx context.Context, version string) ([]byte, error) {
+	if version == "" || version == config.CLIVersion {
+		return ZarfSchema.ReadFile("zarf.schema.json")
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(schemaURL, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the schema of Zarf %s: %w", version, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download the schema of Zarf %s: %s", version, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+var (
+	// variableReferenceRegex matches ###ZARF_VAR_NAME### and ${ZARF_VAR_NAME} or $ZARF_VAR_NAME in actions
+	variableReferenceRegex = regexp.MustCompile(`(?:###|\$\{?)ZARF_VAR_([A-Z0-9_]+)`)
+	// constantReferenceRegex matches ###ZARF_CONST_NAME### and ${ZARF_CONST_NAME} or $ZARF_CONST_NAME in actions
+	constantReferenceRegex = regexp.MustCompile(`(?:###|\$\{?)ZARF_CONST_([A-Z0-9_]+)`)
+)
+
+// ValidateDefinition checks a zarf.yaml against the schema and for mistakes that would otherwise only be found on
+// package create or deploy, without pulling anything. Imported components are checked when the package is created.
+func ValidateDefinition(path string) (v1alpha1.ZarfPackage, []PackageFinding, error) {
+	if helpers.IsDir(path) {
+		path = filepath.Join(path, layout.ZarfYAML)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	var untypedZarfPackage interface{}
+	if err := goyaml.Unmarshal(b, &untypedZarfPackage); err != nil {
+		return v1alpha1.ZarfPackage{}, nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	jsonSchema, err := ZarfSchema.ReadFile("zarf.schema.json")
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	findings, err := getSchemaFindings(jsonSchema, untypedZarfPackage)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	var pkg v1alpha1.ZarfPackage
+	if err := goyaml.Unmarshal(b, &pkg); err != nil {
+		// Values of the wrong type are already reported by the schema
+		if len(findings) > 0 {
+			locateFindings(findings, b)
+			return pkg, findings, nil
+		}
+		return pkg, nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+
+	if err := ValidatePackage(pkg); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			findings = append(findings, PackageFinding{Description: line, Severity: SevErr})
+		}
+	}
+
+	baseDir := filepath.Dir(path)
+	for i, component := range pkg.Components {
+		findings = append(findings, checkImageReferences(component, i)...)
+		findings = append(findings, checkLocalPaths(component, i, baseDir)...)
+	}
+	findings = append(findings, checkVariableReferences(pkg, string(b), baseDir)...)
+	locateFindings(findings, b)
+	return pkg, findings, nil
+}
+
+// checkImageReferences returns a finding for each image of a component that is not a valid image reference.
+func checkImageReferences(c v1alpha1.ZarfComponent, i int) []PackageFinding {
+	var findings []PackageFinding
+	for j, image := range c.Images {
+		if strings.HasPrefix(image, v1alpha1.ZarfImageLabelPrefix) || isTemplated(image) {
+			continue
+		}
+		if _, err := transform.ParseImageRef(image); err != nil {
+			findings = append(findings, PackageFinding{
+				YqPath:      fmt.Sprintf(".components.[%d].images.[%d]", i, j),
+				Description: "Invalid image reference",
+				Item:        image,
+				Severity:    SevErr,
+			})
+		}
+	}
+	return findings
+}
+
+// checkLocalPaths returns a finding for each local chart, values file, manifest, kustomization, file, data injection
+// and import path of a component that does not exist.
+func checkLocalPaths(c v1alpha1.ZarfComponent, i int, baseDir string) []PackageFinding {
+	var findings []PackageFinding
+	check := func(yqPath, description, path string) {
+		if path == "" || helpers.IsURL(path) || isTemplated(path) {
+			return
+		}
+		fullPath := path
+		if !filepath.IsAbs(path) {
+			fullPath = filepath.Join(baseDir, path)
+		}
+		if _, err := os.Stat(fullPath); errors.Is(err, os.ErrNotExist) {
+			findings = append(findings, PackageFinding{
+				YqPath:      yqPath,
+				Description: description,
+				Item:        path,
+				Severity:    SevErr,
+			})
+		}
+	}
+
+	for j, chart := range c.Charts {
+		check(fmt.Sprintf(".components.[%d].charts.[%d].localPath", i, j), "Chart not found", chart.LocalPath)
+		for k, valuesFile := range chart.ValuesFiles {
+			check(fmt.Sprintf(".components.[%d].charts.[%d].valuesFiles.[%d]", i, j, k), "Values file not found", valuesFile)
+		}
+	}
+	for j, manifest := range c.Manifests {
+		for k, file := range manifest.Files {
+			check(fmt.Sprintf(".components.[%d].manifests.[%d].files.[%d]", i, j, k), "Manifest not found", file)
+		}
+		for k, kustomization := range manifest.Kustomizations {
+			check(fmt.Sprintf(".components.[%d].manifests.[%d].kustomizations.[%d]", i, j, k), "Kustomization not found", kustomization)
+		}
+	}
+	for j, file := range c.Files {
+		check(fmt.Sprintf(".components.[%d].files.[%d].source", i, j), "File not found", file.Source)
+	}
+	for j, data := range c.DataInjections {
+		check(fmt.Sprintf(".components.[%d].dataInjections.[%d].source", i, j), "Data injection source not found", data.Source)
+	}
+	if c.Import.Path != "" && c.Import.URL == "" {
+		check(fmt.Sprintf(".components.[%d].import.path", i), "Imported package not found", filepath.Join(c.Import.Path, layout.ZarfYAML))
+	}
+	return findings
+}
+
+// checkVariableReferences returns a finding for each variable or constant referenced by the package definition, its
+// chart variables or its local values files and manifests that is not declared.
+func checkVariableReferences(pkg v1alpha1.ZarfPackage, definition string, baseDir string) []PackageFinding {
+	variables := map[string]bool{}
+	for _, v := range pkg.Variables {
+		variables[v.Name] = true
+	}
+	constants := map[string]bool{}
+	for _, c := range pkg.Constants {
+		constants[c.Name] = true
+	}
+	// Actions can set variables during deploy
+	for _, component := range pkg.Components {
+		actionSets := []v1alpha1.ZarfComponentActionSet{component.Actions.OnCreate, component.Actions.OnDeploy, component.Actions.OnRemove}
+		for _, as := range actionSets {
+			for _, actions := range [][]v1alpha1.ZarfComponentAction{as.Before, as.After, as.OnSuccess, as.OnFailure} {
+				for _, action := range actions {
+					for _, v := range action.SetVariables {
+						variables[v.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	var findings []PackageFinding
+	reported := map[string]bool{}
+	report := func(source, text string) {
+		for _, match := range variableReferenceRegex.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if variables[name] || reported["var/"+name] {
+				continue
+			}
+			reported["var/"+name] = true
+			findings = append(findings, PackageFinding{
+				Description: fmt.Sprintf("Variable referenced in %s is not declared in variables or set by an action, it must be set with --set on deploy", source),
+				Item:        name,
+				Severity:    SevWarn,
+			})
+		}
+		for _, match := range constantReferenceRegex.FindAllStringSubmatch(text, -1) {
+			name := match[1]
+			if constants[name] || reported["const/"+name] {
+				continue
+			}
+			reported["const/"+name] = true
+			findings = append(findings, PackageFinding{
+				Description: fmt.Sprintf("Constant referenced in %s is not declared in constants", source),
+				Item:        name,
+				Severity:    SevErr,
+			})
+		}
+	}
+
+	report(layout.ZarfYAML, definition)
+	for i, component := range pkg.Components {
+		for j, chart := range component.Charts {
+			for k, variable := range chart.Variables {
+				if !variables[variable.Name] && !reported["var/"+variable.Name] {
+					reported["var/"+variable.Name] = true
+					findings = append(findings, PackageFinding{
+						YqPath:      fmt.Sprintf(".components.[%d].charts.[%d].variables.[%d].name", i, j, k),
+						Description: "Chart variable is not declared in variables or set by an action, it must be set with --set on deploy",
+						Item:        variable.Name,
+						Severity:    SevWarn,
+					})
+				}
+			}
+			for _, valuesFile := range chart.ValuesFiles {
+				reportFile(report, valuesFile, baseDir)
+			}
+		}
+		for _, manifest := range component.Manifests {
+			for _, file := range manifest.Files {
+				reportFile(report, file, baseDir)
+			}
+		}
+	}
+	return findings
+}
+
+// reportFile reports the variable references of a local file, files that can not be read are already reported by
+// checkLocalPaths.
+func reportFile(report func(source, text string), path string, baseDir string) {
+	if helpers.IsURL(path) || isTemplated(path) {
+		return
+	}
+	fullPath := path
+	if !filepath.IsAbs(path) {
+		fullPath = filepath.Join(baseDir, path)
+	}
+	b, err := os.ReadFile(fullPath)
+	if err != nil {
+		return
+	}
+	report(path, string(b))
+}
+
+// isTemplated returns whether a value contains a package template or variable that is replaced on create.
+func isTemplated(value string) bool {
+	return strings.Contains(value, v1alpha1.ZarfPackageTemplatePrefix) || strings.Contains(value, v1alpha1.ZarfPackageVariablePrefix)
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	ZarfSchema = os.DirFS("../../..").(fs.ReadFileFS)
+
+	tests := []struct {
+		name             string
+		definition       string
+		files            []string
+		expectedFindings []PackageFinding
+	}{
+		{
+			name: "valid package",
+			definition: `kind: ZarfPackageConfig
+metadata:
+  name: valid
+variables:
+  - name: DOMAIN
+constants:
+  - name: VERSION
+    value: 1.0.0
+components:
+  - name: podinfo
+    required: true
+    images:
+      - ghcr.io/stefanprodan/podinfo:6.4.0
+      - docker-label:app=podinfo
+    charts:
+      - name: podinfo
+        namespace: podinfo
+        localPath: charts/podinfo
+        version: 6.4.0
+        valuesFiles:
+          - values.yaml
+    actions:
+      onDeploy:
+        after:
+          - cmd: echo ${ZARF_VAR_HOST} ###ZARF_VAR_DOMAIN### ###ZARF_CONST_VERSION###
+            setVariables:
+              - name: HOST
+`,
+			files: []string{"charts/podinfo/Chart.yaml", "values.yaml"},
+		},
+		{
+			name: "invalid package",
+			definition: `kind: ZarfPackageConfig
+metadata:
+  name: invalid
+components:
+  - name: podinfo
+    required: true
+    default: true
+    images:
+      - ghcr.io/stefanprodan/Podinfo:6.4.0
+    charts:
+      - name: podinfo
+        namespace: podinfo
+        localPath: charts/podinfo
+        version: 6.4.0
+        valuesFiles:
+          - values.yaml
+    manifests:
+      - name: config
+        files:
+          - config.yaml
+`,
+			files: []string{"values.yaml=host: ###ZARF_VAR_HOST###\nversion: ###ZARF_CONST_VERSION###"},
+			expectedFindings: []PackageFinding{
+				{Description: `component "podinfo" cannot be both required and default`, Severity: SevErr},
+				{YqPath: ".components.[0].images.[0]", Description: "Invalid image reference", Item: "ghcr.io/stefanprodan/Podinfo:6.4.0", Severity: SevErr, Line: 9, Column: 9},
+				{YqPath: ".components.[0].charts.[0].localPath", Description: "Chart not found", Item: "charts/podinfo", Severity: SevErr, Line: 13, Column: 9},
+				{YqPath: ".components.[0].manifests.[0].files.[0]", Description: "Manifest not found", Item: "config.yaml", Severity: SevErr, Line: 20, Column: 13},
+				{Description: "Variable referenced in values.yaml is not declared in variables or set by an action, it must be set with --set on deploy", Item: "HOST", Severity: SevWarn},
+				{Description: "Constant referenced in values.yaml is not declared in constants", Item: "VERSION", Severity: SevErr},
+			},
+		},
+		{
+			name: "invalid schema",
+			definition: `kind: ZarfPackageConfig
+metadata:
+  name: Invalid
+  descripton: misspelled
+components:
+  - name: podinfo
+    required: yes please
+    charts:
+      - namespace: podinfo
+`,
+			expectedFindings: []PackageFinding{
+				{
+					YqPath:      ".metadata.name",
+					Description: "Does not match pattern '^[a-z0-9][a-z0-9\\-]*$'",
+					Severity:    SevErr,
+					Line:        3,
+					Column:      3,
+					SchemaPath:  "#/$defs/ZarfMetadata/properties/name/pattern",
+					Suggestion:  `Use "invalid"`,
+				},
+				{
+					YqPath:      ".metadata.descripton",
+					Description: "Additional property descripton is not allowed",
+					Severity:    SevErr,
+					Line:        4,
+					Column:      3,
+					SchemaPath:  "#/$defs/ZarfMetadata/additionalProperties",
+					Suggestion:  `Did you mean "description"?`,
+				},
+				{
+					YqPath:      ".components.[0].required",
+					Description: "Invalid type. Expected: boolean, given: string",
+					Severity:    SevErr,
+					Line:        7,
+					Column:      5,
+					SchemaPath:  "#/$defs/ZarfComponent/properties/required/type",
+					Suggestion:  "Change the value to be of type boolean",
+				},
+				{
+					YqPath:      ".components.[0].charts.[0]",
+					Description: "name is required",
+					Severity:    SevErr,
+					Line:        9,
+					Column:      9,
+					SchemaPath:  "#/$defs/ZarfChart/required",
+					Suggestion:  "Add the name property",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "zarf.yaml"), []byte(tt.definition), 0o600)
+			require.NoError(t, err)
+			for _, file := range tt.files {
+				name, content, _ := strings.Cut(file, "=")
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			}
+
+			_, findings, err := ValidateDefinition(dir)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.expectedFindings, findings)
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	ZarfSchema = os.DirFS("../../..").(fs.ReadFileFS)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0.36.1/zarf.schema.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema"}`))
+	}))
+	defer srv.Close()
+	schemaURL = srv.URL + "/%s/zarf.schema.json"
+
+	embedded, err := Schema(context.Background(), "")
+	require.NoError(t, err)
+	require.Contains(t, string(embedded), "ZarfComponent")
+
+	released, err := Schema(context.Background(), "0.36.1")
+	require.NoError(t, err)
+	require.Equal(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema"}`, string(released))
+
+	_, err = Schema(context.Background(), "v0.0.0")
+	require.EqualError(t, err, "unable to download the schema of Zarf v0.0.0: 404 Not Found")
+}
@@ -7,6 +7,7 @@ package lint
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/fatih/color"
@@ -27,6 +28,13 @@ type PackageFinding struct {
 	// If it is not set the base package will be used when displaying the error
 	PackagePathOverride string
 	Severity            Severity
+	// Line and Column are the position of the key at YqPath in the zarf.yaml of the package, they are 0 when it is unknown
+	Line   int
+	Column int
+	// SchemaPath is the JSON pointer to the keyword of the zarf.yaml schema that failed for schema errors
+	SchemaPath string
+	// Suggestion is a suggested fix for the finding
+	Suggestion string
 }
 
 // Severity is the type of finding
@@ -45,6 +53,20 @@ func (f PackageFinding) itemizedDescription() string {
 	return fmt.Sprintf("%s - %s", f.Description, f.Item)
 }
 
+func (f PackageFinding) suggestedDescription() string {
+	if f.Suggestion == "" {
+		return f.itemizedDescription()
+	}
+	return fmt.Sprintf("%s. %s", f.itemizedDescription(), f.Suggestion)
+}
+
+func (f PackageFinding) location() string {
+	if f.Line == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", f.Line, f.Column)
+}
+
 func colorWrapSev(s Severity) string {
 	if s == SevErr {
 		return message.ColorWrap("Error", color.FgRed)
@@ -69,28 +91,31 @@ func PrintFindings(findings []PackageFinding, severity Severity, baseDir string,
 	}
 	mapOfFindingsByPath := GroupFindingsByPath(findings, packageName)
 
-	header := []string{"Type", "Path", "Message"}
+	header := []string{"Type", "Line", "Path", "Message"}
 
 	for _, findings := range mapOfFindingsByPath {
 		lintData := [][]string{}
 		for _, finding := range findings {
 			lintData = append(lintData, []string{
 				colorWrapSev(finding.Severity),
+				finding.location(),
 				message.ColorWrap(finding.YqPath, color.FgCyan),
-				finding.itemizedDescription(),
+				finding.suggestedDescription(),
 			})
 		}
-		var packagePathFromUser string
-		if helpers.IsOCIURL(findings[0].PackagePathOverride) {
-			packagePathFromUser = findings[0].PackagePathOverride
-		} else {
-			packagePathFromUser = filepath.Join(baseDir, findings[0].PackagePathOverride)
-		}
-		message.Notef("Linting package %q at %s", findings[0].PackageNameOverride, packagePathFromUser)
+		message.Notef("Linting package %q at %s", findings[0].PackageNameOverride, packagePathFromUser(findings[0], baseDir))
 		message.Table(header, lintData)
 	}
 }
 
+func packagePathFromUser(finding PackageFinding, baseDir string) string {
+	// Packages imported from OCI or git are shown by their URL
+	if strings.Contains(finding.PackagePathOverride, "://") {
+		return finding.PackagePathOverride
+	}
+	return filepath.Join(baseDir, finding.PackagePathOverride)
+}
+
 // GroupFindingsByPath groups findings by their package path
 func GroupFindingsByPath(findings []PackageFinding, packageName string) map[string][]PackageFinding {
 	for i := range findings {
@@ -5,10 +5,12 @@
 package lint
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
@@ -20,33 +22,47 @@ import (
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-// Validate lints the given Zarf package
-func Validate(ctx context.Context, createOpts types.ZarfCreateOptions) error {
-	var findings []PackageFinding
+// Validate lints the given Zarf package, printing the findings in a table or writing them to stdout as JSON or SARIF
+func Validate(ctx context.Context, createOpts types.ZarfCreateOptions, format OutputFormat) error {
 	if err := os.Chdir(createOpts.BaseDir); err != nil {
 		return fmt.Errorf("unable to access directory %q: %w", createOpts.BaseDir, err)
 	}
+	findings, err := ValidatePackageSchema()
+	if err != nil {
+		return err
+	}
 	var pkg v1alpha1.ZarfPackage
 	if err := utils.ReadYaml(layout.ZarfYAML, &pkg); err != nil {
-		return err
+		// Values of the wrong type are already reported by the schema
+		if !HasSevOrHigher(findings, SevErr) {
+			return err
+		}
+		return reportFindings(findings, createOpts.BaseDir, pkg.Metadata.Name, format)
 	}
 
 	compFindings, err := lintComponents(ctx, pkg, createOpts)
 	if err != nil {
 		return err
 	}
-	findings = append(findings, compFindings...)
-	schemaFindings, err := ValidatePackageSchema()
-	if err != nil {
-		return err
-	}
-	findings = append(findings, schemaFindings...)
+	locatePackageFindings(compFindings, ".")
+	findings = append(compFindings, findings...)
+	return reportFindings(findings, createOpts.BaseDir, pkg.Metadata.Name, format)
+}
 
-	if len(findings) == 0 {
-		message.Successf("0 findings for %q", pkg.Metadata.Name)
+func reportFindings(findings []PackageFinding, baseDir string, packageName string, format OutputFormat) error {
+	slices.SortStableFunc(findings, func(a, b PackageFinding) int {
+		return cmp.Or(cmp.Compare(a.PackagePathOverride, b.PackagePathOverride), cmp.Compare(a.Line, b.Line))
+	})
+	if format != OutputTable {
+		if err := WriteFindings(os.Stdout, format, findings, baseDir, packageName); err != nil {
+			return err
+		}
+	} else if len(findings) == 0 {
+		message.Successf("0 findings for %q", packageName)
 		return nil
+	} else {
+		PrintFindings(findings, SevWarn, baseDir, packageName)
 	}
-	PrintFindings(findings, SevWarn, createOpts.BaseDir, pkg.Metadata.Name)
 	if HasSevOrHigher(findings, SevErr) {
 		return errors.New("errors during lint")
 	}
@@ -56,13 +72,17 @@ func Validate(ctx context.Context, createOpts types.ZarfCreateOptions) error {
 func lintComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, createOpts types.ZarfCreateOptions) ([]PackageFinding, error) {
 	var findings []PackageFinding
 
+	skeletons, err := composer.LoadSkeletonResolver(layout.ZarfComposeLockYAML)
+	if err != nil {
+		return nil, err
+	}
 	for i, component := range pkg.Components {
 		arch := config.GetArch(pkg.Metadata.Architecture)
 		if !composer.CompatibleComponent(component, arch, createOpts.Flavor) {
 			continue
 		}
 
-		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, createOpts.Flavor)
+		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, createOpts.Flavor, createOpts.GitCredentials, skeletons)
 
 		if err != nil {
 			return nil, err
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+// locateFindings sets the line and column of the findings whose yq path is found in the given yaml.
+func locateFindings(findings []PackageFinding, b []byte) {
+	file, err := parser.ParseBytes(b, 0)
+	if err != nil || len(file.Docs) == 0 {
+		return
+	}
+	for i := range findings {
+		if findings[i].YqPath == "" || findings[i].Line != 0 {
+			continue
+		}
+		pos := locate(file.Docs[0].Body, splitYqPath(findings[i].YqPath))
+		if pos == nil {
+			continue
+		}
+		findings[i].Line = pos.Line
+		findings[i].Column = pos.Column
+	}
+}
+
+// locatePackageFindings sets the line and column of findings in the zarf.yaml of the package they originated from,
+// findings from packages imported from OCI or git are not located.
+func locatePackageFindings(findings []PackageFinding, baseDir string) {
+	byPath := map[string][]int{}
+	for i, finding := range findings {
+		if strings.Contains(finding.PackagePathOverride, "://") {
+			continue
+		}
+		byPath[finding.PackagePathOverride] = append(byPath[finding.PackagePathOverride], i)
+	}
+	for path, indexes := range byPath {
+		b, err := os.ReadFile(filepath.Join(baseDir, path, layout.ZarfYAML))
+		if err != nil {
+			continue
+		}
+		located := make([]PackageFinding, 0, len(indexes))
+		for _, i := range indexes {
+			located = append(located, findings[i])
+		}
+		locateFindings(located, b)
+		for j, i := range indexes {
+			findings[i] = located[j]
+		}
+	}
+}
+
+// splitYqPath splits a yq path such as .components.[0].name into its keys and indexes.
+func splitYqPath(yqPath string) []string {
+	if yqPath == "(root)" {
+		return nil
+	}
+	segments := []string{}
+	for _, segment := range strings.Split(yqPath, ".") {
+		segment = strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// locate returns the position of the node at path, using the key of mapping entries so that the position is that of
+// the line the entry starts on. When the path is not found the position of the closest node that is found is returned.
+func locate(node ast.Node, path []string) *token.Position {
+	if node == nil {
+		return nil
+	}
+	pos := nodePosition(node)
+	for _, segment := range path {
+		node = unwrapNode(node)
+		switch n := node.(type) {
+		case *ast.MappingNode:
+			mv := findMappingValue(n.Values, segment)
+			if mv == nil {
+				return pos
+			}
+			pos = mv.Key.GetToken().Position
+			node = mv.Value
+		case *ast.MappingValueNode:
+			mv := findMappingValue([]*ast.MappingValueNode{n}, segment)
+			if mv == nil {
+				return pos
+			}
+			pos = mv.Key.GetToken().Position
+			node = mv.Value
+		case *ast.SequenceNode:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(n.Values) {
+				return pos
+			}
+			node = n.Values[idx]
+			pos = nodePosition(node)
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// nodePosition returns the position a node starts at, which for mappings is their first key rather than its colon.
+func nodePosition(node ast.Node) *token.Position {
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingValueNode:
+		return n.Key.GetToken().Position
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			return n.Values[0].Key.GetToken().Position
+		}
+	}
+	return node.GetToken().Position
+}
+
+func findMappingValue(values []*ast.MappingValueNode, key string) *ast.MappingValueNode {
+	for _, mv := range values {
+		if scalar, ok := mv.Key.(ast.ScalarNode); ok && fmt.Sprint(scalar.GetValue()) == key {
+			return mv
+		}
+	}
+	return nil
+}
+
+func unwrapNode(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.TagNode:
+			node = n.Value
+		case *ast.AnchorNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocateFindings(t *testing.T) {
+	t.Parallel()
+	const definition = `kind: ZarfPackageConfig
+metadata:
+  name: locate
+x-defaults: &defaults
+  required: true
+components:
+  - name: first
+    <<: *defaults
+    images:
+      - nginx:1.25
+      - !!str busybox:1.36
+  - name: second
+    charts:
+      - namespace: podinfo
+        version: 6.4.0
+`
+	tests := []struct {
+		name           string
+		yqPath         string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{
+			name:           "root",
+			yqPath:         "(root)",
+			expectedLine:   1,
+			expectedColumn: 1,
+		},
+		{
+			name:           "key",
+			yqPath:         ".metadata.name",
+			expectedLine:   3,
+			expectedColumn: 3,
+		},
+		{
+			name:           "sequence item",
+			yqPath:         ".components.[0].images.[0]",
+			expectedLine:   10,
+			expectedColumn: 9,
+		},
+		{
+			name:           "tagged sequence item",
+			yqPath:         ".components.[0].images.[1]",
+			expectedLine:   11,
+			expectedColumn: 9,
+		},
+		{
+			name:           "mapping in a sequence",
+			yqPath:         ".components.[1].charts.[0]",
+			expectedLine:   14,
+			expectedColumn: 9,
+		},
+		{
+			name:           "missing key is located at its parent",
+			yqPath:         ".components.[1].charts.[0].name",
+			expectedLine:   14,
+			expectedColumn: 9,
+		},
+		{
+			name:           "index out of range is located at its sequence",
+			yqPath:         ".components.[2]",
+			expectedLine:   6,
+			expectedColumn: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			findings := []PackageFinding{{YqPath: tt.yqPath}, {Description: "unlocated"}}
+			locateFindings(findings, []byte(definition))
+			require.Equal(t, tt.expectedLine, findings[0].Line)
+			require.Equal(t, tt.expectedColumn, findings[0].Column)
+			require.Zero(t, findings[1].Line)
+		})
+	}
+}
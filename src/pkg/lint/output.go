@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+// OutputFormat is the format lint findings are written in.
+type OutputFormat string
+
+// The formats lint findings can be written in
+const (
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputSARIF OutputFormat = "sarif"
+)
+
+// sarifSchema is the schema of the version of SARIF findings are written in.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// JSONFinding is a finding as it is written with --output json.
+type JSONFinding struct {
+	Severity   string `json:"severity"`
+	Package    string `json:"package"`
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Path       string `json:"path,omitempty"`
+	SchemaPath string `json:"schemaPath,omitempty"`
+	Message    string `json:"message"`
+	Item       string `json:"item,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// WriteFindings writes the findings as JSON or SARIF, located in the zarf.yaml of the package they originated from
+// relative to baseDir.
+func WriteFindings(w io.Writer, format OutputFormat, findings []PackageFinding, baseDir string, packageName string) error {
+	GroupFindingsByPath(findings, packageName)
+
+	var out any
+	switch format {
+	case OutputJSON:
+		jsonFindings := []JSONFinding{}
+		for _, finding := range findings {
+			jsonFindings = append(jsonFindings, JSONFinding{
+				Severity:   finding.Severity.level(),
+				Package:    finding.PackageNameOverride,
+				File:       findingFile(finding, baseDir),
+				Line:       finding.Line,
+				Column:     finding.Column,
+				Path:       finding.YqPath,
+				SchemaPath: finding.SchemaPath,
+				Message:    finding.Description,
+				Item:       finding.Item,
+				Suggestion: finding.Suggestion,
+			})
+		}
+		out = jsonFindings
+	case OutputSARIF:
+		out = sarifFindings(findings, baseDir)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func sarifFindings(findings []PackageFinding, baseDir string) sarifLog {
+	rules := []sarifRule{}
+	results := []sarifResult{}
+	seenRules := map[string]bool{}
+	for _, finding := range findings {
+		ruleID := finding.ruleID()
+		if !seenRules[ruleID] {
+			seenRules[ruleID] = true
+			rules = append(rules, sarifRule{ID: ruleID})
+		}
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(findingFile(finding, baseDir))},
+			},
+		}
+		// SARIF lines start at 1, findings that could not be located are reported on the file
+		if finding.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+		}
+		if finding.YqPath != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: finding.YqPath}}
+		}
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			Level:     finding.Severity.level(),
+			Message:   sarifMessage{Text: finding.suggestedDescription()},
+			Locations: []sarifLocation{location},
+		})
+	}
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "zarf",
+				Version:        config.CLIVersion,
+				InformationURI: "https://zarf.dev",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// ruleID identifies the kind of a finding, schema errors are identified by the keyword of the schema that failed.
+func (f PackageFinding) ruleID() string {
+	if f.SchemaPath != "" {
+		return "schema/" + path.Base(f.SchemaPath)
+	}
+	return "lint"
+}
+
+// findingFile returns the zarf.yaml a finding originated from, or the URL of the package for remote packages.
+func findingFile(finding PackageFinding, baseDir string) string {
+	if strings.Contains(finding.PackagePathOverride, "://") {
+		return finding.PackagePathOverride
+	}
+	return filepath.Join(packagePathFromUser(finding, baseDir), layout.ZarfYAML)
+}
+
+func (s Severity) level() string {
+	if s == SevErr {
+		return "error"
+	} else if s == SevWarn {
+		return "warning"
+	}
+	return "none"
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package lint contains functions for verifying zarf yaml files are valid
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFindings(t *testing.T) {
+	t.Parallel()
+	newFindings := func() []PackageFinding {
+		return []PackageFinding{
+			{
+				YqPath:      ".metadata.descripton",
+				Description: "Additional property descripton is not allowed",
+				Severity:    SevErr,
+				Line:        4,
+				Column:      3,
+				SchemaPath:  "#/$defs/ZarfMetadata/additionalProperties",
+				Suggestion:  `Did you mean "description"?`,
+			},
+			{
+				YqPath:              ".components.[0].images.[0]",
+				Description:         "Image not pinned with digest",
+				Item:                "nginx:1.25",
+				Severity:            SevWarn,
+				Line:                9,
+				Column:              9,
+				PackageNameOverride: "imported",
+				PackagePathOverride: "imported",
+			},
+			{
+				Description:         "Unpinned repository",
+				Severity:            SevWarn,
+				PackagePathOverride: "oci://ghcr.io/zarf-dev/packages/imported:1.0.0",
+			},
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, WriteFindings(&buf, OutputJSON, newFindings(), "packages/app", "app"))
+		var findings []JSONFinding
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &findings))
+		expected := []JSONFinding{
+			{
+				Severity:   "error",
+				Package:    "app",
+				File:       "packages/app/zarf.yaml",
+				Line:       4,
+				Column:     3,
+				Path:       ".metadata.descripton",
+				SchemaPath: "#/$defs/ZarfMetadata/additionalProperties",
+				Message:    "Additional property descripton is not allowed",
+				Suggestion: `Did you mean "description"?`,
+			},
+			{
+				Severity: "warning",
+				Package:  "imported",
+				File:     "packages/app/imported/zarf.yaml",
+				Line:     9,
+				Column:   9,
+				Path:     ".components.[0].images.[0]",
+				Message:  "Image not pinned with digest",
+				Item:     "nginx:1.25",
+			},
+			{
+				Severity: "warning",
+				Package:  "app",
+				File:     "oci://ghcr.io/zarf-dev/packages/imported:1.0.0",
+				Message:  "Unpinned repository",
+			},
+		}
+		require.Equal(t, expected, findings)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, WriteFindings(&buf, OutputSARIF, newFindings(), "packages/app", "app"))
+		var log sarifLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+		require.Equal(t, "2.1.0", log.Version)
+		require.Len(t, log.Runs, 1)
+		require.Equal(t, []sarifRule{{ID: "schema/additionalProperties"}, {ID: "lint"}}, log.Runs[0].Tool.Driver.Rules)
+		expected := []sarifResult{
+			{
+				RuleID:  "schema/additionalProperties",
+				Level:   "error",
+				Message: sarifMessage{Text: `Additional property descripton is not allowed. Did you mean "description"?`},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "packages/app/zarf.yaml"},
+						Region:           &sarifRegion{StartLine: 4, StartColumn: 3},
+					},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: ".metadata.descripton"}},
+				}},
+			},
+			{
+				RuleID:  "lint",
+				Level:   "warning",
+				Message: sarifMessage{Text: "Image not pinned with digest - nginx:1.25"},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "packages/app/imported/zarf.yaml"},
+						Region:           &sarifRegion{StartLine: 9, StartColumn: 9},
+					},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: ".components.[0].images.[0]"}},
+				}},
+			},
+			{
+				RuleID:  "lint",
+				Level:   "warning",
+				Message: sarifMessage{Text: "Unpinned repository"},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "oci://ghcr.io/zarf-dev/packages/imported:1.0.0"},
+					},
+				}},
+			},
+		}
+		require.Equal(t, expected, log.Runs[0].Results)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		t.Parallel()
+		err := WriteFindings(&bytes.Buffer{}, OutputTable, newFindings(), ".", "app")
+		require.EqualError(t, err, `unsupported output format "table"`)
+	})
+}
@@ -14,6 +14,10 @@ import (
 )
 
 func isPinnedImage(image string) (bool, error) {
+	// Images selected by a label are resolved to the tags of local images on package create
+	if strings.HasPrefix(image, v1alpha1.ZarfImageLabelPrefix) {
+		return false, nil
+	}
 	transformedImage, err := transform.ParseImageRef(image)
 	if err != nil {
 		if strings.Contains(image, v1alpha1.ZarfPackageTemplatePrefix) ||
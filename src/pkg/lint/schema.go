@@ -5,13 +5,20 @@
 package lint
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/fs"
+	"os"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/agnivade/levenshtein"
+	goyaml "github.com/goccy/go-yaml"
 	"github.com/xeipuuv/gojsonschema"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
-	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 // ZarfSchema is exported so main.go can embed the schema file
@@ -19,8 +26,12 @@ var ZarfSchema fs.ReadFileFS
 
 // ValidatePackageSchema checks the Zarf package in the current directory against the Zarf schema
 func ValidatePackageSchema() ([]PackageFinding, error) {
+	b, err := os.ReadFile(layout.ZarfYAML)
+	if err != nil {
+		return nil, err
+	}
 	var untypedZarfPackage interface{}
-	if err := utils.ReadYaml(layout.ZarfYAML, &untypedZarfPackage); err != nil {
+	if err := goyaml.Unmarshal(b, &untypedZarfPackage); err != nil {
 		return nil, err
 	}
 
@@ -29,7 +40,12 @@ func ValidatePackageSchema() ([]PackageFinding, error) {
 		return nil, err
 	}
 
-	return getSchemaFindings(jsonSchema, untypedZarfPackage)
+	findings, err := getSchemaFindings(jsonSchema, untypedZarfPackage)
+	if err != nil {
+		return nil, err
+	}
+	locateFindings(findings, b)
+	return findings, nil
 }
 
 func makeFieldPathYqCompat(field string) string {
@@ -51,11 +67,23 @@ func getSchemaFindings(jsonSchema []byte, obj interface{}) ([]PackageFinding, er
 	if err != nil {
 		return nil, err
 	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(jsonSchema, &schema); err != nil {
+		return nil, err
+	}
 
 	for _, schemaErr := range schemaErrors {
+		yqPath := makeFieldPathYqCompat(schemaErr.Field())
+		// Point at the property that is not allowed rather than the object it is in
+		if property, ok := schemaErr.Details()["property"].(string); ok && schemaErr.Type() == "additional_property_not_allowed" {
+			yqPath = strings.TrimPrefix(yqPath, "(root)") + "." + property
+		}
+		schemaPath, subSchema := schemaLocation(schema, schemaErr.Field(), schemaKeyword(schemaErr.Type()))
 		findings = append(findings, PackageFinding{
-			YqPath:      makeFieldPathYqCompat(schemaErr.Field()),
+			YqPath:      yqPath,
+			SchemaPath:  schemaPath,
 			Description: schemaErr.Description(),
+			Suggestion:  suggestSchemaFix(schemaErr, subSchema),
 			Severity:    SevErr,
 		})
 	}
@@ -63,6 +91,145 @@ func getSchemaFindings(jsonSchema []byte, obj interface{}) ([]PackageFinding, er
 	return findings, nil
 }
 
+// schemaKeyword returns the JSON schema keyword that failed for a type of schema error.
+func schemaKeyword(errType string) string {
+	keywords := map[string]string{
+		"invalid_type":                    "type",
+		"additional_property_not_allowed": "additionalProperties",
+		"number_not":                      "not",
+		"number_any_of":                   "anyOf",
+		"number_one_of":                   "oneOf",
+		"number_all_of":                   "allOf",
+		"array_min_items":                 "minItems",
+		"array_max_items":                 "maxItems",
+		"unique":                          "uniqueItems",
+		"string_gte":                      "minLength",
+		"string_lte":                      "maxLength",
+		"number_gte":                      "minimum",
+		"number_lte":                      "maximum",
+		"number_gt":                       "exclusiveMinimum",
+		"number_lt":                       "exclusiveMaximum",
+	}
+	if keyword, ok := keywords[errType]; ok {
+		return keyword
+	}
+	return errType
+}
+
+// schemaLocation returns the JSON pointer to the keyword of the schema that the value at field failed, along with
+// the schema the keyword is in. References are followed so the pointer is to the definition the keyword is in.
+func schemaLocation(schema map[string]interface{}, field string, keyword string) (string, map[string]interface{}) {
+	pointer, node := dereference(schema, "#", schema)
+	if field != "(root)" {
+		for _, segment := range strings.Split(field, ".") {
+			if properties, ok := node["properties"].(map[string]interface{}); ok {
+				if property, ok := properties[segment].(map[string]interface{}); ok {
+					pointer, node = dereference(schema, pointer+"/properties/"+escapePointer(segment), property)
+					continue
+				}
+			}
+			if items, ok := node["items"].(map[string]interface{}); ok && isIndex(segment) {
+				pointer, node = dereference(schema, pointer+"/items", items)
+				continue
+			}
+			break
+		}
+	}
+	return pointer + "/" + keyword, node
+}
+
+// dereference follows the $ref of a schema to a definition in the same document.
+func dereference(root map[string]interface{}, pointer string, node map[string]interface{}) (string, map[string]interface{}) {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return pointer, node
+		}
+		target := root
+		for _, segment := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			next, ok := target[segment].(map[string]interface{})
+			if !ok {
+				return pointer, node
+			}
+			target = next
+		}
+		pointer, node = ref, target
+	}
+}
+
+func isIndex(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+func escapePointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+// suggestSchemaFix returns a suggested fix for a schema error, or an empty string when there is none.
+func suggestSchemaFix(schemaErr gojsonschema.ResultError, subSchema map[string]interface{}) string {
+	details := schemaErr.Details()
+	switch schemaErr.Type() {
+	case "additional_property_not_allowed":
+		property, _ := details["property"].(string)
+		properties, _ := subSchema["properties"].(map[string]interface{})
+		if closest := closestProperty(property, properties); closest != "" {
+			return fmt.Sprintf("Did you mean %q?", closest)
+		}
+		return fmt.Sprintf("Remove %s, it is not a property of this object", property)
+	case "required":
+		return fmt.Sprintf("Add the %s property", details["property"])
+	case "invalid_type":
+		if details["expected"] == "string" && details["given"] != "null" {
+			return "Quote the value to make it a string"
+		}
+		return fmt.Sprintf("Change the value to be of type %s", details["expected"])
+	case "enum":
+		return fmt.Sprintf("Use one of %s", details["allowed"])
+	case "pattern":
+		pattern, _ := details["pattern"].(string)
+		value, ok := schemaErr.Value().(string)
+		re, err := regexp.Compile(pattern)
+		if ok && err == nil {
+			for _, candidate := range []string{strings.ToLower(value), strings.ToUpper(value), strings.Trim(strings.ToLower(value), "-")} {
+				if candidate != "" && re.MatchString(candidate) {
+					return fmt.Sprintf("Use %q", candidate)
+				}
+			}
+		}
+		return fmt.Sprintf("Change the value to match %s", pattern)
+	case "number_not":
+		if not, ok := subSchema["not"].(map[string]interface{}); ok {
+			if pattern, ok := not["pattern"].(string); ok && strings.Contains(pattern, v1alpha1.ZarfPackageTemplatePrefix) {
+				return "Package templates are not evaluated here, use the value itself"
+			}
+		}
+	case "array_min_items":
+		return fmt.Sprintf("Add at least %v item(s)", details["min"])
+	}
+	return ""
+}
+
+// closestProperty returns the property closest to a misspelled one, or an empty string when none is close.
+func closestProperty(property string, properties map[string]interface{}) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	closest := ""
+	closestDistance := len(property)/3 + 1
+	for _, name := range names {
+		if strings.EqualFold(name, property) {
+			return name
+		}
+		if d := levenshtein.ComputeDistance(name, property); d <= closestDistance && (closest == "" || d < levenshtein.ComputeDistance(closest, property)) {
+			closest = name
+		}
+	}
+	return closest
+}
+
 func runSchema(jsonSchema []byte, pkg interface{}) ([]gojsonschema.ResultError, error) {
 	schemaLoader := gojsonschema.NewBytesLoader(jsonSchema)
 	documentLoader := gojsonschema.NewGoLoader(pkg)
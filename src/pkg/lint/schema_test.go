@@ -184,6 +184,8 @@ components:
 				Description: "Invalid type. Expected: array, given: null",
 				Severity:    SevErr,
 				YqPath:      ".components",
+				SchemaPath:  "#/properties/components/type",
+				Suggestion:  "Change the value to be of type array",
 			},
 		}
 		require.ElementsMatch(t, expected, findings)
@@ -7,10 +7,15 @@ package lint
 import (
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/artifacts"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
@@ -44,20 +49,32 @@ const (
 	PkgValidateErrConstant                = "invalid package constant: %w"
 	PkgValidateErrYOLONoOCI               = "OCI images not allowed in YOLO"
 	PkgValidateErrYOLONoGit               = "git repos not allowed in YOLO"
+	PkgValidateErrYOLONoArtifacts         = "artifacts not allowed in YOLO"
 	PkgValidateErrYOLONoArch              = "cluster architecture not allowed in YOLO"
 	PkgValidateErrYOLONoDistro            = "cluster distros not allowed in YOLO"
+	PkgValidateErrYOLONoAgent             = "requiring the Zarf agent not allowed in YOLO"
 	PkgValidateErrComponentNameNotUnique  = "component name %q is not unique"
 	PkgValidateErrComponentReqDefault     = "component %q cannot be both required and default"
 	PkgValidateErrComponentReqGrouped     = "component %q cannot be both required and grouped"
 	PkgValidateErrChartNameNotUnique      = "chart name %q is not unique"
 	PkgValidateErrChart                   = "invalid chart definition: %w"
 	PkgValidateErrManifestNameNotUnique   = "manifest name %q is not unique"
+	PkgValidateErrOperatorNameNotUnique   = "operator name %q is not unique"
+	PkgValidateErrOperator                = "invalid operator definition: %w"
+	PkgValidateErrOperatorName            = "operator name %q is not a valid Kubernetes resource name: %s"
+	PkgValidateErrOperatorNamespace       = "operator %q must include a namespace"
+	PkgValidateErrOperatorCatalogImage    = "operator %q must include a catalogImage"
+	PkgValidateErrOperatorCatalogImageRef = "operator %q catalogImage %q is not a valid image reference: %w"
 	PkgValidateErrManifest                = "invalid manifest definition: %w"
+	PkgValidateErrPluginName              = "plugin name %q of component %q must be lowercase letters, numbers and hyphens"
+	PkgValidateErrArtifacts               = "invalid artifacts of component %q: %w"
 	PkgValidateErrGroupMultipleDefaults   = "group %q has multiple defaults (%q, %q)"
 	PkgValidateErrGroupOneComponent       = "group %q only has one component (%q)"
 	PkgValidateErrAction                  = "invalid action: %w"
 	PkgValidateErrActionCmdWait           = "action %q cannot be both a command and wait action"
 	PkgValidateErrActionClusterNetwork    = "a single wait action must contain only one of cluster or network"
+	PkgValidateErrActionWaitExpression    = "wait action expression %q must not contain single quotes, use double quoted CEL strings"
+	PkgValidateErrHealthCheck             = "health check of component %q must include a kind and name"
 	PkgValidateErrChartName               = "chart %q exceed the maximum length of %d characters"
 	PkgValidateErrChartNamespaceMissing   = "chart %q must include a namespace"
 	PkgValidateErrChartURLOrPath          = "chart %q must have either a url or localPath"
@@ -65,6 +82,15 @@ const (
 	PkgValidateErrManifestFileOrKustomize = "manifest %q must have at least one file or kustomization"
 	PkgValidateErrManifestNameLength      = "manifest %q exceed the maximum length of %d characters"
 	PkgValidateErrVariable                = "invalid package variable: %w"
+	PkgValidateErrImageSignature          = "invalid image signature: %w"
+	PkgValidateErrImageSignatureKeyless   = "image signature must have either a key or a keyless identity and issuer"
+	PkgValidateErrImageSignatureRegexp    = "image signature %s %q is not a valid regular expression: %w"
+	PkgValidateErrImageSignatureNoImages  = "image signature images %q do not match any images of component %q"
+	PkgValidateErrSquashImagesNoImages    = "squash image %q does not match any images of component %q"
+	PkgValidateErrFile                    = "invalid file: %w"
+	PkgValidateErrFileMode                = "file %q mode %q is not an octal file mode"
+	PkgValidateErrFileOwner               = "file %q %s must not be negative"
+	PkgValidateErrFileSystemdUnit         = "file %q is installed as a systemd unit but its target does not end in a unit type (%s)"
 )
 
 // ValidatePackage runs all validation checks on the package.
@@ -82,6 +108,9 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 	groupDefault := make(map[string]string)
 	groupedComponents := make(map[string][]string)
 	if pkg.Metadata.YOLO {
+		if pkg.Metadata.RequireAgent {
+			err = errors.Join(err, errors.New(PkgValidateErrYOLONoAgent))
+		}
 		for _, component := range pkg.Components {
 			if len(component.Images) > 0 {
 				err = errors.Join(err, errors.New(PkgValidateErrYOLONoOCI))
@@ -89,6 +118,9 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			if len(component.Repos) > 0 {
 				err = errors.Join(err, errors.New(PkgValidateErrYOLONoGit))
 			}
+			if len(component.Artifacts) > 0 {
+				err = errors.Join(err, errors.New(PkgValidateErrYOLONoArtifacts))
+			}
 			if component.Only.Cluster.Architecture != "" {
 				err = errors.Join(err, errors.New(PkgValidateErrYOLONoArch))
 			}
@@ -133,6 +165,53 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 				err = errors.Join(err, fmt.Errorf(PkgValidateErrManifest, manifestErr))
 			}
 		}
+		uniqueOperatorNames := make(map[string]bool)
+		for _, operator := range component.Operators {
+			// ensure operator name is unique
+			if _, ok := uniqueOperatorNames[operator.Name]; ok {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrOperatorNameNotUnique, operator.Name))
+			}
+			uniqueOperatorNames[operator.Name] = true
+			if operatorErr := validateOperator(operator); operatorErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrOperator, operatorErr))
+			}
+		}
+		for _, healthCheck := range component.HealthChecks {
+			if healthCheck.Kind == "" || healthCheck.Name == "" {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrHealthCheck, component.Name))
+			}
+		}
+		for _, plugin := range component.Plugins {
+			if !IsLowercaseNumberHyphenNoStartHyphen(plugin.Name) {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPluginName, plugin.Name, component.Name))
+			}
+		}
+		for _, set := range component.Artifacts {
+			if artifactsErr := artifacts.Validate(set); artifactsErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrArtifacts, component.Name, artifactsErr))
+			}
+		}
+		for _, file := range component.Files {
+			if fileErr := validateFile(file); fileErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrFile, fileErr))
+			}
+		}
+		for _, signature := range component.ImageSignatures {
+			if signatureErr := validateImageSignature(signature); signatureErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrImageSignature, signatureErr))
+			}
+			if len(signature.Images) > 0 && !slices.ContainsFunc(component.Images, signature.Matches) {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrImageSignatureNoImages, signature.Images, component.Name))
+			}
+		}
+		for _, pattern := range component.SquashImages {
+			if !slices.ContainsFunc(component.Images, func(image string) bool {
+				matched, _ := path.Match(pattern, image)
+				return matched
+			}) {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrSquashImagesNoImages, pattern, component.Name))
+			}
+		}
 		if actionsErr := validateActions(component.Actions); actionsErr != nil {
 			err = errors.Join(err, fmt.Errorf("%q: %w", component.Name, actionsErr))
 		}
@@ -227,6 +306,11 @@ func validateAction(action v1alpha1.ZarfComponentAction) error {
 		if action.Wait.Cluster == nil && action.Wait.Network == nil {
 			err = errors.Join(err, errors.New(PkgValidateErrActionClusterNetwork))
 		}
+
+		// The expression is passed to zarf tools wait-for in single quotes
+		if action.Wait.Cluster != nil && strings.Contains(action.Wait.Cluster.Expression, "'") {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionWaitExpression, action.Wait.Cluster.Expression))
+		}
 	}
 
 	return err
@@ -287,6 +371,70 @@ func validateChart(chart v1alpha1.ZarfChart) error {
 	return err
 }
 
+// validateOperator runs all validation checks on an operator.
+func validateOperator(operator v1alpha1.ZarfOperator) error {
+	var err error
+
+	// The name is used for the OLM resources Zarf creates for the operator
+	if errs := validation.IsDNS1123Label(operator.Name); len(errs) > 0 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrOperatorName, operator.Name, strings.Join(errs, "; ")))
+	}
+
+	if operator.Namespace == "" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrOperatorNamespace, operator.Name))
+	}
+
+	if operator.CatalogImage == "" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrOperatorCatalogImage, operator.Name))
+	} else if _, refErr := transform.ParseImageRef(operator.CatalogImage); refErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrOperatorCatalogImageRef, operator.Name, operator.CatalogImage, refErr))
+	}
+
+	return err
+}
+
+// validateImageSignature runs all validation checks on an image signature.
+func validateImageSignature(signature v1alpha1.ZarfImageSignature) error {
+	isKeyless := signature.Identity != "" || signature.Issuer != ""
+	if signature.Key != "" && isKeyless {
+		return errors.New(PkgValidateErrImageSignatureKeyless)
+	}
+	if signature.Key == "" && (signature.Identity == "" || signature.Issuer == "") {
+		return errors.New(PkgValidateErrImageSignatureKeyless)
+	}
+	var err error
+	if _, reErr := regexp.Compile(signature.Identity); reErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrImageSignatureRegexp, "identity", signature.Identity, reErr))
+	}
+	if _, reErr := regexp.Compile(signature.Issuer); reErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrImageSignatureRegexp, "issuer", signature.Issuer, reErr))
+	}
+	return err
+}
+
+// systemdUnitTypes are the extensions of the systemd unit files that can be installed from a file.
+var systemdUnitTypes = []string{".service", ".socket", ".timer", ".mount", ".automount", ".path", ".target", ".slice"}
+
+// validateFile runs all validation checks on a file.
+func validateFile(file v1alpha1.ZarfFile) error {
+	var err error
+	if file.Mode != "" {
+		if mode, parseErr := strconv.ParseUint(file.Mode, 8, 32); parseErr != nil || mode > 0o7777 {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrFileMode, file.Target, file.Mode))
+		}
+	}
+	if file.UID != nil && *file.UID < 0 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrFileOwner, file.Target, "uid"))
+	}
+	if file.GID != nil && *file.GID < 0 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrFileOwner, file.Target, "gid"))
+	}
+	if file.Systemd.IsUnit() && !slices.Contains(systemdUnitTypes, path.Ext(file.Target)) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrFileSystemdUnit, file.Target, strings.Join(systemdUnitTypes, ", ")))
+	}
+	return err
+}
+
 // validateManifest runs all validation checks on a manifest.
 func validateManifest(manifest v1alpha1.ZarfManifest) error {
 	var err error
@@ -5,6 +5,7 @@
 package lint
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestZarfPackageValidate(t *testing.T) {
@@ -56,6 +58,10 @@ func TestZarfPackageValidate(t *testing.T) {
 							{Name: "manifest1", Files: []string{"file1"}},
 							{Name: "manifest1", Files: []string{"file2"}},
 						},
+						Operators: []v1alpha1.ZarfOperator{
+							{Name: "operator1", Namespace: "whatever", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+							{Name: "operator1", Namespace: "whatever", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+						},
 					},
 					{
 						Name:            "required-in-group",
@@ -78,6 +84,40 @@ func TestZarfPackageValidate(t *testing.T) {
 					{
 						Name: "duplicate",
 					},
+					{
+						Name:   "unmatched-signature",
+						Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0"},
+						ImageSignatures: []v1alpha1.ZarfImageSignature{
+							{Images: []string{"ghcr.io/stefanprodan/podinfo:*"}, Key: "cosign.pub"},
+							{Images: []string{"docker.io/library/nginx:*"}, Key: "cosign.pub"},
+						},
+					},
+					{
+						Name:         "unmatched-squash",
+						Images:       []string{"ghcr.io/stefanprodan/podinfo:6.4.0"},
+						SquashImages: []string{"ghcr.io/stefanprodan/podinfo:*", "docker.io/library/nginx:*"},
+					},
+					{
+						Name: "invalid-health-check",
+						HealthChecks: []v1alpha1.ZarfComponentHealthCheck{
+							{Kind: "Deployment", Name: "podinfo"},
+							{Kind: "Deployment"},
+						},
+					},
+					{
+						Name: "invalid-plugin",
+						Plugins: []v1alpha1.ZarfComponentPlugin{
+							{Name: "database-dump"},
+							{Name: "../vm-image"},
+						},
+					},
+					{
+						Name: "invalid-artifacts",
+						Artifacts: []v1alpha1.ZarfArtifacts{
+							{Type: "pypi", Packages: []string{"requests==2.32.3"}},
+							{Type: "npm", Packages: []string{"lodash@^4.17.0"}},
+						},
+					},
 				},
 				Constants: []v1alpha1.Constant{
 					{
@@ -92,10 +132,16 @@ func TestZarfPackageValidate(t *testing.T) {
 				fmt.Sprintf(PkgValidateErrComponentReqDefault, "invalid"),
 				fmt.Sprintf(PkgValidateErrChartNameNotUnique, "chart1"),
 				fmt.Sprintf(PkgValidateErrManifestNameNotUnique, "manifest1"),
+				fmt.Sprintf(PkgValidateErrOperatorNameNotUnique, "operator1"),
 				fmt.Sprintf(PkgValidateErrComponentReqGrouped, "required-in-group"),
 				fmt.Sprintf(PkgValidateErrComponentNameNotUnique, "duplicate"),
 				fmt.Sprintf(PkgValidateErrGroupOneComponent, "a-group", "required-in-group"),
 				fmt.Sprintf(PkgValidateErrGroupMultipleDefaults, "multi-default", "multi-default", "multi-default-2"),
+				fmt.Sprintf(PkgValidateErrImageSignatureNoImages, []string{"docker.io/library/nginx:*"}, "unmatched-signature"),
+				fmt.Sprintf(PkgValidateErrSquashImagesNoImages, "docker.io/library/nginx:*", "unmatched-squash"),
+				fmt.Sprintf(PkgValidateErrHealthCheck, "invalid-health-check"),
+				fmt.Sprintf(PkgValidateErrPluginName, "../vm-image", "invalid-plugin"),
+				fmt.Errorf(PkgValidateErrArtifacts, "invalid-artifacts", errors.New(`npm package "lodash@^4.17.0" must be pinned to an exact version`)).Error(),
 			},
 		},
 		{
@@ -103,14 +149,18 @@ func TestZarfPackageValidate(t *testing.T) {
 			pkg: v1alpha1.ZarfPackage{
 				Kind: v1alpha1.ZarfInitConfig,
 				Metadata: v1alpha1.ZarfMetadata{
-					Name: "invalid-yolo",
-					YOLO: true,
+					Name:         "invalid-yolo",
+					YOLO:         true,
+					RequireAgent: true,
 				},
 				Components: []v1alpha1.ZarfComponent{
 					{
 						Name:   "yolo",
 						Images: []string{"an-image"},
 						Repos:  []string{"a-repo"},
+						Artifacts: []v1alpha1.ZarfArtifacts{
+							{Type: "pypi", Packages: []string{"requests==2.32.3"}},
+						},
 						Only: v1alpha1.ZarfComponentOnlyTarget{
 							Cluster: v1alpha1.ZarfComponentOnlyCluster{
 								Architecture: "not-empty",
@@ -122,8 +172,10 @@ func TestZarfPackageValidate(t *testing.T) {
 			},
 			expectedErrs: []string{
 				PkgValidateErrInitNoYOLO,
+				PkgValidateErrYOLONoAgent,
 				PkgValidateErrYOLONoOCI,
 				PkgValidateErrYOLONoGit,
+				PkgValidateErrYOLONoArtifacts,
 				PkgValidateErrYOLONoArch,
 				PkgValidateErrYOLONoDistro,
 			},
@@ -184,6 +236,167 @@ func TestValidateManifest(t *testing.T) {
 	}
 }
 
+func TestValidateOperator(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		operator     v1alpha1.ZarfOperator
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid",
+			operator:     v1alpha1.ZarfOperator{Name: "etcd", Namespace: "operators", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+			expectedErrs: nil,
+		},
+		{
+			name:     "invalid name",
+			operator: v1alpha1.ZarfOperator{Name: "Etcd", Namespace: "operators", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrOperatorName, "Etcd", strings.Join(validation.IsDNS1123Label("Etcd"), "; ")),
+			},
+		},
+		{
+			name:     "no namespace or catalog image",
+			operator: v1alpha1.ZarfOperator{Name: "etcd"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrOperatorNamespace, "etcd"),
+				fmt.Sprintf(PkgValidateErrOperatorCatalogImage, "etcd"),
+			},
+		},
+		{
+			name:     "invalid catalog image",
+			operator: v1alpha1.ZarfOperator{Name: "etcd", Namespace: "operators", CatalogImage: "quay.io/operatorhubio/catalog:-bad"},
+			expectedErrs: []string{
+				"operator \"etcd\" catalogImage \"quay.io/operatorhubio/catalog:-bad\" is not a valid image reference: invalid reference format",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateOperator(tt.operator)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateImageSignature(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		signature    v1alpha1.ZarfImageSignature
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "key",
+			signature:    v1alpha1.ZarfImageSignature{Key: "cosign.pub"},
+			expectedErrs: nil,
+		},
+		{
+			name:         "keyless",
+			signature:    v1alpha1.ZarfImageSignature{Identity: "^https://github.com/zarf-dev/.*$", Issuer: "^https://token.actions.githubusercontent.com$"},
+			expectedErrs: nil,
+		},
+		{
+			name:         "key and keyless",
+			signature:    v1alpha1.ZarfImageSignature{Key: "cosign.pub", Identity: "identity", Issuer: "issuer"},
+			expectedErrs: []string{PkgValidateErrImageSignatureKeyless},
+		},
+		{
+			name:         "keyless without issuer",
+			signature:    v1alpha1.ZarfImageSignature{Identity: "identity"},
+			expectedErrs: []string{PkgValidateErrImageSignatureKeyless},
+		},
+		{
+			name:         "nothing",
+			signature:    v1alpha1.ZarfImageSignature{},
+			expectedErrs: []string{PkgValidateErrImageSignatureKeyless},
+		},
+		{
+			name:         "invalid regular expression",
+			signature:    v1alpha1.ZarfImageSignature{Identity: "(", Issuer: "issuer"},
+			expectedErrs: []string{"image signature identity \"(\" is not a valid regular expression: error parsing regexp: missing closing ): `(`"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateImageSignature(tt.signature)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	t.Parallel()
+	root := 0
+	negative := -1
+	tests := []struct {
+		file         v1alpha1.ZarfFile
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "mode and owner",
+			file:         v1alpha1.ZarfFile{Target: "/etc/app.conf", Mode: "0640", UID: &root, GID: &root},
+			expectedErrs: nil,
+		},
+		{
+			name:         "systemd unit",
+			file:         v1alpha1.ZarfFile{Target: "/etc/systemd/system/app.service", Systemd: v1alpha1.ZarfFileSystemd{Enable: true, Start: true}},
+			expectedErrs: nil,
+		},
+		{
+			name:         "invalid mode",
+			file:         v1alpha1.ZarfFile{Target: "/etc/app.conf", Mode: "rw-r--r--"},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrFileMode, "/etc/app.conf", "rw-r--r--")},
+		},
+		{
+			name:         "mode out of range",
+			file:         v1alpha1.ZarfFile{Target: "/etc/app.conf", Mode: "17777"},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrFileMode, "/etc/app.conf", "17777")},
+		},
+		{
+			name: "negative owner",
+			file: v1alpha1.ZarfFile{Target: "/etc/app.conf", UID: &negative, GID: &negative},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrFileOwner, "/etc/app.conf", "uid"),
+				fmt.Sprintf(PkgValidateErrFileOwner, "/etc/app.conf", "gid"),
+			},
+		},
+		{
+			name:         "systemd unit without a unit type",
+			file:         v1alpha1.ZarfFile{Target: "/usr/local/bin/app", Systemd: v1alpha1.ZarfFileSystemd{Install: true}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrFileSystemdUnit, "/usr/local/bin/app", strings.Join(systemdUnitTypes, ", "))},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateFile(tt.file)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
 func TestValidateReleaseName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -453,6 +666,17 @@ func TestValidateComponentAction(t *testing.T) {
 			},
 			expectedErrs: []string{PkgValidateErrActionClusterNetwork},
 		},
+		{
+			name: "expression with single quotes",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Cluster: &v1alpha1.ZarfComponentActionWaitCluster{
+					Kind:       "Pod",
+					Name:       "podinfo",
+					Expression: "status.phase == 'Running'",
+				}},
+			},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrActionWaitExpression, "status.phase == 'Running'")},
+		},
 	}
 
 	for _, tt := range tests {
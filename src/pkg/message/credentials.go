@@ -5,10 +5,16 @@
 package message
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/pterm/pterm"
+	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -22,6 +28,13 @@ const (
 	AgentKey        = "agent"
 )
 
+// Supported formats for exporting credentials
+const (
+	CredentialFormatDockerConfig = "dockerconfig"
+	CredentialFormatNetrc        = "netrc"
+	CredentialFormatEnv          = "env"
+)
+
 // PrintCredentialTable displays credentials in a table
 func PrintCredentialTable(state *types.ZarfState, componentsToDeploy []types.DeployedComponent) {
 	if len(componentsToDeploy) == 0 {
@@ -59,6 +72,18 @@ func PrintCredentialTable(state *types.ZarfState, componentsToDeploy []types.Dep
 	}
 }
 
+// PrintEphemeralCredential displays credentials issued for the lifetime of a `zarf connect` tunnel.
+func PrintEphemeralCredential(application, username, password, validity string) {
+	// Pause the logfile's output to avoid credentials being printed to the log file
+	if logFile != nil {
+		logFile.Pause()
+		defer logFile.Resume()
+	}
+
+	header := []string{"Application", "Username", "Password", "Valid"}
+	Table(header, [][]string{{application, username, password, validity}})
+}
+
 // PrintComponentCredential displays credentials for a single component
 func PrintComponentCredential(state *types.ZarfState, componentName string) {
 	switch strings.ToLower(componentName) {
@@ -71,6 +96,9 @@ func PrintComponentCredential(state *types.ZarfState, componentName string) {
 	case ArtifactKey:
 		Notef("Artifact Server token (username: %s):", state.ArtifactServer.PushUsername)
 		fmt.Println(state.ArtifactServer.PushToken)
+		if state.ArtifactServer.PushTokenExpiresAt != nil {
+			Notef("The token expires at %s", state.ArtifactServer.PushTokenExpiresAt.Format(time.RFC3339))
+		}
 	case RegistryKey:
 		Notef("Image Registry password (username: %s):", state.RegistryInfo.PushUsername)
 		fmt.Println(state.RegistryInfo.PushPassword)
@@ -122,6 +150,7 @@ func PrintCredentialUpdates(oldState *types.ZarfState, newState *types.ZarfState
 			pterm.Printfln("    %s: %s", pterm.Bold.Sprint("URL Address"), compareStrings(oA.Address, nA.Address, false))
 			pterm.Printfln("    %s: %s", pterm.Bold.Sprint("Push Username"), compareStrings(oA.PushUsername, nA.PushUsername, false))
 			pterm.Printfln("    %s: %s", pterm.Bold.Sprint("Push Token"), compareStrings(oA.PushToken, nA.PushToken, true))
+			pterm.Printfln("    %s: %s", pterm.Bold.Sprint("Push Token Lifetime"), compareStrings(oA.PushTokenLifetime, nA.PushTokenLifetime, false))
 		case AgentKey:
 			oT := oldState.AgentTLS
 			nT := newState.AgentTLS
@@ -136,6 +165,135 @@ func PrintCredentialUpdates(oldState *types.ZarfState, newState *types.ZarfState
 	pterm.Println()
 }
 
+// sanitizedStateFields are the paths of the fields of the Zarf state that hold credentials
+var sanitizedStateFields = [][]string{
+	{"agentTLS", "ca"},
+	{"agentTLS", "cert"},
+	{"agentTLS", "key"},
+	{"gitServer", "pushPassword"},
+	{"gitServer", "pullPassword"},
+	{"registryInfo", "pushPassword"},
+	{"registryInfo", "pullPassword"},
+	{"registryInfo", "secret"},
+	{"registryInfo", "s3", "accessKey"},
+	{"registryInfo", "s3", "secretKey"},
+	{"artifactServer", "pushPassword"},
+}
+
+// stateSection returns the object at path in a Zarf state converted to a map, or nil if there is none.
+func stateSection(state map[string]any, path []string) map[string]any {
+	section := state
+	for _, key := range path {
+		section, _ = section[key].(map[string]any)
+		if section == nil {
+			return nil
+		}
+	}
+	return section
+}
+
+// CredentialStateDiff renders a line based diff between two Zarf states with every credential sanitized.
+//
+// Changed credentials are marked so that the diff shows which of them would be replaced without revealing them.
+func CredentialStateDiff(oldState *types.ZarfState, newState *types.ZarfState) (string, error) {
+	oldMap, err := stateToMap(oldState)
+	if err != nil {
+		return "", err
+	}
+	newMap, err := stateToMap(newState)
+	if err != nil {
+		return "", err
+	}
+	for _, field := range sanitizedStateFields {
+		parent, key := field[:len(field)-1], field[len(field)-1]
+		oldSection := stateSection(oldMap, parent)
+		newSection := stateSection(newMap, parent)
+		if oldSection == nil || newSection == nil {
+			continue
+		}
+		oldValue, inOld := oldSection[key]
+		newValue, inNew := newSection[key]
+		changed := fmt.Sprint(oldValue) != fmt.Sprint(newValue)
+		// Optional credentials such as the S3 keys are omitted when they are not set
+		if inOld {
+			oldSection[key] = "**sanitized**"
+		}
+		if inNew {
+			newSection[key] = "**sanitized**"
+			if changed {
+				newSection[key] = "**sanitized (changed)**"
+			}
+		}
+	}
+
+	oldCredentials := additionalPullCredentials(oldMap)
+	for i, credential := range additionalPullCredentials(newMap) {
+		changed := i >= len(oldCredentials) || fmt.Sprint(oldCredentials[i]["password"]) != fmt.Sprint(credential["password"])
+		credential["password"] = "**sanitized**"
+		if changed {
+			credential["password"] = "**sanitized (changed)**"
+		}
+	}
+	for _, credential := range oldCredentials {
+		credential["password"] = "**sanitized**"
+	}
+
+	oldJSON, err := json.MarshalIndent(oldMap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	newJSON, err := json.MarshalIndent(newMap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(string(oldJSON)+"\n", string(newJSON)+"\n")
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	var sb strings.Builder
+	for _, diff := range diffs {
+		prefix := "  "
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		}
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line != "" {
+				sb.WriteString(prefix + line)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// additionalPullCredentials returns the credentials of other registries in a Zarf state map.
+func additionalPullCredentials(m map[string]any) []map[string]any {
+	registryInfo, _ := m["registryInfo"].(map[string]any)
+	entries, _ := registryInfo["additionalPullCredentials"].([]any)
+	credentials := []map[string]any{}
+	for _, entry := range entries {
+		if credential, ok := entry.(map[string]any); ok {
+			credentials = append(credentials, credential)
+		}
+	}
+	return credentials
+}
+
+func stateToMap(state *types.ZarfState) (map[string]any, error) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func compareStrings(old string, new string, secret bool) string {
 	if new == old {
 		if secret {
@@ -148,3 +306,99 @@ func compareStrings(old string, new string, secret bool) string {
 	}
 	return fmt.Sprintf("%s -> %s", pterm.FgRed.Sprint(old), pterm.FgGreen.Sprint(new))
 }
+
+// FormatCredentials renders the credentials for the given service key as a ready to use auth file in the given format.
+//
+// An empty key selects the push credentials of the registry (dockerconfig), the git server (netrc) or every service (env).
+func FormatCredentials(state *types.ZarfState, key, format string) (string, error) {
+	key = strings.ToLower(key)
+	switch format {
+	case CredentialFormatDockerConfig:
+		username, password := state.RegistryInfo.PushUsername, state.RegistryInfo.PushPassword
+		switch key {
+		case "", RegistryKey:
+		case RegistryReadKey:
+			username, password = state.RegistryInfo.PullUsername, state.RegistryInfo.PullPassword
+		default:
+			return "", fmt.Errorf("the %s format only supports the %s and %s keys", format, RegistryKey, RegistryReadKey)
+		}
+		auth := map[string]map[string]map[string]string{
+			"auths": {
+				state.RegistryInfo.Address: {
+					"username": username,
+					"password": password,
+					"auth":     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+				},
+			},
+		}
+		b, err := json.MarshalIndent(auth, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case CredentialFormatNetrc:
+		address, username, password := state.GitServer.Address, state.GitServer.PushUsername, state.GitServer.PushPassword
+		switch key {
+		case "", GitKey:
+		case GitReadKey:
+			username, password = state.GitServer.PullUsername, state.GitServer.PullPassword
+		case ArtifactKey:
+			address, username, password = state.ArtifactServer.Address, state.ArtifactServer.PushUsername, state.ArtifactServer.PushToken
+		default:
+			return "", fmt.Errorf("the %s format only supports the %s, %s and %s keys", format, GitKey, GitReadKey, ArtifactKey)
+		}
+		u, err := url.Parse(address)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse the server address %s: %w", address, err)
+		}
+		// netrc tokens are separated by whitespace and most clients do not support quoting them
+		if strings.ContainsFunc(username+password, unicode.IsSpace) {
+			return "", fmt.Errorf("the %s format does not support whitespace in the username or password of %s", format, u.Hostname())
+		}
+		return fmt.Sprintf("machine %s login %s password %s\n", u.Hostname(), username, password), nil
+	case CredentialFormatEnv:
+		vars := [][]string{}
+		if key == "" || key == RegistryKey || key == RegistryReadKey {
+			vars = append(vars, []string{"ZARF_REGISTRY", state.RegistryInfo.Address})
+			if key != RegistryReadKey {
+				vars = append(vars,
+					[]string{"ZARF_REGISTRY_PUSH_USERNAME", state.RegistryInfo.PushUsername},
+					[]string{"ZARF_REGISTRY_PUSH_PASSWORD", state.RegistryInfo.PushPassword})
+			}
+			if key != RegistryKey {
+				vars = append(vars,
+					[]string{"ZARF_REGISTRY_PULL_USERNAME", state.RegistryInfo.PullUsername},
+					[]string{"ZARF_REGISTRY_PULL_PASSWORD", state.RegistryInfo.PullPassword})
+			}
+		}
+		if key == "" || key == GitKey || key == GitReadKey {
+			vars = append(vars, []string{"ZARF_GIT_SERVER", state.GitServer.Address})
+			if key != GitReadKey {
+				vars = append(vars,
+					[]string{"ZARF_GIT_PUSH_USERNAME", state.GitServer.PushUsername},
+					[]string{"ZARF_GIT_PUSH_PASSWORD", state.GitServer.PushPassword})
+			}
+			if key != GitKey {
+				vars = append(vars,
+					[]string{"ZARF_GIT_PULL_USERNAME", state.GitServer.PullUsername},
+					[]string{"ZARF_GIT_PULL_PASSWORD", state.GitServer.PullPassword})
+			}
+		}
+		if key == "" || key == ArtifactKey {
+			vars = append(vars,
+				[]string{"ZARF_ARTIFACT_SERVER", state.ArtifactServer.Address},
+				[]string{"ZARF_ARTIFACT_PUSH_USERNAME", state.ArtifactServer.PushUsername},
+				[]string{"ZARF_ARTIFACT_PUSH_TOKEN", state.ArtifactServer.PushToken})
+		}
+		if len(vars) == 0 {
+			return "", fmt.Errorf("unknown service key %s", key)
+		}
+		var sb strings.Builder
+		for _, v := range vars {
+			sb.WriteString(fmt.Sprintf("export %s='%s'\n", v[0], strings.ReplaceAll(v[1], "'", `'\''`)))
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported credential format %s, valid formats are: %s, %s, and %s", format, CredentialFormatDockerConfig, CredentialFormatNetrc, CredentialFormatEnv)
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestFormatCredentials(t *testing.T) {
+	t.Parallel()
+
+	state := &types.ZarfState{
+		RegistryInfo: types.RegistryInfo{
+			Address:      "127.0.0.1:31999",
+			PushUsername: "zarf-push",
+			PushPassword: "push-pass",
+			PullUsername: "zarf-pull",
+			PullPassword: "pull-pass",
+		},
+		GitServer: types.GitServerInfo{
+			Address:      "http://zarf-gitea-http.zarf.svc.cluster.local:3000",
+			PushUsername: "zarf-git-user",
+			PushPassword: "git'pass",
+			PullUsername: "zarf-git-read-user",
+			PullPassword: "git read\tpass",
+		},
+		ArtifactServer: types.ArtifactServerInfo{
+			Address:      "http://zarf-gitea-http.zarf.svc.cluster.local:3000/api/packages/zarf-git-user",
+			PushUsername: "zarf-git-user",
+			PushToken:    "token",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		key         string
+		format      string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:   "dockerconfig read-only",
+			key:    RegistryReadKey,
+			format: CredentialFormatDockerConfig,
+			expected: `{
+  "auths": {
+    "127.0.0.1:31999": {
+      "auth": "emFyZi1wdWxsOnB1bGwtcGFzcw==",
+      "password": "pull-pass",
+      "username": "zarf-pull"
+    }
+  }
+}
+`,
+		},
+		{
+			name:        "dockerconfig git",
+			key:         GitKey,
+			format:      CredentialFormatDockerConfig,
+			expectedErr: "the dockerconfig format only supports the registry and registry-readonly keys",
+		},
+		{
+			name:     "netrc",
+			format:   CredentialFormatNetrc,
+			expected: "machine zarf-gitea-http.zarf.svc.cluster.local login zarf-git-user password git'pass\n",
+		},
+		{
+			name:        "netrc whitespace",
+			key:         GitReadKey,
+			format:      CredentialFormatNetrc,
+			expectedErr: "the netrc format does not support whitespace in the username or password of zarf-gitea-http.zarf.svc.cluster.local",
+		},
+		{
+			name:     "netrc artifact",
+			key:      ArtifactKey,
+			format:   CredentialFormatNetrc,
+			expected: "machine zarf-gitea-http.zarf.svc.cluster.local login zarf-git-user password token\n",
+		},
+		{
+			name:   "env git",
+			key:    GitKey,
+			format: CredentialFormatEnv,
+			expected: `export ZARF_GIT_SERVER='http://zarf-gitea-http.zarf.svc.cluster.local:3000'
+export ZARF_GIT_PUSH_USERNAME='zarf-git-user'
+export ZARF_GIT_PUSH_PASSWORD='git'\''pass'
+`,
+		},
+		{
+			name:        "unknown format",
+			format:      "yaml",
+			expectedErr: "unsupported credential format yaml, valid formats are: dockerconfig, netrc, and env",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out, err := FormatCredentials(state, tt.key, tt.format)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestCredentialStateDiff(t *testing.T) {
+	t.Parallel()
+
+	oldState := &types.ZarfState{
+		Distro: "k3s",
+		RegistryInfo: types.RegistryInfo{
+			Address:      "127.0.0.1:31999",
+			PushUsername: "zarf-push",
+			PushPassword: "old-push-pass",
+			PullUsername: "zarf-pull",
+			PullPassword: "pull-pass",
+		},
+		AgentTLS: types.GeneratedPKI{
+			CA: []byte("old-ca"),
+		},
+	}
+	oldState.RegistryInfo.AdditionalPullCredentials = []types.RegistryCredential{
+		{Address: "mirror.example.com", Username: "mirror-user", Password: "old-mirror-pass"},
+	}
+	newState := *oldState
+	newState.RegistryInfo.PushPassword = "new-push-pass"
+	newState.RegistryInfo.PushUsername = "new-push-user"
+	newState.RegistryInfo.AdditionalPullCredentials = []types.RegistryCredential{
+		{Address: "mirror.example.com", Username: "mirror-user", Password: "new-mirror-pass"},
+		{Address: "other.example.com", Username: "other-user", Password: "other-pass"},
+	}
+
+	diff, err := CredentialStateDiff(oldState, &newState)
+	require.NoError(t, err)
+	require.NotContains(t, diff, "old-push-pass")
+	require.NotContains(t, diff, "new-push-pass")
+	require.NotContains(t, diff, "pull-pass\"")
+	require.Contains(t, diff, "-     \"pushPassword\": \"**sanitized**\",\n")
+	require.Contains(t, diff, "+     \"pushPassword\": \"**sanitized (changed)**\",\n")
+	require.Contains(t, diff, "-     \"pushUsername\": \"zarf-push\",\n")
+	require.Contains(t, diff, "+     \"pushUsername\": \"new-push-user\",\n")
+	require.Contains(t, diff, "      \"pullPassword\": \"**sanitized**\",\n")
+	require.Contains(t, diff, "    \"ca\": \"**sanitized**\",\n")
+	require.NotContains(t, diff, "mirror-pass")
+	require.NotContains(t, diff, "other-pass")
+	require.Contains(t, diff, "+         \"password\": \"**sanitized (changed)**\",\n")
+	require.Contains(t, diff, "+         \"address\": \"other.example.com\",\n")
+
+	s3State := *oldState
+	s3State.RegistryInfo.S3 = types.RegistryS3Storage{Bucket: "images", AccessKey: "old-access-key", SecretKey: "old-secret-key"}
+	rotatedS3State := s3State
+	rotatedS3State.RegistryInfo.S3.SecretKey = "new-secret-key"
+	diff, err = CredentialStateDiff(&s3State, &rotatedS3State)
+	require.NoError(t, err)
+	require.NotContains(t, diff, "access-key")
+	require.NotContains(t, diff, "secret-key")
+	require.Contains(t, diff, "      \"accessKey\": \"**sanitized**\",\n")
+	require.Contains(t, diff, "-       \"secretKey\": \"**sanitized**\"\n")
+	require.Contains(t, diff, "+       \"secretKey\": \"**sanitized (changed)**\"\n")
+
+	diff, err = CredentialStateDiff(oldState, oldState)
+	require.NoError(t, err)
+	require.NotContains(t, diff, "\n+ ")
+	require.NotContains(t, diff, "\n- ")
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package message provides a rich set of functions for displaying messages to the user.
+package message
+
+import (
+	"time"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var deployPhaseHeaders = map[types.DeployPhase]string{
+	types.DeployPhaseActions:        "Actions",
+	types.DeployPhaseFiles:          "Files",
+	types.DeployPhaseImages:         "Images",
+	types.DeployPhaseRepos:          "Repos",
+	types.DeployPhaseArtifacts:      "Artifacts",
+	types.DeployPhaseOperators:      "Operators",
+	types.DeployPhaseCharts:         "Charts",
+	types.DeployPhaseDataInjections: "Data Injections",
+	types.DeployPhasePlugins:        "Plugins",
+	types.DeployPhaseHealthChecks:   "Health Checks",
+	types.DeployPhaseWebhooks:       "Webhooks",
+	types.DeployPhaseTotal:          "Total",
+}
+
+// PrintDeployDurationTable prints a table of how long each phase of each deployed component took.
+func PrintDeployDurationTable(deployedComponents []types.DeployedComponent) {
+	header, data := deployDurationTable(deployedComponents)
+	if len(data) > 0 {
+		Table(header, data)
+	}
+}
+
+// deployDurationTable builds the rows of the deploy duration table, omitting phases that no component ran.
+func deployDurationTable(deployedComponents []types.DeployedComponent) ([]string, [][]string) {
+	phases := []types.DeployPhase{}
+	for _, phase := range types.DeployPhases {
+		for _, component := range deployedComponents {
+			if _, ok := component.PhaseDurations[phase]; ok {
+				phases = append(phases, phase)
+				break
+			}
+		}
+	}
+	if len(phases) == 0 {
+		return nil, nil
+	}
+
+	header := []string{"Component"}
+	for _, phase := range phases {
+		header = append(header, deployPhaseHeaders[phase])
+	}
+
+	data := [][]string{}
+	for _, component := range deployedComponents {
+		row := []string{component.Name}
+		for _, phase := range phases {
+			if _, ok := component.PhaseDurations[phase]; !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, component.PhaseDurations.Get(phase).Round(time.Millisecond).String())
+		}
+		data = append(data, row)
+	}
+	return header, data
+}
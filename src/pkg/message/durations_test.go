@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestDeployDurationTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		deployedComponents []types.DeployedComponent
+		expectedHeader     []string
+		expectedData       [][]string
+	}{
+		{
+			name: "no durations",
+			deployedComponents: []types.DeployedComponent{
+				{Name: "first"},
+			},
+		},
+		{
+			name: "phases that did not run are omitted",
+			deployedComponents: []types.DeployedComponent{
+				{
+					Name: "first",
+					PhaseDurations: types.PhaseDurations{
+						types.DeployPhaseImages: 1500,
+						types.DeployPhaseTotal:  2000,
+					},
+				},
+				{
+					Name: "second",
+					PhaseDurations: types.PhaseDurations{
+						types.DeployPhaseCharts:   61000,
+						types.DeployPhaseWebhooks: 0,
+						types.DeployPhaseTotal:    62000,
+					},
+				},
+			},
+			expectedHeader: []string{"Component", "Images", "Charts", "Webhooks", "Total"},
+			expectedData: [][]string{
+				{"first", "1.5s", "-", "-", "2s"},
+				{"second", "-", "1m1s", "0s", "1m2s"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			header, data := deployDurationTable(tt.deployedComponents)
+			require.Equal(t, tt.expectedHeader, header)
+			require.Equal(t, tt.expectedData, data)
+		})
+	}
+}
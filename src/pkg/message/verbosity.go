@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package message provides a rich set of functions for displaying messages to the user.
+package message
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The levels of verbosity set with -v, -vv and -vvv, each level includes the detail of the levels before it.
+const (
+	// VerbosityComponent shows the debug messages of the components being created or deployed.
+	VerbosityComponent = 1
+	// VerbosityChart also shows the debug log of Helm and the manifests rendered from charts.
+	VerbosityChart = 2
+	// VerbosityHTTP also logs the HTTP requests to registries and git servers, with their secrets redacted.
+	VerbosityHTTP = 3
+)
+
+// verbosity holds the verbosity set with -v
+var verbosity int
+
+// redacted replaces the values of secret headers and query parameters in wire logs.
+const redacted = "[REDACTED]"
+
+// secretHeaders are the headers whose values are never logged.
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Registry-Auth"}
+
+// secretQueryParams are the query parameters whose values are never logged, matched case-insensitively.
+var secretQueryParams = []string{"access_token", "password", "token", "x-amz-credential", "x-amz-security-token", "x-amz-signature", "sig", "signature"}
+
+// SetVerbosity sets the verbosity. Any verbosity shows debug messages, the highest also shows trace messages, unless
+// the log level already shows them.
+func SetVerbosity(level int) {
+	verbosity = level
+	switch {
+	case level >= VerbosityHTTP && logLevel < TraceLevel:
+		SetLogLevel(TraceLevel)
+	case level > 0 && logLevel < DebugLevel:
+		SetLogLevel(DebugLevel)
+	}
+}
+
+// IsVerbose returns whether the verbosity is at least the given level.
+func IsVerbose(level int) bool {
+	return verbosity >= level
+}
+
+// wireTransport logs the requests it sends and the responses it receives when the verbosity is VerbosityHTTP.
+type wireTransport struct {
+	base http.RoundTripper
+}
+
+// WireTransport wraps base, or http.DefaultTransport when it is nil, so the requests sent through it are logged at
+// the VerbosityHTTP verbosity. Credentials in the headers, query and user info of requests are redacted, and bodies
+// are never logged.
+func WireTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &wireTransport{base: base}
+}
+
+// WireClient returns a copy of the client that logs its requests like WireTransport.
+func WireClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := *client
+	c.Transport = WireTransport(client.Transport)
+	return &c
+}
+
+func (t *wireTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !IsVerbose(VerbosityHTTP) {
+		return t.base.RoundTrip(req)
+	}
+	start := time.Now()
+	Debugf("HTTP request %s %s\n%s", req.Method, RedactURL(req.URL), redactHeaders(req.Header))
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		Debugf("HTTP request %s %s failed after %s: %s", req.Method, RedactURL(req.URL), time.Since(start).Round(time.Millisecond), err.Error())
+		return nil, err
+	}
+	Debugf("HTTP response %s %s: %s in %s\n%s", req.Method, RedactURL(req.URL), resp.Status, time.Since(start).Round(time.Millisecond), redactHeaders(resp.Header))
+	return resp, nil
+}
+
+// RedactURL returns the URL with the password of its user info and the values of secret query parameters redacted.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redactedURL := *u
+	if redactedURL.RawQuery != "" {
+		query := redactedURL.Query()
+		for key := range query {
+			if isSecretQueryParam(key) {
+				query[key] = []string{redacted}
+			}
+		}
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.Redacted()
+}
+
+func isSecretQueryParam(key string) bool {
+	for _, param := range secretQueryParams {
+		if strings.EqualFold(key, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders formats the headers one per line, sorted by name, with the values of secret headers redacted.
+func redactHeaders(header http.Header) string {
+	names := []string{}
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{}
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		for _, secret := range secretHeaders {
+			if strings.EqualFold(name, secret) {
+				value = redacted
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", name, value))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -181,9 +181,15 @@ func convertWaitToCmd(_ context.Context, wait v1alpha1.ZarfComponentActionWait,
 			ns = fmt.Sprintf("-n %s", ns)
 		}
 
+		// Single quotes keep the expression a single argument in both sh and PowerShell.
+		expression := ""
+		if cluster.Expression != "" {
+			expression = fmt.Sprintf("--expression '%s'", cluster.Expression)
+		}
+
 		// Build a call to the zarf tools wait-for command.
-		return fmt.Sprintf("./zarf tools wait-for %s %s %s %s %s",
-			cluster.Kind, cluster.Name, cluster.Condition, ns, timeoutString), nil
+		return fmt.Sprintf("./zarf tools wait-for %s %s %s %s %s %s",
+			cluster.Kind, cluster.Name, cluster.Condition, ns, expression, timeoutString), nil
 	}
 
 	network := wait.Network
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// LoadDeployAnswers reads an answers file exported by a previous deployment.
+func LoadDeployAnswers(path string) (types.DeployAnswers, error) {
+	var answers types.DeployAnswers
+	if err := utils.ReadYaml(path, &answers); err != nil {
+		return types.DeployAnswers{}, fmt.Errorf(lang.PkgDeployErrAnswers, path, err)
+	}
+	return answers, nil
+}
+
+// applyAnswers takes the components and variable values that were not given for this deployment from answers.
+func (p *Packager) applyAnswers(answers types.DeployAnswers) {
+	if p.cfg.PkgOpts.OptionalComponents == "" {
+		p.cfg.PkgOpts.OptionalComponents = strings.Join(answers.Components, ",")
+	}
+	if p.cfg.PkgOpts.SetVariables == nil {
+		p.cfg.PkgOpts.SetVariables = map[string]string{}
+	}
+	for name, value := range answers.Variables {
+		if _, ok := p.cfg.PkgOpts.SetVariables[name]; !ok {
+			p.cfg.PkgOpts.SetVariables[name] = value
+		}
+	}
+}
+
+// exportAnswers writes the components and variable values of this deployment to the export answers file.
+func (p *Packager) exportAnswers() error {
+	path := p.cfg.DeployOpts.ExportAnswersPath
+	answers := types.DeployAnswers{
+		Package:   p.cfg.Pkg.Metadata.Name,
+		Version:   p.cfg.Pkg.Metadata.Version,
+		Variables: p.variableConfig.GetSetVariableValues(),
+	}
+	for _, component := range p.cfg.Pkg.Components {
+		answers.Components = append(answers.Components, component.Name)
+	}
+	if err := utils.WriteYaml(path, answers, helpers.ReadWriteUser); err != nil {
+		return err
+	}
+
+	for name := range answers.Variables {
+		if variable, ok := p.variableConfig.GetSetVariable(name); ok && variable.Sensitive {
+			message.Warnf(lang.PkgDeployWarnAnswersSensitive, path)
+			break
+		}
+	}
+	message.Successf(lang.PkgDeploySuccessAnswersExported, path)
+	return nil
+}
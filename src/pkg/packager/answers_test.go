@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestDeployAnswers(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+
+	// Export the answers of a deployment
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("DOMAIN", "uds.dev", false, false, v1alpha1.RawVariableType)
+	vc.SetVariable("PASSWORD", "hunter2", true, false, v1alpha1.RawVariableType)
+	vc.SetVariable("DERIVED", "from-an-action", false, false, v1alpha1.RawVariableType)
+	exporter := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{
+				Metadata:   v1alpha1.ZarfMetadata{Name: "app", Version: "1.0.0"},
+				Components: []v1alpha1.ZarfComponent{{Name: "required"}, {Name: "optional"}},
+			},
+			DeployOpts: types.ZarfDeployOptions{ExportAnswersPath: path},
+		},
+		variableConfig: vc,
+	}
+	require.NoError(t, exporter.exportAnswers())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	answers, err := LoadDeployAnswers(path)
+	require.NoError(t, err)
+	expected := types.DeployAnswers{
+		Package:    "app",
+		Version:    "1.0.0",
+		Components: []string{"required", "optional"},
+		Variables: map[string]string{
+			"DOMAIN":   "uds.dev",
+			"PASSWORD": "hunter2",
+			"DERIVED":  "from-an-action",
+		},
+	}
+	require.Equal(t, expected, answers)
+
+	// Values given for the deployment take precedence over the answers
+	replayer := &Packager{
+		cfg: &types.PackagerConfig{
+			PkgOpts: types.ZarfPackageOptions{
+				SetVariables: map[string]string{"DOMAIN": "example.com"},
+			},
+		},
+	}
+	replayer.applyAnswers(answers)
+	require.Equal(t, "required,optional", replayer.cfg.PkgOpts.OptionalComponents)
+	require.Equal(t, map[string]string{
+		"DOMAIN":   "example.com",
+		"PASSWORD": "hunter2",
+		"DERIVED":  "from-an-action",
+	}, replayer.cfg.PkgOpts.SetVariables)
+
+	replayer = &Packager{
+		cfg: &types.PackagerConfig{
+			PkgOpts: types.ZarfPackageOptions{OptionalComponents: "optional"},
+		},
+	}
+	replayer.applyAnswers(answers)
+	require.Equal(t, "optional", replayer.cfg.PkgOpts.OptionalComponents)
+	require.Equal(t, expected.Variables, replayer.cfg.PkgOpts.SetVariables)
+
+	_, err = LoadDeployAnswers(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "unable to read the answers file")
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+
+	"github.com/zarf-dev/zarf/src/internal/packager/artifacts"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// pushArtifactsToServer publishes the language ecosystem packages of a component to the artifact server, through a
+// tunnel when the artifact server runs in the cluster.
+func (p *Packager) pushArtifactsToServer(ctx context.Context, artifactsPath string) error {
+	index, err := artifacts.ReadIndex(artifactsPath)
+	if err != nil {
+		return err
+	}
+	spinner := message.NewProgressSpinner("Publishing %d artifacts to the artifact server", len(index.Artifacts))
+	defer spinner.Stop()
+
+	return retry.Do(func() error {
+		publisher := artifacts.Publisher{
+			Address:  p.state.ArtifactServer.Address,
+			Username: p.state.ArtifactServer.PushUsername,
+			Password: p.state.ArtifactServer.PushToken,
+			Client:   message.WireClient(nil),
+		}
+		publish := func() error {
+			published, err := publisher.Publish(ctx, artifactsPath, index.Artifacts)
+			if err != nil {
+				return err
+			}
+			spinner.Successf("Published %d artifacts to the artifact server, %d were already published", published, len(index.Artifacts)-published)
+			return nil
+		}
+
+		namespace, name, port, err := serviceInfoFromServiceURL(p.state.ArtifactServer.Address)
+		// If the artifact server is a service in the cluster, publish through a port-forward tunnel to it
+		if err != nil {
+			return publish()
+		}
+		if !p.isConnectedToCluster() {
+			connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			if err := p.connectToCluster(connectCtx); err != nil {
+				return err
+			}
+		}
+		tunnel, err := p.cluster.NewTunnel(namespace, cluster.SvcResource, name, "", 0, port)
+		if err != nil {
+			return err
+		}
+		if _, err := tunnel.Connect(ctx); err != nil {
+			return err
+		}
+		defer tunnel.Close()
+		address, err := url.Parse(p.state.ArtifactServer.Address)
+		if err != nil {
+			return err
+		}
+		publisher.Address = tunnel.HTTPEndpoint() + address.Path
+		return tunnel.Wrap(publish)
+	}, retry.Context(ctx), retry.Attempts(uint(p.cfg.PkgOpts.Retries)), retry.Delay(500*time.Millisecond))
+}
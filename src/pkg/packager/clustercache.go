@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+// retainInClusterCache stores the deployed package in the in-cluster package cache if it has been deployed,
+// failing to do so does not fail the deployment.
+func (p *Packager) retainInClusterCache(ctx context.Context) {
+	if !p.isConnectedToCluster() || p.cfg.Pkg.IsInitConfig() {
+		return
+	}
+	if _, ok := p.source.(*sources.ClusterCacheSource); ok {
+		return
+	}
+	ok, err := p.cluster.HasPackageCache(ctx)
+	if err != nil {
+		message.Debugf("Unable to check for the package cache: %s", err.Error())
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := p.pushToClusterCache(ctx); err != nil {
+		message.Warnf("Unable to retain %s in the package cache: %s", p.cfg.Pkg.Metadata.Name, err.Error())
+	}
+}
+
+// pushToClusterCache pushes the complete package from its source to the in-cluster package cache.
+func (p *Packager) pushToClusterCache(ctx context.Context) error {
+	if p.cfg.Pkg.Metadata.Version == "" {
+		return fmt.Errorf("packages without a version can not be retained")
+	}
+
+	state, err := p.cluster.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	tunnel, err := p.cluster.ConnectToPackageCache(ctx)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+
+	ref, err := sources.ClusterCacheReference(tunnel.Endpoint(), &p.cfg.Pkg.Metadata, &p.cfg.Pkg.Build)
+	if err != nil {
+		return err
+	}
+	dst, err := zoci.NewRemote(ref, oci.PlatformForArch(p.cfg.Pkg.Build.Architecture), oci.WithPlainHTTP(true))
+	if err != nil {
+		return err
+	}
+	dst.SetBasicAuth(state.RegistryInfo.PushUsername, state.RegistryInfo.PushPassword)
+
+	switch src := p.source.(type) {
+	case *sources.OCISource:
+		return zoci.CopyPackage(ctx, src.Remote, dst, config.CommonOptions.OCIConcurrency)
+	case *sources.TarballSource:
+		// The deployed layout only holds the selected components and has been unarchived, so the package is loaded again in full
+		tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		pkgOpts := *src.ZarfPackageOptions
+		tarball := &sources.TarballSource{ZarfPackageOptions: &pkgOpts}
+		paths := layout.New(tmpDir)
+		pkg, _, err := tarball.LoadPackage(ctx, paths, filters.Empty(), false)
+		if err != nil {
+			return err
+		}
+		return dst.PublishPackage(ctx, &pkg, paths, config.CommonOptions.OCIConcurrency)
+	default:
+		return fmt.Errorf("packages deployed from a %T can not be retained", p.source)
+	}
+}
@@ -36,8 +36,14 @@ type Packager struct {
 	cluster        *cluster.Cluster
 	layout         *layout.PackagePaths
 	hpaModified    bool
-	connectStrings types.ConnectStrings
-	source         sources.PackageSource
+	agentChecked   bool
+	// Whether the ownership of the Helm releases and namespaces of the package has been checked
+	ownershipChecked bool
+	connectStrings   types.ConnectStrings
+	phaseDurations   types.PhaseDurations
+	skippedPhases    []types.DeployPhase
+	clusterFacts     *types.ClusterFacts
+	source           sources.PackageSource
 }
 
 // Modifier is a function that modifies the packager.
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// gitImportSchemes are the URL schemes of git repositories that components can be imported from.
+var gitImportSchemes = []string{"https://", "http://", "ssh://", "file://"}
+
+var (
+	// gitImportsMu guards gitImports
+	gitImportsMu sync.Mutex
+	// gitImports maps the URLs of the git repositories imported from to where they were cloned, so each is only cloned once
+	gitImports = map[string]string{}
+)
+
+// isGitImport returns whether an import URL references a git repository instead of an OCI skeleton package.
+func isGitImport(url string) bool {
+	for _, scheme := range gitImportSchemes {
+		if strings.HasPrefix(url, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneGitImport shallow clones the git repository of an import URL into the cache, returning its path relative to
+// the current working directory.
+func cloneGitImport(ctx context.Context, url string, credentials []types.GitCredential) (string, error) {
+	gitImportsMu.Lock()
+	defer gitImportsMu.Unlock()
+
+	repoPath, ok := gitImports[url]
+	if !ok {
+		auth, err := git.AuthForURL(credentials, url)
+		if err != nil {
+			return "", err
+		}
+		cache := filepath.Join(config.GetAbsCachePath(), "git-imports")
+		if err := helpers.CreateDirectory(cache, helpers.ReadWriteExecuteUser); err != nil {
+			return "", err
+		}
+		// Remove the clone left over from a previous run so the ref is fetched again
+		repoFolder, err := transform.GitURLtoFolderName(url)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse git url %s: %w", url, err)
+		}
+		if err := os.RemoveAll(filepath.Join(cache, repoFolder)); err != nil {
+			return "", err
+		}
+
+		message.Debugf("Cloning %s to import components from", url)
+		repo, err := git.CloneWithAuth(ctx, cache, url, true, auth)
+		if err != nil {
+			return "", fmt.Errorf("unable to clone %s to import components from: %w", url, err)
+		}
+		repoPath = repo.Path()
+		gitImports[url] = repoPath
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(cwd, repoPath)
+}
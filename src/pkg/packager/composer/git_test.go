@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/gitkit"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestGitImport(t *testing.T) {
+	cachePath := config.CommonOptions.CachePath
+	config.CommonOptions.CachePath = t.TempDir()
+	t.Cleanup(func() {
+		config.CommonOptions.CachePath = cachePath
+	})
+
+	gitSrv := gitkit.New(gitkit.Config{
+		Dir:        t.TempDir(),
+		AutoCreate: true,
+		Auth:       true,
+	})
+	gitSrv.AuthFunc = func(cred gitkit.Credential, _ *gitkit.Request) (bool, error) {
+		return cred.Username == "zarf" && cred.Password == "token", nil
+	}
+	require.NoError(t, gitSrv.Setup())
+	srv := httptest.NewServer(http.HandlerFunc(gitSrv.ServeHTTP))
+	t.Cleanup(srv.Close)
+	repoAddress := fmt.Sprintf("%s/components.git", srv.URL)
+
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	f, err := fs.Create("lib/zarf.yaml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("kind: ZarfPackageConfig\nmetadata:\n  name: lib\ncomponents:\n  - name: app\n    files:\n      - source: app.txt\n        target: app.txt\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = w.Add("lib/zarf.yaml")
+	require.NoError(t, err)
+	_, err = w.Commit("Add the app component", &git.CommitOptions{Author: &object.Signature{Email: "example@example.com"}})
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{repoAddress}})
+	require.NoError(t, err)
+	auth := &githttp.BasicAuth{Username: "zarf", Password: "token"}
+	require.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin", Auth: auth}))
+
+	head := v1alpha1.ZarfComponent{
+		Name: "app",
+		Import: v1alpha1.ZarfComponentImport{
+			URL:  repoAddress,
+			Path: "lib",
+		},
+	}
+	credentials := []types.GitCredential{
+		{URL: "https://git.example.com/", Username: "other", Password: "other"},
+		{URL: srv.URL + "/", Username: "zarf", Password: "token"},
+	}
+	ic, err := NewImportChain(context.Background(), head, 0, "test", "amd64", "", credentials, nil)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("component \"app\" imports \"app\" in %s (lib)", repoAddress), ic.String())
+	require.Equal(t, fmt.Sprintf("%s (lib)", repoAddress), ic.Tail().ImportLocation())
+
+	composed, err := ic.Compose(context.Background())
+	require.NoError(t, err)
+	require.Len(t, composed.Files, 1)
+	require.Equal(t, "app.txt", filepath.Base(composed.Files[0].Source))
+	require.FileExists(t, filepath.Join(filepath.Dir(composed.Files[0].Source), "zarf.yaml"))
+}
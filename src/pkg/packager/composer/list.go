@@ -18,6 +18,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // Node is a node in the import chain
@@ -50,12 +51,28 @@ func (n *Node) OriginalPackageName() string {
 func (n *Node) ImportLocation() string {
 	if n.prev != nil {
 		if n.prev.ZarfComponent.Import.URL != "" {
-			return n.prev.ZarfComponent.Import.URL
+			return n.prev.importSource()
 		}
 	}
 	return n.relativeToHead
 }
 
+// importSource returns the path or URL this node imports its component from
+func (n *Node) importSource() string {
+	if n.Import.URL == "" {
+		return n.Import.Path
+	}
+	if n.Import.Path != "" {
+		return fmt.Sprintf("%s (%s)", n.Import.URL, n.Import.Path)
+	}
+	return n.Import.URL
+}
+
+// isOCIImport returns whether this node imports a component from an OCI skeleton package
+func (n *Node) isOCIImport() bool {
+	return n.Import.URL != "" && !isGitImport(n.Import.URL)
+}
+
 // Next returns next node in the chain
 func (n *Node) Next() *Node {
 	return n.next
@@ -83,6 +100,8 @@ type ImportChain struct {
 	tail *Node
 
 	remote *zoci.Remote
+	// skeletons resolves the URL of the skeleton package the chain imports from
+	skeletons *SkeletonResolver
 }
 
 // Head returns the first node in the import chain
@@ -129,13 +148,13 @@ func validateComponentCompose(c v1alpha1.ZarfComponent) error {
 		err = errors.Join(err, errors.New("neither a path nor a URL was provided"))
 	}
 
-	// ensure path and url are not both provided
-	if path != "" && url != "" {
+	// ensure path and url are not both provided, unless the path is within a git repository
+	if path != "" && url != "" && !isGitImport(url) {
 		err = errors.Join(err, errors.New("both a path and a URL were provided"))
 	}
 
 	// validation for path
-	if url == "" && path != "" {
+	if path != "" {
 		// ensure path is not an absolute path
 		if filepath.IsAbs(path) {
 			err = errors.Join(err, errors.New("path cannot be an absolute path"))
@@ -143,10 +162,10 @@ func validateComponentCompose(c v1alpha1.ZarfComponent) error {
 	}
 
 	// validation for url
-	if url != "" && path == "" {
-		ok := helpers.IsOCIURL(url)
+	if url != "" {
+		ok := helpers.IsOCIURL(url) || isGitImport(url)
 		if !ok {
-			err = errors.Join(err, errors.New("URL is not a valid OCI URL"))
+			err = errors.Join(err, errors.New("URL is not a valid OCI or git URL"))
 		}
 	}
 
@@ -155,8 +174,8 @@ func validateComponentCompose(c v1alpha1.ZarfComponent) error {
 
 // NewImportChain creates a new import chain from a component
 // Returning the chain on error so we can have additional information to use during lint
-func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int, originalPackageName, arch, flavor string) (*ImportChain, error) {
-	ic := &ImportChain{}
+func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int, originalPackageName, arch, flavor string, gitCredentials []types.GitCredential, skeletons *SkeletonResolver) (*ImportChain, error) {
+	ic := &ImportChain{skeletons: skeletons}
 	if arch == "" {
 		return ic, fmt.Errorf("cannot build import chain: architecture must be provided")
 	}
@@ -167,10 +186,11 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 
 	node := ic.head
 	for node != nil {
-		isLocal := node.Import.Path != ""
-		isRemote := node.Import.URL != ""
+		isGit := isGitImport(node.Import.URL)
+		isLocal := node.Import.Path != "" && node.Import.URL == ""
+		isRemote := node.Import.URL != "" && !isGit
 
-		if !isLocal && !isRemote {
+		if !isLocal && !isRemote && !isGit {
 			// This is the end of the import chain,
 			// as the current node/component is not importing anything
 			return ic, nil
@@ -181,19 +201,36 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 		}
 
 		// ensure that remote components are not importing other remote components
-		if node.prev != nil && node.prev.Import.URL != "" && isRemote {
+		if node.prev != nil && node.prev.isOCIImport() && isRemote {
 			return ic, fmt.Errorf("detected malformed import chain, cannot import remote components from remote components")
 		}
 		// ensure that remote components are not importing local components
-		if node.prev != nil && node.prev.Import.URL != "" && isLocal {
+		if node.prev != nil && node.prev.isOCIImport() && isLocal {
 			return ic, fmt.Errorf("detected malformed import chain, cannot import local components from remote components")
 		}
+		// ensure that remote components are not importing components from git repositories
+		if node.prev != nil && node.prev.isOCIImport() && isGit {
+			return ic, fmt.Errorf("detected malformed import chain, cannot import git components from remote components")
+		}
 
 		var pkg v1alpha1.ZarfPackage
 
 		var relativeToHead string
 		var importURL string
-		if isLocal {
+		if isGit {
+			repoPath, err := cloneGitImport(ctx, node.Import.URL, gitCredentials)
+			if err != nil {
+				return ic, err
+			}
+			// paths within the repository are relative to where it was cloned rather than the previous node
+			history = []string{repoPath, node.Import.Path}
+			relativeToHead = filepath.Join(history...)
+			importURL = node.Import.URL
+
+			if err := utils.ReadYaml(filepath.Join(relativeToHead, layout.ZarfYAML), &pkg); err != nil {
+				return ic, err
+			}
+		} else if isLocal {
 			history = append(history, node.Import.Path)
 			relativeToHead = filepath.Join(history...)
 
@@ -240,16 +277,14 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 			componentNotFound := "component %q not found in %q"
 			if isLocal {
 				return ic, fmt.Errorf(componentNotFound, name, relativeToHead)
-			} else if isRemote {
-				return ic, fmt.Errorf(componentNotFound, name, importURL)
 			}
+			return ic, fmt.Errorf(componentNotFound, name, importURL)
 		} else if len(found) > 1 {
 			multipleComponentsFound := "multiple components named %q found in %q satisfying %q"
 			if isLocal {
 				return ic, fmt.Errorf(multipleComponentsFound, name, relativeToHead, arch)
-			} else if isRemote {
-				return ic, fmt.Errorf(multipleComponentsFound, name, importURL, arch)
 			}
+			return ic, fmt.Errorf(multipleComponentsFound, name, importURL, arch)
 		}
 
 		ic.append(found[0], index[0], pkg.Metadata.Name, relativeToHead, pkg.Variables, pkg.Constants)
@@ -268,21 +303,13 @@ func (ic *ImportChain) String() string {
 
 	name := ic.head.ImportName()
 
-	if ic.head.Import.Path != "" {
-		s.WriteString(fmt.Sprintf("component %q imports %q in %s", ic.head.Name, name, ic.head.Import.Path))
-	} else {
-		s.WriteString(fmt.Sprintf("component %q imports %q in %s", ic.head.Name, name, ic.head.Import.URL))
-	}
+	s.WriteString(fmt.Sprintf("component %q imports %q in %s", ic.head.Name, name, ic.head.importSource()))
 
 	node := ic.head.next
 	for node != ic.tail {
 		name := node.ImportName()
 		s.WriteString(", which imports ")
-		if node.Import.Path != "" {
-			s.WriteString(fmt.Sprintf("%q in %s", name, node.Import.Path))
-		} else {
-			s.WriteString(fmt.Sprintf("%q in %s", name, node.Import.URL))
-		}
+		s.WriteString(fmt.Sprintf("%q in %s", name, node.importSource()))
 
 		node = node.next
 	}
@@ -49,7 +49,7 @@ func TestNewImportChain(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			_, err := NewImportChain(context.Background(), tt.head, 0, testPackageName, tt.arch, tt.flavor)
+			_, err := NewImportChain(context.Background(), tt.head, 0, testPackageName, tt.arch, tt.flavor, nil, nil)
 			require.ErrorContains(t, err, tt.expectedErr)
 		})
 	}
@@ -571,6 +571,17 @@ func TestValidateZarfComponent(t *testing.T) {
 			},
 			expectedErrs: nil,
 		},
+		{
+			name: "valid git URL with path",
+			component: v1alpha1.ZarfComponent{
+				Name: "component3",
+				Import: v1alpha1.ZarfComponentImport{
+					URL:  "https://git.example.com/my-org/components.git@v1.0.0",
+					Path: "packages/app",
+				},
+			},
+			expectedErrs: nil,
+		},
 		{
 			name: "neither path nor URL provided",
 			component: v1alpha1.ZarfComponent{
@@ -586,7 +597,7 @@ func TestValidateZarfComponent(t *testing.T) {
 				Name: "both",
 				Import: v1alpha1.ZarfComponentImport{
 					Path: "relative/path",
-					URL:  "https://example.com",
+					URL:  "oci://example.com/package:v0.0.1",
 				},
 			},
 			expectedErrs: []string{
@@ -610,11 +621,11 @@ func TestValidateZarfComponent(t *testing.T) {
 			component: v1alpha1.ZarfComponent{
 				Name: "bad-url",
 				Import: v1alpha1.ZarfComponentImport{
-					URL: "https://example.com",
+					URL: "example.com/package",
 				},
 			},
 			expectedErrs: []string{
-				"URL is not a valid OCI URL",
+				"URL is not a valid OCI or git URL",
 			},
 		},
 	}
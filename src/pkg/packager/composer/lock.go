@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+// ComposeLock pins the OCI skeleton packages components are imported from to the versions and digests their URLs
+// resolved to when the package definition was locked.
+type ComposeLock struct {
+	// The locked skeleton packages, sorted by URL
+	Skeletons []LockedSkeleton `json:"skeletons"`
+}
+
+// LockedSkeleton is the URL of a skeleton package as it is written in an import and the package it resolved to.
+type LockedSkeleton struct {
+	// The URL as it is written in the import, which may have a semver range in place of its tag
+	URL string `json:"url"`
+	// The URL pinned to the version it resolved to and the digest of that version
+	Resolved string `json:"resolved"`
+}
+
+// LoadComposeLock reads a compose lockfile, returning whether it exists.
+func LoadComposeLock(lockPath string) (ComposeLock, bool, error) {
+	var lock ComposeLock
+	b, err := os.ReadFile(lockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, false, nil
+	}
+	if err != nil {
+		return lock, false, fmt.Errorf("unable to read the compose lockfile: %w", err)
+	}
+	if err := goyaml.UnmarshalWithOptions(b, &lock, goyaml.Strict()); err != nil {
+		return lock, false, fmt.Errorf("unable to parse the compose lockfile %s: %w", lockPath, err)
+	}
+	return lock, true, nil
+}
+
+// Write writes the lock to lockPath.
+func (l ComposeLock) Write(lockPath string) error {
+	b, err := goyaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(lockPath, b, helpers.ReadWriteUser); err != nil {
+		return fmt.Errorf("unable to write the compose lockfile: %w", err)
+	}
+	return nil
+}
+
+// SkeletonResolver resolves the URLs of the OCI skeleton packages components are imported from to the exact packages
+// they are composed from, taking them from a compose lock when it is set.
+type SkeletonResolver struct {
+	lock     *ComposeLock
+	lockPath string
+
+	mu sync.Mutex
+	// resolved maps the URLs resolved so far to the packages they resolved to
+	resolved map[string]string
+}
+
+// NewSkeletonResolver returns a resolver that takes the skeleton packages from the lock read from lockPath, or
+// resolves them from their registries when lock is nil.
+func NewSkeletonResolver(lock *ComposeLock, lockPath string) *SkeletonResolver {
+	return &SkeletonResolver{lock: lock, lockPath: lockPath, resolved: map[string]string{}}
+}
+
+// LoadSkeletonResolver returns a resolver that uses the compose lockfile at lockPath when it exists.
+func LoadSkeletonResolver(lockPath string) (*SkeletonResolver, error) {
+	lock, locked, err := LoadComposeLock(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return NewSkeletonResolver(nil, lockPath), nil
+	}
+	return NewSkeletonResolver(&lock, lockPath), nil
+}
+
+// Resolve returns the URL of the skeleton package pinned to the version its semver range or tag selects and the digest
+// of that version. A nil resolver returns the URL as it is.
+func (r *SkeletonResolver) Resolve(ctx context.Context, url string) (string, error) {
+	if r == nil {
+		return url, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if resolved, ok := r.resolved[url]; ok {
+		return resolved, nil
+	}
+	if r.lock != nil {
+		idx := slices.IndexFunc(r.lock.Skeletons, func(skeleton LockedSkeleton) bool {
+			return skeleton.URL == url
+		})
+		if idx < 0 {
+			return "", fmt.Errorf(lang.PkgCreateErrComposeLockMissing, url, r.lockPath)
+		}
+		r.resolved[url] = r.lock.Skeletons[idx].Resolved
+		return r.resolved[url], nil
+	}
+
+	versioned, err := zoci.ResolveVersionRange(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	if versioned != url {
+		message.Infof(lang.PkgCreateResolvedSkeletonVersion, url, versioned)
+	}
+	remote, err := zoci.NewRemote(versioned, zoci.PlatformForSkeleton())
+	if err != nil {
+		return "", err
+	}
+	resolved, err := remote.PinnedReference(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.resolved[url] = resolved
+	return resolved, nil
+}
+
+// Lock returns the skeleton packages resolved so far as a compose lock.
+func (r *SkeletonResolver) Lock() ComposeLock {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock := ComposeLock{Skeletons: []LockedSkeleton{}}
+	for url, resolved := range r.resolved {
+		lock.Skeletons = append(lock.Skeletons, LockedSkeleton{URL: url, Resolved: resolved})
+	}
+	slices.SortFunc(lock.Skeletons, func(a, b LockedSkeleton) int {
+		return cmp.Compare(a.URL, b.URL)
+	})
+	return lock
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+func TestSkeletonResolver(t *testing.T) {
+	insecure := config.CommonOptions.Insecure
+	config.CommonOptions.Insecure = true
+	t.Cleanup(func() {
+		config.CommonOptions.Insecure = insecure
+	})
+
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+	repository := host + "/skeletons/podinfo"
+
+	remote, err := zoci.NewRemote(repository, zoci.PlatformForSkeleton())
+	require.NoError(t, err)
+	digests := map[string]string{}
+	for _, tag := range []string{"1.2.0", "1.2.4", "1.3.0"} {
+		opts := oras.PackManifestOptions{ManifestAnnotations: map[string]string{"tag": tag}}
+		desc, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, zoci.ZarfConfigMediaType, opts)
+		require.NoError(t, err)
+		require.NoError(t, remote.Repo().Tag(ctx, desc, tag))
+		digests[tag] = desc.Digest.String()
+	}
+	rangeURL := fmt.Sprintf("oci://%s:~1.2", repository)
+	tagURL := fmt.Sprintf("oci://%s:1.3.0", repository)
+
+	resolver := NewSkeletonResolver(nil, "zarf-compose-lock.yaml")
+	resolved, err := resolver.Resolve(ctx, rangeURL)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("oci://%s:1.2.4@%s", repository, digests["1.2.4"]), resolved)
+	resolved, err = resolver.Resolve(ctx, tagURL)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("oci://%s:1.3.0@%s", repository, digests["1.3.0"]), resolved)
+	_, err = resolver.Resolve(ctx, fmt.Sprintf("oci://%s:^2.0", repository))
+	require.EqualError(t, err, fmt.Sprintf("no version of %s satisfies \"^2.0\"", repository))
+
+	lock := resolver.Lock()
+	require.Equal(t, ComposeLock{Skeletons: []LockedSkeleton{
+		{URL: tagURL, Resolved: fmt.Sprintf("oci://%s:1.3.0@%s", repository, digests["1.3.0"])},
+		{URL: rangeURL, Resolved: fmt.Sprintf("oci://%s:1.2.4@%s", repository, digests["1.2.4"])},
+	}}, lock)
+	lockPath := filepath.Join(t.TempDir(), "zarf-compose-lock.yaml")
+	require.NoError(t, lock.Write(lockPath))
+
+	// A newer version that satisfies the range is not selected once the skeleton packages are locked
+	desc, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, zoci.ZarfConfigMediaType, oras.PackManifestOptions{})
+	require.NoError(t, err)
+	require.NoError(t, remote.Repo().Tag(ctx, desc, "1.2.5"))
+
+	locked, err := LoadSkeletonResolver(lockPath)
+	require.NoError(t, err)
+	resolved, err = locked.Resolve(ctx, rangeURL)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("oci://%s:1.2.4@%s", repository, digests["1.2.4"]), resolved)
+	pinned, err := zoci.NewRemote(resolved, zoci.PlatformForSkeleton())
+	require.NoError(t, err)
+	require.Equal(t, digests["1.2.4"], pinned.Repo().Reference.Reference)
+	_, err = locked.Resolve(ctx, fmt.Sprintf("oci://%s:^1.0", repository))
+	require.EqualError(t, err, fmt.Sprintf("the skeleton package oci://%s:^1.0 is missing from %s, run 'zarf dev lock' to update it", repository, lockPath))
+
+	unlocked, err := LoadSkeletonResolver(filepath.Join(t.TempDir(), "zarf-compose-lock.yaml"))
+	require.NoError(t, err)
+	resolved, err = unlocked.Resolve(ctx, rangeURL)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("oci://%s:1.2.5@%s", repository, desc.Digest), resolved)
+}
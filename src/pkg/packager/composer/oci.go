@@ -27,8 +27,11 @@ func (ic *ImportChain) getRemote(ctx context.Context, url string) (*zoci.Remote,
 	if ic.remote != nil {
 		return ic.remote, nil
 	}
-	var err error
-	ic.remote, err = zoci.NewRemote(url, zoci.PlatformForSkeleton())
+	resolved, err := ic.skeletons.Resolve(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	ic.remote, err = zoci.NewRemote(resolved, zoci.PlatformForSkeleton())
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +45,7 @@ func (ic *ImportChain) getRemote(ctx context.Context, url string) (*zoci.Remote,
 // ContainsOCIImport returns true if the import chain contains a remote import
 func (ic *ImportChain) ContainsOCIImport() bool {
 	// only the 2nd to last node may have a remote import
-	return ic.tail.prev != nil && ic.tail.prev.Import.URL != ""
+	return ic.tail.prev != nil && ic.tail.prev.isOCIImport()
 }
 
 func (ic *ImportChain) fetchOCISkeleton(ctx context.Context) error {
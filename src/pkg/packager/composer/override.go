@@ -81,7 +81,12 @@ func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponen
 	c.DataInjections = append(c.DataInjections, override.DataInjections...)
 	c.Files = append(c.Files, override.Files...)
 	c.Images = append(c.Images, override.Images...)
+	c.ImageSignatures = append(c.ImageSignatures, override.ImageSignatures...)
+	c.SquashImages = append(c.SquashImages, override.SquashImages...)
 	c.Repos = append(c.Repos, override.Repos...)
+	c.Artifacts = append(c.Artifacts, override.Artifacts...)
+	c.Plugins = append(c.Plugins, override.Plugins...)
+	c.HealthChecks = append(c.HealthChecks, override.HealthChecks...)
 
 	// Merge charts with the same name to keep them unique
 	for _, overrideChart := range override.Charts {
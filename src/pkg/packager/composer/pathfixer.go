@@ -6,6 +6,7 @@ package composer
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
@@ -57,6 +58,14 @@ func fixPaths(child *v1alpha1.ZarfComponent, relativeToHead string) {
 		child.DataInjections[dataInjectionsIdx].Source = composed
 	}
 
+	for signatureIdx, signature := range child.ImageSignatures {
+		// KMS URIs and env:// references are not paths
+		if signature.Key != "" && !strings.Contains(signature.Key, "://") {
+			composed := makePathRelativeTo(signature.Key, relativeToHead)
+			child.ImageSignatures[signatureIdx].Key = composed
+		}
+	}
+
 	defaultDir := child.Actions.OnCreate.Defaults.Dir
 	child.Actions.OnCreate.Before = fixActionPaths(child.Actions.OnCreate.Before, defaultDir, relativeToHead)
 	child.Actions.OnCreate.After = fixActionPaths(child.Actions.OnCreate.After, defaultDir, relativeToHead)
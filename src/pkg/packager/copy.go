@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"errors"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+// Copy copies a package with all of its architectures from one OCI registry to another, streaming its blobs between
+// them rather than pulling the package first.
+func (p *Packager) Copy(ctx context.Context) error {
+	src, ok := p.source.(*sources.OCISource)
+	if !ok {
+		return errors.New("only packages in an OCI registry can be copied")
+	}
+	dst, err := p.newPublishRemote(p.cfg.PublishOpts.PackageDestination, oci.PlatformForArch(config.GetArch()))
+	if err != nil {
+		return err
+	}
+	desc, err := zoci.CopyPackageIndex(ctx, src.Remote, dst, config.CommonOptions.OCIConcurrency)
+	if err != nil {
+		return err
+	}
+	message.Infof("Digest: %s", desc.Digest)
+	return nil
+}
@@ -11,9 +11,12 @@ import (
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/internal/telemetry"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 // Create generates a Zarf package tarball for a given PackageConfig and optional base directory.
@@ -23,6 +26,17 @@ func (p *Packager) Create(ctx context.Context) error {
 		return err
 	}
 
+	if git.IsBuildContext(p.cfg.CreateOpts.BaseDir) {
+		tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		if err := p.cloneBuildContext(ctx, tmp); err != nil {
+			return err
+		}
+	}
+
 	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
 		return fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
 	}
@@ -54,5 +68,29 @@ func (p *Packager) Create(ctx context.Context) error {
 		return err
 	}
 
-	return pc.Output(ctx, p.layout, &p.cfg.Pkg)
+	if err := pc.Output(ctx, p.layout, &p.cfg.Pkg); err != nil {
+		return err
+	}
+	if size, err := helpers.GetDirSize(p.layout.Base); err == nil {
+		telemetry.RecordPackageSize(size)
+	}
+	return nil
+}
+
+// cloneBuildContext clones the git build context the package is created from into tmp and creates the package from
+// the clone instead.
+func (p *Packager) cloneBuildContext(ctx context.Context, tmp string) error {
+	bc, err := git.ParseBuildContext(p.cfg.CreateOpts.BaseDir)
+	if err != nil {
+		return err
+	}
+	spinner := message.NewProgressSpinner("Cloning %s", p.cfg.CreateOpts.BaseDir)
+	defer spinner.Stop()
+	dir, err := git.CloneBuildContext(ctx, tmp, bc, p.cfg.CreateOpts.GitCredentials)
+	if err != nil {
+		return err
+	}
+	spinner.Successf("Cloned %s", p.cfg.CreateOpts.BaseDir)
+	p.cfg.CreateOpts.BaseDir = dir
+	return nil
 }
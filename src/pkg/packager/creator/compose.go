@@ -8,11 +8,13 @@ import (
 	"context"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // ComposeComponents composes components and their dependencies into a single Zarf package using an import chain.
-func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string) (v1alpha1.ZarfPackage, []string, error) {
+func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, gitCredentials []types.GitCredential, skeletons *composer.SkeletonResolver) (v1alpha1.ZarfPackage, []string, error) {
 	components := []v1alpha1.ZarfComponent{}
 	warnings := []string{}
 
@@ -32,7 +34,7 @@ func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor str
 		component.Only.Flavor = ""
 
 		// build the import chain
-		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, flavor)
+		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, flavor, gitCredentials, skeletons)
 		if err != nil {
 			return v1alpha1.ZarfPackage{}, nil, err
 		}
@@ -61,3 +63,12 @@ func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor str
 
 	return pkg, warnings, nil
 }
+
+// newSkeletonResolver returns the resolver of the OCI skeleton packages components are imported from, which takes them
+// from the compose lockfile of the package definition unless it is ignored.
+func newSkeletonResolver(createOpts types.ZarfCreateOptions) (*composer.SkeletonResolver, error) {
+	if createOpts.IgnoreComposeLock {
+		return composer.NewSkeletonResolver(nil, layout.ZarfComposeLockYAML), nil
+	}
+	return composer.LoadSkeletonResolver(layout.ZarfComposeLockYAML)
+}
@@ -161,7 +161,7 @@ func TestComposeComponents(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			pkg, _, err := ComposeComponents(context.Background(), tt.pkg, tt.flavor)
+			pkg, _, err := ComposeComponents(context.Background(), tt.pkg, tt.flavor, nil, nil)
 
 			if tt.expectedErr == "" {
 				require.NoError(t, err)
@@ -6,12 +6,21 @@ package creator
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/defenseunicorns/pkg/oci"
+	v1types "github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -55,3 +64,149 @@ func loadDifferentialData(ctx context.Context, diffPkgPath string) (diffData *ty
 		DifferentialPackageVersion: diffPkg.Metadata.Version,
 	}, nil
 }
+
+// loadRemoteDifferentialData returns the DifferentialData of the published package at ref for the architecture. Only
+// the zarf.yaml, image index and image manifests of the package are pulled, from which the layers of its images are
+// known as well.
+func loadRemoteDifferentialData(ctx context.Context, ref, arch string) (*types.DifferentialData, error) {
+	remote, err := zoci.NewRemote(ref, oci.PlatformForArch(arch))
+	if err != nil {
+		return nil, err
+	}
+	root, err := remote.FetchRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the package %s: %w", ref, err)
+	}
+	diffPkg, err := remote.FetchZarfYAML(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diffData := &types.DifferentialData{
+		DifferentialImages:         map[string]bool{},
+		DifferentialRepos:          map[string]bool{},
+		DifferentialPackageVersion: diffPkg.Metadata.Version,
+		DifferentialLayers:         map[string]map[string]bool{},
+	}
+	for _, component := range diffPkg.Components {
+		for _, image := range component.Images {
+			diffData.DifferentialImages[image] = true
+		}
+		for _, repo := range component.Repos {
+			diffData.DifferentialRepos[repo] = true
+		}
+	}
+
+	if oci.IsEmptyDescriptor(root.Locate(layout.IndexPath)) {
+		return diffData, nil
+	}
+	index, err := remote.FetchImagesIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	diffData.DifferentialLayers, err = imageLayersByRepository(*index, func(desc ocispec.Descriptor) ([]byte, error) {
+		blob := root.Locate(filepath.Join(layout.ImagesBlobsDir, desc.Digest.Encoded()))
+		if oci.IsEmptyDescriptor(blob) {
+			return nil, fmt.Errorf("the manifest %s is not in the package %s", desc.Digest, ref)
+		}
+		return remote.FetchLayer(ctx, blob)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diffData, nil
+}
+
+// imageLayersByRepository returns the layers of the images of an OCI image index by the repository path of the images,
+// reading the manifests of the images with fetch.
+func imageLayersByRepository(index ocispec.Index, fetch func(ocispec.Descriptor) ([]byte, error)) (map[string]map[string]bool, error) {
+	layers := map[string]map[string]bool{}
+	for _, desc := range index.Manifests {
+		name := desc.Annotations[ocispec.AnnotationBaseImageName]
+		if name == "" {
+			continue
+		}
+		refInfo, err := transform.ParseImageRef(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse image ref %q: %w", name, err)
+		}
+		if layers[refInfo.Path] == nil {
+			layers[refInfo.Path] = map[string]bool{}
+		}
+		if err := addImageLayers(layers[refInfo.Path], desc, fetch); err != nil {
+			return nil, err
+		}
+	}
+	return layers, nil
+}
+
+// addImageLayers adds the layers of the image or image index with the given descriptor to layers.
+func addImageLayers(layers map[string]bool, desc ocispec.Descriptor, fetch func(ocispec.Descriptor) ([]byte, error)) error {
+	b, err := fetch(desc)
+	if err != nil {
+		return err
+	}
+	if v1types.MediaType(desc.MediaType).IsIndex() {
+		var index ocispec.Index
+		if err := json.Unmarshal(b, &index); err != nil {
+			return fmt.Errorf("unable to read the image index %s: %w", desc.Digest, err)
+		}
+		for _, child := range index.Manifests {
+			if err := addImageLayers(layers, child, fetch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return fmt.Errorf("unable to read the image manifest %s: %w", desc.Digest, err)
+	}
+	for _, layer := range manifest.Layers {
+		layers[layer.Digest.String()] = true
+	}
+	return nil
+}
+
+// removeDifferentialLayers removes the layers of the images in the image layout at dst that the images of the same
+// repository in the reference package already have, as they are in the registry the reference package was deployed
+// to. A layer that an image of another repository needs is kept, and manifests and configs are always kept.
+func removeDifferentialLayers(dst *layout.Images, diffData *types.DifferentialData) (int, error) {
+	b, err := os.ReadFile(dst.Index)
+	if err != nil {
+		return 0, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return 0, fmt.Errorf("unable to read the image index: %w", err)
+	}
+	layers, err := imageLayersByRepository(index, func(desc ocispec.Descriptor) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dst.Base, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	removable := map[string]bool{}
+	needed := map[string]bool{}
+	for repo, repoLayers := range layers {
+		for layer := range repoLayers {
+			if diffData.DifferentialLayers[repo][layer] {
+				removable[layer] = true
+			} else {
+				needed[layer] = true
+			}
+		}
+	}
+	removed := 0
+	for layer := range removable {
+		if needed[layer] {
+			continue
+		}
+		if err := dst.RemoveBlob(strings.TrimPrefix(layer, "sha256:")); err != nil {
+			return 0, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	v1types "github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestRemoveDifferentialLayers(t *testing.T) {
+	t.Parallel()
+
+	shared, err := random.Layer(64, v1types.DockerLayer)
+	require.NoError(t, err)
+	unchanged, err := random.Layer(64, v1types.DockerLayer)
+	require.NoError(t, err)
+	changed, err := random.Layer(64, v1types.DockerLayer)
+	require.NoError(t, err)
+	other, err := random.Layer(64, v1types.DockerLayer)
+	require.NoError(t, err)
+	digest := func(layer v1.Layer) string {
+		d, err := layer.Digest()
+		require.NoError(t, err)
+		return d.String()
+	}
+
+	tests := []struct {
+		name            string
+		baseLayers      map[string]map[string]bool
+		expectedRemoved []string
+	}{
+		{
+			name:       "base without the repositories",
+			baseLayers: map[string]map[string]bool{"library/postgres": {digest(shared): true}},
+		},
+		{
+			name:            "layer needed by an image of another repository",
+			baseLayers:      map[string]map[string]bool{"library/nginx": {digest(shared): true, digest(unchanged): true}},
+			expectedRemoved: []string{digest(unchanged)},
+		},
+		{
+			name: "layers of both repositories",
+			baseLayers: map[string]map[string]bool{
+				"library/nginx": {digest(shared): true, digest(unchanged): true},
+				"library/redis": {digest(shared): true},
+			},
+			expectedRemoved: []string{digest(shared), digest(unchanged)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths := layout.New(t.TempDir())
+			paths.AddImages()
+			lp, err := clayout.Write(paths.Images.Base, empty.Index)
+			require.NoError(t, err)
+			nginx, err := mutate.AppendLayers(empty.Image, shared, unchanged, changed)
+			require.NoError(t, err)
+			redis, err := mutate.AppendLayers(empty.Image, shared, other)
+			require.NoError(t, err)
+			require.NoError(t, lp.AppendImage(nginx, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: "docker.io/library/nginx:1.26.0"})))
+			require.NoError(t, lp.AppendImage(redis, clayout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: "docker.io/library/redis:7.2.0"})))
+			require.NoError(t, paths.Images.AddV1Image(nginx))
+			require.NoError(t, paths.Images.AddV1Image(redis))
+			blobs := len(paths.Images.Blobs)
+
+			manifests, err := lp.ImageIndex()
+			require.NoError(t, err)
+
+			removed, err := removeDifferentialLayers(&paths.Images, &types.DifferentialData{DifferentialLayers: tt.baseLayers})
+			require.NoError(t, err)
+			require.Equal(t, len(tt.expectedRemoved), removed)
+			require.Len(t, paths.Images.Blobs, blobs-removed)
+
+			for _, layer := range []string{digest(shared), digest(unchanged), digest(changed), digest(other)} {
+				path := filepath.Join(paths.Images.Base, "blobs", "sha256", layer[len("sha256:"):])
+				if slices.Contains(tt.expectedRemoved, layer) {
+					require.NoFileExists(t, path)
+					continue
+				}
+				require.FileExists(t, path)
+			}
+			// The manifests and configs are kept so the images can still be pushed
+			index, err := manifests.IndexManifest()
+			require.NoError(t, err)
+			for _, desc := range index.Manifests {
+				require.FileExists(t, filepath.Join(paths.Images.Base, "blobs", "sha256", desc.Digest.Hex))
+				img, err := lp.Image(desc.Digest)
+				require.NoError(t, err)
+				config, err := img.ConfigName()
+				require.NoError(t, err)
+				require.FileExists(t, filepath.Join(paths.Images.Base, "blobs", "sha256", config.Hex))
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// ImageLock pins the images of a package definition to the digests their tags resolved to when it was locked.
+type ImageLock struct {
+	// The locked images, sorted by image
+	Images []LockedImage `json:"images"`
+}
+
+// LockedImage is an image and the digest its tag resolved to.
+type LockedImage struct {
+	// The image as it is written in the package definition
+	Image string `json:"image"`
+	// The digest the tag of the image resolved to
+	Digest string `json:"digest"`
+}
+
+// LoadImageLock reads an image lockfile, returning whether it exists.
+func LoadImageLock(lockPath string) (ImageLock, bool, error) {
+	var lock ImageLock
+	b, err := os.ReadFile(lockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, false, nil
+	}
+	if err != nil {
+		return lock, false, fmt.Errorf("unable to read the image lockfile: %w", err)
+	}
+	if err := goyaml.UnmarshalWithOptions(b, &lock, goyaml.Strict()); err != nil {
+		return lock, false, fmt.Errorf("unable to parse the image lockfile %s: %w", lockPath, err)
+	}
+	return lock, true, nil
+}
+
+// Write writes the lock to lockPath.
+func (l ImageLock) Write(lockPath string) error {
+	b, err := goyaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(lockPath, b, helpers.ReadWriteUser); err != nil {
+		return fmt.Errorf("unable to write the image lockfile: %w", err)
+	}
+	return nil
+}
+
+// Digests returns the locked digests of the lockable images of the components by image reference, erroring when any
+// of them are missing from the lock.
+func (l ImageLock) Digests(components []v1alpha1.ZarfComponent, lockPath string) (map[string]string, error) {
+	locked := map[string]string{}
+	for _, image := range l.Images {
+		locked[image.Image] = image.Digest
+	}
+	images, err := LockableImages(components)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	missing := []string{}
+	for _, image := range images {
+		digest, ok := locked[image]
+		if !ok {
+			missing = append(missing, image)
+			continue
+		}
+		refInfo, err := transform.ParseImageRef(image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ref for image %s: %w", image, err)
+		}
+		digests[refInfo.Reference] = digest
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf(lang.PkgCreateErrImageLockMissing, len(missing), lockPath, strings.Join(missing, ", "))
+	}
+	return digests, nil
+}
+
+// NewImageLock locks the lockable images of the components to the digests returned by resolve.
+func NewImageLock(components []v1alpha1.ZarfComponent, resolve func(image string) (string, error)) (ImageLock, error) {
+	images, err := LockableImages(components)
+	if err != nil {
+		return ImageLock{}, err
+	}
+	lock := ImageLock{Images: []LockedImage{}}
+	for _, image := range images {
+		digest, err := resolve(image)
+		if err != nil {
+			return ImageLock{}, fmt.Errorf("unable to resolve the digest of %s: %w", image, err)
+		}
+		lock.Images = append(lock.Images, LockedImage{Image: image, Digest: digest})
+	}
+	return lock, nil
+}
+
+// LockableImages returns the sorted images of the components that are referenced by tag, images pinned by digest or
+// loaded from a tarball always resolve to the same content.
+func LockableImages(components []v1alpha1.ZarfComponent) ([]string, error) {
+	images := []string{}
+	for _, component := range components {
+		for _, image := range component.Images {
+			if strings.HasSuffix(image, ".tar") || strings.HasSuffix(image, ".tar.gz") || strings.HasSuffix(image, ".tgz") {
+				continue
+			}
+			refInfo, err := transform.ParseImageRef(image)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create ref for image %s: %w", image, err)
+			}
+			if refInfo.Digest != "" {
+				continue
+			}
+			images = append(images, image)
+		}
+	}
+	slices.Sort(images)
+	return slices.Compact(images), nil
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package creator
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestImageLock(t *testing.T) {
+	t.Parallel()
+
+	components := []v1alpha1.ZarfComponent{
+		{
+			Name: "app",
+			Images: []string{
+				"nginx:1.27",
+				"ghcr.io/my-org/app:1.0.0",
+				"ghcr.io/my-org/pinned:1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				"images/local.tar",
+			},
+		},
+		{
+			Name:   "other",
+			Images: []string{"nginx:1.27"},
+		},
+	}
+
+	images, err := LockableImages(components)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghcr.io/my-org/app:1.0.0", "nginx:1.27"}, images)
+
+	digests := map[string]string{
+		"ghcr.io/my-org/app:1.0.0": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"nginx:1.27":               "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+	}
+	lock, err := NewImageLock(components, func(image string) (string, error) {
+		return digests[image], nil
+	})
+	require.NoError(t, err)
+
+	lockPath := filepath.Join(t.TempDir(), "zarf-lock.yaml")
+	_, exists, err := LoadImageLock(lockPath)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoError(t, lock.Write(lockPath))
+	loaded, exists, err := LoadImageLock(lockPath)
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, lock, loaded)
+
+	// Locked digests are keyed by the fully qualified reference pulled
+	locked, err := loaded.Digests(components, lockPath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"ghcr.io/my-org/app:1.0.0":     digests["ghcr.io/my-org/app:1.0.0"],
+		"docker.io/library/nginx:1.27": digests["nginx:1.27"],
+	}, locked)
+
+	components[1].Images = append(components[1].Images, "nginx:1.28")
+	_, err = loaded.Digests(components, lockPath)
+	require.ErrorContains(t, err, "1 image(s) missing from")
+	require.ErrorContains(t, err, "nginx:1.28")
+
+	_, err = NewImageLock(components, func(_ string) (string, error) {
+		return "", errors.New("not found")
+	})
+	require.EqualError(t, err, "unable to resolve the digest of ghcr.io/my-org/app:1.0.0: not found")
+}
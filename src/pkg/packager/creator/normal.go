@@ -11,31 +11,40 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/mholt/archiver/v3"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/extensions/bigbang"
 	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/internal/packager/artifacts"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/images"
 	"github.com/zarf-dev/zarf/src/internal/packager/kustomize"
+	"github.com/zarf-dev/zarf/src/internal/packager/plugins"
 	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
+	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -46,6 +55,15 @@ var (
 // PackageCreator provides methods for creating normal (not skeleton) Zarf packages.
 type PackageCreator struct {
 	createOpts types.ZarfCreateOptions
+
+	// differential is the published package a differential package is created from
+	differential *types.DifferentialData
+	// skeletons resolves the OCI skeleton packages components are imported from
+	skeletons *composer.SkeletonResolver
+
+	mu sync.Mutex
+	// materials are the images and git repositories the package is built from
+	materials []v1alpha1.ZarfBuildMaterial
 }
 
 func updateRelativeDifferentialPackagePath(path string, cwd string) string {
@@ -58,7 +76,7 @@ func updateRelativeDifferentialPackagePath(path string, cwd string) string {
 // NewPackageCreator returns a new PackageCreator.
 func NewPackageCreator(createOpts types.ZarfCreateOptions, cwd string) *PackageCreator {
 	createOpts.DifferentialPackagePath = updateRelativeDifferentialPackagePath(createOpts.DifferentialPackagePath, cwd)
-	return &PackageCreator{createOpts}
+	return &PackageCreator{createOpts: createOpts}
 }
 
 // LoadPackageDefinition loads and configures a zarf.yaml file during package create.
@@ -71,7 +89,11 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 	pkg.Metadata.Architecture = config.GetArch(pkg.Metadata.Architecture)
 
 	// Compose components into a single zarf.yaml file
-	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, pc.createOpts.Flavor)
+	pc.skeletons, err = newSkeletonResolver(pc.createOpts)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, pc.createOpts.Flavor, pc.createOpts.GitCredentials, pc.skeletons)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
@@ -90,12 +112,30 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
+	pkg.Components = addOperatorCatalogImages(pkg.Components)
+	pkg.Components, err = resolveImageLabels(ctx, pkg.Components, func(ctx context.Context, selector images.LabelSelector) ([]string, error) {
+		return images.LocalImagesByLabel(ctx, selector, pc.createOpts.ContainerdAddress, pc.createOpts.ContainerdNamespace)
+	})
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
 
 	// If we are creating a differential package, remove duplicate images and repos.
-	if pc.createOpts.DifferentialPackagePath != "" {
+	if pc.createOpts.DifferentialPackagePath != "" || pc.createOpts.DifferentialFrom != "" {
 		pkg.Build.Differential = true
 
-		diffData, err := loadDifferentialData(ctx, pc.createOpts.DifferentialPackagePath)
+		var diffData *types.DifferentialData
+		if pc.createOpts.DifferentialFrom != "" {
+			if !helpers.IsOCIURL(pc.createOpts.DifferentialFrom) {
+				return v1alpha1.ZarfPackage{}, nil, fmt.Errorf(lang.PkgCreateErrDifferentialFromOCI, pc.createOpts.DifferentialFrom)
+			}
+			diffData, err = loadRemoteDifferentialData(ctx, pc.createOpts.DifferentialFrom, pkg.Metadata.Architecture)
+			// The layers of the published package are left out of this package once its images are pulled
+			pc.differential = diffData
+			pkg.Build.DifferentialPackage = pc.createOpts.DifferentialFrom
+		} else {
+			diffData, err = loadDifferentialData(ctx, pc.createOpts.DifferentialPackagePath)
+		}
 		if err != nil {
 			return v1alpha1.ZarfPackage{}, nil, err
 		}
@@ -123,43 +163,61 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
 
+	if pc.createOpts.ImagePolicyPath != "" {
+		policy, err := LoadImagePolicy(pc.createOpts.ImagePolicyPath)
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+		if err := policy.Check(pkg.Components); err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+	}
+
 	return pkg, warnings, nil
 }
 
+// ComposeLock returns the OCI skeleton packages the components loaded by LoadPackageDefinition were imported from.
+func (pc *PackageCreator) ComposeLock() composer.ComposeLock {
+	if pc.skeletons == nil {
+		return composer.ComposeLock{Skeletons: []composer.LockedSkeleton{}}
+	}
+	return pc.skeletons.Lock()
+}
+
 // Assemble assembles all of the package assets into Zarf's tmp directory layout.
 func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths, components []v1alpha1.ZarfComponent, arch string) error {
 	var imageList []transform.Image
+	imageSignatures := map[string][]v1alpha1.ZarfImageSignature{}
+	squashImages := map[string]bool{}
 
-	skipSBOMFlagUsed := pc.createOpts.SkipSBOM
-	componentSBOMs := map[string]*layout.ComponentSBOM{}
-
-	for _, component := range components {
-		onCreate := component.Actions.OnCreate
-
-		onFailure := func() {
-			if err := actions.Run(ctx, onCreate.Defaults, onCreate.OnFailure, nil); err != nil {
-				message.Debugf("unable to run component failure action: %s", err.Error())
-			}
-		}
-
-		if err := pc.addComponent(ctx, component, dst); err != nil {
-			onFailure()
-			return fmt.Errorf("unable to add component %q: %w", component.Name, err)
+	// Images are locked to digests when the package definition has a lockfile, similar to a go.sum
+	lock, locked, err := LoadImageLock(layout.ZarfLockYAML)
+	if err != nil {
+		return err
+	}
+	var lockedDigests map[string]string
+	if locked {
+		lockedDigests, err = lock.Digests(components, layout.ZarfLockYAML)
+		if err != nil {
+			return err
 		}
+	}
 
-		if err := actions.Run(ctx, onCreate.Defaults, onCreate.OnSuccess, nil); err != nil {
-			onFailure()
-			return fmt.Errorf("unable to run component success action: %w", err)
+	for _, batch := range componentBatches(components, pc.createOpts.ComponentConcurrency) {
+		if err := pc.assembleBatch(ctx, dst, components, batch, arch); err != nil {
+			return err
 		}
+	}
 
-		if !skipSBOMFlagUsed {
-			componentSBOM, err := pc.getFilesToSBOM(component, dst)
+	for i, component := range components {
+		if pc.createOpts.IncludeReferrers && len(component.Images) > 0 {
+			referrers, err := findComponentReferrers(ctx, component, pc.createOpts.RegistryOverrides, arch)
 			if err != nil {
-				return fmt.Errorf("unable to create component SBOM: %w", err)
-			}
-			if componentSBOM != nil && len(componentSBOM.Files) > 0 {
-				componentSBOMs[component.Name] = componentSBOM
+				return fmt.Errorf("unable to find the referrers of the images of component %q: %w", component.Name, err)
 			}
+			// Record the referrers as component images so they are pushed alongside the images on deploy.
+			components[i].Images = helpers.Unique(append(components[i].Images, referrers...))
+			component.Images = components[i].Images
 		}
 
 		// Combine all component images into a single entry for efficient layer reuse.
@@ -169,6 +227,14 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 				return fmt.Errorf("failed to create ref for image %s: %w", src, err)
 			}
 			imageList = append(imageList, refInfo)
+			for _, signature := range component.ImageSignatures {
+				if signature.Matches(src) {
+					imageSignatures[refInfo.Reference] = append(imageSignatures[refInfo.Reference], signature)
+				}
+			}
+			if component.SquashesImage(src) {
+				squashImages[refInfo.Reference] = true
+			}
 		}
 	}
 
@@ -176,7 +242,6 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 	rs := rand.NewSource(time.Now().UnixNano())
 	rnd := rand.New(rs)
 	rnd.Shuffle(len(imageList), func(i, j int) { imageList[i], imageList[j] = imageList[j], imageList[i] })
-	var sbomImageList []transform.Image
 
 	// Images are handled separately from other component assets.
 	if len(imageList) > 0 {
@@ -189,7 +254,15 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 			ImageList:            imageList,
 			Arch:                 arch,
 			RegistryOverrides:    pc.createOpts.RegistryOverrides,
+			RegistryMirrors:      pc.createOpts.RegistryMirrors,
+			Proxy:                pc.createOpts.Proxy,
 			CacheDirectory:       filepath.Join(config.GetAbsCachePath(), layout.ImagesDir),
+			Digests:              lockedDigests,
+			CacheMaxSize:         int64(pc.createOpts.MaxCacheSizeMB) * 1000 * 1000,
+			AllPlatforms:         pc.createOpts.AllPlatforms,
+			ContainerdAddress:    pc.createOpts.ContainerdAddress,
+			ContainerdNamespace:  pc.createOpts.ContainerdNamespace,
+			Concurrency:          config.CommonOptions.ImageConcurrency,
 		}
 
 		pulled, err := images.Pull(ctx, pullCfg)
@@ -197,28 +270,79 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 			return err
 		}
 
+		for info, img := range pulled {
+			// The images are recorded as pulled, before squashing or recompressing them changes their digests
+			digest, err := img.Digest()
+			if err != nil {
+				return err
+			}
+			pc.addMaterial(helpers.OCIURLPrefix+info.Reference, digest.Algorithm, digest.Hex)
+
+			signatures, ok := imageSignatures[info.Reference]
+			if !ok {
+				continue
+			}
+			idx, err := utils.LoadOCIImageIndex(dst.Images.Base, info)
+			if err != nil {
+				return err
+			}
+			if err := verifyImageSignatures(ctx, info, img, idx, signatures); err != nil {
+				return err
+			}
+		}
+
+		// Squash and recompress after verifying signatures since both change the image digests.
+		if len(squashImages) > 0 {
+			pulled, err = images.SquashImages(ctx, dst.Images.Base, pulled, func(info transform.Image) bool {
+				return squashImages[info.Reference]
+			}, config.CommonOptions.ImageConcurrency)
+			if err != nil {
+				return err
+			}
+		}
+		if pc.createOpts.RecompressZstd {
+			pulled, err = images.RecompressZstd(ctx, dst.Images.Base, pulled, config.CommonOptions.ImageConcurrency)
+			if err != nil {
+				return err
+			}
+		}
+		if pc.createOpts.ConvertEstargz {
+			pulled, err = images.ConvertEstargz(ctx, dst.Images.Base, pulled, config.CommonOptions.ImageConcurrency)
+			if err != nil {
+				return err
+			}
+		}
+
 		for info, img := range pulled {
 			if err := dst.Images.AddV1Image(img); err != nil {
 				return err
 			}
-			ok, err := utils.OnlyHasImageLayers(img)
+			idx, err := utils.LoadOCIImageIndex(dst.Images.Base, info)
 			if err != nil {
-				return fmt.Errorf("failed to validate %s is an image and not an artifact: %w", info, err)
+				return err
 			}
-			if ok {
-				sbomImageList = append(sbomImageList, info)
+			if idx != nil {
+				if err := dst.Images.AddV1ImageIndex(idx); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
 	// Ignore SBOM creation if the flag is set.
-	if skipSBOMFlagUsed {
+	if pc.createOpts.SkipSBOM {
 		message.Debug("Skipping image SBOM processing per --skip-sbom flag")
-	} else {
-		dst.AddSBOMs()
-		if err := sbom.Catalog(componentSBOMs, sbomImageList, dst); err != nil {
-			return fmt.Errorf("unable to create an SBOM catalog for the package: %w", err)
+	} else if err := GenerateSBOMs(dst, components, pc.createOpts); err != nil {
+		return err
+	}
+
+	// Layers are removed after the SBOMs are generated from them
+	if pc.differential != nil && len(imageList) > 0 {
+		removed, err := removeDifferentialLayers(&dst.Images, pc.differential)
+		if err != nil {
+			return fmt.Errorf("unable to remove the layers of %s: %w", pc.createOpts.DifferentialFrom, err)
 		}
+		message.Debugf("Removed %d image layers that %s already has", removed, pc.createOpts.DifferentialFrom)
 	}
 
 	return nil
@@ -236,6 +360,8 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 //
 // - writes the Zarf package as a tarball to a local directory,
 // or an OCI registry based on the --output flag
+//
+// When writing a tarball the checksums are generated while the package files are written to it.
 func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths, pkg *v1alpha1.ZarfPackage) (err error) {
 	// Process the component directories into compressed tarballs
 	// NOTE: This is purposefully being done after the SBOM cataloging
@@ -246,28 +372,32 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 		}
 	}
 
-	// Calculate all the checksums
-	pkg.Metadata.AggregateChecksum, err = dst.GenerateChecksums()
-	if err != nil {
-		return fmt.Errorf("unable to generate checksums for the package: %w", err)
-	}
-
-	if err := recordPackageMetadata(pkg, pc.createOpts); err != nil {
-		return err
-	}
-
-	if err := utils.WriteYaml(dst.ZarfYAML, pkg, helpers.ReadUser); err != nil {
-		return fmt.Errorf("unable to write zarf.yaml: %w", err)
-	}
-
-	// Sign the package if a key has been provided
-	if err := dst.SignPackage(pc.createOpts.SigningKeyPath, pc.createOpts.SigningKeyPassword, !config.CommonOptions.Confirm); err != nil {
-		return err
+	// Record the checksums and metadata, then write and sign the zarf.yaml
+	finalize := func(aggregateChecksum string) error {
+		pkg.Metadata.AggregateChecksum = aggregateChecksum
+		if err := recordPackageMetadata(pkg, pc.createOpts); err != nil {
+			return err
+		}
+		pkg.Build.Materials = pc.sortedMaterials()
+		if err := utils.WriteYaml(dst.ZarfYAML, pkg, helpers.ReadUser); err != nil {
+			return fmt.Errorf("unable to write zarf.yaml: %w", err)
+		}
+		// Sign the package if a key has been provided
+		return dst.SignPackage(pc.createOpts.SigningKeyPath, pc.createOpts.SigningKeyPassword, !config.CommonOptions.Confirm)
 	}
 
 	// Create a remote ref + client for the package (if output is OCI)
 	// then publish the package to the remote.
 	if helpers.IsOCIURL(pc.createOpts.Output) {
+		// Calculate all the checksums
+		aggregateChecksum, err := dst.GenerateChecksums()
+		if err != nil {
+			return fmt.Errorf("unable to generate checksums for the package: %w", err)
+		}
+		if err := finalize(aggregateChecksum); err != nil {
+			return err
+		}
+
 		ref, err := zoci.ReferenceFromMetadata(pc.createOpts.Output, &pkg.Metadata, &pkg.Build)
 		if err != nil {
 			return err
@@ -296,9 +426,18 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 
 		// Try to remove the package if it already exists.
 		_ = os.Remove(tarballPath)
+		_ = os.Remove(tarballPath + utils.AgeSuffix)
 
-		// Create the package tarball.
-		if err := dst.ArchivePackage(tarballPath, pc.createOpts.MaxPackageSizeMB); err != nil {
+		// Create the package tarball, calculating the checksums while the files are written to it.
+		if len(pc.createOpts.EncryptRecipients) > 0 || pc.createOpts.EncryptPassphrase != "" {
+			encrypt, err := utils.NewAgeEncryptor(pc.createOpts.EncryptRecipients, pc.createOpts.EncryptPassphrase)
+			if err != nil {
+				return err
+			}
+			if err := dst.ArchiveEncryptedPackageWithChecksums(tarballPath, pc.createOpts.MaxPackageSizeMB, encrypt, finalize); err != nil {
+				return fmt.Errorf("unable to archive package: %w", err)
+			}
+		} else if err := dst.ArchivePackageWithChecksums(tarballPath, pc.createOpts.MaxPackageSizeMB, finalize); err != nil {
 			return fmt.Errorf("unable to archive package: %w", err)
 		}
 	}
@@ -330,6 +469,29 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 	return nil
 }
 
+// addMaterial records an image or git repository the package is built from.
+func (pc *PackageCreator) addMaterial(uri, algorithm, digest string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	// Components can share a git repository
+	for _, material := range pc.materials {
+		if material.URI == uri {
+			return
+		}
+	}
+	pc.materials = append(pc.materials, v1alpha1.ZarfBuildMaterial{URI: uri, Digest: map[string]string{algorithm: digest}})
+}
+
+// sortedMaterials returns the recorded materials ordered by URI, as components are assembled concurrently.
+func (pc *PackageCreator) sortedMaterials() []v1alpha1.ZarfBuildMaterial {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	sort.Slice(pc.materials, func(i, j int) bool {
+		return pc.materials[i].URI < pc.materials[j].URI
+	})
+	return pc.materials
+}
+
 func (pc *PackageCreator) processExtensions(ctx context.Context, components []v1alpha1.ZarfComponent, layout *layout.PackagePaths, isYOLO bool) (processedComponents []v1alpha1.ZarfComponent, err error) {
 	// Create component paths and process extensions for each component.
 	for _, c := range components {
@@ -351,10 +513,106 @@ func (pc *PackageCreator) processExtensions(ctx context.Context, components []v1
 	return processedComponents, nil
 }
 
-func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.ZarfComponent, dst *layout.PackagePaths) error {
+// downloadOptions returns how the remote files and manifests of components are downloaded.
+func (pc *PackageCreator) downloadOptions() utils.DownloadOptions {
+	return utils.DownloadOptions{
+		Retries:        pc.createOpts.Retries,
+		Mirrors:        pc.createOpts.URLMirrors,
+		CacheDirectory: filepath.Join(config.GetAbsCachePath(), "downloads"),
+	}
+}
+
+// componentBatches splits the indexes of the components into batches that are assembled one after another, with the
+// components of a batch assembled at once. Create actions and plugins run commands that may depend on the components
+// before them, so components that have them are always assembled in a batch of their own.
+func componentBatches(components []v1alpha1.ZarfComponent, concurrency int) [][]int {
+	batches := [][]int{}
+	var batch []int
+	for i, component := range components {
+		onCreate := component.Actions.OnCreate
+		hasActions := len(onCreate.Before)+len(onCreate.After)+len(onCreate.OnSuccess)+len(onCreate.OnFailure) > 0
+		if concurrency <= 1 || hasActions || len(component.Plugins) > 0 {
+			if len(batch) > 0 {
+				batches = append(batches, batch)
+				batch = nil
+			}
+			batches = append(batches, []int{i})
+			continue
+		}
+		batch = append(batch, i)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// assembleBatch assembles a batch of components into the package layout. The components of a batch with more than one
+// component are assembled concurrently, each into its own workspace, and moved into the layout in order once all of
+// them succeed. Remote files, charts and images are still fetched through the shared caches.
+func (pc *PackageCreator) assembleBatch(ctx context.Context, dst *layout.PackagePaths, components []v1alpha1.ZarfComponent, batch []int, arch string) error {
+	if len(batch) == 1 {
+		return pc.assembleComponent(ctx, components[batch[0]], &dst.Components, arch)
+	}
+
+	workspace, err := os.MkdirTemp(dst.Base, "workspace-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workspace)
+
+	message.Infof("Assembling %d components concurrently", len(batch))
+	workspaces := make([]*layout.Components, len(batch))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(pc.createOpts.ComponentConcurrency)
+	for i, idx := range batch {
+		workspaces[i] = &layout.Components{Base: filepath.Join(workspace, strconv.Itoa(i))}
+		g.Go(func() error {
+			return pc.assembleComponent(gCtx, components[idx], workspaces[i], arch)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	for i, idx := range batch {
+		if _, err := dst.Components.Move(workspaces[i], components[idx]); err != nil {
+			return fmt.Errorf("unable to move component %q into the package: %w", components[idx].Name, err)
+		}
+	}
+	return nil
+}
+
+// assembleComponent adds a component to the components directory and runs its create success or failure actions.
+func (pc *PackageCreator) assembleComponent(ctx context.Context, component v1alpha1.ZarfComponent, dst *layout.Components, arch string) error {
+	onCreate := component.Actions.OnCreate
+
+	onFailure := func() {
+		if err := actions.Run(ctx, onCreate.Defaults, onCreate.OnFailure, nil); err != nil {
+			message.Debugf("unable to run component failure action: %s", err.Error())
+		}
+	}
+
+	if err := pc.addComponent(ctx, component, dst, arch); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to add component %q: %w", component.Name, err)
+	}
+
+	if err := actions.Run(ctx, onCreate.Defaults, onCreate.OnSuccess, nil); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to run component success action: %w", err)
+	}
+	return nil
+}
+
+func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.ZarfComponent, dst *layout.Components, arch string) error {
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
+	if message.IsVerbose(message.VerbosityComponent) {
+		if b, err := goyaml.Marshal(component); err == nil {
+			message.Debugf("Creating the component %s:\n%s", component.Name, b)
+		}
+	}
 
-	componentPaths, err := dst.Components.Create(component)
+	componentPaths, err := dst.Create(component)
 	if err != nil {
 		return err
 	}
@@ -388,7 +646,7 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 				compressedFile := filepath.Join(componentPaths.Temp, compressedFileName)
 
 				// If the file is an archive, download it to the componentPath.Temp
-				if err := utils.DownloadToFile(ctx, file.Source, compressedFile, component.DeprecatedCosignKeyPath); err != nil {
+				if err := utils.DownloadToFileWithOptions(ctx, file.Source, compressedFile, component.DeprecatedCosignKeyPath, pc.downloadOptions()); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err.Error())
 				}
 
@@ -397,7 +655,7 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 					return fmt.Errorf(lang.ErrFileExtract, file.ExtractPath, compressedFileName, err.Error())
 				}
 			} else {
-				if err := utils.DownloadToFile(ctx, file.Source, dst, component.DeprecatedCosignKeyPath); err != nil {
+				if err := utils.DownloadToFileWithOptions(ctx, file.Source, dst, component.DeprecatedCosignKeyPath, pc.downloadOptions()); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err.Error())
 				}
 			}
@@ -448,7 +706,7 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 			dst := filepath.Join(componentPaths.Base, rel)
 
 			if helpers.IsURL(data.Source) {
-				if err := utils.DownloadToFile(ctx, data.Source, dst, component.DeprecatedCosignKeyPath); err != nil {
+				if err := utils.DownloadToFileWithOptions(ctx, data.Source, dst, component.DeprecatedCosignKeyPath, pc.downloadOptions()); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, data.Source, err.Error())
 				}
 			} else {
@@ -481,7 +739,7 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 				// Copy manifests without any processing.
 				spinner.Updatef("Copying manifest %s", path)
 				if helpers.IsURL(path) {
-					if err := utils.DownloadToFile(ctx, path, dst, component.DeprecatedCosignKeyPath); err != nil {
+					if err := utils.DownloadToFileWithOptions(ctx, path, dst, component.DeprecatedCosignKeyPath, pc.downloadOptions()); err != nil {
 						return fmt.Errorf(lang.ErrDownloading, path, err.Error())
 					}
 				} else {
@@ -514,14 +772,60 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 
 		for _, url := range component.Repos {
 			// Pull all the references if there is no `@` in the string.
-			_, err := git.Clone(ctx, componentPaths.Repos, url, false)
+			repo, err := git.Clone(ctx, componentPaths.Repos, url, false)
 			if err != nil {
 				return fmt.Errorf("unable to pull git repo %s: %w", url, err)
 			}
+			head, err := repo.Head()
+			if err != nil {
+				return err
+			}
+			pc.addMaterial("git+"+url, "gitCommit", head)
 		}
 		spinner.Success()
 	}
 
+	// Resolve all specified language ecosystem packages.
+	if len(component.Artifacts) > 0 {
+		resolved := []artifacts.Artifact{}
+		for _, set := range component.Artifacts {
+			spinner := message.NewProgressSpinner("Loading %d %s packages", len(set.Packages), set.Type)
+			files, err := artifacts.Resolve(ctx, set, componentPaths.Artifacts, componentPaths.Temp, pc.downloadOptions())
+			if err != nil {
+				spinner.Stop()
+				return err
+			}
+			resolved = append(resolved, files...)
+			spinner.Success()
+		}
+		if err := artifacts.WriteIndex(componentPaths.Artifacts, resolved); err != nil {
+			return err
+		}
+	}
+
+	if len(component.Plugins) > 0 {
+		baseDir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		for pluginIdx, plugin := range component.Plugins {
+			pluginDir := filepath.Join(componentPaths.Plugins, strconv.Itoa(pluginIdx))
+			if err := helpers.CreateDirectory(pluginDir, helpers.ReadWriteExecuteUser); err != nil {
+				return err
+			}
+			req := plugins.Request{
+				Phase:        plugins.PhaseCreate,
+				Component:    component.Name,
+				Dir:          pluginDir,
+				BaseDir:      baseDir,
+				Architecture: arch,
+			}
+			if err := plugins.Run(ctx, plugin, req); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err := actions.Run(ctx, onCreate.Defaults, onCreate.After, nil); err != nil {
 		return fmt.Errorf("unable to run component after action: %w", err)
 	}
@@ -529,36 +833,58 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 	return nil
 }
 
-func (pc *PackageCreator) getFilesToSBOM(component v1alpha1.ZarfComponent, dst *layout.PackagePaths) (*layout.ComponentSBOM, error) {
-	componentPaths, err := dst.Components.Create(component)
-	if err != nil {
-		return nil, err
-	}
-	// Create an struct to hold the SBOM information for this component.
-	componentSBOM := &layout.ComponentSBOM{
-		Files:     []string{},
-		Component: componentPaths,
-	}
+// findComponentReferrers returns the cosign artifacts and OCI referrers attached to the images of a component.
+func findComponentReferrers(ctx context.Context, component v1alpha1.ZarfComponent, registryOverrides map[string]string, arch string) ([]string, error) {
+	spinner := message.NewProgressSpinner("Looking up the referrers of %d images", len(component.Images))
+	defer spinner.Stop()
 
-	appendSBOMFiles := func(path string) {
-		if helpers.IsDir(path) {
-			files, _ := helpers.RecursiveFileList(path, nil, false)
-			componentSBOM.Files = append(componentSBOM.Files, files...)
-		} else {
-			componentSBOM.Files = append(componentSBOM.Files, path)
+	referrers := []string{}
+	for _, src := range component.Images {
+		refInfo, err := transform.ParseImageRef(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ref for image %s: %w", src, err)
 		}
+		spinner.Updatef("Looking up the referrers of %s", refInfo.Reference)
+		found, err := images.FindReferrers(ctx, refInfo, registryOverrides, images.CommonOpts(arch)...)
+		if err != nil {
+			return nil, err
+		}
+		for _, referrer := range found {
+			message.Debugf("Including the referrer %s of %s", referrer, refInfo.Reference)
+		}
+		referrers = append(referrers, found...)
 	}
+	spinner.Successf("Found %d referrers of %d images", len(referrers), len(component.Images))
+	return referrers, nil
+}
 
-	for filesIdx, file := range component.Files {
-		path := filepath.Join(componentPaths.Files, strconv.Itoa(filesIdx), filepath.Base(file.Target))
-		appendSBOMFiles(path)
+// verifyImageSignatures verifies the cosign signatures required on a pulled image against its digest, using the
+// digest of the image index the image was selected from when it was pulled from one.
+func verifyImageSignatures(ctx context.Context, info transform.Image, img v1.Image, idx v1.ImageIndex, signatures []v1alpha1.ZarfImageSignature) error {
+	digest, err := img.Digest()
+	if idx != nil {
+		digest, err = idx.Digest()
+	}
+	if err != nil {
+		return err
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s@%s", info.Name, digest))
+	if err != nil {
+		return err
 	}
 
-	for dataIdx, data := range component.DataInjections {
-		path := filepath.Join(componentPaths.DataInjections, strconv.Itoa(dataIdx), filepath.Base(data.Target.Path))
+	spinner := message.NewProgressSpinner("Verifying the cosign signatures of %s", info.Reference)
+	defer spinner.Stop()
 
-		appendSBOMFiles(path)
+	for _, signature := range signatures {
+		signer := signature.Key
+		if signer == "" {
+			signer = signature.Identity
+		}
+		if err := utils.CosignVerifyImage(ctx, ref, signature.Key, signature.Identity, signature.Issuer); err != nil {
+			return fmt.Errorf(lang.PkgCreateErrImageSignature, info.Reference, signer, err)
+		}
 	}
-
-	return componentSBOM, nil
+	spinner.Successf("Verified the cosign signatures of %s (%s)", info.Reference, digest)
+	return nil
 }
@@ -5,10 +5,22 @@
 package creator
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 func TestDifferentialPackagePathSetCorrectly(t *testing.T) {
@@ -56,3 +68,220 @@ func TestDifferentialPackagePathSetCorrectly(t *testing.T) {
 		})
 	}
 }
+
+func TestAddOperatorCatalogImages(t *testing.T) {
+	t.Parallel()
+
+	components := []v1alpha1.ZarfComponent{
+		{
+			Name:   "listed",
+			Images: []string{"quay.io/operatorhubio/catalog:latest"},
+			Operators: []v1alpha1.ZarfOperator{
+				{Name: "etcd", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+			},
+		},
+		{
+			Name:   "unlisted",
+			Images: []string{"quay.io/coreos/etcd-operator:v0.9.4"},
+			Operators: []v1alpha1.ZarfOperator{
+				{Name: "etcd", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+				{Name: "prometheus", CatalogImage: "quay.io/operatorhubio/catalog:latest"},
+			},
+		},
+		{
+			Name: "none",
+		},
+	}
+	components = addOperatorCatalogImages(components)
+	require.Equal(t, []string{"quay.io/operatorhubio/catalog:latest"}, components[0].Images)
+	require.Equal(t, []string{"quay.io/coreos/etcd-operator:v0.9.4", "quay.io/operatorhubio/catalog:latest"}, components[1].Images)
+	require.Empty(t, components[2].Images)
+}
+
+func TestResolveImageLabels(t *testing.T) {
+	t.Parallel()
+
+	local := map[string][]string{
+		"com.example.build=123": {"ci.example.com/api:123", "ci.example.com/web:123"},
+		"com.example.app":       {"ci.example.com/api:123"},
+	}
+	find := func(_ context.Context, selector images.LabelSelector) ([]string, error) {
+		return local[selector.String()], nil
+	}
+
+	tests := []struct {
+		name     string
+		images   []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "no selectors",
+			images:   []string{"ghcr.io/stefanprodan/podinfo:6.4.0"},
+			expected: []string{"ghcr.io/stefanprodan/podinfo:6.4.0"},
+		},
+		{
+			name:     "label value",
+			images:   []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "docker-label:com.example.build=123"},
+			expected: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "ci.example.com/api:123", "ci.example.com/web:123"},
+		},
+		{
+			name:     "label key with duplicate images",
+			images:   []string{"docker-label:com.example.app", "ci.example.com/api:123"},
+			expected: []string{"ci.example.com/api:123"},
+		},
+		{
+			name:   "no matching images",
+			images: []string{"docker-label:com.example.build=456"},
+			err:    `component "app": no local images found with label com.example.build=456`,
+		},
+		{
+			name:   "invalid selector",
+			images: []string{"docker-label:=123"},
+			err:    `invalid label selector "=123"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			components := []v1alpha1.ZarfComponent{{Name: "app", Images: tt.images}}
+			components, err := resolveImageLabels(context.Background(), components, find)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, components[0].Images)
+		})
+	}
+}
+
+func TestComponentBatches(t *testing.T) {
+	t.Parallel()
+
+	actions := v1alpha1.ZarfComponentActions{
+		OnCreate: v1alpha1.ZarfComponentActionSet{
+			Before: []v1alpha1.ZarfComponentAction{{Cmd: "make"}},
+		},
+	}
+	components := []v1alpha1.ZarfComponent{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "build", Actions: actions},
+		{Name: "c"},
+		{Name: "plugin", Plugins: []v1alpha1.ZarfComponentPlugin{{Name: "plugin"}}},
+		{Name: "d"},
+		{Name: "e"},
+	}
+
+	tests := []struct {
+		name        string
+		concurrency int
+		expected    [][]int
+	}{
+		{
+			name:        "serial",
+			concurrency: 1,
+			expected:    [][]int{{0}, {1}, {2}, {3}, {4}, {5}, {6}},
+		},
+		{
+			name:        "unset",
+			concurrency: 0,
+			expected:    [][]int{{0}, {1}, {2}, {3}, {4}, {5}, {6}},
+		},
+		{
+			name:        "concurrent",
+			concurrency: 4,
+			expected:    [][]int{{0, 1}, {2}, {3}, {4}, {5, 6}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, componentBatches(components, tt.concurrency))
+		})
+	}
+}
+
+func TestAssembleComponentsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	components := []v1alpha1.ZarfComponent{}
+	for _, name := range []string{"first", "second", "third"} {
+		path := filepath.Join(src, name+".txt")
+		require.NoError(t, os.WriteFile(path, []byte(name), 0o600))
+		components = append(components, v1alpha1.ZarfComponent{
+			Name:  name,
+			Files: []v1alpha1.ZarfFile{{Source: path, Target: name + ".txt"}},
+		})
+	}
+
+	dst := layout.New(t.TempDir())
+	pc := NewPackageCreator(types.ZarfCreateOptions{ComponentConcurrency: 2}, "")
+	for _, batch := range componentBatches(components, 2) {
+		require.NoError(t, pc.assembleBatch(context.Background(), dst, components, batch, "amd64"))
+	}
+
+	require.Len(t, dst.Components.Dirs, 3)
+	for _, component := range components {
+		paths := dst.Components.Dirs[component.Name]
+		require.Equal(t, filepath.Join(dst.Components.Base, component.Name), paths.Base)
+		b, err := os.ReadFile(filepath.Join(paths.Files, "0", component.Name+".txt"))
+		require.NoError(t, err)
+		require.Equal(t, component.Name, string(b))
+	}
+	// The workspaces are removed once the components are moved into the package
+	entries, err := os.ReadDir(dst.Base)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NotContains(t, entry.Name(), "workspace-")
+	}
+}
+
+func TestAssembleComponentsConcurrentlySharedDownload(t *testing.T) {
+	cachePath := config.CommonOptions.CachePath
+	config.CommonOptions.CachePath = t.TempDir()
+	t.Cleanup(func() {
+		config.CommonOptions.CachePath = cachePath
+	})
+
+	content := bytes.Repeat([]byte("zarf"), 1<<20)
+	var notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		//nolint:errcheck // ignore
+		rw.Write(content)
+	}))
+	t.Cleanup(srv.Close)
+
+	components := []v1alpha1.ZarfComponent{}
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		components = append(components, v1alpha1.ZarfComponent{
+			Name:  name,
+			Files: []v1alpha1.ZarfFile{{Source: srv.URL + "/file.txt", Target: "file.txt"}},
+		})
+	}
+	pc := NewPackageCreator(types.ZarfCreateOptions{ComponentConcurrency: 4}, "")
+	batches := componentBatches(components, 4)
+	require.Len(t, batches, 1)
+
+	// The components download the same URL at once, first filling the cache and then revalidating it
+	for range 2 {
+		dst := layout.New(t.TempDir())
+		require.NoError(t, pc.assembleBatch(context.Background(), dst, components, batches[0], "amd64"))
+		for _, component := range components {
+			b, err := os.ReadFile(filepath.Join(dst.Components.Dirs[component.Name].Files, "0", "file.txt"))
+			require.NoError(t, err)
+			require.Equal(t, content, b)
+		}
+	}
+	require.Positive(t, notModified.Load())
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	goyaml "github.com/goccy/go-yaml"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// ImagePolicy constrains the images packages may contain, it is set by an organization rather than a package author.
+//
+// Patterns are globs matched against fully qualified image names without their tag or digest (e.g.
+// docker.io/library/nginx). A pattern also matches every image below it, so "ghcr.io/my-org" matches
+// ghcr.io/my-org/app and "*.dso.mil" matches every image of the registries under dso.mil.
+type ImagePolicy struct {
+	// Patterns of the images packages may contain, every image is allowed when empty
+	Allow []string `json:"allow,omitempty"`
+	// Patterns of the images packages may not contain, checked after allow
+	Deny []string `json:"deny,omitempty"`
+	// Whether every image must be pinned by digest
+	RequireDigest bool `json:"requireDigest,omitempty"`
+	// Patterns of the images that must be pinned by digest, in addition to every image when requireDigest is set
+	RequireDigestFor []string `json:"requireDigestFor,omitempty"`
+}
+
+// LoadImagePolicy reads an image policy file.
+func LoadImagePolicy(policyPath string) (ImagePolicy, error) {
+	var policy ImagePolicy
+	b, err := os.ReadFile(policyPath)
+	if err != nil {
+		return policy, fmt.Errorf("unable to read the image policy: %w", err)
+	}
+	if err := goyaml.UnmarshalWithOptions(b, &policy, goyaml.Strict()); err != nil {
+		return policy, fmt.Errorf("unable to parse the image policy %s: %w", policyPath, err)
+	}
+	for _, pattern := range slices.Concat(policy.Allow, policy.Deny, policy.RequireDigestFor) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return policy, fmt.Errorf("invalid pattern %q in the image policy %s: %w", pattern, policyPath, err)
+		}
+	}
+	return policy, nil
+}
+
+// Check returns every violation of the policy by the images of the components, joined into a single error.
+func (p ImagePolicy) Check(components []v1alpha1.ZarfComponent) error {
+	var errs []error
+	for _, component := range components {
+		for _, image := range component.Images {
+			for _, reason := range p.violations(image) {
+				errs = append(errs, fmt.Errorf("component %q image %s %s", component.Name, image, reason))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s:\n%w", fmt.Sprintf(lang.PkgCreateErrImagePolicy, len(errs)), errors.Join(errs...))
+}
+
+// violations returns the reasons an image violates the policy.
+func (p ImagePolicy) violations(image string) []string {
+	refInfo, err := transform.ParseImageRef(image)
+	if err != nil {
+		return []string{fmt.Sprintf("is not a valid image reference: %s", err.Error())}
+	}
+	reasons := []string{}
+	if len(p.Allow) > 0 && !matchesAnyImagePattern(p.Allow, refInfo.Name) {
+		reasons = append(reasons, "is not in the allowed images")
+	}
+	if pattern, ok := firstMatchingImagePattern(p.Deny, refInfo.Name); ok {
+		reasons = append(reasons, fmt.Sprintf("is denied by %q", pattern))
+	}
+	if refInfo.Digest == "" && (p.RequireDigest || matchesAnyImagePattern(p.RequireDigestFor, refInfo.Name)) {
+		reasons = append(reasons, "must be pinned by digest")
+	}
+	return reasons
+}
+
+func matchesAnyImagePattern(patterns []string, name string) bool {
+	_, ok := firstMatchingImagePattern(patterns, name)
+	return ok
+}
+
+// firstMatchingImagePattern returns the first pattern matching the image name or one of its parent paths.
+func firstMatchingImagePattern(patterns []string, name string) (string, bool) {
+	segments := strings.Split(name, "/")
+	for _, pattern := range patterns {
+		for i := len(segments); i > 0; i-- {
+			if ok, _ := path.Match(pattern, strings.Join(segments[:i], "/")); ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
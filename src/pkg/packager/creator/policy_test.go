@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package creator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestImagePolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		policy       ImagePolicy
+		images       []string
+		expectedErrs []string
+	}{
+		{
+			name:   "empty policy",
+			policy: ImagePolicy{},
+			images: []string{"nginx:1.27", "ghcr.io/stefanprodan/podinfo:6.4.0"},
+		},
+		{
+			name:   "allowed registry and organization",
+			policy: ImagePolicy{Allow: []string{"registry1.dso.mil", "ghcr.io/zarf-dev"}},
+			images: []string{"registry1.dso.mil/ironbank/opensource/nginx:1.27", "ghcr.io/zarf-dev/zarf/agent:v0.38.0"},
+		},
+		{
+			name:   "glob patterns",
+			policy: ImagePolicy{Allow: []string{"*.dso.mil", "docker.io/library/*"}},
+			images: []string{"registry1.dso.mil/ironbank/nginx:1.27", "nginx:1.27"},
+		},
+		{
+			name:   "not allowed",
+			policy: ImagePolicy{Allow: []string{"ghcr.io/zarf-dev"}},
+			images: []string{"ghcr.io/zarf-dev/zarf/agent:v0.38.0", "ghcr.io/zarf-dev-fork/agent:v0.38.0", "nginx:1.27"},
+			expectedErrs: []string{
+				"2 image policy violation(s) found",
+				`component "test" image ghcr.io/zarf-dev-fork/agent:v0.38.0 is not in the allowed images`,
+				`component "test" image nginx:1.27 is not in the allowed images`,
+			},
+		},
+		{
+			name:   "denied",
+			policy: ImagePolicy{Allow: []string{"docker.io"}, Deny: []string{"docker.io/library/busybox"}},
+			images: []string{"busybox:1.36", "nginx:1.27"},
+			expectedErrs: []string{
+				"1 image policy violation(s) found",
+				`image busybox:1.36 is denied by "docker.io/library/busybox"`,
+			},
+		},
+		{
+			name:   "digest required",
+			policy: ImagePolicy{RequireDigest: true},
+			images: []string{"nginx:1.27", "nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"},
+			expectedErrs: []string{
+				"1 image policy violation(s) found",
+				"image nginx:1.27 must be pinned by digest",
+			},
+		},
+		{
+			name:   "digest required for some images",
+			policy: ImagePolicy{RequireDigestFor: []string{"ghcr.io"}},
+			images: []string{"nginx:1.27", "ghcr.io/stefanprodan/podinfo:6.4.0"},
+			expectedErrs: []string{
+				"image ghcr.io/stefanprodan/podinfo:6.4.0 must be pinned by digest",
+			},
+		},
+		{
+			name:   "every violation is reported",
+			policy: ImagePolicy{Deny: []string{"docker.io"}, RequireDigest: true},
+			images: []string{"nginx:1.27"},
+			expectedErrs: []string{
+				"2 image policy violation(s) found",
+				`image nginx:1.27 is denied by "docker.io"`,
+				"image nginx:1.27 must be pinned by digest",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			components := []v1alpha1.ZarfComponent{{Name: "test", Images: tt.images}}
+			err := tt.policy.Check(components)
+			if len(tt.expectedErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, expectedErr := range tt.expectedErrs {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
+
+func TestLoadImagePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contents    string
+		expected    ImagePolicy
+		expectedErr string
+	}{
+		{
+			name:     "valid policy",
+			contents: "allow:\n  - registry1.dso.mil\ndeny:\n  - docker.io\nrequireDigest: true\n",
+			expected: ImagePolicy{Allow: []string{"registry1.dso.mil"}, Deny: []string{"docker.io"}, RequireDigest: true},
+		},
+		{
+			name:        "unknown field",
+			contents:    "allowed:\n  - registry1.dso.mil\n",
+			expectedErr: "unable to parse the image policy",
+		},
+		{
+			name:        "invalid pattern",
+			contents:    "deny:\n  - \"docker.io/[\"\n",
+			expectedErr: `invalid pattern "docker.io/["`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+			require.NoError(t, os.WriteFile(policyPath, []byte(tt.contents), 0o600))
+			policy, err := LoadImagePolicy(policyPath)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, policy)
+		})
+	}
+}
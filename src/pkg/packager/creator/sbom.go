@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// GenerateSBOMs catalogs the images and component files of an assembled package into its SBOMs.
+//
+// Components listed in createOpts.SkipSBOMComponents are left out, though images they share with other components are still cataloged.
+func GenerateSBOMs(dst *layout.PackagePaths, components []v1alpha1.ZarfComponent, createOpts types.ZarfCreateOptions) error {
+	for _, name := range createOpts.SkipSBOMComponents {
+		if !slices.ContainsFunc(components, func(component v1alpha1.ZarfComponent) bool { return component.Name == name }) {
+			message.Warnf(lang.CmdPackageSBOMGenerateSkipComponentMissing, name)
+		}
+	}
+
+	componentSBOMs := map[string]*layout.ComponentSBOM{}
+	imageList := []transform.Image{}
+	for _, component := range components {
+		if slices.Contains(createOpts.SkipSBOMComponents, component.Name) {
+			message.Debugf("Skipping SBOM generation for component %q", component.Name)
+			continue
+		}
+
+		componentSBOM, err := getFilesToSBOM(component, dst)
+		if err != nil {
+			return fmt.Errorf("unable to create component SBOM: %w", err)
+		}
+		if componentSBOM != nil && len(componentSBOM.Files) > 0 {
+			componentSBOMs[component.Name] = componentSBOM
+		}
+
+		for _, src := range component.Images {
+			refInfo, err := transform.ParseImageRef(src)
+			if err != nil {
+				return fmt.Errorf("failed to create ref for image %s: %w", src, err)
+			}
+			imageList = append(imageList, refInfo)
+		}
+	}
+
+	// Only images (not other OCI artifacts) can be cataloged
+	sbomImageList := []transform.Image{}
+	for _, refInfo := range helpers.Unique(imageList) {
+		img, err := utils.LoadOCIImage(dst.Images.Base, refInfo)
+		if err != nil {
+			return err
+		}
+		ok, err := utils.OnlyHasImageLayers(img)
+		if err != nil {
+			return fmt.Errorf("failed to validate %s is an image and not an artifact: %w", refInfo.Reference, err)
+		}
+		if ok {
+			sbomImageList = append(sbomImageList, refInfo)
+		}
+	}
+
+	dst.AddSBOMs()
+	if err := sbom.Catalog(componentSBOMs, sbomImageList, dst); err != nil {
+		return fmt.Errorf("unable to create an SBOM catalog for the package: %w", err)
+	}
+
+	if createOpts.ScanVulnerabilities {
+		if err := scanVulnerabilities(dst, createOpts); err != nil {
+			return err
+		}
+	}
+
+	if createOpts.CompressSBOM {
+		if err := dst.SBOMs.Compress(); err != nil {
+			return fmt.Errorf("unable to compress the SBOMs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanVulnerabilities scans the archived SBOMs of a package for vulnerabilities and adds the report to them, failing when
+// vulnerabilities at or above createOpts.FailOnSeverity are found.
+func scanVulnerabilities(dst *layout.PackagePaths, createOpts types.ZarfCreateOptions) error {
+	if err := dst.SBOMs.Unarchive(); err != nil {
+		return err
+	}
+	report, err := sbom.ScanVulnerabilities(dst.SBOMs.Path, createOpts.VulnerabilityDBPath)
+	if err != nil {
+		return fmt.Errorf("unable to scan the SBOMs for vulnerabilities: %w", err)
+	}
+	if err := dst.SBOMs.Archive(); err != nil {
+		return err
+	}
+	sbom.PrintVulnerabilityReport(report)
+
+	if createOpts.FailOnSeverity == "" {
+		return nil
+	}
+	if count := report.AtOrAbove(createOpts.FailOnSeverity); count > 0 {
+		sbom.PrintVulnerabilities(report, createOpts.FailOnSeverity)
+		return fmt.Errorf("found %d vulnerabilities at or above the %s severity", count, createOpts.FailOnSeverity)
+	}
+	return nil
+}
+
+func getFilesToSBOM(component v1alpha1.ZarfComponent, dst *layout.PackagePaths) (*layout.ComponentSBOM, error) {
+	componentPaths, err := dst.Components.Create(component)
+	if err != nil {
+		return nil, err
+	}
+	// Create an struct to hold the SBOM information for this component.
+	componentSBOM := &layout.ComponentSBOM{
+		Files:     []string{},
+		Component: componentPaths,
+	}
+
+	appendSBOMFiles := func(path string) {
+		if helpers.IsDir(path) {
+			files, _ := helpers.RecursiveFileList(path, nil, false)
+			componentSBOM.Files = append(componentSBOM.Files, files...)
+		} else {
+			componentSBOM.Files = append(componentSBOM.Files, path)
+		}
+	}
+
+	for filesIdx, file := range component.Files {
+		path := filepath.Join(componentPaths.Files, strconv.Itoa(filesIdx), filepath.Base(file.Target))
+		appendSBOMFiles(path)
+	}
+
+	for dataIdx, data := range component.DataInjections {
+		path := filepath.Join(componentPaths.DataInjections, strconv.Itoa(dataIdx), filepath.Base(data.Target.Path))
+
+		appendSBOMFiles(path)
+	}
+
+	return componentSBOM, nil
+}
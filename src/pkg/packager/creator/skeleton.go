@@ -53,7 +53,11 @@ func (sc *SkeletonCreator) LoadPackageDefinition(ctx context.Context, src *layou
 	pkg.Metadata.Architecture = config.GetArch()
 
 	// Compose components into a single zarf.yaml file
-	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, sc.createOpts.Flavor)
+	skeletons, err := newSkeletonResolver(sc.createOpts)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, sc.createOpts.Flavor, sc.createOpts.GitCredentials, skeletons)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
@@ -6,6 +6,8 @@ package creator
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
@@ -14,6 +16,39 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
+// MergeSetVariables returns the values of the package templates, taking each from the --set and --set-file flags, the
+// environment variable the config file maps it to, or the config file, in that order of precedence. The values of
+// --set-file are read from the files at their paths.
+func MergeSetVariables(configSet, configEnv, flagSet, flagSetFile map[string]string) (map[string]string, error) {
+	setVariables := map[string]string{}
+	for key, value := range configSet {
+		setVariables[strings.ToUpper(key)] = value
+	}
+	for key, env := range configEnv {
+		// Variables that are not in the environment fall back to the config file
+		if value, ok := os.LookupEnv(env); ok {
+			setVariables[strings.ToUpper(key)] = value
+		}
+	}
+	flags := map[string]bool{}
+	for key, value := range flagSet {
+		setVariables[strings.ToUpper(key)] = value
+		flags[strings.ToUpper(key)] = true
+	}
+	for key, path := range flagSetFile {
+		key = strings.ToUpper(key)
+		if flags[key] {
+			return nil, fmt.Errorf(lang.PkgCreateErrSetFileConflict, key)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(lang.PkgCreateErrSetFile, key, err)
+		}
+		setVariables[key] = string(b)
+	}
+	return setVariables, nil
+}
+
 // FillActiveTemplate merges user-specified variables into the configuration templates of a zarf.yaml.
 func FillActiveTemplate(pkg v1alpha1.ZarfPackage, setVariables map[string]string) (v1alpha1.ZarfPackage, []string, error) {
 	templateMap := map[string]string{}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+func TestMergeSetVariables(t *testing.T) {
+	cert := "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUZ\n-----END CERTIFICATE-----\n"
+	certPath := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(certPath, []byte(cert), 0o600))
+
+	tests := []struct {
+		name        string
+		configSet   map[string]string
+		configEnv   map[string]string
+		env         map[string]string
+		flagSet     map[string]string
+		flagSetFile map[string]string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name:      "config file",
+			configSet: map[string]string{"registry": "config.example.com"},
+			configEnv: map[string]string{"registry": "ZARF_TEST_REGISTRY"},
+			expected:  map[string]string{"REGISTRY": "config.example.com"},
+		},
+		{
+			name:      "environment over the config file",
+			configSet: map[string]string{"registry": "config.example.com", "tag": "1.0.0"},
+			configEnv: map[string]string{"registry": "ZARF_TEST_REGISTRY"},
+			env:       map[string]string{"ZARF_TEST_REGISTRY": "env.example.com"},
+			expected:  map[string]string{"REGISTRY": "env.example.com", "TAG": "1.0.0"},
+		},
+		{
+			name:      "flags over the environment",
+			configEnv: map[string]string{"registry": "ZARF_TEST_REGISTRY", "ca": "ZARF_TEST_CA"},
+			env:       map[string]string{"ZARF_TEST_REGISTRY": "env.example.com", "ZARF_TEST_CA": "env"},
+			flagSet:   map[string]string{"REGISTRY": "flag.example.com"},
+			flagSetFile: map[string]string{
+				"ca": certPath,
+			},
+			expected: map[string]string{"REGISTRY": "flag.example.com", "CA": cert},
+		},
+		{
+			name:        "set and set-file",
+			flagSet:     map[string]string{"ca": "inline"},
+			flagSetFile: map[string]string{"CA": certPath},
+			expectedErr: "the package template CA is set by both --set and --set-file",
+		},
+		{
+			name:        "missing file",
+			flagSetFile: map[string]string{"script": filepath.Join(t.TempDir(), "missing.sh")},
+			expectedErr: "unable to read the file of the package template SCRIPT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			setVariables, err := MergeSetVariables(tt.configSet, tt.configEnv, tt.flagSet, tt.flagSetFile)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, setVariables)
+		})
+	}
+}
+
+func TestFillActiveTemplate(t *testing.T) {
+	confirm := config.CommonOptions.Confirm
+	config.CommonOptions.Confirm = true
+	t.Cleanup(func() {
+		config.CommonOptions.Confirm = confirm
+	})
+
+	pkg := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{Name: "test", Description: "###ZARF_PKG_TMPL_DESCRIPTION###"},
+		Components: []v1alpha1.ZarfComponent{{
+			Name: "###ZARF_PKG_TMPL_NAME###",
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Before: []v1alpha1.ZarfComponentAction{{Cmd: "###ZARF_PKG_TMPL_SCRIPT###"}},
+				},
+			},
+		}},
+	}
+	script := "#!/bin/sh\nset -e\necho \"installing\"\n\tkubectl apply -f \"$1\"\n"
+	filled, _, err := FillActiveTemplate(pkg, map[string]string{"NAME": "app", "DESCRIPTION": "a \"quoted\" description", "SCRIPT": script})
+	require.NoError(t, err)
+	require.Equal(t, "app", filled.Components[0].Name)
+	require.Equal(t, "a \"quoted\" description", filled.Metadata.Description)
+	require.Equal(t, script, filled.Components[0].Actions.OnDeploy.Before[0].Cmd)
+
+	_, _, err = FillActiveTemplate(pkg, map[string]string{"NAME": "app"})
+	require.ErrorContains(t, err, "must be '--set' when using the '--confirm' flag")
+}
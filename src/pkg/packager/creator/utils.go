@@ -5,13 +5,19 @@
 package creator
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
 	"github.com/zarf-dev/zarf/src/types"
@@ -37,6 +43,59 @@ func Validate(pkg v1alpha1.ZarfPackage, baseDir string) error {
 	return nil
 }
 
+// addOperatorCatalogImages adds the catalog image of every operator to the images of its component so it is packaged
+// and pushed to the Zarf registry with the rest of the component's images.
+func addOperatorCatalogImages(components []v1alpha1.ZarfComponent) []v1alpha1.ZarfComponent {
+	for i := range components {
+		for _, operator := range components[i].Operators {
+			if operator.CatalogImage == "" || slices.Contains(components[i].Images, operator.CatalogImage) {
+				continue
+			}
+			components[i].Images = append(components[i].Images, operator.CatalogImage)
+		}
+	}
+	return components
+}
+
+// localImageFinder lists the names of the local images with a label that matches a selector.
+type localImageFinder func(ctx context.Context, selector images.LabelSelector) ([]string, error)
+
+// resolveImageLabels replaces the image label selectors of every component with the local images that match them, so
+// images built in CI do not have to be listed by their exact tag.
+func resolveImageLabels(ctx context.Context, components []v1alpha1.ZarfComponent, find localImageFinder) ([]v1alpha1.ZarfComponent, error) {
+	isSelector := func(image string) bool {
+		return strings.HasPrefix(image, v1alpha1.ZarfImageLabelPrefix)
+	}
+	for i := range components {
+		if !slices.ContainsFunc(components[i].Images, isSelector) {
+			continue
+		}
+		resolved := []string{}
+		for _, image := range components[i].Images {
+			selector, ok := strings.CutPrefix(image, v1alpha1.ZarfImageLabelPrefix)
+			if !ok {
+				resolved = append(resolved, image)
+				continue
+			}
+			ls, err := images.ParseLabelSelector(selector)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", components[i].Name, err)
+			}
+			found, err := find(ctx, ls)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", components[i].Name, err)
+			}
+			if len(found) == 0 {
+				return nil, fmt.Errorf("component %q: no local images found with label %s", components[i].Name, ls)
+			}
+			message.Notef("Found %d local images with label %s for component %q: %s", len(found), ls, components[i].Name, strings.Join(found, ", "))
+			resolved = append(resolved, found...)
+		}
+		components[i].Images = helpers.Unique(resolved)
+	}
+	return components, nil
+}
+
 // recordPackageMetadata records various package metadata during package create.
 func recordPackageMetadata(pkg *v1alpha1.ZarfPackage, createOpts types.ZarfCreateOptions) error {
 	now := time.Now()
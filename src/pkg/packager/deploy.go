@@ -6,12 +6,14 @@ package packager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -20,17 +22,22 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/faultinject"
 	"github.com/zarf-dev/zarf/src/internal/git"
 	"github.com/zarf-dev/zarf/src/internal/gitea"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/internal/packager/plugins"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/internal/telemetry"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -55,6 +62,38 @@ func (p *Packager) resetRegistryHPA(ctx context.Context) {
 
 // Deploy attempts to deploy the given PackageConfig.
 func (p *Packager) Deploy(ctx context.Context) error {
+	if p.cfg.DeployOpts.FaultInject != "" {
+		if err := faultinject.Enable(p.cfg.DeployOpts.FaultInject); err != nil {
+			return err
+		}
+		defer faultinject.Disable()
+		message.Warnf(lang.WarnFaultInjectEnabled, p.cfg.DeployOpts.FaultInject)
+	}
+
+	if err := validateSkipPhases(p.cfg.DeployOpts.SkipPhases); err != nil {
+		return err
+	}
+	if len(p.cfg.DeployOpts.SkipPhases) > 0 {
+		message.Warnf(lang.PkgDeployWarnSkipPhases, strings.Join(p.cfg.DeployOpts.SkipPhases, ", "))
+	}
+
+	var answers types.DeployAnswers
+	if p.cfg.DeployOpts.AnswersPath != "" {
+		var err error
+		answers, err = LoadDeployAnswers(p.cfg.DeployOpts.AnswersPath)
+		if err != nil {
+			return err
+		}
+		p.applyAnswers(answers)
+	}
+
+	if err := p.loadTrustedKeys(ctx); err != nil {
+		return err
+	}
+
+	// Loading the package may replace its source with a local tarball, the source is retained as given
+	source := p.cfg.PkgOpts.PackageSource
+
 	isInteractive := !config.CommonOptions.Confirm
 
 	deployFilter := filters.Combine(
@@ -83,11 +122,18 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		}
 	}
 
+	if size, err := helpers.GetDirSize(p.layout.Base); err == nil {
+		telemetry.RecordPackageSize(size)
+	}
+
 	validateWarnings, err := validateLastNonBreakingVersion(config.CLIVersion, p.cfg.Pkg.Build.LastNonBreakingVersion)
 	if err != nil {
 		return err
 	}
 	warnings = append(warnings, validateWarnings...)
+	if answers.Package != "" && answers.Package != p.cfg.Pkg.Metadata.Name {
+		warnings = append(warnings, fmt.Sprintf(lang.PkgDeployWarnAnswersPackage, p.cfg.DeployOpts.AnswersPath, answers.Package, p.cfg.Pkg.Metadata.Name))
+	}
 
 	sbomViewFiles, sbomWarnings, err := p.layout.SBOMs.StageSBOMViewFiles()
 	if err != nil {
@@ -112,6 +158,10 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		}
 	}
 
+	if err := p.confirmPlugins(p.cfg.Pkg.Components); err != nil {
+		return err
+	}
+
 	p.hpaModified = false
 	p.connectStrings = make(types.ConnectStrings)
 	// Reset registry HPA scale down whether an error occurs or not
@@ -119,6 +169,12 @@ func (p *Packager) Deploy(ctx context.Context) error {
 
 	// Get a list of all the components we are deploying and actually deploy them
 	deployedComponents, err := p.deployComponents(ctx)
+	// The answers are exported even when the deployment fails so it can be retried with the same values
+	if p.cfg.DeployOpts.ExportAnswersPath != "" {
+		if exportErr := p.exportAnswers(); exportErr != nil {
+			err = errors.Join(err, exportErr)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -129,11 +185,27 @@ func (p *Packager) Deploy(ctx context.Context) error {
 	// Notify all the things about the successful deployment
 	message.Successf("Zarf deployment complete")
 
+	if p.cfg.Pkg.IsInitConfig() && p.isConnectedToCluster() {
+		// The health of the Zarf components is informational, so failing to record it does not fail the deployment
+		if err := p.cluster.UpdateZarfStateStatus(ctx); err != nil {
+			message.Debugf("Unable to update the status of the Zarf state: %s", err.Error())
+		}
+		// Packages recorded by earlier versions of Zarf are compressed so they can keep being updated
+		if err := p.cluster.CompactDeployedPackages(ctx); err != nil {
+			message.Warnf("Unable to compact the records of deployed packages: %s", err.Error())
+		}
+	}
+
+	p.retainInClusterCache(ctx)
+	p.retainOnHost(ctx, source)
+
 	err = p.printTablesForDeployment(ctx, deployedComponents)
 	if err != nil {
 		return err
 	}
 
+	message.PrintDeployDurationTable(deployedComponents)
+
 	return nil
 }
 
@@ -154,6 +226,19 @@ func (p *Packager) deployComponents(ctx context.Context) (deployedComponents []t
 				return nil, fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
 			}
 
+			// Check once, before the first component of the package is deployed, that no other package owns its resources
+			// and that the package a differential package was created from is deployed
+			if !p.ownershipChecked {
+				p.ownershipChecked = true
+				if err := p.checkOwnership(ctx); err != nil {
+					return nil, err
+				}
+				deployed, _ := p.cluster.GetDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name)
+				if err := validateDifferentialBase(p.cfg.Pkg, deployed); err != nil {
+					return nil, err
+				}
+			}
+
 			// If this package has been deployed before, increment the package generation within the secret
 			if existingDeployedPackage, _ := p.cluster.GetDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name); existingDeployedPackage != nil {
 				packageGeneration = existingDeployedPackage.Generation + 1
@@ -177,6 +262,11 @@ func (p *Packager) deployComponents(ctx context.Context) (deployedComponents []t
 		deployedComponents = append(deployedComponents, deployedComponent)
 		idx := len(deployedComponents) - 1
 
+		// Track how long each phase of this component's deployment takes
+		p.phaseDurations = types.PhaseDurations{}
+		p.skippedPhases = nil
+		deployStart := time.Now()
+
 		// Update the package secret to indicate that we are attempting to deploy this component
 		if p.isConnectedToCluster() {
 			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, p.connectStrings, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
@@ -196,7 +286,7 @@ func (p *Packager) deployComponents(ctx context.Context) (deployedComponents []t
 		onDeploy := component.Actions.OnDeploy
 
 		onFailure := func() {
-			if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnFailure, p.variableConfig); err != nil {
+			if err := p.runActions(ctx, onDeploy.Defaults, onDeploy.OnFailure); err != nil {
 				message.Debugf("unable to run component failure action: %s", err.Error())
 			}
 		}
@@ -205,6 +295,9 @@ func (p *Packager) deployComponents(ctx context.Context) (deployedComponents []t
 			onFailure()
 
 			// Update the package secret to indicate that we failed to deploy this component
+			p.phaseDurations.Add(types.DeployPhaseTotal, time.Since(deployStart))
+			deployedComponents[idx].PhaseDurations = p.phaseDurations
+			deployedComponents[idx].SkippedPhases = p.skippedPhases
 			deployedComponents[idx].Status = types.ComponentStatusFailed
 			if p.isConnectedToCluster() {
 				if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, p.connectStrings, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
@@ -216,18 +309,35 @@ func (p *Packager) deployComponents(ctx context.Context) (deployedComponents []t
 		}
 
 		// Update the package secret to indicate that we successfully deployed this component
-		deployedComponents[idx].InstalledCharts = charts
+		if slices.Contains(p.skippedPhases, types.DeployPhaseCharts) {
+			// The charts installed by an earlier deployment are still installed, so they are kept for package remove
+			deployedComponents[idx].InstalledCharts = mergeInstalledCharts(deployedComponents[idx].InstalledCharts, charts)
+		} else {
+			deployedComponents[idx].InstalledCharts = charts
+		}
 		deployedComponents[idx].Status = types.ComponentStatusSucceeded
+		deployedComponents[idx].PhaseDurations = p.phaseDurations
+		deployedComponents[idx].SkippedPhases = p.skippedPhases
 		if p.isConnectedToCluster() {
+			webhookStart := time.Now()
 			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, p.connectStrings, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
 				message.Debugf("Unable to record package deployment for component %q: this will affect features like `zarf package remove`: %s", component.Name, err.Error())
 			}
+			p.phaseDurations.Add(types.DeployPhaseWebhooks, time.Since(webhookStart))
 		}
 
-		if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnSuccess, p.variableConfig); err != nil {
+		if err := p.runActions(ctx, onDeploy.Defaults, onDeploy.OnSuccess); err != nil {
 			onFailure()
 			return deployedComponents, fmt.Errorf("unable to run component success action: %w", err)
 		}
+
+		// Record the final timings now that the webhooks and success actions have completed
+		p.phaseDurations.Add(types.DeployPhaseTotal, time.Since(deployStart))
+		if p.isConnectedToCluster() {
+			if _, err := p.cluster.RecordPackageDeployment(ctx, p.cfg.Pkg, deployedComponents, p.connectStrings, packageGeneration); err != nil {
+				message.Debugf("Unable to record deployment durations for component %q: %s", component.Name, err.Error())
+			}
+		}
 	}
 
 	return deployedComponents, nil
@@ -240,9 +350,13 @@ func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.Z
 	isInjector := component.Name == "zarf-injector"
 	isAgent := component.Name == "zarf-agent"
 	isK3s := component.Name == "k3s"
+	isTrustRoot := component.Name == cluster.TrustRootName
 
 	if isK3s {
 		p.cfg.InitOpts.ApplianceMode = true
+		if err := p.runHostPreflightChecks(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Always init the state before the first component that requires the cluster (on most deployments, the zarf-seed-registry)
@@ -253,6 +367,11 @@ func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.Z
 		}
 	}
 
+	// The trust root is kept in the cluster instead of being copied to the host like the files of other components
+	if isTrustRoot {
+		return nil, p.deployTrustRoot(ctx, component)
+	}
+
 	if hasExternalRegistry && (isSeedRegistry || isInjector || isRegistry) {
 		message.Notef("Not deploying the component (%s) since external registry information was provided during `zarf init`", component.Name)
 		return nil, nil
@@ -293,11 +412,17 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 
 	// All components now require a name
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
+	if message.IsVerbose(message.VerbosityComponent) {
+		if b, err := goyaml.Marshal(component); err == nil {
+			message.Debugf("Deploying the component %s:\n%s", component.Name, b)
+		}
+	}
 
-	hasImages := len(component.Images) > 0 && !noImgPush
-	hasCharts := len(component.Charts) > 0
-	hasManifests := len(component.Manifests) > 0
-	hasRepos := len(component.Repos) > 0
+	hasImages := p.runPhase(component, types.DeployPhaseImages, len(component.Images) > 0 && !noImgPush)
+	hasChartsOrManifests := p.runPhase(component, types.DeployPhaseCharts, len(component.Charts) > 0 || len(component.Manifests) > 0)
+	hasOperators := len(component.Operators) > 0
+	hasRepos := p.runPhase(component, types.DeployPhaseRepos, len(component.Repos) > 0)
+	hasArtifacts := p.runPhase(component, types.DeployPhaseArtifacts, len(component.Artifacts) > 0)
 	hasFiles := len(component.Files) > 0
 
 	onDeploy := component.Actions.OnDeploy
@@ -319,33 +444,59 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 				p.hpaModified = true
 			}
 		}
+
+		// Check the agent once, before the first images of the deployment are referenced
+		if hasImages && !p.agentChecked {
+			p.agentChecked = true
+			if err := p.checkAgentHealth(ctx); err != nil {
+				return charts, err
+			}
+		}
 	}
 
-	err = p.populateComponentAndStateTemplates(component.Name)
+	err = p.populateComponentAndStateTemplates(ctx, component.Name)
 	if err != nil {
 		return charts, err
 	}
 
-	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.Before, p.variableConfig); err != nil {
+	if err = p.runActions(ctx, onDeploy.Defaults, onDeploy.Before); err != nil {
 		return charts, fmt.Errorf("unable to run component before action: %w", err)
 	}
 
 	if hasFiles {
-		if err := p.processComponentFiles(component, componentPath.Files); err != nil {
+		start := time.Now()
+		if err := p.processComponentFiles(ctx, component, componentPath.Files); err != nil {
 			return charts, fmt.Errorf("unable to process the component files: %w", err)
 		}
+		p.phaseDurations.Add(types.DeployPhaseFiles, time.Since(start))
 	}
 
 	if hasImages {
-		if err := p.pushImagesToRegistry(ctx, component.Images, noImgChecksum); err != nil {
+		start := time.Now()
+		pushed, err := p.pushImagesToRegistry(ctx, component.Images, noImgChecksum)
+		if err != nil {
 			return charts, fmt.Errorf("unable to push images to the registry: %w", err)
 		}
+		if err := p.cluster.UpdateImageMap(ctx, p.cfg.Pkg.Metadata.Name, pushed); err != nil {
+			message.Warnf(lang.PkgDeployWarnImageMap, err.Error())
+		}
+		p.phaseDurations.Add(types.DeployPhaseImages, time.Since(start))
 	}
 
 	if hasRepos {
+		start := time.Now()
 		if err = p.pushReposToRepository(ctx, componentPath.Repos, component.Repos); err != nil {
 			return charts, fmt.Errorf("unable to push the repos to the repository: %w", err)
 		}
+		p.phaseDurations.Add(types.DeployPhaseRepos, time.Since(start))
+	}
+
+	if hasArtifacts {
+		start := time.Now()
+		if err = p.pushArtifactsToServer(ctx, componentPath.Artifacts); err != nil {
+			return charts, fmt.Errorf("unable to publish the artifacts to the artifact server: %w", err)
+		}
+		p.phaseDurations.Add(types.DeployPhaseArtifacts, time.Since(start))
 	}
 
 	g, gCtx := errgroup.WithContext(ctx)
@@ -355,25 +506,181 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 		})
 	}
 
-	if hasCharts || hasManifests {
-		if charts, err = p.installChartAndManifests(ctx, componentPath, component); err != nil {
+	if hasOperators {
+		start := time.Now()
+		if charts, err = p.installOperators(ctx, componentPath, component); err != nil {
 			return charts, err
 		}
+		p.phaseDurations.Add(types.DeployPhaseOperators, time.Since(start))
 	}
 
-	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.After, p.variableConfig); err != nil {
+	if hasChartsOrManifests {
+		start := time.Now()
+		installedCharts, err := p.installChartAndManifests(ctx, componentPath, component)
+		charts = append(charts, installedCharts...)
+		if err != nil {
+			return charts, err
+		}
+		p.phaseDurations.Add(types.DeployPhaseCharts, time.Since(start))
+	}
+
+	if len(component.Plugins) > 0 {
+		start := time.Now()
+		if err := p.runPlugins(ctx, plugins.PhaseDeploy, componentPath.Plugins, component); err != nil {
+			return charts, err
+		}
+		p.phaseDurations.Add(types.DeployPhasePlugins, time.Since(start))
+	}
+
+	if len(component.HealthChecks) > 0 {
+		start := time.Now()
+		if err := p.runHealthChecks(ctx, component.HealthChecks); err != nil {
+			return charts, fmt.Errorf("unable to run the component health checks: %w", err)
+		}
+		p.phaseDurations.Add(types.DeployPhaseHealthChecks, time.Since(start))
+	}
+
+	if err = p.runActions(ctx, onDeploy.Defaults, onDeploy.After); err != nil {
 		return charts, fmt.Errorf("unable to run component after action: %w", err)
 	}
 
+	// Only the time spent waiting on data injections after everything else has finished is recorded
+	start := time.Now()
 	err = g.Wait()
 	if err != nil {
 		return nil, err
 	}
+	if len(component.DataInjections) > 0 {
+		p.phaseDurations.Add(types.DeployPhaseDataInjections, time.Since(start))
+	}
 	return charts, nil
 }
 
+// runHealthChecks waits for the resources of the health checks of a component within the deploy timeout.
+func (p *Packager) runHealthChecks(ctx context.Context, healthChecks []v1alpha1.ZarfComponentHealthCheck) error {
+	waitCtx, cancel := context.WithTimeout(ctx, p.cfg.DeployOpts.Timeout)
+	defer cancel()
+
+	spinner := message.NewProgressSpinner("Running %d health checks", len(healthChecks))
+	defer spinner.Stop()
+
+	for _, healthCheck := range healthChecks {
+		opts := cluster.WaitOptions{
+			Kind:       healthCheck.Kind,
+			Name:       healthCheck.Name,
+			Namespace:  healthCheck.Namespace,
+			Condition:  healthCheck.Condition,
+			Expression: healthCheck.Expression,
+		}
+		if opts.Condition == "" {
+			opts.Condition = cluster.WaitConditionCurrent
+		}
+		spinner.Updatef("Waiting for %s", opts)
+		if err := p.cluster.WaitFor(waitCtx, opts); err != nil {
+			return err
+		}
+	}
+
+	spinner.Success()
+	return nil
+}
+
+// runHostPreflightChecks checks whether the host is ready to install the bundled K3s, reporting how to fix the
+// problems found.
+func (p *Packager) runHostPreflightChecks() error {
+	if p.cfg.InitOpts.SkipHostPreflight {
+		message.Warn(lang.PkgDeployWarnSkipHostPreflight)
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner(lang.PkgDeployHostPreflight)
+	checks := cluster.RunHostPreflightChecks()
+	failed := 0
+	for _, check := range checks {
+		if check.Status == cluster.HealthCheckFail {
+			failed++
+		}
+	}
+	if failed == 0 && !slices.ContainsFunc(checks, func(check cluster.HealthCheck) bool { return check.Status == cluster.HealthCheckWarn }) {
+		spinner.Success()
+		return nil
+	}
+	spinner.Stop()
+	cluster.PrintHealthChecks(checks)
+	if failed > 0 {
+		return fmt.Errorf(lang.PkgDeployErrHostPreflight, failed)
+	}
+	return nil
+}
+
+// validateSkipPhases checks that only the phases that can be skipped are.
+func validateSkipPhases(phases []string) error {
+	for _, phase := range phases {
+		if !slices.Contains(types.SkippableDeployPhases, types.DeployPhase(phase)) {
+			skippable := []string{}
+			for _, p := range types.SkippableDeployPhases {
+				skippable = append(skippable, string(p))
+			}
+			return fmt.Errorf(lang.PkgDeployErrSkipPhase, phase, strings.Join(skippable, ", "))
+		}
+	}
+	return nil
+}
+
+// validateDifferentialBase checks that the published package a differential package was created from is the deployed
+// package, as the images, repositories and layers it provides were left out of the differential package. The
+// differential package itself can be deployed again.
+func validateDifferentialBase(pkg v1alpha1.ZarfPackage, deployed *types.DeployedPackage) error {
+	if pkg.Build.DifferentialPackage == "" {
+		return nil
+	}
+	base := pkg.Build.DifferentialPackageVersion
+	if deployed == nil {
+		return fmt.Errorf(lang.PkgDeployErrDifferentialBase, base, pkg.Metadata.Name)
+	}
+	version := deployed.Data.Metadata.Version
+	if version != base && version != pkg.Metadata.Version {
+		return fmt.Errorf(lang.PkgDeployErrDifferentialVersion, base, pkg.Metadata.Name, version)
+	}
+	return nil
+}
+
+// runPhase returns whether a phase the component has work for should run, recording it as skipped when it is not.
+func (p *Packager) runPhase(component v1alpha1.ZarfComponent, phase types.DeployPhase, hasWork bool) bool {
+	if !hasWork {
+		return false
+	}
+	if !slices.Contains(p.cfg.DeployOpts.SkipPhases, string(phase)) {
+		return true
+	}
+	p.skippedPhases = append(p.skippedPhases, phase)
+	message.Warnf(lang.PkgDeploySkippedPhase, phase, component.Name)
+	return false
+}
+
+// mergeInstalledCharts adds the charts installed by this deployment to the charts installed by earlier ones.
+func mergeInstalledCharts(installed, charts []types.InstalledChart) []types.InstalledChart {
+	merged := slices.Clone(installed)
+	for _, chart := range charts {
+		if !slices.Contains(merged, chart) {
+			merged = append(merged, chart)
+		}
+	}
+	return merged
+}
+
+// runActions runs a set of component actions and records how long they took.
+func (p *Packager) runActions(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefaults, list []v1alpha1.ZarfComponentAction) error {
+	if len(list) > 0 {
+		defer func(start time.Time) {
+			p.phaseDurations.Add(types.DeployPhaseActions, time.Since(start))
+		}(time.Now())
+	}
+	return actions.Run(ctx, defaultCfg, list, p.variableConfig)
+}
+
 // Move files onto the host of the machine performing the deployment.
-func (p *Packager) processComponentFiles(component v1alpha1.ZarfComponent, pkgLocation string) error {
+func (p *Packager) processComponentFiles(ctx context.Context, component v1alpha1.ZarfComponent, pkgLocation string) error {
 	spinner := message.NewProgressSpinner("Copying %d files", len(component.Files))
 	defer spinner.Stop()
 
@@ -427,6 +734,9 @@ func (p *Packager) processComponentFiles(component v1alpha1.ZarfComponent, pkgLo
 		if err != nil {
 			return fmt.Errorf("unable to copy file %s to %s: %w", fileLocation, file.Target, err)
 		}
+		if err := setFilePermissions(file, file.Target); err != nil {
+			return err
+		}
 
 		// Loop over all symlinks and create them
 		for _, link := range file.Symlinks {
@@ -446,11 +756,74 @@ func (p *Packager) processComponentFiles(component v1alpha1.ZarfComponent, pkgLo
 		_ = os.RemoveAll(fileLocation)
 	}
 
+	if err := installSystemdUnits(ctx, component.Files); err != nil {
+		return err
+	}
+
 	spinner.Success()
 
 	return nil
 }
 
+// checkAgentHealth warns when the Zarf agent would not rewrite the image references of the package, failing instead when
+// the package or the deployment requires the agent.
+func (p *Packager) checkAgentHealth(ctx context.Context) error {
+	if p.cfg.Pkg.IsInitConfig() || p.cfg.Pkg.Metadata.YOLO {
+		return nil
+	}
+	err := p.cluster.CheckAgentHealth(ctx)
+	if err == nil {
+		return nil
+	}
+	if p.cfg.Pkg.Metadata.RequireAgent || p.cfg.DeployOpts.RequireAgent {
+		return fmt.Errorf(lang.PkgDeployErrAgentUnhealthy, err)
+	}
+	message.Warnf(lang.PkgDeployWarnAgentUnhealthy, err)
+	return nil
+}
+
+// checkOwnership fails the deployment when the package would take over Helm releases or namespaces owned by another
+// deployed package, only warning when sharing them is allowed.
+func (p *Packager) checkOwnership(ctx context.Context) error {
+	releases := []types.InstalledChart{}
+	namespaces := []string{}
+	for _, component := range p.cfg.Pkg.Components {
+		for _, chart := range component.Charts {
+			releaseName := chart.ReleaseName
+			if releaseName == "" {
+				releaseName = chart.Name
+			}
+			releases = append(releases, types.InstalledChart{Namespace: chart.Namespace, ChartName: releaseName})
+			namespaces = append(namespaces, chart.Namespace)
+		}
+		for _, manifest := range component.Manifests {
+			namespaces = append(namespaces, manifest.Namespace)
+		}
+		for _, operator := range component.Operators {
+			namespaces = append(namespaces, operator.Namespace)
+		}
+	}
+	slices.Sort(namespaces)
+	namespaces = slices.Compact(namespaces)
+
+	conflicts, err := p.cluster.FindOwnershipConflicts(ctx, p.cfg.Pkg.Metadata.Name, releases, namespaces)
+	if err != nil {
+		return fmt.Errorf("unable to check the ownership of the Helm releases and namespaces of the package: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	owned := []string{}
+	for _, conflict := range conflicts {
+		owned = append(owned, conflict.String())
+	}
+	if p.cfg.DeployOpts.AllowShared {
+		message.Warnf(lang.PkgDeployWarnShared, strings.Join(owned, ", "))
+		return nil
+	}
+	return fmt.Errorf(lang.PkgDeployErrOwnership, strings.Join(owned, ", "))
+}
+
 // setupState fetches the current ZarfState from the k8s cluster and sets the packager to use it
 func (p *Packager) setupState(ctx context.Context) (err error) {
 	// If we are touching K8s, make sure we can talk to it once per deployment
@@ -505,8 +878,17 @@ func (p *Packager) setupState(ctx context.Context) (err error) {
 	return nil
 }
 
-func (p *Packager) populateComponentAndStateTemplates(componentName string) error {
-	applicationTemplates, err := template.GetZarfTemplates(componentName, p.state)
+func (p *Packager) populateComponentAndStateTemplates(ctx context.Context, componentName string) error {
+	// Cluster facts are looked up once per deployment
+	if p.clusterFacts == nil && p.isConnectedToCluster() {
+		facts, err := p.cluster.GetClusterFacts(ctx)
+		if err != nil {
+			message.Warnf("Unable to look up some cluster facts, their ###ZARF_CLUSTER_*### values will not be templated: %s", err.Error())
+		}
+		p.clusterFacts = &facts
+	}
+
+	applicationTemplates, err := template.GetZarfTemplates(componentName, p.state, p.clusterFacts)
 	if err != nil {
 		return err
 	}
@@ -519,13 +901,14 @@ func (p *Packager) populatePackageVariableConfig() error {
 	return p.variableConfig.PopulateVariables(p.cfg.Pkg.Variables, p.cfg.PkgOpts.SetVariables)
 }
 
-// Push all of the components images to the configured container registry.
-func (p *Packager) pushImagesToRegistry(ctx context.Context, componentImages []string, noImgChecksum bool) error {
+// Push all of the components images to the configured container registry, returning their references by digest in
+// the registry keyed by their original references.
+func (p *Packager) pushImagesToRegistry(ctx context.Context, componentImages []string, noImgChecksum bool) (map[string]string, error) {
 	var combinedImageList []transform.Image
 	for _, src := range componentImages {
 		ref, err := transform.ParseImageRef(src)
 		if err != nil {
-			return fmt.Errorf("failed to create ref for image %s: %w", src, err)
+			return nil, fmt.Errorf("failed to create ref for image %s: %w", src, err)
 		}
 		combinedImageList = append(combinedImageList, ref)
 	}
@@ -539,6 +922,7 @@ func (p *Packager) pushImagesToRegistry(ctx context.Context, componentImages []s
 		NoChecksum:      noImgChecksum,
 		Arch:            p.cfg.Pkg.Build.Architecture,
 		Retries:         p.cfg.PkgOpts.Retries,
+		Concurrency:     config.CommonOptions.ImageConcurrency,
 	}
 
 	return images.Push(ctx, pushCfg)
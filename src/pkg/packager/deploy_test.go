@@ -272,3 +272,123 @@ func TestServiceInfoFromServiceURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSkipPhases(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		phases      []string
+		expectedErr string
+	}{
+		{
+			name: "no phases",
+		},
+		{
+			name:   "skippable phases",
+			phases: []string{"images", "repos", "charts"},
+		},
+		{
+			name:        "phase that can not be skipped",
+			phases:      []string{"images", "actions"},
+			expectedErr: `unable to skip the "actions" deploy phase, only images, repos, artifacts, charts can be skipped`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateSkipPhases(tt.phases)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRunPhase(t *testing.T) {
+	t.Parallel()
+
+	p := &Packager{cfg: &types.PackagerConfig{DeployOpts: types.ZarfDeployOptions{SkipPhases: []string{"images", "charts"}}}}
+	component := v1alpha1.ZarfComponent{Name: "test"}
+
+	require.False(t, p.runPhase(component, types.DeployPhaseImages, true))
+	require.False(t, p.runPhase(component, types.DeployPhaseCharts, false))
+	require.True(t, p.runPhase(component, types.DeployPhaseRepos, true))
+	// Only phases the component has work for are recorded as skipped
+	require.Equal(t, []types.DeployPhase{types.DeployPhaseImages}, p.skippedPhases)
+}
+
+func TestMergeInstalledCharts(t *testing.T) {
+	t.Parallel()
+
+	installed := []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}}
+	charts := []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}, {Namespace: "operators", ChartName: "operator"}}
+	merged := mergeInstalledCharts(installed, charts)
+	require.Equal(t, []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}, {Namespace: "operators", ChartName: "operator"}}, merged)
+	require.Len(t, installed, 1)
+}
+
+func TestValidateDifferentialBase(t *testing.T) {
+	t.Parallel()
+
+	differential := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{Name: "podinfo", Version: "1.1.0"},
+		Build: v1alpha1.ZarfBuildData{
+			Differential:               true,
+			DifferentialPackage:        "oci://ghcr.io/zarf-dev/packages/podinfo:1.0.0",
+			DifferentialPackageVersion: "1.0.0",
+		},
+	}
+	deployed := func(version string) *types.DeployedPackage {
+		return &types.DeployedPackage{Data: v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo", Version: version}}}
+	}
+
+	tests := []struct {
+		name        string
+		pkg         v1alpha1.ZarfPackage
+		deployed    *types.DeployedPackage
+		expectedErr string
+	}{
+		{
+			name: "package not created from a published package",
+			pkg:  v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo", Version: "1.1.0"}},
+		},
+		{
+			name:        "base not deployed",
+			pkg:         differential,
+			expectedErr: "this differential package requires version 1.0.0 of \"podinfo\" to be deployed first",
+		},
+		{
+			name:     "base deployed",
+			pkg:      differential,
+			deployed: deployed("1.0.0"),
+		},
+		{
+			name:     "package deployed again",
+			pkg:      differential,
+			deployed: deployed("1.1.0"),
+		},
+		{
+			name:        "other version deployed",
+			pkg:         differential,
+			deployed:    deployed("0.9.0"),
+			expectedErr: "this differential package requires version 1.0.0 of \"podinfo\" to be deployed first, version 0.9.0 is deployed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateDifferentialBase(tt.pkg, tt.deployed)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
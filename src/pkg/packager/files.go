@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+)
+
+// setFilePermissions sets the mode and ownership of a file deployed to target, the mode is set on each file within a
+// folder and the ownership on the folder and everything within it.
+func setFilePermissions(file v1alpha1.ZarfFile, target string) error {
+	if file.Mode != "" {
+		mode, err := strconv.ParseUint(file.Mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid mode %q for %s: %w", file.Mode, target, err)
+		}
+		err = filepath.WalkDir(target, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Folders keep their mode so their files stay reachable
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return os.Chmod(path, fs.FileMode(mode))
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set the mode of %s: %w", target, err)
+		}
+	}
+
+	if file.UID != nil || file.GID != nil {
+		uid, gid := -1, -1
+		if file.UID != nil {
+			uid = *file.UID
+		}
+		if file.GID != nil {
+			gid = *file.GID
+		}
+		err := filepath.WalkDir(target, func(path string, _ fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Lchown(path, uid, gid)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set the owner of %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// systemdCommands returns the systemctl arguments that install the systemd units of files, reloading the systemd
+// manager configuration once before enabling and starting the units.
+func systemdCommands(files []v1alpha1.ZarfFile) [][]string {
+	cmds := [][]string{}
+	for _, file := range files {
+		if !file.Systemd.IsUnit() {
+			continue
+		}
+		if len(cmds) == 0 {
+			cmds = append(cmds, []string{"daemon-reload"})
+		}
+		unit := filepath.Base(file.Target)
+		if file.Systemd.Enable {
+			cmds = append(cmds, []string{"enable", unit})
+		}
+		if file.Systemd.Start {
+			cmds = append(cmds, []string{"restart", unit})
+		}
+	}
+	return cmds
+}
+
+// installSystemdUnits installs the systemd units of files with systemctl.
+func installSystemdUnits(ctx context.Context, files []v1alpha1.ZarfFile) error {
+	cmds := systemdCommands(files)
+	if len(cmds) == 0 {
+		return nil
+	}
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("systemd units can only be installed on linux, not %s", runtime.GOOS)
+	}
+	execCfg := exec.Config{
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	for _, args := range cmds {
+		message.Debugf("Running systemctl %v", args)
+		if _, errOut, err := exec.CmdWithContext(ctx, execCfg, "systemctl", args...); err != nil {
+			return fmt.Errorf("unable to run systemctl %v: %s: %w", args, errOut, err)
+		}
+	}
+	return nil
+}
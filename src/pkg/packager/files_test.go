@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestSetFilePermissions(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and owners are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(target, "conf"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "app.sh"), []byte("#!/bin/sh"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "conf", "app.conf"), []byte("key=value"), 0o600))
+
+	uid, gid := os.Getuid(), os.Getgid()
+	err := setFilePermissions(v1alpha1.ZarfFile{Mode: "0750", UID: &uid, GID: &gid}, target)
+	require.NoError(t, err)
+
+	for _, path := range []string{"app.sh", filepath.Join("conf", "app.conf")} {
+		fi, err := os.Stat(filepath.Join(target, path))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o750), fi.Mode().Perm())
+	}
+	// Folders keep their mode
+	fi, err := os.Stat(filepath.Join(target, "conf"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+	err = setFilePermissions(v1alpha1.ZarfFile{Mode: "rwx"}, target)
+	require.ErrorContains(t, err, "invalid mode \"rwx\"")
+}
+
+func TestSystemdCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		files    []v1alpha1.ZarfFile
+		expected [][]string
+	}{
+		{
+			name:     "no units",
+			files:    []v1alpha1.ZarfFile{{Target: "/usr/local/bin/app"}},
+			expected: [][]string{},
+		},
+		{
+			name: "units",
+			files: []v1alpha1.ZarfFile{
+				{Target: "/usr/local/bin/app"},
+				{Target: "/etc/systemd/system/app.service", Systemd: v1alpha1.ZarfFileSystemd{Enable: true, Start: true}},
+				{Target: "/etc/systemd/system/app-backup.timer", Systemd: v1alpha1.ZarfFileSystemd{Enable: true}},
+				{Target: "/etc/systemd/system/app-backup.service", Systemd: v1alpha1.ZarfFileSystemd{Install: true}},
+			},
+			expected: [][]string{
+				{"daemon-reload"},
+				{"enable", "app.service"},
+				{"restart", "app.service"},
+				{"enable", "app-backup.timer"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, systemdCommands(tt.files))
+		})
+	}
+}
@@ -63,7 +63,7 @@ func (p *Packager) Generate(ctx context.Context) (err error) {
 		},
 	}
 
-	images, err := p.findImages(ctx)
+	images, _, err := p.findImages(ctx)
 	if err != nil {
 		// purposefully not returning error here, as we can still generate the package without images
 		message.Warnf("Unable to find images: %s", err.Error())
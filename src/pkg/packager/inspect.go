@@ -8,9 +8,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
 	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
@@ -55,3 +60,51 @@ func (p *Packager) Inspect(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// InspectLayers reports how image layers are shared across the images in a package.
+func (p *Packager) InspectLayers(ctx context.Context) (err error) {
+	p.cfg.Pkg, _, err = p.source.LoadPackage(ctx, p.layout, filters.Empty(), false)
+	if err != nil {
+		return fmt.Errorf("unable to load the package: %w", err)
+	}
+
+	if helpers.InvalidPath(p.layout.Images.Index) {
+		message.Note(lang.CmdPackageInspectLayersNoImages)
+		return nil
+	}
+
+	report, err := images.AnalyzeLayers(p.layout.Images.Base)
+	if err != nil {
+		return err
+	}
+
+	message.Notef(lang.CmdPackageInspectLayersTotal, len(report.Images), utils.ByteFormat(float64(report.TotalSize), 2))
+
+	imageRows := [][]string{}
+	for _, image := range report.Images {
+		imageRows = append(imageRows, []string{
+			image.Reference,
+			utils.ByteFormat(float64(image.UniqueSize), 2),
+			utils.ByteFormat(float64(image.TotalSize), 2),
+		})
+	}
+	message.Table([]string{"Image", "Unique Size", "Total Size"}, imageRows)
+
+	if len(report.SharedLayers) > 0 {
+		layerRows := [][]string{}
+		for _, layer := range report.SharedLayers {
+			layerRows = append(layerRows, []string{
+				layer.Digest,
+				utils.ByteFormat(float64(layer.Size), 2),
+				strings.Join(layer.Images, "\n"),
+			})
+		}
+		message.Table([]string{"Shared Layer", "Size", "Images"}, layerRows)
+	}
+
+	for _, hint := range report.Hints {
+		message.Warn(hint)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+)
+
+// Lock resolves the images of a package definition to digests and writes them to its zarf-lock.yaml, and the skeleton
+// packages its components are imported from to its zarf-compose-lock.yaml.
+func (p *Packager) Lock(ctx context.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Return to the original working directory
+		if err := os.Chdir(cwd); err != nil {
+			message.Warnf("Unable to return to the original working directory: %s", err.Error())
+		}
+	}()
+	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
+		return fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
+	}
+	message.Note(fmt.Sprintf("Using build directory %s", p.cfg.CreateOpts.BaseDir))
+
+	// The skeleton packages are resolved again rather than taken from the compose lockfile that is being updated
+	createOpts := p.cfg.CreateOpts
+	createOpts.IgnoreComposeLock = true
+	c := creator.NewPackageCreator(createOpts, cwd)
+
+	if err := helpers.CreatePathAndCopy(layout.ZarfYAML, p.layout.ZarfYAML); err != nil {
+		return err
+	}
+
+	pkg, warnings, err := c.LoadPackageDefinition(ctx, p.layout)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		message.Warn(warning)
+	}
+	p.cfg.Pkg = pkg
+
+	spinner := message.NewProgressSpinner("Resolving the digests of the package images")
+	defer spinner.Stop()
+
+	lock, err := creator.NewImageLock(pkg.Components, func(image string) (string, error) {
+		spinner.Updatef("Resolving the digest of %s", image)
+		desc, err := images.Head(image, p.cfg.CreateOpts.RegistryMirrors)
+		if err != nil {
+			return "", err
+		}
+		return desc.Digest.String(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := lock.Write(layout.ZarfLockYAML); err != nil {
+		return err
+	}
+
+	spinner.Successf("Locked %d images in %s", len(lock.Images), filepath.Join(p.cfg.CreateOpts.BaseDir, layout.ZarfLockYAML))
+
+	composeLock := c.ComposeLock()
+	if len(composeLock.Skeletons) == 0 {
+		// Remove the lock of skeleton imports that were since removed from the package definition
+		if err := os.Remove(layout.ZarfComposeLockYAML); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := composeLock.Write(layout.ZarfComposeLockYAML); err != nil {
+		return err
+	}
+	message.Successf("Locked %d skeleton packages in %s", len(composeLock.Skeletons), filepath.Join(p.cfg.CreateOpts.BaseDir, layout.ZarfComposeLockYAML))
+	return nil
+}
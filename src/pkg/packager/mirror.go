@@ -65,7 +65,7 @@ func (p *Packager) mirrorComponent(ctx context.Context, component v1alpha1.ZarfC
 	hasRepos := len(component.Repos) > 0
 
 	if hasImages {
-		if err := p.pushImagesToRegistry(ctx, component.Images, p.cfg.MirrorOpts.NoImgChecksum); err != nil {
+		if _, err := p.pushImagesToRegistry(ctx, component.Images, p.cfg.MirrorOpts.NoImgChecksum); err != nil {
 			return fmt.Errorf("unable to push images to the registry: %w", err)
 		}
 	}
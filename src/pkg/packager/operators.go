@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// installOperators installs the operators of a component from their OLM catalogs and waits for them to be installed.
+//
+// The OLM resources of each operator are installed as a Zarf manifest chart so they are upgraded and removed with the
+// package, the catalog image is rewritten to the Zarf registry by the agent.
+func (p *Packager) installOperators(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) (installedCharts []types.InstalledChart, err error) {
+	manifestsPath := filepath.Join(componentPaths.Temp, "operators")
+	if err := helpers.CreateDirectory(manifestsPath, helpers.ReadWriteExecuteUser); err != nil {
+		return installedCharts, err
+	}
+
+	for _, operator := range component.Operators {
+		groups, err := p.cluster.OperatorGroups(ctx, operator.Namespace)
+		if err != nil {
+			return installedCharts, err
+		}
+		// Only create an operator group when the namespace has none or the one there was created for this operator
+		withOperatorGroup := len(groups) == 0 || slices.Equal(groups, []string{operator.Name})
+
+		b, err := operatorManifests(operator, withOperatorGroup)
+		if err != nil {
+			return installedCharts, err
+		}
+		manifest := v1alpha1.ZarfManifest{
+			Name:      fmt.Sprintf("operator-%s", operator.Name),
+			Namespace: operator.Namespace,
+			Files:     []string{fmt.Sprintf("operator-%s.yaml", operator.Name)},
+			NoWait:    true,
+		}
+		if err := os.WriteFile(filepath.Join(manifestsPath, manifest.Files[0]), b, helpers.ReadWriteUser); err != nil {
+			return installedCharts, err
+		}
+
+		helmCfg, err := helm.NewFromZarfManifest(
+			manifest,
+			manifestsPath,
+			p.cfg.Pkg.Metadata.Name,
+			component.Name,
+			helm.WithDeployInfo(
+				p.cfg,
+				p.variableConfig,
+				p.state,
+				p.cluster,
+				nil,
+				p.cfg.DeployOpts.Timeout,
+				p.cfg.PkgOpts.Retries),
+		)
+		if err != nil {
+			return installedCharts, err
+		}
+		_, installedChartName, err := helmCfg.InstallOrUpgradeChart(ctx)
+		if err != nil {
+			return installedCharts, err
+		}
+		installedCharts = append(installedCharts, types.InstalledChart{Namespace: manifest.Namespace, ChartName: installedChartName})
+
+		if operator.NoWait {
+			continue
+		}
+		if err := p.cluster.WaitForOperator(ctx, operator, p.cfg.DeployOpts.Timeout); err != nil {
+			return installedCharts, err
+		}
+	}
+
+	return installedCharts, nil
+}
+
+// operatorManifests returns the OLM resources that install an operator: an operator group if requested, a catalog source
+// serving its catalog image and a subscription to it, all named after the operator.
+func operatorManifests(operator v1alpha1.ZarfOperator, withOperatorGroup bool) ([]byte, error) {
+	objects := []map[string]any{}
+	if withOperatorGroup {
+		spec := map[string]any{}
+		if len(operator.TargetNamespaces) > 0 {
+			spec["targetNamespaces"] = operator.TargetNamespaces
+		}
+		objects = append(objects, olmObject(cluster.OperatorGroupGVR.GroupVersion().String(), "OperatorGroup", operator, spec))
+	}
+	objects = append(objects, olmObject(cluster.CatalogSourceGVR.GroupVersion().String(), "CatalogSource", operator, map[string]any{
+		"sourceType":  "grpc",
+		"image":       operator.CatalogImage,
+		"displayName": fmt.Sprintf("Zarf catalog for %s", operator.Name),
+		"publisher":   "Zarf",
+	}))
+	subscription := map[string]any{
+		"name":                operator.Name,
+		"source":              operator.Name,
+		"sourceNamespace":     operator.Namespace,
+		"installPlanApproval": "Automatic",
+	}
+	if operator.Channel != "" {
+		subscription["channel"] = operator.Channel
+	}
+	if operator.StartingCSV != "" {
+		subscription["startingCSV"] = operator.StartingCSV
+	}
+	objects = append(objects, olmObject(cluster.SubscriptionGVR.GroupVersion().String(), "Subscription", operator, subscription))
+
+	var buf bytes.Buffer
+	for _, obj := range objects {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+func olmObject(apiVersion, kind string, operator v1alpha1.ZarfOperator, spec map[string]any) map[string]any {
+	return map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":      operator.Name,
+			"namespace": operator.Namespace,
+		},
+		"spec": spec,
+	}
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestOperatorManifests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		operator          v1alpha1.ZarfOperator
+		withOperatorGroup bool
+		expected          string
+	}{
+		{
+			name: "all namespaces",
+			operator: v1alpha1.ZarfOperator{
+				Name:         "etcd",
+				Namespace:    "operators",
+				CatalogImage: "quay.io/operatorhubio/catalog:latest",
+			},
+			withOperatorGroup: true,
+			expected: `---
+apiVersion: operators.coreos.com/v1
+kind: OperatorGroup
+metadata:
+  name: etcd
+  namespace: operators
+spec: {}
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  displayName: Zarf catalog for etcd
+  image: quay.io/operatorhubio/catalog:latest
+  publisher: Zarf
+  sourceType: grpc
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  installPlanApproval: Automatic
+  name: etcd
+  source: etcd
+  sourceNamespace: operators
+`,
+		},
+		{
+			name: "existing operator group",
+			operator: v1alpha1.ZarfOperator{
+				Name:             "etcd",
+				Namespace:        "operators",
+				CatalogImage:     "quay.io/operatorhubio/catalog:latest",
+				Channel:          "singlenamespace-alpha",
+				StartingCSV:      "etcdoperator.v0.9.4",
+				TargetNamespaces: []string{"operators"},
+			},
+			withOperatorGroup: false,
+			expected: `---
+apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  displayName: Zarf catalog for etcd
+  image: quay.io/operatorhubio/catalog:latest
+  publisher: Zarf
+  sourceType: grpc
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  channel: singlenamespace-alpha
+  installPlanApproval: Automatic
+  name: etcd
+  source: etcd
+  sourceNamespace: operators
+  startingCSV: etcdoperator.v0.9.4
+`,
+		},
+		{
+			name: "target namespaces",
+			operator: v1alpha1.ZarfOperator{
+				Name:             "etcd",
+				Namespace:        "operators",
+				CatalogImage:     "quay.io/operatorhubio/catalog:latest",
+				TargetNamespaces: []string{"operators", "apps"},
+			},
+			withOperatorGroup: true,
+			expected: `---
+apiVersion: operators.coreos.com/v1
+kind: OperatorGroup
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  targetNamespaces:
+  - operators
+  - apps
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: CatalogSource
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  displayName: Zarf catalog for etcd
+  image: quay.io/operatorhubio/catalog:latest
+  publisher: Zarf
+  sourceType: grpc
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: etcd
+  namespace: operators
+spec:
+  installPlanApproval: Automatic
+  name: etcd
+  source: etcd
+  sourceNamespace: operators
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b, err := operatorManifests(tt.operator, tt.withOperatorGroup)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(b))
+		})
+	}
+}
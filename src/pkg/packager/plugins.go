@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/pterm/pterm"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/packager/plugins"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// confirmPlugins lists the plugins of the components and asks whether to run them, unless they were allowed with
+// --allow-plugins. The package chooses the executables, which run with the permissions of the user.
+func (p *Packager) confirmPlugins(components []v1alpha1.ZarfComponent) error {
+	names := []string{}
+	rows := [][]string{}
+	for _, component := range components {
+		for _, plugin := range component.Plugins {
+			executable, err := plugins.Executable(plugin.Name)
+			if err != nil {
+				executable = "not installed"
+			}
+			clusterAccess := "no"
+			if plugin.RequiresCluster {
+				clusterAccess = "yes"
+			}
+			names = append(names, plugin.Name)
+			rows = append(rows, []string{component.Name, plugin.Name, executable, clusterAccess})
+		}
+	}
+	if len(names) == 0 || p.cfg.PkgOpts.AllowPlugins {
+		return nil
+	}
+
+	message.HorizontalRule()
+	message.Title(lang.PkgPluginsTitle, lang.PkgPluginsHelp)
+	message.Table([]string{"Component", "Plugin", "Executable", "Cluster Access"}, rows)
+	if config.CommonOptions.Confirm {
+		return fmt.Errorf(lang.PkgPluginsErrNotAllowed, strings.Join(names, ", "))
+	}
+
+	pterm.Println()
+	confirm := false
+	prompt := &survey.Confirm{
+		Message: lang.PkgPluginsPrompt,
+	}
+	if err := survey.AskOne(prompt, &confirm); err != nil || !confirm {
+		return errors.New(lang.PkgPluginsErrDeclined)
+	}
+	return nil
+}
+
+// runPlugins runs the plugins of a component for the deploy or remove phase.
+//
+// On deploy each plugin is given the directory it packaged on create, on remove the package is loaded from the cluster
+// so there is no directory.
+func (p *Packager) runPlugins(ctx context.Context, phase plugins.Phase, pluginsPath string, component v1alpha1.ZarfComponent) error {
+	for pluginIdx, plugin := range component.Plugins {
+		req := plugins.Request{
+			Phase:        phase,
+			Package:      p.cfg.Pkg.Metadata.Name,
+			Component:    component.Name,
+			Architecture: p.cfg.Pkg.Metadata.Architecture,
+			Variables:    p.variableConfig.GetSetVariableValues(),
+		}
+		if pluginsPath != "" {
+			req.Dir = filepath.Join(pluginsPath, strconv.Itoa(pluginIdx))
+			// Plugins that packaged nothing may have no directory in the package
+			if err := helpers.CreateDirectory(req.Dir, helpers.ReadWriteExecuteUser); err != nil {
+				return err
+			}
+		}
+		if plugin.RequiresCluster {
+			if p.state == nil && p.cluster != nil {
+				state, err := p.cluster.LoadZarfState(ctx)
+				if err != nil {
+					return err
+				}
+				p.state = state
+			}
+			kubeContext, err := cluster.CurrentContext("", "")
+			if err != nil {
+				return err
+			}
+			req.Cluster = plugins.NewClusterInfo(kubeContext, p.state)
+		}
+		if err := plugins.Run(ctx, plugin, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
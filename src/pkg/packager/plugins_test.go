@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestConfirmPlugins(t *testing.T) {
+	// The confirm option is global, so the test cases are not run in parallel
+	confirm := config.CommonOptions.Confirm
+	t.Cleanup(func() {
+		config.CommonOptions.Confirm = confirm
+	})
+	config.CommonOptions.Confirm = true
+
+	components := []v1alpha1.ZarfComponent{
+		{Name: "database", Plugins: []v1alpha1.ZarfComponentPlugin{{Name: "database-dump", RequiresCluster: true}}},
+		{Name: "vm", Plugins: []v1alpha1.ZarfComponentPlugin{{Name: "vm-image"}}},
+		{Name: "manifests"},
+	}
+
+	tests := []struct {
+		name          string
+		components    []v1alpha1.ZarfComponent
+		allowPlugins  bool
+		expectedError string
+	}{
+		{
+			name:       "no plugins",
+			components: components[2:],
+		},
+		{
+			name:          "plugins not allowed",
+			components:    components,
+			expectedError: "the package runs the plugins database-dump, vm-image, review them with 'zarf package inspect' and allow them with --allow-plugins",
+		},
+		{
+			name:         "plugins allowed",
+			components:   components,
+			allowPlugins: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Packager{cfg: &types.PackagerConfig{PkgOpts: types.ZarfPackageOptions{AllowPlugins: tt.allowPlugins}}}
+			err := p.confirmPlugins(tt.components)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -11,12 +11,14 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/goccy/go-yaml"
-	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 	v1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +33,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -70,11 +73,132 @@ func (p *Packager) FindImages(ctx context.Context) (map[string][]string, error)
 	}
 	p.cfg.Pkg = pkg
 
-	return p.findImages(ctx)
+	imagesMap, confirmedImages, err := p.findImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.cfg.FindImagesOpts.Update && imagesMap != nil {
+		if err := p.updatePackageDefinitionImages(layout.ZarfYAML, confirmedImages); err != nil {
+			return nil, fmt.Errorf("unable to update the images in %s: %w", layout.ZarfYAML, err)
+		}
+	}
+
+	return imagesMap, nil
+}
+
+// FindClusterImages lists the unique images running in the namespaces of the cluster pinned to their digests, and
+// prints them as the images of a component to snapshot the cluster into a package.
+func (p *Packager) FindClusterImages(ctx context.Context) ([]string, error) {
+	if err := p.connectToCluster(ctx); err != nil {
+		return nil, err
+	}
+
+	spinner := message.NewProgressSpinner(lang.PkgFindClusterImages)
+	defer spinner.Stop()
+	images, err := p.cluster.ListRunningImages(ctx, p.cfg.FindImagesOpts.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	spinner.Successf(lang.PkgFindClusterImagesFound, len(images))
+
+	namespaces := "all namespaces"
+	if len(p.cfg.FindImagesOpts.Namespaces) > 0 {
+		namespaces = "namespaces " + strings.Join(p.cfg.FindImagesOpts.Namespaces, ", ")
+	}
+	definition := fmt.Sprintf("\n    # Images running in %s\n    images:\n", namespaces)
+	for _, image := range images {
+		definition += fmt.Sprintf("      - %s\n", image)
+	}
+	fmt.Println(definition)
+	return images, nil
+}
+
+// updatePackageDefinitionImages writes the confirmed images of each component that are not yet listed in the package
+// back into its zarf.yaml, pinned to the digests their tags currently resolve to. Images that may not be images and the
+// cosign artifacts of the images are only printed.
+func (p *Packager) updatePackageDefinitionImages(path string, confirmedImages map[string][]string) error {
+	newImages := map[string][]string{}
+	count := 0
+	for _, component := range p.cfg.Pkg.Components {
+		for _, image := range confirmedImages[component.Name] {
+			if isImageListed(component.Images, image) || slices.Contains(newImages[component.Name], image) {
+				continue
+			}
+			pinned, err := p.pinImage(image)
+			if err != nil {
+				message.Warnf("Not adding the image %s to %s, unable to resolve its digest: %s", image, path, err.Error())
+				continue
+			}
+			newImages[component.Name] = append(newImages[component.Name], pinned)
+			count++
+		}
+	}
+	if count == 0 {
+		message.Note(fmt.Sprintf("No new images found, %s is already up to date", path))
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	b, err = updateImagesInDefinition(b, p.cfg.CreateOpts.Flavor, newImages)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, fi.Mode()); err != nil {
+		return err
+	}
+	message.Successf("Added %d new image(s) to %s", count, path)
+	return nil
+}
+
+// pinImage returns the reference of an image pinned to the digest it currently resolves to.
+func (p *Packager) pinImage(image string) (string, error) {
+	refInfo, err := transform.ParseImageRef(image)
+	if err != nil {
+		return "", err
+	}
+	if refInfo.Digest != "" {
+		return image, nil
+	}
+	desc, err := images.Head(image, p.cfg.FindImagesOpts.RegistryMirrors)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s@%s", image, desc.Digest), nil
+}
+
+// isImageListed returns whether an image is in a list of images, including when it is listed pinned to a digest.
+func isImageListed(listed []string, image string) bool {
+	refInfo, err := transform.ParseImageRef(image)
+	if err != nil {
+		return slices.Contains(listed, image)
+	}
+	for _, l := range listed {
+		listedInfo, err := transform.ParseImageRef(l)
+		if err != nil {
+			continue
+		}
+		if listedInfo.Name != refInfo.Name || listedInfo.Tag != refInfo.Tag {
+			continue
+		}
+		if refInfo.Digest == "" || listedInfo.Digest == refInfo.Digest {
+			return true
+		}
+	}
+	return false
 }
 
+// findImages returns the images found in each component, and the images among them that were found in the image fields
+// of the resources or the image annotations of the charts rather than only guessed from the text of the resources.
 // TODO: Refactor to return output string instead of printing inside of function.
-func (p *Packager) findImages(ctx context.Context) (map[string][]string, error) {
+func (p *Packager) findImages(ctx context.Context) (map[string][]string, map[string][]string, error) {
 	for _, component := range p.cfg.Pkg.Components {
 		if len(component.Repos) > 0 && p.cfg.FindImagesOpts.RepoHelmChartPath == "" {
 			message.Note("This Zarf package contains git repositories, " +
@@ -86,19 +210,19 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 	}
 
 	if err := p.populatePackageVariableConfig(); err != nil {
-		return nil, fmt.Errorf("unable to set the active variables: %w", err)
+		return nil, nil, fmt.Errorf("unable to set the active variables: %w", err)
 	}
 
 	// Set default builtin values so they exist in case any helm charts rely on them
 	registryInfo := types.RegistryInfo{Address: p.cfg.FindImagesOpts.RegistryURL}
 	err := registryInfo.FillInEmptyValues()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	gitServer := types.GitServerInfo{}
 	err = gitServer.FillInEmptyValues()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	artifactServer := types.ArtifactServerInfo{}
 	artifactServer.FillInEmptyValues()
@@ -110,6 +234,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 
 	componentDefinition := "\ncomponents:\n"
 	imagesMap := map[string][]string{}
+	confirmedImages := map[string][]string{}
 	whyResources := []string{}
 	for _, component := range p.cfg.Pkg.Components {
 		if len(component.Charts)+len(component.Manifests)+len(component.Repos) < 1 {
@@ -122,7 +247,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 			for _, repo := range component.Repos {
 				matches := strings.Split(repo, "@")
 				if len(matches) < 2 {
-					return nil, fmt.Errorf("cannot convert the Git repository %s to a Helm chart without a version tag", repo)
+					return nil, nil, fmt.Errorf("cannot convert the Git repository %s to a Helm chart without a version tag", repo)
 				}
 
 				// If a repo helm chart path is specified,
@@ -138,11 +263,11 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 
 		componentPaths, err := p.layout.Components.Create(component)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		err = p.populateComponentAndStateTemplates(component.Name)
+		err = p.populateComponentAndStateTemplates(ctx, component.Name)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		resources := []*unstructured.Unstructured{}
@@ -159,37 +284,37 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 			)
 			err = helmCfg.PackageChart(ctx, component.DeprecatedCosignKeyPath)
 			if err != nil {
-				return nil, fmt.Errorf("unable to package the chart %s: %w", chart.Name, err)
+				return nil, nil, fmt.Errorf("unable to package the chart %s: %w", chart.Name, err)
 			}
 
 			valuesFilePaths, err := helpers.RecursiveFileList(componentPaths.Values, nil, false)
 			// TODO: The values path should exist if the path is set, otherwise it should be empty.
 			if err != nil && !errors.Is(err, os.ErrNotExist) {
-				return nil, err
+				return nil, nil, err
 			}
 			for _, valueFilePath := range valuesFilePaths {
 				err := p.variableConfig.ReplaceTextTemplate(valueFilePath)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 
 			chartTemplate, chartValues, err := helmCfg.TemplateChart(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("could not render the Helm template for chart %s: %w", chart.Name, err)
+				return nil, nil, fmt.Errorf("could not render the Helm template for chart %s: %w", chart.Name, err)
 			}
 
 			// Break the template into separate resources
 			yamls, err := utils.SplitYAML([]byte(chartTemplate))
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			resources = append(resources, yamls...)
 
 			chartTarball := helm.StandardName(componentPaths.Charts, chart) + ".tgz"
 			annotatedImages, err := helm.FindAnnotatedImagesForChart(chartTarball, chartValues)
 			if err != nil {
-				return nil, fmt.Errorf("could not look up image annotations for chart URL %s: %w", chart.URL, err)
+				return nil, nil, fmt.Errorf("could not look up image annotations for chart URL %s: %w", chart.URL, err)
 			}
 			for _, image := range annotatedImages {
 				matchedImages[image] = true
@@ -199,7 +324,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 			if p.cfg.FindImagesOpts.Why != "" {
 				whyResourcesChart, err := findWhyResources(yamls, p.cfg.FindImagesOpts.Why, component.Name, chart.Name, true)
 				if err != nil {
-					return nil, fmt.Errorf("could not determine why resource for the chart %s: %w", chart.Name, err)
+					return nil, nil, fmt.Errorf("could not determine why resource for the chart %s: %w", chart.Name, err)
 				}
 				whyResources = append(whyResources, whyResourcesChart...)
 			}
@@ -211,7 +336,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 				kname := fmt.Sprintf("kustomization-%s-%d.yaml", manifest.Name, idx)
 				destination := filepath.Join(componentPaths.Manifests, kname)
 				if err := kustomize.Build(k, destination, manifest.KustomizeAllowAnyDirectory); err != nil {
-					return nil, fmt.Errorf("unable to build the kustomization for %s: %w", k, err)
+					return nil, nil, fmt.Errorf("unable to build the kustomization for %s: %w", k, err)
 				}
 				manifest.Files = append(manifest.Files, destination)
 			}
@@ -221,32 +346,32 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 					mname := fmt.Sprintf("manifest-%s-%d.yaml", manifest.Name, idx)
 					destination := filepath.Join(componentPaths.Manifests, mname)
 					if err := utils.DownloadToFile(ctx, f, destination, component.DeprecatedCosignKeyPath); err != nil {
-						return nil, fmt.Errorf(lang.ErrDownloading, f, err.Error())
+						return nil, nil, fmt.Errorf(lang.ErrDownloading, f, err.Error())
 					}
 					f = destination
 				} else {
 					filename := filepath.Base(f)
 					newDestination := filepath.Join(componentPaths.Manifests, filename)
 					if err := helpers.CreatePathAndCopy(f, newDestination); err != nil {
-						return nil, fmt.Errorf("unable to copy manifest %s: %w", f, err)
+						return nil, nil, fmt.Errorf("unable to copy manifest %s: %w", f, err)
 					}
 					f = newDestination
 				}
 
 				if err := p.variableConfig.ReplaceTextTemplate(f); err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				// Read the contents of each file
 				contents, err := os.ReadFile(f)
 				if err != nil {
-					return nil, fmt.Errorf("could not read the file %s: %w", f, err)
+					return nil, nil, fmt.Errorf("could not read the file %s: %w", f, err)
 				}
 
 				// Break the manifest into separate resources
 				yamls, err := utils.SplitYAML(contents)
 				if err != nil {
 					fmt.Println("got this err")
-					return nil, err
+					return nil, nil, err
 				}
 				resources = append(resources, yamls...)
 
@@ -254,7 +379,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 				if p.cfg.FindImagesOpts.Why != "" {
 					whyResourcesManifest, err := findWhyResources(yamls, p.cfg.FindImagesOpts.Why, component.Name, manifest.Name, false)
 					if err != nil {
-						return nil, fmt.Errorf("could not find why resources for manifest %s: %w", manifest.Name, err)
+						return nil, nil, fmt.Errorf("could not find why resources for manifest %s: %w", manifest.Name, err)
 					}
 					whyResources = append(whyResources, whyResourcesManifest...)
 				}
@@ -266,7 +391,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 
 		for _, resource := range resources {
 			if matchedImages, maybeImages, err = processUnstructuredImages(resource, matchedImages, maybeImages); err != nil {
-				return nil, fmt.Errorf("could not process the Kubernetes resource %s: %w", resource.GetName(), err)
+				return nil, nil, fmt.Errorf("could not process the Kubernetes resource %s: %w", resource.GetName(), err)
 			}
 		}
 
@@ -280,13 +405,14 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 				imagesMap[component.Name] = append(imagesMap[component.Name], image)
 				componentDefinition += fmt.Sprintf("      - %s\n", image)
 			}
+			confirmedImages[component.Name] = sortedMatchedImages
 		}
 
 		// Handle the "maybes"
 		if len(sortedExpectedImages) > 0 {
 			var validImages []string
 			for _, image := range sortedExpectedImages {
-				if descriptor, err := crane.Head(image, images.WithGlobalInsecureFlag()...); err != nil {
+				if descriptor, err := images.Head(image, p.cfg.FindImagesOpts.RegistryMirrors); err != nil {
 					// Test if this is a real image, if not just quiet log to debug, this is normal
 					message.Debugf("Suspected image does not appear to be valid: %#v", err)
 				} else {
@@ -318,7 +444,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 					spinner.Updatef("Looking up cosign artifacts for discovered images (%d/%d)", idx+1, len(imagesMap[component.Name]))
 					cosignArtifacts, err := utils.GetCosignArtifacts(image)
 					if err != nil {
-						return nil, fmt.Errorf("could not lookup the cosing artifacts for image %s: %w", image, err)
+						return nil, nil, fmt.Errorf("could not lookup the cosing artifacts for image %s: %w", image, err)
 					}
 					cosignArtifactList = append(cosignArtifactList, cosignArtifacts...)
 				}
@@ -338,14 +464,14 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 
 	if p.cfg.FindImagesOpts.Why != "" {
 		if len(whyResources) == 0 {
-			return nil, fmt.Errorf("image %s not found in any charts or manifests", p.cfg.FindImagesOpts.Why)
+			return nil, nil, fmt.Errorf("image %s not found in any charts or manifests", p.cfg.FindImagesOpts.Why)
 		}
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	fmt.Println(componentDefinition)
 
-	return imagesMap, nil
+	return imagesMap, confirmedImages, nil
 }
 
 func processUnstructuredImages(resource *unstructured.Unstructured, matchedImages, maybeImages map[string]bool) (map[string]bool, map[string]bool, error) {
@@ -462,3 +588,83 @@ func getSortedImages(matchedImages map[string]bool, maybeImages map[string]bool)
 
 	return sortedMatchedImages, sortedMaybeImages
 }
+
+// updateImagesInDefinition adds newly discovered images to the images lists of the components in a raw zarf.yaml.
+// The images are merged into the parsed YAML nodes of the file so that its comments and ordering are preserved.
+func updateImagesInDefinition(b []byte, flavor string, newImages map[string][]string) ([]byte, error) {
+	file, err := parser.ParseBytes(b, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Docs) == 0 {
+		return nil, errors.New("package definition is empty")
+	}
+
+	for _, kv := range mappingValues(file.Docs[0].Body) {
+		if kv.Key.String() != "components" {
+			continue
+		}
+		seq, ok := kv.Value.(*ast.SequenceNode)
+		if !ok {
+			return nil, errors.New("components must be a list")
+		}
+		for idx, item := range seq.Values {
+			var name, onlyFlavor string
+			hasImages := false
+			for _, field := range mappingValues(item) {
+				switch field.Key.String() {
+				case "name":
+					name = strings.TrimSpace(field.Value.String())
+				case "images":
+					hasImages = true
+				case "only":
+					for _, only := range mappingValues(field.Value) {
+						if only.Key.String() == "flavor" {
+							onlyFlavor = strings.TrimSpace(only.Value.String())
+						}
+					}
+				}
+			}
+			if len(newImages[name]) == 0 {
+				continue
+			}
+			if onlyFlavor != "" && onlyFlavor != flavor {
+				continue
+			}
+
+			path := fmt.Sprintf("$.components[%d].images", idx)
+			var node ast.Node
+			if hasImages {
+				node, err = yaml.ValueToNode(newImages[name], yaml.IndentSequence(true))
+			} else {
+				path = fmt.Sprintf("$.components[%d]", idx)
+				node, err = yaml.ValueToNode(map[string][]string{"images": newImages[name]}, yaml.IndentSequence(true))
+			}
+			if err != nil {
+				return nil, err
+			}
+			yamlPath, err := yaml.PathString(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := yamlPath.MergeFromNode(file, node); err != nil {
+				return nil, fmt.Errorf("unable to update the images of component %s: %w", name, err)
+			}
+		}
+	}
+	return []byte(file.String()), nil
+}
+
+func mappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	case *ast.TagNode:
+		return mappingValues(n.Value)
+	case *ast.AnchorNode:
+		return mappingValues(n.Value)
+	}
+	return nil
+}
@@ -4,12 +4,23 @@
 package packager
 
 import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/test/testutil"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -172,3 +183,129 @@ func TestGetSortedImages(t *testing.T) {
 	expectedSortedMaybeImages := []string{"B", "Z"}
 	require.Equal(t, expectedSortedMaybeImages, sortedMaybeImages)
 }
+
+func TestUpdateImagesInDefinition(t *testing.T) {
+	t.Parallel()
+
+	definition := `kind: ZarfPackageConfig
+metadata:
+  name: update # keep me
+components:
+  # the first component
+  - name: existing
+    required: true
+    images:
+      - nginx:1.16.0 # pinned
+    manifests:
+      - name: app
+  - name: missing
+    charts:
+      - name: chart
+  - name: other-flavor
+    only:
+      flavor: other
+`
+	newImages := map[string][]string{
+		"existing":     {"busybox:latest"},
+		"missing":      {"alpine:3.20", "ghcr.io/zarf-dev/zarf/agent:v0.38.1"},
+		"other-flavor": {"redis:7"},
+	}
+	b, err := updateImagesInDefinition([]byte(definition), "", newImages)
+	require.NoError(t, err)
+
+	expected := `kind: ZarfPackageConfig
+metadata:
+  name: update # keep me
+components:
+  # the first component
+  - name: existing
+    required: true
+    images:
+      - nginx:1.16.0 # pinned
+      - busybox:latest
+    manifests:
+      - name: app
+  - name: missing
+    charts:
+      - name: chart
+    images:
+      - alpine:3.20
+      - ghcr.io/zarf-dev/zarf/agent:v0.38.1
+  - name: other-flavor
+    only:
+      flavor: other
+`
+	require.Equal(t, expected, string(b))
+
+	b, err = updateImagesInDefinition([]byte("components:\n  - name: flow\n    images: [nginx]\n"), "", map[string][]string{"flow": {"busybox"}})
+	require.NoError(t, err)
+	require.Equal(t, "components:\n  - name: flow\n    images: [nginx, busybox]\n", string(b))
+}
+
+func TestUpdatePackageDefinitionImages(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+	digests := map[string]string{}
+	for _, repo := range []string{"app", "sidecar"} {
+		img, err := random.Image(1024, 1)
+		require.NoError(t, err)
+		require.NoError(t, crane.Push(img, fmt.Sprintf("%s/%s:1.0.0", host, repo), crane.Insecure))
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		digests[repo] = digest.String()
+	}
+
+	path := filepath.Join(t.TempDir(), "zarf.yaml")
+	require.NoError(t, helpers.CreatePathAndCopy("./testdata/find-images/update/zarf.yaml", path))
+	var pkg v1alpha1.ZarfPackage
+	require.NoError(t, utils.ReadYaml(path, &pkg))
+	p := &Packager{cfg: &types.PackagerConfig{Pkg: pkg}}
+
+	confirmedImages := map[string][]string{
+		"app": {
+			"nginx:1.16.0",
+			fmt.Sprintf("%s/app:1.0.0", host),
+			fmt.Sprintf("%s/missing:1.0.0", host),
+		},
+		"sidecar": {
+			fmt.Sprintf("%s/sidecar:1.0.0@%s", host, digests["sidecar"]),
+		},
+	}
+	require.NoError(t, p.updatePackageDefinitionImages(path, confirmedImages))
+
+	expected := fmt.Sprintf(`kind: ZarfPackageConfig
+metadata:
+  name: update # the images of the components are updated by the test
+components:
+  # images that are already listed are not added again
+  - name: app
+    required: true
+    images:
+      - nginx:1.16.0 # already listed
+      - %[1]s/app:1.0.0@%[2]s
+    manifests:
+      - name: app
+        files:
+          - deployment.yaml
+  - name: sidecar
+    manifests:
+      - name: sidecar
+        files:
+          - sidecar.yaml
+    images:
+      - %[1]s/sidecar:1.0.0@%[3]s
+`, host, digests["app"], digests["sidecar"])
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(b))
+
+	// Images that are listed pinned to their digest are not added again
+	require.NoError(t, utils.ReadYaml(path, &p.cfg.Pkg))
+	require.NoError(t, p.updatePackageDefinitionImages(path, confirmedImages))
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(unchanged))
+}
@@ -6,9 +6,11 @@ package packager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
@@ -22,10 +24,13 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
-// Publish publishes the package to a registry
+// Publish publishes the package to a registry, or concurrently to each of the registries it is mirrored to
 func (p *Packager) Publish(ctx context.Context) (err error) {
+	destinations := append([]string{p.cfg.PublishOpts.PackageDestination}, p.cfg.PublishOpts.MirrorDestinations...)
+
 	_, isOCISource := p.source.(*sources.OCISource)
 	if isOCISource && p.cfg.PublishOpts.SigningKeyPath == "" {
 		// oci --> oci is a special case, where we will use oci.CopyPackage so that we can transfer the package
@@ -35,16 +40,19 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 		parts := strings.Split(srcRemote.Repo().Reference.Repository, "/")
 		packageName := parts[len(parts)-1]
 
-		p.cfg.PublishOpts.PackageDestination = p.cfg.PublishOpts.PackageDestination + "/" + packageName
-
 		arch := config.GetArch()
 
-		dstRemote, err := zoci.NewRemote(p.cfg.PublishOpts.PackageDestination, oci.PlatformForArch(arch))
-		if err != nil {
-			return err
-		}
-
-		return zoci.CopyPackage(ctx, srcRemote, dstRemote, config.CommonOptions.OCIConcurrency)
+		_, err := p.publishConcurrently(ctx, destinations, func(ctx context.Context, destination string) (*zoci.Remote, error) {
+			dstRemote, err := p.newPublishRemote(destination+"/"+packageName, oci.PlatformForArch(arch))
+			if err != nil {
+				return nil, err
+			}
+			if err := zoci.CopyPackage(ctx, srcRemote, dstRemote, config.CommonOptions.OCIConcurrency); err != nil {
+				return nil, err
+			}
+			return dstRemote, nil
+		})
+		return err
 	}
 
 	if p.cfg.CreateOpts.IsSkeleton {
@@ -83,28 +91,35 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 		}
 	}
 
-	// Get a reference to the registry for this package
-	ref, err := zoci.ReferenceFromMetadata(p.cfg.PublishOpts.PackageDestination, &p.cfg.Pkg.Metadata, &p.cfg.Pkg.Build)
-	if err != nil {
-		return err
-	}
 	var platform ocispec.Platform
 	if p.cfg.CreateOpts.IsSkeleton {
 		platform = zoci.PlatformForSkeleton()
 	} else {
 		platform = oci.PlatformForArch(p.cfg.Pkg.Build.Architecture)
 	}
-	remote, err := zoci.NewRemote(ref, platform)
-	if err != nil {
-		return err
-	}
 
-	message.HeaderInfof("📦 PACKAGE PUBLISH %s:%s", p.cfg.Pkg.Metadata.Name, ref)
+	message.HeaderInfof("📦 PACKAGE PUBLISH %s:%s", p.cfg.Pkg.Metadata.Name, strings.Join(destinations, ", "))
 
-	// Publish the package/skeleton to the registry
-	if err := remote.PublishPackage(ctx, &p.cfg.Pkg, p.layout, config.CommonOptions.OCIConcurrency); err != nil {
+	// Publish the package/skeleton to the registries
+	remotes, err := p.publishConcurrently(ctx, destinations, func(ctx context.Context, destination string) (*zoci.Remote, error) {
+		// Get a reference to the registry for this package
+		ref, err := zoci.ReferenceFromMetadata(destination, &p.cfg.Pkg.Metadata, &p.cfg.Pkg.Build)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := p.newPublishRemote(ref, platform)
+		if err != nil {
+			return nil, err
+		}
+		if err := remote.PublishPackage(ctx, &p.cfg.Pkg, p.layout, config.CommonOptions.OCIConcurrency); err != nil {
+			return nil, err
+		}
+		return remote, nil
+	})
+	if err != nil {
 		return err
 	}
+	remote := remotes[0]
 	if p.cfg.CreateOpts.IsSkeleton {
 		message.Title("How to import components from this skeleton:", "")
 		ex := []v1alpha1.ZarfComponent{}
@@ -121,3 +136,67 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 	}
 	return nil
 }
+
+// publishConcurrently publishes the package to each of the destinations at once, a destination that fails does not
+// stop the others. When the package is mirrored to several destinations, the digest of the package in each of them is
+// printed once they are all done.
+func (p *Packager) publishConcurrently(ctx context.Context, destinations []string, publish func(ctx context.Context, destination string) (*zoci.Remote, error)) ([]*zoci.Remote, error) {
+	remotes := make([]*zoci.Remote, len(destinations))
+	errs := make([]error, len(destinations))
+	var wg sync.WaitGroup
+	for i, destination := range destinations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			remotes[i], errs[i] = publish(ctx, destination)
+		}()
+	}
+	wg.Wait()
+
+	if len(destinations) == 1 {
+		return remotes, errs[0]
+	}
+	rows := [][]string{}
+	for i, destination := range destinations {
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("unable to publish to %s: %w", destination, errs[i])
+			rows = append(rows, []string{destination, "failed"})
+			continue
+		}
+		root, err := remotes[i].ResolveRoot(ctx)
+		if err != nil {
+			errs[i] = fmt.Errorf("unable to resolve the digest of the package published to %s: %w", destination, err)
+			rows = append(rows, []string{remotes[i].Repo().Reference.String(), "unknown"})
+			continue
+		}
+		rows = append(rows, []string{remotes[i].Repo().Reference.String(), root.Digest.String()})
+	}
+	message.Table([]string{"Reference", "Digest"}, rows)
+	return remotes, errors.Join(errs...)
+}
+
+// newPublishRemote returns a remote for the reference that authenticates with the credentials of its registry, or
+// with the Docker credential store when there are none.
+func (p *Packager) newPublishRemote(ref string, platform ocispec.Platform) (*zoci.Remote, error) {
+	remote, err := zoci.NewRemote(ref, platform)
+	if err != nil {
+		return nil, err
+	}
+	if credential, ok := publishCredential(p.cfg.PublishOpts.Credentials, remote.Repo().Reference.Registry); ok {
+		remote.SetBasicAuth(credential.Username, credential.Password)
+	}
+	remote.ChunkSize = int64(p.cfg.PublishOpts.ChunkSizeMB) * 1000 * 1000
+	return remote, nil
+}
+
+// publishCredential returns the credential of the registry, matched by its host and port. The address of a credential
+// may be given as an oci:// URL.
+func publishCredential(credentials []types.RegistryCredential, registry string) (types.RegistryCredential, bool) {
+	for _, credential := range credentials {
+		address := strings.TrimSuffix(strings.TrimPrefix(credential.Address, helpers.OCIURLPrefix), "/")
+		if address == registry {
+			return credential, true
+		}
+	}
+	return types.RegistryCredential{}, false
+}
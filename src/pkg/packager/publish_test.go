@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestPublishCredential(t *testing.T) {
+	t.Parallel()
+
+	credentials := []types.RegistryCredential{
+		{Address: "ghcr.io", Username: "ghcr-user", Password: "ghcr-pass"},
+		{Address: "oci://registry.example.com:5000/", Username: "mirror-user", Password: "mirror-pass"},
+	}
+	tests := []struct {
+		name     string
+		registry string
+		expected string
+		found    bool
+	}{
+		{
+			name:     "registry host",
+			registry: "ghcr.io",
+			expected: "ghcr-user",
+			found:    true,
+		},
+		{
+			name:     "oci url with port",
+			registry: "registry.example.com:5000",
+			expected: "mirror-user",
+			found:    true,
+		},
+		{
+			name:     "port does not match",
+			registry: "registry.example.com",
+			found:    false,
+		},
+		{
+			name:     "unknown registry",
+			registry: "docker.io",
+			found:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			credential, ok := publishCredential(credentials, tt.registry)
+			require.Equal(t, tt.found, ok)
+			require.Equal(t, tt.expected, credential.Username)
+		})
+	}
+}
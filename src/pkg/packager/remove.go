@@ -6,7 +6,6 @@ package packager
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
@@ -21,6 +20,7 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/internal/packager/plugins"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
@@ -59,6 +59,9 @@ func (p *Packager) Remove(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if err := p.confirmPlugins(included); err != nil {
+		return err
+	}
 
 	for _, component := range included {
 		componentsToRemove = append(componentsToRemove, component.Name)
@@ -108,7 +111,7 @@ func (p *Packager) Remove(ctx context.Context) (err error) {
 func (p *Packager) updatePackageSecret(ctx context.Context, deployedPackage types.DeployedPackage) error {
 	// Only attempt to update the package secret if we are actually connected to a cluster
 	if p.cluster != nil {
-		newPackageSecretData, err := json.Marshal(deployedPackage)
+		newPackageSecretData, err := cluster.DeployedPackageSecretData(&deployedPackage)
 		if err != nil {
 			return err
 		}
@@ -130,9 +133,7 @@ func (p *Packager) updatePackageSecret(ctx context.Context, deployedPackage type
 				},
 			},
 			Type: corev1.SecretTypeOpaque,
-			Data: map[string][]byte{
-				"data": newPackageSecretData,
-			},
+			Data: newPackageSecretData,
 		}
 
 		err = func() error {
@@ -180,6 +181,14 @@ func (p *Packager) removeComponent(ctx context.Context, deployedPackage *types.D
 		return nil, fmt.Errorf("unable to run the before action for component (%s): %w", c.Name, err)
 	}
 
+	if len(c.Plugins) > 0 {
+		spinner.Updatef("Removing the plugins of the '%s' component", deployedComponent.Name)
+		if err := p.runPlugins(ctx, plugins.PhaseRemove, "", c); err != nil {
+			onFailure()
+			return deployedPackage, fmt.Errorf("unable to remove the plugins of component (%s): %w", c.Name, err)
+		}
+	}
+
 	for _, chart := range helpers.Reverse(deployedComponent.InstalledCharts) {
 		spinner.Updatef("Uninstalling chart '%s' from the '%s' component", chart.ChartName, deployedComponent.Name)
 
@@ -236,6 +245,9 @@ func (p *Packager) removeComponent(ctx context.Context, deployedPackage *types.D
 			if err != nil {
 				message.Warnf("Unable to delete the '%s' package secret: '%s' (this may be normal if the cluster was removed)", secretName, err.Error())
 			}
+			if err := p.cluster.DeleteImageMap(ctx, deployedPackage.Name); err != nil {
+				message.Warnf("Unable to delete the image map of the '%s' package: '%s'", deployedPackage.Name, err.Error())
+			}
 		}
 	} else {
 		err := p.updatePackageSecret(ctx, *deployedPackage)
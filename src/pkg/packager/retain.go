@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/retention"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// RetainDir returns the directory the archives of deployed packages are retained in, the given directory or a
+// directory in the Zarf cache when it is empty.
+func RetainDir(dir string) string {
+	if dir != "" {
+		return config.GetAbsHomePath(dir)
+	}
+	return filepath.Join(config.GetAbsCachePath(), "deployed-packages")
+}
+
+// retainOnHost copies the archive of the deployed package into the retention directory when --retain is set,
+// failing to do so does not fail the deployment.
+func (p *Packager) retainOnHost(ctx context.Context, source string) {
+	if !p.cfg.DeployOpts.Retain {
+		return
+	}
+	spinner := message.NewProgressSpinner(lang.PkgDeployRetaining, p.cfg.Pkg.Metadata.Name)
+	defer spinner.Stop()
+	entry, pruned, err := p.retainArchive(ctx, source)
+	if err != nil {
+		spinner.Stop()
+		message.Warnf(lang.PkgDeployErrRetain, p.cfg.Pkg.Metadata.Name, err.Error())
+		return
+	}
+	for _, e := range pruned {
+		message.Debugf("Deleted the retained archive %s of %s %s", e.Path, e.Name, e.Version)
+	}
+	spinner.Successf(lang.PkgDeployRetained, p.cfg.Pkg.Metadata.Name, filepath.Join(RetainDir(p.cfg.DeployOpts.RetainDir), entry.Path), entry.SHA256)
+}
+
+// retainArchive finds or collects the archive of the deployed package and retains it.
+func (p *Packager) retainArchive(ctx context.Context, source string) (retention.Entry, []retention.Entry, error) {
+	entry := retention.Entry{
+		Name:         p.cfg.Pkg.Metadata.Name,
+		Version:      p.cfg.Pkg.Metadata.Version,
+		Architecture: p.cfg.Pkg.Build.Architecture,
+		Source:       source,
+	}
+	dir := RetainDir(p.cfg.DeployOpts.RetainDir)
+
+	var archive string
+	switch src := p.source.(type) {
+	case *sources.TarballSource, *sources.SplitTarballSource, *sources.URLSource:
+		// Loading these sources leaves the package source pointing at the local, reassembled or downloaded tarball
+		archive = p.cfg.PkgOpts.PackageSource
+	case *sources.OCISource:
+		// The deployed layout only holds the selected components and has been unarchived, so the package is pulled again in full
+		tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			return retention.Entry{}, nil, err
+		}
+		defer os.RemoveAll(tmpDir)
+		pkgOpts := *src.ZarfPackageOptions
+		collected, err := (&sources.OCISource{ZarfPackageOptions: &pkgOpts, Remote: src.Remote}).Collect(ctx, tmpDir)
+		if err != nil {
+			return retention.Entry{}, nil, err
+		}
+		archive = collected
+	default:
+		return retention.Entry{}, nil, fmt.Errorf("packages deployed from a %T can not be retained", p.source)
+	}
+	return retention.Retain(dir, archive, entry, p.cfg.DeployOpts.RetainMax)
+}
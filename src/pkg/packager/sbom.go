@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// GenerateSBOM generates (or regenerates) the SBOMs for a local package tarball and rewrites the package with them.
+func (p *Packager) GenerateSBOM(ctx context.Context) (err error) {
+	tarball, ok := p.source.(*sources.TarballSource)
+	if !ok {
+		return errors.New(lang.CmdPackageSBOMGenerateErrSource)
+	}
+
+	p.cfg.Pkg, _, err = p.source.LoadPackage(ctx, p.layout, filters.Empty(), true)
+	if err != nil {
+		return fmt.Errorf("unable to load the package: %w", err)
+	}
+
+	// Rewriting the package changes its checksums, so an existing signature can not be kept
+	if p.layout.Signature != "" {
+		if p.cfg.CreateOpts.SigningKeyPath == "" {
+			return errors.New(lang.CmdPackageSBOMGenerateErrSigned)
+		}
+		if err := os.Remove(p.layout.Signature); err != nil {
+			return err
+		}
+		p.layout.Signature = ""
+	}
+
+	if err := os.RemoveAll(p.layout.SBOMs.Path); err != nil {
+		return err
+	}
+	if err := creator.GenerateSBOMs(p.layout, p.cfg.Pkg.Components, p.cfg.CreateOpts); err != nil {
+		return err
+	}
+
+	for _, component := range p.cfg.Pkg.Components {
+		if err := p.layout.Components.Archive(component, true); err != nil {
+			return fmt.Errorf("unable to archive component: %w", err)
+		}
+	}
+
+	finalize := func(aggregateChecksum string) error {
+		p.cfg.Pkg.Metadata.AggregateChecksum = aggregateChecksum
+		if err := utils.WriteYaml(p.layout.ZarfYAML, p.cfg.Pkg, helpers.ReadUser); err != nil {
+			return fmt.Errorf("unable to write zarf.yaml: %w", err)
+		}
+		return p.layout.SignPackage(p.cfg.CreateOpts.SigningKeyPath, p.cfg.CreateOpts.SigningKeyPassword, !config.CommonOptions.Confirm)
+	}
+
+	outputDir := p.cfg.CreateOpts.Output
+	if outputDir == "" {
+		outputDir = filepath.Dir(tarball.PackageSource)
+	}
+	packageName := fmt.Sprintf("%s%s", sources.NameFromMetadata(&p.cfg.Pkg, false), sources.PkgSuffix(p.cfg.Pkg.Metadata.Uncompressed))
+	tarballPath := filepath.Join(outputDir, packageName)
+
+	// Try to remove the package if it already exists, its contents have already been loaded
+	_ = os.Remove(tarballPath)
+	if err := p.layout.ArchivePackageWithChecksums(tarballPath, p.cfg.CreateOpts.MaxPackageSizeMB, finalize); err != nil {
+		return fmt.Errorf("unable to archive package: %w", err)
+	}
+
+	message.Successf(lang.CmdPackageSBOMGenerateSuccess, p.cfg.Pkg.Metadata.Name, tarballPath)
+	return nil
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/lint"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that ClusterCacheSource implements PackageSource
+	_ PackageSource = (*ClusterCacheSource)(nil)
+)
+
+// ClusterCacheRepository is the repository in the in-cluster package cache that deployed packages are retained under.
+const ClusterCacheRepository = "zarf-packages"
+
+// ClusterCacheReference returns the reference of a package in the in-cluster package cache reachable at endpoint.
+func ClusterCacheReference(endpoint string, metadata *v1alpha1.ZarfMetadata, build *v1alpha1.ZarfBuildData) (string, error) {
+	return zoci.ReferenceFromMetadata(fmt.Sprintf("%s/%s", endpoint, ClusterCacheRepository), metadata, build)
+}
+
+// NewClusterCacheSource creates a new source for packages retained in the in-cluster package cache.
+//
+// The package source is the package name, optionally followed by the version to load (e.g. my-package:1.0.0),
+// which defaults to the version currently deployed.
+func NewClusterCacheSource(pkgOpts *types.ZarfPackageOptions) (PackageSource, error) {
+	name, _, _ := strings.Cut(pkgOpts.PackageSource, ":")
+	if !lint.IsLowercaseNumberHyphenNoStartHyphen(name) {
+		return nil, fmt.Errorf("invalid package name %q", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cluster.DefaultTimeout)
+	defer cancel()
+
+	c, err := cluster.NewClusterWithWait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterCacheSource{pkgOpts, c}, nil
+}
+
+// ClusterCacheSource is a package source for the in-cluster package cache.
+type ClusterCacheSource struct {
+	*types.ZarfPackageOptions
+	*cluster.Cluster
+}
+
+// LoadPackage loads a package from the in-cluster package cache.
+func (s *ClusterCacheSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (v1alpha1.ZarfPackage, []string, error) {
+	src, tunnel, err := s.connect(ctx)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	defer tunnel.Close()
+	return src.LoadPackage(ctx, dst, filter, unarchiveAll)
+}
+
+// LoadPackageMetadata loads package metadata from the in-cluster package cache.
+func (s *ClusterCacheSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (v1alpha1.ZarfPackage, []string, error) {
+	src, tunnel, err := s.connect(ctx)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	defer tunnel.Close()
+	return src.LoadPackageMetadata(ctx, dst, wantSBOM, skipValidation)
+}
+
+// Collect pulls a package from the in-cluster package cache and writes it to a tarball.
+func (s *ClusterCacheSource) Collect(ctx context.Context, dir string) (string, error) {
+	src, tunnel, err := s.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tunnel.Close()
+	return src.Collect(ctx, dir)
+}
+
+// connect opens a tunnel to the in-cluster package cache and returns an OCI source for the requested package.
+func (s *ClusterCacheSource) connect(ctx context.Context) (*OCISource, *cluster.Tunnel, error) {
+	ok, err := s.HasPackageCache(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("the %s component has not been deployed to the cluster", cluster.ZarfPackageCacheName)
+	}
+
+	name, version, _ := strings.Cut(s.PackageSource, ":")
+	metadata := &v1alpha1.ZarfMetadata{Name: name, Version: version}
+	var build *v1alpha1.ZarfBuildData
+	arch := config.GetArch()
+	deployedPackage, err := s.GetDeployedPackage(ctx, name)
+	if err == nil {
+		arch = deployedPackage.Data.Build.Architecture
+		if version == "" {
+			metadata.Version = deployedPackage.Data.Metadata.Version
+			build = &deployedPackage.Data.Build
+		}
+	} else if version == "" {
+		return nil, nil, fmt.Errorf("unable to find the deployed version of %s, specify the version to load as %s:<version>: %w", name, name, err)
+	}
+
+	state, err := s.LoadZarfState(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tunnel, err := s.ConnectToPackageCache(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ref, err := ClusterCacheReference(tunnel.Endpoint(), metadata, build)
+	if err != nil {
+		tunnel.Close()
+		return nil, nil, err
+	}
+	remote, err := zoci.NewRemote(ref, oci.PlatformForArch(arch), oci.WithPlainHTTP(true))
+	if err != nil {
+		tunnel.Close()
+		return nil, nil, err
+	}
+	remote.SetBasicAuth(state.RegistryInfo.PullUsername, state.RegistryInfo.PullPassword)
+
+	return &OCISource{ZarfPackageOptions: s.ZarfPackageOptions, Remote: remote}, tunnel, nil
+}
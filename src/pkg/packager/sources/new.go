@@ -42,6 +42,10 @@ type PackageSource interface {
 
 // Identify returns the type of package source based on the provided package source string.
 func Identify(pkgSrc string) string {
+	if pkgSrc == StdinPackageSource {
+		return "stdin"
+	}
+
 	if helpers.IsURL(pkgSrc) {
 		parsed, _ := url.Parse(pkgSrc)
 		return parsed.Scheme
@@ -81,6 +85,8 @@ func New(pkgOpts *types.ZarfPackageOptions) (PackageSource, error) {
 		source = &URLSource{pkgOpts}
 	case "split":
 		source = &SplitTarballSource{pkgOpts}
+	case "stdin":
+		source = &StdinSource{ZarfPackageOptions: pkgOpts}
 	default:
 		return nil, fmt.Errorf("could not identify source type for %q", pkgSrc)
 	}
@@ -15,11 +15,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"filippo.io/age"
 	"github.com/stretchr/testify/require"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -80,12 +82,24 @@ func TestNewPackageSource(t *testing.T) {
 			expectedIdentify: "tarball",
 			expectedType:     &TarballSource{},
 		},
+		{
+			name:             "local encrypted tar zst",
+			src:              "zarf-package-manifests-amd64-v1.0.0.tar.zst.age",
+			expectedIdentify: "tarball",
+			expectedType:     &TarballSource{},
+		},
 		{
 			name:             "local tar split",
 			src:              "testdata/.part000",
 			expectedIdentify: "split",
 			expectedType:     &SplitTarballSource{},
 		},
+		{
+			name:             "stdin",
+			src:              "-",
+			expectedIdentify: "stdin",
+			expectedType:     &StdinSource{},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -198,3 +212,112 @@ func TestPackageSource(t *testing.T) {
 		})
 	}
 }
+
+func TestStdinSource(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("./testdata/expected-pkg.json")
+	require.NoError(t, err)
+	expectedPkg := v1alpha1.ZarfPackage{}
+	err = json.Unmarshal(b, &expectedPkg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		shasum      string
+		expectedErr string
+	}{
+		{
+			name: "without shasum",
+		},
+		{
+			name:   "with shasum",
+			shasum: "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+		},
+		{
+			name:        "wrong shasum",
+			shasum:      "0000000000000000000000000000000000000000000000000000000000000000",
+			expectedErr: "shasum mismatch for the package read from stdin: expected 0000000000000000000000000000000000000000000000000000000000000000, got 835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// TODO once our messaging is thread safe, re-parallelize this test
+			f, err := os.Open(filepath.Join("testdata", "zarf-package-wordpress-amd64-16.0.4.tar.zst"))
+			require.NoError(t, err)
+			defer f.Close()
+
+			ps := &StdinSource{
+				ZarfPackageOptions: &types.ZarfPackageOptions{PackageSource: StdinPackageSource, Shasum: tt.shasum},
+				Reader:             f,
+			}
+			pkgLayout := layout.New(t.TempDir())
+			pkg, warnings, err := ps.LoadPackage(context.Background(), pkgLayout, filters.Empty(), true)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, warnings)
+			require.Equal(t, expectedPkg, pkg)
+			for _, component := range pkg.Components {
+				require.DirExists(t, filepath.Join(pkgLayout.Components.Base, component.Name))
+			}
+		})
+	}
+}
+
+func TestEncryptedPackageSource(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("./testdata/expected-pkg.json")
+	require.NoError(t, err)
+	expectedPkg := v1alpha1.ZarfPackage{}
+	err = json.Unmarshal(b, &expectedPkg)
+	require.NoError(t, err)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityPath := filepath.Join(t.TempDir(), "key.txt")
+	require.NoError(t, os.WriteFile(identityPath, []byte(identity.String()+"\n"), 0o600))
+	encrypt, err := utils.NewAgeEncryptor([]string{identity.Recipient().String()}, "")
+	require.NoError(t, err)
+
+	src, err := os.Open(filepath.Join("testdata", "zarf-package-wordpress-amd64-16.0.4.tar.zst"))
+	require.NoError(t, err)
+	defer src.Close()
+	encryptedPath := filepath.Join(t.TempDir(), "zarf-package-wordpress-amd64-16.0.4.tar.zst.age")
+	dst, err := os.Create(encryptedPath)
+	require.NoError(t, err)
+	w, err := encrypt(dst)
+	require.NoError(t, err)
+	_, err = io.Copy(w, src)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, dst.Close())
+
+	// TODO once our messaging is thread safe, re-parallelize these loads
+	ps := &TarballSource{&types.ZarfPackageOptions{PackageSource: encryptedPath}}
+	_, _, err = ps.LoadPackageMetadata(context.Background(), layout.New(t.TempDir()), false, false)
+	require.EqualError(t, err, "the package is encrypted, provide an age identity file with --decrypt-identity or the passphrase with --decrypt-passphrase")
+
+	ps = &TarballSource{&types.ZarfPackageOptions{PackageSource: encryptedPath, DecryptIdentityPaths: []string{identityPath}}}
+	pkg, _, err := ps.LoadPackageMetadata(context.Background(), layout.New(t.TempDir()), false, false)
+	require.NoError(t, err)
+	require.Equal(t, expectedPkg, pkg)
+	pkg, _, err = ps.LoadPackage(context.Background(), layout.New(t.TempDir()), filters.Empty(), true)
+	require.NoError(t, err)
+	require.Equal(t, expectedPkg, pkg)
+
+	encrypted, err := os.Open(encryptedPath)
+	require.NoError(t, err)
+	defer encrypted.Close()
+	stdin := &StdinSource{
+		ZarfPackageOptions: &types.ZarfPackageOptions{PackageSource: StdinPackageSource, DecryptIdentityPaths: []string{identityPath}},
+		Reader:             encrypted,
+	}
+	pkg, _, err = stdin.LoadPackage(context.Background(), layout.New(t.TempDir()), filters.Empty(), true)
+	require.NoError(t, err)
+	require.Equal(t, expectedPkg, pkg)
+}
@@ -79,7 +79,11 @@ func (s *OCISource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, f
 
 		spinner.Success()
 
-		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
+		if err := verifyReferrers(ctx, s.Remote, dst, s.PublicKeyPath, s.TrustedKeys); err != nil {
+			return pkg, nil, err
+		}
+
+		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.TrustedKeys); err != nil {
 			return pkg, nil, err
 		}
 	}
@@ -112,7 +116,7 @@ func (s *OCISource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, f
 func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
 	toPull := zoci.PackageAlwaysPull
 	if wantSBOM {
-		toPull = append(toPull, layout.SBOMTar)
+		toPull = append(toPull, layout.SBOMTarballs...)
 	}
 	layersFetched, err := s.PullPaths(ctx, dst.Base, toPull)
 	if err != nil {
@@ -141,7 +145,11 @@ func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.Package
 			spinner.Success()
 		}
 
-		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
+		if err := verifyReferrers(ctx, s.Remote, dst, s.PublicKeyPath, s.TrustedKeys); err != nil {
+			return pkg, nil, err
+		}
+
+		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.TrustedKeys); err != nil {
 			if errors.Is(err, ErrPkgSigButNoKey) && skipValidation {
 				message.Warn("The package was signed but no public key was provided, skipping signature validation")
 			} else {
@@ -190,6 +198,17 @@ func (s *OCISource) Collect(ctx context.Context, dir string) (string, error) {
 
 	spinner.Success()
 
+	if err := verifyReferrers(ctx, s.Remote, loaded, s.PublicKeyPath, s.TrustedKeys); err != nil {
+		return "", err
+	}
+
+	// The package is only validated against a key when one was given, as pulling a package does not deploy it
+	if s.PublicKeyPath != "" {
+		if err := ValidatePackageSignature(ctx, loaded, s.PublicKeyPath, s.TrustedKeys); err != nil {
+			return "", err
+		}
+	}
+
 	// TODO (@Noxsios) remove the suffix check at v1.0.0
 	isSkeleton := pkg.Build.Architecture == zoci.SkeletonArch || strings.HasSuffix(s.Repo().Reference.Reference, zoci.SkeletonArch)
 	name := fmt.Sprintf("%s%s", NameFromMetadata(&pkg, isSkeleton), PkgSuffix(pkg.Metadata.Uncompressed))
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/defenseunicorns/pkg/oci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+// verifyReferrers discovers the signatures, SBOMs and provenance attached to the package and verifies them against
+// the pulled package. The SBOMs attached to the package must be the SBOMs within it. When the package is not signed
+// itself, an attached signature is added to the package so it is validated like a signature within the package.
+func verifyReferrers(ctx context.Context, remote *zoci.Remote, dst *layout.PackagePaths, publicKeyPath string, trustedKeys []string) error {
+	root, err := remote.FetchRoot(ctx)
+	if err != nil {
+		return err
+	}
+	subject, err := remote.ResolveRoot(ctx)
+	if err != nil {
+		return err
+	}
+	referrers, err := remote.Referrers(ctx, subject)
+	if err != nil {
+		// Not every registry supports the referrers API or its tag fallback, this should not fail the pull
+		message.Warnf("Unable to discover the signatures, SBOMs and provenance attached to %s: %s", remote.Repo().Reference, err.Error())
+		return nil
+	}
+
+	signatures := []zoci.Referrer{}
+	for _, referrer := range referrers {
+		switch referrer.Manifest.ArtifactType {
+		case zoci.SignatureArtifactType:
+			signatures = append(signatures, referrer)
+		case zoci.SBOMArtifactType:
+			for _, layer := range referrer.Manifest.Layers {
+				title := layer.Annotations[ocispec.AnnotationTitle]
+				expected := root.Locate(title)
+				if oci.IsEmptyDescriptor(expected) || expected.Digest != layer.Digest {
+					return fmt.Errorf("the SBOM %s attached to the package does not match the SBOMs within it", title)
+				}
+			}
+			message.Debugf("Verified the SBOMs attached to the package in %s", referrer.Descriptor.Digest)
+		case zoci.ProvenanceArtifactType:
+			for _, layer := range referrer.Manifest.Layers {
+				// Fetching a layer verifies it against its digest
+				if _, err := remote.FetchLayer(ctx, layer); err != nil {
+					return fmt.Errorf("unable to fetch the provenance attached to the package: %w", err)
+				}
+			}
+			message.Debugf("Verified the provenance attached to the package in %s", referrer.Descriptor.Digest)
+		default:
+			message.Debugf("Skipping the referrer %s of the unknown artifact type %q", referrer.Descriptor.Digest, referrer.Manifest.ArtifactType)
+		}
+	}
+
+	if dst.Signature != "" || len(signatures) == 0 {
+		return nil
+	}
+	signaturePath := filepath.Join(dst.Base, layout.Signature)
+	for i, signature := range signatures {
+		layer := signature.Locate(layout.Signature)
+		if oci.IsEmptyDescriptor(layer) {
+			continue
+		}
+		b, err := remote.FetchLayer(ctx, layer)
+		if err != nil {
+			return fmt.Errorf("unable to fetch the signature attached to the package: %w", err)
+		}
+		if err := os.WriteFile(signaturePath, b, helpers.ReadWriteUser); err != nil {
+			return err
+		}
+		dst.Signature = signaturePath
+		// Of several signatures, the one made with the key the package is validated with is kept
+		if i == len(signatures)-1 || ValidatePackageSignature(ctx, dst, publicKeyPath, trustedKeys) == nil {
+			break
+		}
+	}
+	if dst.Signature != "" {
+		message.Debugf("Added the signature attached to %s to the package", remote.Repo().Reference)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+func TestVerifyReferrers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		attachedSBOM string
+		expectedErr  string
+	}{
+		{
+			name:         "matching SBOM and attached signature",
+			attachedSBOM: "sboms",
+		},
+		{
+			name:         "SBOM that is not within the package",
+			attachedSBOM: "other sboms",
+			expectedErr:  "the SBOM sboms.tar attached to the package does not match the SBOMs within it",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+			t.Cleanup(srv.Close)
+			host := strings.TrimPrefix(srv.URL, "http://")
+
+			remote, err := zoci.NewRemote(host+"/test:1.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+			sboms := content.NewDescriptorFromBytes(zoci.ZarfLayerMediaTypeBlob, []byte("sboms"))
+			sboms.Annotations = map[string]string{ocispec.AnnotationTitle: layout.SBOMTar}
+			require.NoError(t, remote.Repo().Push(ctx, sboms, bytes.NewReader([]byte("sboms"))))
+			opts := oras.PackManifestOptions{Layers: []ocispec.Descriptor{sboms}}
+			subject, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, "application/vnd.zarf.test", opts)
+			require.NoError(t, err)
+			require.NoError(t, remote.Repo().Tag(ctx, subject, "1.0.0"))
+
+			dir := t.TempDir()
+			sbomPath := filepath.Join(dir, layout.SBOMTar)
+			require.NoError(t, os.WriteFile(sbomPath, []byte(tt.attachedSBOM), 0o644))
+			_, err = remote.AttachReferrer(ctx, subject, zoci.SBOMArtifactType, map[string]string{layout.SBOMTar: sbomPath}, nil)
+			require.NoError(t, err)
+			sigPath := filepath.Join(dir, layout.Signature)
+			require.NoError(t, os.WriteFile(sigPath, []byte("signature"), 0o644))
+			_, err = remote.AttachReferrer(ctx, subject, zoci.SignatureArtifactType, map[string]string{layout.Signature: sigPath}, nil)
+			require.NoError(t, err)
+
+			dst := layout.New(t.TempDir())
+			err = verifyReferrers(ctx, remote, dst, "", nil)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(dst.Base, layout.Signature), dst.Signature)
+			b, err := os.ReadFile(dst.Signature)
+			require.NoError(t, err)
+			require.Equal(t, "signature", string(b))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// StdinPackageSource is the package source that reads a package tarball from stdin.
+const StdinPackageSource = "-"
+
+// zstdMagic is the magic number that zstd frames start with.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	// verify that StdinSource implements PackageSource
+	_ PackageSource = (*StdinSource)(nil)
+)
+
+// StdinSource is a package source for a tarball streamed on stdin, e.g. `cat pkg.tar.zst | zarf package deploy -`.
+//
+// The tarball is extracted as it is read so that it is never written to disk as a whole.
+type StdinSource struct {
+	*types.ZarfPackageOptions
+	// Reader is the stream the tarball is read from, stdin is read when it is nil
+	Reader io.Reader
+}
+
+// reader returns the stream the tarball is read from and the hash of what has been read from it.
+func (s *StdinSource) reader() (io.Reader, hash.Hash) {
+	r := s.Reader
+	if r == nil {
+		r = os.Stdin
+	}
+	h := sha256.New()
+	return io.TeeReader(r, h), h
+}
+
+// verifyShasum compares the hash of the stream to the shasum of the package when one is set.
+func (s *StdinSource) verifyShasum(h hash.Hash) error {
+	if s.Shasum == "" {
+		return nil
+	}
+	if received := hex.EncodeToString(h.Sum(nil)); received != s.Shasum {
+		return fmt.Errorf("shasum mismatch for the package read from stdin: expected %s, got %s", s.Shasum, received)
+	}
+	return nil
+}
+
+// LoadPackage loads a package from a tarball streamed on stdin.
+func (s *StdinSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	spinner := message.NewProgressSpinner("Loading package from stdin")
+	defer spinner.Stop()
+
+	r, h := s.reader()
+	r, err = utils.DecryptAgeStream(r, s.DecryptIdentityPaths, s.DecryptPassphrase)
+	if err != nil {
+		return pkg, nil, err
+	}
+	pathsExtracted, err := extractTarStream(r, dst.Base)
+	if err != nil {
+		return pkg, nil, fmt.Errorf("unable to extract the package from stdin: %w", err)
+	}
+	if err := s.verifyShasum(h); err != nil {
+		return pkg, nil, err
+	}
+
+	pkg, warnings, err = loadExtractedPackage(ctx, dst, pathsExtracted, filter, unarchiveAll, s.PublicKeyPath, s.TrustedKeys)
+	if err != nil {
+		return pkg, nil, err
+	}
+
+	spinner.Success()
+
+	return pkg, warnings, nil
+}
+
+// LoadPackageMetadata loads a package's metadata from a tarball streamed on stdin.
+func (s *StdinSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return pkg, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dstTarball, err := s.Collect(ctx, tmp)
+	if err != nil {
+		return pkg, nil, err
+	}
+
+	ts := &TarballSource{
+		&types.ZarfPackageOptions{
+			PackageSource: dstTarball,
+			PublicKeyPath: s.PublicKeyPath,
+			TrustedKeys:   s.TrustedKeys,
+		},
+	}
+
+	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, skipValidation)
+}
+
+// Collect writes a tarball streamed on stdin to the destination directory.
+func (s *StdinSource) Collect(_ context.Context, dir string) (string, error) {
+	dstTarball := filepath.Join(dir, "zarf-package-stdin-unknown")
+	f, err := os.Create(dstTarball)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r, h := s.reader()
+	r, err = utils.DecryptAgeStream(r, s.DecryptIdentityPaths, s.DecryptPassphrase)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("unable to read the package from stdin: %w", err)
+	}
+	if err := s.verifyShasum(h); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return RenameFromMetadata(dstTarball)
+}
+
+// extractTarStream extracts a tar or zstd compressed tar stream to dir, returning the paths of the files extracted.
+// The rest of the stream is read once the tar ends so that it is fully hashed.
+func extractTarStream(r io.Reader, dir string) ([]string, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the package tarball: %w", err)
+	}
+	var tr *tar.Reader
+	if bytes.Equal(magic, zstdMagic) {
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		tr = tar.NewReader(zr)
+	} else {
+		tr = tar.NewReader(br)
+	}
+
+	pathsExtracted := []string{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Clean(header.Name)
+		if !filepath.IsLocal(path) {
+			return nil, fmt.Errorf("the package tarball contains the path %s outside of the package", header.Name)
+		}
+
+		dstPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(dstPath), helpers.ReadExecuteAllWriteUser); err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tr, dstPath); err != nil {
+			return nil, err
+		}
+		pathsExtracted = append(pathsExtracted, path)
+	}
+
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return nil, err
+	}
+	return pathsExtracted, nil
+}
+
+// writeTarFile writes the current file of a tar reader to dstPath.
+func writeTarFile(tr *tar.Reader, dstPath string) error {
+	f, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, tr); err != nil {
+		return err
+	}
+	return f.Close()
+}
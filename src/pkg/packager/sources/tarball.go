@@ -12,13 +12,16 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/mholt/archiver/v3"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -44,9 +47,15 @@ func (s *TarballSource) LoadPackage(ctx context.Context, dst *layout.PackagePath
 		}
 	}
 
+	tarball, cleanup, err := decryptTarball(s.PackageSource, s.ZarfPackageOptions)
+	if err != nil {
+		return pkg, nil, err
+	}
+	defer cleanup()
+
 	pathsExtracted := []string{}
 
-	err = archiver.Walk(s.PackageSource, func(f archiver.File) error {
+	err = archiver.Walk(tarball, func(f archiver.File) error {
 		if f.IsDir() {
 			return nil
 		}
@@ -82,57 +91,11 @@ func (s *TarballSource) LoadPackage(ctx context.Context, dst *layout.PackagePath
 		return pkg, nil, err
 	}
 
-	dst.SetFromPaths(pathsExtracted)
-
-	pkg, warnings, err = dst.ReadZarfYAML()
-	if err != nil {
-		return pkg, nil, err
-	}
-	pkg.Components, err = filter.Apply(pkg)
+	pkg, warnings, err = loadExtractedPackage(ctx, dst, pathsExtracted, filter, unarchiveAll, s.PublicKeyPath, s.TrustedKeys)
 	if err != nil {
 		return pkg, nil, err
 	}
 
-	if err := dst.MigrateLegacy(); err != nil {
-		return pkg, nil, err
-	}
-
-	if !dst.IsLegacyLayout() {
-		spinner := message.NewProgressSpinner("Validating full package checksums")
-		defer spinner.Stop()
-
-		if err := ValidatePackageIntegrity(dst, pkg.Metadata.AggregateChecksum, false); err != nil {
-			return pkg, nil, err
-		}
-
-		spinner.Success()
-
-		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
-			return pkg, nil, err
-		}
-	}
-
-	if unarchiveAll {
-		for _, component := range pkg.Components {
-			if err := dst.Components.Unarchive(component); err != nil {
-				if errors.Is(err, layout.ErrNotLoaded) {
-					_, err := dst.Components.Create(component)
-					if err != nil {
-						return pkg, nil, err
-					}
-				} else {
-					return pkg, nil, err
-				}
-			}
-		}
-
-		if dst.SBOMs.Path != "" {
-			if err := dst.SBOMs.Unarchive(); err != nil {
-				return pkg, nil, err
-			}
-		}
-	}
-
 	spinner.Success()
 
 	return pkg, warnings, nil
@@ -146,14 +109,20 @@ func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.Pac
 		}
 	}
 
+	tarball, cleanup, err := decryptTarball(s.PackageSource, s.ZarfPackageOptions)
+	if err != nil {
+		return pkg, nil, err
+	}
+	defer cleanup()
+
 	toExtract := zoci.PackageAlwaysPull
 	if wantSBOM {
-		toExtract = append(toExtract, layout.SBOMTar)
+		toExtract = append(toExtract, layout.SBOMTarballs...)
 	}
 	pathsExtracted := []string{}
 
 	for _, rel := range toExtract {
-		if err := archiver.Extract(s.PackageSource, rel, dst.Base); err != nil {
+		if err := archiver.Extract(tarball, rel, dst.Base); err != nil {
 			return pkg, nil, err
 		}
 		// archiver.Extract will not return an error if the file does not exist, so we must manually check
@@ -185,7 +154,7 @@ func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.Pac
 			spinner.Success()
 		}
 
-		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
+		if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.TrustedKeys); err != nil {
 			if errors.Is(err, ErrPkgSigButNoKey) && skipValidation {
 				message.Warn("The package was signed but no public key was provided, skipping signature validation")
 			} else {
@@ -237,3 +206,93 @@ func (s *TarballSource) Collect(_ context.Context, dir string) (string, error) {
 	}
 	return dst, nil
 }
+
+// decryptTarball decrypts the package tarball into a temporary directory when it is encrypted with age, returning the
+// path of the decrypted tarball and a function that removes it.
+func decryptTarball(path string, pkgOpts *types.ZarfPackageOptions) (string, func(), error) {
+	encrypted, err := utils.IsAgeEncrypted(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !encrypted {
+		return path, func() {}, nil
+	}
+
+	spinner := message.NewProgressSpinner("Decrypting package %q", path)
+	defer spinner.Stop()
+
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return "", nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), utils.AgeSuffix)
+	if !IsValidFileExtension(name) {
+		name += ".tar.zst"
+	}
+	decrypted := filepath.Join(tmp, name)
+	if err := utils.DecryptAgeFile(path, decrypted, pkgOpts.DecryptIdentityPaths, pkgOpts.DecryptPassphrase); err != nil {
+		os.RemoveAll(tmp)
+		return "", nil, err
+	}
+
+	spinner.Success()
+
+	return decrypted, func() { os.RemoveAll(tmp) }, nil
+}
+
+// loadExtractedPackage loads a package whose tarball was extracted to the paths of dst, validating its integrity and
+// signature before unarchiving its components.
+func loadExtractedPackage(ctx context.Context, dst *layout.PackagePaths, pathsExtracted []string, filter filters.ComponentFilterStrategy, unarchiveAll bool, publicKeyPath string, trustedKeys []string) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	dst.SetFromPaths(pathsExtracted)
+
+	pkg, warnings, err = dst.ReadZarfYAML()
+	if err != nil {
+		return pkg, nil, err
+	}
+	pkg.Components, err = filter.Apply(pkg)
+	if err != nil {
+		return pkg, nil, err
+	}
+
+	if err := dst.MigrateLegacy(); err != nil {
+		return pkg, nil, err
+	}
+
+	if !dst.IsLegacyLayout() {
+		spinner := message.NewProgressSpinner("Validating full package checksums")
+		defer spinner.Stop()
+
+		if err := ValidatePackageIntegrity(dst, pkg.Metadata.AggregateChecksum, false); err != nil {
+			return pkg, nil, err
+		}
+
+		spinner.Success()
+
+		if err := ValidatePackageSignature(ctx, dst, publicKeyPath, trustedKeys); err != nil {
+			return pkg, nil, err
+		}
+	}
+
+	if unarchiveAll {
+		for _, component := range pkg.Components {
+			if err := dst.Components.Unarchive(component); err != nil {
+				if errors.Is(err, layout.ErrNotLoaded) {
+					_, err := dst.Components.Create(component)
+					if err != nil {
+						return pkg, nil, err
+					}
+				} else {
+					return pkg, nil, err
+				}
+			}
+		}
+
+		if dst.SBOMs.Path != "" {
+			if err := dst.SBOMs.Unarchive(); err != nil {
+				return pkg, nil, err
+			}
+		}
+	}
+
+	return pkg, warnings, nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 )
 
@@ -25,8 +26,10 @@ func GetValidPackageExtensions() [2]string {
 	return [...]string{".tar.zst", ".tar"}
 }
 
-// IsValidFileExtension returns true if the filename has a valid package extension.
+// IsValidFileExtension returns true if the filename has a valid package extension, which may be followed by the
+// extension of an encrypted package.
 func IsValidFileExtension(filename string) bool {
+	filename = strings.TrimSuffix(filename, utils.AgeSuffix)
 	for _, extension := range GetValidPackageExtensions() {
 		if strings.HasSuffix(filename, extension) {
 			return true
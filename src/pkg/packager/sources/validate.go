@@ -18,6 +18,7 @@ import (
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/trust"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
@@ -26,10 +27,15 @@ var (
 	ErrPkgKeyButNoSig = errors.New("a key was provided but the package is not signed - the package may be corrupted or the --key flag was erroneously specified")
 	// ErrPkgSigButNoKey is returned when a package is signed but no key was provided
 	ErrPkgSigButNoKey = errors.New("package is signed but no key was provided - add a key with the --key flag or use the --insecure flag and run the command again")
+	// ErrPkgSigNotTrusted is returned when a package is not signed by any package signing key of the trust root of the cluster
+	ErrPkgSigNotTrusted = errors.New("package is not signed by any package signing key of the trust root of the cluster - add a key with the --key flag or use the --insecure flag and run the command again")
+	// ErrPkgTrustedButNoSig is returned when the cluster has a trust root but the package is not signed
+	ErrPkgTrustedButNoSig = errors.New("the cluster has a trust root but the package is not signed - sign the package with a package signing key of the trust root or use the --insecure flag and run the command again")
 )
 
-// ValidatePackageSignature validates the signature of a package
-func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, publicKeyPath string) error {
+// ValidatePackageSignature validates the signature of a package with the public key, or with the trusted keys when
+// no public key was provided
+func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, publicKeyPath string, trustedKeys []string) error {
 	// If the insecure flag was provided ignore the signature validation
 	if config.CommonOptions.Insecure {
 		return nil
@@ -41,6 +47,12 @@ func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, p
 
 	// Handle situations where there is no signature within the package
 	sigExist := paths.Signature != ""
+	if publicKeyPath == "" && len(trustedKeys) > 0 {
+		if !sigExist {
+			return ErrPkgTrustedButNoSig
+		}
+		return validateTrustedSignature(paths, trustedKeys)
+	}
 	if !sigExist && publicKeyPath == "" {
 		// Nobody was expecting a signature, so we can just return
 		return nil
@@ -60,6 +72,26 @@ func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, p
 	return nil
 }
 
+// validateTrustedSignature validates that the package is signed by one of the trusted keys.
+func validateTrustedSignature(paths *layout.PackagePaths, trustedKeys []string) error {
+	blob, err := os.ReadFile(paths.ZarfYAML)
+	if err != nil {
+		return err
+	}
+	sig, err := os.ReadFile(paths.Signature)
+	if err != nil {
+		return err
+	}
+	for _, key := range trustedKeys {
+		if err := trust.VerifyBlob(key, blob, sig); err != nil {
+			continue
+		}
+		message.Successf("Package signature validated with the trust root of the cluster!")
+		return nil
+	}
+	return ErrPkgSigNotTrusted
+}
+
 // ValidatePackageIntegrity validates the integrity of a package by comparing checksums
 func ValidatePackageIntegrity(loaded *layout.PackagePaths, aggregateChecksum string, isPartial bool) error {
 	// ensure checksums.txt and zarf.yaml were loaded
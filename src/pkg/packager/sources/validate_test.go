@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+func TestValidatePackageSignatureTrustedKeys(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() (string, ed25519.PrivateKey) {
+		pub, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), private
+	}
+	signingKey, private := newKey()
+	otherKey, _ := newKey()
+
+	dir := t.TempDir()
+	paths := layout.New(dir)
+	blob := []byte("kind: ZarfPackageConfig\n")
+	require.NoError(t, os.WriteFile(paths.ZarfYAML, blob, 0o644))
+	paths.Signature = filepath.Join(dir, layout.Signature)
+	require.NoError(t, os.WriteFile(paths.Signature, []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, blob))), 0o644))
+
+	tests := []struct {
+		name          string
+		signed        bool
+		trustedKeys   []string
+		expectedError error
+	}{
+		{
+			name:        "signed by a trusted key",
+			signed:      true,
+			trustedKeys: []string{otherKey, signingKey},
+		},
+		{
+			name:          "not signed by a trusted key",
+			signed:        true,
+			trustedKeys:   []string{otherKey},
+			expectedError: ErrPkgSigNotTrusted,
+		},
+		{
+			name:          "signed without trusted keys",
+			signed:        true,
+			expectedError: ErrPkgSigButNoKey,
+		},
+		{
+			name:          "not signed",
+			trustedKeys:   []string{signingKey},
+			expectedError: ErrPkgTrustedButNoSig,
+		},
+		{
+			name: "not signed without trusted keys",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths := *paths
+			if !tt.signed {
+				paths.Signature = ""
+			}
+			err := ValidatePackageSignature(context.Background(), &paths, "", tt.trustedKeys)
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
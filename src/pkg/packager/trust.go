@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/trust"
+)
+
+// loadTrustedKeys sets the package signing keys of the trust root of the cluster to validate the signature of the
+// package with when no public key was provided. Clusters that can't be reached or never had a trust root are skipped.
+func (p *Packager) loadTrustedKeys(ctx context.Context) error {
+	if p.cfg.PkgOpts.PublicKeyPath != "" || config.CommonOptions.Insecure {
+		return nil
+	}
+	c := p.cluster
+	if c == nil {
+		var err error
+		c, err = cluster.NewCluster()
+		if err != nil {
+			message.Debugf("Not using a trust root, unable to connect to the cluster: %s", err.Error())
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	root, err := c.LoadTrustRoot(ctx)
+	if err != nil {
+		return fmt.Errorf(lang.PkgDeployErrTrustRoot, err)
+	}
+	if root == nil {
+		return nil
+	}
+	if err := root.Verify(time.Now()); err != nil {
+		return fmt.Errorf(lang.PkgDeployErrTrustRoot, err)
+	}
+	message.Debugf("Using the %d package signing keys of version %d of the trust root of the cluster", len(root.PackageKeys()), root.Signed.Version)
+	p.cfg.PkgOpts.TrustedKeys = root.PackageKeys()
+	return nil
+}
+
+// deployTrustRoot makes the cluster trust the trust root of the component. The trust root it replaces must have signed
+// it, or the trust root pinned with --trust-root when the cluster has none.
+func (p *Packager) deployTrustRoot(ctx context.Context, component v1alpha1.ZarfComponent) error {
+	if len(component.Files) != 1 {
+		return fmt.Errorf(lang.PkgDeployErrTrustRootFile, component.Name)
+	}
+	if err := p.connectToCluster(ctx); err != nil {
+		return err
+	}
+
+	spinner := message.NewProgressSpinner(lang.PkgDeployTrustRoot)
+	defer spinner.Stop()
+
+	path := filepath.Join(p.layout.Components.Dirs[component.Name].Files, "0", filepath.Base(component.Files[0].Target))
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	root, err := trust.Parse(b)
+	if err != nil {
+		return err
+	}
+	var pinned *trust.Root
+	if p.cfg.DeployOpts.TrustRootPath != "" {
+		b, err := os.ReadFile(p.cfg.DeployOpts.TrustRootPath)
+		if err != nil {
+			return err
+		}
+		pinned, err = trust.Parse(b)
+		if err != nil {
+			return err
+		}
+	}
+	if err := p.cluster.UpdateTrustRoot(ctx, root, pinned); err != nil {
+		return err
+	}
+	spinner.Successf(lang.PkgDeployTrustRootUpdated, root.Signed.Version, len(root.PackageKeys()))
+	return nil
+}
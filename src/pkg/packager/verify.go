@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+// Verify validates the signature of a package and, when requested, the SLSA provenance attached to it.
+func (p *Packager) Verify(ctx context.Context) (err error) {
+	src, isOCI := p.source.(*sources.OCISource)
+	if p.cfg.VerifyOpts.Provenance && !isOCI {
+		return fmt.Errorf(lang.CmdPackageVerifyErrProvenance, p.cfg.PkgOpts.PackageSource)
+	}
+
+	p.cfg.Pkg, _, err = p.source.LoadPackageMetadata(ctx, p.layout, false, false)
+	if err != nil {
+		return err
+	}
+	message.Successf(lang.CmdPackageVerifySuccess, p.cfg.PkgOpts.PackageSource)
+
+	if !p.cfg.VerifyOpts.Provenance {
+		return nil
+	}
+	subject, err := src.ResolveRoot(ctx)
+	if err != nil {
+		return err
+	}
+	statement, err := src.FetchProvenance(ctx, subject)
+	if err != nil {
+		return err
+	}
+	if err := zoci.VerifyProvenance(statement, &p.cfg.Pkg, subject); err != nil {
+		return err
+	}
+	message.Successf(lang.CmdPackageVerifyProvenance, p.cfg.PkgOpts.PackageSource)
+	return nil
+}
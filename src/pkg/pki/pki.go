@@ -7,12 +7,16 @@ package pki
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -154,3 +158,80 @@ func generateCert(host string, ca *x509.Certificate, caKey *rsa.PrivateKey, vali
 
 	return cert, privateKey, nil
 }
+
+// LoadOrGeneratePKI returns the CA and server keypair stored as ca.pem, cert.pem and key.pem in the directory, and
+// generates and stores new ones when they are missing or the certificate expires within a day.
+func LoadOrGeneratePKI(dir, host string, dnsNames ...string) (types.GeneratedPKI, error) {
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	ca, caErr := os.ReadFile(caPath)
+	cert, certErr := os.ReadFile(certPath)
+	key, keyErr := os.ReadFile(keyPath)
+	if caErr == nil && certErr == nil && keyErr == nil {
+		if block, _ := pem.Decode(cert); block != nil {
+			parsed, err := x509.ParseCertificate(block.Bytes)
+			if err == nil && time.Now().Add(24*time.Hour).Before(parsed.NotAfter) {
+				return types.GeneratedPKI{CA: ca, Cert: cert, Key: key}, nil
+			}
+		}
+	}
+
+	results, err := GeneratePKI(host, dnsNames...)
+	if err != nil {
+		return types.GeneratedPKI{}, err
+	}
+	if err := helpers.CreateDirectory(dir, helpers.ReadWriteExecuteUser); err != nil {
+		return types.GeneratedPKI{}, err
+	}
+	for path, data := range map[string][]byte{caPath: results.CA, certPath: results.Cert, keyPath: results.Key} {
+		if err := os.WriteFile(path, data, helpers.ReadWriteUser); err != nil {
+			return types.GeneratedPKI{}, err
+		}
+	}
+	return results, nil
+}
+
+// ValidatePKI checks that the certificate and key of a keypair belong together, that the certificate is valid for host
+// and that it can be verified with the CA, including any intermediate certificates that follow it.
+func ValidatePKI(keypair types.GeneratedPKI, host string) error {
+	pair, err := tls.X509KeyPair(keypair.Cert, keypair.Key)
+	if err != nil {
+		return fmt.Errorf("the certificate and key are not a valid keypair: %w", err)
+	}
+	block, _ := pem.Decode(keypair.CA)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("the CA is not a PEM encoded certificate")
+	}
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("unable to parse the CA: %w", err)
+	}
+	certs := []*x509.Certificate{}
+	for _, der := range pair.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("unable to parse the certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if err := certs[0].VerifyHostname(host); err != nil {
+		return fmt.Errorf("the certificate is not valid for %s: %w", host, err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("the certificate can not be verified with the CA: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package pki
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrGeneratePKI(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "tls")
+	generated, err := LoadOrGeneratePKI(dir, "localhost")
+	require.NoError(t, err)
+	for _, name := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.NotEmpty(t, b)
+	}
+
+	// The stored keypair is reused while it is valid
+	loaded, err := LoadOrGeneratePKI(dir, "localhost")
+	require.NoError(t, err)
+	require.Equal(t, generated, loaded)
+
+	// A corrupt keypair is replaced
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), []byte("corrupt"), 0o600))
+	regenerated, err := LoadOrGeneratePKI(dir, "localhost")
+	require.NoError(t, err)
+	require.NotEqual(t, generated.CA, regenerated.CA)
+}
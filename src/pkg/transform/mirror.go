@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package transform provides helper functions to transform URLs to airgap equivalents
+package transform
+
+import (
+	"strings"
+)
+
+// MirrorImageRef returns the reference of an image on a mirror of the given upstream registry (optionally followed by a repository path),
+// or false when the image is not from upstream.
+func MirrorImageRef(image Image, upstream, mirror string) (string, bool) {
+	rest, ok := strings.CutPrefix(image.Reference, strings.TrimSuffix(upstream, "/"))
+	if !ok || !strings.HasPrefix(rest, "/") {
+		return "", false
+	}
+	return strings.TrimSuffix(mirror, "/") + rest, true
+}
+
+// UpstreamImageRef returns the upstream reference for an image pulled from a mirror, where mirrors maps upstream registries to the addresses of their mirrors.
+// References that are not on a mirror are returned unchanged.
+func UpstreamImageRef(srcReference string, mirrors map[string][]string) (string, error) {
+	if len(mirrors) == 0 {
+		return srcReference, nil
+	}
+	image, err := ParseImageRef(srcReference)
+	if err != nil {
+		return "", err
+	}
+
+	// The most specific mirror address wins when several of them match
+	upstreamRef := srcReference
+	matched := ""
+	for upstream, addresses := range mirrors {
+		for _, address := range addresses {
+			ref, ok := MirrorImageRef(image, address, upstream)
+			if ok && len(address) > len(matched) {
+				upstreamRef = ref
+				matched = address
+			}
+		}
+	}
+	return upstreamRef, nil
+}
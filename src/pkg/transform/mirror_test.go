@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorImageRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		image       string
+		upstream    string
+		mirror      string
+		expectedRef string
+		expectedOk  bool
+	}{
+		{
+			name:        "registry mirror",
+			image:       "nginx:1.25",
+			upstream:    "docker.io",
+			mirror:      "mirror.example.com",
+			expectedRef: "mirror.example.com/library/nginx:1.25",
+			expectedOk:  true,
+		},
+		{
+			name:        "mirror with a path",
+			image:       "ghcr.io/stefanprodan/podinfo:6.4.0",
+			upstream:    "ghcr.io",
+			mirror:      "mirror.example.com/ghcr/",
+			expectedRef: "mirror.example.com/ghcr/stefanprodan/podinfo:6.4.0",
+			expectedOk:  true,
+		},
+		{
+			name:        "upstream with a path",
+			image:       "docker.io/library/alpine@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a",
+			upstream:    "docker.io/library",
+			mirror:      "mirror.example.com/official",
+			expectedRef: "mirror.example.com/official/alpine@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a",
+			expectedOk:  true,
+		},
+		{
+			name:       "different registry",
+			image:      "quay.io/prometheus/prometheus:v2.45.0",
+			upstream:   "docker.io",
+			mirror:     "mirror.example.com",
+			expectedOk: false,
+		},
+		{
+			name:       "registry prefix is not a match",
+			image:      "ghcr.io.example.com/podinfo:6.4.0",
+			upstream:   "ghcr.io",
+			mirror:     "mirror.example.com",
+			expectedOk: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			image, err := ParseImageRef(tt.image)
+			require.NoError(t, err)
+			ref, ok := MirrorImageRef(image, tt.upstream, tt.mirror)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expectedRef, ref)
+		})
+	}
+}
+
+func TestUpstreamImageRef(t *testing.T) {
+	t.Parallel()
+
+	mirrors := map[string][]string{
+		"docker.io": {"mirror.example.com", "backup.example.com/dockerhub"},
+		"ghcr.io":   {"mirror.example.com/ghcr"},
+	}
+
+	tests := []struct {
+		name        string
+		image       string
+		expectedRef string
+	}{
+		{
+			name:        "first mirror",
+			image:       "mirror.example.com/library/nginx:1.25",
+			expectedRef: "docker.io/library/nginx:1.25",
+		},
+		{
+			name:        "fallback mirror",
+			image:       "backup.example.com/dockerhub/library/nginx:1.25",
+			expectedRef: "docker.io/library/nginx:1.25",
+		},
+		{
+			name:        "most specific mirror",
+			image:       "mirror.example.com/ghcr/stefanprodan/podinfo:6.4.0",
+			expectedRef: "ghcr.io/stefanprodan/podinfo:6.4.0",
+		},
+		{
+			name:        "not mirrored",
+			image:       "quay.io/prometheus/prometheus:v2.45.0",
+			expectedRef: "quay.io/prometheus/prometheus:v2.45.0",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := UpstreamImageRef(tt.image, mirrors)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRef, ref)
+		})
+	}
+}
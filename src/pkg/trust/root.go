@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package trust contains the TUF-style trust root that distributes the keys packages are signed with.
+package trust
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// RootType is the type of the signed metadata of a trust root.
+	RootType = "root"
+	// RootRole is the role of the keys that sign the trust root and its rotations.
+	RootRole = "root"
+	// PackagesRole is the role of the keys that sign packages.
+	PackagesRole = "packages"
+)
+
+// Root is a trust root, the package signing keys it lists are trusted when a threshold of its root keys signed it.
+type Root struct {
+	Signed     RootMetadata `json:"signed"`
+	Signatures []Signature  `json:"signatures"`
+}
+
+// RootMetadata is the signed content of a trust root.
+type RootMetadata struct {
+	Type    string    `json:"_type"`
+	Version int       `json:"version"`
+	Expires time.Time `json:"expires"`
+	// Keys by their key ID, the hex encoded SHA256 digest of the DER encoded public key
+	Keys  map[string]Key  `json:"keys"`
+	Roles map[string]Role `json:"roles"`
+}
+
+// Key is a public key of a trust root.
+type Key struct {
+	// PEM encoded public key, ECDSA (e.g. generated by zarf tools gen-key), Ed25519 or RSA
+	Public string `json:"public"`
+}
+
+// Role lists the keys of a role and how many of them have to sign.
+type Role struct {
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+}
+
+// Signature is the base64 encoded signature of the signed metadata of a trust root by one of its keys.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// New returns an unsigned trust root of the root keys and package signing keys.
+func New(version int, expires time.Time, rootKeys [][]byte, rootThreshold int, packageKeys [][]byte) (*Root, error) {
+	r := &Root{
+		Signed: RootMetadata{
+			Type:    RootType,
+			Version: version,
+			Expires: expires.UTC().Truncate(time.Second),
+			Keys:    map[string]Key{},
+			Roles: map[string]Role{
+				RootRole:     {KeyIDs: []string{}, Threshold: rootThreshold},
+				PackagesRole: {KeyIDs: []string{}, Threshold: 1},
+			},
+		},
+		Signatures: []Signature{},
+	}
+	for role, keys := range map[string][][]byte{RootRole: rootKeys, PackagesRole: packageKeys} {
+		for _, key := range keys {
+			id, err := KeyID(key)
+			if err != nil {
+				return nil, err
+			}
+			r.Signed.Keys[id] = Key{Public: string(key)}
+			roleKeys := r.Signed.Roles[role]
+			if !slices.Contains(roleKeys.KeyIDs, id) {
+				roleKeys.KeyIDs = append(roleKeys.KeyIDs, id)
+				slices.Sort(roleKeys.KeyIDs)
+			}
+			r.Signed.Roles[role] = roleKeys
+		}
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Parse reads a trust root and checks that it is well formed, it does not verify its signatures.
+func Parse(b []byte) (*Root, error) {
+	r := &Root{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("unable to read the trust root: %w", err)
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Marshal returns the trust root as indented JSON.
+func (r *Root) Marshal() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// SignedBytes returns the bytes the keys of the trust root sign, the compact JSON encoding of its signed metadata.
+func (r *Root) SignedBytes() ([]byte, error) {
+	return json.Marshal(r.Signed)
+}
+
+// AddSignature adds the signature of the signed metadata by one of the keys of the trust root, or of the trusted roots
+// it rotates, replacing an earlier signature of that key. It returns the ID of the key that made the signature.
+func (r *Root) AddSignature(sig []byte, trusted ...*Root) (string, error) {
+	msg, err := r.SignedBytes()
+	if err != nil {
+		return "", err
+	}
+	keys := maps.Clone(r.Signed.Keys)
+	for _, t := range trusted {
+		maps.Copy(keys, t.Signed.Keys)
+	}
+	ids := []string{}
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if verifySignature(keys[id].Public, msg, sig) != nil {
+			continue
+		}
+		r.Signatures = slices.DeleteFunc(r.Signatures, func(s Signature) bool { return s.KeyID == id })
+		r.Signatures = append(r.Signatures, Signature{KeyID: id, Sig: base64.StdEncoding.EncodeToString(sig)})
+		return id, nil
+	}
+	return "", errors.New("the signature was not made by any key of the trust root or the trusted roots")
+}
+
+// Verify checks that a threshold of the root keys of the trust root signed it and that it has not expired.
+func (r *Root) Verify(now time.Time) error {
+	if err := r.verifyRole(r.Signed.Roles[RootRole], r.Signed.Keys); err != nil {
+		return fmt.Errorf("trust root version %d is not signed by its own root keys: %w", r.Signed.Version, err)
+	}
+	if r.Expired(now) {
+		return fmt.Errorf("trust root version %d expired at %s", r.Signed.Version, r.Signed.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// VerifyUpdate checks that the trust root can replace the trusted root. A threshold of the root keys of both roots
+// must have signed it, and its version must be newer so an old root can not be rolled back to.
+func (r *Root) VerifyUpdate(trusted *Root, now time.Time) error {
+	if err := r.Verify(now); err != nil {
+		return err
+	}
+	if r.Signed.Version <= trusted.Signed.Version {
+		return fmt.Errorf("trust root version %d is not newer than the trusted version %d", r.Signed.Version, trusted.Signed.Version)
+	}
+	if err := r.verifyRole(trusted.Signed.Roles[RootRole], trusted.Signed.Keys); err != nil {
+		return fmt.Errorf("trust root version %d is not signed by the root keys of the trusted version %d: %w", r.Signed.Version, trusted.Signed.Version, err)
+	}
+	return nil
+}
+
+// Equal returns whether the trust roots have the same signed metadata.
+func (r *Root) Equal(other *Root) bool {
+	a, errA := r.SignedBytes()
+	b, errB := other.SignedBytes()
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// Expired returns whether the trust root has expired.
+func (r *Root) Expired(now time.Time) bool {
+	return !now.Before(r.Signed.Expires)
+}
+
+// PackageKeys returns the PEM encoded public keys that packages are signed with.
+func (r *Root) PackageKeys() []string {
+	keys := []string{}
+	for _, id := range r.Signed.Roles[PackagesRole].KeyIDs {
+		keys = append(keys, r.Signed.Keys[id].Public)
+	}
+	return keys
+}
+
+// VerifyBlob checks the base64 encoded signature of the blob, as made by cosign sign-blob, with the PEM encoded key.
+func VerifyBlob(key string, blob []byte, b64Sig []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b64Sig)))
+	if err != nil {
+		return fmt.Errorf("unable to decode the signature: %w", err)
+	}
+	return verifySignature(key, blob, sig)
+}
+
+// KeyID returns the ID of the PEM encoded public key.
+func KeyID(key []byte) (string, error) {
+	pub, err := parsePublicKey(string(key))
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func (r *Root) validate() error {
+	if r.Signed.Type != RootType {
+		return fmt.Errorf("unsupported trust root type %q, must be %q", r.Signed.Type, RootType)
+	}
+	if r.Signed.Version < 1 {
+		return fmt.Errorf("invalid trust root version %d, must be at least 1", r.Signed.Version)
+	}
+	for id, key := range r.Signed.Keys {
+		keyID, err := KeyID([]byte(key.Public))
+		if err != nil {
+			return fmt.Errorf("invalid key %s: %w", id, err)
+		}
+		if keyID != id {
+			return fmt.Errorf("the ID of key %s does not match the key", id)
+		}
+	}
+	for _, name := range []string{RootRole, PackagesRole} {
+		role, ok := r.Signed.Roles[name]
+		if !ok {
+			return fmt.Errorf("the trust root has no %s role", name)
+		}
+		if role.Threshold < 1 || role.Threshold > len(role.KeyIDs) {
+			return fmt.Errorf("invalid threshold %d of the %s role with %d keys", role.Threshold, name, len(role.KeyIDs))
+		}
+		for _, id := range role.KeyIDs {
+			if _, ok := r.Signed.Keys[id]; !ok {
+				return fmt.Errorf("the %s role lists the unknown key %s", name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyRole checks that a threshold of the keys of the role signed the trust root.
+func (r *Root) verifyRole(role Role, keys map[string]Key) error {
+	msg, err := r.SignedBytes()
+	if err != nil {
+		return err
+	}
+	signed := map[string]bool{}
+	for _, s := range r.Signatures {
+		key, ok := keys[s.KeyID]
+		if !ok || !slices.Contains(role.KeyIDs, s.KeyID) {
+			continue
+		}
+		if VerifyBlob(key.Public, msg, []byte(s.Sig)) == nil {
+			signed[s.KeyID] = true
+		}
+	}
+	if len(signed) < role.Threshold {
+		return fmt.Errorf("%d of the %d required signatures are valid", len(signed), role.Threshold)
+	}
+	return nil
+}
+
+func parsePublicKey(key string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("the key is not PEM encoded")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the public key: %w", err)
+	}
+	return pub, nil
+}
+
+func verifySignature(key string, msg, sig []byte) error {
+	pub, err := parsePublicKey(key)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(msg)
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, msg, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig)
+	default:
+		return fmt.Errorf("unsupported key type %T", pub)
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package trust contains the TUF-style trust root that distributes the keys packages are signed with.
+package trust
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	private *ecdsa.PrivateKey
+	public  []byte
+}
+
+func newTestKey(t *testing.T) testKey {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	return testKey{private: private, public: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}
+}
+
+func (k testKey) sign(t *testing.T, msg []byte) []byte {
+	t.Helper()
+
+	digest := sha256.Sum256(msg)
+	sig, err := ecdsa.SignASN1(rand.Reader, k.private, digest[:])
+	require.NoError(t, err)
+	return sig
+}
+
+func signRoot(t *testing.T, r *Root, keys ...testKey) {
+	t.Helper()
+
+	msg, err := r.SignedBytes()
+	require.NoError(t, err)
+	for _, k := range keys {
+		_, err := r.AddSignature(k.sign(t, msg))
+		require.NoError(t, err)
+	}
+}
+
+func TestRootVerifyUpdate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rootA, rootB, rootC, pkgKey := newTestKey(t), newTestKey(t), newTestKey(t), newTestKey(t)
+
+	trusted, err := New(1, now.Add(24*time.Hour), [][]byte{rootA.public, rootB.public}, 2, [][]byte{pkgKey.public})
+	require.NoError(t, err)
+	signRoot(t, trusted, rootA, rootB)
+	require.NoError(t, trusted.Verify(now))
+	require.EqualError(t, trusted.Verify(now.Add(24*time.Hour)), "trust root version 1 expired at 2026-01-02T00:00:00Z")
+
+	tests := []struct {
+		name          string
+		version       int
+		rootKeys      []testKey
+		signers       []testKey
+		expectedError string
+	}{
+		{
+			name:     "rotated root key",
+			version:  2,
+			rootKeys: []testKey{rootB, rootC},
+			signers:  []testKey{rootA, rootB, rootC},
+		},
+		{
+			name:          "not signed by the trusted root keys",
+			version:       2,
+			rootKeys:      []testKey{rootB, rootC},
+			signers:       []testKey{rootB, rootC},
+			expectedError: "trust root version 2 is not signed by the root keys of the trusted version 1: 1 of the 2 required signatures are valid",
+		},
+		{
+			name:          "not signed by its own root keys",
+			version:       2,
+			rootKeys:      []testKey{rootB, rootC},
+			signers:       []testKey{rootA, rootB},
+			expectedError: "trust root version 2 is not signed by its own root keys: 1 of the 2 required signatures are valid",
+		},
+		{
+			name:          "rollback",
+			version:       1,
+			rootKeys:      []testKey{rootA, rootB},
+			signers:       []testKey{rootA, rootB},
+			expectedError: "trust root version 1 is not newer than the trusted version 1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rootKeys := [][]byte{}
+			for _, k := range tt.rootKeys {
+				rootKeys = append(rootKeys, k.public)
+			}
+			updated, err := New(tt.version, now.Add(24*time.Hour), rootKeys, 2, [][]byte{pkgKey.public})
+			require.NoError(t, err)
+			msg, err := updated.SignedBytes()
+			require.NoError(t, err)
+			for _, k := range tt.signers {
+				_, err := updated.AddSignature(k.sign(t, msg), trusted)
+				require.NoError(t, err)
+			}
+
+			b, err := updated.Marshal()
+			require.NoError(t, err)
+			parsed, err := Parse(b)
+			require.NoError(t, err)
+			require.True(t, parsed.Equal(updated))
+
+			err = parsed.VerifyUpdate(trusted, now)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRootAddSignature(t *testing.T) {
+	t.Parallel()
+
+	rootKey, otherKey := newTestKey(t), newTestKey(t)
+	r, err := New(1, time.Now().Add(time.Hour), [][]byte{rootKey.public}, 1, [][]byte{rootKey.public})
+	require.NoError(t, err)
+	msg, err := r.SignedBytes()
+	require.NoError(t, err)
+
+	_, err = r.AddSignature(otherKey.sign(t, msg))
+	require.EqualError(t, err, "the signature was not made by any key of the trust root or the trusted roots")
+
+	// Signing again replaces the signature of the key
+	signRoot(t, r, rootKey, rootKey)
+	require.Len(t, r.Signatures, 1)
+	id, err := KeyID(rootKey.public)
+	require.NoError(t, err)
+	require.Equal(t, id, r.Signatures[0].KeyID)
+	require.Equal(t, []string{string(rootKey.public)}, r.PackageKeys())
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	key := newTestKey(t)
+	id, err := KeyID(key.public)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		root          string
+		expectedError string
+	}{
+		{
+			name:          "wrong type",
+			root:          `{"signed":{"_type":"targets","version":1}}`,
+			expectedError: `unsupported trust root type "targets", must be "root"`,
+		},
+		{
+			name:          "missing role",
+			root:          `{"signed":{"_type":"root","version":1,"roles":{"root":{"keyids":[],"threshold":0}}}}`,
+			expectedError: "invalid threshold 0 of the root role with 0 keys",
+		},
+		{
+			name:          "unknown key",
+			root:          `{"signed":{"_type":"root","version":1,"roles":{"root":{"keyids":["abc"],"threshold":1},"packages":{"keyids":["abc"],"threshold":1}}}}`,
+			expectedError: "the root role lists the unknown key abc",
+		},
+		{
+			name:          "mismatched key ID",
+			root:          `{"signed":{"_type":"root","version":1,"keys":{"abc":{"public":` + jsonString(t, key.public) + `}}}}`,
+			expectedError: "the ID of key abc does not match the key",
+		},
+		{
+			name: "valid",
+			root: `{"signed":{"_type":"root","version":1,"keys":{"` + id + `":{"public":` + jsonString(t, key.public) + `}},"roles":{"root":{"keyids":["` + id + `"],"threshold":1},"packages":{"keyids":["` + id + `"],"threshold":1}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse([]byte(tt.root))
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyBlob(t *testing.T) {
+	t.Parallel()
+
+	key, otherKey := newTestKey(t), newTestKey(t)
+	blob := []byte("kind: ZarfPackageConfig\n")
+	sig := []byte(base64.StdEncoding.EncodeToString(key.sign(t, blob)) + "\n")
+	require.NoError(t, VerifyBlob(string(key.public), blob, sig))
+	require.EqualError(t, VerifyBlob(string(otherKey.public), blob, sig), "invalid signature")
+	require.EqualError(t, VerifyBlob(string(key.public), []byte("kind: ZarfInitConfig\n"), sig), "invalid signature")
+}
+
+func jsonString(t *testing.T, b []byte) string {
+	t.Helper()
+
+	s, err := json.Marshal(string(b))
+	require.NoError(t, err)
+	return string(s)
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// AgeSuffix is the file extension of files encrypted with age.
+const AgeSuffix = ".age"
+
+// ageHeader is the first line of the binary format of files encrypted with age.
+var ageHeader = []byte("age-encryption.org/v1\n")
+
+// Encryptor wraps a writer so that what is written to it is encrypted.
+type Encryptor func(w io.Writer) (io.WriteCloser, error)
+
+// NewAgeEncryptor returns an encryptor for the age recipients (age1...), or for the passphrase when it is set. A
+// passphrase can not be combined with recipients.
+func NewAgeEncryptor(recipients []string, passphrase string) (Encryptor, error) {
+	if passphrase != "" && len(recipients) > 0 {
+		return nil, errors.New("a passphrase can not be combined with recipients")
+	}
+	parsed := []age.Recipient{}
+	if passphrase != "" {
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, recipient)
+	}
+	for _, r := range recipients {
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(r))
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", r, err)
+		}
+		parsed = append(parsed, recipient)
+	}
+	if len(parsed) == 0 {
+		return nil, errors.New("no recipients or passphrase to encrypt with")
+	}
+	return func(w io.Writer) (io.WriteCloser, error) {
+		return age.Encrypt(w, parsed...)
+	}, nil
+}
+
+// IsAgeEncrypted returns whether the file is encrypted with age.
+func IsAgeEncrypted(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	header := make([]byte, len(ageHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(header, ageHeader), nil
+}
+
+// NewAgeDecryptor returns a reader of the contents of the age encrypted reader, decrypted with the identities in the
+// age identity files or the passphrase.
+func NewAgeDecryptor(r io.Reader, identityPaths []string, passphrase string) (io.Reader, error) {
+	identities := []age.Identity{}
+	if passphrase != "" {
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	for _, path := range identityPaths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the age identity file: %w", err)
+		}
+		parsed, err := age.ParseIdentities(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid age identity file %s: %w", path, err)
+		}
+		identities = append(identities, parsed...)
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("the package is encrypted, provide an age identity file with --decrypt-identity or the passphrase with --decrypt-passphrase")
+	}
+	decrypted, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the package: %w", err)
+	}
+	return decrypted, nil
+}
+
+// DecryptAgeStream returns a reader of the stream decrypted with the identities in the age identity files or the
+// passphrase when it is encrypted with age, and of the stream as it is otherwise.
+func DecryptAgeStream(r io.Reader, identityPaths []string, passphrase string) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(ageHeader))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !bytes.Equal(header, ageHeader) {
+		return br, nil
+	}
+	return NewAgeDecryptor(br, identityPaths, passphrase)
+}
+
+// DecryptAgeFile decrypts the age encrypted file at src to dst with the identities in the age identity files or the
+// passphrase.
+func DecryptAgeFile(src, dst string, identityPaths []string, passphrase string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	decrypted, err := NewAgeDecryptor(bufio.NewReader(in), identityPaths, passphrase)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, out.Close())
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+	if _, err := io.Copy(out, decrypted); err != nil {
+		return fmt.Errorf("unable to decrypt the package: %w", err)
+	}
+	return nil
+}
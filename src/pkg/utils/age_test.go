@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeEncryption(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityPath := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(identityPath, []byte("# created for a test\n"+identity.String()+"\n"), 0o600))
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	otherPath := filepath.Join(dir, "other.txt")
+	require.NoError(t, os.WriteFile(otherPath, []byte(other.String()+"\n"), 0o600))
+
+	tests := []struct {
+		name              string
+		recipients        []string
+		passphrase        string
+		identityPaths     []string
+		decryptPassphrase string
+		expectedError     string
+	}{
+		{
+			name:          "recipient",
+			recipients:    []string{identity.Recipient().String()},
+			identityPaths: []string{identityPath},
+		},
+		{
+			name:          "one of several recipients",
+			recipients:    []string{other.Recipient().String(), identity.Recipient().String()},
+			identityPaths: []string{identityPath},
+		},
+		{
+			name:              "passphrase",
+			passphrase:        "correct horse battery staple",
+			decryptPassphrase: "correct horse battery staple",
+		},
+		{
+			name:          "wrong identity",
+			recipients:    []string{identity.Recipient().String()},
+			identityPaths: []string{otherPath},
+			expectedError: "unable to decrypt the package: no identity matched any of the recipients",
+		},
+		{
+			name:              "wrong passphrase",
+			passphrase:        "correct horse battery staple",
+			decryptPassphrase: "incorrect",
+			expectedError:     "unable to decrypt the package: no identity matched any of the recipients",
+		},
+		{
+			name:          "no identity",
+			recipients:    []string{identity.Recipient().String()},
+			expectedError: "the package is encrypted, provide an age identity file with --decrypt-identity or the passphrase with --decrypt-passphrase",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encrypt, err := NewAgeEncryptor(tt.recipients, tt.passphrase)
+			require.NoError(t, err)
+			src := filepath.Join(t.TempDir(), "package.tar.zst.age")
+			f, err := os.Create(src)
+			require.NoError(t, err)
+			w, err := encrypt(f)
+			require.NoError(t, err)
+			_, err = w.Write([]byte("package contents"))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			require.NoError(t, f.Close())
+
+			encrypted, err := IsAgeEncrypted(src)
+			require.NoError(t, err)
+			require.True(t, encrypted)
+
+			dst := filepath.Join(t.TempDir(), "package.tar.zst")
+			err = DecryptAgeFile(src, dst, tt.identityPaths, tt.decryptPassphrase)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				require.NoFileExists(t, dst)
+				return
+			}
+			require.NoError(t, err)
+			b, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, "package contents", string(b))
+		})
+	}
+}
+
+func TestNewAgeEncryptorErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAgeEncryptor(nil, "")
+	require.EqualError(t, err, "no recipients or passphrase to encrypt with")
+	_, err = NewAgeEncryptor([]string{"age1invalid"}, "")
+	require.ErrorContains(t, err, `invalid age recipient "age1invalid"`)
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = NewAgeEncryptor([]string{identity.Recipient().String()}, "passphrase")
+	require.EqualError(t, err, "a passphrase can not be combined with recipients")
+}
+
+func TestDecryptAgeStream(t *testing.T) {
+	t.Parallel()
+
+	// Streams that are not encrypted are read as they are
+	r, err := DecryptAgeStream(strings.NewReader("plain"), nil, "")
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "plain", string(b))
+
+	encrypt, err := NewAgeEncryptor(nil, "passphrase")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := encrypt(&buf)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("secret"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err = DecryptAgeStream(&buf, nil, "passphrase")
+	require.NoError(t, err)
+	b, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(b))
+
+	plain := filepath.Join(t.TempDir(), "plain.tar")
+	require.NoError(t, os.WriteFile(plain, []byte("tar"), 0o600))
+	encrypted, err := IsAgeEncrypted(plain)
+	require.NoError(t, err)
+	require.False(t, encrypted)
+}
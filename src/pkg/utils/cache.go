@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CachePruneOptions controls which entries of a cache directory are removed by PruneCacheDir.
+type CachePruneOptions struct {
+	// Remove entries that have not been used for longer than this duration, zero disables the age check
+	OlderThan time.Duration
+	// Remove the least recently used entries until the directory is at most this many bytes, zero disables the size check
+	MaxSize int64
+}
+
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// CacheStats describes the top level entries of a cache directory.
+type CacheStats struct {
+	Entries int
+	Size    int64
+	// LastUsed is when the most recently used entry was last used, it is zero when the cache is empty
+	LastUsed time.Time
+	// LeastRecentlyUsed is when the least recently used entry was last used, it is zero when the cache is empty
+	LeastRecentlyUsed time.Time
+}
+
+// CacheDirStats returns the stats of the top level entries of a cache directory, an empty cache is returned when it does not exist.
+func CacheDirStats(dir string) (CacheStats, error) {
+	stats := CacheStats{}
+	entries, total, err := readCacheEntries(dir)
+	if err != nil {
+		return stats, err
+	}
+	stats.Entries = len(entries)
+	stats.Size = total
+	for _, entry := range entries {
+		if stats.LastUsed.IsZero() || entry.modTime.After(stats.LastUsed) {
+			stats.LastUsed = entry.modTime
+		}
+		if stats.LeastRecentlyUsed.IsZero() || entry.modTime.Before(stats.LeastRecentlyUsed) {
+			stats.LeastRecentlyUsed = entry.modTime
+		}
+	}
+	return stats, nil
+}
+
+// PruneCacheDir removes the top level entries of a cache directory that match the given options, least recently used first.
+//
+// It returns the number of bytes that were freed.
+func PruneCacheDir(dir string, opts CachePruneOptions) (int64, error) {
+	entries, total, err := readCacheEntries(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	// Least recently used entries first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	var freed int64
+	for _, entry := range entries {
+		expired := opts.OlderThan > 0 && time.Since(entry.modTime) > opts.OlderThan
+		oversized := opts.MaxSize > 0 && total-freed > opts.MaxSize
+		if !expired && !oversized {
+			continue
+		}
+		if err := os.RemoveAll(entry.path); err != nil {
+			return freed, err
+		}
+		freed += entry.size
+	}
+	return freed, nil
+}
+
+// readCacheEntries returns the top level entries of a cache directory and their total size.
+func readCacheEntries(dir string) ([]cacheEntry, int64, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := []cacheEntry{}
+	var total int64
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			return nil, 0, err
+		}
+		path := filepath.Join(dir, de.Name())
+		size := info.Size()
+		if de.IsDir() {
+			size, err = dirSize(path)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		entries = append(entries, cacheEntry{path: path, size: size, modTime: info.ModTime()})
+		total += size
+	}
+	return entries, total, nil
+}
+
+// TouchCacheEntries marks the given entries of a cache directory as recently used, missing entries are ignored.
+func TouchCacheEntries(dir string, names ...string) error {
+	now := time.Now()
+	for _, name := range names {
+		err := os.Chtimes(filepath.Join(dir, name), now, now)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCacheDir(t *testing.T) {
+	t.Parallel()
+
+	newCache := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		now := time.Now()
+		for i, name := range []string{"oldest", "older", "newest"} {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o600))
+			modTime := now.Add(-time.Duration(3-i) * time.Hour)
+			require.NoError(t, os.Chtimes(path, modTime, modTime))
+		}
+		return dir
+	}
+	remaining := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		names := []string{}
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	tests := []struct {
+		name              string
+		opts              CachePruneOptions
+		expectedFreed     int64
+		expectedRemaining []string
+	}{
+		{
+			name:              "no options",
+			opts:              CachePruneOptions{},
+			expectedFreed:     0,
+			expectedRemaining: []string{"newest", "older", "oldest"},
+		},
+		{
+			name:              "older than",
+			opts:              CachePruneOptions{OlderThan: 150 * time.Minute},
+			expectedFreed:     100,
+			expectedRemaining: []string{"newest", "older"},
+		},
+		{
+			name:              "max size",
+			opts:              CachePruneOptions{MaxSize: 150},
+			expectedFreed:     200,
+			expectedRemaining: []string{"newest"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := newCache(t)
+			freed, err := PruneCacheDir(dir, tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedFreed, freed)
+			require.Equal(t, tt.expectedRemaining, remaining(t, dir))
+		})
+	}
+
+	freed, err := PruneCacheDir(filepath.Join(t.TempDir(), "missing"), CachePruneOptions{MaxSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), freed)
+}
+
+func TestTouchCacheEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "layer")
+	require.NoError(t, os.WriteFile(path, []byte("layer"), 0o600))
+	old := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	require.NoError(t, TouchCacheEntries(dir, "layer", "missing"))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.True(t, info.ModTime().After(old))
+}
+
+func TestCacheDirStats(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	older := now.Add(-time.Hour)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "layer"), make([]byte, 100), 0o600))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "layer"), now, now))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "repo"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "repo", "file"), make([]byte, 50), 0o600))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "repo"), older, older))
+
+	stats, err := CacheDirStats(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Entries)
+	require.Equal(t, int64(150), stats.Size)
+	require.True(t, now.Equal(stats.LastUsed))
+	require.True(t, older.Equal(stats.LeastRecentlyUsed))
+
+	stats, err = CacheDirStats(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Equal(t, CacheStats{}, stats)
+}
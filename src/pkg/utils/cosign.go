@@ -12,7 +12,6 @@ import (
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
@@ -59,7 +58,7 @@ func Sget(ctx context.Context, image, key string, out io.Writer) error {
 	}
 
 	opts := []remote.Option{
-		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithAuthFromKeychain(Keychain),
 		remote.WithContext(ctx),
 	}
 
@@ -178,6 +177,50 @@ func Sget(ctx context.Context, image, key string, out io.Writer) error {
 	return err
 }
 
+// CosignVerifyImage verifies that the image digest is signed with the given public key or, when no key is
+// provided, with a keyless certificate whose identity and issuer match the given regular expressions.
+func CosignVerifyImage(ctx context.Context, ref name.Digest, key, identity, issuer string) error {
+	co := &cosign.CheckOpts{
+		ClaimVerifier: cosign.SimpleClaimVerifier,
+		RegistryClientOpts: []ociremote.Option{ociremote.WithRemoteOptions(
+			remote.WithAuthFromKeychain(Keychain),
+			remote.WithContext(ctx),
+		)},
+	}
+
+	var err error
+	if key != "" {
+		co.SigVerifier, err = sigs.LoadPublicKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("unable to load the public key %s: %w", key, err)
+		}
+		co.IgnoreTlog = true
+		co.IgnoreSCT = true
+		co.Offline = true
+	} else {
+		co.Identities = []cosign.Identity{{SubjectRegExp: identity, IssuerRegExp: issuer}}
+		co.RootCerts, err = fulcio.GetRoots()
+		if err != nil {
+			return fmt.Errorf("getting Fulcio roots: %w", err)
+		}
+		co.IntermediateCerts, err = fulcio.GetIntermediates()
+		if err != nil {
+			return fmt.Errorf("getting Fulcio intermediates: %w", err)
+		}
+		co.RekorPubKeys, err = cosign.GetRekorPubs(ctx)
+		if err != nil {
+			return fmt.Errorf("getting Rekor public keys: %w", err)
+		}
+		co.CTLogPubKeys, err = cosign.GetCTLogPubs(ctx)
+		if err != nil {
+			return fmt.Errorf("getting CTLog public keys: %w", err)
+		}
+	}
+
+	_, _, err = cosign.VerifyImageSignatures(ctx, ref, co)
+	return err
+}
+
 // CosignVerifyBlob verifies the zarf.yaml.sig was signed with the key provided by the flag
 func CosignVerifyBlob(ctx context.Context, blobRef string, sigRef string, keyPath string) error {
 	keyOptions := options.KeyOpts{KeyRef: keyPath}
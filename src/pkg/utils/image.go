@@ -32,9 +32,11 @@ func LoadOCIImage(imgPath string, refInfo transform.Image) (v1.Image, error) {
 
 	// Search through all the manifests within this package until we find the annotation that matches our ref
 	for _, manifest := range idxManifest.Manifests {
-		if manifest.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Reference ||
-			// A backwards compatibility shim for older Zarf versions that would leave docker.io off of image annotations
-			(manifest.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Path+refInfo.TagOrDigest && refInfo.Host == "docker.io") {
+		// Image indexes are stored next to the image for the package architecture
+		if manifest.MediaType.IsIndex() {
+			continue
+		}
+		if matchesImageNameAnnotation(manifest, refInfo) {
 			// This is the image we are looking for, load it and then return
 			return layoutPath.Image(manifest.Digest)
 		}
@@ -43,6 +45,32 @@ func LoadOCIImage(imgPath string, refInfo transform.Image) (v1.Image, error) {
 	return nil, fmt.Errorf("unable to find image (%s) at the path (%s)", refInfo.Reference, imgPath)
 }
 
+// LoadOCIImageIndex returns the v1.ImageIndex stored for the image ref at the location provided, or nil if the image was stored without its index.
+func LoadOCIImageIndex(imgPath string, refInfo transform.Image) (v1.ImageIndex, error) {
+	layoutPath := layout.Path(imgPath)
+	imgIdx, err := layoutPath.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	idxManifest, err := imgIdx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manifest := range idxManifest.Manifests {
+		if manifest.MediaType.IsIndex() && matchesImageNameAnnotation(manifest, refInfo) {
+			return imgIdx.ImageIndex(manifest.Digest)
+		}
+	}
+	return nil, nil
+}
+
+func matchesImageNameAnnotation(desc v1.Descriptor, refInfo transform.Image) bool {
+	return desc.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Reference ||
+		// A backwards compatibility shim for older Zarf versions that would leave docker.io off of image annotations
+		(desc.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Path+refInfo.TagOrDigest && refInfo.Host == "docker.io")
+}
+
 // AddImageNameAnnotation adds an annotation to the index.json file so that the deploying code can figure out what the image reference <-> digest shasum will be.
 func AddImageNameAnnotation(ociPath string, referenceToDigest map[string]string) error {
 	indexPath := filepath.Join(ociPath, "index.json")
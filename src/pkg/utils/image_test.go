@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+func TestLoadOCIImageIndex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	lp, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	idx, err := random.Index(64, 1, 2)
+	require.NoError(t, err)
+	idxManifest, err := idx.IndexManifest()
+	require.NoError(t, err)
+	img, err := idx.Image(idxManifest.Manifests[0].Digest)
+	require.NoError(t, err)
+
+	indexRef, err := transform.ParseImageRef("docker.io/library/multi:1.0.0")
+	require.NoError(t, err)
+	imageRef, err := transform.ParseImageRef("docker.io/library/single:1.0.0")
+	require.NoError(t, err)
+
+	err = lp.AppendImage(img, layout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: indexRef.Reference}))
+	require.NoError(t, err)
+	err = lp.AppendIndex(idx, layout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: indexRef.Reference}))
+	require.NoError(t, err)
+	err = lp.AppendImage(img, layout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: imageRef.Reference}))
+	require.NoError(t, err)
+
+	loadedImg, err := LoadOCIImage(dir, indexRef)
+	require.NoError(t, err)
+	expectedDigest, err := img.Digest()
+	require.NoError(t, err)
+	loadedDigest, err := loadedImg.Digest()
+	require.NoError(t, err)
+	require.Equal(t, expectedDigest, loadedDigest)
+
+	loadedIdx, err := LoadOCIImageIndex(dir, indexRef)
+	require.NoError(t, err)
+	require.NotNil(t, loadedIdx)
+	expectedDigest, err = idx.Digest()
+	require.NoError(t, err)
+	loadedDigest, err = loadedIdx.Digest()
+	require.NoError(t, err)
+	require.Equal(t, expectedDigest, loadedDigest)
+
+	loadedIdx, err = LoadOCIImageIndex(dir, imageRef)
+	require.NoError(t, err)
+	require.Nil(t, loadedIdx)
+}
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const configRefPrefix = "config:"
+
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// interpolator expands the references in the string values of config settings.
+type interpolator struct {
+	settings  map[string]any
+	lookupEnv func(string) (string, bool)
+	resolved  map[string]string
+	resolving map[string]bool
+}
+
+// InterpolateConfig returns a copy of the config settings with the references in their string values expanded:
+//
+//	${NAME}              the value of the NAME environment variable, which must be set
+//	${NAME:-default}     the value of the NAME environment variable, or default when it is unset or empty
+//	${config:some.key}   the value of another key of the settings, which may itself contain references
+//	$${                  a literal ${
+//
+// Keys of the settings are nested maps as returned by viper, referenced with their lowercase dotted path.
+func InterpolateConfig(settings map[string]any, lookupEnv func(string) (string, bool)) (map[string]any, error) {
+	i := &interpolator{
+		settings:  settings,
+		lookupEnv: lookupEnv,
+		resolved:  map[string]string{},
+		resolving: map[string]bool{},
+	}
+	expanded, err := i.expandMap("", settings)
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+func (i *interpolator) expandMap(prefix string, m map[string]any) (map[string]any, error) {
+	expanded := make(map[string]any, len(m))
+	for k, value := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch value := value.(type) {
+		case string:
+			s, err := i.resolveKey(key)
+			if err != nil {
+				return nil, err
+			}
+			expanded[k] = s
+		default:
+			v, err := i.expandValue(key, value)
+			if err != nil {
+				return nil, err
+			}
+			expanded[k] = v
+		}
+	}
+	return expanded, nil
+}
+
+func (i *interpolator) expandValue(key string, value any) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		return i.expandMap(key, value)
+	case []any:
+		expanded := make([]any, len(value))
+		for idx, item := range value {
+			v, err := i.expandValue(key, item)
+			if err != nil {
+				return nil, err
+			}
+			expanded[idx] = v
+		}
+		return expanded, nil
+	case string:
+		s, err := i.expand(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to interpolate %s: %w", key, err)
+		}
+		return s, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveKey returns the expanded value of the key of the settings, detecting keys that reference themselves.
+func (i *interpolator) resolveKey(key string) (string, error) {
+	if s, ok := i.resolved[key]; ok {
+		return s, nil
+	}
+	if i.resolving[key] {
+		return "", fmt.Errorf("config key %s references itself", key)
+	}
+
+	var value any = i.settings
+	for _, part := range strings.Split(key, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("config key %s is not set", key)
+		}
+		value, ok = m[part]
+		if !ok {
+			return "", fmt.Errorf("config key %s is not set", key)
+		}
+	}
+
+	var s string
+	switch value := value.(type) {
+	case string:
+		i.resolving[key] = true
+		expanded, err := i.expand(value)
+		delete(i.resolving, key)
+		if err != nil {
+			return "", fmt.Errorf("unable to interpolate %s: %w", key, err)
+		}
+		s = expanded
+	case map[string]any, []any:
+		return "", fmt.Errorf("config key %s is not a single value", key)
+	default:
+		s = fmt.Sprint(value)
+	}
+	i.resolved[key] = s
+	return s, nil
+}
+
+// expand replaces the references in the string.
+func (i *interpolator) expand(s string) (string, error) {
+	var sb strings.Builder
+	for {
+		idx := strings.Index(s, "$")
+		if idx < 0 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		sb.WriteString(s[:idx])
+		s = s[idx:]
+
+		switch {
+		case strings.HasPrefix(s, "$${"):
+			sb.WriteString("${")
+			s = s[3:]
+		case strings.HasPrefix(s, "${"):
+			end := strings.Index(s, "}")
+			if end < 0 {
+				return "", fmt.Errorf("unterminated reference in %q", s)
+			}
+			value, err := i.resolveReference(s[2:end])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+			s = s[end+1:]
+		default:
+			sb.WriteString("$")
+			s = s[1:]
+		}
+	}
+}
+
+// resolveReference returns the value of the expression between ${ and }.
+func (i *interpolator) resolveReference(expr string) (string, error) {
+	if strings.HasPrefix(expr, configRefPrefix) {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(expr, configRefPrefix)))
+		if key == "" {
+			return "", fmt.Errorf("missing config key in ${%s}", expr)
+		}
+		return i.resolveKey(key)
+	}
+
+	name, fallback, hasFallback := strings.Cut(expr, ":-")
+	if !envNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid environment variable name in ${%s}", expr)
+	}
+	value, ok := i.lookupEnv(name)
+	if hasFallback && value == "" {
+		return fallback, nil
+	}
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
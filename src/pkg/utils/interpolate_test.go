@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolateConfig(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"ENVIRONMENT": "staging",
+		"REGISTRY":    "registry.staging.example.com",
+		"EMPTY":       "",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name          string
+		settings      map[string]any
+		expected      map[string]any
+		expectedError string
+	}{
+		{
+			name: "environment variables",
+			settings: map[string]any{
+				"log_level": "info",
+				"package": map[string]any{
+					"deploy": map[string]any{
+						"set": map[string]any{
+							"env":    "${ENVIRONMENT}",
+							"domain": "${ENVIRONMENT}.example.com",
+						},
+					},
+				},
+			},
+			expected: map[string]any{
+				"log_level": "info",
+				"package": map[string]any{
+					"deploy": map[string]any{
+						"set": map[string]any{
+							"env":    "staging",
+							"domain": "staging.example.com",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "defaults",
+			settings: map[string]any{
+				"a": "${MISSING:-fallback}",
+				"b": "${EMPTY:-fallback}",
+				"c": "${ENVIRONMENT:-fallback}",
+				"d": "${MISSING:-}",
+			},
+			expected: map[string]any{
+				"a": "fallback",
+				"b": "fallback",
+				"c": "staging",
+				"d": "",
+			},
+		},
+		{
+			name: "config keys",
+			settings: map[string]any{
+				"registry": "${REGISTRY}",
+				"init": map[string]any{
+					"registry": map[string]any{
+						"url":      "${config:registry}:443",
+						"nodeport": 31999,
+					},
+				},
+				"mirror": "${config:init.registry.url}/mirror",
+				"port":   "${config:init.registry.nodeport}",
+			},
+			expected: map[string]any{
+				"registry": "registry.staging.example.com",
+				"init": map[string]any{
+					"registry": map[string]any{
+						"url":      "registry.staging.example.com:443",
+						"nodeport": 31999,
+					},
+				},
+				"mirror": "registry.staging.example.com:443/mirror",
+				"port":   "31999",
+			},
+		},
+		{
+			name: "lists",
+			settings: map[string]any{
+				"components": []any{"base", "${ENVIRONMENT}-overlay", 3},
+			},
+			expected: map[string]any{
+				"components": []any{"base", "staging-overlay", 3},
+			},
+		},
+		{
+			name: "escaping",
+			settings: map[string]any{
+				"a": "$${ENVIRONMENT}",
+				"b": "price: $5 $$ ${ENVIRONMENT}",
+			},
+			expected: map[string]any{
+				"a": "${ENVIRONMENT}",
+				"b": "price: $5 $$ staging",
+			},
+		},
+		{
+			name:          "unset environment variable",
+			settings:      map[string]any{"a": "${MISSING}"},
+			expectedError: "unable to interpolate a: environment variable MISSING is not set",
+		},
+		{
+			name:          "invalid environment variable",
+			settings:      map[string]any{"a": "${NOT-VALID}"},
+			expectedError: "unable to interpolate a: invalid environment variable name in ${NOT-VALID}",
+		},
+		{
+			name:          "unterminated reference",
+			settings:      map[string]any{"a": "${ENVIRONMENT"},
+			expectedError: `unable to interpolate a: unterminated reference in "${ENVIRONMENT"`,
+		},
+		{
+			name:          "missing config key",
+			settings:      map[string]any{"a": "${config:b.c}"},
+			expectedError: "unable to interpolate a: config key b.c is not set",
+		},
+		{
+			name:          "config key with a map",
+			settings:      map[string]any{"a": "${config:b}", "b": map[string]any{"c": "d"}},
+			expectedError: "unable to interpolate a: config key b is not a single value",
+		},
+		{
+			name:          "cycle",
+			settings:      map[string]any{"a": "${config:a}"},
+			expectedError: "unable to interpolate a: config key a references itself",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expanded, err := InterpolateConfig(tt.settings, lookupEnv)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, expanded)
+		})
+	}
+}
+
+func TestInterpolateConfigIndirectCycle(t *testing.T) {
+	t.Parallel()
+
+	_, err := InterpolateConfig(map[string]any{"a": "${config:b}", "b": "${config:a}"}, func(string) (string, bool) { return "", false })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "references itself")
+}
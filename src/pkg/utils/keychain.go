@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
+	"github.com/chrismellard/docker-credential-acr-env/pkg/credhelper"
+	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// builtinCredentialHelpers are the cloud registry credential helpers that are used in place of their
+// docker-credential-<name> binaries when those are not on the PATH.
+var builtinCredentialHelpers = map[string]authn.Keychain{
+	"ecr-login": authn.NewKeychainFromHelper(ecr.NewECRHelper(ecr.WithLogger(io.Discard))),
+	"acr-env":   authn.NewKeychainFromHelper(credhelper.NewACRCredentialsHelper()),
+	"gcloud":    google.Keychain,
+	"gcr":       google.Keychain,
+}
+
+// ambientKeychains are the keychains of the providers in the registry_auth section of the config file that authenticate
+// with the cloud credentials of the environment.
+var ambientKeychains = map[string]authn.Keychain{
+	types.RegistryAuthProviderAWS:   builtinCredentialHelpers["ecr-login"],
+	types.RegistryAuthProviderGCP:   builtinCredentialHelpers["gcr"],
+	types.RegistryAuthProviderAzure: builtinCredentialHelpers["acr-env"],
+}
+
+// Keychain resolves registry credentials from the provider the registry_auth section of the config file selects for
+// the registry, or otherwise from the Docker config file, including the credential helpers configured under credHelpers
+// and credsStore. The ECR, GCR and ACR helpers are built in and used when their binaries are not installed.
+var Keychain authn.Keychain = credentialHelperKeychain{}
+
+type credentialHelperKeychain struct{}
+
+// Resolve returns the authenticator for the registry of target.
+func (credentialHelperKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	if settings, ok := config.CommonOptions.RegistryAuth[target.RegistryStr()]; ok {
+		if settings.Provider == types.RegistryAuthProviderOIDC {
+			return oidcAuthenticator{settings: settings}, nil
+		}
+		return ambientKeychains[settings.Provider].Resolve(target)
+	}
+	helper, err := credentialHelperFor(target.RegistryStr())
+	if err != nil {
+		return nil, err
+	}
+	if helper == "" {
+		return authn.DefaultKeychain.Resolve(target)
+	}
+	if _, err := exec.LookPath("docker-credential-" + helper); err == nil {
+		return authn.DefaultKeychain.Resolve(target)
+	}
+	if kc, ok := builtinCredentialHelpers[helper]; ok {
+		return kc.Resolve(target)
+	}
+	return nil, fmt.Errorf("the credential helper docker-credential-%s configured for %s was not found in the PATH", helper, target.RegistryStr())
+}
+
+// credentialHelperFor returns the credential helper the Docker config file configures for registry, if any.
+func credentialHelperFor(registry string) (string, error) {
+	cf, err := dockerconfig.Load(os.Getenv("DOCKER_CONFIG"))
+	if err != nil {
+		return "", err
+	}
+	key := registry
+	if key == name.DefaultRegistry {
+		key = authn.DefaultAuthKey
+	}
+	if helper, ok := cf.CredentialHelpers[key]; ok {
+		return helper, nil
+	}
+	if helper, ok := cf.CredentialHelpers[registry]; ok {
+		return helper, nil
+	}
+	return cf.CredentialsStore, nil
+}
+
+// RegistryAuth resolves the credentials for registry from Keychain.
+func RegistryAuth(ctx context.Context, registry string) (authn.AuthConfig, error) {
+	if registry == "registry-1.docker.io" {
+		registry = name.DefaultRegistry
+	}
+	reg, err := name.NewRegistry(registry)
+	if err != nil {
+		return authn.AuthConfig{}, err
+	}
+	authenticator, err := authn.Resolve(ctx, Keychain, reg)
+	if err != nil {
+		return authn.AuthConfig{}, err
+	}
+	cfg, err := authn.Authorization(ctx, authenticator)
+	if err != nil {
+		return authn.AuthConfig{}, err
+	}
+	return *cfg, nil
+}
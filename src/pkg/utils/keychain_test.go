@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeychain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+
+	tests := []struct {
+		name         string
+		dockerConfig string
+		registry     string
+		expected     authn.AuthConfig
+		expectedErr  string
+	}{
+		{
+			name:         "credential helper for the registry",
+			dockerConfig: `{"credHelpers": {"registry.example.com": "fake"}}`,
+			registry:     "registry.example.com",
+			expected:     authn.AuthConfig{Username: "helper-user", Password: "helper-secret"},
+		},
+		{
+			name:         "credential store",
+			dockerConfig: `{"credsStore": "fake"}`,
+			registry:     "registry.example.com",
+			expected:     authn.AuthConfig{Username: "helper-user", Password: "helper-secret"},
+		},
+		{
+			name:         "static auth without a credential helper",
+			dockerConfig: `{"auths": {"registry.example.com": {"auth": "c3RhdGljOnBhc3N3b3Jk"}}}`,
+			registry:     "registry.example.com",
+			expected:     authn.AuthConfig{Username: "static", Password: "password"},
+		},
+		{
+			name:         "credential helper for another registry",
+			dockerConfig: `{"credHelpers": {"other.example.com": "missing"}}`,
+			registry:     "registry.example.com",
+			expected:     authn.AuthConfig{},
+		},
+		{
+			name:         "missing credential helper",
+			dockerConfig: `{"credHelpers": {"registry.example.com": "missing"}}`,
+			registry:     "registry.example.com",
+			expectedErr:  "the credential helper docker-credential-missing configured for registry.example.com was not found in the PATH",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binDir := t.TempDir()
+			helper := "#!/bin/sh\nread -r server\necho '{\"ServerURL\":\"registry.example.com\",\"Username\":\"helper-user\",\"Secret\":\"helper-secret\"}'\n"
+			err := os.WriteFile(filepath.Join(binDir, "docker-credential-fake"), []byte(helper), 0o755)
+			require.NoError(t, err)
+			configDir := t.TempDir()
+			err = os.WriteFile(filepath.Join(configDir, "config.json"), []byte(tt.dockerConfig), 0o644)
+			require.NoError(t, err)
+
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("DOCKER_CONFIG", configDir)
+			t.Setenv("PATH", binDir)
+
+			cfg, err := RegistryAuth(context.Background(), tt.registry)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -6,14 +6,19 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -38,8 +43,42 @@ func parseChecksum(src string) (string, string, error) {
 	return src, checksum, nil
 }
 
+// DownloadOptions configures the retries, mirrors and cache of DownloadToFileWithOptions.
+type DownloadOptions struct {
+	// Retries is the number of times a download is attempted with exponential backoff, it is attempted once when zero
+	Retries int
+	// Mirrors maps URL prefixes to alternate prefixes that are tried, in order, after the URL fails
+	Mirrors map[string][]string
+	// CacheDirectory caches downloads by URL, cached downloads are revalidated with their ETag
+	CacheDirectory string
+}
+
+// urls returns src followed by its URL on each mirror of the longest matching prefix.
+func (o DownloadOptions) urls(src string) []string {
+	urls := []string{src}
+	var match string
+	for prefix := range o.Mirrors {
+		if strings.HasPrefix(src, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return urls
+	}
+	for _, mirror := range o.Mirrors[match] {
+		urls = append(urls, mirror+strings.TrimPrefix(src, match))
+	}
+	return urls
+}
+
 // DownloadToFile downloads a given URL to the target filepath (including the cosign key if necessary).
-func DownloadToFile(ctx context.Context, src string, dst string, cosignKeyPath string) (err error) {
+func DownloadToFile(ctx context.Context, src string, dst string, cosignKeyPath string) error {
+	return DownloadToFileWithOptions(ctx, src, dst, cosignKeyPath, DownloadOptions{})
+}
+
+// DownloadToFileWithOptions downloads a given URL to the target filepath (including the cosign key if necessary),
+// retrying failed downloads and falling back to the mirrors of the URL.
+func DownloadToFileWithOptions(ctx context.Context, src string, dst string, cosignKeyPath string, opts DownloadOptions) (err error) {
 	// check if the parsed URL has a checksum
 	// if so, remove it and use the checksum to validate the file
 	src, checksum, err := parseChecksum(src)
@@ -52,28 +91,55 @@ func DownloadToFile(ctx context.Context, src string, dst string, cosignKeyPath s
 		return fmt.Errorf(lang.ErrCreatingDir, filepath.Dir(dst), err.Error())
 	}
 
-	// Create the file
-	file, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf(lang.ErrWritingFile, dst, err.Error())
-	}
-	defer file.Close()
-
 	parsed, err := url.Parse(src)
 	if err != nil {
 		return fmt.Errorf("unable to parse the URL: %s", src)
 	}
+	// Partial downloads and downloads that do not match the checksum are removed
+	defer func() {
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
 	// If the source url starts with the sget protocol use that, otherwise do a typical GET call
 	if parsed.Scheme == helpers.SGETURLScheme {
-		err = Sget(ctx, src, cosignKeyPath, file)
+		// Create the file
+		file, err := os.Create(dst)
 		if err != nil {
-			return fmt.Errorf("unable to download file with sget: %s: %w", src, err)
+			return fmt.Errorf(lang.ErrWritingFile, dst, err.Error())
 		}
+		defer file.Close()
+		err = Sget(ctx, src, cosignKeyPath, file)
 		if err != nil {
-			return err
+			return fmt.Errorf("unable to download file with sget: %s: %w", src, err)
 		}
 	} else {
-		err = httpGetFile(src, file)
+		urls := opts.urls(src)
+		attempts := uint(max(opts.Retries, 1))
+		retryable := false
+		err = retry.Do(func() error {
+			errs := []error{}
+			retryable = false
+			for _, u := range urls {
+				err := httpGetFile(ctx, u, dst, opts.CacheDirectory)
+				if err == nil {
+					return nil
+				}
+				message.Debugf("Unable to download %s: %s", u, err.Error())
+				errs = append(errs, err)
+				retryable = retryable || isRetryableDownloadError(err)
+			}
+			return errors.Join(errs...)
+		},
+			retry.Context(ctx),
+			retry.Attempts(attempts),
+			retry.Delay(500*time.Millisecond),
+			retry.LastErrorOnly(true),
+			retry.RetryIf(func(error) bool { return retryable }),
+			retry.OnRetry(func(n uint, err error) {
+				message.Warnf("Retrying the download of %s (attempt %d of %d): %s", src, n+2, attempts, err.Error())
+			}),
+		)
 		if err != nil {
 			return err
 		}
@@ -93,19 +159,70 @@ func DownloadToFile(ctx context.Context, src string, dst string, cosignKeyPath s
 	return nil
 }
 
-func httpGetFile(url string, destinationFile *os.File) error {
+// httpStatusError is a download that failed with an unexpected HTTP status.
+type httpStatusError struct {
+	status     string
+	statusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("bad HTTP status: %s", e.status)
+}
+
+// isRetryableDownloadError returns whether a download can succeed when it is retried, client errors other than
+// timeouts and rate limits fail again.
+func isRetryableDownloadError(err error) bool {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	switch statusErr.statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return statusErr.statusCode >= http.StatusInternalServerError
+}
+
+// httpGetFile downloads url to dst, revalidating the download cached in cacheDirectory with its ETag when it is set.
+func httpGetFile(ctx context.Context, url string, dst string, cacheDirectory string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to download the file %s: %w", url, err)
+	}
+	var cachePath string
+	if cacheDirectory != "" {
+		cachePath = filepath.Join(cacheDirectory, fmt.Sprintf("%x", sha256.Sum256([]byte(url))))
+		if etag, err := os.ReadFile(cachePath + ".etag"); err == nil && !helpers.InvalidPath(cachePath) {
+			req.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
 	// Get the data
-	resp, err := http.Get(url)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("unable to download the file %s", url)
+		return fmt.Errorf("unable to download the file %s: %w", url, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cachePath != "" {
+		message.Debugf("Using the cached download of %s", url)
+		if err := helpers.CreatePathAndCopy(cachePath, dst); err != nil {
+			return fmt.Errorf("unable to copy the cached download of %s: %w", url, err)
+		}
+		return nil
+	}
+
 	// Check server response
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad HTTP status: %s", resp.Status)
+		return &httpStatusError{status: resp.Status, statusCode: resp.StatusCode}
 	}
 
+	destinationFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf(lang.ErrWritingFile, dst, err.Error())
+	}
+	defer destinationFile.Close()
+
 	// Writer the body to file
 	title := fmt.Sprintf("Downloading %s", filepath.Base(url))
 	progressBar := message.NewProgressBar(resp.ContentLength, title)
@@ -117,5 +234,60 @@ func httpGetFile(url string, destinationFile *os.File) error {
 
 	title = fmt.Sprintf("Downloaded %s", url)
 	progressBar.Successf("%s", title)
+
+	if etag := resp.Header.Get("ETag"); cachePath != "" && etag != "" {
+		if err := cacheDownload(dst, cachePath, etag); err != nil {
+			message.WarnErr(err, fmt.Sprintf("Unable to cache the download of %s", url))
+		}
+	}
 	return nil
 }
+
+// cacheDownload stores the download at dst in cachePath, the ETag is written last so it is only used with a complete download.
+// Both are written to temporary files that are renamed into place, so concurrent downloads of the same URL never copy
+// a partially written cache entry.
+func cacheDownload(dst string, cachePath string, etag string) error {
+	if err := helpers.CreateDirectory(filepath.Dir(cachePath), helpers.ReadWriteExecuteUser); err != nil {
+		return err
+	}
+	if err := os.Remove(cachePath + ".etag"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	err := writeFileAtomic(cachePath, func(w io.Writer) error {
+		src, err := os.Open(dst)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cachePath+".etag", func(w io.Writer) error {
+		_, err := w.Write([]byte(etag))
+		return err
+	})
+}
+
+// writeFileAtomic writes path with write through a temporary file in the same directory that is renamed into place.
+func writeFileAtomic(path string, write func(io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
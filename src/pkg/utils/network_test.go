@@ -5,12 +5,16 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/zarf-dev/zarf/src/test/testutil"
@@ -141,6 +145,7 @@ func TestDownloadToFile(t *testing.T) {
 			err := DownloadToFile(testutil.TestContext(t), src, dst, "")
 			if tt.expectedErr != "" {
 				require.ErrorContains(t, err, tt.expectedErr)
+				require.NoFileExists(t, dst)
 				return
 			}
 			require.NoError(t, err)
@@ -154,3 +159,123 @@ func TestDownloadToFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDownloadToFileWithOptions(t *testing.T) {
+	t.Parallel()
+
+	var flakyRequests, cachedRequests, notModified atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/flaky/README.md", func(rw http.ResponseWriter, _ *http.Request) {
+		if flakyRequests.Add(1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		//nolint:errcheck // ignore
+		rw.Write([]byte("flaky\n"))
+	})
+	mux.HandleFunc("/mirror/README.md", func(rw http.ResponseWriter, _ *http.Request) {
+		//nolint:errcheck // ignore
+		rw.Write([]byte("mirror\n"))
+	})
+	mux.HandleFunc("/cached/README.md", func(rw http.ResponseWriter, req *http.Request) {
+		cachedRequests.Add(1)
+		rw.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		//nolint:errcheck // ignore
+		rw.Write([]byte("cached\n"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(func() { srv.Close() })
+
+	t.Run("retries", func(t *testing.T) {
+		t.Parallel()
+		dst := filepath.Join(t.TempDir(), "README.md")
+		err := DownloadToFileWithOptions(testutil.TestContext(t), srv.URL+"/flaky/README.md", dst, "", DownloadOptions{Retries: 3})
+		require.NoError(t, err)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "flaky\n", string(b))
+		require.Equal(t, int32(3), flakyRequests.Load())
+	})
+
+	t.Run("mirrors", func(t *testing.T) {
+		t.Parallel()
+		dst := filepath.Join(t.TempDir(), "README.md")
+		opts := DownloadOptions{
+			Mirrors: map[string][]string{
+				srv.URL + "/":        {srv.URL + "/missing/"},
+				srv.URL + "/broken/": {srv.URL + "/missing/", srv.URL + "/mirror/"},
+			},
+		}
+		err := DownloadToFileWithOptions(testutil.TestContext(t), srv.URL+"/broken/README.md", dst, "", opts)
+		require.NoError(t, err)
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, "mirror\n", string(b))
+
+		err = DownloadToFileWithOptions(testutil.TestContext(t), srv.URL+"/other/README.md", dst, "", opts)
+		require.ErrorContains(t, err, "bad HTTP status: 404 Not Found")
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		t.Parallel()
+		opts := DownloadOptions{CacheDirectory: t.TempDir()}
+		for range 2 {
+			dst := filepath.Join(t.TempDir(), "README.md")
+			err := DownloadToFileWithOptions(testutil.TestContext(t), srv.URL+"/cached/README.md", dst, "", opts)
+			require.NoError(t, err)
+			b, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, "cached\n", string(b))
+		}
+		require.Equal(t, int32(2), cachedRequests.Load())
+		require.Equal(t, int32(1), notModified.Load())
+	})
+}
+
+func TestCacheDownloadConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	content := bytes.Repeat([]byte("zarf"), 1<<20)
+	dst := filepath.Join(dir, "download")
+	require.NoError(t, os.WriteFile(dst, content, 0o600))
+	cachePath := filepath.Join(dir, "cache", "entry")
+	require.NoError(t, cacheDownload(dst, cachePath, `"v1"`))
+
+	// The cache entry is always complete while it is rewritten
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			b, err := os.ReadFile(cachePath)
+			if err == nil && !bytes.Equal(content, b) {
+				t.Errorf("read a partial cache entry of %d bytes", len(b))
+				return
+			}
+		}
+	}()
+	for range 10 {
+		require.NoError(t, cacheDownload(dst, cachePath, `"v1"`))
+	}
+	close(done)
+	wg.Wait()
+
+	etag, err := os.ReadFile(cachePath + ".etag")
+	require.NoError(t, err)
+	require.Equal(t, `"v1"`, string(etag))
+	entries, err := os.ReadDir(filepath.Dir(cachePath))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const (
+	// tokenExchangeGrantType is the grant type of an OAuth 2.0 token exchange (RFC 8693).
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// idTokenType is the token type of the OIDC token that is exchanged.
+	idTokenType = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// oidcAuthenticator authenticates to a registry with an OIDC token of the environment, which is requested again for
+// every authorization since the tokens are short lived.
+type oidcAuthenticator struct {
+	settings types.RegistryAuth
+}
+
+// Authorization returns the credentials for the registry.
+func (a oidcAuthenticator) Authorization() (*authn.AuthConfig, error) {
+	return a.AuthorizationContext(context.Background())
+}
+
+// AuthorizationContext returns the credentials for the registry, exchanging the OIDC token for a registry token when
+// an exchange URL is set.
+func (a oidcAuthenticator) AuthorizationContext(ctx context.Context) (*authn.AuthConfig, error) {
+	token, err := oidcToken(ctx, a.settings)
+	if err != nil {
+		return nil, err
+	}
+	if a.settings.ExchangeURL != "" {
+		token, err = exchangeToken(ctx, a.settings, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.settings.Username == "" {
+		return &authn.AuthConfig{RegistryToken: token}, nil
+	}
+	return &authn.AuthConfig{Username: a.settings.Username, Password: token}, nil
+}
+
+// oidcToken returns the OIDC token from the file or environment variable of the settings, or otherwise requests one
+// from GitHub Actions.
+func oidcToken(ctx context.Context, settings types.RegistryAuth) (string, error) {
+	switch {
+	case settings.TokenFile != "":
+		b, err := os.ReadFile(settings.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the OIDC token: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	case settings.TokenEnv != "":
+		token := os.Getenv(settings.TokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("the OIDC token environment variable %s is not set", settings.TokenEnv)
+		}
+		return token, nil
+	case os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != "":
+		return githubActionsToken(ctx, settings.Audience)
+	}
+	return "", errors.New("no OIDC token found, set token_file or token_env, or run in GitHub Actions with the id-token: write permission")
+}
+
+// githubActionsToken requests an OIDC token for the audience from GitHub Actions.
+func githubActionsToken(ctx context.Context, audience string) (string, error) {
+	u, err := url.Parse(os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"))
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	if audience != "" {
+		query := u.Query()
+		query.Set("audience", audience)
+		u.RawQuery = query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to request an OIDC token from GitHub Actions: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to request an OIDC token from GitHub Actions: %s", resp.Status)
+	}
+	var token struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("unable to read the OIDC token from GitHub Actions: %w", err)
+	}
+	return token.Value, nil
+}
+
+// exchangeToken exchanges the OIDC token for a registry token at the token exchange endpoint of the settings.
+func exchangeToken(ctx context.Context, settings types.RegistryAuth, token string) (string, error) {
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {token},
+		"subject_token_type": {idTokenType},
+	}
+	if settings.Audience != "" {
+		form.Set("audience", settings.Audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.ExchangeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to exchange the OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+	var exchanged struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	// A failed exchange describes its error in the body
+	decodeErr := json.NewDecoder(resp.Body).Decode(&exchanged)
+	if resp.StatusCode != http.StatusOK {
+		if exchanged.Error != "" {
+			return "", fmt.Errorf("unable to exchange the OIDC token: %s: %s", resp.Status, strings.TrimSpace(exchanged.Error+" "+exchanged.ErrorDescription))
+		}
+		return "", fmt.Errorf("unable to exchange the OIDC token: %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("unable to read the exchanged token: %w", decodeErr)
+	}
+	if exchanged.AccessToken == "" {
+		return "", errors.New("the token exchange did not return an access token")
+	}
+	return exchanged.AccessToken, nil
+}
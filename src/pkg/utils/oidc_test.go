@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestOIDCAuthenticator(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer request-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": "github-" + r.URL.Query().Get("audience")})
+	}))
+	t.Cleanup(github.Close)
+	exchange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("grant_type") != tokenExchangeGrantType || r.PostForm.Get("subject_token_type") != idTokenType {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "unsupported_grant_type"})
+			return
+		}
+		if r.PostForm.Get("subject_token") != "file-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "the token is not trusted"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "registry-" + r.PostForm.Get("audience")})
+	}))
+	t.Cleanup(exchange.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0o600))
+
+	tests := []struct {
+		name        string
+		settings    types.RegistryAuth
+		env         map[string]string
+		expected    authn.AuthConfig
+		expectedErr string
+	}{
+		{
+			name:     "token file as a bearer token",
+			settings: types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, TokenFile: tokenFile},
+			expected: authn.AuthConfig{RegistryToken: "file-token"},
+		},
+		{
+			name:     "token environment variable with a username",
+			settings: types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, TokenEnv: "CI_JOB_JWT", Username: "ci"},
+			env:      map[string]string{"CI_JOB_JWT": "env-token"},
+			expected: authn.AuthConfig{Username: "ci", Password: "env-token"},
+		},
+		{
+			name:     "GitHub Actions token",
+			settings: types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, Audience: "registry.example.com"},
+			env: map[string]string{
+				"ACTIONS_ID_TOKEN_REQUEST_URL":   github.URL + "?api-version=2.0",
+				"ACTIONS_ID_TOKEN_REQUEST_TOKEN": "request-token",
+			},
+			expected: authn.AuthConfig{RegistryToken: "github-registry.example.com"},
+		},
+		{
+			name:     "exchanged token",
+			settings: types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, TokenFile: tokenFile, ExchangeURL: exchange.URL, Audience: "zarf", Username: "oauth2"},
+			expected: authn.AuthConfig{Username: "oauth2", Password: "registry-zarf"},
+		},
+		{
+			name:        "rejected exchange",
+			settings:    types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, TokenEnv: "CI_JOB_JWT", ExchangeURL: exchange.URL},
+			env:         map[string]string{"CI_JOB_JWT": "env-token"},
+			expectedErr: "unable to exchange the OIDC token: 400 Bad Request: invalid_grant the token is not trusted",
+		},
+		{
+			name:        "missing token environment variable",
+			settings:    types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC, TokenEnv: "CI_JOB_JWT"},
+			expectedErr: "the OIDC token environment variable CI_JOB_JWT is not set",
+		},
+		{
+			name:        "no token",
+			settings:    types.RegistryAuth{Provider: types.RegistryAuthProviderOIDC},
+			expectedErr: "no OIDC token found, set token_file or token_env, or run in GitHub Actions with the id-token: write permission",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+			t.Setenv("CI_JOB_JWT", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			config.CommonOptions.RegistryAuth = map[string]types.RegistryAuth{"registry.example.com": tt.settings}
+			t.Cleanup(func() {
+				config.CommonOptions.RegistryAuth = nil
+			})
+
+			cfg, err := RegistryAuth(context.Background(), "registry.example.com")
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// initialThrottleBackoff is how long requests to a registry wait after it first throttles a request.
+	initialThrottleBackoff = time.Second
+	// defaultMaxThrottleBackoff is the longest wait between retries when the rate limit does not set one.
+	defaultMaxThrottleBackoff = time.Minute
+)
+
+// registryThrottle is the state shared by all the requests to a registry, so once the registry throttles one request
+// the others back off with it instead of being throttled too.
+type registryThrottle struct {
+	mu sync.Mutex
+	// limiter spends the request budget of the registry, it is nil when requests are not limited
+	limiter *rate.Limiter
+	// until is the time requests to the registry wait until
+	until time.Time
+	// backoff is the wait after the next throttled request, it doubles with each throttled request in a row
+	backoff time.Duration
+}
+
+var (
+	registryThrottlesMu sync.Mutex
+	registryThrottles   = map[string]*registryThrottle{}
+)
+
+// throttleFor returns the throttle of the registry at host.
+func throttleFor(host string, requestsPerSecond float64) *registryThrottle {
+	registryThrottlesMu.Lock()
+	defer registryThrottlesMu.Unlock()
+	throttle, ok := registryThrottles[host]
+	if !ok {
+		throttle = &registryThrottle{backoff: initialThrottleBackoff}
+		if requestsPerSecond > 0 {
+			throttle.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+		}
+		registryThrottles[host] = throttle
+	}
+	return throttle
+}
+
+// wait blocks until a request may be sent to the registry.
+func (t *registryThrottle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	delay := time.Until(t.until)
+	t.mu.Unlock()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if t.limiter == nil {
+		return nil
+	}
+	return t.limiter.Wait(ctx)
+}
+
+// throttled backs off the requests to the registry after it throttled one, for as long as the Retry-After header
+// asks or otherwise the backoff, and returns how long that is.
+func (t *registryThrottle) throttled(retryAfter string, maxBackoff time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delay, ok := parseRetryAfter(retryAfter)
+	if !ok {
+		// Jitter keeps the requests that were throttled together from being retried together
+		delay = t.backoff + time.Duration(rand.Int63n(int64(t.backoff)/10+1))
+		t.backoff *= 2
+	}
+	delay = min(delay, maxBackoff)
+	t.backoff = min(t.backoff, maxBackoff)
+	if until := time.Now().Add(delay); until.After(t.until) {
+		t.until = until
+	}
+	return delay
+}
+
+// succeeded resets the backoff once the registry accepts a request again.
+func (t *registryThrottle) succeeded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.backoff = initialThrottleBackoff
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitTransport spends the request budget of each registry and retries the requests a registry throttles.
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+// RateLimitTransport wraps base, or http.DefaultTransport when it is nil, so requests to each registry keep to the
+// request budget of the registry rate limit, and requests throttled with a 429 response are retried with a backoff
+// shared by all the requests to the registry. Requests with a body that cannot be replayed are not retried.
+func RateLimitTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limit := config.CommonOptions.RegistryRateLimit
+	maxBackoff := limit.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultMaxThrottleBackoff
+	}
+	throttle := throttleFor(req.URL.Host, limit.RequestsPerSecond)
+
+	for attempt := 0; ; attempt++ {
+		if err := throttle.wait(req.Context()); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			throttle.succeeded()
+			return resp, nil
+		}
+		delay := throttle.throttled(resp.Header.Get("Retry-After"), maxBackoff)
+		replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= limit.Retries || !replayable {
+			return resp, nil
+		}
+		message.Debugf("%s throttled %s %s, retrying in %s (%d/%d)", req.URL.Host, req.Method, message.RedactURL(req.URL), delay.Round(time.Millisecond), attempt+1, limit.Retries)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
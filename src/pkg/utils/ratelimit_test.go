@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		value         string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{
+			name:          "seconds",
+			value:         "30",
+			expectedDelay: 30 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "date in the past",
+			value:         "Wed, 21 Oct 2015 07:28:00 GMT",
+			expectedDelay: 0,
+			expectedOK:    true,
+		},
+		{
+			name: "missing",
+		},
+		{
+			name:  "malformed",
+			value: "soon",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := parseRetryAfter(tt.value)
+			require.Equal(t, tt.expectedOK, ok)
+			require.Equal(t, tt.expectedDelay, delay)
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	config.CommonOptions.RegistryRateLimit = types.RegistryRateLimit{Retries: 2, MaxBackoff: 10 * time.Millisecond}
+	t.Cleanup(func() {
+		config.CommonOptions.RegistryRateLimit = types.RegistryRateLimit{}
+	})
+
+	tests := []struct {
+		name             string
+		throttled        int32
+		body             bool
+		expectedStatus   int
+		expectedRequests int32
+	}{
+		{
+			name:             "throttled request is retried",
+			throttled:        2,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+		},
+		{
+			name:             "throttled request runs out of retries",
+			throttled:        5,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedRequests: 3,
+		},
+		{
+			name:             "throttled request with a replayable body is retried",
+			throttled:        1,
+			body:             true,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= tt.throttled {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				if tt.body {
+					b, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					require.Equal(t, "manifest", string(b))
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if tt.body {
+				req, err = http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("manifest"))
+			}
+			require.NoError(t, err)
+
+			client := &http.Client{Transport: RateLimitTransport(nil)}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+			require.Equal(t, tt.expectedRequests, requests.Load())
+		})
+	}
+}
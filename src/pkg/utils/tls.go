@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// AppendCAFile adds the certificates of the PEM encoded CA bundle at caFile to pool.
+func AppendCAFile(pool *x509.CertPool, caFile string) error {
+	b, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("unable to read the CA bundle %s: %w", caFile, err)
+	}
+	if !pool.AppendCertsFromPEM(b) {
+		return fmt.Errorf("no certificates found in the CA bundle %s", caFile)
+	}
+	return nil
+}
+
+// RegistryTLSTransport returns a transport that connects to the registries in the registry_tls section of the config
+// file with their CAs, client certificates and verification on top of the TLS configuration of transport, and to every
+// other host with transport itself.
+func RegistryTLSTransport(transport *http.Transport) http.RoundTripper {
+	if len(config.CommonOptions.RegistryTLS) == 0 {
+		return transport
+	}
+	return &registryTLSTransport{
+		base:       transport,
+		registries: config.CommonOptions.RegistryTLS,
+		transports: map[string]http.RoundTripper{},
+	}
+}
+
+type registryTLSTransport struct {
+	base       *http.Transport
+	registries map[string]types.RegistryTLS
+
+	mu sync.Mutex
+	// transports are the transports of the registries that have been connected to
+	transports map[string]http.RoundTripper
+}
+
+// RoundTrip sends the request with the transport of its registry.
+func (t *registryTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	registry := req.URL.Host
+	settings, ok := t.registries[registry]
+	if !ok {
+		registry = req.URL.Hostname()
+		settings, ok = t.registries[registry]
+	}
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	transport, err := t.transportFor(registry, settings)
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// transportFor returns the transport of the registry, loading its certificates on the first request to it.
+func (t *registryTLSTransport) transportFor(registry string, settings types.RegistryTLS) (http.RoundTripper, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if transport, ok := t.transports[registry]; ok {
+		return transport, nil
+	}
+	tlsConfig, err := registryTLSConfig(t.base.TLSClientConfig, settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration for registry %s: %w", registry, err)
+	}
+	transport := t.base.Clone()
+	transport.TLSClientConfig = tlsConfig
+	t.transports[registry] = transport
+	return transport, nil
+}
+
+// registryTLSConfig returns base with the CA bundle, client certificate and verification of a registry applied. The CA
+// bundle is trusted along with the CAs base already trusts, and verification is skipped if either skips it.
+func registryTLSConfig(base *tls.Config, settings types.RegistryTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		tlsConfig = base.Clone()
+	}
+	if settings.CAFile != "" {
+		pool := rootCAs(tlsConfig)
+		if err := AppendCAFile(pool, settings.CAFile); err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if settings.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate %s: %w", settings.CertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if settings.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	return tlsConfig, nil
+}
+
+// rootCAs returns a copy of the CAs a TLS configuration trusts, which are the system CAs when it does not set them.
+func rootCAs(tlsConfig *tls.Config) *x509.CertPool {
+	if tlsConfig.RootCAs != nil {
+		return tlsConfig.RootCAs.Clone()
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return x509.NewCertPool()
+	}
+	return pool
+}
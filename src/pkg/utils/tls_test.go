@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestRegistryTLSTransport(t *testing.T) {
+	t.Cleanup(func() {
+		config.CommonOptions.RegistryTLS = nil
+	})
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	// The certificate of the server doubles as the client certificate
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "registry.crt")
+	keyFile := filepath.Join(dir, "registry.key")
+	cert := srv.TLS.Certificates[0]
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0o600))
+
+	tests := []struct {
+		name        string
+		registries  map[string]types.RegistryTLS
+		expectedErr string
+	}{
+		{
+			name:        "registry without TLS configuration",
+			registries:  map[string]types.RegistryTLS{"registry.example.com": {InsecureSkipVerify: true}},
+			expectedErr: "certificate signed by unknown authority",
+		},
+		{
+			name:        "CA bundle without a client certificate",
+			registries:  map[string]types.RegistryTLS{host: {CAFile: certFile}},
+			expectedErr: "remote error: tls: certificate required",
+		},
+		{
+			name:       "CA bundle and client certificate",
+			registries: map[string]types.RegistryTLS{host: {CAFile: certFile, CertFile: certFile, KeyFile: keyFile}},
+		},
+		{
+			name:       "skip verify and client certificate matched by host",
+			registries: map[string]types.RegistryTLS{"127.0.0.1": {InsecureSkipVerify: true, CertFile: certFile, KeyFile: keyFile}},
+		},
+		{
+			name:        "missing CA bundle",
+			registries:  map[string]types.RegistryTLS{host: {CAFile: filepath.Join(dir, "missing.crt")}},
+			expectedErr: "invalid TLS configuration for registry " + host + ": unable to read the CA bundle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.CommonOptions.RegistryTLS = tt.registries
+
+			client := &http.Client{Transport: RegistryTLSTransport(http.DefaultTransport.(*http.Transport).Clone())}
+			resp, err := client.Get(srv.URL)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
@@ -9,130 +9,15 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"path"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/zarf-dev/zarf/src/api/v1alpha1"
-	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
-
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
-// isJSONPathWaitType checks if the condition is a JSONPath or condition.
-func isJSONPathWaitType(condition string) bool {
-	if len(condition) == 0 || condition[0] != '{' || !strings.Contains(condition, "=") || !strings.Contains(condition, "}") {
-		return false
-	}
-
-	return true
-}
-
-// ExecuteWait executes the wait-for command.
-func ExecuteWait(waitTimeout, waitNamespace, condition, kind, identifier string, timeout time.Duration) error {
-	// Handle network endpoints.
-	switch kind {
-	case "http", "https", "tcp":
-		return waitForNetworkEndpoint(kind, identifier, condition, timeout)
-	}
-
-	// Type of wait, condition or JSONPath
-	var waitType string
-
-	// Check if waitType is JSONPath or condition
-	if isJSONPathWaitType(condition) {
-		waitType = "jsonpath="
-	} else {
-		waitType = "condition="
-	}
-
-	// Get the Zarf command configuration.
-	zarfCommand, err := GetFinalExecutableCommand()
-	if err != nil {
-		return fmt.Errorf("could not locate the current Zarf binary path: %w", err)
-	}
-
-	identifierMsg := identifier
-
-	// If the identifier contains an equals sign, convert to a label selector.
-	if strings.ContainsRune(identifier, '=') {
-		identifierMsg = fmt.Sprintf(" with label `%s`", identifier)
-		identifier = fmt.Sprintf("-l %s", identifier)
-	}
-
-	// Set the timeout for the wait-for command.
-	expired := time.After(timeout)
-
-	// Set the custom message for optional namespace.
-	namespaceMsg := ""
-	namespaceFlag := ""
-	if waitNamespace != "" {
-		namespaceFlag = fmt.Sprintf("-n %s", waitNamespace)
-		namespaceMsg = fmt.Sprintf(" in namespace %s", waitNamespace)
-	}
-
-	// Setup the spinner messages.
-	conditionMsg := fmt.Sprintf("Waiting for %s%s%s to be %s.", kind, identifierMsg, namespaceMsg, condition)
-	existMsg := fmt.Sprintf("Waiting for %s%s to exist.", path.Join(kind, identifierMsg), namespaceMsg)
-	spinner := message.NewProgressSpinner(existMsg)
-
-	// Get the OS shell to execute commands in
-	shell, shellArgs := exec.GetOSShell(v1alpha1.Shell{Windows: "cmd"})
-
-	defer spinner.Stop()
-
-	for {
-		// Delay the check for 1 second
-		time.Sleep(time.Second)
-
-		select {
-		case <-expired:
-			return errors.New("wait timed out")
-
-		default:
-			spinner.Updatef(existMsg)
-			// Check if the resource exists.
-			zarfKubectlGet := fmt.Sprintf("%s tools kubectl get %s %s %s", zarfCommand, namespaceFlag, kind, identifier)
-			stdout, stderr, err := exec.Cmd(shell, append(shellArgs, zarfKubectlGet)...)
-			if err != nil {
-				message.Debug(stdout, stderr, err)
-				continue
-			}
-
-			resourceNotFound := strings.Contains(stderr, "No resources found") && identifier == ""
-			if resourceNotFound {
-				message.Debug(stdout, stderr, err)
-				continue
-			}
-
-			// If only checking for existence, exit here.
-			switch condition {
-			case "", "exist", "exists":
-				spinner.Success()
-				return nil
-			}
-
-			spinner.Updatef(conditionMsg)
-			// Wait for the resource to meet the given condition.
-			zarfKubectlWait := fmt.Sprintf("%s tools kubectl wait %s %s %s --for %s%s --timeout=%s",
-				zarfCommand, namespaceFlag, kind, identifier, waitType, condition, waitTimeout)
-
-			// If there is an error, log it and try again.
-			if stdout, stderr, err := exec.Cmd(shell, append(shellArgs, zarfKubectlWait)...); err != nil {
-				message.Debug(stdout, stderr, err)
-				continue
-			}
-
-			// And just like that, success!
-			spinner.Successf(conditionMsg)
-			return nil
-		}
-	}
-}
-
-// waitForNetworkEndpoint waits for a network endpoint to respond.
-func waitForNetworkEndpoint(resource, name, condition string, timeout time.Duration) error {
+// WaitForNetworkEndpoint waits for a network endpoint to respond.
+func WaitForNetworkEndpoint(resource, name, condition string, timeout time.Duration) error {
 	// Set the timeout for the wait-for command.
 	expired := time.After(timeout)
 
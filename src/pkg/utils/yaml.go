@@ -159,6 +159,8 @@ func ReloadYamlTemplate(config any, mappings map[string]string) error {
 		}
 		// Properly escape " in the yaml text output
 		value = strings.ReplaceAll(value, "\"", "\\\"")
+		// Keep the line breaks of multi-line values such as certificates, which double quoted yaml folds into spaces
+		value = strings.NewReplacer("\r", "\\r", "\n", "\\n").Replace(value)
 		text = []byte(strings.ReplaceAll(string(text), template, value))
 	}
 
@@ -20,6 +20,15 @@ func (vc *VariableConfig) GetSetVariable(name string) (variable *v1alpha1.SetVar
 	return variable, ok
 }
 
+// GetSetVariableValues gets the values of all the variables set within a VariableConfig by their name
+func (vc *VariableConfig) GetSetVariableValues() map[string]string {
+	values := make(map[string]string, len(vc.setVariableMap))
+	for name, variable := range vc.setVariableMap {
+		values[name] = variable.Value
+	}
+	return values
+}
+
 // PopulateVariables handles setting the active variables within a VariableConfig's SetVariableMap
 func (vc *VariableConfig) PopulateVariables(variables []v1alpha1.InteractiveVariable, presetVariables map[string]string) error {
 	for name, value := range presetVariables {
@@ -5,12 +5,17 @@
 package zoci
 
 import (
+	"context"
 	"log/slog"
+	"net/http"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 const (
@@ -25,6 +30,11 @@ const (
 // Remote is a wrapper around the Oras remote repository with zarf specific functions
 type Remote struct {
 	*oci.OrasRemote
+	// ChunkSize is the size of the chunks that larger layers are published in, layers are published in a single
+	// request when it is zero
+	ChunkSize int64
+	// transport connects to the registry, the progress of the remote, its wire logs and rate limits wrap it
+	transport http.RoundTripper
 }
 
 // NewRemote returns an oras remote repository client and context for the given url
@@ -41,7 +51,78 @@ func NewRemote(url string, platform ocispec.Platform, mods ...oci.Modifier) (*Re
 	if err != nil {
 		return nil, err
 	}
-	return &Remote{remote}, nil
+	// Every remote starts out with the oras default client, each needs an HTTP client of its own to wrap
+	client := remote.Repo().Client.(*auth.Client)
+	transport, ok := client.Client.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	r := &Remote{
+		OrasRemote: remote,
+		transport:  utils.RegistryTLSTransport(transport),
+	}
+	r.Repo().Client = &auth.Client{
+		Client: &http.Client{},
+		Header: client.Header,
+	}
+	r.setTransport(nil)
+	r.setCredential(keychainCredential, keychainTokenCache)
+	return r, nil
+}
+
+// setTransport makes the remote connect to the registry with its TLS configuration, report the progress of its
+// requests to bar, log them at the highest verbosity and back off when the registry throttles them.
+func (r *Remote) setTransport(bar helpers.ProgressWriter) {
+	client := r.Repo().Client.(*auth.Client).Client
+	client.Transport = utils.RateLimitTransport(message.WireTransport(helpers.NewTransport(r.transport, bar)))
+}
+
+// SetProgressWriter sets the progress writer for the remote.
+func (r *Remote) SetProgressWriter(bar helpers.ProgressWriter) {
+	r.setTransport(bar)
+}
+
+// ClearProgressWriter clears the progress writer for the remote.
+func (r *Remote) ClearProgressWriter() {
+	r.setTransport(nil)
+}
+
+// keychainTokenCache caches the tokens of each registry for all the remotes that authenticate with the Docker
+// credential store, so concurrent pulls and pushes do not each request their own tokens.
+var keychainTokenCache = auth.NewCache()
+
+// SetBasicAuth authenticates to the remote with the given username and password instead of the Docker credential store.
+func (r *Remote) SetBasicAuth(username, password string) {
+	r.setCredential(auth.StaticCredential(r.Repo().Reference.Registry, auth.Credential{
+		Username: username,
+		Password: password,
+	}), auth.NewCache())
+}
+
+// setCredential replaces the auth client of the remote with one using the given credential function and token cache.
+func (r *Remote) setCredential(credential auth.CredentialFunc, cache auth.Cache) {
+	client := r.Repo().Client.(*auth.Client)
+	r.Repo().Client = &auth.Client{
+		Client:     client.Client,
+		Header:     client.Header.Clone(),
+		Cache:      cache,
+		Credential: credential,
+	}
+}
+
+// keychainCredential resolves the credentials for a registry with the Docker credential helpers on every token
+// request, so short lived tokens such as those issued for ECR are refreshed.
+func keychainCredential(ctx context.Context, hostport string) (auth.Credential, error) {
+	cfg, err := utils.RegistryAuth(ctx, hostport)
+	if err != nil {
+		return auth.EmptyCredential, err
+	}
+	return auth.Credential{
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		AccessToken:  cfg.RegistryToken,
+		RefreshToken: cfg.IdentityToken,
+	}, nil
 }
 
 // PlatformForSkeleton sets the target architecture for the remote to skeleton
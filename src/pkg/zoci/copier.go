@@ -8,10 +8,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/defenseunicorns/pkg/oci"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 )
 
@@ -53,6 +55,105 @@ func CopyPackage(ctx context.Context, src *Remote, dst *Remote, concurrency int)
 		return err
 	}
 
+	if err := copyReferrers(ctx, src, dst, srcRoot, concurrency); err != nil {
+		// Not every registry supports the referrers API or its tag fallback, this should not fail the copy
+		message.Warnf("Unable to copy the signatures, SBOMs and provenance attached to %s: %s", src.Repo().Reference, err.Error())
+	}
+
 	src.Log().Info(fmt.Sprintf("Published %s to %s", src.Repo().Reference, dst.Repo().Reference))
 	return nil
 }
+
+// CopyPackageIndex copies the package at the reference of src with all of its architectures and referrers to dst,
+// tagged with the reference of dst or, when it has none, that of src. Blobs are streamed from one registry to the
+// other without being written to disk, blobs dst already has are skipped, and blobs are mounted instead of copied
+// when src and dst are repositories of the same registry. It returns the descriptor of the copied package.
+func CopyPackageIndex(ctx context.Context, src *Remote, dst *Remote, concurrency int) (ocispec.Descriptor, error) {
+	srcRef := src.Repo().Reference
+	dstRef := dst.Repo().Reference
+	tag := dstRef.Reference
+	if tag == "" {
+		tag = srcRef.Reference
+	}
+
+	root, err := src.Repo().Resolve(ctx, srcRef.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("unable to resolve %s: %w", srcRef, err)
+	}
+	size, err := graphSize(ctx, src, root)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	progressBar := message.NewProgressBar(size, fmt.Sprintf("Copying %s", srcRef))
+	defer progressBar.Close()
+	var mu sync.Mutex
+	addProgress := func(desc ocispec.Descriptor) {
+		mu.Lock()
+		defer mu.Unlock()
+		progressBar.Add(int(desc.Size))
+	}
+
+	copyOpts := oras.DefaultCopyOptions
+	copyOpts.Concurrency = concurrency
+	copyOpts.PostCopy = func(_ context.Context, desc ocispec.Descriptor) error {
+		addProgress(desc)
+		return nil
+	}
+	copyOpts.OnCopySkipped = func(_ context.Context, desc ocispec.Descriptor) error {
+		message.Debugf("Skipping %s, %s already has it", desc.Digest, dstRef)
+		addProgress(desc)
+		return nil
+	}
+	if srcRef.Registry == dstRef.Registry && srcRef.Repository != dstRef.Repository {
+		copyOpts.MountFrom = func(_ context.Context, _ ocispec.Descriptor) ([]string, error) {
+			return []string{srcRef.Repository}, nil
+		}
+		copyOpts.OnMounted = func(_ context.Context, desc ocispec.Descriptor) error {
+			message.Debugf("Mounted %s from %s", desc.Digest, srcRef.Repository)
+			addProgress(desc)
+			return nil
+		}
+	}
+
+	desc, err := oras.Copy(ctx, src.Repo(), srcRef.Reference, dst.Repo(), tag, copyOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("unable to copy %s to %s: %w", srcRef, dstRef, err)
+	}
+	progressBar.Successf("Copied %s to %s", srcRef, dstRef)
+
+	// The signatures and SBOMs of a package refer to the manifest of each of its architectures
+	subjects := []ocispec.Descriptor{desc}
+	if desc.MediaType == ocispec.MediaTypeImageIndex {
+		manifests, err := content.Successors(ctx, src.Repo(), desc)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		subjects = append(subjects, manifests...)
+	}
+	for _, subject := range subjects {
+		if err := copyReferrers(ctx, src, dst, subject, concurrency); err != nil {
+			// Not every registry supports the referrers API or its tag fallback, this should not fail the copy
+			message.Warnf("Unable to copy the signatures, SBOMs and provenance attached to %s: %s", subject.Digest, err.Error())
+			break
+		}
+	}
+	return desc, nil
+}
+
+// graphSize returns the size of the descriptor and all of its successors.
+func graphSize(ctx context.Context, remote *Remote, desc ocispec.Descriptor) (int64, error) {
+	size := desc.Size
+	successors, err := content.Successors(ctx, remote.Repo(), desc)
+	if err != nil {
+		return 0, err
+	}
+	for _, successor := range successors {
+		n, err := graphSize(ctx, remote, successor)
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return size, nil
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestCopyPackageIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srcSrv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(srcSrv.Close)
+	srcHost := strings.TrimPrefix(srcSrv.URL, "http://")
+	dstSrv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(dstSrv.Close)
+	dstHost := strings.TrimPrefix(dstSrv.URL, "http://")
+
+	src, err := NewRemote(srcHost+"/staging/dos-games:1.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	sig := filepath.Join(t.TempDir(), "zarf.yaml.sig")
+	require.NoError(t, os.WriteFile(sig, []byte("signature"), 0o644))
+	for _, arch := range []string{"amd64", "arm64"} {
+		opts := oras.PackManifestOptions{ManifestAnnotations: map[string]string{"arch": arch}}
+		desc, err := oras.PackManifest(ctx, src.Repo(), oras.PackManifestVersion1_1, ZarfConfigMediaType, opts)
+		require.NoError(t, err)
+		_, err = src.AttachReferrer(ctx, desc, SignatureArtifactType, map[string]string{"zarf.yaml.sig": sig}, nil)
+		require.NoError(t, err)
+		desc.Platform = &ocispec.Platform{OS: oci.MultiOS, Architecture: arch}
+		index.Manifests = append(index.Manifests, desc)
+	}
+	b, err := json.Marshal(index)
+	require.NoError(t, err)
+	root, err := oras.TagBytes(ctx, src.Repo(), ocispec.MediaTypeImageIndex, b, "1.0.0")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		dst         string
+		expectedTag string
+	}{
+		{
+			name:        "another registry",
+			dst:         dstHost + "/production/dos-games",
+			expectedTag: "1.0.0",
+		},
+		{
+			name:        "another repository of the same registry",
+			dst:         srcHost + "/production/dos-games:stable",
+			expectedTag: "stable",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dst, err := NewRemote(tt.dst, oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+			desc, err := CopyPackageIndex(ctx, src, dst, 2)
+			require.NoError(t, err)
+			require.Equal(t, root.Digest, desc.Digest)
+
+			copied, err := dst.Repo().Resolve(ctx, tt.expectedTag)
+			require.NoError(t, err)
+			require.Equal(t, root.Digest, copied.Digest)
+			for _, manifest := range index.Manifests {
+				referrers, err := dst.Referrers(ctx, manifest)
+				require.NoError(t, err)
+				require.Len(t, referrers, 1)
+				require.Equal(t, SignatureArtifactType, referrers[0].Manifest.ArtifactType)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+const (
+	// ProvenanceFile is the title of the SLSA provenance statement within the provenance attached to a package.
+	ProvenanceFile = "provenance.json"
+	// ProvenanceStatementType is the in-toto statement type of the provenance of a package.
+	ProvenanceStatementType = "https://in-toto.io/Statement/v1"
+	// ProvenanceBuildType describes how the parameters of the provenance of a package are read.
+	ProvenanceBuildType = "https://docs.zarf.dev/ref/packages/#provenance"
+	// ProvenanceBuilderID identifies Zarf as the builder of a package.
+	ProvenanceBuilderID = "https://github.com/zarf-dev/zarf"
+)
+
+// ProvenanceParameters are the parameters a package was created with, the external parameters of its provenance.
+type ProvenanceParameters struct {
+	Package                    string            `json:"package"`
+	Version                    string            `json:"version,omitempty"`
+	Architecture               string            `json:"architecture"`
+	Flavor                     string            `json:"flavor,omitempty"`
+	RegistryOverrides          map[string]string `json:"registryOverrides,omitempty"`
+	DifferentialPackageVersion string            `json:"differentialPackageVersion,omitempty"`
+}
+
+// NewProvenance returns the SLSA v1 provenance of the package published as the subject to the named repository. The
+// statement only depends on the package and the subject, so publishing the same package again results in the same
+// statement.
+func NewProvenance(pkg *v1alpha1.ZarfPackage, name string, subject ocispec.Descriptor) in_toto.ProvenanceStatementSLSA1 {
+	dependencies := []slsa.ResourceDescriptor{}
+	for _, material := range pkg.Build.Materials {
+		dependencies = append(dependencies, slsa.ResourceDescriptor{URI: material.URI, Digest: common.DigestSet(material.Digest)})
+	}
+
+	metadata := slsa.BuildMetadata{}
+	if built, err := time.Parse(time.RFC1123Z, pkg.Build.Timestamp); err == nil {
+		built = built.UTC()
+		metadata.FinishedOn = &built
+	}
+
+	return in_toto.ProvenanceStatementSLSA1{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          ProvenanceStatementType,
+			PredicateType: slsa.PredicateSLSAProvenance,
+			Subject: []in_toto.Subject{{
+				Name:   name,
+				Digest: common.DigestSet{subject.Digest.Algorithm().String(): subject.Digest.Encoded()},
+			}},
+		},
+		Predicate: slsa.ProvenancePredicate{
+			BuildDefinition: slsa.ProvenanceBuildDefinition{
+				BuildType:            ProvenanceBuildType,
+				ExternalParameters:   provenanceParameters(pkg),
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: slsa.ProvenanceRunDetails{
+				Builder: slsa.Builder{
+					ID:      ProvenanceBuilderID,
+					Version: map[string]string{"zarf": pkg.Build.Version},
+				},
+				BuildMetadata: metadata,
+			},
+		},
+	}
+}
+
+// provenanceParameters returns the parameters the package was created with.
+func provenanceParameters(pkg *v1alpha1.ZarfPackage) ProvenanceParameters {
+	overrides := pkg.Build.RegistryOverrides
+	// Empty overrides are left out of the statement
+	if len(overrides) == 0 {
+		overrides = nil
+	}
+	return ProvenanceParameters{
+		Package:                    pkg.Metadata.Name,
+		Version:                    pkg.Metadata.Version,
+		Architecture:               pkg.Build.Architecture,
+		Flavor:                     pkg.Build.Flavor,
+		RegistryOverrides:          overrides,
+		DifferentialPackageVersion: pkg.Build.DifferentialPackageVersion,
+	}
+}
+
+// VerifyProvenance verifies that the provenance statement is the SLSA v1 provenance of the package published as the
+// subject, and that it records the parameters and materials the package was created with.
+func VerifyProvenance(statement in_toto.ProvenanceStatementSLSA1, pkg *v1alpha1.ZarfPackage, subject ocispec.Descriptor) error {
+	if statement.Type != ProvenanceStatementType {
+		return fmt.Errorf("the provenance is not an in-toto statement, its type is %q", statement.Type)
+	}
+	if statement.PredicateType != slsa.PredicateSLSAProvenance {
+		return fmt.Errorf("the provenance is not a SLSA v1 provenance, its predicate type is %q", statement.PredicateType)
+	}
+	buildDefinition := statement.Predicate.BuildDefinition
+	if buildDefinition.BuildType != ProvenanceBuildType {
+		return fmt.Errorf("the provenance was not made by Zarf, its build type is %q", buildDefinition.BuildType)
+	}
+
+	matched := false
+	for _, s := range statement.Subject {
+		if s.Digest[subject.Digest.Algorithm().String()] == subject.Digest.Encoded() {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return fmt.Errorf("the provenance does not describe the package %s", subject.Digest)
+	}
+
+	// The parameters are decoded again as they are read into a map from JSON
+	b, err := json.Marshal(buildDefinition.ExternalParameters)
+	if err != nil {
+		return err
+	}
+	var parameters ProvenanceParameters
+	if err := json.Unmarshal(b, &parameters); err != nil {
+		return fmt.Errorf("unable to read the parameters of the provenance: %w", err)
+	}
+	if !reflect.DeepEqual(parameters, provenanceParameters(pkg)) {
+		return errors.New("the parameters of the provenance do not match the package")
+	}
+
+	if len(buildDefinition.ResolvedDependencies) != len(pkg.Build.Materials) {
+		return fmt.Errorf("the provenance records %d materials but the package was built from %d", len(buildDefinition.ResolvedDependencies), len(pkg.Build.Materials))
+	}
+	for i, dependency := range buildDefinition.ResolvedDependencies {
+		material := pkg.Build.Materials[i]
+		if dependency.URI != material.URI || !reflect.DeepEqual(map[string]string(dependency.Digest), material.Digest) {
+			return fmt.Errorf("the material %s of the provenance does not match the package", dependency.URI)
+		}
+	}
+	return nil
+}
+
+// FetchProvenance returns the SLSA provenance statement attached to the subject.
+func (r *Remote) FetchProvenance(ctx context.Context, subject ocispec.Descriptor) (in_toto.ProvenanceStatementSLSA1, error) {
+	var statement in_toto.ProvenanceStatementSLSA1
+	referrers, err := r.Referrers(ctx, subject)
+	if err != nil {
+		return statement, fmt.Errorf("unable to discover the provenance attached to the package: %w", err)
+	}
+	for _, referrer := range referrers {
+		if referrer.Manifest.ArtifactType != ProvenanceArtifactType {
+			continue
+		}
+		layer := referrer.Locate(ProvenanceFile)
+		if oci.IsEmptyDescriptor(layer) {
+			continue
+		}
+		b, err := r.FetchLayer(ctx, layer)
+		if err != nil {
+			return statement, fmt.Errorf("unable to fetch the provenance attached to the package: %w", err)
+		}
+		if err := json.Unmarshal(b, &statement); err != nil {
+			return statement, fmt.Errorf("unable to read the provenance attached to the package: %w", err)
+		}
+		return statement, nil
+	}
+	return statement, fmt.Errorf("no provenance is attached to %s", r.Repo().Reference)
+}
+
+// attachProvenance attaches the SLSA provenance of the package to the subject.
+func (r *Remote) attachProvenance(ctx context.Context, pkg *v1alpha1.ZarfPackage, subject ocispec.Descriptor, annotations map[string]string) error {
+	ref := r.Repo().Reference
+	statement := NewProvenance(pkg, fmt.Sprintf("%s/%s", ref.Registry, ref.Repository), subject)
+	b, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, ProvenanceFile)
+	if err := os.WriteFile(path, b, helpers.ReadWriteUser); err != nil {
+		return err
+	}
+
+	files := map[string]string{ProvenanceFile: path}
+	_, err = r.AttachReferrer(ctx, subject, ProvenanceArtifactType, files, annotations)
+	return err
+}
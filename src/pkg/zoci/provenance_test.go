@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"oras.land/oras-go/v2"
+)
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	remote, err := NewRemote(host+"/dos-games:1.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	subject, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, ZarfConfigMediaType, oras.PackManifestOptions{})
+	require.NoError(t, err)
+	other, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, ZarfConfigMediaType, oras.PackManifestOptions{
+		ManifestAnnotations: map[string]string{ocispec.AnnotationTitle: "other"},
+	})
+	require.NoError(t, err)
+
+	pkg := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{Name: "dos-games", Version: "1.0.0"},
+		Build: v1alpha1.ZarfBuildData{
+			Architecture: "amd64",
+			Timestamp:    "Sat, 01 Jun 2024 12:00:00 +0000",
+			Version:      "v0.38.0",
+			Flavor:       "upstream",
+			Materials: []v1alpha1.ZarfBuildMaterial{
+				{URI: "git+https://github.com/defenseunicorns/zarf-public-test.git", Digest: map[string]string{"gitCommit": "d4ea3d5c2e8b7a1f4c1a4e2b3c5d6e7f8a9b0c1d"}},
+				{URI: "oci://ghcr.io/zarf-dev/doom-game:0.0.1", Digest: map[string]string{"sha256": "7e3d2f8b3e1a2c4d5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e"}},
+			},
+		},
+	}
+	require.NoError(t, remote.attachProvenance(ctx, &pkg, subject, nil))
+
+	statement, err := remote.FetchProvenance(ctx, subject)
+	require.NoError(t, err)
+	require.Equal(t, host+"/dos-games", statement.Subject[0].Name)
+	require.Equal(t, ProvenanceBuilderID, statement.Predicate.RunDetails.Builder.ID)
+	require.Equal(t, "2024-06-01T12:00:00Z", statement.Predicate.RunDetails.BuildMetadata.FinishedOn.Format(time.RFC3339))
+
+	_, err = remote.FetchProvenance(ctx, other)
+	require.EqualError(t, err, "no provenance is attached to "+host+"/dos-games:1.0.0")
+
+	tests := []struct {
+		name        string
+		modify      func(statement *in_toto.ProvenanceStatementSLSA1, pkg *v1alpha1.ZarfPackage)
+		subject     ocispec.Descriptor
+		expectedErr string
+	}{
+		{
+			name:    "provenance of the package",
+			subject: subject,
+		},
+		{
+			name:        "provenance of another package",
+			subject:     other,
+			expectedErr: "the provenance does not describe the package " + other.Digest.String(),
+		},
+		{
+			name: "other predicate type",
+			modify: func(statement *in_toto.ProvenanceStatementSLSA1, _ *v1alpha1.ZarfPackage) {
+				statement.PredicateType = "https://slsa.dev/provenance/v0.2"
+			},
+			subject:     subject,
+			expectedErr: "the provenance is not a SLSA v1 provenance, its predicate type is \"https://slsa.dev/provenance/v0.2\"",
+		},
+		{
+			name: "package built with another flavor",
+			modify: func(_ *in_toto.ProvenanceStatementSLSA1, pkg *v1alpha1.ZarfPackage) {
+				pkg.Build.Flavor = "registry1"
+			},
+			subject:     subject,
+			expectedErr: "the parameters of the provenance do not match the package",
+		},
+		{
+			name: "package built from another commit",
+			modify: func(_ *in_toto.ProvenanceStatementSLSA1, pkg *v1alpha1.ZarfPackage) {
+				pkg.Build.Materials[0].Digest = map[string]string{"gitCommit": "0000000000000000000000000000000000000000"}
+			},
+			subject:     subject,
+			expectedErr: "the material git+https://github.com/defenseunicorns/zarf-public-test.git of the provenance does not match the package",
+		},
+		{
+			name: "package built from fewer materials",
+			modify: func(_ *in_toto.ProvenanceStatementSLSA1, pkg *v1alpha1.ZarfPackage) {
+				pkg.Build.Materials = pkg.Build.Materials[:1]
+			},
+			subject:     subject,
+			expectedErr: "the provenance records 2 materials but the package was built from 1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statement, err := remote.FetchProvenance(ctx, subject)
+			require.NoError(t, err)
+			pkg := pkg
+			pkg.Build.Materials = append([]v1alpha1.ZarfBuildMaterial{}, pkg.Build.Materials...)
+			if tt.modify != nil {
+				tt.modify(&statement, &pkg)
+			}
+
+			err = VerifyProvenance(statement, &pkg, tt.subject)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
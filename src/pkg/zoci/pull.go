@@ -6,11 +6,13 @@ package zoci
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
@@ -103,9 +105,11 @@ func (r *Remote) LayersFromRequestedComponents(ctx context.Context, requestedCom
 	// Append the sboms.tar layer if it exists
 	//
 	// Since sboms.tar is not a heavy addition 99% of the time, we'll just always pull it
-	sbomsDescriptor := root.Locate(layout.SBOMTar)
-	if !oci.IsEmptyDescriptor(sbomsDescriptor) {
-		layers = append(layers, sbomsDescriptor)
+	for _, sbomTar := range layout.SBOMTarballs {
+		sbomsDescriptor := root.Locate(sbomTar)
+		if !oci.IsEmptyDescriptor(sbomsDescriptor) {
+			layers = append(layers, sbomsDescriptor)
+		}
 	}
 	if len(images) > 0 {
 		// Add the image index and the oci-layout layers
@@ -122,29 +126,62 @@ func (r *Remote) LayersFromRequestedComponents(ctx context.Context, requestedCom
 				return nil, fmt.Errorf("failed to parse image ref %q: %w", image, err)
 			}
 
-			manifestDescriptor := helpers.Find(index.Manifests, func(layer ocispec.Descriptor) bool {
+			// An image that resolved to an image index is stored both as the image for the package architecture and as the index
+			manifestDescriptors := helpers.Filter(index.Manifests, func(layer ocispec.Descriptor) bool {
 				return layer.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Reference ||
 					// A backwards compatibility shim for older Zarf versions that would leave docker.io off of image annotations
 					(layer.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Path+refInfo.TagOrDigest && refInfo.Host == "docker.io")
 			})
+			for _, manifestDescriptor := range manifestDescriptors {
+				imageLayers, err := r.imageLayers(ctx, root, manifestDescriptor)
+				if err != nil {
+					return nil, err
+				}
+				layers = append(layers, imageLayers...)
+			}
+		}
+	}
+	return layers, nil
+}
+
+// imageLayers returns the package layers that hold the image or image index with the given descriptor.
+func (r *Remote) imageLayers(ctx context.Context, root *oci.Manifest, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	isIndex := types.MediaType(desc.MediaType).IsIndex()
 
-			// even though these are technically image manifests, we store them as Zarf blobs
-			manifestDescriptor.MediaType = ZarfLayerMediaTypeBlob
+	// even though these are technically image manifests, we store them as Zarf blobs
+	desc.MediaType = ZarfLayerMediaTypeBlob
+	layers := []ocispec.Descriptor{root.Locate(filepath.Join(layout.ImagesBlobsDir, desc.Digest.Encoded()))}
 
-			manifest, err := r.FetchManifest(ctx, manifestDescriptor)
+	if isIndex {
+		index, err := oci.FetchUnmarshal[ocispec.Index](ctx, r.FetchLayer, json.Unmarshal, desc)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range index.Manifests {
+			childLayers, err := r.imageLayers(ctx, root, child)
 			if err != nil {
 				return nil, err
 			}
-			// Add the manifest and the manifest config layers
-			layers = append(layers, root.Locate(filepath.Join(layout.ImagesBlobsDir, manifestDescriptor.Digest.Encoded())))
-			layers = append(layers, root.Locate(filepath.Join(layout.ImagesBlobsDir, manifest.Config.Digest.Encoded())))
-
-			// Add all the layers from the manifest
-			for _, layer := range manifest.Layers {
-				layerPath := filepath.Join(layout.ImagesBlobsDir, layer.Digest.Encoded())
-				layers = append(layers, root.Locate(layerPath))
-			}
+			layers = append(layers, childLayers...)
+		}
+		return layers, nil
+	}
+
+	manifest, err := r.FetchManifest(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	// Add the manifest config layer
+	layers = append(layers, root.Locate(filepath.Join(layout.ImagesBlobsDir, manifest.Config.Digest.Encoded())))
+
+	// Add all the layers from the manifest, a differential package leaves out the layers of the package it was created from
+	for _, layer := range manifest.Layers {
+		layerPath := filepath.Join(layout.ImagesBlobsDir, layer.Digest.Encoded())
+		desc := root.Locate(layerPath)
+		if oci.IsEmptyDescriptor(desc) {
+			continue
 		}
+		layers = append(layers, desc)
 	}
 	return layers, nil
 }
@@ -156,5 +193,5 @@ func (r *Remote) PullPackageMetadata(ctx context.Context, destinationDir string)
 
 // PullPackageSBOM pulls the package's sboms.tar from the remote repository and saves it to `destinationDir`.
 func (r *Remote) PullPackageSBOM(ctx context.Context, destinationDir string) ([]ocispec.Descriptor, error) {
-	return r.PullPaths(ctx, destinationDir, []string{layout.SBOMTar})
+	return r.PullPaths(ctx, destinationDir, layout.SBOMTarballs)
 }
@@ -32,6 +32,7 @@ func (r *Remote) PublishPackage(ctx context.Context, pkg *v1alpha1.ZarfPackage,
 
 	// Get all of the layers in the package
 	var descs []ocispec.Descriptor
+	layerPaths := map[string]string{}
 	for name, path := range paths.Files() {
 		spinner.Updatef("Preparing layer %s", helpers.First30Last30(name))
 
@@ -42,22 +43,27 @@ func (r *Remote) PublishPackage(ctx context.Context, pkg *v1alpha1.ZarfPackage,
 			return err
 		}
 		descs = append(descs, desc)
+		layerPaths[desc.Digest.String()] = path
 	}
 	spinner.Successf("Prepared all layers")
 
 	copyOpts := r.GetDefaultCopyOpts()
 	copyOpts.Concurrency = concurrency
+	// Layers larger than a chunk are uploaded in chunks, so a failed upload resumes where it failed
+	copyOpts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		path, ok := layerPaths[desc.Digest.String()]
+		if r.ChunkSize <= 0 || desc.Size <= r.ChunkSize || !ok {
+			return nil
+		}
+		if err := r.PushChunked(ctx, desc, path); err != nil {
+			return err
+		}
+		return oras.SkipNode
+	}
 	total := oci.SumDescsSize(descs)
 
 	annotations := annotationsFromMetadata(&pkg.Metadata)
 
-	// assumes referrers API is not supported since OCI artifact
-	// media type is not supported
-	err = r.Repo().SetReferrersCapability(false)
-	if err != nil {
-		return err
-	}
-
 	// push the manifest config
 	manifestConfigDesc, err := r.CreateAndPushManifestConfig(ctx, annotations, ZarfConfigMediaType)
 	if err != nil {
@@ -84,6 +90,10 @@ func (r *Remote) PublishPackage(ctx context.Context, pkg *v1alpha1.ZarfPackage,
 		return err
 	}
 
+	if err := r.attachPackageReferrers(ctx, pkg, paths, publishedDesc); err != nil {
+		return err
+	}
+
 	progressBar.Successf("Published %s [%s]", r.Repo().Reference, ZarfLayerMediaTypeBlob)
 	return nil
 }
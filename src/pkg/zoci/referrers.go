@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+)
+
+// The artifact types of the signatures, SBOMs and provenance attached to packages as OCI referrers.
+const (
+	SignatureArtifactType  = "application/vnd.zarf.signature.v1"
+	SBOMArtifactType       = "application/vnd.zarf.sbom.v1"
+	ProvenanceArtifactType = "application/vnd.zarf.provenance.v1"
+)
+
+// Referrer is an artifact that refers to a package, such as a signature, SBOM or provenance.
+type Referrer struct {
+	// The descriptor of the manifest of the referrer.
+	Descriptor ocispec.Descriptor
+	// The manifest of the referrer, its layers are the files of the artifact.
+	Manifest ocispec.Manifest
+}
+
+// Locate returns the layer of the referrer with the given title, or an empty descriptor when there is none.
+func (ref Referrer) Locate(title string) ocispec.Descriptor {
+	for _, layer := range ref.Manifest.Layers {
+		if layer.Annotations[ocispec.AnnotationTitle] == title {
+			return layer
+		}
+	}
+	return ocispec.Descriptor{}
+}
+
+// AttachReferrer pushes the files, keyed by their title, as an artifact of the given type that refers to the subject.
+// Registries that support the OCI 1.1 referrers API index the artifact themselves, for other registries the artifact
+// is added to the referrers tag of the subject. Files that are already in the repository are not pushed again.
+func (r *Remote) AttachReferrer(ctx context.Context, subject ocispec.Descriptor, artifactType string, files map[string]string, annotations map[string]string) (ocispec.Descriptor, error) {
+	titles := []string{}
+	for title := range files {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	layers := []ocispec.Descriptor{}
+	for _, title := range titles {
+		desc, err := r.pushFile(ctx, files[title], title)
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("unable to push %s: %w", title, err)
+		}
+		layers = append(layers, desc)
+	}
+
+	opts := oras.PackManifestOptions{
+		Subject:             &subject,
+		Layers:              layers,
+		ManifestAnnotations: annotations,
+	}
+	return oras.PackManifest(ctx, r.Repo(), oras.PackManifestVersion1_1, artifactType, opts)
+}
+
+// pushFile pushes the file at path as a blob with the given title unless the repository already has it.
+func (r *Remote) pushFile(ctx context.Context, path, title string) (ocispec.Descriptor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dgst, err := digest.FromReader(f)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType:   ZarfLayerMediaTypeBlob,
+		Digest:      dgst,
+		Size:        fi.Size(),
+		Annotations: map[string]string{ocispec.AnnotationTitle: title},
+	}
+	exists, err := r.Repo().Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if exists {
+		return desc, nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, r.Repo().Push(ctx, desc, f)
+}
+
+// Referrers returns the artifacts that refer to the subject, found through the OCI 1.1 referrers API or, when the
+// registry does not support it, the referrers tag of the subject. The manifest of each referrer is verified against
+// its digest and must name the subject.
+func (r *Remote) Referrers(ctx context.Context, subject ocispec.Descriptor) ([]Referrer, error) {
+	descs := []ocispec.Descriptor{}
+	err := r.Repo().Referrers(ctx, subject, "", func(referrers []ocispec.Descriptor) error {
+		descs = append(descs, referrers...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referrers := []Referrer{}
+	for _, desc := range descs {
+		// Only manifests can describe the files of an artifact
+		if desc.MediaType != ocispec.MediaTypeImageManifest {
+			continue
+		}
+		b, err := content.FetchAll(ctx, r.Repo(), desc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch the referrer %s: %w", desc.Digest, err)
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, fmt.Errorf("unable to read the referrer %s: %w", desc.Digest, err)
+		}
+		if manifest.Subject == nil || manifest.Subject.Digest != subject.Digest {
+			return nil, fmt.Errorf("the referrer %s does not refer to %s", desc.Digest, subject.Digest)
+		}
+		referrers = append(referrers, Referrer{Descriptor: desc, Manifest: manifest})
+	}
+	return referrers, nil
+}
+
+// attachPackageReferrers attaches the signature, SBOMs and provenance of the package to its manifest as referrers, so
+// they can be discovered with the referrers API. The artifacts are created at the build time of the package, so publishing the
+// package again does not attach them twice.
+func (r *Remote) attachPackageReferrers(ctx context.Context, pkg *v1alpha1.ZarfPackage, paths *layout.PackagePaths, subject ocispec.Descriptor) error {
+	annotations := map[string]string{}
+	if built, err := time.Parse(time.RFC1123Z, pkg.Build.Timestamp); err == nil {
+		annotations[ocispec.AnnotationCreated] = built.UTC().Format(time.RFC3339)
+	}
+
+	if paths.Signature != "" {
+		files := map[string]string{layout.Signature: paths.Signature}
+		if _, err := r.AttachReferrer(ctx, subject, SignatureArtifactType, files, annotations); err != nil {
+			return fmt.Errorf("unable to attach the signature of the package: %w", err)
+		}
+	}
+	if paths.SBOMs.IsTarball() {
+		files := map[string]string{filepath.Base(paths.SBOMs.Path): paths.SBOMs.Path}
+		if _, err := r.AttachReferrer(ctx, subject, SBOMArtifactType, files, annotations); err != nil {
+			return fmt.Errorf("unable to attach the SBOMs of the package: %w", err)
+		}
+	}
+	if err := r.attachProvenance(ctx, pkg, subject, annotations); err != nil {
+		return fmt.Errorf("unable to attach the provenance of the package: %w", err)
+	}
+	return nil
+}
+
+// copyReferrers copies the artifacts that refer to the package in src to the same package in dst.
+func copyReferrers(ctx context.Context, src *Remote, dst *Remote, subject ocispec.Descriptor, concurrency int) error {
+	referrers, err := src.Referrers(ctx, subject)
+	if err != nil {
+		return err
+	}
+	copyOpts := src.GetDefaultCopyOpts()
+	copyOpts.Concurrency = concurrency
+	for _, referrer := range referrers {
+		if _, err := oras.Copy(ctx, src.Repo(), referrer.Descriptor.Digest.String(), dst.Repo(), "", copyOpts); err != nil {
+			return fmt.Errorf("unable to copy the referrer %s: %w", referrer.Descriptor.Digest, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestReferrers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		referrersSupport  bool
+		expectedFallbacks int
+	}{
+		{
+			name:             "referrers API",
+			referrersSupport: true,
+		},
+		{
+			name:              "referrers tag fallback",
+			referrersSupport:  false,
+			expectedFallbacks: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			srv := httptest.NewServer(registry.New(registry.WithReferrersSupport(tt.referrersSupport)))
+			t.Cleanup(srv.Close)
+			host := strings.TrimPrefix(srv.URL, "http://")
+
+			remote, err := NewRemote(host+"/test:1.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+			subject, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, "application/vnd.zarf.test", oras.PackManifestOptions{})
+			require.NoError(t, err)
+			require.NoError(t, remote.Repo().Tag(ctx, subject, "1.0.0"))
+
+			referrers, err := remote.Referrers(ctx, subject)
+			require.NoError(t, err)
+			require.Empty(t, referrers)
+
+			sig := filepath.Join(t.TempDir(), "zarf.yaml.sig")
+			require.NoError(t, os.WriteFile(sig, []byte("signature"), 0o644))
+			annotations := map[string]string{ocispec.AnnotationCreated: "2024-06-01T12:00:00Z"}
+			desc, err := remote.AttachReferrer(ctx, subject, SignatureArtifactType, map[string]string{"zarf.yaml.sig": sig}, annotations)
+			require.NoError(t, err)
+
+			// Attaching the same files at the same time again does not add another referrer
+			again, err := remote.AttachReferrer(ctx, subject, SignatureArtifactType, map[string]string{"zarf.yaml.sig": sig}, annotations)
+			require.NoError(t, err)
+			require.Equal(t, desc.Digest, again.Digest)
+
+			referrers, err = remote.Referrers(ctx, subject)
+			require.NoError(t, err)
+			require.Len(t, referrers, 1)
+			require.Equal(t, desc.Digest, referrers[0].Descriptor.Digest)
+			require.Equal(t, SignatureArtifactType, referrers[0].Manifest.ArtifactType)
+			layer := referrers[0].Locate("zarf.yaml.sig")
+			b, err := remote.FetchLayer(ctx, layer)
+			require.NoError(t, err)
+			require.Equal(t, "signature", string(b))
+			require.True(t, oci.IsEmptyDescriptor(referrers[0].Locate("missing")))
+
+			// Registries without the referrers API list the referrers in the referrers tag of the subject
+			fallbacks := 0
+			err = remote.Repo().Tags(ctx, "", func(tags []string) error {
+				for _, tag := range tags {
+					if tag == "sha256-"+subject.Digest.Encoded() {
+						fallbacks++
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedFallbacks, fallbacks)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/defenseunicorns/pkg/oci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// LatestStable is the version range that selects the highest version of a package that is not a prerelease.
+const LatestStable = "latest-stable"
+
+// tagRegexp matches valid OCI tags, a tag that does not match is a version range.
+var tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// splitVersionRange splits an OCI package url into its repository and the semver range in place of its tag. The range
+// is empty when the url has a digest or a tag that is valid in OCI.
+func splitVersionRange(url string) (repository, versionRange string) {
+	raw := strings.TrimPrefix(url, helpers.OCIURLPrefix)
+	if strings.Contains(raw, "@") {
+		return raw, ""
+	}
+	i := strings.LastIndex(raw, ":")
+	if i < 0 || strings.Contains(raw[i+1:], "/") {
+		return raw, ""
+	}
+	tag := raw[i+1:]
+	if tag != LatestStable && tagRegexp.MatchString(tag) {
+		return raw, ""
+	}
+	return raw[:i], tag
+}
+
+// IsVersionRange returns whether the OCI package url has a semver range such as ^1.2 or latest-stable in place of its tag.
+func IsVersionRange(url string) bool {
+	_, versionRange := splitVersionRange(url)
+	return versionRange != ""
+}
+
+// ResolveVersionRange returns the url with its semver range replaced by the highest version tagged in the repository
+// that satisfies it. Urls without a range are returned as they are.
+func ResolveVersionRange(ctx context.Context, url string, mods ...oci.Modifier) (string, error) {
+	repository, versionRange := splitVersionRange(url)
+	if versionRange == "" {
+		return url, nil
+	}
+	remote, err := NewRemote(repository, ocispec.Platform{}, mods...)
+	if err != nil {
+		return "", err
+	}
+	tag, err := remote.ResolveVersion(ctx, versionRange)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s:%s", helpers.OCIURLPrefix, repository, tag), nil
+}
+
+// ResolveVersion returns the tag of the highest version in the repository that satisfies the semver range. Tags that
+// are not versions are ignored, and like prereleases, the tags of flavored packages are only selected by ranges that
+// include a prerelease.
+func (r *Remote) ResolveVersion(ctx context.Context, versionRange string) (string, error) {
+	if versionRange == LatestStable {
+		versionRange = "*"
+	}
+	constraint, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return "", fmt.Errorf("invalid version range %q: %w", versionRange, err)
+	}
+
+	var highest *semver.Version
+	highestTag := ""
+	err = r.Repo().Tags(ctx, "", func(tags []string) error {
+		for _, tag := range tags {
+			version, err := semver.NewVersion(tag)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+			if highest == nil || version.GreaterThan(highest) {
+				highest = version
+				highestTag = tag
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to list the versions of %s: %w", r.Repo().Reference, err)
+	}
+	if highestTag == "" {
+		return "", fmt.Errorf("no version of %s satisfies %q", r.Repo().Reference, versionRange)
+	}
+	return highestTag, nil
+}
+
+// PinnedReference returns the url of the package with the digest its tag currently resolves to, so later pulls get
+// the exact same package even if the tag is moved.
+func (r *Remote) PinnedReference(ctx context.Context) (string, error) {
+	ref := r.Repo().Reference
+	desc, err := r.Repo().Resolve(ctx, ref.Reference)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %s: %w", ref, err)
+	}
+	if ref.ValidateReferenceAsDigest() == nil {
+		return fmt.Sprintf("%s%s/%s@%s", helpers.OCIURLPrefix, ref.Registry, ref.Repository, desc.Digest), nil
+	}
+	return fmt.Sprintf("%s%s/%s:%s@%s", helpers.OCIURLPrefix, ref.Registry, ref.Repository, ref.Reference, desc.Digest), nil
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+)
+
+func TestSplitVersionRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url                string
+		expectedRepository string
+		expectedRange      string
+	}{
+		{
+			url:                "oci://ghcr.io/zarf-dev/packages/dos-games:^1.2",
+			expectedRepository: "ghcr.io/zarf-dev/packages/dos-games",
+			expectedRange:      "^1.2",
+		},
+		{
+			url:                "oci://localhost:5000/dos-games:latest-stable",
+			expectedRepository: "localhost:5000/dos-games",
+			expectedRange:      "latest-stable",
+		},
+		{
+			url:                "oci://localhost:5000/dos-games:>=1.0.0, <2.0.0",
+			expectedRepository: "localhost:5000/dos-games",
+			expectedRange:      ">=1.0.0, <2.0.0",
+		},
+		{
+			url:                "oci://ghcr.io/zarf-dev/packages/dos-games:1.2.0",
+			expectedRepository: "ghcr.io/zarf-dev/packages/dos-games:1.2.0",
+		},
+		{
+			url:                "oci://localhost:5000/dos-games",
+			expectedRepository: "localhost:5000/dos-games",
+		},
+		{
+			url:                "oci://ghcr.io/dos-games@sha256:3e5a6ba8ff9e0e4b2d8e29fb2e5c1f4b4e1c1a61b9c0e6b3d0b5e0d1d2c3b4a5",
+			expectedRepository: "ghcr.io/dos-games@sha256:3e5a6ba8ff9e0e4b2d8e29fb2e5c1f4b4e1c1a61b9c0e6b3d0b5e0d1d2c3b4a5",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+
+			repository, versionRange := splitVersionRange(tt.url)
+			require.Equal(t, tt.expectedRepository, repository)
+			require.Equal(t, tt.expectedRange, versionRange)
+			require.Equal(t, tt.expectedRange != "", IsVersionRange(tt.url))
+		})
+	}
+}
+
+func TestResolveVersionRange(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	remote, err := NewRemote(host+"/dos-games", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	for _, tag := range []string{"1.0.0", "1.2.0", "1.2.5", "1.3.0-rc.1", "1.3.0-upstream", "2.0.0", "latest"} {
+		opts := oras.PackManifestOptions{ManifestAnnotations: map[string]string{"tag": tag}}
+		desc, err := oras.PackManifest(ctx, remote.Repo(), oras.PackManifestVersion1_1, "application/vnd.zarf.test", opts)
+		require.NoError(t, err)
+		require.NoError(t, remote.Repo().Tag(ctx, desc, tag))
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		expectedURL string
+		expectedErr string
+	}{
+		{
+			name:        "caret range",
+			url:         "oci://" + host + "/dos-games:^1.2",
+			expectedURL: "oci://" + host + "/dos-games:1.2.5",
+		},
+		{
+			name:        "latest stable",
+			url:         "oci://" + host + "/dos-games:latest-stable",
+			expectedURL: "oci://" + host + "/dos-games:2.0.0",
+		},
+		{
+			name:        "prerelease range",
+			url:         "oci://" + host + "/dos-games:~1.3.0-0",
+			expectedURL: "oci://" + host + "/dos-games:1.3.0-upstream",
+		},
+		{
+			name:        "concrete tag",
+			url:         "oci://" + host + "/dos-games:1.0.0",
+			expectedURL: "oci://" + host + "/dos-games:1.0.0",
+		},
+		{
+			name:        "unsatisfied range",
+			url:         "oci://" + host + "/dos-games:>=3",
+			expectedErr: "no version of " + host + "/dos-games satisfies \">=3\"",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			url, err := ResolveVersionRange(ctx, tt.url, oci.WithPlainHTTP(true))
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedURL, url)
+		})
+	}
+
+	pinnedRemote, err := NewRemote(host+"/dos-games:2.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	pinned, err := pinnedRemote.PinnedReference(ctx)
+	require.NoError(t, err)
+	desc, err := remote.Repo().Resolve(ctx, "2.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "oci://"+host+"/dos-games:2.0.0@"+desc.Digest.String(), pinned)
+
+	// A pinned reference resolves to the same package
+	again, err := NewRemote(strings.TrimPrefix(pinned, "oci://"), oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+	repinned, err := again.PinnedReference(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "oci://"+host+"/dos-games@"+desc.Digest.String(), repinned)
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// errUploadExpired is returned when the registry no longer knows an upload, which has to be started over.
+var errUploadExpired = errors.New("the upload expired")
+
+// PushChunked pushes the blob at path in chunks of ChunkSize with the chunked upload of the OCI distribution spec.
+// When a chunk fails, the upload resumes from the last chunk the registry received rather than from the start, up to
+// the default number of retries.
+func (r *Remote) PushChunked(ctx context.Context, desc ocispec.Descriptor, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx = auth.AppendRepositoryScope(ctx, r.Repo().Reference, auth.ActionPull, auth.ActionPush)
+	location, err := r.startUpload(ctx)
+	if err != nil {
+		return err
+	}
+	offset := int64(0)
+	for attempt := 0; offset < desc.Size; {
+		size := min(r.ChunkSize, desc.Size-offset)
+		next, received, err := r.uploadChunk(ctx, location, io.NewSectionReader(f, offset, size), offset, size)
+		if err == nil {
+			location, offset, attempt = next, received, 0
+			continue
+		}
+		if ctx.Err() != nil || attempt >= config.ZarfDefaultRetries {
+			return fmt.Errorf("unable to upload %s: %w", desc.Digest, err)
+		}
+		attempt++
+		message.Debugf("Uploading %s failed at %d of %d bytes, resuming (%d/%d): %s", desc.Digest, offset, desc.Size, attempt, config.ZarfDefaultRetries, err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+
+		offset, err = r.uploadProgress(ctx, location)
+		if errors.Is(err, errUploadExpired) {
+			message.Debugf("The upload of %s expired, starting it over", desc.Digest)
+			location, err = r.startUpload(ctx)
+			offset = 0
+		}
+		if err != nil {
+			return fmt.Errorf("unable to resume the upload of %s: %w", desc.Digest, err)
+		}
+	}
+	return r.finishUpload(ctx, location, desc)
+}
+
+// startUpload starts an upload session and returns its location.
+func (r *Remote) startUpload(ctx context.Context) (*url.URL, error) {
+	ref := r.Repo().Reference
+	scheme := "https"
+	if r.Repo().PlainHTTP {
+		scheme = "http"
+	}
+	uploads := &url.URL{Scheme: scheme, Host: ref.Host(), Path: fmt.Sprintf("/v2/%s/blobs/uploads/", ref.Repository)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploads.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.Repo().Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("unable to start an upload to %s: %s", ref, resp.Status)
+	}
+	return uploadLocation(resp)
+}
+
+// uploadChunk uploads the chunk at offset and returns the location of the next chunk and the number of bytes the
+// registry has received.
+func (r *Remote) uploadChunk(ctx context.Context, location *url.URL, chunk *io.SectionReader, offset, size int64) (*url.URL, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, location.String(), chunk)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.ContentLength = size
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(chunk, 0, size)), nil
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, offset+size-1))
+	resp, err := r.Repo().Client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	// The distribution spec answers a chunk with 202, some registries answer it with 204
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		return nil, 0, fmt.Errorf("the registry rejected the chunk at %d: %s", offset, resp.Status)
+	}
+	next, err := uploadLocation(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	received, ok := receivedRange(resp.Header.Get("Range"))
+	if !ok {
+		received = offset + size
+	}
+	return next, received, nil
+}
+
+// uploadProgress returns the number of bytes of the upload the registry has received.
+func (r *Remote) uploadProgress(ctx context.Context, location *url.URL) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := r.Repo().Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		received, _ := receivedRange(resp.Header.Get("Range"))
+		return received, nil
+	case http.StatusNotFound:
+		return 0, errUploadExpired
+	default:
+		return 0, fmt.Errorf("unable to get the status of the upload: %s", resp.Status)
+	}
+}
+
+// finishUpload completes the upload with the digest of the blob.
+func (r *Remote) finishUpload(ctx context.Context, location *url.URL, desc ocispec.Descriptor) error {
+	finish := *location
+	query := finish.Query()
+	query.Set("digest", desc.Digest.String())
+	finish.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, finish.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.Repo().Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("unable to finish the upload of %s: %s", desc.Digest, resp.Status)
+	}
+	return nil
+}
+
+// uploadLocation returns the location of an upload from a response, which may be relative to the request.
+func uploadLocation(resp *http.Response) (*url.URL, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errors.New("the registry did not return the location of the upload")
+	}
+	return resp.Request.URL.Parse(location)
+}
+
+// receivedRange parses the Range header of an upload, 0-<last byte received>, into the number of bytes received.
+// Registries also return 0-0 for an upload that has not received any bytes, which is read as such since chunks are
+// larger than a byte.
+func receivedRange(value string) (int64, bool) {
+	_, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, false
+	}
+	last, err := strconv.ParseInt(end, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if last == 0 {
+		return 0, true
+	}
+	return last + 1, true
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func TestPushChunked(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		failure         string
+		expectedPatches int
+	}{
+		{
+			name:            "every chunk is received",
+			expectedPatches: 3,
+		},
+		{
+			name:            "response to a chunk is lost",
+			failure:         "lost",
+			expectedPatches: 3,
+		},
+		{
+			name:            "upload expires",
+			failure:         "expired",
+			expectedPatches: 5,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			patches := 0
+			received := map[string]string{}
+			reg := registry.New()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				// The registry does not report the status of uploads, so it is answered from the chunks it received
+				if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/uploads/") {
+					if tt.failure == "expired" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.Header().Set("Range", received[r.URL.Path])
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				if r.Method != http.MethodPatch {
+					reg.ServeHTTP(w, r)
+					return
+				}
+				patches++
+				if patches == 2 && tt.failure == "expired" {
+					dropConnection(t, w)
+					return
+				}
+				rec := httptest.NewRecorder()
+				reg.ServeHTTP(rec, r)
+				if rec.Code == http.StatusNoContent {
+					received[r.URL.Path] = rec.Header().Get("Range")
+				}
+				if patches == 2 && tt.failure == "lost" {
+					dropConnection(t, w)
+					return
+				}
+				for key, values := range rec.Header() {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(rec.Code)
+				_, _ = w.Write(rec.Body.Bytes())
+			}))
+			t.Cleanup(srv.Close)
+			host := strings.TrimPrefix(srv.URL, "http://")
+
+			remote, err := NewRemote(host+"/dos-games:1.0.0", oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+			require.NoError(t, err)
+			remote.ChunkSize = 4
+
+			b := []byte("0123456789")
+			path := filepath.Join(t.TempDir(), "images.tar")
+			require.NoError(t, os.WriteFile(path, b, 0o644))
+			desc := content.NewDescriptorFromBytes(ZarfLayerMediaTypeBlob, b)
+
+			ctx := context.Background()
+			require.NoError(t, remote.PushChunked(ctx, desc, path))
+			require.Equal(t, tt.expectedPatches, patches)
+
+			rc, err := remote.Repo().Fetch(ctx, desc)
+			require.NoError(t, err)
+			defer rc.Close()
+			pushed, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.Equal(t, b, pushed)
+		})
+	}
+}
+
+// dropConnection closes the connection of a request without a response, as a flaky link would.
+func dropConnection(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+
+	conn, _, err := w.(http.Hijacker).Hijack()
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestReceivedRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value            string
+		expectedReceived int64
+		expectedOK       bool
+	}{
+		{value: "0-1023", expectedReceived: 1024, expectedOK: true},
+		{value: "0-0", expectedReceived: 0, expectedOK: true},
+		{value: "", expectedOK: false},
+		{value: "0-", expectedOK: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			received, ok := receivedRange(tt.value)
+			require.Equal(t, tt.expectedOK, ok)
+			require.Equal(t, tt.expectedReceived, received)
+		})
+	}
+}
@@ -45,3 +45,18 @@ func ReferenceFromMetadata(registryLocation string, metadata *v1alpha1.ZarfMetad
 func GetInitPackageURL(version string) string {
 	return fmt.Sprintf("ghcr.io/zarf-dev/packages/init:%s", version)
 }
+
+// GetInitPackageURLFromMirror returns the URL for the init package for the given version from a mirror repository.
+//
+// If the mirror reference does not already contain a tag or digest, the version is used as the tag.
+func GetInitPackageURLFromMirror(mirror, version string) (string, error) {
+	raw := strings.TrimPrefix(mirror, helpers.OCIURLPrefix)
+	ref, err := registry.ParseReference(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", raw, err)
+	}
+	if ref.Reference == "" {
+		ref.Reference = version
+	}
+	return helpers.OCIURLPrefix + ref.String(), nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package zoci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInitPackageURLFromMirror(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		mirror      string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "repository",
+			mirror:   "oci://registry.example.com/zarf-dev/packages/init",
+			expected: "oci://registry.example.com/zarf-dev/packages/init:v0.42.0",
+		},
+		{
+			name:     "repository without the oci prefix",
+			mirror:   "localhost:5000/init",
+			expected: "oci://localhost:5000/init:v0.42.0",
+		},
+		{
+			name:     "tag",
+			mirror:   "oci://registry.example.com/zarf-dev/packages/init:v0.41.0",
+			expected: "oci://registry.example.com/zarf-dev/packages/init:v0.41.0",
+		},
+		{
+			name:     "digest",
+			mirror:   "oci://registry.example.com/init@sha256:3e0d7e8ff7d6a1a0e0e8a1c0e4e1c1f5b0a0e3a0c7f8b5d6e2a9f0b1c2d3e4f5",
+			expected: "oci://registry.example.com/init@sha256:3e0d7e8ff7d6a1a0e0e8a1c0e4e1c1f5b0a0e3a0c7f8b5d6e2a9f0b1c2d3e4f5",
+		},
+		{
+			name:        "invalid reference",
+			mirror:      "oci://Registry.Example.com/Init",
+			expectedErr: "failed to parse Registry.Example.com/Init",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			url, err := GetInitPackageURLFromMirror(tt.mirror, "v0.42.0")
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, url)
+		})
+	}
+}
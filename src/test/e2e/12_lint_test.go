@@ -39,8 +39,8 @@ func TestLint(t *testing.T) {
 		require.Contains(t, strippedStderr, lang.UnsetVarLintWarning)
 		require.Contains(t, strippedStderr, fmt.Sprintf(lang.PkgValidateTemplateDeprecation, key, key, key))
 		require.Contains(t, strippedStderr, ".components.[2].repos.[0] | Unpinned repository")
-		require.Contains(t, strippedStderr, ".metadata | Additional property description1 is not allowed")
-		require.Contains(t, strippedStderr, ".components.[0].import | Additional property not-path is not allowed")
+		require.Contains(t, strippedStderr, `.metadata.description1 | Additional property description1 is not allowed. Did you mean "description"?`)
+		require.Contains(t, strippedStderr, ".components.[0].import.not-path | Additional property not-path is not allowed")
 		// Testing the import / compose on lint is working
 		require.Contains(t, strippedStderr, ".components.[1].images.[0] | Image not pinned with digest - registry.com:9001/whatever/image:latest")
 		// Testing import / compose + variables are working
@@ -5,7 +5,12 @@
 package types
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
+	"slices"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -35,6 +40,50 @@ const (
 	ComponentStatusRemoving  ComponentStatus = "Removing"
 )
 
+// DeployPhase is a phase of a component deployment that Zarf records the duration of.
+type DeployPhase string
+
+// All the different phases of a Zarf Component deployment, in the order they run.
+const (
+	DeployPhaseActions        DeployPhase = "actions"
+	DeployPhaseFiles          DeployPhase = "files"
+	DeployPhaseImages         DeployPhase = "images"
+	DeployPhaseRepos          DeployPhase = "repos"
+	DeployPhaseArtifacts      DeployPhase = "artifacts"
+	DeployPhaseOperators      DeployPhase = "operators"
+	DeployPhaseCharts         DeployPhase = "charts"
+	DeployPhaseDataInjections DeployPhase = "dataInjections"
+	DeployPhasePlugins        DeployPhase = "plugins"
+	DeployPhaseHealthChecks   DeployPhase = "healthChecks"
+	DeployPhaseWebhooks       DeployPhase = "webhooks"
+	DeployPhaseTotal          DeployPhase = "total"
+)
+
+// DeployPhases lists the phases of a Zarf Component deployment in display order.
+var DeployPhases = []DeployPhase{
+	DeployPhaseActions,
+	DeployPhaseFiles,
+	DeployPhaseImages,
+	DeployPhaseRepos,
+	DeployPhaseArtifacts,
+	DeployPhaseOperators,
+	DeployPhaseCharts,
+	DeployPhaseDataInjections,
+	DeployPhasePlugins,
+	DeployPhaseHealthChecks,
+	DeployPhaseWebhooks,
+	DeployPhaseTotal,
+}
+
+// SkippableDeployPhases lists the phases of a Zarf Component deployment that can be skipped to recover a partially
+// deployed package without repeating the phases that already succeeded.
+var SkippableDeployPhases = []DeployPhase{
+	DeployPhaseImages,
+	DeployPhaseRepos,
+	DeployPhaseArtifacts,
+	DeployPhaseCharts,
+}
+
 // Values during setup of the initial zarf state
 const (
 	ZarfGeneratedPasswordLen               = 24
@@ -42,6 +91,7 @@ const (
 	ZarfInClusterContainerRegistryNodePort = 31999
 	ZarfRegistryPushUser                   = "zarf-push"
 	ZarfRegistryPullUser                   = "zarf-pull"
+	ZarfRegistryNamespacePullUserPrefix    = "zarf-pull-"
 
 	ZarfGitPushUser = "zarf-git-user"
 	ZarfGitReadUser = "zarf-git-read-user"
@@ -57,7 +107,8 @@ type GeneratedPKI struct {
 	Key  []byte `json:"key"`
 }
 
-// ZarfState is maintained as a secret in the Zarf namespace to track Zarf init data.
+// ZarfState is maintained as a ZarfState custom resource in the Zarf namespace to track Zarf init data, with its
+// sensitive fields in a linked secret.
 type ZarfState struct {
 	// Indicates if Zarf was initialized while deploying its own k8s cluster
 	ZarfAppliance bool `json:"zarfAppliance"`
@@ -74,8 +125,92 @@ type ZarfState struct {
 	GitServer GitServerInfo `json:"gitServer"`
 	// Information about the container registry Zarf is configured to use
 	RegistryInfo RegistryInfo `json:"registryInfo"`
+	// Mirrors of upstream registries, image references on a mirror are resolved back to their upstream registry by the agent
+	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
 	// Information about the artifact registry Zarf is configured to use
 	ArtifactServer ArtifactServerInfo `json:"artifactServer"`
+	// HTTP proxy the init components are templated with
+	Proxy ProxyInfo `json:"proxy,omitempty"`
+	// Namespaces Zarf-managed image and git pull secrets are written to, every namespace when empty
+	SecretNamespaces NamespaceFilter `json:"secretNamespaces,omitempty"`
+}
+
+// NamespaceFilter selects namespaces by allow and deny lists of glob patterns (e.g. team-*).
+type NamespaceFilter struct {
+	// Patterns of the namespaces that are allowed, every namespace is allowed when empty
+	Allow []string `json:"allow,omitempty"`
+	// Patterns of the namespaces that are denied, taking precedence over allow
+	Deny []string `json:"deny,omitempty"`
+}
+
+// Validate returns an error if any of the patterns are malformed.
+func (f NamespaceFilter) Validate() error {
+	for _, pattern := range append(slices.Clone(f.Allow), f.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Allows returns true if the namespace matches an allow pattern, or there are none, and does not match a deny pattern.
+func (f NamespaceFilter) Allows(namespace string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, namespace); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(f.Allow) > 0 && !matches(f.Allow) {
+		return false
+	}
+	return !matches(f.Deny)
+}
+
+// ZarfStateStatus is the status of the ZarfState custom resource.
+type ZarfStateStatus struct {
+	// Whether every component in the Zarf namespace is ready
+	Healthy bool `json:"healthy"`
+	// Health of the workloads in the Zarf namespace
+	Components []ComponentHealth `json:"components"`
+	// When the status was last updated
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ComponentHealth is the readiness of a workload in the Zarf namespace.
+type ComponentHealth struct {
+	// Name of the workload
+	Name string `json:"name"`
+	// Kind of the workload (Deployment or StatefulSet)
+	Kind string `json:"kind"`
+	// Whether every desired replica is ready
+	Ready bool `json:"ready"`
+	// Number of ready replicas
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// Number of desired replicas
+	Replicas int32 `json:"replicas"`
+}
+
+// ProxyInfo contains the addresses of an HTTP proxy in the format of the proxy environment variables.
+type ProxyInfo struct {
+	// URL of the proxy for http requests
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// URL of the proxy for https requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// Comma separated hosts, domains and CIDRs that are connected to directly
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// ClusterFacts are details of the cluster a package is deployed to that are made available to templates and actions.
+type ClusterFacts struct {
+	// Number of nodes in the cluster
+	NodeCount int
+	// Kubernetes version of the cluster (e.g. v1.30.2)
+	K8sVersion string
+	// Name of the StorageClass marked as the cluster default
+	DefaultStorageClass string
 }
 
 // DeployedPackage contains information about a Zarf Package that has been deployed to a cluster
@@ -107,6 +242,23 @@ type DeployedComponent struct {
 	InstalledCharts    []InstalledChart `json:"installedCharts"`
 	Status             ComponentStatus  `json:"status"`
 	ObservedGeneration int              `json:"observedGeneration"`
+	// How long each phase of the most recent deployment of this component took, in milliseconds
+	PhaseDurations PhaseDurations `json:"phaseDurationsMs,omitempty"`
+	// Phases of the most recent deployment of this component that were skipped
+	SkippedPhases []DeployPhase `json:"skippedPhases,omitempty"`
+}
+
+// PhaseDurations maps the phases of a component deployment to how long they took in milliseconds.
+type PhaseDurations map[DeployPhase]int64
+
+// Add records that the given phase ran for d, accumulating with any earlier runs of the same phase.
+func (pd PhaseDurations) Add(phase DeployPhase, d time.Duration) {
+	pd[phase] += d.Milliseconds()
+}
+
+// Get returns how long the given phase took.
+func (pd PhaseDurations) Get(phase DeployPhase) time.Duration {
+	return time.Duration(pd[phase]) * time.Millisecond
 }
 
 // Webhook contains information about a Component Webhook operating on a Zarf package secret.
@@ -186,6 +338,12 @@ type ArtifactServerInfo struct {
 	PushToken string `json:"pushPassword"`
 	// URL address of the artifact registry
 	Address string `json:"address"`
+	// How long a push token is used for before it expires and is rotated (e.g. 720h), tokens never expire when empty
+	PushTokenLifetime string `json:"pushTokenLifetime,omitempty"`
+	// Time the push token was created
+	PushTokenCreatedAt *time.Time `json:"pushTokenCreatedAt,omitempty"`
+	// Time the push token expires, set from the creation time and the token lifetime
+	PushTokenExpiresAt *time.Time `json:"pushTokenExpiresAt,omitempty"`
 }
 
 // IsInternal returns true if the artifact server URL is equivalent to the artifact server deployed through the default init package
@@ -193,6 +351,52 @@ func (as ArtifactServerInfo) IsInternal() bool {
 	return as.Address == ZarfInClusterArtifactServiceURL
 }
 
+// SetPushToken sets the push token and records when it was created and, if the token has a lifetime, when it expires.
+func (as *ArtifactServerInfo) SetPushToken(token string, created time.Time) error {
+	as.PushToken = token
+	as.PushTokenCreatedAt = nil
+	as.PushTokenExpiresAt = nil
+	if token == "" {
+		return nil
+	}
+	lifetime, err := as.ParsePushTokenLifetime()
+	if err != nil {
+		return err
+	}
+	created = created.UTC().Truncate(time.Second)
+	as.PushTokenCreatedAt = &created
+	if lifetime == 0 {
+		return nil
+	}
+	expires := created.Add(lifetime)
+	as.PushTokenExpiresAt = &expires
+	return nil
+}
+
+// ParsePushTokenLifetime returns the lifetime of push tokens, zero is returned when tokens never expire.
+func (as ArtifactServerInfo) ParsePushTokenLifetime() (time.Duration, error) {
+	if as.PushTokenLifetime == "" {
+		return 0, nil
+	}
+	lifetime, err := time.ParseDuration(as.PushTokenLifetime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid artifact push token lifetime %q: %w", as.PushTokenLifetime, err)
+	}
+	if lifetime <= 0 {
+		return 0, fmt.Errorf("invalid artifact push token lifetime %q: the lifetime must be positive", as.PushTokenLifetime)
+	}
+	return lifetime, nil
+}
+
+// PushTokenExpiresWithin returns true if the push token expires within the duration from now, tokens without an
+// expiry never expire.
+func (as ArtifactServerInfo) PushTokenExpiresWithin(d time.Duration) bool {
+	if as.PushTokenExpiresAt == nil {
+		return false
+	}
+	return time.Now().Add(d).After(*as.PushTokenExpiresAt)
+}
+
 // FillInEmptyValues sets every necessary value that's currently empty to a reasonable default
 func (as *ArtifactServerInfo) FillInEmptyValues() {
 	// Set default svc url if an external registry was not provided
@@ -222,6 +426,59 @@ type RegistryInfo struct {
 	NodePort int `json:"nodePort"`
 	// Secret value that the registry was seeded with
 	Secret string `json:"secret"`
+	// Whether every namespace is given its own pull-only credentials instead of the pull user, only supported by the internal registry
+	NamespacedPullCredentials bool `json:"namespacedPullCredentials,omitempty"`
+	// Namespaces that have been given their own pull-only credentials
+	PullNamespaces []string `json:"pullNamespaces,omitempty"`
+	// Cloud credential provider (ecr, acr or gcr) the short-lived push and pull credentials of the registry are issued and refreshed by
+	CredentialProvider string `json:"credentialProvider,omitempty"`
+	// Credentials of other registries, such as external mirrors, that are written to the image pull secrets alongside the registry's
+	AdditionalPullCredentials []RegistryCredential `json:"additionalPullCredentials,omitempty"`
+	// Hashed htpasswd entries of the pull credentials issued to open 'zarf connect registry --ephemeral-credentials' tunnels, by username
+	TunnelPullEntries map[string]string `json:"tunnelPullEntries,omitempty"`
+	// Number of replicas of the internal registry, only the HPA scales the registry when not set
+	Replicas int `json:"replicas,omitempty"`
+	// S3-compatible bucket the internal registry stores images in instead of a PVC
+	S3 RegistryS3Storage `json:"s3,omitempty"`
+}
+
+// RegistryS3Storage is an S3-compatible bucket the internal registry stores images in, which lets every replica of the
+// registry share its storage.
+type RegistryS3Storage struct {
+	// Name of the bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Region of the bucket
+	Region string `json:"region,omitempty"`
+	// Endpoint of an S3-compatible service such as MinIO, AWS S3 is used when not set
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix within the bucket that images are stored under
+	RootDirectory string `json:"rootDirectory,omitempty"`
+	// Access key to the bucket, the credentials of the registry's service account or node are used when not set
+	AccessKey string `json:"accessKey,omitempty"`
+	// Secret key to the bucket
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// RegistryCredential is the address of a registry and the credentials to pull from it with.
+type RegistryCredential struct {
+	// Address of the registry (e.g. mirror.example.com)
+	Address string `json:"address" mapstructure:"address"`
+	// Username to pull from the registry with
+	Username string `json:"username" mapstructure:"username"`
+	// Password to pull from the registry with
+	Password string `json:"password" mapstructure:"password"`
+}
+
+// NamespacePullCredentials returns the username and password the image pull secret of the namespace authenticates with.
+// When every namespace is given its own credentials the password is derived from the pull password, so that rotating
+// the pull password rotates every namespace's password and a leaked secret only grants access as that namespace.
+func (ri RegistryInfo) NamespacePullCredentials(namespace string) (string, string) {
+	if !ri.NamespacedPullCredentials {
+		return ri.PullUsername, ri.PullPassword
+	}
+	mac := hmac.New(sha256.New, []byte(ri.PullPassword))
+	mac.Write([]byte(namespace))
+	return ZarfRegistryNamespacePullUserPrefix + namespace, hex.EncodeToString(mac.Sum(nil))
 }
 
 // IsInternal returns true if the registry URL is equivalent to the registry deployed through the default init package
@@ -26,6 +26,9 @@ type PackagerConfig struct {
 	// InspectOpts tracks user-defined options used to inspect the package
 	InspectOpts ZarfInspectOptions
 
+	// VerifyOpts tracks user-defined options used to verify the package
+	VerifyOpts ZarfVerifyOptions
+
 	// PublishOpts tracks user-defined options used to publish the package
 	PublishOpts ZarfPublishOptions
 
@@ -5,6 +5,8 @@
 package types
 
 import (
+	"fmt"
+	"path"
 	"time"
 )
 
@@ -20,6 +22,26 @@ type ZarfCommonOptions struct {
 	TempDirectory string
 	// Number of concurrent layer operations to perform when interacting with a remote package
 	OCIConcurrency int
+	// Number of concurrent image operations to perform when pulling, saving and pushing images
+	ImageConcurrency int
+	// URL of the Prometheus pushgateway or OTLP collector to export anonymous usage metrics to
+	MetricsEndpoint string
+	// Protocol to export usage metrics with
+	MetricsProtocol string
+	// Proxy or SSH jump host to connect to the Kubernetes API server through
+	KubeProxy string
+	// Kubeconfig contexts Zarf may target
+	ContextPolicy ContextPolicy
+	// Name of the protected kubeconfig context that is confirmed to be targeted
+	ConfirmContext string
+	// Path to an operator-held key file the Zarf state is additionally signed with
+	StateKeyPath string
+	// Limits on the requests sent to each registry and the retries of throttled requests
+	RegistryRateLimit RegistryRateLimit
+	// TLS configuration of individual registries (host or host:port)
+	RegistryTLS map[string]RegistryTLS
+	// Credential providers of individual registries (host or host:port) used in place of the Docker credential store
+	RegistryAuth map[string]RegistryAuth
 }
 
 // ZarfPackageOptions tracks the user-defined preferences during common package operations.
@@ -36,8 +58,16 @@ type ZarfPackageOptions struct {
 	SetVariables map[string]string
 	// Location where the public key component of a cosign key-pair can be found
 	PublicKeyPath string
+	// PEM encoded package signing keys of the trust root of the cluster, used when no public key was provided
+	TrustedKeys []string
+	// Locations of age identity files to decrypt an encrypted package with
+	DecryptIdentityPaths []string
+	// Passphrase to decrypt a package encrypted with a passphrase
+	DecryptPassphrase string
 	// The number of retries to perform for Zarf deploy operations like image pushes or Helm installs
 	Retries int
+	// Run the plugins of the package on deploy and remove without listing them and asking first
+	AllowPlugins bool
 }
 
 // ZarfInspectOptions tracks the user-defined preferences during a package inspection.
@@ -50,8 +80,16 @@ type ZarfInspectOptions struct {
 	ListImages bool
 }
 
+// ZarfVerifyOptions tracks the user-defined preferences during a package verification.
+type ZarfVerifyOptions struct {
+	// Verify the SLSA provenance attached to the package
+	Provenance bool
+}
+
 // ZarfFindImagesOptions tracks the user-defined preferences during a prepare find-images search.
 type ZarfFindImagesOptions struct {
+	// Mirrors to look up images on before falling back to their upstream registries
+	RegistryMirrors RegistryMirrors
 	// Path to the helm chart directory
 	RepoHelmChartPath string
 	// Kubernetes version to use for the helm chart
@@ -62,18 +100,60 @@ type ZarfFindImagesOptions struct {
 	Why string
 	// Optionally skip lookup of cosign artifacts when finding images
 	SkipCosign bool
+	// Write newly discovered images back into the images list of each component in the zarf.yaml
+	Update bool
+	// List the images running in the connected cluster instead of the images of a package
+	FromCluster bool
+	// Namespaces to list the running images of with FromCluster, every namespace when empty
+	Namespaces []string
 }
 
 // ZarfDeployOptions tracks the user-defined preferences during a package deploy.
 type ZarfDeployOptions struct {
 	// Whether to adopt any pre-existing K8s resources into the Helm charts managed by Zarf
 	AdoptExistingResources bool
+	// Whether to deploy to Helm releases and namespaces owned by another deployed package
+	AllowShared bool
 	// Skip waiting for external webhooks to execute as each package component is deployed
 	SkipWebhooks bool
+	// Fail the deployment instead of warning when the Zarf agent would not rewrite the image references of the package
+	RequireAgent bool
+	// Phases of each component deployment to skip (images, repos or charts)
+	SkipPhases []string
 	// Timeout for performing Helm operations
 	Timeout time.Duration
 	// [Library Only] A map of component names to chart names containing Helm Chart values to override values on deploy
 	ValuesOverridesMap map[string]map[string]map[string]interface{}
+	// [Dev Only] Comma separated list of pipeline points to inject failures at (e.g. registry-push:2,helm-timeout)
+	FaultInject string
+	// Load the package from the in-cluster package cache instead of the given package source
+	FromClusterCache bool
+	// Directory to write the rendered manifests and values of Helm charts that fail to render
+	HelmDebugDir string
+	// Location of an answers file from a previous deployment to take the components and variable values from
+	AnswersPath string
+	// Location to write the components and variable values of this deployment to as an answers file
+	ExportAnswersPath string
+	// Whether to retain the archive of the deployed package on the deploy host
+	Retain bool
+	// Directory to retain the archives of deployed packages in, defaults to a directory in the Zarf cache
+	RetainDir string
+	// Number of the most recently deployed archives of each package to retain, all are retained when not positive
+	RetainMax int
+	// Location of the trust root a cluster without one must be given, instead of trusting the first one it receives
+	TrustRootPath string
+}
+
+// DeployAnswers records how a package was deployed so the deployment can be repeated.
+type DeployAnswers struct {
+	// Name of the deployed package
+	Package string `json:"package"`
+	// Version of the deployed package
+	Version string `json:"version,omitempty"`
+	// Names of the components selected for deployment
+	Components []string `json:"components,omitempty"`
+	// Values of the variables set during the deployment by name, whether set on the command line, prompted for, defaulted or set by actions
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
 // ZarfMirrorOptions tracks the user-defined preferences during a package mirror.
@@ -86,10 +166,16 @@ type ZarfMirrorOptions struct {
 type ZarfPublishOptions struct {
 	// Location where the Zarf package will be published to
 	PackageDestination string
+	// Locations of further registries the Zarf package is published to concurrently with PackageDestination
+	MirrorDestinations []string
+	// Credentials of the registries to publish to, registries without credentials use the Docker credential store
+	Credentials []RegistryCredential
 	// Password to the private key signature file that will be used to sign the published package
 	SigningKeyPassword string
 	// Location where the private key component of a cosign key-pair can be found
 	SigningKeyPath string
+	// Size in megabytes of the chunks layers larger than it are uploaded in, 0 uploads layers in one request
+	ChunkSizeMB int
 }
 
 // ZarfPullOptions tracks the user-defined preferences during a package pull.
@@ -124,12 +210,32 @@ type ZarfInitOptions struct {
 	ArtifactServer ArtifactServerInfo
 	// StorageClass of the k8s cluster Zarf is initializing
 	StorageClass string
+	// PKI used by the Zarf agent, generated during init when empty
+	AgentTLS GeneratedPKI
+	// Mirrors of upstream registries the agent resolves image references on back to their upstream registries
+	RegistryMirrors RegistryMirrors
+	// Proxy recorded in the Zarf state and templated into the init components
+	Proxy ProxyConfig
+	// Namespaces Zarf-managed image and git pull secrets are written to
+	SecretNamespaces NamespaceFilter
+	// Whether to skip the host preflight checks run before installing the bundled K3s
+	SkipHostPreflight bool
 }
 
 // ZarfCreateOptions tracks the user-defined options used to create the package.
 type ZarfCreateOptions struct {
 	// Disable the generation of SBOM materials during package creation
 	SkipSBOM bool
+	// Names of components to leave out of SBOM generation, images shared with other components are still cataloged
+	SkipSBOMComponents []string
+	// Whether to zstd compress the SBOM tarball so it is stored separately from the package compression
+	CompressSBOM bool
+	// Whether to scan the SBOMs for vulnerabilities and store the report with them
+	ScanVulnerabilities bool
+	// Path to a grype vulnerability database archive to scan with instead of downloading the latest database
+	VulnerabilityDBPath string
+	// Severity at or above which vulnerabilities found in the SBOMs fail package creation
+	FailOnSeverity string
 	// Location where the Zarf package will be created from
 	BaseDir string
 	// Location where the finalized Zarf package will be placed
@@ -142,16 +248,54 @@ type ZarfCreateOptions struct {
 	SetVariables map[string]string
 	// Size of chunks to use when splitting a zarf package into multiple files in megabytes
 	MaxPackageSizeMB int
+	// Age recipients to encrypt the package tarball for
+	EncryptRecipients []string
+	// Passphrase to encrypt the package tarball with instead of age recipients
+	EncryptPassphrase string
+	// Maximum size of the image layer cache in megabytes, least recently used layers are pruned after pulling images
+	MaxCacheSizeMB int
+	// Whether to include every platform of images that resolve to an image index instead of only the package architecture
+	AllPlatforms bool
+	// Whether to include the cosign artifacts and OCI referrers attached to images in the package
+	IncludeReferrers bool
+	// Number of components without create actions or plugins to assemble at once
+	ComponentConcurrency int
+	// Whether to recompress the gzip layers of images with zstd to shrink the package
+	RecompressZstd bool
+	// Whether to convert the gzip layers of images to eStargz so lazy-pulling snapshotters can start containers early
+	ConvertEstargz bool
+	// Path to an organizational policy file of the images packages may contain
+	ImagePolicyPath string
+	// Address of the containerd socket to load local images from when they are not found on a remote or in docker
+	ContainerdAddress string
+	// Containerd namespace to load local images from when they are not found on a remote or in docker
+	ContainerdNamespace string
 	// Location where the private key component of a cosign key-pair can be found
 	SigningKeyPath string
 	// Password to the private key signature file that will be used to sigh the created package
 	SigningKeyPassword string
 	// Path to a previously built package used as the basis for creating a differential package
 	DifferentialPackagePath string
+	// OCI reference of a published package used as the basis for creating a differential package that also leaves out
+	// the image layers of the published package
+	DifferentialFrom string
 	// A map of domains to override on package create when pulling images
 	RegistryOverrides map[string]string
+	// Mirrors to pull images from before falling back to their upstream registries
+	RegistryMirrors RegistryMirrors
+	// Proxy to pull images through instead of the proxy environment variables
+	Proxy ProxyConfig
+	// Alternate URL prefixes to download remote files and manifests from when their URL prefix fails
+	URLMirrors map[string][]string
+	// Credentials to clone the git repositories that components are imported from with
+	GitCredentials []GitCredential
+	// Number of times to attempt downloading remote files and manifests
+	Retries int
 	// An optional variant that controls which components will be included in a package
 	Flavor string
+	// Whether to resolve the OCI skeleton packages components are imported from again instead of taking them from the
+	// compose lockfile
+	IgnoreComposeLock bool
 	// Whether to create a skeleton package
 	IsSkeleton bool
 	// Whether to create a YOLO package
@@ -173,4 +317,232 @@ type DifferentialData struct {
 	DifferentialImages         map[string]bool
 	DifferentialRepos          map[string]bool
 	DifferentialPackageVersion string
+	// The layers of the images of the reference package by the repository path of the images
+	DifferentialLayers map[string]map[string]bool
+}
+
+// RegistryMirror is a registry that images of an upstream registry are pulled from in its place.
+type RegistryMirror struct {
+	// Address of the mirror, optionally followed by the repository path the upstream repositories are nested under (e.g. mirror.example.com/dockerhub)
+	Address string `mapstructure:"address"`
+	// Path to a PEM encoded CA bundle used to verify the certificate of the mirror
+	CAFile string `mapstructure:"ca_file"`
+	// Username to authenticate to the mirror with, the Docker credential store is used when empty
+	Username string `mapstructure:"username"`
+	// Password to authenticate to the mirror with
+	Password string `mapstructure:"password"`
+}
+
+// GitCredential is how git repositories whose URL starts with a prefix are authenticated to.
+type GitCredential struct {
+	// URL prefix of the repositories the credential is used for (e.g. https://git.example.com/my-org/ or git@git.example.com:)
+	URL string `mapstructure:"url"`
+	// Username to authenticate with, defaults to git
+	Username string `mapstructure:"username"`
+	// Password or access token to authenticate over http(s) with
+	Password string `mapstructure:"password"`
+	// Path to the private key to authenticate over ssh with
+	SSHKey string `mapstructure:"ssh_key"`
+	// Password to the private key
+	SSHKeyPassword string `mapstructure:"ssh_key_password"`
+}
+
+// ProxyConfig is the HTTP proxy images are pulled through, it replaces the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables when set.
+type ProxyConfig struct {
+	// URL of the proxy for http requests
+	HTTPProxy string `mapstructure:"http_proxy"`
+	// URL of the proxy for https requests
+	HTTPSProxy string `mapstructure:"https_proxy"`
+	// Hosts, domains (e.g. .example.com) and CIDRs that are connected to directly
+	NoProxy []string `mapstructure:"no_proxy"`
+	// Path to a PEM encoded CA bundle used to verify the certificates presented through the proxy
+	CAFile string `mapstructure:"ca_file"`
+	// Rules for individual registries (host or host:port) that take precedence over the proxies above
+	Registries map[string]RegistryProxy `mapstructure:"registries"`
+}
+
+// RegistryProxy is how a registry is connected to.
+type RegistryProxy struct {
+	// URL of the proxy to connect to the registry through
+	Proxy string `mapstructure:"proxy"`
+	// Whether to connect to the registry directly
+	NoProxy bool `mapstructure:"no_proxy"`
+}
+
+// IsSet returns if any proxy configuration was provided.
+func (p ProxyConfig) IsSet() bool {
+	return p.HTTPProxy != "" || p.HTTPSProxy != "" || len(p.NoProxy) > 0 || p.CAFile != "" || len(p.Registries) > 0
+}
+
+// RegistryRateLimit limits the requests Zarf sends to each registry and how the requests a registry throttles with a
+// 429 response are retried.
+type RegistryRateLimit struct {
+	// Requests per second to send to each registry, requests are not limited when zero
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	// Number of times a throttled request is retried before its 429 response is returned
+	Retries int `mapstructure:"retries"`
+	// Longest time to wait before retrying a throttled request, even if the registry asks to wait longer
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// Validate returns an error when a limit is negative.
+func (l RegistryRateLimit) Validate() error {
+	if l.RequestsPerSecond < 0 {
+		return fmt.Errorf("requests_per_second must not be negative, got %v", l.RequestsPerSecond)
+	}
+	if l.Retries < 0 {
+		return fmt.Errorf("retries must not be negative, got %d", l.Retries)
+	}
+	if l.MaxBackoff < 0 {
+		return fmt.Errorf("max_backoff must not be negative, got %s", l.MaxBackoff)
+	}
+	return nil
+}
+
+// RegistryTLS is how the TLS connections to a registry are verified and authenticated, in addition to the system CAs.
+type RegistryTLS struct {
+	// Path to a PEM encoded CA bundle used to verify the certificate of the registry
+	CAFile string `mapstructure:"ca_file"`
+	// Path to a PEM encoded client certificate to authenticate to the registry with
+	CertFile string `mapstructure:"cert_file"`
+	// Path to the PEM encoded private key of the client certificate
+	KeyFile string `mapstructure:"key_file"`
+	// Whether to skip the verification of the certificate of the registry
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// Validate returns an error when only one of the client certificate and its key is set.
+func (t RegistryTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// The providers of ambient registry credentials.
+const (
+	// RegistryAuthProviderAWS authenticates to ECR with the AWS credentials of the environment, such as IRSA
+	RegistryAuthProviderAWS = "aws"
+	// RegistryAuthProviderGCP authenticates to GCR and Artifact Registry with the application default credentials, such as workload identity
+	RegistryAuthProviderGCP = "gcp"
+	// RegistryAuthProviderAzure authenticates to ACR with the Azure credentials of the environment, such as workload identity or a managed identity
+	RegistryAuthProviderAzure = "azure"
+	// RegistryAuthProviderOIDC authenticates with an OIDC token of the environment, optionally exchanged for a registry token
+	RegistryAuthProviderOIDC = "oidc"
+)
+
+// RegistryAuth is how Zarf authenticates to a registry in place of the Docker credential store.
+type RegistryAuth struct {
+	// Provider of the credentials, one of aws, gcp, azure or oidc
+	Provider string `mapstructure:"provider"`
+	// Path to a file holding the OIDC token, such as a projected service account token
+	TokenFile string `mapstructure:"token_file"`
+	// Environment variable holding the OIDC token
+	TokenEnv string `mapstructure:"token_env"`
+	// Audience of the OIDC token requested from GitHub Actions and of the token it is exchanged for
+	Audience string `mapstructure:"audience"`
+	// URL of an OAuth 2.0 token exchange (RFC 8693) endpoint the OIDC token is exchanged at, the OIDC token is used as is when empty
+	ExchangeURL string `mapstructure:"exchange_url"`
+	// Username the token authenticates with, the token is sent to the registry as a bearer token when empty
+	Username string `mapstructure:"username"`
+}
+
+// Validate returns an error when the provider is unknown or OIDC settings are set for another provider.
+func (a RegistryAuth) Validate() error {
+	switch a.Provider {
+	case RegistryAuthProviderAWS, RegistryAuthProviderGCP, RegistryAuthProviderAzure:
+		if a.TokenFile != "" || a.TokenEnv != "" || a.Audience != "" || a.ExchangeURL != "" || a.Username != "" {
+			return fmt.Errorf("token_file, token_env, audience, exchange_url and username only apply to the %s provider", RegistryAuthProviderOIDC)
+		}
+	case RegistryAuthProviderOIDC:
+		if a.TokenFile != "" && a.TokenEnv != "" {
+			return fmt.Errorf("only one of token_file and token_env may be set")
+		}
+	default:
+		return fmt.Errorf("unknown provider %q, must be one of %s, %s, %s or %s", a.Provider, RegistryAuthProviderAWS, RegistryAuthProviderGCP, RegistryAuthProviderAzure, RegistryAuthProviderOIDC)
+	}
+	return nil
+}
+
+// ContextPolicy restricts the kubeconfig contexts that Zarf and the embedded kubectl may target, so that operators
+// sharing a workstation do not deploy to the wrong cluster.
+type ContextPolicy struct {
+	// Glob patterns of the contexts that may be targeted, every context may be targeted when empty
+	Allowed []string `mapstructure:"allowed"`
+	// Glob patterns of the contexts that must be confirmed before they are targeted
+	Protected []string `mapstructure:"protected"`
+}
+
+// Validate returns an error when a pattern of the policy is malformed.
+func (p ContextPolicy) Validate() error {
+	for _, pattern := range append(append([]string{}, p.Allowed...), p.Protected...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid context pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsSet returns if any context policy was provided.
+func (p ContextPolicy) IsSet() bool {
+	return len(p.Allowed) > 0 || len(p.Protected) > 0
+}
+
+// ConnectDefinition is a named zarf connect target declared in the config file. Fields that are not set are taken
+// from the service with the zarf.dev/connect-name label of the same name, or from the Zarf registry or git server.
+type ConnectDefinition struct {
+	// Descriptive text that explains what the resource you would be connecting to is used for
+	Description string `mapstructure:"description"`
+	// Namespace of the resource
+	Namespace string `mapstructure:"namespace"`
+	// Type of the resource, svc or pod
+	Type string `mapstructure:"type"`
+	// Name of the resource
+	Name string `mapstructure:"name"`
+	// Label selector of the pods to connect to instead of a named resource
+	Selector string `mapstructure:"selector"`
+	// Port of the resource to connect to
+	RemotePort int `mapstructure:"remote_port"`
+	// Local port to bind the tunnel to, a free port is picked when not set
+	LocalPort int `mapstructure:"local_port"`
+	// Protocol of the remote port, tcp or udp
+	Protocol string `mapstructure:"protocol"`
+	// URL path that gets appended to the k8s port-forward result
+	URL string `mapstructure:"url"`
+	// Print the URL of the tunnel instead of opening it in a browser
+	CLIOnly bool `mapstructure:"cli_only"`
+}
+
+// Allows returns if the context may be targeted.
+func (p ContextPolicy) Allows(context string) bool {
+	return len(p.Allowed) == 0 || matchesContext(p.Allowed, context)
+}
+
+// Protects returns if the context must be confirmed before it is targeted.
+func (p ContextPolicy) Protects(context string) bool {
+	return matchesContext(p.Protected, context)
+}
+
+func matchesContext(patterns []string, context string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, context); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistryMirrors maps upstream registries (optionally followed by a repository path) to the mirrors that are tried, in order, before the upstream registry.
+type RegistryMirrors map[string][]RegistryMirror
+
+// Addresses returns the addresses of the mirrors of each upstream registry.
+func (m RegistryMirrors) Addresses() map[string][]string {
+	addresses := map[string][]string{}
+	for upstream, mirrors := range m {
+		for _, mirror := range mirrors {
+			addresses[upstream] = append(addresses[upstream], mirror.Address)
+		}
+	}
+	return addresses
 }